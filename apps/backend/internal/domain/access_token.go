@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Prefixes of machine tokens, so that a leaked token can be recognised
+const (
+	alertSourceTokenPrefix    = "rnx_alert_"
+	discoveryAgentTokenPrefix = "rnx_agent_"
+)
+
+// accessToken is a machine token of an alert source or discovery agent. The value is shown once;
+// only the hash and a short display prefix are stored.
+type accessToken struct {
+	Value  string
+	Hash   string
+	Prefix string
+}
+
+// newAccessToken generates a random token starting with prefix
+func newAccessToken(prefix string) (accessToken, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return accessToken{}, err
+	}
+	value := prefix + hex.EncodeToString(buf)
+	return accessToken{
+		Value:  value,
+		Hash:   hashAccessToken(value),
+		Prefix: value[:len(prefix)+4],
+	}, nil
+}
+
+func hashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAccessToken(t *testing.T) {
+	for _, prefix := range []string{alertSourceTokenPrefix, discoveryAgentTokenPrefix} {
+		t.Run(prefix, func(t *testing.T) {
+			token, err := newAccessToken(prefix)
+			require.NoError(t, err)
+
+			assert.True(t, strings.HasPrefix(token.Value, prefix))
+			assert.Len(t, token.Value, len(prefix)+48)
+			assert.Equal(t, token.Value[:len(prefix)+4], token.Prefix)
+			assert.Equal(t, hashAccessToken(token.Value), token.Hash)
+			assert.NotContains(t, token.Hash, token.Value)
+
+			other, err := newAccessToken(prefix)
+			require.NoError(t, err)
+			assert.NotEqual(t, token.Value, other.Value)
+		})
+	}
+}
+
+func TestHashAccessToken(t *testing.T) {
+	assert.Equal(t, "610d68168165e9665a639a5455a924d6c98282d2294aaefb3920f4fcfc32519b", hashAccessToken("rnx_alert_test"))
+	assert.NotEqual(t, hashAccessToken("rnx_alert_test"), hashAccessToken("rnx_agent_test"))
+}
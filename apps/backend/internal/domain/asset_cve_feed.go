@@ -0,0 +1,549 @@
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+// maxVulnerabilityFeedSize limits a feed after decompression
+const maxVulnerabilityFeedSize = 512 << 20
+
+// vulnerabilityFeed is a parsed NVD CVE feed or CPE dictionary
+type vulnerabilityFeed struct {
+	format     string
+	cves       []repo.CVEEntry
+	dictionary []repo.CPEDictionaryEntry
+	skipped    int
+}
+
+// NVD JSON 1.1 data feeds (nvdcve-1.1-*.json)
+type nvdLegacyFeed struct {
+	Items []struct {
+		CVE struct {
+			Meta struct {
+				ID string `json:"ID"`
+			} `json:"CVE_data_meta"`
+			Description struct {
+				Data []nvdLangValue `json:"description_data"`
+			} `json:"description"`
+		} `json:"cve"`
+		Configurations struct {
+			Nodes []nvdLegacyNode `json:"nodes"`
+		} `json:"configurations"`
+		Impact struct {
+			V3 *struct {
+				CVSS nvdCVSSData `json:"cvssV3"`
+			} `json:"baseMetricV3"`
+			V2 *struct {
+				CVSS     nvdCVSSData `json:"cvssV2"`
+				Severity string      `json:"severity"`
+			} `json:"baseMetricV2"`
+		} `json:"impact"`
+		PublishedDate    string `json:"publishedDate"`
+		LastModifiedDate string `json:"lastModifiedDate"`
+	} `json:"CVE_Items"`
+}
+
+type nvdLegacyNode struct {
+	Children []nvdLegacyNode `json:"children"`
+	CPEMatch []nvdCPEMatch   `json:"cpe_match"`
+}
+
+// NVD CVE API 2.0 responses (/rest/json/cves/2.0) and CPE API 2.0 responses (/rest/json/cpes/2.0)
+type nvdAPIFeed struct {
+	Vulnerabilities []struct {
+		CVE struct {
+			ID           string         `json:"id"`
+			Published    string         `json:"published"`
+			LastModified string         `json:"lastModified"`
+			VulnStatus   string         `json:"vulnStatus"`
+			Descriptions []nvdLangValue `json:"descriptions"`
+			Metrics      struct {
+				V31 []nvdAPIMetric `json:"cvssMetricV31"`
+				V30 []nvdAPIMetric `json:"cvssMetricV30"`
+				V2  []nvdAPIMetric `json:"cvssMetricV2"`
+			} `json:"metrics"`
+			Configurations []struct {
+				Nodes []struct {
+					CPEMatch []nvdCPEMatch `json:"cpeMatch"`
+				} `json:"nodes"`
+			} `json:"configurations"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+	Products []struct {
+		CPE struct {
+			CPEName    string         `json:"cpeName"`
+			Deprecated bool           `json:"deprecated"`
+			Titles     []nvdLangTitle `json:"titles"`
+		} `json:"cpe"`
+	} `json:"products"`
+}
+
+type nvdLangValue struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+type nvdLangTitle struct {
+	Lang  string `json:"lang"`
+	Title string `json:"title"`
+}
+
+type nvdCVSSData struct {
+	Version      string  `json:"version"`
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity"`
+	VectorString string  `json:"vectorString"`
+}
+
+type nvdAPIMetric struct {
+	Type         string      `json:"type"` // Primary - оценка NVD, Secondary - оценка CNA
+	CVSSData     nvdCVSSData `json:"cvssData"`
+	BaseSeverity string      `json:"baseSeverity"` // для CVSS v2 уровень указан вне cvssData
+}
+
+// nvdCPEMatch is a cpe_match (1.1) or cpeMatch (2.0) element
+type nvdCPEMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	CPE23URI              string `json:"cpe23Uri"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// cpeDictionaryItem is a cpe-item of the official CPE dictionary XML
+type cpeDictionaryItem struct {
+	Deprecated bool `xml:"deprecated,attr"`
+	Titles     []struct {
+		Lang  string `xml:"lang,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"title"`
+	CPE23 struct {
+		Name string `xml:"name,attr"`
+	} `xml:"cpe23-item"`
+}
+
+// parseVulnerabilityFeed unpacks gzip or zip archives and detects the feed format: NVD JSON 1.1 feed,
+// CVE API 2.0 response, CPE API 2.0 response or the CPE dictionary XML
+func parseVulnerabilityFeed(data []byte) (*vulnerabilityFeed, error) {
+	data, err := unpackVulnerabilityFeed(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVulnerabilityFeed, err)
+	}
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\xef\xbb\xbf"))
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty file", ErrInvalidVulnerabilityFeed)
+	}
+
+	if data[0] == '<' {
+		entries, err := parseCPEDictionaryXML(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidVulnerabilityFeed, err)
+		}
+		return &vulnerabilityFeed{format: dto.VulnerabilityFeedCPEDictionary, dictionary: entries}, nil
+	}
+
+	var feed struct {
+		nvdLegacyFeed
+		nvdAPIFeed
+	}
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVulnerabilityFeed, err)
+	}
+	switch {
+	case len(feed.Items) > 0:
+		return parseNVDLegacyFeed(feed.nvdLegacyFeed), nil
+	case len(feed.Vulnerabilities) > 0:
+		return parseNVDAPIFeed(feed.nvdAPIFeed), nil
+	case len(feed.Products) > 0:
+		return parseCPEAPIFeed(feed.nvdAPIFeed), nil
+	}
+	return nil, fmt.Errorf("%w: no CVE_Items, vulnerabilities or products found", ErrInvalidVulnerabilityFeed)
+}
+
+// unpackVulnerabilityFeed returns the feed contents; NVD distributes feeds as .json.gz and .json.zip
+func unpackVulnerabilityFeed(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return readFeedLimited(reader)
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range archive.File {
+			name := strings.ToLower(file.Name)
+			if !strings.HasSuffix(name, ".json") && !strings.HasSuffix(name, ".xml") {
+				continue
+			}
+			reader, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer reader.Close()
+			return readFeedLimited(reader)
+		}
+		return nil, fmt.Errorf("archive contains no .json or .xml file")
+	}
+	return data, nil
+}
+
+func readFeedLimited(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxVulnerabilityFeedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxVulnerabilityFeedSize {
+		return nil, fmt.Errorf("unpacked feed is larger than %d MB", maxVulnerabilityFeedSize>>20)
+	}
+	return data, nil
+}
+
+func parseNVDLegacyFeed(feed nvdLegacyFeed) *vulnerabilityFeed {
+	result := &vulnerabilityFeed{format: dto.VulnerabilityFeedNVD}
+	for _, item := range feed.Items {
+		description := englishValue(item.CVE.Description.Data)
+		if item.CVE.Meta.ID == "" || strings.HasPrefix(description, "** REJECT **") {
+			result.skipped++
+			continue
+		}
+
+		entry := repo.CVEEntry{
+			CVEID:       item.CVE.Meta.ID,
+			Description: importStringPtr(description),
+			PublishedAt: parseNVDTime(item.PublishedDate),
+			ModifiedAt:  parseNVDTime(item.LastModifiedDate),
+		}
+		switch {
+		case item.Impact.V3 != nil:
+			setCVEScore(&entry, item.Impact.V3.CVSS, item.Impact.V3.CVSS.BaseSeverity)
+		case item.Impact.V2 != nil:
+			setCVEScore(&entry, item.Impact.V2.CVSS, item.Impact.V2.Severity)
+		}
+
+		var matches []nvdCPEMatch
+		var collect func(nodes []nvdLegacyNode)
+		collect = func(nodes []nvdLegacyNode) {
+			for _, node := range nodes {
+				matches = append(matches, node.CPEMatch...)
+				collect(node.Children)
+			}
+		}
+		collect(item.Configurations.Nodes)
+		entry.Matches = convertCPEMatches(matches)
+
+		result.cves = append(result.cves, entry)
+	}
+	return result
+}
+
+func parseNVDAPIFeed(feed nvdAPIFeed) *vulnerabilityFeed {
+	result := &vulnerabilityFeed{format: dto.VulnerabilityFeedNVD}
+	for _, item := range feed.Vulnerabilities {
+		cve := item.CVE
+		if cve.ID == "" || strings.EqualFold(cve.VulnStatus, "Rejected") {
+			result.skipped++
+			continue
+		}
+
+		entry := repo.CVEEntry{
+			CVEID:       cve.ID,
+			Description: importStringPtr(englishValue(cve.Descriptions)),
+			PublishedAt: parseNVDTime(cve.Published),
+			ModifiedAt:  parseNVDTime(cve.LastModified),
+		}
+		for _, metrics := range [][]nvdAPIMetric{cve.Metrics.V31, cve.Metrics.V30, cve.Metrics.V2} {
+			if metric := primaryMetric(metrics); metric != nil {
+				severity := metric.CVSSData.BaseSeverity
+				if severity == "" {
+					severity = metric.BaseSeverity
+				}
+				setCVEScore(&entry, metric.CVSSData, severity)
+				break
+			}
+		}
+
+		var matches []nvdCPEMatch
+		for _, configuration := range cve.Configurations {
+			for _, node := range configuration.Nodes {
+				matches = append(matches, node.CPEMatch...)
+			}
+		}
+		entry.Matches = convertCPEMatches(matches)
+
+		result.cves = append(result.cves, entry)
+	}
+	return result
+}
+
+func parseCPEAPIFeed(feed nvdAPIFeed) *vulnerabilityFeed {
+	result := &vulnerabilityFeed{format: dto.VulnerabilityFeedCPEDictionary}
+	seen := make(map[string]bool)
+	for _, item := range feed.Products {
+		var title string
+		for _, t := range item.CPE.Titles {
+			if title == "" || strings.HasPrefix(t.Lang, "en") {
+				title = t.Title
+			}
+		}
+		entry, ok := cpeDictionaryEntry(item.CPE.CPEName, title, item.CPE.Deprecated)
+		if !ok {
+			result.skipped++
+			continue
+		}
+		if key := entry.Vendor + ":" + entry.Product + ":" + entry.NormalizedTitle; !seen[key] {
+			seen[key] = true
+			result.dictionary = append(result.dictionary, entry)
+		}
+	}
+	return result
+}
+
+// parseCPEDictionaryXML streams the official CPE dictionary; titles of all versions of a product
+// collapse into one entry once the version is stripped
+func parseCPEDictionaryXML(data []byte) ([]repo.CPEDictionaryEntry, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	seen := make(map[string]bool)
+	var entries []repo.CPEDictionaryEntry
+	items := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "cpe-item" {
+			continue
+		}
+
+		var item cpeDictionaryItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return nil, err
+		}
+		items++
+		var title string
+		for _, t := range item.Titles {
+			if title == "" || strings.HasPrefix(t.Lang, "en") {
+				title = t.Value
+			}
+		}
+		entry, ok := cpeDictionaryEntry(item.CPE23.Name, title, item.Deprecated)
+		if !ok {
+			continue
+		}
+		if key := entry.Vendor + ":" + entry.Product + ":" + entry.NormalizedTitle; !seen[key] {
+			seen[key] = true
+			entries = append(entries, entry)
+		}
+	}
+	if items == 0 {
+		return nil, fmt.Errorf("no cpe-item elements found")
+	}
+	return entries, nil
+}
+
+func cpeDictionaryEntry(cpe23, title string, deprecated bool) (repo.CPEDictionaryEntry, bool) {
+	name, ok := parseCPE23(cpe23)
+	if !ok || deprecated || strings.TrimSpace(title) == "" {
+		return repo.CPEDictionaryEntry{}, false
+	}
+	version := ""
+	if name.version != nil {
+		version = *name.version
+	}
+	normalized := normalizeSoftwareName(title, version)
+	if normalized == "" {
+		return repo.CPEDictionaryEntry{}, false
+	}
+	return repo.CPEDictionaryEntry{
+		CPEProduct:      repo.CPEProduct{Vendor: name.vendor, Product: name.product},
+		Title:           strings.TrimSpace(title),
+		NormalizedTitle: normalized,
+	}, true
+}
+
+// convertCPEMatches keeps the vulnerable application and operating system configurations
+func convertCPEMatches(matches []nvdCPEMatch) []repo.CVECPEMatch {
+	var result []repo.CVECPEMatch
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		uri := m.Criteria
+		if uri == "" {
+			uri = m.CPE23URI
+		}
+		name, ok := parseCPE23(uri)
+		if !m.Vulnerable || !ok {
+			continue
+		}
+		key := strings.Join([]string{uri, m.VersionStartIncluding, m.VersionStartExcluding, m.VersionEndIncluding, m.VersionEndExcluding}, "|")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, repo.CVECPEMatch{
+			CPE23:                 uri,
+			Vendor:                name.vendor,
+			Product:               name.product,
+			Version:               name.version,
+			VersionStartIncluding: importStringPtr(m.VersionStartIncluding),
+			VersionStartExcluding: importStringPtr(m.VersionStartExcluding),
+			VersionEndIncluding:   importStringPtr(m.VersionEndIncluding),
+			VersionEndExcluding:   importStringPtr(m.VersionEndExcluding),
+		})
+	}
+	return result
+}
+
+// cpeName holds the parts of a CPE 2.3 name used for matching
+type cpeName struct {
+	vendor  string
+	product string
+	version *string // nil для '*' (любая) и '-' (не применимо)
+}
+
+// parseCPE23 parses "cpe:2.3:part:vendor:product:version:..." names of applications (a) and operating systems (o)
+func parseCPE23(uri string) (cpeName, bool) {
+	var parts []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range uri {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	parts = append(parts, current.String())
+
+	if len(parts) < 6 || parts[0] != "cpe" || parts[1] != "2.3" || (parts[2] != "a" && parts[2] != "o") {
+		return cpeName{}, false
+	}
+	name := cpeName{vendor: strings.ToLower(parts[3]), product: strings.ToLower(parts[4])}
+	if name.vendor == "" || name.vendor == "*" || name.product == "" || name.product == "*" {
+		return cpeName{}, false
+	}
+	if version := parts[5]; version != "" && version != "*" && version != "-" {
+		name.version = &version
+	}
+	return name, true
+}
+
+func englishValue(values []nvdLangValue) string {
+	var value string
+	for _, v := range values {
+		if value == "" || v.Lang == "en" {
+			value = v.Value
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+func primaryMetric(metrics []nvdAPIMetric) *nvdAPIMetric {
+	for i := range metrics {
+		if metrics[i].Type == "Primary" {
+			return &metrics[i]
+		}
+	}
+	if len(metrics) > 0 {
+		return &metrics[0]
+	}
+	return nil
+}
+
+func setCVEScore(entry *repo.CVEEntry, cvss nvdCVSSData, severity string) {
+	score := cvss.BaseScore
+	entry.CVSSScore = &score
+	entry.CVSSVersion = importStringPtr(cvss.Version)
+	entry.CVSSVector = importStringPtr(cvss.VectorString)
+	severity = strings.ToLower(severity)
+	if severity == "" {
+		severity = cvssSeverity(score)
+	}
+	entry.Severity = &severity
+}
+
+// cvssSeverity returns the CVSS v3 qualitative rating of the score
+func cvssSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return dto.CVESeverityCritical
+	case score >= 7.0:
+		return dto.CVESeverityHigh
+	case score >= 4.0:
+		return dto.CVESeverityMedium
+	case score > 0:
+		return dto.CVESeverityLow
+	default:
+		return dto.CVESeverityNone
+	}
+}
+
+// parseNVDTime parses "2019-01-01T05:29Z" (1.1 feeds) and "2021-12-10T10:15:09.143" (API 2.0)
+func parseNVDTime(value string) *time.Time {
+	for _, layout := range []string{"2006-01-02T15:04Z07:00", "2006-01-02T15:04:05.999", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+var (
+	softwareNameQualifier = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]`)
+	softwareVersionToken  = regexp.MustCompile(`^v?\d+([._]\d+)*[a-z]?$`)
+)
+
+// softwareArchitectureTokens are dropped from software names
+var softwareArchitectureTokens = map[string]bool{
+	"x64": true, "x86": true, "x86_64": true, "amd64": true, "arm64": true, "i386": true, "i686": true,
+	"64-bit": true, "32-bit": true, "64bit": true, "32bit": true,
+}
+
+// normalizeSoftwareName lowercases an installed software name or a CPE title and removes the version,
+// parenthesized qualifiers such as "(x64 en-US)" and architecture suffixes: "Mozilla Firefox 115.0 (x64 en-US)"
+// and "Mozilla Firefox 115.0" both become "mozilla firefox"
+func normalizeSoftwareName(name, version string) string {
+	name = strings.ToLower(name)
+	if version = strings.ToLower(strings.TrimSpace(version)); version != "" {
+		name = strings.ReplaceAll(strings.ReplaceAll(name, "v"+version, " "), version, " ")
+	}
+	name = softwareNameQualifier.ReplaceAllString(name, " ")
+
+	var tokens []string
+	for _, token := range strings.Fields(name) {
+		token = strings.Trim(token, ",;:-–")
+		if token == "" || softwareArchitectureTokens[token] || softwareVersionToken.MatchString(token) {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	return strings.Join(tokens, " ")
+}
@@ -0,0 +1,432 @@
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+const testNVDLegacyFeed = `{
+  "CVE_data_type": "CVE",
+  "CVE_Items": [
+    {
+      "cve": {
+        "CVE_data_meta": {"ID": "CVE-2021-44228"},
+        "description": {"description_data": [{"lang": "en", "value": " Apache Log4j2 JNDI features do not protect against attacker controlled LDAP. "}]}
+      },
+      "configurations": {
+        "nodes": [
+          {
+            "operator": "AND",
+            "children": [
+              {"cpe_match": [
+                {"vulnerable": true, "cpe23Uri": "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*", "versionStartIncluding": "2.0.1", "versionEndExcluding": "2.12.2"},
+                {"vulnerable": true, "cpe23Uri": "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*", "versionStartIncluding": "2.0.1", "versionEndExcluding": "2.12.2"}
+              ]},
+              {"cpe_match": [
+                {"vulnerable": false, "cpe23Uri": "cpe:2.3:o:debian:debian_linux:10.0:*:*:*:*:*:*:*"}
+              ]}
+            ]
+          },
+          {"cpe_match": [
+            {"vulnerable": true, "cpe23Uri": "cpe:2.3:a:apache:log4j:2.0:beta9:*:*:*:*:*:*"},
+            {"vulnerable": true, "cpe23Uri": "cpe:2.3:h:cisco:router:-:*:*:*:*:*:*:*"}
+          ]}
+        ]
+      },
+      "impact": {
+        "baseMetricV3": {"cvssV3": {"version": "3.1", "vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", "baseScore": 10.0, "baseSeverity": "CRITICAL"}},
+        "baseMetricV2": {"cvssV2": {"version": "2.0", "baseScore": 9.3}, "severity": "HIGH"}
+      },
+      "publishedDate": "2021-12-10T10:15Z",
+      "lastModifiedDate": "2022-02-01T14:21Z"
+    },
+    {
+      "cve": {
+        "CVE_data_meta": {"ID": "CVE-2020-0001"},
+        "description": {"description_data": [{"lang": "en", "value": "** REJECT ** DO NOT USE THIS CANDIDATE NUMBER."}]}
+      }
+    },
+    {
+      "cve": {
+        "CVE_data_meta": {"ID": "CVE-2019-0002"},
+        "description": {"description_data": [{"lang": "en", "value": "Old CVE with CVSS v2 only."}]}
+      },
+      "impact": {"baseMetricV2": {"cvssV2": {"version": "2.0", "baseScore": 5.0}, "severity": "MEDIUM"}}
+    }
+  ]
+}`
+
+const testNVDAPIFeed = `{
+  "resultsPerPage": 3,
+  "format": "NVD_CVE",
+  "version": "2.0",
+  "vulnerabilities": [
+    {"cve": {
+      "id": "CVE-2023-4863",
+      "published": "2023-09-12T15:15:24.327",
+      "lastModified": "2024-01-07T11:15:10.110",
+      "vulnStatus": "Modified",
+      "descriptions": [{"lang": "es", "value": "Desbordamiento"}, {"lang": "en", "value": "Heap buffer overflow in libwebp."}],
+      "metrics": {
+        "cvssMetricV31": [
+          {"type": "Secondary", "cvssData": {"version": "3.1", "baseScore": 9.6, "baseSeverity": "CRITICAL"}},
+          {"type": "Primary", "cvssData": {"version": "3.1", "vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H", "baseScore": 8.8, "baseSeverity": "HIGH"}}
+        ]
+      },
+      "configurations": [{"nodes": [{"cpeMatch": [
+        {"vulnerable": true, "criteria": "cpe:2.3:a:google:chrome:*:*:*:*:*:*:*:*", "versionEndExcluding": "116.0.5845.187"},
+        {"vulnerable": true, "criteria": "cpe:2.3:a:mozilla:firefox:*:*:*:*:*:*:*:*", "versionEndExcluding": "117.0.1"}
+      ]}]}]
+    }},
+    {"cve": {"id": "CVE-2023-0001", "vulnStatus": "Rejected", "descriptions": [{"lang": "en", "value": "Rejected reason"}]}},
+    {"cve": {
+      "id": "CVE-2010-0001",
+      "vulnStatus": "Analyzed",
+      "descriptions": [{"lang": "en", "value": "Old CVE."}],
+      "metrics": {"cvssMetricV2": [{"type": "Primary", "cvssData": {"version": "2.0", "baseScore": 7.5}, "baseSeverity": "HIGH"}]}
+    }}
+  ]
+}`
+
+const testCPEAPIFeed = `{
+  "format": "NVD_CPE",
+  "products": [
+    {"cpe": {"cpeName": "cpe:2.3:a:mozilla:firefox:115.0:*:*:*:*:*:*:*", "titles": [{"lang": "ru", "title": "Мозилла Файрфокс 115.0"}, {"lang": "en", "title": "Mozilla Firefox 115.0"}]}},
+    {"cpe": {"cpeName": "cpe:2.3:a:mozilla:firefox:116.0:*:*:*:*:*:*:*", "titles": [{"lang": "en", "title": "Mozilla Firefox 116.0"}]}},
+    {"cpe": {"cpeName": "cpe:2.3:a:oldsoft:tool:1.0:*:*:*:*:*:*:*", "deprecated": true, "titles": [{"lang": "en", "title": "OldSoft Tool 1.0"}]}},
+    {"cpe": {"cpeName": "cpe:2.3:h:cisco:router:-:*:*:*:*:*:*:*", "titles": [{"lang": "en", "title": "Cisco Router"}]}}
+  ]
+}`
+
+const testCPEDictionaryXML = `<?xml version="1.0" encoding="UTF-8"?>
+<cpe-list xmlns="http://cpe.mitre.org/dictionary/2.0" xmlns:cpe-23="http://scap.nist.gov/schema/cpe-extension/2.3">
+  <generator><product_name>National Vulnerability Database (NVD)</product_name></generator>
+  <cpe-item name="cpe:/a:7-zip:7-zip:23.01">
+    <title xml:lang="en-US">7-Zip 23.01</title>
+    <cpe-23:cpe23-item name="cpe:2.3:a:7-zip:7-zip:23.01:*:*:*:*:*:*:*"/>
+  </cpe-item>
+  <cpe-item name="cpe:/a:7-zip:7-zip:22.01">
+    <title xml:lang="en-US">7-Zip 22.01</title>
+    <cpe-23:cpe23-item name="cpe:2.3:a:7-zip:7-zip:22.01:*:*:*:*:*:*:*"/>
+  </cpe-item>
+  <cpe-item name="cpe:/a:microsoft:visual_c%2b%2b:2019" deprecated="true">
+    <title xml:lang="en-US">Microsoft Visual C++ 2019</title>
+    <cpe-23:cpe23-item name="cpe:2.3:a:microsoft:visual_c\+\+:2019:*:*:*:*:*:*:*"/>
+  </cpe-item>
+  <cpe-item name="cpe:/a:notepad-plus-plus:notepad%2b%2b:8.5.4">
+    <title xml:lang="en-US">Notepad++ v8.5.4</title>
+    <cpe-23:cpe23-item name="cpe:2.3:a:notepad-plus-plus:notepad\+\+:8.5.4:*:*:*:*:*:*:*"/>
+  </cpe-item>
+</cpe-list>`
+
+func gzipFeed(t *testing.T, data string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func zipFeed(t *testing.T, name, data string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	writer, err := archive.Create(name)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+	return buffer.Bytes()
+}
+
+func TestParseVulnerabilityFeed_NVDLegacy(t *testing.T) {
+	feed, err := parseVulnerabilityFeed([]byte(testNVDLegacyFeed))
+	require.NoError(t, err)
+
+	assert.Equal(t, dto.VulnerabilityFeedNVD, feed.format)
+	assert.Equal(t, 1, feed.skipped, "rejected CVEs are skipped")
+	require.Len(t, feed.cves, 2)
+
+	log4shell := feed.cves[0]
+	assert.Equal(t, "CVE-2021-44228", log4shell.CVEID)
+	require.NotNil(t, log4shell.Description)
+	assert.Equal(t, "Apache Log4j2 JNDI features do not protect against attacker controlled LDAP.", *log4shell.Description)
+	require.NotNil(t, log4shell.CVSSScore)
+	assert.Equal(t, 10.0, *log4shell.CVSSScore, "CVSS v3 is preferred over v2")
+	assert.Equal(t, "3.1", *log4shell.CVSSVersion)
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", *log4shell.CVSSVector)
+	assert.Equal(t, dto.CVESeverityCritical, *log4shell.Severity)
+	assert.Equal(t, timePtr(time.Date(2021, 12, 10, 10, 15, 0, 0, time.UTC)), log4shell.PublishedAt)
+	assert.Equal(t, timePtr(time.Date(2022, 2, 1, 14, 21, 0, 0, time.UTC)), log4shell.ModifiedAt)
+
+	// nested children are collected; duplicates, non-vulnerable and hardware configurations are dropped
+	require.Len(t, log4shell.Matches, 2)
+	assert.Equal(t, repo.CVECPEMatch{
+		CPE23:                 "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*",
+		Vendor:                "apache",
+		Product:               "log4j",
+		VersionStartIncluding: stringPtr("2.0.1"),
+		VersionEndExcluding:   stringPtr("2.12.2"),
+	}, log4shell.Matches[0])
+	assert.Equal(t, "log4j", log4shell.Matches[1].Product)
+	assert.Equal(t, stringPtr("2.0"), log4shell.Matches[1].Version)
+
+	old := feed.cves[1]
+	assert.Equal(t, "CVE-2019-0002", old.CVEID)
+	assert.Equal(t, 5.0, *old.CVSSScore)
+	assert.Equal(t, "2.0", *old.CVSSVersion)
+	assert.Equal(t, dto.CVESeverityMedium, *old.Severity)
+	assert.Nil(t, old.CVSSVector)
+	assert.Nil(t, old.PublishedAt)
+	assert.Empty(t, old.Matches)
+}
+
+func TestParseVulnerabilityFeed_NVDAPI(t *testing.T) {
+	feed, err := parseVulnerabilityFeed([]byte(testNVDAPIFeed))
+	require.NoError(t, err)
+
+	assert.Equal(t, dto.VulnerabilityFeedNVD, feed.format)
+	assert.Equal(t, 1, feed.skipped)
+	require.Len(t, feed.cves, 2)
+
+	webp := feed.cves[0]
+	assert.Equal(t, "CVE-2023-4863", webp.CVEID)
+	assert.Equal(t, "Heap buffer overflow in libwebp.", *webp.Description, "the English description is preferred")
+	assert.Equal(t, 8.8, *webp.CVSSScore, "the primary NVD metric is preferred over the CNA one")
+	assert.Equal(t, dto.CVESeverityHigh, *webp.Severity)
+	assert.Equal(t, timePtr(time.Date(2023, 9, 12, 15, 15, 24, 327000000, time.UTC)), webp.PublishedAt)
+	require.Len(t, webp.Matches, 2)
+	assert.Equal(t, "google", webp.Matches[0].Vendor)
+	assert.Equal(t, "chrome", webp.Matches[0].Product)
+	assert.Nil(t, webp.Matches[0].Version)
+	assert.Equal(t, stringPtr("116.0.5845.187"), webp.Matches[0].VersionEndExcluding)
+	assert.Equal(t, "firefox", webp.Matches[1].Product)
+
+	old := feed.cves[1]
+	assert.Equal(t, 7.5, *old.CVSSScore)
+	assert.Equal(t, dto.CVESeverityHigh, *old.Severity, "CVSS v2 severity is read outside cvssData")
+}
+
+func TestParseVulnerabilityFeed_CPEDictionary(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantSkipped int
+		want        []repo.CPEDictionaryEntry
+	}{
+		{
+			name:        "CPE API 2.0",
+			data:        testCPEAPIFeed,
+			wantSkipped: 2,
+			want: []repo.CPEDictionaryEntry{
+				{CPEProduct: repo.CPEProduct{Vendor: "mozilla", Product: "firefox"}, Title: "Mozilla Firefox 115.0", NormalizedTitle: "mozilla firefox"},
+			},
+		},
+		{
+			name: "official dictionary XML",
+			data: testCPEDictionaryXML,
+			want: []repo.CPEDictionaryEntry{
+				{CPEProduct: repo.CPEProduct{Vendor: "7-zip", Product: "7-zip"}, Title: "7-Zip 23.01", NormalizedTitle: "7-zip"},
+				{CPEProduct: repo.CPEProduct{Vendor: "notepad-plus-plus", Product: "notepad++"}, Title: "Notepad++ v8.5.4", NormalizedTitle: "notepad++"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := parseVulnerabilityFeed([]byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, dto.VulnerabilityFeedCPEDictionary, feed.format)
+			assert.Equal(t, tt.wantSkipped, feed.skipped)
+			assert.Equal(t, tt.want, feed.dictionary)
+			assert.Empty(t, feed.cves)
+		})
+	}
+}
+
+func TestParseVulnerabilityFeed_Archives(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"gzip", gzipFeed(t, testNVDAPIFeed)},
+		{"zip", zipFeed(t, "nvdcve-2.0-modified.json", testNVDAPIFeed)},
+		{"byte order mark", append([]byte("\xef\xbb\xbf\n"), testNVDAPIFeed...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := parseVulnerabilityFeed(tt.data)
+			require.NoError(t, err)
+			require.Len(t, feed.cves, 2)
+			assert.Equal(t, "CVE-2023-4863", feed.cves[0].CVEID)
+		})
+	}
+}
+
+func TestParseVulnerabilityFeed_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", []byte("  \n")},
+		{"not json", []byte("CVE-2021-44228,10.0")},
+		{"no known sections", []byte(`{"resultsPerPage": 0, "vulnerabilities": []}`)},
+		{"xml without cpe items", []byte(`<?xml version="1.0"?><cpe-list></cpe-list>`)},
+		{"broken xml", []byte(`<cpe-list><cpe-item>`)},
+		{"broken gzip", []byte{0x1f, 0x8b, 0x00, 0x01}},
+		{"zip without feed", zipFeed(t, "README.txt", testNVDAPIFeed)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseVulnerabilityFeed(tt.data)
+			assert.ErrorIs(t, err, ErrInvalidVulnerabilityFeed)
+		})
+	}
+}
+
+func TestParseCPE23(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		wantOK  bool
+		want    cpeName
+		version string
+	}{
+		{
+			name:    "application with version",
+			uri:     "cpe:2.3:a:mozilla:firefox:115.0:*:*:*:*:*:*:*",
+			wantOK:  true,
+			want:    cpeName{vendor: "mozilla", product: "firefox"},
+			version: "115.0",
+		},
+		{
+			name:   "operating system, any version",
+			uri:    "cpe:2.3:o:Microsoft:Windows_10:*:*:*:*:*:*:*:*",
+			wantOK: true,
+			want:   cpeName{vendor: "microsoft", product: "windows_10"},
+		},
+		{
+			name:   "not applicable version",
+			uri:    "cpe:2.3:a:vendor:product:-:*:*:*:*:*:*:*",
+			wantOK: true,
+			want:   cpeName{vendor: "vendor", product: "product"},
+		},
+		{
+			name:    "escaped characters",
+			uri:     `cpe:2.3:a:microsoft:visual_c\+\+:2019\:sp1:*:*:*:*:*:*:*`,
+			wantOK:  true,
+			want:    cpeName{vendor: "microsoft", product: "visual_c++"},
+			version: "2019:sp1",
+		},
+		{name: "hardware", uri: "cpe:2.3:h:cisco:router:-:*:*:*:*:*:*:*"},
+		{name: "CPE 2.2 URI", uri: "cpe:/a:mozilla:firefox:115.0"},
+		{name: "too short", uri: "cpe:2.3:a:mozilla:firefox"},
+		{name: "any vendor", uri: "cpe:2.3:a:*:firefox:115.0:*:*:*:*:*:*:*"},
+		{name: "empty product", uri: "cpe:2.3:a:mozilla::115.0:*:*:*:*:*:*:*"},
+		{name: "empty", uri: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ok := parseCPE23(tt.uri)
+			require.Equal(t, tt.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, tt.want.vendor, name.vendor)
+			assert.Equal(t, tt.want.product, name.product)
+			if tt.version == "" {
+				assert.Nil(t, name.version)
+			} else {
+				require.NotNil(t, name.version)
+				assert.Equal(t, tt.version, *name.version)
+			}
+		})
+	}
+}
+
+func TestCVSSSeverity(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{10.0, dto.CVESeverityCritical},
+		{9.0, dto.CVESeverityCritical},
+		{8.9, dto.CVESeverityHigh},
+		{7.0, dto.CVESeverityHigh},
+		{6.9, dto.CVESeverityMedium},
+		{4.0, dto.CVESeverityMedium},
+		{3.9, dto.CVESeverityLow},
+		{0.1, dto.CVESeverityLow},
+		{0, dto.CVESeverityNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, cvssSeverity(tt.score))
+		})
+	}
+}
+
+func TestParseNVDTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *time.Time
+	}{
+		{"2019-01-01T05:29Z", timePtr(time.Date(2019, 1, 1, 5, 29, 0, 0, time.UTC))},
+		{"2021-12-10T10:15:09.143", timePtr(time.Date(2021, 12, 10, 10, 15, 9, 143000000, time.UTC))},
+		{"2021-12-10T10:15:09Z", timePtr(time.Date(2021, 12, 10, 10, 15, 9, 0, time.UTC))},
+		{"10.12.2021", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := parseNVDTime(tt.value)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.True(t, tt.want.Equal(*got), "got %s", got)
+		})
+	}
+}
+
+func TestNormalizeSoftwareName(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{"Mozilla Firefox 115.0 (x64 en-US)", "115.0", "mozilla firefox"},
+		{"Mozilla Firefox 115.0", "", "mozilla firefox"},
+		{"7-Zip 23.01 (x64)", "23.01", "7-zip"},
+		{"Python 3.11.4 x86_64", "3.11.4", "python"},
+		{"Notepad++ v8.5.4", "", "notepad++"},
+		{"Notepad++ v8.5.4 (64-bit x64)", "8.5.4", "notepad++"},
+		{"Microsoft Visual C++ 2019 X64 Minimum Runtime - 14.29.30133", "14.29.30133", "microsoft visual c++ minimum runtime"},
+		{"[Beta] Tool 1.0", "", "tool"},
+		{"openssl", "3.0.2-0ubuntu1.10", "openssl"},
+		{"1.0", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeSoftwareName(tt.name, tt.version))
+		})
+	}
+}
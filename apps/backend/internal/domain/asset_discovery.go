@@ -0,0 +1,556 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// Discovery agents
+
+func (s *AssetService) ListDiscoveryAgents(ctx context.Context, tenantID string) ([]*repo.AssetDiscoveryAgent, error) {
+	log.Printf("DEBUG: asset_service.ListDiscoveryAgents tenant=%s", tenantID)
+
+	agents, err := s.assetRepo.ListDiscoveryAgents(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: asset_service.ListDiscoveryAgents ListDiscoveryAgents: %v", err)
+		return nil, err
+	}
+	return agents, nil
+}
+
+// CreateDiscoveryAgent registers an agent and returns it together with its token, which is not stored in plain text
+func (s *AssetService) CreateDiscoveryAgent(ctx context.Context, tenantID string, req dto.AssetDiscoveryAgentRequest, createdBy string) (*repo.AssetDiscoveryAgent, string, error) {
+	log.Printf("DEBUG: asset_service.CreateDiscoveryAgent tenant=%s name=%s", tenantID, req.Name)
+
+	token, err := newAccessToken(discoveryAgentTokenPrefix)
+	if err != nil {
+		log.Printf("ERROR: asset_service.CreateDiscoveryAgent newAccessToken: %v", err)
+		return nil, "", err
+	}
+
+	agent := &repo.AssetDiscoveryAgent{
+		ID:                    uuid.New().String(),
+		TenantID:              tenantID,
+		ReporterID:            createdBy,
+		RemoveMissingSoftware: true,
+		IsActive:              true,
+		CreatedAt:             time.Now(),
+	}
+	agent.TokenHash, agent.TokenPrefix = token.Hash, token.Prefix
+	if err := s.applyDiscoveryAgentRequest(ctx, agent, req); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.assetRepo.SaveDiscoveryAgent(ctx, agent); err != nil {
+		log.Printf("ERROR: asset_service.CreateDiscoveryAgent SaveDiscoveryAgent: %v", err)
+		return nil, "", err
+	}
+
+	log.Printf("INFO: asset_service.CreateDiscoveryAgent created id=%s", agent.ID)
+	return agent, token.Value, nil
+}
+
+func (s *AssetService) UpdateDiscoveryAgent(ctx context.Context, id, tenantID string, req dto.AssetDiscoveryAgentRequest) (*repo.AssetDiscoveryAgent, error) {
+	log.Printf("DEBUG: asset_service.UpdateDiscoveryAgent id=%s tenant=%s", id, tenantID)
+
+	agent, err := s.getDiscoveryAgent(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyDiscoveryAgentRequest(ctx, agent, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.assetRepo.SaveDiscoveryAgent(ctx, agent); err != nil {
+		log.Printf("ERROR: asset_service.UpdateDiscoveryAgent SaveDiscoveryAgent: %v", err)
+		return nil, err
+	}
+	return agent, nil
+}
+
+// RotateDiscoveryAgentToken issues a new token; the previous one stops working immediately
+func (s *AssetService) RotateDiscoveryAgentToken(ctx context.Context, id, tenantID string) (*repo.AssetDiscoveryAgent, string, error) {
+	log.Printf("DEBUG: asset_service.RotateDiscoveryAgentToken id=%s tenant=%s", id, tenantID)
+
+	agent, err := s.getDiscoveryAgent(ctx, id, tenantID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := newAccessToken(discoveryAgentTokenPrefix)
+	if err != nil {
+		log.Printf("ERROR: asset_service.RotateDiscoveryAgentToken newAccessToken: %v", err)
+		return nil, "", err
+	}
+	agent.TokenHash, agent.TokenPrefix = token.Hash, token.Prefix
+	agent.UpdatedAt = time.Now()
+
+	if err := s.assetRepo.SaveDiscoveryAgent(ctx, agent); err != nil {
+		log.Printf("ERROR: asset_service.RotateDiscoveryAgentToken SaveDiscoveryAgent: %v", err)
+		return nil, "", err
+	}
+	return agent, token.Value, nil
+}
+
+func (s *AssetService) DeleteDiscoveryAgent(ctx context.Context, id, tenantID string) error {
+	log.Printf("DEBUG: asset_service.DeleteDiscoveryAgent id=%s tenant=%s", id, tenantID)
+
+	if _, err := s.getDiscoveryAgent(ctx, id, tenantID); err != nil {
+		return err
+	}
+
+	if err := s.assetRepo.DeleteDiscoveryAgent(ctx, id, tenantID); err != nil {
+		log.Printf("ERROR: asset_service.DeleteDiscoveryAgent DeleteDiscoveryAgent: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *AssetService) getDiscoveryAgent(ctx context.Context, id, tenantID string) (*repo.AssetDiscoveryAgent, error) {
+	agent, err := s.assetRepo.GetDiscoveryAgent(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: asset_service.getDiscoveryAgent GetDiscoveryAgent: %v", err)
+		return nil, err
+	}
+	if agent == nil {
+		return nil, ErrDiscoveryAgentNotFound
+	}
+	return agent, nil
+}
+
+func (s *AssetService) applyDiscoveryAgentRequest(ctx context.Context, agent *repo.AssetDiscoveryAgent, req dto.AssetDiscoveryAgentRequest) error {
+	if req.ReporterID != nil && *req.ReporterID != "" {
+		reporter, err := s.userRepo.GetByID(ctx, *req.ReporterID)
+		if err != nil {
+			log.Printf("ERROR: asset_service.applyDiscoveryAgentRequest GetByID reporter: %v", err)
+			return err
+		}
+		if reporter == nil || reporter.TenantID != agent.TenantID {
+			return NewValidationError("reporter_id", "user not found")
+		}
+		agent.ReporterID = *req.ReporterID
+	}
+
+	agent.Name = req.Name
+	if req.RemoveMissingSoftware != nil {
+		agent.RemoveMissingSoftware = *req.RemoveMissingSoftware
+	}
+	if req.IsActive != nil {
+		agent.IsActive = *req.IsActive
+	}
+	agent.UpdatedAt = time.Now()
+	return nil
+}
+
+// Reports
+
+// IngestDiscoveryReport authenticates the agent by token, matches the report to an asset by serial number,
+// MAC address or hostname and updates the asset passport and software; reports that match no asset
+// or several assets are left in the reconciliation queue
+func (s *AssetService) IngestDiscoveryReport(ctx context.Context, token string, body []byte) (*repo.AssetDiscoveryReport, error) {
+	if token == "" {
+		return nil, ErrDiscoveryAgentUnauthorized
+	}
+	agent, err := s.assetRepo.GetDiscoveryAgentByTokenHash(ctx, hashAccessToken(token))
+	if err != nil {
+		log.Printf("ERROR: asset_service.IngestDiscoveryReport GetDiscoveryAgentByTokenHash: %v", err)
+		return nil, err
+	}
+	if agent == nil || !agent.IsActive {
+		log.Printf("WARN: asset_service.IngestDiscoveryReport rejected unknown or inactive token")
+		return nil, ErrDiscoveryAgentUnauthorized
+	}
+
+	format, inventory, err := parseDiscoveryReport(body)
+	if err != nil {
+		log.Printf("WARN: asset_service.IngestDiscoveryReport agent=%s parse: %v", agent.ID, err)
+		return nil, err
+	}
+	log.Printf("DEBUG: asset_service.IngestDiscoveryReport agent=%s tenant=%s format=%s host=%s", agent.ID, agent.TenantID, format, inventory.Hostname)
+
+	s.discoveryMu.Lock()
+	defer s.discoveryMu.Unlock()
+
+	now := time.Now()
+	report := &repo.AssetDiscoveryReport{
+		ID:            uuid.New().String(),
+		TenantID:      agent.TenantID,
+		AgentID:       &agent.ID,
+		Format:        format,
+		MACAddresses:  inventory.MACAddresses,
+		IPAddresses:   inventory.IPAddresses,
+		Inventory:     *inventory,
+		Status:        dto.AssetDiscoveryStatusPending,
+		CandidateIDs:  []string{},
+		ChangedFields: []string{},
+		ReceivedAt:    now,
+	}
+	if inventory.Hostname != "" {
+		report.Hostname = &inventory.Hostname
+	}
+	if inventory.SerialNumber != "" {
+		report.SerialNumber = &inventory.SerialNumber
+	}
+
+	assetID, matchedBy, candidateIDs, err := s.matchDiscoveryReport(ctx, agent.TenantID, inventory)
+	if err != nil {
+		log.Printf("ERROR: asset_service.IngestDiscoveryReport matchDiscoveryReport: %v", err)
+		return nil, err
+	}
+	report.CandidateIDs = candidateIDs
+
+	if assetID != "" {
+		asset, err := s.assetRepo.GetByID(ctx, assetID)
+		if err != nil {
+			log.Printf("ERROR: asset_service.IngestDiscoveryReport GetByID: %v", err)
+			return nil, err
+		}
+		if err := s.applyDiscoveryReport(ctx, asset, report, agent.RemoveMissingSoftware, agent.ReporterID); err != nil {
+			log.Printf("ERROR: asset_service.IngestDiscoveryReport applyDiscoveryReport asset=%s: %v", assetID, err)
+			return nil, err
+		}
+		report.Status = dto.AssetDiscoveryStatusApplied
+		report.AssetID = &assetID
+		report.MatchedBy = &matchedBy
+	}
+
+	if err := s.assetRepo.SaveDiscoveryReport(ctx, report); err != nil {
+		log.Printf("ERROR: asset_service.IngestDiscoveryReport SaveDiscoveryReport: %v", err)
+		return nil, err
+	}
+	if err := s.assetRepo.TouchDiscoveryAgent(ctx, agent.ID, now); err != nil {
+		log.Printf("WARN: asset_service.IngestDiscoveryReport TouchDiscoveryAgent: %v", err)
+	}
+
+	log.Printf("INFO: asset_service.IngestDiscoveryReport report=%s status=%s asset=%s changed=%d software=+%d/~%d/-%d",
+		report.ID, report.Status, assetID, len(report.ChangedFields), report.SoftwareAdded, report.SoftwareUpdated, report.SoftwareRemoved)
+	return report, nil
+}
+
+func (s *AssetService) ListDiscoveryReports(ctx context.Context, tenantID string, filters map[string]interface{}, page, pageSize int) ([]*repo.AssetDiscoveryReport, int64, error) {
+	reports, total, err := s.assetRepo.ListDiscoveryReports(ctx, tenantID, filters, page, pageSize)
+	if err != nil {
+		log.Printf("ERROR: asset_service.ListDiscoveryReports ListDiscoveryReports: %v", err)
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+func (s *AssetService) GetDiscoveryReport(ctx context.Context, id, tenantID string) (*repo.AssetDiscoveryReport, error) {
+	report, err := s.assetRepo.GetDiscoveryReport(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: asset_service.GetDiscoveryReport GetDiscoveryReport: %v", err)
+		return nil, err
+	}
+	if report == nil {
+		return nil, ErrDiscoveryReportNotFound
+	}
+	return report, nil
+}
+
+// ResolveDiscoveryReport takes a report out of the reconciliation queue: applies it to an existing asset,
+// creates a new asset from it or ignores it
+func (s *AssetService) ResolveDiscoveryReport(ctx context.Context, id, tenantID string, req dto.AssetDiscoveryResolveRequest, userID string) (*repo.AssetDiscoveryReport, error) {
+	log.Printf("DEBUG: asset_service.ResolveDiscoveryReport id=%s action=%s", id, req.Action)
+
+	s.discoveryMu.Lock()
+	defer s.discoveryMu.Unlock()
+
+	report, err := s.GetDiscoveryReport(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != dto.AssetDiscoveryStatusPending {
+		return nil, ErrDiscoveryReportResolved
+	}
+
+	removeMissing := false
+	if report.AgentID != nil {
+		agent, err := s.assetRepo.GetDiscoveryAgent(ctx, *report.AgentID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		removeMissing = agent != nil && agent.RemoveMissingSoftware
+	}
+
+	var matchedBy string
+	switch req.Action {
+	case "ignore":
+		report.Status = dto.AssetDiscoveryStatusIgnored
+	case "link":
+		asset, err := s.assetRepo.GetByID(ctx, *req.AssetID)
+		if err != nil {
+			return nil, err
+		}
+		if asset == nil || asset.TenantID != tenantID {
+			return nil, ErrAssetNotFound
+		}
+		if asset.Status == dto.AssetStatusDecommissioned {
+			return nil, NewValidationError("asset_id", "cannot apply a report to a decommissioned asset")
+		}
+		if err := s.applyDiscoveryReport(ctx, asset, report, removeMissing, userID); err != nil {
+			log.Printf("ERROR: asset_service.ResolveDiscoveryReport applyDiscoveryReport: %v", err)
+			return nil, err
+		}
+		matchedBy = dto.AssetDiscoveryMatchManual
+	case "create":
+		created, err := s.CreateAsset(ctx, tenantID, discoveryAssetRequest(*req.Asset, report.Inventory), userID)
+		if err != nil {
+			log.Printf("ERROR: asset_service.ResolveDiscoveryReport CreateAsset: %v", err)
+			return nil, err
+		}
+		asset, err := s.assetRepo.GetByID(ctx, created.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.applyDiscoveryReport(ctx, asset, report, removeMissing, userID); err != nil {
+			log.Printf("ERROR: asset_service.ResolveDiscoveryReport applyDiscoveryReport: %v", err)
+			return nil, err
+		}
+		matchedBy = dto.AssetDiscoveryMatchCreated
+	}
+
+	now := time.Now()
+	if matchedBy != "" {
+		report.Status = dto.AssetDiscoveryStatusApplied
+		report.MatchedBy = &matchedBy
+	}
+	report.ResolvedBy = &userID
+	report.ResolvedAt = &now
+
+	if err := s.assetRepo.SaveDiscoveryReport(ctx, report); err != nil {
+		log.Printf("ERROR: asset_service.ResolveDiscoveryReport SaveDiscoveryReport: %v", err)
+		return nil, err
+	}
+	return report, nil
+}
+
+// discoveryAssetRequest fills the passport fields the user left empty from the report
+func discoveryAssetRequest(req dto.CreateAssetRequest, inventory dto.AssetDiscoveryInventory) dto.CreateAssetRequest {
+	fill := func(field **string, value string, limit int) {
+		if *field == nil && value != "" {
+			value = truncateRunes(value, limit)
+			*field = &value
+		}
+	}
+	fill(&req.SerialNumber, inventory.SerialNumber, 255)
+	fill(&req.PCNumber, inventory.Hostname, 100)
+	fill(&req.Model, inventory.Model, 255)
+	fill(&req.Manufacturer, inventory.Manufacturer, 255)
+	fill(&req.CPU, inventory.CPU, 255)
+	fill(&req.RAM, inventory.RAM, 100)
+	fill(&req.HDDInfo, inventory.HDDInfo, 10000)
+	fill(&req.NetworkCard, inventory.NetworkCard, 255)
+	if len(inventory.IPAddresses) > 0 {
+		fill(&req.IPAddress, inventory.IPAddresses[0], 45)
+	}
+	if len(inventory.MACAddresses) > 0 {
+		fill(&req.MACAddress, inventory.MACAddresses[0], 17)
+	}
+	return req
+}
+
+// matchDiscoveryReport looks for the asset by serial number, then MAC address, then hostname. The first
+// criterion that finds assets decides: one active asset is a match, several assets or a decommissioned one
+// are returned as candidates for manual reconciliation.
+func (s *AssetService) matchDiscoveryReport(ctx context.Context, tenantID string, inventory *dto.AssetDiscoveryInventory) (string, string, []string, error) {
+	var serials, hostnames []string
+	serial := strings.ToLower(inventory.SerialNumber)
+	if serial != "" {
+		serials = append(serials, serial)
+	}
+	if hostname := strings.ToLower(inventory.Hostname); hostname != "" {
+		hostnames = append(hostnames, hostname)
+		// Имя хоста без домена: в реестре активов часто хранится короткое имя
+		if short, _, found := strings.Cut(hostname, "."); found && short != "" {
+			hostnames = append(hostnames, short)
+		}
+	}
+
+	assets, err := s.assetRepo.FindDiscoveryCandidates(ctx, tenantID, serials, inventory.MACAddresses, hostnames)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	criteria := []struct {
+		name    string
+		matches func(asset repo.Asset) bool
+	}{
+		{dto.AssetDiscoveryMatchSerial, func(asset repo.Asset) bool {
+			return serial != "" && asset.SerialNumber != nil && strings.ToLower(*asset.SerialNumber) == serial
+		}},
+		{dto.AssetDiscoveryMatchMAC, func(asset repo.Asset) bool {
+			return asset.MACAddress != nil && containsString(inventory.MACAddresses, *asset.MACAddress)
+		}},
+		{dto.AssetDiscoveryMatchHostname, func(asset repo.Asset) bool {
+			return containsString(hostnames, strings.ToLower(asset.Name)) ||
+				(asset.PCNumber != nil && containsString(hostnames, strings.ToLower(*asset.PCNumber)))
+		}},
+	}
+	for _, criterion := range criteria {
+		var matched []repo.Asset
+		for _, asset := range assets {
+			if criterion.matches(asset) {
+				matched = append(matched, asset)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		candidateIDs := make([]string, 0, len(matched))
+		for _, asset := range matched {
+			candidateIDs = append(candidateIDs, asset.ID)
+		}
+		if len(matched) == 1 && matched[0].Status != dto.AssetStatusDecommissioned {
+			return matched[0].ID, criterion.name, candidateIDs, nil
+		}
+		return "", "", candidateIDs, nil
+	}
+	return "", "", []string{}, nil
+}
+
+// applyDiscoveryReport updates the passport fields present in the report, synchronizes installed software
+// and records every change in the asset history
+func (s *AssetService) applyDiscoveryReport(ctx context.Context, asset *repo.Asset, report *repo.AssetDiscoveryReport, removeMissing bool, changedBy string) error {
+	inventory := report.Inventory
+	type change struct{ field, oldValue, newValue string }
+	var changes []change
+
+	update := func(field string, target **string, value string, limit int) {
+		value = truncateRunes(value, limit)
+		if value == "" {
+			return
+		}
+		oldValue := ""
+		if *target != nil {
+			oldValue = **target
+		}
+		if oldValue == value {
+			return
+		}
+		changes = append(changes, change{field, oldValue, value})
+		*target = &value
+	}
+	update("serial_number", &asset.SerialNumber, inventory.SerialNumber, 255)
+	update("model", &asset.Model, inventory.Model, 255)
+	update("manufacturer", &asset.Manufacturer, inventory.Manufacturer, 255)
+	update("cpu", &asset.CPU, inventory.CPU, 255)
+	update("ram", &asset.RAM, inventory.RAM, 100)
+	update("hdd_info", &asset.HDDInfo, inventory.HDDInfo, 10000)
+	update("network_card", &asset.NetworkCard, inventory.NetworkCard, 255)
+	// Адреса меняются, только если текущего адреса актива нет в отчете
+	if asset.IPAddress == nil || !containsString(inventory.IPAddresses, *asset.IPAddress) {
+		if len(inventory.IPAddresses) > 0 {
+			update("ip_address", &asset.IPAddress, inventory.IPAddresses[0], 45)
+		}
+	}
+	if asset.MACAddress == nil || !containsString(inventory.MACAddresses, normalizeDiscoveryMAC(*asset.MACAddress)) {
+		if len(inventory.MACAddresses) > 0 {
+			update("mac_address", &asset.MACAddress, inventory.MACAddresses[0], 17)
+		}
+	}
+
+	if len(changes) > 0 {
+		asset.UpdatedAt = time.Now()
+		if err := s.assetRepo.Update(ctx, *asset); err != nil {
+			return err
+		}
+		for _, c := range changes {
+			report.ChangedFields = append(report.ChangedFields, c.field)
+			if err := s.assetRepo.AddHistory(ctx, asset.ID, c.field, c.oldValue, c.newValue, changedBy); err != nil {
+				log.Printf("WARN: asset_service.applyDiscoveryReport AddHistory %s: %v", c.field, err)
+			}
+		}
+	}
+
+	return s.syncDiscoverySoftware(ctx, asset.ID, report, removeMissing, changedBy)
+}
+
+func (s *AssetService) syncDiscoverySoftware(ctx context.Context, assetID string, report *repo.AssetDiscoveryReport, removeMissing bool, changedBy string) error {
+	reported := report.Inventory.Software
+	// Отчет без раздела ПО не означает, что ПО удалено
+	if len(reported) == 0 {
+		return nil
+	}
+
+	installed, err := s.assetRepo.GetAssetSoftware(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]repo.AssetSoftware, len(installed))
+	for _, software := range installed {
+		key := strings.ToLower(software.SoftwareName)
+		if _, ok := existing[key]; !ok {
+			existing[key] = software
+		}
+	}
+
+	addHistory := func(field, oldValue, newValue string) {
+		if err := s.assetRepo.AddHistory(ctx, assetID, field, oldValue, newValue, changedBy); err != nil {
+			log.Printf("WARN: asset_service.syncDiscoverySoftware AddHistory %s: %v", field, err)
+		}
+	}
+
+	seen := make(map[string]bool, len(reported))
+	for _, item := range reported {
+		key := strings.ToLower(item.Name)
+		seen[key] = true
+
+		current, ok := existing[key]
+		if !ok {
+			if err := s.assetRepo.AddSoftware(ctx, assetID, item.Name, item.Version, item.InstalledAt); err != nil {
+				return err
+			}
+			report.SoftwareAdded++
+			addHistory("software_added", "", softwareLabel(item.Name, item.Version))
+			continue
+		}
+
+		currentVersion := ""
+		if current.Version != nil {
+			currentVersion = *current.Version
+		}
+		if item.Version != "" && item.Version != currentVersion {
+			if err := s.assetRepo.UpdateSoftware(ctx, current.ID, item.Version, item.InstalledAt); err != nil {
+				return err
+			}
+			report.SoftwareUpdated++
+			addHistory("software_updated", softwareLabel(current.SoftwareName, currentVersion), softwareLabel(item.Name, item.Version))
+		}
+	}
+
+	if removeMissing {
+		for _, software := range installed {
+			if seen[strings.ToLower(software.SoftwareName)] {
+				continue
+			}
+			if err := s.assetRepo.DeleteSoftware(ctx, software.ID); err != nil {
+				return err
+			}
+			report.SoftwareRemoved++
+			version := ""
+			if software.Version != nil {
+				version = *software.Version
+			}
+			addHistory("software_removed", softwareLabel(software.SoftwareName, version), "Software removed")
+		}
+	}
+	return nil
+}
+
+func softwareLabel(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return fmt.Sprintf("%s %s", name, version)
+}
@@ -0,0 +1,414 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+)
+
+// maxDiscoverySoftware limits the number of packages accepted from one report
+const maxDiscoverySoftware = 5000
+
+// parseDiscoveryReport detects the report format (native, osquery table results or OCS inventory)
+// and converts the report to the native inventory with normalized identifiers
+func parseDiscoveryReport(body []byte) (string, *dto.AssetDiscoveryInventory, error) {
+	body = bytes.TrimPrefix(bytes.TrimSpace(body), []byte("\xef\xbb\xbf"))
+	if len(body) == 0 {
+		return "", nil, fmt.Errorf("%w: empty body", ErrInvalidDiscoveryReport)
+	}
+
+	var document map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidDiscoveryReport, err)
+	}
+
+	var format string
+	var inventory *dto.AssetDiscoveryInventory
+	if tables := osqueryTables(document); tables != nil {
+		format, inventory = dto.AssetDiscoveryFormatOsquery, parseOsqueryInventory(tables)
+	} else if sections := ocsSections(document); sections != nil {
+		format, inventory = dto.AssetDiscoveryFormatOCS, parseOCSInventory(sections)
+	} else {
+		inventory = &dto.AssetDiscoveryInventory{}
+		if err := json.Unmarshal(body, inventory); err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidDiscoveryReport, err)
+		}
+		format = dto.AssetDiscoveryFormatNative
+	}
+
+	normalizeDiscoveryInventory(inventory)
+	if inventory.Hostname == "" && inventory.SerialNumber == "" && len(inventory.MACAddresses) == 0 {
+		return "", nil, fmt.Errorf("%w: report has no hostname, serial number or MAC address", ErrInvalidDiscoveryReport)
+	}
+	if len(inventory.Software) > maxDiscoverySoftware {
+		return "", nil, fmt.Errorf("%w: too many software entries (%d, max %d)", ErrInvalidDiscoveryReport, len(inventory.Software), maxDiscoverySoftware)
+	}
+	return format, inventory, nil
+}
+
+// normalizeDiscoveryInventory trims values, drops placeholder serial numbers, normalizes MAC and IP addresses
+// and removes duplicate software entries
+func normalizeDiscoveryInventory(inventory *dto.AssetDiscoveryInventory) {
+	for _, value := range []*string{&inventory.Hostname, &inventory.Manufacturer, &inventory.Model, &inventory.CPU,
+		&inventory.RAM, &inventory.HDDInfo, &inventory.NetworkCard, &inventory.OS} {
+		*value = strings.TrimSpace(*value)
+	}
+	inventory.Hostname = strings.TrimSuffix(inventory.Hostname, ".")
+	inventory.SerialNumber = normalizeDiscoverySerial(inventory.SerialNumber)
+
+	macAddresses := []string{}
+	for _, value := range inventory.MACAddresses {
+		if mac := normalizeDiscoveryMAC(value); mac != "" && !containsString(macAddresses, mac) {
+			macAddresses = append(macAddresses, mac)
+		}
+	}
+	inventory.MACAddresses = macAddresses
+
+	ipAddresses := []string{}
+	for _, value := range inventory.IPAddresses {
+		ip := net.ParseIP(strings.TrimSpace(strings.Split(value, "/")[0]))
+		if ip == nil || ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		if address := ip.String(); !containsString(ipAddresses, address) {
+			ipAddresses = append(ipAddresses, address)
+		}
+	}
+	// IPv4 адреса первыми: первый адрес записывается в паспорт актива
+	sort.SliceStable(ipAddresses, func(i, j int) bool {
+		return net.ParseIP(ipAddresses[i]).To4() != nil && net.ParseIP(ipAddresses[j]).To4() == nil
+	})
+	inventory.IPAddresses = ipAddresses
+
+	seen := make(map[string]bool, len(inventory.Software))
+	software := make([]dto.AssetDiscoverySoftware, 0, len(inventory.Software))
+	for _, item := range inventory.Software {
+		item.Name = truncateRunes(strings.TrimSpace(item.Name), 255)
+		item.Version = truncateRunes(strings.TrimSpace(item.Version), 100)
+		key := strings.ToLower(item.Name)
+		if item.Name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		software = append(software, item)
+	}
+	inventory.Software = software
+}
+
+// Serial numbers that firmware reports when the vendor did not set one
+var placeholderSerials = map[string]bool{
+	"0": true, "none": true, "n/a": true, "na": true, "unknown": true, "default string": true,
+	"to be filled by o.e.m.": true, "system serial number": true, "chassis serial number": true,
+	"not specified": true, "not applicable": true, "0123456789": true, "123456789": true,
+}
+
+func normalizeDiscoverySerial(value string) string {
+	value = strings.TrimSpace(value)
+	if placeholderSerials[strings.ToLower(value)] || strings.Trim(value, "0") == "" {
+		return ""
+	}
+	return value
+}
+
+// normalizeDiscoveryMAC returns the address in the lower-case colon form PostgreSQL uses for MACADDR,
+// or an empty string for invalid, empty and broadcast addresses
+func normalizeDiscoveryMAC(value string) string {
+	mac, err := net.ParseMAC(strings.TrimSpace(value))
+	if err != nil || len(mac) != 6 {
+		return ""
+	}
+	if bytes.Equal(mac, net.HardwareAddr{0, 0, 0, 0, 0, 0}) || bytes.Equal(mac, net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+		return ""
+	}
+	return mac.String()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// osquery
+
+// osqueryTables returns the table results of an osquery report: either {"table": [rows]}
+// or the distributed query response {"queries": {"table": [rows]}}
+func osqueryTables(document map[string]interface{}) map[string][]map[string]interface{} {
+	if queries, ok := document["queries"].(map[string]interface{}); ok {
+		document = queries
+	}
+	if _, ok := document["system_info"]; !ok {
+		return nil
+	}
+
+	tables := make(map[string][]map[string]interface{})
+	for name, value := range document {
+		tables[name] = discoveryRows(value)
+	}
+	return tables
+}
+
+func parseOsqueryInventory(tables map[string][]map[string]interface{}) *dto.AssetDiscoveryInventory {
+	inventory := &dto.AssetDiscoveryInventory{}
+	if rows := tables["system_info"]; len(rows) > 0 {
+		info := rows[0]
+		inventory.Hostname = discoveryField(info, "hostname")
+		if inventory.Hostname == "" {
+			inventory.Hostname = discoveryField(info, "computer_name")
+		}
+		inventory.SerialNumber = discoveryField(info, "hardware_serial")
+		inventory.Manufacturer = discoveryField(info, "hardware_vendor")
+		inventory.Model = discoveryField(info, "hardware_model")
+		inventory.CPU = discoveryField(info, "cpu_brand")
+		inventory.RAM = formatDiscoveryBytes(discoveryField(info, "physical_memory"), 1)
+	}
+	if rows := tables["os_version"]; len(rows) > 0 {
+		inventory.OS = strings.TrimSpace(discoveryField(rows[0], "name") + " " + discoveryField(rows[0], "version"))
+	}
+
+	var cards []string
+	for _, row := range tables["interface_details"] {
+		if mac := normalizeDiscoveryMAC(discoveryField(row, "mac")); mac != "" {
+			inventory.MACAddresses = append(inventory.MACAddresses, mac)
+			if description := discoveryField(row, "description"); description != "" && !containsString(cards, description) {
+				cards = append(cards, description)
+			}
+		}
+	}
+	inventory.NetworkCard = strings.Join(cards, "; ")
+	for _, row := range tables["interface_addresses"] {
+		inventory.IPAddresses = append(inventory.IPAddresses, discoveryField(row, "address"))
+	}
+
+	var disks []string
+	for _, row := range tables["disk_info"] {
+		disks = appendDiscoveryDisk(disks, discoveryField(row, "hardware_model"), formatDiscoveryBytes(discoveryField(row, "disk_size"), 1))
+	}
+	if len(disks) == 0 {
+		for _, row := range tables["block_devices"] {
+			if discoveryField(row, "parent") != "" || discoveryField(row, "type") == "loop" {
+				continue
+			}
+			size, _ := strconv.ParseFloat(discoveryField(row, "size"), 64)
+			blockSize, _ := strconv.ParseFloat(discoveryField(row, "block_size"), 64)
+			if blockSize == 0 {
+				blockSize = 512
+			}
+			disks = appendDiscoveryDisk(disks, discoveryField(row, "model"), formatDiscoveryBytes(strconv.FormatFloat(size*blockSize, 'f', 0, 64), 1))
+		}
+	}
+	inventory.HDDInfo = strings.Join(disks, "; ")
+
+	// Windows, Debian/Ubuntu, RHEL и macOS
+	for _, row := range tables["programs"] {
+		inventory.Software = append(inventory.Software, dto.AssetDiscoverySoftware{
+			Name:        discoveryField(row, "name"),
+			Version:     discoveryField(row, "version"),
+			InstalledAt: parseDiscoveryDate(discoveryField(row, "install_date")),
+		})
+	}
+	for _, row := range tables["deb_packages"] {
+		inventory.Software = append(inventory.Software, dto.AssetDiscoverySoftware{
+			Name:    discoveryField(row, "name"),
+			Version: discoveryField(row, "version"),
+		})
+	}
+	for _, row := range tables["rpm_packages"] {
+		version := discoveryField(row, "version")
+		if release := discoveryField(row, "release"); release != "" {
+			version += "-" + release
+		}
+		inventory.Software = append(inventory.Software, dto.AssetDiscoverySoftware{
+			Name:        discoveryField(row, "name"),
+			Version:     version,
+			InstalledAt: parseDiscoveryDate(discoveryField(row, "install_time")),
+		})
+	}
+	for _, row := range tables["apps"] {
+		name := discoveryField(row, "bundle_name")
+		if name == "" {
+			name = strings.TrimSuffix(discoveryField(row, "name"), ".app")
+		}
+		inventory.Software = append(inventory.Software, dto.AssetDiscoverySoftware{
+			Name:    name,
+			Version: discoveryField(row, "bundle_short_version"),
+		})
+	}
+	return inventory
+}
+
+// OCS Inventory
+
+// ocsSections returns the upper-cased sections of an OCS inventory: the agent inventory
+// ({"REQUEST": {"CONTENT": {...}}} or {"CONTENT": {...}}), or a computer from the OCS REST API ({"<id>": {...}})
+func ocsSections(document map[string]interface{}) map[string][]map[string]interface{} {
+	document = upperKeys(document)
+	if request, ok := document["REQUEST"].(map[string]interface{}); ok {
+		document = upperKeys(request)
+	}
+	if content, ok := document["CONTENT"].(map[string]interface{}); ok {
+		document = upperKeys(content)
+	}
+	if len(document) == 1 {
+		for _, value := range document {
+			if computer, ok := value.(map[string]interface{}); ok {
+				if _, ok := upperKeys(computer)["HARDWARE"]; ok {
+					document = upperKeys(computer)
+				}
+			}
+		}
+	}
+	if _, ok := document["HARDWARE"]; !ok {
+		return nil
+	}
+
+	sections := make(map[string][]map[string]interface{})
+	for name, value := range document {
+		rows := discoveryRows(value)
+		for i, row := range rows {
+			rows[i] = upperKeys(row)
+		}
+		sections[name] = rows
+	}
+	return sections
+}
+
+func parseOCSInventory(sections map[string][]map[string]interface{}) *dto.AssetDiscoveryInventory {
+	inventory := &dto.AssetDiscoveryInventory{}
+	if rows := sections["HARDWARE"]; len(rows) > 0 {
+		hardware := rows[0]
+		inventory.Hostname = discoveryField(hardware, "NAME")
+		inventory.CPU = discoveryField(hardware, "PROCESSORT")
+		inventory.RAM = formatDiscoveryBytes(discoveryField(hardware, "MEMORY"), 1<<20)
+		inventory.OS = strings.TrimSpace(discoveryField(hardware, "OSNAME") + " " + discoveryField(hardware, "OSVERSION"))
+		inventory.IPAddresses = append(inventory.IPAddresses, strings.Split(discoveryField(hardware, "IPADDR"), "/")...)
+	}
+	if rows := sections["BIOS"]; len(rows) > 0 {
+		bios := rows[0]
+		inventory.SerialNumber = discoveryField(bios, "SSN")
+		inventory.Manufacturer = discoveryField(bios, "SMANUFACTURER")
+		inventory.Model = discoveryField(bios, "SMODEL")
+	}
+	if rows := sections["CPUS"]; inventory.CPU == "" && len(rows) > 0 {
+		inventory.CPU = discoveryField(rows[0], "TYPE")
+	}
+
+	var cards []string
+	for _, row := range sections["NETWORKS"] {
+		if mac := normalizeDiscoveryMAC(discoveryField(row, "MACADDR")); mac != "" {
+			inventory.MACAddresses = append(inventory.MACAddresses, mac)
+			if description := discoveryField(row, "DESCRIPTION"); description != "" && !containsString(cards, description) {
+				cards = append(cards, description)
+			}
+		}
+		inventory.IPAddresses = append(inventory.IPAddresses, discoveryField(row, "IPADDRESS"))
+	}
+	inventory.NetworkCard = strings.Join(cards, "; ")
+
+	var disks []string
+	for _, row := range sections["STORAGES"] {
+		if size := discoveryField(row, "DISKSIZE"); size != "" && size != "0" {
+			disks = appendDiscoveryDisk(disks, discoveryField(row, "MODEL"), formatDiscoveryBytes(size, 1<<20))
+		}
+	}
+	inventory.HDDInfo = strings.Join(disks, "; ")
+
+	for _, row := range sections["SOFTWARES"] {
+		inventory.Software = append(inventory.Software, dto.AssetDiscoverySoftware{
+			Name:        discoveryField(row, "NAME"),
+			Version:     discoveryField(row, "VERSION"),
+			InstalledAt: parseDiscoveryDate(discoveryField(row, "INSTALLDATE")),
+		})
+	}
+	return inventory
+}
+
+// Helpers
+
+// discoveryRows accepts a single object or an array of objects
+func discoveryRows(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		rows := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			if row, ok := item.(map[string]interface{}); ok {
+				rows = append(rows, row)
+			}
+		}
+		return rows
+	}
+	return nil
+}
+
+func upperKeys(value map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(value))
+	for key, v := range value {
+		result[strings.ToUpper(key)] = v
+	}
+	return result
+}
+
+func discoveryField(row map[string]interface{}, key string) string {
+	switch v := row[key].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// formatDiscoveryBytes formats a size given in units of unit bytes, e.g. "16 GB"
+func formatDiscoveryBytes(value string, unit float64) string {
+	size, err := strconv.ParseFloat(value, 64)
+	if err != nil || size <= 0 {
+		return ""
+	}
+	size *= unit
+	if size >= 1<<40 {
+		return strconv.FormatFloat(size/(1<<40), 'f', 1, 64) + " TB"
+	}
+	return strconv.FormatFloat(size/(1<<30), 'f', 0, 64) + " GB"
+}
+
+func appendDiscoveryDisk(disks []string, model, size string) []string {
+	if disk := strings.TrimSpace(model + " " + size); disk != "" {
+		return append(disks, disk)
+	}
+	return disks
+}
+
+// parseDiscoveryDate accepts Unix time and the date formats of Windows (20240131), OCS and ISO 8601
+func parseDiscoveryDate(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	if len(value) >= 9 {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+			t := time.Unix(seconds, 0).UTC()
+			return &t
+		}
+	}
+	for _, layout := range []string{"20060102", "2006/01/02", "2006-01-02", time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiscoveryReport_Native(t *testing.T) {
+	body := `{
+		"hostname": " ws-042.corp.local. ",
+		"serial_number": "To Be Filled By O.E.M.",
+		"os": "Ubuntu 22.04",
+		"mac_addresses": ["00-1A-2B-3C-4D-5E", "00:1a:2b:3c:4d:5e", "ff:ff:ff:ff:ff:ff", "garbage"],
+		"ip_addresses": ["fe80::1", "2001:db8::10", "127.0.0.1", "10.0.0.15/24", "10.0.0.15", "0.0.0.0"],
+		"software": [
+			{"name": " nginx ", "version": "1.24.0"},
+			{"name": "NGINX", "version": "1.18.0"},
+			{"name": "", "version": "1.0"},
+			{"name": "openssl", "version": "3.0.2", "installed_at": "2026-01-10T00:00:00Z"}
+		]
+	}`
+
+	format, inventory, err := parseDiscoveryReport([]byte(body))
+	require.NoError(t, err)
+
+	assert.Equal(t, dto.AssetDiscoveryFormatNative, format)
+	assert.Equal(t, "ws-042.corp.local", inventory.Hostname)
+	assert.Empty(t, inventory.SerialNumber, "placeholder serial numbers are dropped")
+	assert.Equal(t, []string{"00:1a:2b:3c:4d:5e"}, inventory.MACAddresses)
+	assert.Equal(t, []string{"10.0.0.15", "2001:db8::10"}, inventory.IPAddresses, "IPv4 first, loopback and link-local dropped")
+	require.Len(t, inventory.Software, 2)
+	assert.Equal(t, dto.AssetDiscoverySoftware{Name: "nginx", Version: "1.24.0"}, inventory.Software[0])
+	assert.Equal(t, "openssl", inventory.Software[1].Name)
+	require.NotNil(t, inventory.Software[1].InstalledAt)
+}
+
+func TestParseDiscoveryReport_Osquery(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "table results",
+			body: `{
+				"system_info": [{"hostname": "", "computer_name": "MAC-01", "hardware_serial": "C02XK1ABJG5H", "hardware_vendor": "Apple Inc.",
+					"hardware_model": "MacBookPro16,1", "cpu_brand": "Intel(R) Core(TM) i7", "physical_memory": "17179869184"}],
+				"os_version": [{"name": "macOS", "version": "14.2"}],
+				"interface_details": [{"mac": "A4:83:E7:01:02:03", "description": "Wi-Fi"}, {"mac": "00:00:00:00:00:00", "description": "lo0"}],
+				"interface_addresses": [{"address": "192.168.1.20"}, {"address": "127.0.0.1"}],
+				"disk_info": [{"hardware_model": "APPLE SSD", "disk_size": "512110190592"}],
+				"apps": [{"name": "Safari.app", "bundle_short_version": "17.2"}, {"bundle_name": "Slack", "name": "Slack.app", "bundle_short_version": "4.36"}],
+				"rpm_packages": [{"name": "bash", "version": "5.1.8", "release": "6.el9", "install_time": "1704067200"}]
+			}`,
+		},
+		{
+			name: "distributed query response",
+			body: `{"queries": {
+				"system_info": {"computer_name": "MAC-01", "hardware_serial": "C02XK1ABJG5H", "hardware_vendor": "Apple Inc.",
+					"hardware_model": "MacBookPro16,1", "cpu_brand": "Intel(R) Core(TM) i7", "physical_memory": 17179869184},
+				"os_version": {"name": "macOS", "version": "14.2"},
+				"interface_details": [{"mac": "a4:83:e7:01:02:03", "description": "Wi-Fi"}],
+				"interface_addresses": [{"address": "192.168.1.20"}],
+				"block_devices": [{"name": "/dev/disk0", "model": "APPLE SSD", "size": "1000215216", "block_size": "512"},
+					{"name": "/dev/disk0s1", "parent": "/dev/disk0", "size": "1000"}],
+				"apps": [{"name": "Safari.app", "bundle_short_version": "17.2"}, {"bundle_name": "Slack", "bundle_short_version": "4.36"}],
+				"rpm_packages": [{"name": "bash", "version": "5.1.8", "release": "6.el9", "install_time": 1704067200}]
+			}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, inventory, err := parseDiscoveryReport([]byte(tt.body))
+			require.NoError(t, err)
+
+			assert.Equal(t, dto.AssetDiscoveryFormatOsquery, format)
+			assert.Equal(t, "MAC-01", inventory.Hostname)
+			assert.Equal(t, "C02XK1ABJG5H", inventory.SerialNumber)
+			assert.Equal(t, "Apple Inc.", inventory.Manufacturer)
+			assert.Equal(t, "MacBookPro16,1", inventory.Model)
+			assert.Equal(t, "16 GB", inventory.RAM)
+			assert.Equal(t, "macOS 14.2", inventory.OS)
+			assert.Equal(t, "Wi-Fi", inventory.NetworkCard)
+			assert.Equal(t, []string{"a4:83:e7:01:02:03"}, inventory.MACAddresses)
+			assert.Equal(t, []string{"192.168.1.20"}, inventory.IPAddresses)
+			assert.True(t, strings.HasPrefix(inventory.HDDInfo, "APPLE SSD "), inventory.HDDInfo)
+
+			require.Len(t, inventory.Software, 3)
+			assert.Equal(t, "bash", inventory.Software[0].Name)
+			assert.Equal(t, "5.1.8-6.el9", inventory.Software[0].Version)
+			require.NotNil(t, inventory.Software[0].InstalledAt)
+			assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), *inventory.Software[0].InstalledAt)
+			assert.Equal(t, "Safari", inventory.Software[1].Name)
+			assert.Equal(t, "Slack", inventory.Software[2].Name)
+		})
+	}
+}
+
+func TestParseDiscoveryReport_OCS(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "agent request",
+			body: `{"REQUEST": {"CONTENT": {
+				"HARDWARE": {"NAME": "PC-ACC-07", "PROCESSORT": "Intel Core i5", "MEMORY": "16384", "OSNAME": "Microsoft Windows 10 Pro", "OSVERSION": "10.0.19045", "IPADDR": "10.1.2.3/10.1.2.4"},
+				"BIOS": {"SSN": "PF2ABC12", "SMANUFACTURER": "LENOVO", "SMODEL": "ThinkCentre M720"},
+				"NETWORKS": [{"MACADDR": "54:E1:AD:11:22:33", "DESCRIPTION": "Intel Ethernet", "IPADDRESS": "10.1.2.3"}],
+				"STORAGES": [{"MODEL": "Samsung SSD", "DISKSIZE": "953869"}, {"MODEL": "Card reader", "DISKSIZE": "0"}],
+				"SOFTWARES": [{"NAME": "7-Zip", "VERSION": "23.01", "INSTALLDATE": "2024/01/31"}, {"NAME": "Google Chrome", "VERSION": "120.0", "INSTALLDATE": "20240115"}]
+			}}}`,
+		},
+		{
+			name: "rest api computer with lower-case keys",
+			body: `{"42": {
+				"hardware": {"name": "PC-ACC-07", "processort": "Intel Core i5", "memory": 16384, "osname": "Microsoft Windows 10 Pro", "osversion": "10.0.19045", "ipaddr": "10.1.2.3"},
+				"bios": {"ssn": "PF2ABC12", "smanufacturer": "LENOVO", "smodel": "ThinkCentre M720"},
+				"networks": [{"macaddr": "54-e1-ad-11-22-33", "description": "Intel Ethernet", "ipaddress": "10.1.2.4"}],
+				"storages": [{"model": "Samsung SSD", "disksize": 953869}],
+				"softwares": [{"name": "7-Zip", "version": "23.01", "installdate": "2024-01-31"}, {"name": "Google Chrome", "version": "120.0"}]
+			}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, inventory, err := parseDiscoveryReport([]byte(tt.body))
+			require.NoError(t, err)
+
+			assert.Equal(t, dto.AssetDiscoveryFormatOCS, format)
+			assert.Equal(t, "PC-ACC-07", inventory.Hostname)
+			assert.Equal(t, "PF2ABC12", inventory.SerialNumber)
+			assert.Equal(t, "LENOVO", inventory.Manufacturer)
+			assert.Equal(t, "ThinkCentre M720", inventory.Model)
+			assert.Equal(t, "Intel Core i5", inventory.CPU)
+			assert.Equal(t, "16 GB", inventory.RAM)
+			assert.Equal(t, "Microsoft Windows 10 Pro 10.0.19045", inventory.OS)
+			assert.Equal(t, []string{"54:e1:ad:11:22:33"}, inventory.MACAddresses)
+			assert.Equal(t, []string{"10.1.2.3", "10.1.2.4"}, inventory.IPAddresses)
+			assert.Equal(t, "Samsung SSD 932 GB", inventory.HDDInfo)
+			require.Len(t, inventory.Software, 2)
+			assert.Equal(t, "7-Zip", inventory.Software[0].Name)
+			require.NotNil(t, inventory.Software[0].InstalledAt)
+			assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), *inventory.Software[0].InstalledAt)
+		})
+	}
+}
+
+func TestParseDiscoveryReport_Errors(t *testing.T) {
+	var software []string
+	for i := 0; i <= maxDiscoverySoftware; i++ {
+		software = append(software, fmt.Sprintf(`{"name":"package-%d"}`, i))
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", "   "},
+		{"not json", "hostname=ws-01"},
+		{"json array", `[{"hostname":"ws-01"}]`},
+		{"no identifiers", `{"os":"Windows 11","serial_number":"System Serial Number","mac_addresses":["00:00:00:00:00:00"]}`},
+		{"wrong field type", `{"hostname": 42}`},
+		{"too many packages", `{"hostname":"ws-01","software":[` + strings.Join(software, ",") + `]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseDiscoveryReport([]byte(tt.body))
+			assert.ErrorIs(t, err, ErrInvalidDiscoveryReport)
+		})
+	}
+}
+
+func TestNormalizeDiscoverySerial(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{" PF2ABC12 ", "PF2ABC12"},
+		{"Default string", ""},
+		{"NONE", ""},
+		{"0000000", ""},
+		{"", ""},
+		{"000123", "000123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeDiscoverySerial(tt.value))
+		})
+	}
+}
+
+func TestNormalizeDiscoveryMAC(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"00-1A-2B-3C-4D-5E", "00:1a:2b:3c:4d:5e"},
+		{"001a.2b3c.4d5e", "00:1a:2b:3c:4d:5e"},
+		{" 00:1a:2b:3c:4d:5e ", "00:1a:2b:3c:4d:5e"},
+		{"00:00:00:00:00:00", ""},
+		{"FF:FF:FF:FF:FF:FF", ""},
+		{"00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01", ""},
+		{"not-a-mac", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeDiscoveryMAC(tt.value))
+		})
+	}
+}
+
+func TestFormatDiscoveryBytes(t *testing.T) {
+	tests := []struct {
+		value string
+		unit  float64
+		want  string
+	}{
+		{"17179869184", 1, "16 GB"},
+		{"8192", 1 << 20, "8 GB"},
+		{"2097152", 1 << 20, "2.0 TB"},
+		{"0", 1, ""},
+		{"-5", 1, ""},
+		{"abc", 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatDiscoveryBytes(tt.value, tt.unit))
+		})
+	}
+}
+
+func TestParseDiscoveryDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *time.Time
+	}{
+		{"", nil},
+		{"1704067200", timePtr(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{"20240131", timePtr(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))},
+		{"2024/01/31", timePtr(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))},
+		{"2024-01-31", timePtr(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC))},
+		{"2024-01-31 10:20:30", timePtr(time.Date(2024, 1, 31, 10, 20, 30, 0, time.UTC))},
+		{"31.01.2024", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseDiscoveryDate(tt.value))
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,573 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// assetImportAliases lists accepted column headers per field in addition to the field name itself
+var assetImportAliases = map[string][]string{
+	dto.AssetImportFieldName:            {"asset", "asset name", "hostname", "название", "наименование", "актив"},
+	dto.AssetImportFieldType:            {"asset type", "тип"},
+	dto.AssetImportFieldClass:           {"asset class", "класс"},
+	dto.AssetImportFieldInventoryNumber: {"inventory", "inventory no", "инвентарный номер", "инв номер"},
+	dto.AssetImportFieldOwnerEmail:      {"owner", "email", "владелец"},
+	dto.AssetImportFieldLocation:        {"place", "расположение", "местоположение", "размещение"},
+	dto.AssetImportFieldCriticality:     {"критичность"},
+	dto.AssetImportFieldConfidentiality: {"конфиденциальность"},
+	dto.AssetImportFieldIntegrity:       {"целостность"},
+	dto.AssetImportFieldAvailability:    {"доступность"},
+	dto.AssetImportFieldStatus:          {"статус", "состояние"},
+	dto.AssetImportFieldSerialNumber:    {"serial", "s/n", "sn", "серийный номер"},
+	dto.AssetImportFieldPCNumber:        {"pc", "номер пк"},
+	dto.AssetImportFieldManufacturer:    {"vendor", "производитель"},
+	dto.AssetImportFieldModel:           {"модель"},
+	dto.AssetImportFieldCPU:             {"processor", "процессор"},
+	dto.AssetImportFieldRAM:             {"memory", "оперативная память", "озу"},
+	dto.AssetImportFieldHDDInfo:         {"hdd", "disk", "storage", "диск", "накопитель"},
+	dto.AssetImportFieldNetworkCard:     {"nic", "сетевая карта"},
+	dto.AssetImportFieldIPAddress:       {"ip", "ip адрес"},
+	dto.AssetImportFieldMACAddress:      {"mac", "mac адрес"},
+	dto.AssetImportFieldPurchaseYear:    {"year", "год покупки", "год приобретения"},
+	dto.AssetImportFieldWarrantyUntil:   {"warranty", "гарантия", "гарантия до"},
+}
+
+// Accepted cell values keyed by normalizeImportHeader form; unknown values are left for the validator to reject
+var (
+	assetImportTypes = map[string]string{
+		"server": dto.AssetTypeServer, "сервер": dto.AssetTypeServer,
+		"workstation": dto.AssetTypeWorkstation, "рабочая станция": dto.AssetTypeWorkstation, "компьютер": dto.AssetTypeWorkstation, "пк": dto.AssetTypeWorkstation,
+		"application": dto.AssetTypeApplication, "приложение": dto.AssetTypeApplication,
+		"database": dto.AssetTypeDatabase, "база данных": dto.AssetTypeDatabase, "бд": dto.AssetTypeDatabase,
+		"document": dto.AssetTypeDocument, "документ": dto.AssetTypeDocument,
+		"network device": dto.AssetTypeNetworkDevice, "сетевое устройство": dto.AssetTypeNetworkDevice, "сетевое оборудование": dto.AssetTypeNetworkDevice,
+		"other": dto.AssetTypeOther, "другое": dto.AssetTypeOther, "прочее": dto.AssetTypeOther,
+	}
+	assetImportClasses = map[string]string{
+		"hardware": dto.AssetClassHardware, "оборудование": dto.AssetClassHardware,
+		"software": dto.AssetClassSoftware, "по": dto.AssetClassSoftware, "программное обеспечение": dto.AssetClassSoftware,
+		"data": dto.AssetClassData, "данные": dto.AssetClassData,
+		"service": dto.AssetClassService, "сервис": dto.AssetClassService, "услуга": dto.AssetClassService,
+	}
+	assetImportLevels = map[string]string{
+		"low": dto.CriticalityLow, "низкая": dto.CriticalityLow, "низкий": dto.CriticalityLow, "1": dto.CriticalityLow,
+		"medium": dto.CriticalityMedium, "средняя": dto.CriticalityMedium, "средний": dto.CriticalityMedium, "2": dto.CriticalityMedium,
+		"high": dto.CriticalityHigh, "высокая": dto.CriticalityHigh, "высокий": dto.CriticalityHigh, "3": dto.CriticalityHigh,
+	}
+	assetImportStatuses = map[string]string{
+		"active": dto.AssetStatusActive, "активен": dto.AssetStatusActive, "в эксплуатации": dto.AssetStatusActive,
+		"in repair": dto.AssetStatusInRepair, "в ремонте": dto.AssetStatusInRepair,
+		"storage": dto.AssetStatusStorage, "на складе": dto.AssetStatusStorage, "хранение": dto.AssetStatusStorage,
+		"decommissioned": dto.AssetStatusDecommissioned, "списан": dto.AssetStatusDecommissioned,
+	}
+)
+
+// assetImportDefaultClasses is used when the file has no class column or the cell is empty
+var assetImportDefaultClasses = map[string]string{
+	dto.AssetTypeServer:        dto.AssetClassHardware,
+	dto.AssetTypeWorkstation:   dto.AssetClassHardware,
+	dto.AssetTypeNetworkDevice: dto.AssetClassHardware,
+	dto.AssetTypeApplication:   dto.AssetClassSoftware,
+	dto.AssetTypeDatabase:      dto.AssetClassData,
+	dto.AssetTypeDocument:      dto.AssetClassData,
+}
+
+// SetInventoryNumberGenerator connects the inventory number rules used for imported assets
+func (s *AssetService) SetInventoryNumberGenerator(generator InventoryNumberGeneratorInterface) {
+	s.inventoryNumbers = generator
+}
+
+// assetImportEntry is a checked row; asset is nil for rows that failed validation
+type assetImportEntry struct {
+	row   dto.AssetImportRowResponse
+	asset *repo.Asset
+}
+
+// assetImportBatch is a parsed and validated import file
+type assetImportBatch struct {
+	preview *dto.AssetImportPreviewResponse
+	assets  []repo.Asset // valid rows only, in file order
+}
+
+// Asset Import methods
+
+// PreviewAssetImport parses a CSV/XLSX register or an nmap XML report and validates every row without creating assets
+func (s *AssetService) PreviewAssetImport(ctx context.Context, tenantID, filename string, data []byte, mapping map[string]string) (*dto.AssetImportPreviewResponse, error) {
+	batch, err := s.prepareAssetImport(ctx, tenantID, filename, data, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return batch.preview, nil
+}
+
+// ImportAssets creates the assets from the file in one transaction.
+// If any row is invalid or a duplicate nothing is imported unless skipInvalid is set; the preview is returned with ErrAssetImportInvalidRows.
+func (s *AssetService) ImportAssets(ctx context.Context, tenantID, filename string, data []byte, mapping map[string]string, skipInvalid bool, importedBy string) (*dto.AssetImportResultResponse, *dto.AssetImportPreviewResponse, error) {
+	batch, err := s.prepareAssetImport(ctx, tenantID, filename, data, mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+	if batch.preview.InvalidRows > 0 && !skipInvalid {
+		return nil, batch.preview, ErrAssetImportInvalidRows
+	}
+	if len(batch.assets) == 0 {
+		return nil, batch.preview, ErrAssetImportInvalidRows
+	}
+
+	s.assignImportInventoryNumbers(ctx, tenantID, batch.assets)
+
+	note := fmt.Sprintf("Asset imported from %s", path.Base(filename))
+	if err := s.assetRepo.CreateAssets(ctx, batch.assets, importedBy, note); err != nil {
+		log.Printf("ERROR: asset_service.ImportAssets CreateAssets: %v", err)
+		return nil, nil, err
+	}
+
+	result := &dto.AssetImportResultResponse{
+		Imported: len(batch.assets),
+		Skipped:  batch.preview.InvalidRows,
+		AssetIDs: make([]string, 0, len(batch.assets)),
+	}
+	for _, asset := range batch.assets {
+		result.AssetIDs = append(result.AssetIDs, asset.ID)
+	}
+
+	log.Printf("DEBUG: asset_service.ImportAssets tenant=%s source=%s imported=%d skipped=%d", tenantID, batch.preview.Source, result.Imported, result.Skipped)
+	return result, batch.preview, nil
+}
+
+func (s *AssetService) prepareAssetImport(ctx context.Context, tenantID, filename string, data []byte, explicit map[string]string) (*assetImportBatch, error) {
+	var preview *dto.AssetImportPreviewResponse
+	var entries []assetImportEntry
+	var err error
+	if strings.ToLower(path.Ext(filename)) == ".xml" {
+		preview, entries, err = s.readNmapImport(tenantID, data)
+	} else {
+		preview, entries, err = s.readSpreadsheetImport(ctx, tenantID, filename, data, explicit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.markImportDuplicates(ctx, tenantID, entries); err != nil {
+		return nil, err
+	}
+
+	batch := &assetImportBatch{preview: preview}
+	preview.Rows = make([]dto.AssetImportRowResponse, 0, len(entries))
+	for _, entry := range entries {
+		preview.TotalRows++
+		if entry.row.DuplicateOf != nil {
+			preview.DuplicateRows++
+		}
+		if entry.row.Valid {
+			preview.ValidRows++
+			batch.assets = append(batch.assets, *entry.asset)
+		} else {
+			preview.InvalidRows++
+		}
+		preview.Rows = append(preview.Rows, entry.row)
+	}
+	return batch, nil
+}
+
+func (s *AssetService) readSpreadsheetImport(ctx context.Context, tenantID, filename string, data []byte, explicit map[string]string) (*dto.AssetImportPreviewResponse, []assetImportEntry, error) {
+	rows, err := readImportTable(filename, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	headers := rows[0]
+
+	mapping, err := resolveImportMapping(headers, assetImportAliases, explicit)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, required := range []string{dto.AssetImportFieldName, dto.AssetImportFieldType} {
+		if _, ok := mapping[required]; !ok {
+			return nil, nil, NewValidationError("mapping", fmt.Sprintf("no column mapped to required field %q", required))
+		}
+	}
+
+	var emails []string
+	for _, row := range rows[1:] {
+		if email := importCell(row, mapping, dto.AssetImportFieldOwnerEmail); email != "" {
+			emails = append(emails, strings.ToLower(email))
+		}
+	}
+	owners, err := s.assetRepo.ResolveUserIDsByEmail(ctx, tenantID, emails)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preview := &dto.AssetImportPreviewResponse{
+		Source:  dto.AssetImportSourceSpreadsheet,
+		Headers: headers,
+		Mapping: make(map[string]string, len(mapping)),
+	}
+	for field, index := range mapping {
+		preview.Mapping[field] = headers[index]
+	}
+
+	entries := make([]assetImportEntry, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		if isEmptyRow(row) {
+			continue
+		}
+		entries = append(entries, buildAssetImportRow(tenantID, row, i+2, mapping, owners))
+	}
+	return preview, entries, nil
+}
+
+// buildAssetImportRow converts a row into a CreateAssetRequest, resolves the owner and validates it.
+// Empty class defaults by type, empty criticality to medium and empty CIA ratings to the criticality.
+func buildAssetImportRow(tenantID string, row []string, rowNumber int, mapping map[string]int, owners map[string]string) assetImportEntry {
+	response := dto.AssetImportRowResponse{
+		RowNumber:       rowNumber,
+		InventoryNumber: importCell(row, mapping, dto.AssetImportFieldInventoryNumber),
+		OwnerEmail:      importCell(row, mapping, dto.AssetImportFieldOwnerEmail),
+		Errors:          []string{},
+	}
+	req := &response.Asset
+	req.Name = importCell(row, mapping, dto.AssetImportFieldName)
+	req.Type = importChoice(importCell(row, mapping, dto.AssetImportFieldType), assetImportTypes)
+	req.Class = importChoice(importCell(row, mapping, dto.AssetImportFieldClass), assetImportClasses)
+	if req.Class == "" {
+		req.Class = assetImportDefaultClasses[req.Type]
+	}
+	req.Location = importCell(row, mapping, dto.AssetImportFieldLocation)
+	req.Status = importChoice(importCell(row, mapping, dto.AssetImportFieldStatus), assetImportStatuses)
+
+	req.Criticality = importChoice(importCell(row, mapping, dto.AssetImportFieldCriticality), assetImportLevels)
+	if req.Criticality == "" {
+		req.Criticality = dto.CriticalityMedium
+	}
+	for _, rating := range []struct {
+		field  string
+		target *string
+	}{
+		{dto.AssetImportFieldConfidentiality, &req.Confidentiality},
+		{dto.AssetImportFieldIntegrity, &req.Integrity},
+		{dto.AssetImportFieldAvailability, &req.Availability},
+	} {
+		*rating.target = importChoice(importCell(row, mapping, rating.field), assetImportLevels)
+		if *rating.target == "" {
+			*rating.target = req.Criticality
+		}
+	}
+
+	req.SerialNumber = optionalImportCell(row, mapping, dto.AssetImportFieldSerialNumber)
+	req.PCNumber = optionalImportCell(row, mapping, dto.AssetImportFieldPCNumber)
+	req.Manufacturer = optionalImportCell(row, mapping, dto.AssetImportFieldManufacturer)
+	req.Model = optionalImportCell(row, mapping, dto.AssetImportFieldModel)
+	req.CPU = optionalImportCell(row, mapping, dto.AssetImportFieldCPU)
+	req.RAM = optionalImportCell(row, mapping, dto.AssetImportFieldRAM)
+	req.HDDInfo = optionalImportCell(row, mapping, dto.AssetImportFieldHDDInfo)
+	req.NetworkCard = optionalImportCell(row, mapping, dto.AssetImportFieldNetworkCard)
+
+	if value := optionalImportCell(row, mapping, dto.AssetImportFieldIPAddress); value != nil {
+		if ip := net.ParseIP(*value); ip != nil {
+			normalized := ip.String()
+			req.IPAddress = &normalized
+		} else {
+			response.Errors = append(response.Errors, fmt.Sprintf("ip_address: %q is not a valid IP address", *value))
+		}
+	}
+	if value := optionalImportCell(row, mapping, dto.AssetImportFieldMACAddress); value != nil {
+		if mac := normalizeDiscoveryMAC(*value); mac != "" {
+			req.MACAddress = &mac
+		} else {
+			response.Errors = append(response.Errors, fmt.Sprintf("mac_address: %q is not a valid MAC address", *value))
+		}
+	}
+	if value := optionalImportCell(row, mapping, dto.AssetImportFieldPurchaseYear); value != nil {
+		year, err := strconv.Atoi(*value)
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("purchase_year: %q is not a number", *value))
+		} else {
+			req.PurchaseYear = &year
+		}
+	}
+	if value := optionalImportCell(row, mapping, dto.AssetImportFieldWarrantyUntil); value != nil {
+		warrantyUntil, err := parseImportDate(*value)
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("warranty_until: %v", err))
+		} else {
+			req.WarrantyUntil = &warrantyUntil
+		}
+	}
+
+	if response.OwnerEmail != "" {
+		if id, ok := owners[strings.ToLower(response.OwnerEmail)]; ok {
+			req.OwnerID = id
+		} else {
+			response.Errors = append(response.Errors, fmt.Sprintf("owner_email: user %q not found", response.OwnerEmail))
+		}
+	}
+	if len(response.InventoryNumber) > 100 {
+		response.Errors = append(response.Errors, "inventory_number: must satisfy max=100")
+	}
+
+	response.Errors = append(response.Errors, validateImportRequest(req)...)
+	response.Valid = len(response.Errors) == 0
+	if !response.Valid {
+		return assetImportEntry{row: response}
+	}
+	return assetImportEntry{row: response, asset: newImportedAsset(tenantID, response.InventoryNumber, *req, nil)}
+}
+
+func (s *AssetService) readNmapImport(tenantID string, data []byte) (*dto.AssetImportPreviewResponse, []assetImportEntry, error) {
+	hosts, scannedAt, err := parseNmapXML(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]assetImportEntry, 0, len(hosts))
+	for i, host := range hosts {
+		entries = append(entries, buildNmapImportRow(tenantID, host, i+1, scannedAt))
+	}
+	return &dto.AssetImportPreviewResponse{Source: dto.AssetImportSourceNmap}, entries, nil
+}
+
+// buildNmapImportRow converts a scanned host into a server or network_device asset with medium ratings;
+// hostnames, OS and open services are kept in the asset metadata under "network_scan"
+func buildNmapImportRow(tenantID string, host nmapScannedHost, rowNumber int, scannedAt *time.Time) assetImportEntry {
+	response := dto.AssetImportRowResponse{
+		RowNumber: rowNumber,
+		Hostnames: host.Hostnames,
+		OS:        host.OS,
+		Services:  host.Services,
+		Errors:    []string{},
+		Asset: dto.CreateAssetRequest{
+			Name:            host.name(),
+			Type:            host.assetType(),
+			Class:           dto.AssetClassHardware,
+			Criticality:     dto.CriticalityMedium,
+			Confidentiality: dto.CriticalityMedium,
+			Integrity:       dto.CriticalityMedium,
+			Availability:    dto.CriticalityMedium,
+			Status:          dto.AssetStatusActive,
+			IPAddress:       importStringPtr(host.IPAddress),
+			MACAddress:      importStringPtr(host.MACAddress),
+			Manufacturer:    importStringPtr(host.Vendor),
+		},
+	}
+	if host.IPAddress == "" {
+		response.Errors = append(response.Errors, "ip_address: host has no IP address")
+	}
+
+	response.Errors = append(response.Errors, validateImportRequest(&response.Asset)...)
+	response.Valid = len(response.Errors) == 0
+	if !response.Valid {
+		return assetImportEntry{row: response}
+	}
+
+	scan := map[string]interface{}{
+		"source":    dto.AssetImportSourceNmap,
+		"hostnames": host.Hostnames,
+		"os":        host.OS,
+		"services":  host.Services,
+	}
+	if scannedAt != nil {
+		scan["scanned_at"] = scannedAt.Format(time.RFC3339)
+	}
+	metadata, _ := json.Marshal(map[string]interface{}{"network_scan": scan})
+	metadataStr := string(metadata)
+
+	return assetImportEntry{row: response, asset: newImportedAsset(tenantID, "", response.Asset, &metadataStr)}
+}
+
+// newImportedAsset builds the asset row the same way CreateAsset does
+func newImportedAsset(tenantID, inventoryNumber string, req dto.CreateAssetRequest, metadata *string) *repo.Asset {
+	now := time.Now()
+	asset := &repo.Asset{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		InventoryNumber: inventoryNumber,
+		Name:            req.Name,
+		Type:            req.Type,
+		Class:           req.Class,
+		OwnerID:         importStringPtr(req.OwnerID),
+		Location:        importStringPtr(req.Location),
+		Criticality:     req.Criticality,
+		Confidentiality: req.Confidentiality,
+		Integrity:       req.Integrity,
+		Availability:    req.Availability,
+		Status:          req.Status,
+		SerialNumber:    req.SerialNumber,
+		PCNumber:        req.PCNumber,
+		Model:           req.Model,
+		CPU:             req.CPU,
+		RAM:             req.RAM,
+		HDDInfo:         req.HDDInfo,
+		NetworkCard:     req.NetworkCard,
+		IPAddress:       req.IPAddress,
+		MACAddress:      req.MACAddress,
+		Manufacturer:    req.Manufacturer,
+		PurchaseYear:    req.PurchaseYear,
+		Metadata:        metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if asset.Status == "" {
+		asset.Status = dto.AssetStatusActive
+	}
+	if req.WarrantyUntil != nil {
+		warrantyUntil, _ := time.Parse("2006-01-02", *req.WarrantyUntil)
+		asset.WarrantyUntil = &warrantyUntil
+	}
+	return asset
+}
+
+// markImportDuplicates flags rows that share an inventory number, serial number, MAC or IP address
+// with an existing asset or with an earlier row of the same file. Duplicates are invalid rows.
+func (s *AssetService) markImportDuplicates(ctx context.Context, tenantID string, entries []assetImportEntry) error {
+	var numbers, serials, macAddresses, ipAddresses []string
+	keys := make([][][2]string, len(entries))
+	for i := range entries {
+		keys[i] = assetImportKeys(entries[i].row)
+		for _, key := range keys[i] {
+			switch key[0] {
+			case dto.AssetImportFieldInventoryNumber:
+				numbers = append(numbers, key[1])
+			case dto.AssetImportFieldSerialNumber:
+				serials = append(serials, key[1])
+			case dto.AssetImportFieldMACAddress:
+				macAddresses = append(macAddresses, key[1])
+			case dto.AssetImportFieldIPAddress:
+				ipAddresses = append(ipAddresses, key[1])
+			}
+		}
+	}
+
+	existing, err := s.assetRepo.FindImportDuplicates(ctx, tenantID, numbers, serials, macAddresses, ipAddresses)
+	if err != nil {
+		return err
+	}
+	existingByKey := make(map[[2]string]repo.Asset)
+	for _, asset := range existing {
+		assetKeys := [][2]string{{dto.AssetImportFieldInventoryNumber, strings.ToUpper(asset.InventoryNumber)}}
+		if asset.SerialNumber != nil {
+			assetKeys = append(assetKeys, [2]string{dto.AssetImportFieldSerialNumber, strings.ToUpper(*asset.SerialNumber)})
+		}
+		if asset.MACAddress != nil {
+			assetKeys = append(assetKeys, [2]string{dto.AssetImportFieldMACAddress, *asset.MACAddress})
+		}
+		if asset.IPAddress != nil {
+			assetKeys = append(assetKeys, [2]string{dto.AssetImportFieldIPAddress, *asset.IPAddress})
+		}
+		for _, key := range assetKeys {
+			if _, seen := existingByKey[key]; !seen {
+				existingByKey[key] = asset
+			}
+		}
+	}
+
+	rowByKey := make(map[[2]string]int)
+	for i := range entries {
+		row := &entries[i].row
+		for _, key := range keys[i] {
+			if asset, ok := existingByKey[key]; ok {
+				row.DuplicateOf = &dto.AssetImportDuplicate{
+					AssetID:         asset.ID,
+					InventoryNumber: asset.InventoryNumber,
+					Name:            asset.Name,
+					MatchedBy:       key[0],
+				}
+				row.Errors = append(row.Errors, fmt.Sprintf("%s: duplicate of asset %s %q", key[0], asset.InventoryNumber, asset.Name))
+				break
+			}
+			if rowNumber, ok := rowByKey[key]; ok {
+				row.DuplicateOf = &dto.AssetImportDuplicate{RowNumber: rowNumber, MatchedBy: key[0]}
+				row.Errors = append(row.Errors, fmt.Sprintf("%s: duplicate of row %d", key[0], rowNumber))
+				break
+			}
+		}
+		for _, key := range keys[i] {
+			if _, seen := rowByKey[key]; !seen {
+				rowByKey[key] = row.RowNumber
+			}
+		}
+		if row.DuplicateOf != nil {
+			row.Valid = false
+			entries[i].asset = nil
+		}
+	}
+	return nil
+}
+
+// assetImportKeys returns the identifying (field, value) pairs of a row in matching priority order.
+// Placeholder serial numbers such as "To be filled by O.E.M." are not used for matching.
+func assetImportKeys(row dto.AssetImportRowResponse) [][2]string {
+	var keys [][2]string
+	if row.InventoryNumber != "" {
+		keys = append(keys, [2]string{dto.AssetImportFieldInventoryNumber, strings.ToUpper(row.InventoryNumber)})
+	}
+	if row.Asset.SerialNumber != nil {
+		if serial := normalizeDiscoverySerial(*row.Asset.SerialNumber); serial != "" {
+			keys = append(keys, [2]string{dto.AssetImportFieldSerialNumber, strings.ToUpper(serial)})
+		}
+	}
+	if row.Asset.MACAddress != nil && *row.Asset.MACAddress != "" {
+		keys = append(keys, [2]string{dto.AssetImportFieldMACAddress, *row.Asset.MACAddress})
+	}
+	if row.Asset.IPAddress != nil && *row.Asset.IPAddress != "" {
+		keys = append(keys, [2]string{dto.AssetImportFieldIPAddress, *row.Asset.IPAddress})
+	}
+	return keys
+}
+
+// assignImportInventoryNumbers numbers assets by the tenant's inventory number rules (type and class first,
+// then type only). Assets without a matching rule keep an empty number and get the repository default.
+func (s *AssetService) assignImportInventoryNumbers(ctx context.Context, tenantID string, assets []repo.Asset) {
+	if s.inventoryNumbers == nil {
+		return
+	}
+
+	withoutRule := make(map[string]bool)
+	for i := range assets {
+		asset := &assets[i]
+		key := asset.Type + "/" + asset.Class
+		if asset.InventoryNumber != "" || withoutRule[key] {
+			continue
+		}
+
+		class := asset.Class
+		generated, err := s.inventoryNumbers.GenerateInventoryNumber(ctx, tenantID, asset.Type, &class)
+		if err != nil {
+			generated, err = s.inventoryNumbers.GenerateInventoryNumber(ctx, tenantID, asset.Type, nil)
+		}
+		if err != nil {
+			log.Printf("WARNING: no inventory number rule applied for imported %s assets: %v", key, err)
+			withoutRule[key] = true
+			continue
+		}
+		asset.InventoryNumber = generated.InventoryNumber
+	}
+}
+
+// importChoice maps a cell to its canonical value; unknown values are returned lowercased
+func importChoice(value string, choices map[string]string) string {
+	if canonical, ok := choices[normalizeImportHeader(value)]; ok {
+		return canonical
+	}
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// importStringPtr returns nil for empty values so optional columns stay NULL
+func importStringPtr(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+)
+
+type nmapRun struct {
+	Start int64      `xml:"start,attr"`
+	Args  string     `xml:"args,attr"`
+	Hosts []nmapHost `xml:"host"`
+}
+
+type nmapHost struct {
+	Status struct {
+		State string `xml:"state,attr"`
+	} `xml:"status"`
+	Addresses []struct {
+		Addr     string `xml:"addr,attr"`
+		AddrType string `xml:"addrtype,attr"`
+		Vendor   string `xml:"vendor,attr"`
+	} `xml:"address"`
+	Hostnames []struct {
+		Name string `xml:"name,attr"`
+	} `xml:"hostnames>hostname"`
+	Ports []struct {
+		Protocol string `xml:"protocol,attr"`
+		PortID   int    `xml:"portid,attr"`
+		State    struct {
+			State string `xml:"state,attr"`
+		} `xml:"state"`
+		Service struct {
+			Name    string `xml:"name,attr"`
+			Product string `xml:"product,attr"`
+			Version string `xml:"version,attr"`
+		} `xml:"service"`
+	} `xml:"ports>port"`
+	OSMatches []struct {
+		Name      string `xml:"name,attr"`
+		Accuracy  int    `xml:"accuracy,attr"`
+		OSClasses []struct {
+			Type   string `xml:"type,attr"`
+			Vendor string `xml:"vendor,attr"`
+		} `xml:"osclass"`
+	} `xml:"os>osmatch"`
+}
+
+// nmapScannedHost is a live host of an nmap scan converted to asset fields
+type nmapScannedHost struct {
+	IPAddress    string
+	MACAddress   string
+	Vendor       string
+	Hostnames    []string
+	OS           string
+	OSClassTypes []string
+	Services     []dto.AssetImportService
+}
+
+// nmapNetworkDeviceTypes are nmap OS class device types imported as network_device
+var nmapNetworkDeviceTypes = map[string]bool{
+	"router":           true,
+	"broadband router": true,
+	"switch":           true,
+	"firewall":         true,
+	"wap":              true,
+	"bridge":           true,
+	"load balancer":    true,
+	"proxy server":     true,
+	"printer":          true,
+	"print server":     true,
+	"voip adapter":     true,
+	"voip phone":       true,
+	"pbx":              true,
+	"storage-misc":     true,
+	"terminal server":  true,
+	"webcam":           true,
+	"power-device":     true,
+	"security-misc":    true,
+}
+
+// nmapServerPorts are open ports that identify a host without an OS match as a server
+var nmapServerPorts = map[int]bool{
+	22: true, 25: true, 53: true, 80: true, 110: true, 143: true, 389: true, 443: true, 445: true,
+	636: true, 1433: true, 1521: true, 3306: true, 3389: true, 5432: true, 5985: true, 6379: true,
+	8080: true, 8443: true, 9200: true, 27017: true,
+}
+
+// parseNmapXML reads the live hosts of an nmap XML report (nmap -oX). Hosts that are down are skipped.
+func parseNmapXML(data []byte) ([]nmapScannedHost, *time.Time, error) {
+	var run nmapRun
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&run); err != nil {
+		return nil, nil, NewValidationError("file", fmt.Sprintf("invalid nmap XML: %v", err))
+	}
+
+	var scannedAt *time.Time
+	if run.Start > 0 {
+		start := time.Unix(run.Start, 0).UTC()
+		scannedAt = &start
+	}
+
+	var hosts []nmapScannedHost
+	for _, host := range run.Hosts {
+		if host.Status.State != "" && host.Status.State != "up" {
+			continue
+		}
+		hosts = append(hosts, convertNmapHost(host))
+	}
+	if len(hosts) == 0 {
+		return nil, nil, NewValidationError("file", "nmap report contains no live hosts")
+	}
+	if len(hosts) > maxImportRows {
+		return nil, nil, NewValidationError("file", fmt.Sprintf("nmap report contains more than %d hosts", maxImportRows))
+	}
+	return hosts, scannedAt, nil
+}
+
+func convertNmapHost(host nmapHost) nmapScannedHost {
+	var scanned nmapScannedHost
+	for _, address := range host.Addresses {
+		switch address.AddrType {
+		case "ipv4", "ipv6":
+			// The first IPv4 address wins; IPv6 is used only for IPv6-only hosts
+			if ip := net.ParseIP(address.Addr); ip != nil && (scanned.IPAddress == "" || (ip.To4() != nil && strings.Contains(scanned.IPAddress, ":"))) {
+				scanned.IPAddress = ip.String()
+			}
+		case "mac":
+			if mac := normalizeDiscoveryMAC(address.Addr); mac != "" && scanned.MACAddress == "" {
+				scanned.MACAddress = mac
+				scanned.Vendor = strings.TrimSpace(address.Vendor)
+			}
+		}
+	}
+
+	for _, hostname := range host.Hostnames {
+		name := strings.TrimSpace(hostname.Name)
+		if name != "" && !containsString(scanned.Hostnames, name) {
+			scanned.Hostnames = append(scanned.Hostnames, name)
+		}
+	}
+
+	bestAccuracy := -1
+	for _, match := range host.OSMatches {
+		if match.Accuracy <= bestAccuracy {
+			continue
+		}
+		bestAccuracy = match.Accuracy
+		scanned.OS = strings.TrimSpace(match.Name)
+		scanned.OSClassTypes = scanned.OSClassTypes[:0]
+		for _, class := range match.OSClasses {
+			scanned.OSClassTypes = append(scanned.OSClassTypes, strings.ToLower(strings.TrimSpace(class.Type)))
+		}
+	}
+
+	for _, port := range host.Ports {
+		if port.State.State != "open" {
+			continue
+		}
+		scanned.Services = append(scanned.Services, dto.AssetImportService{
+			Port:     port.PortID,
+			Protocol: port.Protocol,
+			Name:     port.Service.Name,
+			Product:  port.Service.Product,
+			Version:  port.Service.Version,
+		})
+	}
+	return scanned
+}
+
+// assetType classifies the host: network equipment by OS class, then general purpose OS or
+// well-known server ports as server, anything else as network_device
+func (h nmapScannedHost) assetType() string {
+	for _, classType := range h.OSClassTypes {
+		if nmapNetworkDeviceTypes[classType] {
+			return dto.AssetTypeNetworkDevice
+		}
+	}
+	for _, classType := range h.OSClassTypes {
+		if classType == "general purpose" {
+			return dto.AssetTypeServer
+		}
+	}
+	for _, service := range h.Services {
+		if nmapServerPorts[service.Port] {
+			return dto.AssetTypeServer
+		}
+	}
+	return dto.AssetTypeNetworkDevice
+}
+
+// name returns the first DNS name of the host, or its IP address if it has none
+func (h nmapScannedHost) name() string {
+	if len(h.Hostnames) > 0 {
+		return h.Hostnames[0]
+	}
+	if h.IPAddress != "" {
+		return h.IPAddress
+	}
+	return h.MACAddress
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNmapReport = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nmaprun>
+<nmaprun scanner="nmap" args="nmap -O -sV -oX scan.xml 10.0.0.0/24" start="1772960400">
+  <host>
+    <status state="up" reason="arp-response"/>
+    <address addr="fe80::1" addrtype="ipv6"/>
+    <address addr="10.0.0.10" addrtype="ipv4"/>
+    <address addr="00:50:56:AA:BB:CC" addrtype="mac" vendor="VMware"/>
+    <hostnames>
+      <hostname name="db01.corp.local" type="PTR"/>
+      <hostname name="db01.corp.local" type="user"/>
+      <hostname name="postgres.corp.local" type="PTR"/>
+    </hostnames>
+    <ports>
+      <port protocol="tcp" portid="22"><state state="open"/><service name="ssh" product="OpenSSH" version="8.9p1"/></port>
+      <port protocol="tcp" portid="5432"><state state="open"/><service name="postgresql" product="PostgreSQL DB" version="14.5"/></port>
+      <port protocol="tcp" portid="8080"><state state="filtered"/><service name="http-proxy"/></port>
+    </ports>
+    <os>
+      <osmatch name="Linux 4.15 - 5.6" accuracy="95"><osclass type="general purpose" vendor="Linux"/></osmatch>
+      <osmatch name="Linux 2.6.32" accuracy="90"><osclass type="general purpose" vendor="Linux"/></osmatch>
+    </os>
+  </host>
+  <host>
+    <status state="down" reason="no-response"/>
+    <address addr="10.0.0.11" addrtype="ipv4"/>
+  </host>
+  <host>
+    <status state="up" reason="echo-reply"/>
+    <address addr="10.0.0.1" addrtype="ipv4"/>
+    <ports>
+      <port protocol="tcp" portid="443"><state state="open"/><service name="https"/></port>
+    </ports>
+    <os>
+      <osmatch name="Cisco IOS 15" accuracy="92"><osclass type="router" vendor="Cisco"/><osclass type="switch" vendor="Cisco"/></osmatch>
+    </os>
+  </host>
+</nmaprun>`
+
+func TestParseNmapXML(t *testing.T) {
+	hosts, scannedAt, err := parseNmapXML([]byte(testNmapReport))
+	require.NoError(t, err)
+
+	require.NotNil(t, scannedAt)
+	assert.Equal(t, time.Unix(1772960400, 0).UTC(), *scannedAt)
+	require.Len(t, hosts, 2, "hosts that are down are skipped")
+
+	db := hosts[0]
+	assert.Equal(t, "10.0.0.10", db.IPAddress, "IPv4 is preferred over IPv6")
+	assert.Equal(t, "00:50:56:aa:bb:cc", db.MACAddress)
+	assert.Equal(t, "VMware", db.Vendor)
+	assert.Equal(t, []string{"db01.corp.local", "postgres.corp.local"}, db.Hostnames)
+	assert.Equal(t, "Linux 4.15 - 5.6", db.OS, "the most accurate OS match wins")
+	assert.Equal(t, []string{"general purpose"}, db.OSClassTypes)
+	assert.Equal(t, []dto.AssetImportService{
+		{Port: 22, Protocol: "tcp", Name: "ssh", Product: "OpenSSH", Version: "8.9p1"},
+		{Port: 5432, Protocol: "tcp", Name: "postgresql", Product: "PostgreSQL DB", Version: "14.5"},
+	}, db.Services, "only open ports are kept")
+
+	router := hosts[1]
+	assert.Equal(t, "10.0.0.1", router.IPAddress)
+	assert.Equal(t, []string{"router", "switch"}, router.OSClassTypes)
+}
+
+func TestParseNmapXML_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not xml", "Nmap scan report for 10.0.0.1"},
+		{"no live hosts", `<nmaprun><host><status state="down"/><address addr="10.0.0.1" addrtype="ipv4"/></host></nmaprun>`},
+		{"empty run", `<nmaprun start="0"></nmaprun>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := parseNmapXML([]byte(tt.data))
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "file", validationErr.Field)
+		})
+	}
+}
+
+func TestNmapScannedHost_AssetType(t *testing.T) {
+	tests := []struct {
+		name string
+		host nmapScannedHost
+		want string
+	}{
+		{"router os class", nmapScannedHost{OSClassTypes: []string{"router"}}, dto.AssetTypeNetworkDevice},
+		{"network class wins over general purpose", nmapScannedHost{OSClassTypes: []string{"general purpose", "firewall"}}, dto.AssetTypeNetworkDevice},
+		{"general purpose os", nmapScannedHost{OSClassTypes: []string{"general purpose"}}, dto.AssetTypeServer},
+		{"server port without os", nmapScannedHost{Services: []dto.AssetImportService{{Port: 3389}}}, dto.AssetTypeServer},
+		{"unknown port without os", nmapScannedHost{Services: []dto.AssetImportService{{Port: 9100}}}, dto.AssetTypeNetworkDevice},
+		{"nothing known", nmapScannedHost{}, dto.AssetTypeNetworkDevice},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.host.assetType())
+		})
+	}
+}
+
+func TestNmapScannedHost_Name(t *testing.T) {
+	assert.Equal(t, "db01", nmapScannedHost{Hostnames: []string{"db01"}, IPAddress: "10.0.0.1"}.name())
+	assert.Equal(t, "10.0.0.1", nmapScannedHost{IPAddress: "10.0.0.1", MACAddress: "00:50:56:aa:bb:cc"}.name())
+	assert.Equal(t, "00:50:56:aa:bb:cc", nmapScannedHost{MACAddress: "00:50:56:aa:bb:cc"}.name())
+}
+
+func TestBuildNmapImportRow(t *testing.T) {
+	hosts, scannedAt, err := parseNmapXML([]byte(testNmapReport))
+	require.NoError(t, err)
+
+	entry := buildNmapImportRow("tenant-1", hosts[0], 1, scannedAt)
+	require.True(t, entry.row.Valid, entry.row.Errors)
+	require.NotNil(t, entry.asset)
+
+	assert.Equal(t, "db01.corp.local", entry.asset.Name)
+	assert.Equal(t, dto.AssetTypeServer, entry.asset.Type)
+	assert.Equal(t, dto.AssetClassHardware, entry.asset.Class)
+	assert.Equal(t, dto.CriticalityMedium, entry.asset.Criticality)
+	require.NotNil(t, entry.row.Asset.IPAddress)
+	assert.Equal(t, "10.0.0.10", *entry.row.Asset.IPAddress)
+
+	require.NotNil(t, entry.asset.Metadata)
+	var metadata struct {
+		NetworkScan struct {
+			Source    string                   `json:"source"`
+			Hostnames []string                 `json:"hostnames"`
+			OS        string                   `json:"os"`
+			Services  []dto.AssetImportService `json:"services"`
+			ScannedAt string                   `json:"scanned_at"`
+		} `json:"network_scan"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(*entry.asset.Metadata), &metadata))
+	assert.Equal(t, dto.AssetImportSourceNmap, metadata.NetworkScan.Source)
+	assert.Equal(t, "Linux 4.15 - 5.6", metadata.NetworkScan.OS)
+	assert.Len(t, metadata.NetworkScan.Services, 2)
+	assert.Equal(t, scannedAt.Format(time.RFC3339), metadata.NetworkScan.ScannedAt)
+
+	withoutIP := buildNmapImportRow("tenant-1", nmapScannedHost{MACAddress: "00:50:56:aa:bb:cc"}, 2, nil)
+	assert.False(t, withoutIP.row.Valid)
+	assert.Nil(t, withoutIP.asset)
+	assert.Contains(t, withoutIP.row.Errors, "ip_address: host has no IP address")
+}
@@ -0,0 +1,304 @@
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestXLSX packs the given parts into a minimal XLSX archive
+func buildTestXLSX(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	for name, content := range files {
+		writer, err := archive.Create(name)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buffer.Bytes()
+}
+
+func TestReadImportTable_CSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want [][]string
+	}{
+		{
+			name: "comma separated",
+			data: "name,type\nweb-01,server\n",
+			want: [][]string{{"name", "type"}, {"web-01", "server"}},
+		},
+		{
+			name: "semicolon separated with BOM and quotes",
+			data: "\xef\xbb\xbfНазвание;Тип;Расположение\r\n\"ws-01\"; рабочая станция;\"Москва; офис 1\"\r\n",
+			want: [][]string{{"Название", "Тип", "Расположение"}, {"ws-01", "рабочая станция", "Москва; офис 1"}},
+		},
+		{
+			name: "ragged rows and trailing empty lines",
+			data: "name,type,location\nweb-01,server\n,,\n",
+			want: [][]string{{"name", "type", "location"}, {"web-01", "server"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readImportTable("assets.csv", []byte(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rows)
+		})
+	}
+}
+
+func TestReadImportTable_XLSX(t *testing.T) {
+	data := buildTestXLSX(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Активы" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId3" Type="worksheet" Target="worksheets/assets.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>name</t></si><si><t>ip</t></si><si><r><t>db</t></r><r><t>-01</t></r></si></sst>`,
+		"xl/worksheets/assets.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>2020</v></c><c r="C2" t="inlineStr"><is><t> 10.0.0.5 </t></is></c></row>
+		</sheetData></worksheet>`,
+	})
+
+	rows, err := readImportTable("Assets.XLSX", data)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name", "", "ip"}, {"db-01", "2020", "10.0.0.5"}}, rows)
+}
+
+func TestReadImportTable_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+	}{
+		{"unsupported extension", "assets.xls", []byte("name\nweb-01\n")},
+		{"header only", "assets.csv", []byte("name,type\n")},
+		{"not utf-8", "assets.csv", []byte("name\n\xcf\xf0\xe8\xe2\xe5\xf2\n")},
+		{"broken quotes", "assets.csv", []byte("name\n\"web-01\n")},
+		{"not a zip", "assets.xlsx", []byte("plain text")},
+		{"bad shared string", "assets.xlsx", buildTestXLSX(t, map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="A1" t="s"><v>7</v></c></row><row><c r="A2"><v>1</v></c></row></sheetData></worksheet>`,
+		})},
+		{"huge column reference", "assets.xlsx", buildTestXLSX(t, map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="A1" t="inlineStr"><is><t>name</t></is></c><c r="ZZZZZZ1"><v>x</v></c></row><row><c r="A2"><v>1</v></c></row></sheetData></worksheet>`,
+		})},
+		{"overflowing column reference", "assets.xlsx", buildTestXLSX(t, map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row><c r="A1"><v>name</v></c></row><row><c r="` + strings.Repeat("Z", 40) + `2"><v>1</v></c></row></sheetData></worksheet>`,
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readImportTable(tt.filename, tt.data)
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "file", validationErr.Field)
+		})
+	}
+}
+
+func TestXLSXColumnIndex(t *testing.T) {
+	tests := map[string]int{"A1": 0, "C7": 2, "Z3": 25, "AA10": 26, "AB12": 27, "XFD1": 16383, "12": -1, "": -1}
+	for ref, want := range tests {
+		got, err := xlsxColumnIndex(ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, want, got, ref)
+	}
+
+	for _, ref := range []string{"XFE1", "ZZZ1", "AAAA1", "ZZZZZZ1", strings.Repeat("Z", 40) + "1"} {
+		_, err := xlsxColumnIndex(ref)
+		assert.Error(t, err, ref)
+	}
+}
+
+func TestResolveImportMapping(t *testing.T) {
+	headers := []string{"Наименование", "IP-адрес", "Серийный номер", "Owner", "Инв. номер", "Owner"}
+
+	mapping, err := resolveImportMapping(headers, assetImportAliases, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, mapping[dto.AssetImportFieldName])
+	assert.Equal(t, 1, mapping[dto.AssetImportFieldIPAddress])
+	assert.Equal(t, 2, mapping[dto.AssetImportFieldSerialNumber])
+	assert.Equal(t, 3, mapping[dto.AssetImportFieldOwnerEmail], "the first of duplicate headers is used")
+	assert.Equal(t, 4, mapping[dto.AssetImportFieldInventoryNumber])
+	assert.NotContains(t, mapping, dto.AssetImportFieldType)
+
+	explicit, err := resolveImportMapping(headers, assetImportAliases, map[string]string{
+		dto.AssetImportFieldLocation:  "серийный_номер",
+		dto.AssetImportFieldIPAddress: "",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, explicit[dto.AssetImportFieldLocation])
+	assert.NotContains(t, explicit, dto.AssetImportFieldIPAddress, "an empty header unmaps the field")
+
+	tests := []struct {
+		name     string
+		explicit map[string]string
+	}{
+		{"unknown field", map[string]string{"colour": "Наименование"}},
+		{"missing column", map[string]string{dto.AssetImportFieldName: "Hostname"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveImportMapping(headers, assetImportAliases, tt.explicit)
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "mapping", validationErr.Field)
+		})
+	}
+}
+
+func TestBuildAssetImportRow(t *testing.T) {
+	headers := []string{"Название", "Тип", "Класс", "Критичность", "Доступность", "Статус", "Владелец", "IP", "MAC", "Год покупки", "Гарантия до", "Серийный номер", "Инвентарный номер"}
+	mapping, err := resolveImportMapping(headers, assetImportAliases, nil)
+	require.NoError(t, err)
+	owners := map[string]string{"ivanov@corp.local": "6f1c1a8e-8a53-4c55-9d3c-4c1a2f4a9b10"}
+
+	tests := []struct {
+		name       string
+		row        []string
+		wantErrors []string
+		check      func(t *testing.T, req dto.CreateAssetRequest)
+	}{
+		{
+			name: "russian values and defaults",
+			row:  []string{"srv-db-01", "Сервер", "", "Высокая", "", "В эксплуатации", "Ivanov@corp.local", "10.0.0.10", "00-50-56-AA-BB-CC", "2021", "31.12.2026", "SN-1", "INV-001"},
+			check: func(t *testing.T, req dto.CreateAssetRequest) {
+				assert.Equal(t, dto.AssetTypeServer, req.Type)
+				assert.Equal(t, dto.AssetClassHardware, req.Class, "class defaults by type")
+				assert.Equal(t, dto.CriticalityHigh, req.Criticality)
+				assert.Equal(t, dto.CriticalityHigh, req.Confidentiality, "empty ratings default to criticality")
+				assert.Equal(t, dto.CriticalityHigh, req.Availability)
+				assert.Equal(t, dto.AssetStatusActive, req.Status)
+				assert.Equal(t, owners["ivanov@corp.local"], req.OwnerID)
+				assert.Equal(t, "00:50:56:aa:bb:cc", *req.MACAddress)
+				assert.Equal(t, 2021, *req.PurchaseYear)
+				assert.Equal(t, "2026-12-31", *req.WarrantyUntil)
+			},
+		},
+		{
+			name: "minimal row",
+			row:  []string{"Portal", "application"},
+			check: func(t *testing.T, req dto.CreateAssetRequest) {
+				assert.Equal(t, dto.AssetClassSoftware, req.Class)
+				assert.Equal(t, dto.CriticalityMedium, req.Criticality)
+				assert.Nil(t, req.IPAddress)
+				assert.Empty(t, req.OwnerID)
+			},
+		},
+		{
+			name: "invalid cells",
+			row:  []string{"", "printer", "", "critical", "", "", "nobody@corp.local", "10.0.0.300", "zz", "two thousand", "someday"},
+			wantErrors: []string{
+				`ip_address: "10.0.0.300" is not a valid IP address`,
+				`mac_address: "zz" is not a valid MAC address`,
+				`purchase_year: "two thousand" is not a number`,
+				`warranty_until: invalid date "someday", use YYYY-MM-DD`,
+				`owner_email: user "nobody@corp.local" not found`,
+				"name: is required",
+				"type: must be one of server workstation application database document network_device other",
+				"class: is required",
+				"criticality: must be one of low medium high",
+				"confidentiality: must be one of low medium high",
+				"integrity: must be one of low medium high",
+				"availability: must be one of low medium high",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := buildAssetImportRow("tenant-1", tt.row, 2, mapping, owners)
+			assert.Equal(t, 2, entry.row.RowNumber)
+			if len(tt.wantErrors) > 0 {
+				assert.False(t, entry.row.Valid)
+				assert.Nil(t, entry.asset)
+				assert.Equal(t, tt.wantErrors, entry.row.Errors)
+				return
+			}
+			require.True(t, entry.row.Valid, entry.row.Errors)
+			require.NotNil(t, entry.asset)
+			assert.Equal(t, "tenant-1", entry.asset.TenantID)
+			tt.check(t, entry.row.Asset)
+		})
+	}
+}
+
+func TestParseImportDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "2026-12-31", want: "2026-12-31"},
+		{value: "31.12.2026", want: "2026-12-31"},
+		{value: "2026-12-31T10:00:00+03:00", want: "2026-12-31"},
+		{value: "46387", want: "2026-12-31"},
+		{value: "12/31/2026", wantErr: true},
+		{value: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseImportDate(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssetImportKeys(t *testing.T) {
+	serial, placeholder := "sn-1", "To be filled by O.E.M."
+	mac, ip := "00:50:56:aa:bb:cc", "10.0.0.5"
+
+	tests := []struct {
+		name string
+		row  dto.AssetImportRowResponse
+		want [][2]string
+	}{
+		{
+			name: "all identifiers in priority order",
+			row: dto.AssetImportRowResponse{
+				InventoryNumber: "inv-001",
+				Asset:           dto.CreateAssetRequest{SerialNumber: &serial, MACAddress: &mac, IPAddress: &ip},
+			},
+			want: [][2]string{
+				{dto.AssetImportFieldInventoryNumber, "INV-001"},
+				{dto.AssetImportFieldSerialNumber, "SN-1"},
+				{dto.AssetImportFieldMACAddress, mac},
+				{dto.AssetImportFieldIPAddress, ip},
+			},
+		},
+		{
+			name: "placeholder serial is skipped",
+			row:  dto.AssetImportRowResponse{Asset: dto.CreateAssetRequest{SerialNumber: &placeholder, IPAddress: &ip}},
+			want: [][2]string{{dto.AssetImportFieldIPAddress, ip}},
+		},
+		{
+			name: "no identifiers",
+			row:  dto.AssetImportRowResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, assetImportKeys(tt.row))
+		})
+	}
+}
@@ -0,0 +1,743 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+var inventoryCampaignStatusLabels = map[string]string{
+	dto.InventoryCampaignPlanned:    "Запланирована",
+	dto.InventoryCampaignInProgress: "Проводится",
+	dto.InventoryCampaignCompleted:  "Завершена",
+	dto.InventoryCampaignSigned:     "Подписана",
+	dto.InventoryCampaignCancelled:  "Отменена",
+}
+
+var inventoryResultLabels = map[string]string{
+	dto.InventoryResultPending:    "Не проверен",
+	dto.InventoryResultFound:      "Найден",
+	dto.InventoryResultMoved:      "Найден в другом месте",
+	dto.InventoryResultMissing:    "Не найден",
+	dto.InventoryResultUnexpected: "Выявлен сверх описи",
+}
+
+var inventoryResolutionLabels = map[string]string{
+	dto.InventoryResolutionConfirmedMissing: "Недостача подтверждена",
+	dto.InventoryResolutionFound:            "Найден позднее",
+	dto.InventoryResolutionLocationUpdated:  "Местоположение исправлено",
+	dto.InventoryResolutionReturned:         "Возвращен на место",
+	dto.InventoryResolutionIgnored:          "Без изменений",
+}
+
+// Inventory campaigns
+
+// CreateInventoryCampaign plans an inventory of the assets within the scope. The campaign is started
+// by the scheduler on the scheduled start date or manually before it.
+func (s *AssetService) CreateInventoryCampaign(ctx context.Context, tenantID string, req dto.InventoryCampaignRequest, createdBy string) (*dto.InventoryCampaignResponse, error) {
+	campaign := &repo.InventoryCampaign{
+		TenantID:  tenantID,
+		Status:    dto.InventoryCampaignPlanned,
+		CreatedBy: createdBy,
+	}
+	if err := s.applyInventoryCampaignRequest(ctx, campaign, req); err != nil {
+		return nil, err
+	}
+	if err := s.assetRepo.CreateInventoryCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	log.Printf("INFO: asset_service.CreateInventoryCampaign tenant=%s campaign=%s start=%s", tenantID, campaign.ID, campaign.ScheduledStart.Format("2006-01-02"))
+	response := inventoryCampaignResponse(*campaign, dto.InventoryCampaignSummary{})
+	return &response, nil
+}
+
+// UpdateInventoryCampaign changes the plan of a campaign that has not started yet
+func (s *AssetService) UpdateInventoryCampaign(ctx context.Context, campaignID, tenantID string, req dto.InventoryCampaignRequest) (*dto.InventoryCampaignResponse, error) {
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != dto.InventoryCampaignPlanned {
+		return nil, ErrInventoryCampaignStatus
+	}
+	if err := s.applyInventoryCampaignRequest(ctx, campaign, req); err != nil {
+		return nil, err
+	}
+	updated, err := s.assetRepo.UpdateInventoryCampaign(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInventoryCampaignStatus
+	}
+	return s.GetInventoryCampaign(ctx, campaignID, tenantID)
+}
+
+func (s *AssetService) applyInventoryCampaignRequest(ctx context.Context, campaign *repo.InventoryCampaign, req dto.InventoryCampaignRequest) error {
+	start, err := time.Parse("2006-01-02", req.ScheduledStart)
+	if err != nil {
+		return NewValidationError("scheduled_start", "invalid date")
+	}
+	var end *time.Time
+	if req.ScheduledEnd != nil {
+		parsed, err := time.Parse("2006-01-02", *req.ScheduledEnd)
+		if err != nil {
+			return NewValidationError("scheduled_end", "invalid date")
+		}
+		if parsed.Before(start) {
+			return NewValidationError("scheduled_end", "must not be before scheduled_start")
+		}
+		end = &parsed
+	}
+
+	auditorIDs := uniqueTrimmed(req.AuditorIDs)
+	for i := range auditorIDs {
+		if err := s.validateLifecycleUser(ctx, campaign.TenantID, "auditor_ids", &auditorIDs[i]); err != nil {
+			return err
+		}
+	}
+
+	campaign.Name = strings.TrimSpace(req.Name)
+	campaign.Description = trimmedPtr(req.Description)
+	campaign.ScopeLocations = uniqueTrimmed(req.ScopeLocations)
+	campaign.ScopeDepartments = uniqueTrimmed(req.ScopeDepartments)
+	campaign.ScopeTypes = uniqueTrimmed(req.ScopeTypes)
+	campaign.AuditorIDs = auditorIDs
+	campaign.ScheduledStart = start
+	campaign.ScheduledEnd = end
+	return nil
+}
+
+// StartInventoryCampaign starts a planned campaign ahead of its scheduled date and builds its inventory list
+func (s *AssetService) StartInventoryCampaign(ctx context.Context, campaignID, tenantID string) (*dto.InventoryCampaignResponse, error) {
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	started, err := s.assetRepo.StartInventoryCampaign(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, ErrInventoryCampaignStatus
+	}
+
+	log.Printf("INFO: asset_service.StartInventoryCampaign tenant=%s campaign=%s", tenantID, campaignID)
+	return s.GetInventoryCampaign(ctx, campaignID, tenantID)
+}
+
+// CancelInventoryCampaign cancels a planned or running campaign; scans made so far are kept
+func (s *AssetService) CancelInventoryCampaign(ctx context.Context, campaignID, tenantID string) (*dto.InventoryCampaignResponse, error) {
+	if _, err := s.getInventoryCampaign(ctx, campaignID, tenantID); err != nil {
+		return nil, err
+	}
+	cancelled, err := s.assetRepo.CancelInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrInventoryCampaignStatus
+	}
+	return s.GetInventoryCampaign(ctx, campaignID, tenantID)
+}
+
+// GetInventoryCampaign returns a campaign with the counts of its inventory list
+func (s *AssetService) GetInventoryCampaign(ctx context.Context, campaignID, tenantID string) (*dto.InventoryCampaignResponse, error) {
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.assetRepo.CountInventoryItems(ctx, []string{campaign.ID})
+	if err != nil {
+		return nil, err
+	}
+	response := inventoryCampaignResponse(*campaign, summaries[campaign.ID])
+	return &response, nil
+}
+
+// ListInventoryCampaigns returns the tenant campaigns with their progress
+func (s *AssetService) ListInventoryCampaigns(ctx context.Context, tenantID string, filters map[string]interface{}) ([]dto.InventoryCampaignResponse, error) {
+	campaigns, err := s.assetRepo.ListInventoryCampaigns(ctx, tenantID, filters)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		ids = append(ids, campaign.ID)
+	}
+	summaries, err := s.assetRepo.CountInventoryItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.InventoryCampaignResponse, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		responses = append(responses, inventoryCampaignResponse(campaign, summaries[campaign.ID]))
+	}
+	return responses, nil
+}
+
+// RunInventoryScheduler periodically starts the planned campaigns whose scheduled date has come until ctx is cancelled
+func (s *AssetService) RunInventoryScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.startDueInventoryCampaigns(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.startDueInventoryCampaigns(ctx)
+		}
+	}
+}
+
+func (s *AssetService) startDueInventoryCampaigns(ctx context.Context) {
+	campaigns, err := s.assetRepo.ListDueInventoryCampaigns(ctx, time.Now())
+	if err != nil {
+		log.Printf("ERROR: AssetService inventory scheduler due campaigns: %v", err)
+		return
+	}
+	started := 0
+	for i := range campaigns {
+		ok, err := s.assetRepo.StartInventoryCampaign(ctx, &campaigns[i])
+		if err != nil {
+			log.Printf("ERROR: AssetService inventory scheduler start campaign=%s: %v", campaigns[i].ID, err)
+			continue
+		}
+		if ok {
+			started++
+		}
+	}
+	if started > 0 {
+		log.Printf("DEBUG: AssetService inventory scheduler started %d inventory campaigns", started)
+	}
+}
+
+// Scanning
+
+// ScanInventoryLabel records a scanned QR or barcode label of a running campaign. The label may hold the
+// inventory number itself or a link with it. An asset of the inventory list is found, or moved if it was
+// scanned outside its recorded location; any other number is recorded as unexpected. Only the campaign
+// auditors can scan.
+func (s *AssetService) ScanInventoryLabel(ctx context.Context, campaignID, tenantID string, req dto.InventoryScanRequest, userID string) (*dto.InventoryScanResponse, error) {
+	number := parseInventoryLabel(req.Code)
+	if number == "" || len(number) > 255 {
+		return nil, NewValidationError("code", "label does not contain an inventory number")
+	}
+	location := trimmedPtr(req.Location)
+
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != dto.InventoryCampaignInProgress {
+		return nil, ErrInventoryCampaignStatus
+	}
+	if !isInventoryAuditor(campaign, userID) {
+		return nil, ErrInventoryNotAuditor
+	}
+
+	item, err := s.assetRepo.GetInventoryItemByNumber(ctx, campaignID, number)
+	if err != nil {
+		return nil, err
+	}
+	duplicate := item != nil && item.ScanCount > 0
+	if item == nil {
+		item = &repo.InventoryItem{
+			CampaignID:      campaignID,
+			InventoryNumber: number,
+			Result:          dto.InventoryResultUnexpected,
+		}
+		asset, err := s.assetRepo.FindAssetByInventoryNumber(ctx, tenantID, number)
+		if err != nil {
+			return nil, err
+		}
+		if asset != nil {
+			item.AssetID = &asset.ID
+			item.InventoryNumber = asset.InventoryNumber
+			item.AssetName = &asset.Name
+			item.ExpectedLocation = asset.Location
+		}
+	} else if item.Expected {
+		item.Result = inventoryScanResult(item.ExpectedLocation, location)
+	}
+
+	now := time.Now()
+	code := strings.TrimSpace(req.Code)
+	item.ScannedCode = &code
+	item.ScannedLocation = location
+	item.ScannedBy = &userID
+	item.ScannedAt = &now
+	item.ScanCount++
+	if err := s.assetRepo.SaveInventoryScan(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return &dto.InventoryScanResponse{Item: inventoryItemResponse(*item), Duplicate: duplicate}, nil
+}
+
+// parseInventoryLabel extracts the inventory number from the label content: either the number itself or
+// a link with the number in the inventory_number (inv) query parameter or in the last path segment
+func parseInventoryLabel(code string) string {
+	code = strings.TrimFunc(code, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) })
+	u, err := url.Parse(code)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return code
+	}
+	for _, key := range []string{"inventory_number", "inv"} {
+		if value := strings.TrimSpace(u.Query().Get(key)); value != "" {
+			return value
+		}
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	return strings.TrimSpace(segments[len(segments)-1])
+}
+
+// inventoryScanResult compares the scan location with the recorded one; a scan without a location,
+// or of an asset without a recorded location, counts as found
+func inventoryScanResult(expected, scanned *string) string {
+	if expected == nil || scanned == nil || strings.TrimSpace(*expected) == "" || strings.TrimSpace(*scanned) == "" ||
+		strings.EqualFold(strings.TrimSpace(*expected), strings.TrimSpace(*scanned)) {
+		return dto.InventoryResultFound
+	}
+	return dto.InventoryResultMoved
+}
+
+func isInventoryAuditor(campaign *repo.InventoryCampaign, userID string) bool {
+	for _, auditorID := range campaign.AuditorIDs {
+		if auditorID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Inventory list and discrepancies
+
+// ListInventoryItems returns the inventory list of the campaign
+func (s *AssetService) ListInventoryItems(ctx context.Context, campaignID, tenantID string, filters map[string]interface{}) ([]dto.InventoryItemResponse, error) {
+	if _, err := s.getInventoryCampaign(ctx, campaignID, tenantID); err != nil {
+		return nil, err
+	}
+	items, err := s.assetRepo.ListInventoryItems(ctx, campaignID, filters)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.InventoryItemResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, inventoryItemResponse(item))
+	}
+	return responses, nil
+}
+
+// ResolveInventoryDiscrepancy records how a moved, missing or unexpected asset was dealt with.
+// location_updated moves the asset to the scanned location. Only the campaign auditors can resolve
+// discrepancies, and only until the report is signed.
+func (s *AssetService) ResolveInventoryDiscrepancy(ctx context.Context, campaignID, itemID, tenantID string, req dto.InventoryResolveRequest, userID string) (*dto.InventoryItemResponse, error) {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != dto.InventoryCampaignInProgress && campaign.Status != dto.InventoryCampaignCompleted {
+		return nil, ErrInventoryCampaignStatus
+	}
+	if !isInventoryAuditor(campaign, userID) {
+		return nil, ErrInventoryNotAuditor
+	}
+
+	item, err := s.assetRepo.GetInventoryItem(ctx, itemID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrInventoryItemNotFound
+	}
+	if item.Resolution != nil {
+		return nil, NewValidationError("resolution", "discrepancy is already resolved")
+	}
+	allowed := inventoryResolutions(item)
+	if len(allowed) == 0 {
+		return nil, NewValidationError("resolution", fmt.Sprintf("%s item is not a discrepancy", item.Result))
+	}
+	if !containsString(allowed, req.Resolution) {
+		return nil, NewValidationError("resolution", fmt.Sprintf("%s item can be resolved as %s", item.Result, strings.Join(allowed, ", ")))
+	}
+
+	var newLocation *string
+	if req.Resolution == dto.InventoryResolutionLocationUpdated {
+		if item.ScannedLocation == nil {
+			return nil, NewValidationError("resolution", "scan location is unknown")
+		}
+		newLocation = item.ScannedLocation
+	}
+
+	now := time.Now()
+	item.Resolution = &req.Resolution
+	item.ResolutionComment = trimmedPtr(req.Comment)
+	item.ResolvedBy = &userID
+	item.ResolvedAt = &now
+	if err := s.assetRepo.ResolveInventoryItem(ctx, item, newLocation); err != nil {
+		return nil, err
+	}
+
+	response := inventoryItemResponse(*item)
+	return &response, nil
+}
+
+// inventoryResolutions lists the resolutions that fit the item result; nil if the item is not a discrepancy
+func inventoryResolutions(item *repo.InventoryItem) []string {
+	switch item.Result {
+	case dto.InventoryResultMissing:
+		return []string{dto.InventoryResolutionConfirmedMissing, dto.InventoryResolutionFound}
+	case dto.InventoryResultMoved:
+		return []string{dto.InventoryResolutionLocationUpdated, dto.InventoryResolutionReturned}
+	case dto.InventoryResultUnexpected:
+		if item.AssetID == nil {
+			return []string{dto.InventoryResolutionIgnored}
+		}
+		return []string{dto.InventoryResolutionLocationUpdated, dto.InventoryResolutionReturned, dto.InventoryResolutionIgnored}
+	}
+	return nil
+}
+
+// Completion and signing
+
+// CompleteInventoryCampaign finishes scanning: the assets of the list that were not scanned become missing
+// and the result of every listed asset is written to its history
+func (s *AssetService) CompleteInventoryCampaign(ctx context.Context, campaignID, tenantID, userID string) (*dto.InventoryCampaignResponse, error) {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if !isInventoryAuditor(campaign, userID) {
+		return nil, ErrInventoryNotAuditor
+	}
+	completed, err := s.assetRepo.CompleteInventoryCampaign(ctx, campaign, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, ErrInventoryCampaignStatus
+	}
+
+	log.Printf("INFO: asset_service.CompleteInventoryCampaign tenant=%s campaign=%s", tenantID, campaignID)
+	return s.GetInventoryCampaign(ctx, campaignID, tenantID)
+}
+
+// SignInventoryCampaign signs the final report of a completed campaign once every discrepancy is resolved.
+// The report is saved in the document storage and its SHA-256 is kept with the campaign.
+func (s *AssetService) SignInventoryCampaign(ctx context.Context, campaignID, tenantID string, req dto.InventorySignRequest, userID string) (*dto.InventoryCampaignResponse, error) {
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
+
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != dto.InventoryCampaignCompleted {
+		return nil, ErrInventoryCampaignStatus
+	}
+	if !isInventoryAuditor(campaign, userID) {
+		return nil, ErrInventoryNotAuditor
+	}
+	summaries, err := s.assetRepo.CountInventoryItems(ctx, []string{campaign.ID})
+	if err != nil {
+		return nil, err
+	}
+	if summaries[campaign.ID].Unresolved > 0 {
+		return nil, ErrInventoryUnresolvedDiscrepancies
+	}
+	if s.documentStorageService == nil {
+		return nil, errors.New("document storage is not configured")
+	}
+
+	now := time.Now()
+	campaign.SignedBy = &userID
+	campaign.SignedAt = &now
+	campaign.SignComment = trimmedPtr(req.Comment)
+
+	format := "html"
+	if req.GeneratePDF {
+		format = "pdf"
+	}
+	content, err := s.renderInventoryReport(ctx, campaign, format)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := "text/html"
+	if req.GeneratePDF {
+		mimeType = "application/pdf"
+	}
+	description := "Инвентаризационная опись"
+	document, err := s.documentStorageService.SaveGeneratedDocument(ctx, tenantID, content,
+		fmt.Sprintf("inventory-%s.%s", now.Format("2006-01-02"), format), mimeType, dto.UploadDocumentDTO{
+			Name:        fmt.Sprintf("Инвентаризационная опись — %s", campaign.Name),
+			Description: &description,
+			Tags:        []string{"#активы", "#инвентаризация"},
+		}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256(content))
+	campaign.ReportDocumentID = &document.ID
+	campaign.ReportSHA256 = &hash
+	signed, err := s.assetRepo.SignInventoryCampaign(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	if !signed {
+		return nil, ErrInventoryCampaignStatus
+	}
+
+	log.Printf("INFO: asset_service.SignInventoryCampaign tenant=%s campaign=%s document=%s", tenantID, campaignID, document.ID)
+	return s.GetInventoryCampaign(ctx, campaignID, tenantID)
+}
+
+// Report
+
+type inventoryReportItem struct {
+	No               int
+	InventoryNumber  string
+	Name             string
+	ExpectedLocation string
+	ScannedLocation  string
+	Result           string
+	Resolution       string
+	Comment          string
+	ScannedBy        string
+	ScannedAt        string
+}
+
+// GenerateInventoryReport renders the inventory report of the campaign in html or pdf format
+func (s *AssetService) GenerateInventoryReport(ctx context.Context, campaignID, tenantID, format string) ([]byte, error) {
+	campaign, err := s.getInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderInventoryReport(ctx, campaign, format)
+}
+
+func (s *AssetService) renderInventoryReport(ctx context.Context, campaign *repo.InventoryCampaign, format string) ([]byte, error) {
+	items, err := s.assetRepo.ListInventoryItems(ctx, campaign.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.assetRepo.CountInventoryItems(ctx, []string{campaign.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	userName := func(userID *string) string {
+		if userID == nil {
+			return ""
+		}
+		if name, ok := names[*userID]; ok {
+			return name
+		}
+		name := s.userDisplayName(ctx, userID)
+		names[*userID] = name
+		return name
+	}
+	formatTime := func(t *time.Time, layout string) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(layout)
+	}
+
+	var expected, discrepancies []inventoryReportItem
+	for _, item := range items {
+		row := inventoryReportItem{
+			InventoryNumber:  item.InventoryNumber,
+			Name:             safeString(item.AssetName),
+			ExpectedLocation: safeString(item.ExpectedLocation),
+			ScannedLocation:  safeString(item.ScannedLocation),
+			Result:           inventoryResultLabels[item.Result],
+			Comment:          safeString(item.ResolutionComment),
+			ScannedBy:        userName(item.ScannedBy),
+			ScannedAt:        formatTime(item.ScannedAt, "02.01.2006 15:04"),
+		}
+		if item.Resolution != nil {
+			row.Resolution = inventoryResolutionLabels[*item.Resolution]
+		}
+		if item.Expected {
+			row.No = len(expected) + 1
+			expected = append(expected, row)
+		}
+		if inventoryResolutions(&item) != nil {
+			discrepancies = append(discrepancies, row)
+		}
+	}
+
+	auditors := make([]string, 0, len(campaign.AuditorIDs))
+	for i := range campaign.AuditorIDs {
+		auditors = append(auditors, userName(&campaign.AuditorIDs[i]))
+	}
+
+	var scope []string
+	if len(campaign.ScopeLocations) > 0 {
+		scope = append(scope, "местоположение: "+strings.Join(campaign.ScopeLocations, ", "))
+	}
+	if len(campaign.ScopeDepartments) > 0 {
+		scope = append(scope, "подразделение: "+strings.Join(campaign.ScopeDepartments, ", "))
+	}
+	if len(campaign.ScopeTypes) > 0 {
+		scope = append(scope, "тип: "+strings.Join(campaign.ScopeTypes, ", "))
+	}
+	if len(scope) == 0 {
+		scope = append(scope, "все активы организации")
+	}
+
+	data := map[string]interface{}{
+		"Campaign":      campaign,
+		"Status":        inventoryCampaignStatusLabels[campaign.Status],
+		"Description":   safeString(campaign.Description),
+		"Scope":         strings.Join(scope, "; "),
+		"ScheduledAt":   campaign.ScheduledStart.Format("02.01.2006"),
+		"StartedAt":     formatTime(campaign.StartedAt, "02.01.2006 15:04"),
+		"CompletedAt":   formatTime(campaign.CompletedAt, "02.01.2006 15:04"),
+		"Auditors":      auditors,
+		"Summary":       summaries[campaign.ID],
+		"Items":         expected,
+		"Discrepancies": discrepancies,
+		"SignedBy":      userName(campaign.SignedBy),
+		"SignedAt":      formatTime(campaign.SignedAt, "02.01.2006 15:04"),
+		"SignComment":   safeString(campaign.SignComment),
+		"GeneratedAt":   time.Now().Format("02.01.2006 15:04"),
+	}
+
+	tmpl, err := template.ParseFS(templatesFS, "templates/inventory_report.html")
+	if err != nil {
+		log.Printf("ERROR: asset_service.renderInventoryReport ParseFS: %v", err)
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		log.Printf("ERROR: asset_service.renderInventoryReport Execute: %v", err)
+		return nil, err
+	}
+
+	if format == "html" {
+		return buf.Bytes(), nil
+	}
+
+	if s.actGenerator == nil {
+		return nil, fmt.Errorf("document templates are not configured")
+	}
+	pdf, err := s.actGenerator.GeneratePDFFromHTML(ctx, buf.String())
+	if err != nil {
+		log.Printf("ERROR: asset_service.renderInventoryReport GeneratePDFFromHTML: %v", err)
+		return nil, err
+	}
+	return pdf, nil
+}
+
+func (s *AssetService) getInventoryCampaign(ctx context.Context, campaignID, tenantID string) (*repo.InventoryCampaign, error) {
+	campaign, err := s.assetRepo.GetInventoryCampaign(ctx, campaignID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, ErrInventoryCampaignNotFound
+	}
+	return campaign, nil
+}
+
+func inventoryCampaignResponse(c repo.InventoryCampaign, summary dto.InventoryCampaignSummary) dto.InventoryCampaignResponse {
+	response := dto.InventoryCampaignResponse{
+		ID:               c.ID,
+		Name:             c.Name,
+		Description:      c.Description,
+		Status:           c.Status,
+		ScopeLocations:   nonNilStrings(c.ScopeLocations),
+		ScopeDepartments: nonNilStrings(c.ScopeDepartments),
+		ScopeTypes:       nonNilStrings(c.ScopeTypes),
+		AuditorIDs:       nonNilStrings(c.AuditorIDs),
+		ScheduledStart:   c.ScheduledStart.Format("2006-01-02"),
+		StartedAt:        c.StartedAt,
+		CompletedAt:      c.CompletedAt,
+		SignedBy:         c.SignedBy,
+		SignedAt:         c.SignedAt,
+		SignComment:      c.SignComment,
+		ReportDocumentID: c.ReportDocumentID,
+		ReportSHA256:     c.ReportSHA256,
+		CreatedBy:        c.CreatedBy,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+		Summary:          summary,
+	}
+	if c.ScheduledEnd != nil {
+		end := c.ScheduledEnd.Format("2006-01-02")
+		response.ScheduledEnd = &end
+	}
+	return response
+}
+
+func inventoryItemResponse(item repo.InventoryItem) dto.InventoryItemResponse {
+	return dto.InventoryItemResponse{
+		ID:                item.ID,
+		AssetID:           item.AssetID,
+		InventoryNumber:   item.InventoryNumber,
+		AssetName:         item.AssetName,
+		Expected:          item.Expected,
+		ExpectedLocation:  item.ExpectedLocation,
+		Result:            item.Result,
+		ScannedCode:       item.ScannedCode,
+		ScannedLocation:   item.ScannedLocation,
+		ScannedBy:         item.ScannedBy,
+		ScannedAt:         item.ScannedAt,
+		ScanCount:         item.ScanCount,
+		Resolution:        item.Resolution,
+		ResolutionComment: item.ResolutionComment,
+		ResolvedBy:        item.ResolvedBy,
+		ResolvedAt:        item.ResolvedAt,
+	}
+}
+
+// uniqueTrimmed returns the non-empty trimmed values in their first-seen order
+func uniqueTrimmed(values []string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInventoryLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"plain number", "INV-000123", "INV-000123"},
+		{"scanner suffix", " INV-000123\r\n", "INV-000123"},
+		{"control characters", "\x02INV-000123\x03", "INV-000123"},
+		{"inventory_number parameter", "https://risknexus.local/assets/scan?inventory_number=INV-000123&src=qr", "INV-000123"},
+		{"inv parameter", "https://risknexus.local/a?inv=INV-000123", "INV-000123"},
+		{"last path segment", "https://risknexus.local/assets/inventory/INV-000123/", "INV-000123"},
+		{"escaped path segment", "https://risknexus.local/assets/INV%20123", "INV 123"},
+		{"link without number", "https://risknexus.local/", ""},
+		{"number with colon is not a link", "INV:000123", "INV:000123"},
+		{"empty", "   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseInventoryLabel(tt.code))
+		})
+	}
+}
+
+func TestInventoryScanResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected *string
+		scanned  *string
+		want     string
+	}{
+		{"same location", stringPtr("Office 1"), stringPtr("Office 1"), dto.InventoryResultFound},
+		{"location differs in case and spacing", stringPtr(" office 1 "), stringPtr("Office 1"), dto.InventoryResultFound},
+		{"other location", stringPtr("Office 1"), stringPtr("Office 2"), dto.InventoryResultMoved},
+		{"scan without location", stringPtr("Office 1"), nil, dto.InventoryResultFound},
+		{"scan with blank location", stringPtr("Office 1"), stringPtr(""), dto.InventoryResultFound},
+		{"asset without recorded location", nil, stringPtr("Office 2"), dto.InventoryResultFound},
+		{"asset with blank recorded location", stringPtr(" "), stringPtr("Office 2"), dto.InventoryResultFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, inventoryScanResult(tt.expected, tt.scanned))
+		})
+	}
+}
+
+func TestInventoryResolutions(t *testing.T) {
+	assetID := "asset-1"
+
+	tests := []struct {
+		name string
+		item repo.InventoryItem
+		want []string
+	}{
+		{"missing", repo.InventoryItem{Result: dto.InventoryResultMissing, AssetID: &assetID},
+			[]string{dto.InventoryResolutionConfirmedMissing, dto.InventoryResolutionFound}},
+		{"moved", repo.InventoryItem{Result: dto.InventoryResultMoved, AssetID: &assetID},
+			[]string{dto.InventoryResolutionLocationUpdated, dto.InventoryResolutionReturned}},
+		{"unexpected known asset", repo.InventoryItem{Result: dto.InventoryResultUnexpected, AssetID: &assetID},
+			[]string{dto.InventoryResolutionLocationUpdated, dto.InventoryResolutionReturned, dto.InventoryResolutionIgnored}},
+		{"unknown number", repo.InventoryItem{Result: dto.InventoryResultUnexpected},
+			[]string{dto.InventoryResolutionIgnored}},
+		{"found is not a discrepancy", repo.InventoryItem{Result: dto.InventoryResultFound, AssetID: &assetID}, nil},
+		{"pending is not a discrepancy", repo.InventoryItem{Result: dto.InventoryResultPending, AssetID: &assetID}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, inventoryResolutions(&tt.item))
+		})
+	}
+}
+
+func TestIsInventoryAuditor(t *testing.T) {
+	campaign := &repo.InventoryCampaign{AuditorIDs: []string{"user-1", "user-2"}}
+
+	assert.True(t, isInventoryAuditor(campaign, "user-2"))
+	assert.False(t, isInventoryAuditor(campaign, "user-3"))
+	assert.False(t, isInventoryAuditor(&repo.InventoryCampaign{}, "user-1"))
+}
+
+func TestUniqueTrimmed(t *testing.T) {
+	assert.Equal(t, []string{"Office 1", "Office 2"}, uniqueTrimmed([]string{" Office 1", "Office 2", "", "Office 1 ", "  "}))
+	assert.Equal(t, []string{}, uniqueTrimmed(nil))
+}
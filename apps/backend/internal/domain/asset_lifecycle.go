@@ -0,0 +1,533 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+// assetActTemplateTypes maps lifecycle operations to the document template type of their act
+var assetActTemplateTypes = map[string]string{
+	dto.AssetLifecycleTransfer:       dto.DocumentTypeTransferAct,
+	dto.AssetLifecycleRepair:         dto.DocumentTypeRepairLog,
+	dto.AssetLifecycleRepairComplete: dto.DocumentTypeRepairLog,
+	dto.AssetLifecycleWriteoff:       dto.DocumentTypeWriteoffAct,
+}
+
+// assetActTitles names the acts by document type; it also tells generated acts apart from other asset documents
+var assetActTitles = map[string]string{
+	dto.DocumentTypeTransferAct: "Акт приема-передачи",
+	dto.DocumentTypeWriteoffAct: "Акт о списании",
+	dto.DocumentTypeRepairLog:   "Акт ремонта",
+}
+
+var assetLifecycleActTitles = map[string]string{
+	dto.AssetLifecycleTransfer:       "Акт приема-передачи",
+	dto.AssetLifecycleRepair:         "Акт передачи в ремонт",
+	dto.AssetLifecycleRepairComplete: "Акт возврата из ремонта",
+	dto.AssetLifecycleWriteoff:       "Акт о списании",
+}
+
+var assetStatusLabels = map[string]string{
+	dto.AssetStatusActive:         "В эксплуатации",
+	dto.AssetStatusInRepair:       "В ремонте",
+	dto.AssetStatusStorage:        "На складе",
+	dto.AssetStatusDecommissioned: "Списан",
+}
+
+var assetWriteoffMethodLabels = map[string]string{
+	"disposal":  "Утилизация",
+	"sale":      "Продажа",
+	"donation":  "Безвозмездная передача",
+	"recycling": "Передача на переработку",
+	"other":     "Иное",
+}
+
+func isAssetActCategory(category string) bool {
+	_, ok := assetActTitles[category]
+	return ok
+}
+
+// SetActGenerator enables generating transfer, repair and write-off acts from document templates
+func (s *AssetService) SetActGenerator(actGenerator AssetActGeneratorInterface) {
+	s.actGenerator = actGenerator
+}
+
+// Lifecycle requests
+
+// RequestLifecycleOperation records a transfer, repair or write-off of the asset for approval.
+// The asset itself is not changed until the operation is approved.
+func (s *AssetService) RequestLifecycleOperation(ctx context.Context, assetID, tenantID string, req dto.AssetLifecycleOperationRequest, requestedBy string) (*dto.AssetLifecycleOperationResponse, error) {
+	asset, err := s.getTenantAsset(ctx, assetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.assetRepo.HasPendingLifecycleOperation(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrAssetLifecyclePendingExists
+	}
+
+	op, err := planLifecycleOperation(asset, req)
+	if err != nil {
+		return nil, err
+	}
+	op.RequestedBy = requestedBy
+	if err := s.validateLifecycleUser(ctx, tenantID, "to_owner_id", op.ToOwnerID); err != nil {
+		return nil, err
+	}
+	if err := s.validateLifecycleUser(ctx, tenantID, "to_responsible_user_id", op.ToResponsibleUserID); err != nil {
+		return nil, err
+	}
+	if op.TemplateID != nil && s.actGenerator != nil {
+		templateType := assetActTemplateTypes[op.OperationType]
+		templates, err := s.actGenerator.ListTemplates(ctx, tenantID, map[string]interface{}{"template_type": templateType, "is_active": true})
+		if err != nil {
+			return nil, err
+		}
+		if !containsTemplate(templates, *op.TemplateID) {
+			return nil, NewValidationError("template_id", fmt.Sprintf("active %s template not found", templateType))
+		}
+	}
+
+	if err := s.assetRepo.CreateLifecycleOperation(ctx, op); err != nil {
+		log.Printf("ERROR: asset_service.RequestLifecycleOperation repo.CreateLifecycleOperation: %v", err)
+		return nil, err
+	}
+	op.AssetName = asset.Name
+	op.InventoryNumber = asset.InventoryNumber
+	response := lifecycleOperationResponse(*op)
+	return &response, nil
+}
+
+// planLifecycleOperation builds the operation from the request and the current asset state.
+// Target fields equal to the current values are dropped, so only real changes are applied.
+func planLifecycleOperation(asset *repo.Asset, req dto.AssetLifecycleOperationRequest) (*repo.AssetLifecycleOperation, error) {
+	if err := checkLifecycleTransition(req.OperationType, asset.Status); err != nil {
+		return nil, err
+	}
+
+	op := &repo.AssetLifecycleOperation{
+		TenantID:              asset.TenantID,
+		AssetID:               asset.ID,
+		OperationType:         req.OperationType,
+		Status:                dto.AssetLifecycleStatusPending,
+		Reason:                strings.TrimSpace(req.Reason),
+		FromStatus:            asset.Status,
+		FromOwnerID:           asset.OwnerID,
+		FromResponsibleUserID: asset.ResponsibleUserID,
+		FromLocation:          asset.Location,
+		ToLocation:            changedValue(asset.Location, trimmedPtr(req.ToLocation)),
+		Details:               req.AssetLifecycleDetails,
+		TemplateID:            req.TemplateID,
+	}
+	if req.OperationType != dto.AssetLifecycleTransfer && (req.ToOwnerID != nil || req.ToResponsibleUserID != nil) {
+		return nil, NewValidationError("to_owner_id", "only a transfer changes the owner or responsible user")
+	}
+
+	switch req.OperationType {
+	case dto.AssetLifecycleTransfer:
+		op.ToOwnerID = changedValue(asset.OwnerID, req.ToOwnerID)
+		op.ToResponsibleUserID = changedValue(asset.ResponsibleUserID, req.ToResponsibleUserID)
+		if req.ToStatus != nil && *req.ToStatus != asset.Status {
+			op.ToStatus = req.ToStatus
+		}
+		if op.ToOwnerID == nil && op.ToResponsibleUserID == nil && op.ToLocation == nil {
+			return nil, NewValidationError("to_owner_id", "transfer must change the owner, responsible user or location")
+		}
+	case dto.AssetLifecycleRepair:
+		op.ToStatus = stringPtr(dto.AssetStatusInRepair)
+	case dto.AssetLifecycleRepairComplete:
+		op.ToStatus = stringPtr(dto.AssetStatusActive)
+		if req.ToStatus != nil {
+			op.ToStatus = req.ToStatus
+		}
+	case dto.AssetLifecycleWriteoff:
+		if op.Details.WriteoffMethod == "" {
+			return nil, NewValidationError("writeoff_method", "write-off method is required")
+		}
+		if len(op.Details.Commission) == 0 {
+			return nil, NewValidationError("commission", "write-off requires a commission")
+		}
+		op.ToStatus = stringPtr(dto.AssetStatusDecommissioned)
+	}
+	if req.OperationType != dto.AssetLifecycleTransfer && req.OperationType != dto.AssetLifecycleRepairComplete && req.ToStatus != nil {
+		return nil, NewValidationError("to_status", "target status is set by the operation type")
+	}
+	return op, nil
+}
+
+// checkLifecycleTransition reports whether the operation is allowed for an asset in the given status
+func checkLifecycleTransition(operationType, status string) error {
+	switch operationType {
+	case dto.AssetLifecycleTransfer:
+		if status == dto.AssetStatusInRepair || status == dto.AssetStatusDecommissioned {
+			return NewValidationError("operation_type", fmt.Sprintf("asset in status %s cannot be transferred", status))
+		}
+	case dto.AssetLifecycleRepair:
+		if status != dto.AssetStatusActive && status != dto.AssetStatusStorage {
+			return NewValidationError("operation_type", fmt.Sprintf("asset in status %s cannot be sent to repair", status))
+		}
+	case dto.AssetLifecycleRepairComplete:
+		if status != dto.AssetStatusInRepair {
+			return NewValidationError("operation_type", "asset is not in repair")
+		}
+	case dto.AssetLifecycleWriteoff:
+		if status == dto.AssetStatusDecommissioned {
+			return NewValidationError("operation_type", "asset is already decommissioned")
+		}
+	default:
+		return NewValidationError("operation_type", "unknown lifecycle operation")
+	}
+	return nil
+}
+
+func (s *AssetService) validateLifecycleUser(ctx context.Context, tenantID, field string, userID *string) error {
+	if userID == nil {
+		return nil
+	}
+	user, err := s.userRepo.GetByID(ctx, *userID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.TenantID != tenantID || !user.IsActive {
+		return NewValidationError(field, "user not found")
+	}
+	return nil
+}
+
+// Lifecycle decisions
+
+// ApproveLifecycleOperation applies a pending operation to the asset and generates its act.
+// The requester cannot approve their own operation. An act that could not be generated does not undo
+// the operation: the error is returned in act_error and the act can be generated again later.
+func (s *AssetService) ApproveLifecycleOperation(ctx context.Context, assetID, operationID, tenantID string, req dto.AssetLifecycleDecisionRequest, approverID string) (*dto.AssetLifecycleOperationResponse, error) {
+	op, err := s.getLifecycleOperation(ctx, assetID, operationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Status != dto.AssetLifecycleStatusPending {
+		return nil, ErrAssetLifecycleOperationNotPending
+	}
+	if op.RequestedBy == approverID {
+		return nil, ErrAssetLifecycleSelfApproval
+	}
+	asset, err := s.getTenantAsset(ctx, assetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLifecycleTransition(op.OperationType, asset.Status); err != nil {
+		return nil, err
+	}
+
+	applied, err := s.assetRepo.ApplyLifecycleOperation(ctx, op, approverID, req.Comment)
+	if err != nil {
+		log.Printf("ERROR: asset_service.ApproveLifecycleOperation repo.ApplyLifecycleOperation: %v", err)
+		return nil, err
+	}
+	if !applied {
+		return nil, ErrAssetLifecycleOperationNotPending
+	}
+
+	actErr := s.generateLifecycleAct(ctx, op, req.GeneratePDF, approverID)
+	if actErr != nil {
+		log.Printf("WARN: asset_service.ApproveLifecycleOperation act for operation %s: %v", op.ID, actErr)
+	}
+	response := lifecycleOperationResponse(*op)
+	if actErr != nil {
+		response.ActError = actErr.Error()
+	}
+	return &response, nil
+}
+
+// RejectLifecycleOperation closes a pending operation without changing the asset
+func (s *AssetService) RejectLifecycleOperation(ctx context.Context, assetID, operationID, tenantID string, req dto.AssetLifecycleDecisionRequest, approverID string) (*dto.AssetLifecycleOperationResponse, error) {
+	return s.closeLifecycleOperation(ctx, assetID, operationID, tenantID, dto.AssetLifecycleStatusRejected, req.Comment, approverID)
+}
+
+// CancelLifecycleOperation withdraws a pending operation; only the requester can cancel it
+func (s *AssetService) CancelLifecycleOperation(ctx context.Context, assetID, operationID, tenantID, userID string) (*dto.AssetLifecycleOperationResponse, error) {
+	op, err := s.getLifecycleOperation(ctx, assetID, operationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if op.RequestedBy != userID {
+		return nil, ErrAssetLifecycleNotRequester
+	}
+	return s.closeLifecycleOperation(ctx, assetID, operationID, tenantID, dto.AssetLifecycleStatusCancelled, nil, userID)
+}
+
+func (s *AssetService) closeLifecycleOperation(ctx context.Context, assetID, operationID, tenantID, status string, comment *string, decidedBy string) (*dto.AssetLifecycleOperationResponse, error) {
+	op, err := s.getLifecycleOperation(ctx, assetID, operationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Status != dto.AssetLifecycleStatusPending {
+		return nil, ErrAssetLifecycleOperationNotPending
+	}
+	closed, err := s.assetRepo.DecideLifecycleOperation(ctx, operationID, tenantID, status, decidedBy, comment)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return nil, ErrAssetLifecycleOperationNotPending
+	}
+	return s.GetLifecycleOperation(ctx, assetID, operationID, tenantID)
+}
+
+// Lifecycle acts
+
+// GenerateLifecycleAct generates the act of an approved operation again, e.g. after the template was fixed
+func (s *AssetService) GenerateLifecycleAct(ctx context.Context, assetID, operationID, tenantID string, generatePDF bool, userID string) (*dto.AssetLifecycleOperationResponse, error) {
+	op, err := s.getLifecycleOperation(ctx, assetID, operationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Status != dto.AssetLifecycleStatusApproved {
+		return nil, NewValidationError("status", "act is generated only for approved operations")
+	}
+	if err := s.generateLifecycleAct(ctx, op, generatePDF, userID); err != nil {
+		return nil, err
+	}
+	response := lifecycleOperationResponse(*op)
+	return &response, nil
+}
+
+// generateLifecycleAct fills the operation template (or the tenant's active template of the act type,
+// custom templates before system ones) and saves the act in the document storage linked to the asset
+func (s *AssetService) generateLifecycleAct(ctx context.Context, op *repo.AssetLifecycleOperation, generatePDF bool, userID string) error {
+	if s.actGenerator == nil {
+		return errors.New("document templates are not configured")
+	}
+
+	templateType := assetActTemplateTypes[op.OperationType]
+	templateID := ""
+	if op.TemplateID != nil {
+		templateID = *op.TemplateID
+	} else {
+		templates, err := s.actGenerator.ListTemplates(ctx, op.TenantID, map[string]interface{}{"template_type": templateType, "is_active": true})
+		if err != nil {
+			return err
+		}
+		for _, t := range templates {
+			if templateID == "" || !t.IsSystem {
+				templateID = t.ID
+			}
+			if !t.IsSystem {
+				break
+			}
+		}
+		if templateID == "" {
+			return NewValidationError("template_id", fmt.Sprintf("no active %s template", templateType))
+		}
+	}
+
+	title := fmt.Sprintf("%s № %s — %s (%s)", assetLifecycleActTitles[op.OperationType], safeString(op.ActNumber), op.AssetName, op.InventoryNumber)
+	result, err := s.actGenerator.FillTemplate(ctx, op.TenantID, userID, dto.FillTemplateRequest{
+		TemplateID:     templateID,
+		AssetID:        op.AssetID,
+		AdditionalData: s.lifecycleActData(ctx, op),
+		SaveAsDocument: true,
+		DocumentTitle:  title,
+		GeneratePDF:    generatePDF,
+	})
+	if err != nil {
+		return err
+	}
+	if result.DocumentID == nil {
+		return errors.New("act document was not saved")
+	}
+
+	if err := s.assetRepo.SetLifecycleDocument(ctx, op.ID, op.TenantID, *result.DocumentID); err != nil {
+		return err
+	}
+	op.DocumentID = result.DocumentID
+	if err := s.assetRepo.AddHistory(ctx, op.AssetID, "document_generated", "", title, userID); err != nil {
+		log.Printf("WARN: asset_service.generateLifecycleAct AddHistory: %v", err)
+	}
+	return nil
+}
+
+// lifecycleActData returns the act placeholders; text entered by users is HTML-escaped
+// as the template is rendered by plain substitution
+func (s *AssetService) lifecycleActData(ctx context.Context, op *repo.AssetLifecycleOperation) map[string]interface{} {
+	fromOwner := s.lifecycleUserName(ctx, op.FromOwnerID)
+	fromResponsible := s.lifecycleUserName(ctx, op.FromResponsibleUserID)
+	fromLocation := html.EscapeString(safeString(op.FromLocation))
+	data := map[string]interface{}{
+		"act_title":             assetLifecycleActTitles[op.OperationType],
+		"act_number":            safeString(op.ActNumber),
+		"operation_reason":      html.EscapeString(op.Reason),
+		"from_owner_name":       fromOwner,
+		"to_owner_name":         fromOwner,
+		"from_responsible_name": fromResponsible,
+		"to_responsible_name":   fromResponsible,
+		"from_location":         fromLocation,
+		"to_location":           fromLocation,
+		"from_status":           assetStatusLabel(op.FromStatus),
+		"to_status":             assetStatusLabel(op.FromStatus),
+		"requested_by_name":     s.lifecycleUserName(ctx, &op.RequestedBy),
+		"approved_by_name":      s.lifecycleUserName(ctx, op.ApproverUserID),
+		"decision_comment":      html.EscapeString(safeString(op.DecisionComment)),
+		"contractor":            html.EscapeString(op.Details.Contractor),
+		"repair_result":         html.EscapeString(op.Details.RepairResult),
+		"writeoff_method":       assetWriteoffMethodLabels[op.Details.WriteoffMethod],
+		"commission":            html.EscapeString(strings.Join(op.Details.Commission, ", ")),
+		"expected_return_date":  "",
+		"act_date":              "",
+	}
+	if op.ToOwnerID != nil {
+		data["to_owner_name"] = s.lifecycleUserName(ctx, op.ToOwnerID)
+	}
+	if op.ToResponsibleUserID != nil {
+		data["to_responsible_name"] = s.lifecycleUserName(ctx, op.ToResponsibleUserID)
+	}
+	if op.ToLocation != nil {
+		data["to_location"] = html.EscapeString(*op.ToLocation)
+	}
+	if op.ToStatus != nil {
+		data["to_status"] = assetStatusLabel(*op.ToStatus)
+	}
+	if date, err := time.Parse("2006-01-02", op.Details.ExpectedReturnDate); err == nil {
+		data["expected_return_date"] = date.Format("02.01.2006")
+	}
+	if op.DecidedAt != nil {
+		data["act_date"] = op.DecidedAt.Format("02.01.2006")
+	}
+	return data
+}
+
+func (s *AssetService) lifecycleUserName(ctx context.Context, userID *string) string {
+	return html.EscapeString(s.userDisplayName(ctx, userID))
+}
+
+// userDisplayName returns the full name of the user, or the email if the name is not filled in
+func (s *AssetService) userDisplayName(ctx context.Context, userID *string) string {
+	if userID == nil {
+		return ""
+	}
+	user, err := s.userRepo.GetByID(ctx, *userID)
+	if err != nil || user == nil {
+		return ""
+	}
+	name := strings.TrimSpace(safeString(user.FirstName) + " " + safeString(user.LastName))
+	if name == "" {
+		name = user.Email
+	}
+	return name
+}
+
+func assetStatusLabel(status string) string {
+	if label, ok := assetStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// Lifecycle lists
+
+// GetLifecycleOperation returns an operation of the asset
+func (s *AssetService) GetLifecycleOperation(ctx context.Context, assetID, operationID, tenantID string) (*dto.AssetLifecycleOperationResponse, error) {
+	op, err := s.getLifecycleOperation(ctx, assetID, operationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	response := lifecycleOperationResponse(*op)
+	return &response, nil
+}
+
+// ListAssetLifecycleOperations returns the operations of the asset, newest first
+func (s *AssetService) ListAssetLifecycleOperations(ctx context.Context, assetID, tenantID string) ([]dto.AssetLifecycleOperationResponse, error) {
+	if _, err := s.getTenantAsset(ctx, assetID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.ListLifecycleOperations(ctx, tenantID, map[string]interface{}{"asset_id": assetID})
+}
+
+// ListLifecycleOperations returns the tenant operations matching the filters; status=pending is the approval queue
+func (s *AssetService) ListLifecycleOperations(ctx context.Context, tenantID string, filters map[string]interface{}) ([]dto.AssetLifecycleOperationResponse, error) {
+	operations, err := s.assetRepo.ListLifecycleOperations(ctx, tenantID, filters)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.AssetLifecycleOperationResponse, 0, len(operations))
+	for _, op := range operations {
+		responses = append(responses, lifecycleOperationResponse(op))
+	}
+	return responses, nil
+}
+
+func (s *AssetService) getLifecycleOperation(ctx context.Context, assetID, operationID, tenantID string) (*repo.AssetLifecycleOperation, error) {
+	op, err := s.assetRepo.GetLifecycleOperation(ctx, operationID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil || op.AssetID != assetID {
+		return nil, ErrAssetLifecycleOperationNotFound
+	}
+	return op, nil
+}
+
+func lifecycleOperationResponse(op repo.AssetLifecycleOperation) dto.AssetLifecycleOperationResponse {
+	return dto.AssetLifecycleOperationResponse{
+		ID:                    op.ID,
+		AssetID:               op.AssetID,
+		AssetName:             op.AssetName,
+		InventoryNumber:       op.InventoryNumber,
+		OperationType:         op.OperationType,
+		Status:                op.Status,
+		Reason:                op.Reason,
+		FromStatus:            op.FromStatus,
+		ToStatus:              op.ToStatus,
+		FromOwnerID:           op.FromOwnerID,
+		ToOwnerID:             op.ToOwnerID,
+		FromResponsibleUserID: op.FromResponsibleUserID,
+		ToResponsibleUserID:   op.ToResponsibleUserID,
+		FromLocation:          op.FromLocation,
+		ToLocation:            op.ToLocation,
+		Details:               op.Details,
+		TemplateID:            op.TemplateID,
+		ActNumber:             op.ActNumber,
+		DocumentID:            op.DocumentID,
+		RequestedBy:           op.RequestedBy,
+		ApproverUserID:        op.ApproverUserID,
+		DecisionComment:       op.DecisionComment,
+		DecidedAt:             op.DecidedAt,
+		CreatedAt:             op.CreatedAt,
+		UpdatedAt:             op.UpdatedAt,
+	}
+}
+
+// changedValue returns target if it differs from the current value, nil otherwise
+func changedValue(current, target *string) *string {
+	if target == nil || safeString(current) == *target {
+		return nil
+	}
+	return target
+}
+
+func trimmedPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	return &trimmed
+}
+
+func containsTemplate(templates []dto.DocumentTemplateDTO, id string) bool {
+	for _, t := range templates {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"testing"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLifecycleTransition(t *testing.T) {
+	tests := []struct {
+		operation string
+		status    string
+		wantErr   bool
+	}{
+		{dto.AssetLifecycleTransfer, dto.AssetStatusActive, false},
+		{dto.AssetLifecycleTransfer, dto.AssetStatusStorage, false},
+		{dto.AssetLifecycleTransfer, dto.AssetStatusInRepair, true},
+		{dto.AssetLifecycleTransfer, dto.AssetStatusDecommissioned, true},
+		{dto.AssetLifecycleRepair, dto.AssetStatusActive, false},
+		{dto.AssetLifecycleRepair, dto.AssetStatusStorage, false},
+		{dto.AssetLifecycleRepair, dto.AssetStatusInRepair, true},
+		{dto.AssetLifecycleRepair, dto.AssetStatusDecommissioned, true},
+		{dto.AssetLifecycleRepairComplete, dto.AssetStatusInRepair, false},
+		{dto.AssetLifecycleRepairComplete, dto.AssetStatusActive, true},
+		{dto.AssetLifecycleWriteoff, dto.AssetStatusActive, false},
+		{dto.AssetLifecycleWriteoff, dto.AssetStatusInRepair, false},
+		{dto.AssetLifecycleWriteoff, dto.AssetStatusDecommissioned, true},
+		{"lend", dto.AssetStatusActive, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation+"_from_"+tt.status, func(t *testing.T) {
+			err := checkLifecycleTransition(tt.operation, tt.status)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "operation_type", validationErr.Field)
+		})
+	}
+}
+
+func TestPlanLifecycleOperation(t *testing.T) {
+	writeoff := dto.AssetLifecycleDetails{WriteoffMethod: "disposal", Commission: []string{"Иванов И.И.", "Петров П.П."}}
+
+	tests := []struct {
+		name            string
+		status          string
+		req             dto.AssetLifecycleOperationRequest
+		wantToStatus    *string
+		wantOwner       *string
+		wantResponsible *string
+		wantLocation    *string
+		wantField       string
+	}{
+		{
+			name:      "transfer to a new owner",
+			req:       dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleTransfer, ToOwnerID: stringPtr("user-2")},
+			wantOwner: stringPtr("user-2"),
+		},
+		{
+			name:         "move to another office and into storage",
+			req:          dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleTransfer, ToLocation: stringPtr("  Office 2 "), ToStatus: stringPtr(dto.AssetStatusStorage)},
+			wantLocation: stringPtr("Office 2"),
+			wantToStatus: stringPtr(dto.AssetStatusStorage),
+		},
+		{
+			name:            "unchanged values are dropped",
+			req:             dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleTransfer, ToOwnerID: stringPtr("user-1"), ToResponsibleUserID: stringPtr("user-3"), ToLocation: stringPtr("Office 1"), ToStatus: stringPtr(dto.AssetStatusActive)},
+			wantResponsible: stringPtr("user-3"),
+		},
+		{
+			name:      "transfer without changes",
+			req:       dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleTransfer, ToOwnerID: stringPtr("user-1"), ToLocation: stringPtr(" Office 1 ")},
+			wantField: "to_owner_id",
+		},
+		{
+			name:         "repair",
+			req:          dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleRepair, AssetLifecycleDetails: dto.AssetLifecycleDetails{Contractor: "Service center"}},
+			wantToStatus: stringPtr(dto.AssetStatusInRepair),
+		},
+		{
+			name:      "repair changing the owner",
+			req:       dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleRepair, ToOwnerID: stringPtr("user-2")},
+			wantField: "to_owner_id",
+		},
+		{
+			name:      "repair with target status",
+			req:       dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleRepair, ToStatus: stringPtr(dto.AssetStatusStorage)},
+			wantField: "to_status",
+		},
+		{
+			name:         "return from repair",
+			status:       dto.AssetStatusInRepair,
+			req:          dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleRepairComplete},
+			wantToStatus: stringPtr(dto.AssetStatusActive),
+		},
+		{
+			name:         "return from repair into storage",
+			status:       dto.AssetStatusInRepair,
+			req:          dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleRepairComplete, ToStatus: stringPtr(dto.AssetStatusStorage)},
+			wantToStatus: stringPtr(dto.AssetStatusStorage),
+		},
+		{
+			name:         "write-off",
+			req:          dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleWriteoff, AssetLifecycleDetails: writeoff},
+			wantToStatus: stringPtr(dto.AssetStatusDecommissioned),
+		},
+		{
+			name:      "write-off without method",
+			req:       dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleWriteoff, AssetLifecycleDetails: dto.AssetLifecycleDetails{Commission: writeoff.Commission}},
+			wantField: "writeoff_method",
+		},
+		{
+			name:      "write-off without commission",
+			req:       dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleWriteoff, AssetLifecycleDetails: dto.AssetLifecycleDetails{WriteoffMethod: "sale"}},
+			wantField: "commission",
+		},
+		{
+			name:      "write-off of a decommissioned asset",
+			status:    dto.AssetStatusDecommissioned,
+			req:       dto.AssetLifecycleOperationRequest{OperationType: dto.AssetLifecycleWriteoff, AssetLifecycleDetails: writeoff},
+			wantField: "operation_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset := &repo.Asset{
+				ID:                "asset-1",
+				TenantID:          "tenant-1",
+				Status:            dto.AssetStatusActive,
+				OwnerID:           stringPtr("user-1"),
+				ResponsibleUserID: stringPtr("user-1"),
+				Location:          stringPtr("Office 1"),
+			}
+			if tt.status != "" {
+				asset.Status = tt.status
+			}
+			tt.req.Reason = "  Плановая замена  "
+
+			op, err := planLifecycleOperation(asset, tt.req)
+			if tt.wantField != "" {
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, dto.AssetLifecycleStatusPending, op.Status)
+			assert.Equal(t, "Плановая замена", op.Reason)
+			assert.Equal(t, asset.Status, op.FromStatus, "the current state is recorded for the act")
+			assert.Equal(t, asset.OwnerID, op.FromOwnerID)
+			assert.Equal(t, asset.Location, op.FromLocation)
+			assert.Equal(t, tt.wantToStatus, op.ToStatus)
+			assert.Equal(t, tt.wantOwner, op.ToOwnerID)
+			assert.Equal(t, tt.wantResponsible, op.ToResponsibleUserID)
+			assert.Equal(t, tt.wantLocation, op.ToLocation)
+		})
+	}
+}
+
+func TestChangedValue(t *testing.T) {
+	assert.Nil(t, changedValue(stringPtr("Office 1"), nil))
+	assert.Nil(t, changedValue(stringPtr("Office 1"), stringPtr("Office 1")))
+	assert.Equal(t, "Office 2", *changedValue(stringPtr("Office 1"), stringPtr("Office 2")))
+	assert.Equal(t, "Office 2", *changedValue(nil, stringPtr("Office 2")))
+	assert.Nil(t, changedValue(nil, stringPtr("")), "clearing an empty value is not a change")
+}
@@ -0,0 +1,482 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+const (
+	defaultAssetGraphDepth = 2
+	maxAssetGraphDepth     = 5
+)
+
+// assetRatingLevels orders CIA ratings; unknown values rank below low
+var assetRatingLevels = map[string]int{
+	dto.CriticalityLow:    1,
+	dto.CriticalityMedium: 2,
+	dto.CriticalityHigh:   3,
+}
+
+// assetRatingAttributes are propagated along dependencies in this order
+var assetRatingAttributes = [4]string{"criticality", "confidentiality", "integrity", "availability"}
+
+// assetRating is an effective rating and the asset it originates from
+type assetRating struct {
+	level   string
+	assetID string
+}
+
+// assetGraph is the tenant dependency map; edges are indexed by both ends
+type assetGraph struct {
+	nodes    map[string]repo.Asset
+	edges    []repo.AssetRelationship
+	bySource map[string][]int
+	byTarget map[string][]int
+
+	effective map[string][4]assetRating
+}
+
+// relationshipEnds returns the provider and consumer of a relationship. The provider is the asset whose failure
+// affects the consumer and which inherits the consumer's ratings: an application runs_on (consumes) its server,
+// a service depends_on (consumes) another, a database stores_data_of (provides for) an information asset.
+// connected_to is informational and has neither.
+func relationshipEnds(rel repo.AssetRelationship) (string, string, bool) {
+	switch rel.RelationshipType {
+	case dto.AssetRelationRunsOn, dto.AssetRelationDependsOn:
+		return rel.TargetAssetID, rel.SourceAssetID, true
+	case dto.AssetRelationStoresDataOf:
+		return rel.SourceAssetID, rel.TargetAssetID, true
+	default:
+		return "", "", false
+	}
+}
+
+func (s *AssetService) loadAssetGraph(ctx context.Context, tenantID string) (*assetGraph, error) {
+	assets, err := s.assetRepo.ListRelationshipNodes(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	relationships, err := s.assetRepo.ListRelationships(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return newAssetGraph(assets, relationships), nil
+}
+
+func newAssetGraph(assets []repo.Asset, relationships []repo.AssetRelationship) *assetGraph {
+	graph := &assetGraph{
+		nodes:     make(map[string]repo.Asset, len(assets)),
+		bySource:  make(map[string][]int),
+		byTarget:  make(map[string][]int),
+		effective: make(map[string][4]assetRating),
+	}
+	for _, asset := range assets {
+		graph.nodes[asset.ID] = asset
+	}
+	for _, rel := range relationships {
+		graph.addEdge(rel)
+	}
+	return graph
+}
+
+// addEdge indexes a relationship and drops the effective ratings computed so far
+func (g *assetGraph) addEdge(rel repo.AssetRelationship) {
+	g.edges = append(g.edges, rel)
+	g.bySource[rel.SourceAssetID] = append(g.bySource[rel.SourceAssetID], len(g.edges)-1)
+	g.byTarget[rel.TargetAssetID] = append(g.byTarget[rel.TargetAssetID], len(g.edges)-1)
+	g.effective = make(map[string][4]assetRating)
+}
+
+// edgesOf returns the indexes of all relationships of the asset
+func (g *assetGraph) edgesOf(assetID string) []int {
+	return append(append([]int{}, g.bySource[assetID]...), g.byTarget[assetID]...)
+}
+
+// consumers returns the relationships in which the asset is the provider
+func (g *assetGraph) consumers(assetID string) []repo.AssetRelationship {
+	var result []repo.AssetRelationship
+	for _, i := range g.edgesOf(assetID) {
+		if provider, _, ok := relationshipEnds(g.edges[i]); ok && provider == assetID {
+			result = append(result, g.edges[i])
+		}
+	}
+	return result
+}
+
+// reliesOn reports whether consumer transitively relies on provider
+func (g *assetGraph) reliesOn(consumer, provider string) bool {
+	visited := map[string]bool{consumer: true}
+	queue := []string{consumer}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, i := range g.edgesOf(current) {
+			next, from, ok := relationshipEnds(g.edges[i])
+			if !ok || from != current || visited[next] {
+				continue
+			}
+			if next == provider {
+				return true
+			}
+			visited[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return false
+}
+
+// effectiveRatings raises the asset's own ratings to the highest ratings of everything that relies on it
+func (g *assetGraph) effectiveRatings(assetID string, visiting map[string]bool) [4]assetRating {
+	if ratings, ok := g.effective[assetID]; ok {
+		return ratings
+	}
+
+	asset := g.nodes[assetID]
+	ratings := [4]assetRating{
+		{asset.Criticality, assetID},
+		{asset.Confidentiality, assetID},
+		{asset.Integrity, assetID},
+		{asset.Availability, assetID},
+	}
+
+	visiting[assetID] = true
+	for _, rel := range g.consumers(assetID) {
+		_, consumer, _ := relationshipEnds(rel)
+		if visiting[consumer] {
+			continue
+		}
+		inherited := g.effectiveRatings(consumer, visiting)
+		for i := range ratings {
+			if assetRatingLevels[inherited[i].level] > assetRatingLevels[ratings[i].level] {
+				ratings[i] = inherited[i]
+			}
+		}
+	}
+	delete(visiting, assetID)
+
+	g.effective[assetID] = ratings
+	return ratings
+}
+
+func (g *assetGraph) node(assetID string) dto.AssetGraphNode {
+	asset := g.nodes[assetID]
+	node := assetGraphNode(asset)
+
+	ratings := g.effectiveRatings(assetID, make(map[string]bool))
+	effective := &dto.AssetEffectiveCIA{
+		Criticality:     ratings[0].level,
+		Confidentiality: ratings[1].level,
+		Integrity:       ratings[2].level,
+		Availability:    ratings[3].level,
+		InheritedFrom:   []dto.AssetRatingSource{},
+	}
+	for i, rating := range ratings {
+		if rating.assetID == assetID {
+			continue
+		}
+		source := g.nodes[rating.assetID]
+		effective.Raised = true
+		effective.InheritedFrom = append(effective.InheritedFrom, dto.AssetRatingSource{
+			Attribute:       assetRatingAttributes[i],
+			Level:           rating.level,
+			AssetID:         source.ID,
+			InventoryNumber: source.InventoryNumber,
+			Name:            source.Name,
+		})
+	}
+	node.Effective = effective
+	return node
+}
+
+func assetGraphNode(asset repo.Asset) dto.AssetGraphNode {
+	return dto.AssetGraphNode{
+		ID:              asset.ID,
+		InventoryNumber: asset.InventoryNumber,
+		Name:            asset.Name,
+		Type:            asset.Type,
+		Status:          asset.Status,
+		Criticality:     asset.Criticality,
+		Confidentiality: asset.Confidentiality,
+		Integrity:       asset.Integrity,
+		Availability:    asset.Availability,
+	}
+}
+
+// Asset relationships
+
+// getTenantAsset returns the asset if it belongs to the tenant, ErrAssetNotFound otherwise
+func (s *AssetService) getTenantAsset(ctx context.Context, assetID, tenantID string) (*repo.Asset, error) {
+	asset, err := s.assetRepo.GetByID(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset == nil || asset.TenantID != tenantID {
+		return nil, ErrAssetNotFound
+	}
+	return asset, nil
+}
+
+// loadAssetGraphFor loads the tenant graph and makes sure the asset is a node even without relationships
+func (s *AssetService) loadAssetGraphFor(ctx context.Context, assetID, tenantID string) (*assetGraph, error) {
+	asset, err := s.getTenantAsset(ctx, assetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := s.loadAssetGraph(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := graph.nodes[asset.ID]; !ok {
+		graph.nodes[asset.ID] = *asset
+	}
+	return graph, nil
+}
+
+func (s *AssetService) ListAssetRelationships(ctx context.Context, assetID, tenantID string) ([]dto.AssetRelationshipResponse, error) {
+	graph, err := s.loadAssetGraphFor(ctx, assetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.AssetRelationshipResponse, 0)
+	for _, i := range graph.edgesOf(assetID) {
+		responses = append(responses, graph.relationshipResponse(graph.edges[i], assetID))
+	}
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].CreatedAt.Before(responses[j].CreatedAt)
+	})
+	return responses, nil
+}
+
+func (g *assetGraph) relationshipResponse(rel repo.AssetRelationship, assetID string) dto.AssetRelationshipResponse {
+	response := dto.AssetRelationshipResponse{
+		ID:               rel.ID,
+		RelationshipType: rel.RelationshipType,
+		Direction:        dto.AssetRelationOutgoing,
+		Description:      rel.Description,
+		CreatedBy:        rel.CreatedBy,
+		CreatedAt:        rel.CreatedAt,
+	}
+	related := rel.TargetAssetID
+	if rel.TargetAssetID == assetID {
+		response.Direction = dto.AssetRelationIncoming
+		related = rel.SourceAssetID
+	}
+	response.RelatedAsset = g.node(related)
+	return response
+}
+
+// CreateAssetRelationship links the asset (source) to the target asset. Duplicate links, including reversed
+// connected_to, and links that would make an asset transitively rely on itself are rejected.
+func (s *AssetService) CreateAssetRelationship(ctx context.Context, assetID, tenantID string, req dto.AssetRelationshipRequest, createdBy string) (*dto.AssetRelationshipResponse, error) {
+	log.Printf("DEBUG: asset_service.CreateAssetRelationship asset=%s target=%s type=%s", assetID, req.TargetAssetID, req.RelationshipType)
+
+	if req.TargetAssetID == assetID {
+		return nil, NewValidationError("target_asset_id", "asset cannot be related to itself")
+	}
+	target, err := s.getTenantAsset(ctx, req.TargetAssetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := s.loadAssetGraphFor(ctx, assetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := graph.nodes[target.ID]; !ok {
+		graph.nodes[target.ID] = *target
+	}
+	source := graph.nodes[assetID]
+
+	for _, i := range graph.bySource[assetID] {
+		existing := graph.edges[i]
+		if existing.TargetAssetID == target.ID && existing.RelationshipType == req.RelationshipType {
+			return nil, ErrAssetRelationshipExists
+		}
+	}
+	if req.RelationshipType == dto.AssetRelationConnectedTo {
+		for _, i := range graph.byTarget[assetID] {
+			existing := graph.edges[i]
+			if existing.SourceAssetID == target.ID && existing.RelationshipType == dto.AssetRelationConnectedTo {
+				return nil, ErrAssetRelationshipExists
+			}
+		}
+	}
+
+	rel := repo.AssetRelationship{
+		TenantID:         tenantID,
+		SourceAssetID:    assetID,
+		TargetAssetID:    target.ID,
+		RelationshipType: req.RelationshipType,
+		Description:      req.Description,
+		CreatedBy:        &createdBy,
+	}
+	if provider, consumer, ok := relationshipEnds(rel); ok && graph.reliesOn(provider, consumer) {
+		return nil, ErrAssetRelationshipCycle
+	}
+
+	if err := s.assetRepo.CreateRelationship(ctx, &rel); err != nil {
+		log.Printf("ERROR: asset_service.CreateAssetRelationship CreateRelationship: %v", err)
+		return nil, err
+	}
+
+	label := relationshipLabel(rel, source, *target)
+	for _, id := range []string{rel.SourceAssetID, rel.TargetAssetID} {
+		if err := s.assetRepo.AddHistory(ctx, id, "relationship_added", "", label, createdBy); err != nil {
+			log.Printf("WARN: asset_service.CreateAssetRelationship AddHistory: %v", err)
+		}
+	}
+
+	// Recompute effective ratings with the new relationship
+	graph.addEdge(rel)
+
+	response := graph.relationshipResponse(rel, assetID)
+	return &response, nil
+}
+
+func (s *AssetService) DeleteAssetRelationship(ctx context.Context, assetID, relationshipID, tenantID, deletedBy string) error {
+	log.Printf("DEBUG: asset_service.DeleteAssetRelationship asset=%s relationship=%s", assetID, relationshipID)
+
+	rel, err := s.assetRepo.GetRelationship(ctx, relationshipID, tenantID)
+	if err != nil {
+		return err
+	}
+	if rel == nil || (rel.SourceAssetID != assetID && rel.TargetAssetID != assetID) {
+		return ErrAssetRelationshipNotFound
+	}
+
+	if err := s.assetRepo.DeleteRelationship(ctx, rel.ID, tenantID); err != nil {
+		log.Printf("ERROR: asset_service.DeleteAssetRelationship DeleteRelationship: %v", err)
+		return err
+	}
+
+	source, err := s.assetRepo.GetByID(ctx, rel.SourceAssetID)
+	if err != nil || source == nil {
+		return err
+	}
+	target, err := s.assetRepo.GetByID(ctx, rel.TargetAssetID)
+	if err != nil || target == nil {
+		return err
+	}
+	label := relationshipLabel(*rel, *source, *target)
+	for _, id := range []string{rel.SourceAssetID, rel.TargetAssetID} {
+		if err := s.assetRepo.AddHistory(ctx, id, "relationship_removed", label, "", deletedBy); err != nil {
+			log.Printf("WARN: asset_service.DeleteAssetRelationship AddHistory: %v", err)
+		}
+	}
+	return nil
+}
+
+// relationshipLabel formats a relationship as "INV-1 App runs_on INV-2 Server" for asset history
+func relationshipLabel(rel repo.AssetRelationship, source, target repo.Asset) string {
+	return fmt.Sprintf("%s %s %s %s %s", source.InventoryNumber, source.Name, rel.RelationshipType, target.InventoryNumber, target.Name)
+}
+
+// Dependency map and impact analysis
+
+// GetAssetGraph returns the assets reachable from the asset over relationships of any type and direction,
+// up to depth hops, with the effective ratings of every node
+func (s *AssetService) GetAssetGraph(ctx context.Context, assetID, tenantID string, depth int) (*dto.AssetGraphResponse, error) {
+	if depth <= 0 {
+		depth = defaultAssetGraphDepth
+	}
+	if depth > maxAssetGraphDepth {
+		depth = maxAssetGraphDepth
+	}
+
+	graph, err := s.loadAssetGraphFor(ctx, assetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AssetGraphResponse{
+		RootAssetID: assetID,
+		Depth:       depth,
+		Nodes:       []dto.AssetGraphNode{graph.node(assetID)},
+		Edges:       []dto.AssetGraphEdge{},
+	}
+	distance := map[string]int{assetID: 0}
+	includedEdges := make(map[int]bool)
+	queue := []string{assetID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if distance[current] == depth {
+			continue
+		}
+		for _, i := range graph.edgesOf(current) {
+			rel := graph.edges[i]
+			next := rel.TargetAssetID
+			if next == current {
+				next = rel.SourceAssetID
+			}
+			if !includedEdges[i] {
+				includedEdges[i] = true
+				response.Edges = append(response.Edges, dto.AssetGraphEdge{
+					ID:               rel.ID,
+					SourceAssetID:    rel.SourceAssetID,
+					TargetAssetID:    rel.TargetAssetID,
+					RelationshipType: rel.RelationshipType,
+				})
+			}
+			if _, seen := distance[next]; seen {
+				continue
+			}
+			distance[next] = distance[current] + 1
+			response.Nodes = append(response.Nodes, graph.node(next))
+			queue = append(queue, next)
+		}
+	}
+	return response, nil
+}
+
+// GetAssetImpact lists every asset that transitively relies on the asset and is affected when it fails.
+// connected_to relationships are not followed.
+func (s *AssetService) GetAssetImpact(ctx context.Context, assetID, tenantID string) (*dto.AssetImpactResponse, error) {
+	graph, err := s.loadAssetGraphFor(ctx, assetID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AssetImpactResponse{
+		Asset:         graph.node(assetID),
+		Affected:      []dto.AssetImpactEntry{},
+		ByCriticality: make(map[string]int),
+	}
+	visited := map[string]bool{assetID: true}
+	depth := map[string]int{assetID: 0}
+	queue := []string{assetID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, rel := range graph.consumers(current) {
+			_, consumer, _ := relationshipEnds(rel)
+			if visited[consumer] {
+				continue
+			}
+			visited[consumer] = true
+			depth[consumer] = depth[current] + 1
+			queue = append(queue, consumer)
+
+			entry := dto.AssetImpactEntry{
+				Asset:            graph.node(consumer),
+				Depth:            depth[consumer],
+				ViaAssetID:       current,
+				RelationshipType: rel.RelationshipType,
+			}
+			response.Affected = append(response.Affected, entry)
+
+			criticality := entry.Asset.Effective.Criticality
+			response.ByCriticality[criticality]++
+			if assetRatingLevels[criticality] > assetRatingLevels[response.HighestImpact] {
+				response.HighestImpact = criticality
+			}
+		}
+	}
+	response.TotalAffected = len(response.Affected)
+	return response, nil
+}
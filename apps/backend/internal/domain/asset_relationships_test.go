@@ -0,0 +1,241 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testGraphAsset(id, criticality, confidentiality, integrity, availability string) repo.Asset {
+	return repo.Asset{ID: id, TenantID: "tenant-1", Name: id, InventoryNumber: "INV-" + id,
+		Criticality: criticality, Confidentiality: confidentiality, Integrity: integrity, Availability: availability}
+}
+
+func testRelationship(source, relationshipType, target string) repo.AssetRelationship {
+	return repo.AssetRelationship{ID: source + "-" + target, TenantID: "tenant-1", SourceAssetID: source, TargetAssetID: target, RelationshipType: relationshipType}
+}
+
+// testAssetGraphData: service depends_on app, app runs_on server, server connected_to switch,
+// db stores_data_of records
+func testAssetGraphData() ([]repo.Asset, []repo.AssetRelationship) {
+	low, medium, high := dto.CriticalityLow, dto.CriticalityMedium, dto.CriticalityHigh
+	assets := []repo.Asset{
+		testGraphAsset("service", medium, low, low, high),
+		testGraphAsset("app", high, high, low, medium),
+		testGraphAsset("server", low, low, low, low),
+		testGraphAsset("switch", low, low, low, low),
+		testGraphAsset("db", low, low, medium, low),
+		testGraphAsset("records", medium, high, high, low),
+	}
+	relationships := []repo.AssetRelationship{
+		testRelationship("service", dto.AssetRelationDependsOn, "app"),
+		testRelationship("app", dto.AssetRelationRunsOn, "server"),
+		testRelationship("server", dto.AssetRelationConnectedTo, "switch"),
+		testRelationship("db", dto.AssetRelationStoresDataOf, "records"),
+	}
+	return assets, relationships
+}
+
+func TestRelationshipEnds(t *testing.T) {
+	tests := []struct {
+		relationshipType string
+		wantProvider     string
+		wantConsumer     string
+		wantOK           bool
+	}{
+		{dto.AssetRelationRunsOn, "b", "a", true},
+		{dto.AssetRelationDependsOn, "b", "a", true},
+		{dto.AssetRelationStoresDataOf, "a", "b", true},
+		{dto.AssetRelationConnectedTo, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relationshipType, func(t *testing.T) {
+			provider, consumer, ok := relationshipEnds(testRelationship("a", tt.relationshipType, "b"))
+			assert.Equal(t, tt.wantProvider, provider)
+			assert.Equal(t, tt.wantConsumer, consumer)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestAssetGraph_ReliesOn(t *testing.T) {
+	graph := newAssetGraph(testAssetGraphData())
+
+	tests := []struct {
+		consumer, provider string
+		want               bool
+	}{
+		{"app", "server", true},
+		{"service", "server", true},
+		{"records", "db", true},
+		{"server", "app", false},
+		{"server", "switch", false},
+		{"switch", "server", false},
+		{"service", "db", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.consumer+"_on_"+tt.provider, func(t *testing.T) {
+			assert.Equal(t, tt.want, graph.reliesOn(tt.consumer, tt.provider))
+		})
+	}
+}
+
+func TestAssetGraph_EffectiveRatings(t *testing.T) {
+	graph := newAssetGraph(testAssetGraphData())
+
+	server := graph.node("server")
+	require.NotNil(t, server.Effective)
+	assert.Equal(t, dto.CriticalityLow, server.Criticality, "the own rating is kept")
+	assert.Equal(t, dto.AssetEffectiveCIA{
+		Criticality:     dto.CriticalityHigh,
+		Confidentiality: dto.CriticalityHigh,
+		Integrity:       dto.CriticalityLow,
+		Availability:    dto.CriticalityHigh,
+		Raised:          true,
+		InheritedFrom: []dto.AssetRatingSource{
+			{Attribute: "criticality", Level: dto.CriticalityHigh, AssetID: "app", InventoryNumber: "INV-app", Name: "app"},
+			{Attribute: "confidentiality", Level: dto.CriticalityHigh, AssetID: "app", InventoryNumber: "INV-app", Name: "app"},
+			{Attribute: "availability", Level: dto.CriticalityHigh, AssetID: "service", InventoryNumber: "INV-service", Name: "service"},
+		},
+	}, *server.Effective, "ratings propagate transitively and name the asset they originate from")
+
+	db := graph.node("db").Effective
+	assert.Equal(t, dto.CriticalityHigh, db.Confidentiality, "a database inherits the ratings of the data it stores")
+	assert.Equal(t, dto.CriticalityHigh, db.Integrity)
+
+	switchNode := graph.node("switch").Effective
+	assert.False(t, switchNode.Raised, "connected_to does not propagate ratings")
+	assert.Empty(t, switchNode.InheritedFrom)
+
+	service := graph.node("service").Effective
+	assert.False(t, service.Raised, "nothing relies on the service")
+}
+
+func TestAssetGraph_EffectiveRatingsWithCycle(t *testing.T) {
+	graph := newAssetGraph(
+		[]repo.Asset{
+			testGraphAsset("a", dto.CriticalityLow, dto.CriticalityLow, dto.CriticalityLow, dto.CriticalityLow),
+			testGraphAsset("b", dto.CriticalityHigh, dto.CriticalityLow, dto.CriticalityLow, dto.CriticalityLow),
+		},
+		[]repo.AssetRelationship{
+			testRelationship("a", dto.AssetRelationDependsOn, "b"),
+			testRelationship("b", dto.AssetRelationDependsOn, "a"),
+		},
+	)
+
+	a := graph.node("a").Effective
+	assert.Equal(t, dto.CriticalityHigh, a.Criticality, "a stored cycle does not stop propagation")
+	assert.Equal(t, dto.CriticalityHigh, graph.node("b").Effective.Criticality)
+}
+
+func TestAssetGraph_AddEdgeResetsRatings(t *testing.T) {
+	graph := newAssetGraph(testAssetGraphData())
+	assert.Equal(t, dto.CriticalityLow, graph.node("switch").Effective.Criticality)
+
+	graph.addEdge(testRelationship("app", dto.AssetRelationDependsOn, "switch"))
+	assert.Equal(t, dto.CriticalityHigh, graph.node("switch").Effective.Criticality)
+}
+
+// fakeRelationshipRepo serves the test graph and stores created relationships
+type fakeRelationshipRepo struct {
+	AssetRepoInterface
+
+	assets        []repo.Asset
+	relationships []repo.AssetRelationship
+}
+
+func (r *fakeRelationshipRepo) GetByID(ctx context.Context, id string) (*repo.Asset, error) {
+	for i := range r.assets {
+		if r.assets[i].ID == id {
+			asset := r.assets[i]
+			return &asset, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRelationshipRepo) ListRelationshipNodes(ctx context.Context, tenantID string) ([]repo.Asset, error) {
+	return r.assets, nil
+}
+
+func (r *fakeRelationshipRepo) ListRelationships(ctx context.Context, tenantID string) ([]repo.AssetRelationship, error) {
+	return r.relationships, nil
+}
+
+func (r *fakeRelationshipRepo) CreateRelationship(ctx context.Context, rel *repo.AssetRelationship) error {
+	rel.ID = rel.SourceAssetID + "-" + rel.TargetAssetID
+	r.relationships = append(r.relationships, *rel)
+	return nil
+}
+
+func (r *fakeRelationshipRepo) AddHistory(ctx context.Context, assetID, fieldChanged, oldValue, newValue, changedBy string) error {
+	return nil
+}
+
+func TestCreateAssetRelationship(t *testing.T) {
+	tests := []struct {
+		name             string
+		source           string
+		target           string
+		relationshipType string
+		wantErr          error
+		wantField        string
+	}{
+		{name: "new dependency", source: "db", target: "server", relationshipType: dto.AssetRelationRunsOn},
+		{name: "provider relying on its consumer", source: "server", target: "service", relationshipType: dto.AssetRelationDependsOn, wantErr: ErrAssetRelationshipCycle},
+		{name: "data store of its own host", source: "server", target: "app", relationshipType: dto.AssetRelationStoresDataOf},
+		{name: "host storing data of an app relying on it", source: "app", target: "server", relationshipType: dto.AssetRelationStoresDataOf, wantErr: ErrAssetRelationshipCycle},
+		{name: "connection back to a dependency", source: "server", target: "app", relationshipType: dto.AssetRelationConnectedTo},
+		{name: "duplicate", source: "app", target: "server", relationshipType: dto.AssetRelationRunsOn, wantErr: ErrAssetRelationshipExists},
+		{name: "reversed connection", source: "switch", target: "server", relationshipType: dto.AssetRelationConnectedTo, wantErr: ErrAssetRelationshipExists},
+		{name: "self", source: "app", target: "app", relationshipType: dto.AssetRelationDependsOn, wantField: "target_asset_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assets, relationships := testAssetGraphData()
+			assetRepo := &fakeRelationshipRepo{assets: assets, relationships: relationships}
+			service := NewAssetService(assetRepo, nil, nil)
+
+			response, err := service.CreateAssetRelationship(context.Background(), tt.source, "tenant-1",
+				dto.AssetRelationshipRequest{TargetAssetID: tt.target, RelationshipType: tt.relationshipType}, "user-1")
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, assetRepo.relationships, len(relationships))
+			case tt.wantField != "":
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, dto.AssetRelationOutgoing, response.Direction)
+				assert.Len(t, assetRepo.relationships, len(relationships)+1)
+			}
+		})
+	}
+}
+
+func TestGetAssetImpact(t *testing.T) {
+	assets, relationships := testAssetGraphData()
+	service := NewAssetService(&fakeRelationshipRepo{assets: assets, relationships: relationships}, nil, nil)
+
+	impact, err := service.GetAssetImpact(context.Background(), "server", "tenant-1")
+	require.NoError(t, err)
+
+	require.Equal(t, 2, impact.TotalAffected, "the connected switch is not affected")
+	assert.Equal(t, "app", impact.Affected[0].Asset.ID)
+	assert.Equal(t, 1, impact.Affected[0].Depth)
+	assert.Equal(t, "service", impact.Affected[1].Asset.ID)
+	assert.Equal(t, 2, impact.Affected[1].Depth)
+	assert.Equal(t, "app", impact.Affected[1].ViaAssetID)
+	assert.Equal(t, dto.CriticalityHigh, impact.HighestImpact)
+	assert.Equal(t, map[string]int{dto.CriticalityHigh: 1, dto.CriticalityMedium: 1}, impact.ByCriticality)
+}
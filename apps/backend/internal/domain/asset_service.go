@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"risknexus/backend/internal/dto"
@@ -21,6 +22,16 @@ type AssetService struct {
 	assetRepo              AssetRepoInterface
 	userRepo               UserRepoInterface
 	documentStorageService DocumentStorageServiceInterface
+	inventoryNumbers       InventoryNumberGeneratorInterface
+	riskService            AssetRiskServiceInterface
+	actGenerator           AssetActGeneratorInterface
+
+	// discoveryMu serializes applying discovery reports so software is not added twice by concurrent reports
+	discoveryMu sync.Mutex
+	// vulnerabilityMu serializes vulnerability matching so a risk is not created twice for the same CVE
+	vulnerabilityMu sync.Mutex
+	// inventoryMu serializes label scans and campaign completion so a scan is not lost or counted twice
+	inventoryMu sync.Mutex
 }
 
 func NewAssetService(assetRepo AssetRepoInterface, userRepo UserRepoInterface, documentStorageService DocumentStorageServiceInterface) *AssetService {
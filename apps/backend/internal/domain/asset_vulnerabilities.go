@@ -0,0 +1,583 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+const (
+	// defaultVulnerabilityRiskCVSS - по умолчанию риски создаются для критических (CVSS >= 9.0) уязвимостей
+	defaultVulnerabilityRiskCVSS = 9.0
+	// vulnerabilityRiskCatalogCode - встроенная уязвимость каталога "Отсутствие обновлений безопасности"
+	vulnerabilityRiskCatalogCode = "V.002"
+	// maxCPEProductTokens limits the number of name words joined into a CPE product candidate
+	maxCPEProductTokens = 4
+)
+
+var versionSegment = regexp.MustCompile(`[0-9]+|[a-z]+`)
+
+// SetRiskService enables creating risks for critical vulnerabilities of high-criticality assets
+func (s *AssetService) SetRiskService(riskService AssetRiskServiceInterface) {
+	s.riskService = riskService
+}
+
+// Vulnerability feeds
+
+// ImportVulnerabilityFeed loads an NVD CVE feed or a CPE dictionary into the tenant CVE database
+// and re-matches all installed software against it
+func (s *AssetService) ImportVulnerabilityFeed(ctx context.Context, tenantID, filename string, data []byte, importedBy string) (*dto.VulnerabilityFeedImportResponse, error) {
+	feed, err := parseVulnerabilityFeed(data)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("DEBUG: asset_service.ImportVulnerabilityFeed file=%s format=%s cves=%d dictionary=%d skipped=%d",
+		filename, feed.format, len(feed.cves), len(feed.dictionary), feed.skipped)
+
+	response := &dto.VulnerabilityFeedImportResponse{
+		Format:            feed.format,
+		CVEs:              len(feed.cves),
+		DictionaryEntries: len(feed.dictionary),
+		Skipped:           feed.skipped,
+	}
+	for _, entry := range feed.cves {
+		response.CPEMatches += len(entry.Matches)
+	}
+
+	if len(feed.cves) > 0 {
+		if err := s.assetRepo.UpsertCVEEntries(ctx, tenantID, feed.cves); err != nil {
+			log.Printf("ERROR: asset_service.ImportVulnerabilityFeed UpsertCVEEntries: %v", err)
+			return nil, err
+		}
+	}
+	if len(feed.dictionary) > 0 {
+		if err := s.assetRepo.UpsertCPEDictionary(ctx, tenantID, feed.dictionary); err != nil {
+			log.Printf("ERROR: asset_service.ImportVulnerabilityFeed UpsertCPEDictionary: %v", err)
+			return nil, err
+		}
+	}
+
+	response.TotalCVEs, response.TotalDictionary, err = s.assetRepo.CountVulnerabilityFeed(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Matching, err = s.MatchAssetVulnerabilities(ctx, tenantID, dto.VulnerabilityMatchRequest{}, importedBy)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Vulnerability matching
+
+// softwareProduct is a CPE product recognized for installed software
+type softwareProduct struct {
+	repo.CPEProduct
+	tokens int
+}
+
+// MatchAssetVulnerabilities matches the software of one asset (req.AssetID) or of all tenant assets against
+// the CVE database, opens newly found vulnerabilities and closes the ones no longer affecting the installed
+// versions. With req.CreateRisks a risk is created for every open vulnerability with CVSS >= req.MinCVSS
+// on an asset whose effective criticality is at least req.MinCriticality.
+func (s *AssetService) MatchAssetVulnerabilities(ctx context.Context, tenantID string, req dto.VulnerabilityMatchRequest, requestedBy string) (*dto.VulnerabilityMatchResponse, error) {
+	if req.CreateRisks && s.riskService == nil {
+		return nil, errors.New("risk service is not configured")
+	}
+	assetID := ""
+	if req.AssetID != nil {
+		if _, err := s.getTenantAsset(ctx, *req.AssetID, tenantID); err != nil {
+			return nil, err
+		}
+		assetID = *req.AssetID
+	}
+
+	s.vulnerabilityMu.Lock()
+	defer s.vulnerabilityMu.Unlock()
+
+	software, err := s.assetRepo.ListSoftwareForMatching(ctx, tenantID, assetID)
+	if err != nil {
+		return nil, err
+	}
+	response := &dto.VulnerabilityMatchResponse{CheckedSoftware: len(software), RiskIDs: []string{}}
+
+	products, err := s.recognizeSoftwareProducts(ctx, tenantID, software)
+	if err != nil {
+		return nil, err
+	}
+	productKeys := make(map[string]bool)
+	for _, recognized := range products {
+		response.RecognizedSoftware++
+		for _, p := range recognized {
+			productKeys[p.Vendor+":"+p.Product] = true
+		}
+	}
+
+	matchesByProduct := make(map[string][]repo.CVECPEMatch)
+	if len(productKeys) > 0 {
+		matches, err := s.assetRepo.ListCPEMatches(ctx, tenantID, mapKeys(productKeys))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			key := m.Vendor + ":" + m.Product
+			matchesByProduct[key] = append(matchesByProduct[key], m)
+		}
+	}
+
+	var found []repo.AssetVulnerability
+	softwareIDs := make([]string, 0, len(software))
+	for _, sw := range software {
+		softwareIDs = append(softwareIDs, sw.ID)
+		version := ""
+		if sw.Version != nil {
+			version = strings.TrimSpace(*sw.Version)
+		}
+		seen := make(map[string]bool)
+		for _, p := range products[sw.ID] {
+			for _, m := range matchesByProduct[p.Vendor+":"+p.Product] {
+				if seen[m.CVEEntryID] || !cpeVersionAffected(m, version) {
+					continue
+				}
+				seen[m.CVEEntryID] = true
+				found = append(found, repo.AssetVulnerability{
+					TenantID:   tenantID,
+					AssetID:    sw.AssetID,
+					SoftwareID: sw.ID,
+					CVEEntryID: m.CVEEntryID,
+					Vendor:     m.Vendor,
+					Product:    m.Product,
+				})
+			}
+		}
+	}
+	response.Vulnerabilities = len(found)
+
+	if len(softwareIDs) > 0 {
+		response.Detected, response.Fixed, err = s.assetRepo.SaveVulnerabilityMatches(ctx, tenantID, softwareIDs, found, time.Now())
+		if err != nil {
+			log.Printf("ERROR: asset_service.MatchAssetVulnerabilities SaveVulnerabilityMatches: %v", err)
+			return nil, err
+		}
+	}
+	log.Printf("DEBUG: asset_service.MatchAssetVulnerabilities tenant=%s asset=%s checked=%d recognized=%d open=%d detected=%d fixed=%d",
+		tenantID, assetID, response.CheckedSoftware, response.RecognizedSoftware, response.Vulnerabilities, response.Detected, response.Fixed)
+
+	if req.CreateRisks {
+		minCVSS := defaultVulnerabilityRiskCVSS
+		if req.MinCVSS != nil {
+			minCVSS = *req.MinCVSS
+		}
+		minCriticality := dto.CriticalityHigh
+		if req.MinCriticality != nil {
+			minCriticality = *req.MinCriticality
+		}
+		response.RiskIDs, err = s.createVulnerabilityRisks(ctx, tenantID, assetID, minCVSS, minCriticality, requestedBy)
+		if err != nil {
+			return nil, err
+		}
+		response.RisksCreated = len(response.RiskIDs)
+	}
+	return response, nil
+}
+
+// recognizeSoftwareProducts finds the CPE products of installed software. The CPE dictionary is consulted
+// first by normalized title; otherwise consecutive words of the name are tried as a CVE product
+// ("Apache HTTP Server" -> http_server) and accepted when the vendor is part of the name too or the product
+// is the whole name. Of several candidates the longest product wins.
+func (s *AssetService) recognizeSoftwareProducts(ctx context.Context, tenantID string, software []repo.AssetSoftware) (map[string][]repo.CPEProduct, error) {
+	names := make(map[string]string, len(software))
+	titles := make(map[string]bool)
+	candidates := make(map[string]bool)
+	for _, sw := range software {
+		version := ""
+		if sw.Version != nil {
+			version = *sw.Version
+		}
+		name := normalizeSoftwareName(sw.SoftwareName, version)
+		if name == "" {
+			continue
+		}
+		names[sw.ID] = name
+		titles[name] = true
+		for _, candidate := range cpeProductCandidates(strings.Fields(name)) {
+			candidates[candidate] = true
+		}
+	}
+
+	dictionary, err := s.assetRepo.FindCPEByTitles(ctx, tenantID, mapKeys(titles))
+	if err != nil {
+		return nil, err
+	}
+	byTitle := make(map[string][]repo.CPEProduct)
+	for _, entry := range dictionary {
+		byTitle[entry.NormalizedTitle] = append(byTitle[entry.NormalizedTitle], entry.CPEProduct)
+	}
+
+	cveProducts, err := s.assetRepo.FindCVEProducts(ctx, tenantID, mapKeys(candidates))
+	if err != nil {
+		return nil, err
+	}
+	byProduct := make(map[string][]repo.CPEProduct)
+	for _, p := range cveProducts {
+		byProduct[p.Product] = append(byProduct[p.Product], p)
+	}
+
+	result := make(map[string][]repo.CPEProduct)
+	for id, name := range names {
+		if products, ok := byTitle[name]; ok {
+			result[id] = products
+			continue
+		}
+
+		tokens := strings.Fields(name)
+		inName := make(map[string]bool, len(tokens))
+		for _, token := range tokens {
+			inName[token] = true
+		}
+		var best []softwareProduct
+		for _, candidate := range cpeProductCandidates(tokens) {
+			for _, p := range byProduct[candidate] {
+				size := len(strings.Split(p.Product, "_"))
+				if !cpeVendorInName(p.Vendor, inName) && size != len(tokens) {
+					continue
+				}
+				switch {
+				case len(best) == 0 || size > best[0].tokens:
+					best = []softwareProduct{{p, size}}
+				case size == best[0].tokens:
+					best = append(best, softwareProduct{p, size})
+				}
+			}
+		}
+		for _, p := range best {
+			result[id] = append(result[id], p.CPEProduct)
+		}
+	}
+	return result, nil
+}
+
+// cpeProductCandidates joins up to maxCPEProductTokens consecutive name words with underscores,
+// as CPE product names are written
+func cpeProductCandidates(tokens []string) []string {
+	var candidates []string
+	for i := range tokens {
+		for j := i + 1; j <= len(tokens) && j-i <= maxCPEProductTokens; j++ {
+			candidates = append(candidates, strings.Join(tokens[i:j], "_"))
+		}
+	}
+	return candidates
+}
+
+// cpeVendorInName reports whether every word of the CPE vendor ("microsoft", "oracle_corporation")
+// is present in the software name
+func cpeVendorInName(vendor string, inName map[string]bool) bool {
+	for _, word := range strings.Split(vendor, "_") {
+		if !inName[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// cpeVersionAffected reports whether the installed version falls under the vulnerable configuration.
+// Configurations without a version and bounds affect every version; software without a version
+// is only affected by such configurations.
+func cpeVersionAffected(m repo.CVECPEMatch, version string) bool {
+	if m.Version != nil {
+		return version != "" && compareVersions(version, *m.Version) == 0
+	}
+	bounded := m.VersionStartIncluding != nil || m.VersionStartExcluding != nil ||
+		m.VersionEndIncluding != nil || m.VersionEndExcluding != nil
+	if !bounded {
+		return true
+	}
+	if version == "" {
+		return false
+	}
+	if m.VersionStartIncluding != nil && compareVersions(version, *m.VersionStartIncluding) < 0 {
+		return false
+	}
+	if m.VersionStartExcluding != nil && compareVersions(version, *m.VersionStartExcluding) <= 0 {
+		return false
+	}
+	if m.VersionEndIncluding != nil && compareVersions(version, *m.VersionEndIncluding) > 0 {
+		return false
+	}
+	if m.VersionEndExcluding != nil && compareVersions(version, *m.VersionEndExcluding) >= 0 {
+		return false
+	}
+	return true
+}
+
+// compareVersions compares dotted versions segment by segment: numbers numerically, words alphabetically,
+// a number above a word, missing segments as zero. A trailing word marks a pre-release, so 1.0rc1 < 1.0 = 1.0.0 < 1.0.1.
+func compareVersions(a, b string) int {
+	left := versionSegment.FindAllString(strings.ToLower(a), -1)
+	right := versionSegment.FindAllString(strings.ToLower(b), -1)
+	for i := 0; i < len(left) || i < len(right); i++ {
+		switch {
+		case i >= len(left):
+			if isVersionNumber(right[i]) {
+				if strings.Trim(right[i], "0") == "" {
+					continue
+				}
+				return -1
+			}
+			return 1
+		case i >= len(right):
+			if isVersionNumber(left[i]) {
+				if strings.Trim(left[i], "0") == "" {
+					continue
+				}
+				return 1
+			}
+			return -1
+		}
+		l, r := left[i], right[i]
+		ln, rn := isVersionNumber(l), isVersionNumber(r)
+		switch {
+		case ln && rn:
+			l, r = strings.TrimLeft(l, "0"), strings.TrimLeft(r, "0")
+			if len(l) != len(r) {
+				if len(l) < len(r) {
+					return -1
+				}
+				return 1
+			}
+		case ln:
+			return 1
+		case rn:
+			return -1
+		}
+		if c := strings.Compare(l, r); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func isVersionNumber(segment string) bool {
+	return segment[0] >= '0' && segment[0] <= '9'
+}
+
+func mapKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Vulnerability risks
+
+// createVulnerabilityRisks creates one risk per asset and CVE for open vulnerabilities with CVSS >= minCVSS
+// on assets whose effective criticality (with ratings inherited over relationships) is at least minCriticality.
+// Vulnerabilities of an asset and CVE that already has a risk are linked to it.
+func (s *AssetService) createVulnerabilityRisks(ctx context.Context, tenantID, assetID string, minCVSS float64, minCriticality, createdBy string) ([]string, error) {
+	filters := map[string]interface{}{
+		"status":   dto.AssetVulnerabilityOpen,
+		"min_cvss": minCVSS,
+	}
+	if assetID != "" {
+		filters["asset_id"] = assetID
+	}
+	vulnerabilities, err := s.assetRepo.ListAssetVulnerabilities(ctx, tenantID, filters)
+	if err != nil {
+		return nil, err
+	}
+	if len(vulnerabilities) == 0 {
+		return []string{}, nil
+	}
+
+	graph, err := s.loadAssetGraph(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	type riskKey struct{ assetID, cveEntryID string }
+	var keys []riskKey
+	groups := make(map[riskKey][]repo.AssetVulnerability)
+	for _, v := range vulnerabilities {
+		key := riskKey{v.AssetID, v.CVEEntryID}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], v)
+	}
+
+	vulnerabilityID := s.vulnerabilityCatalogEntryID(ctx, tenantID)
+	riskIDs := []string{}
+	for _, key := range keys {
+		group := groups[key]
+		var existing *string
+		unlinked := false
+		for _, v := range group {
+			if v.RiskID != nil {
+				existing = v.RiskID
+			} else {
+				unlinked = true
+			}
+		}
+		if existing != nil {
+			if unlinked {
+				if err := s.assetRepo.SetVulnerabilityRisk(ctx, tenantID, key.assetID, key.cveEntryID, *existing); err != nil {
+					return riskIDs, err
+				}
+			}
+			continue
+		}
+
+		v := group[0]
+		criticality := v.AssetCriticality
+		if _, ok := graph.nodes[v.AssetID]; ok {
+			criticality = graph.effectiveRatings(v.AssetID, make(map[string]bool))[0].level
+		}
+		if assetRatingLevels[criticality] < assetRatingLevels[minCriticality] {
+			continue
+		}
+
+		risk, err := s.createVulnerabilityRisk(ctx, tenantID, v, criticality, vulnerabilityID)
+		if err != nil {
+			log.Printf("ERROR: asset_service.createVulnerabilityRisks CreateRisk %s asset=%s: %v", v.CVEID, v.AssetID, err)
+			return riskIDs, err
+		}
+		if err := s.assetRepo.SetVulnerabilityRisk(ctx, tenantID, key.assetID, key.cveEntryID, risk.ID); err != nil {
+			return riskIDs, err
+		}
+		if err := s.assetRepo.AddHistory(ctx, v.AssetID, "vulnerability_risk", "", fmt.Sprintf("%s: %s", v.CVEID, risk.Title), createdBy); err != nil {
+			log.Printf("WARN: asset_service.createVulnerabilityRisks AddHistory: %v", err)
+		}
+		riskIDs = append(riskIDs, risk.ID)
+	}
+	return riskIDs, nil
+}
+
+func (s *AssetService) createVulnerabilityRisk(ctx context.Context, tenantID string, v repo.AssetVulnerability, criticality string, vulnerabilityID *string) (*repo.Risk, error) {
+	software := v.SoftwareName
+	if v.SoftwareVersion != nil && *v.SoftwareVersion != "" {
+		software += " " + *v.SoftwareVersion
+	}
+	score := 0.0
+	if v.CVSSScore != nil {
+		score = *v.CVSSScore
+	}
+
+	title := fmt.Sprintf("%s в %s на активе %s", v.CVEID, software, v.InventoryNumber)
+	description := fmt.Sprintf("Уязвимость %s (CVSS %.1f) в установленном ПО «%s» актива %s «%s»",
+		v.CVEID, score, software, v.InventoryNumber, v.AssetName)
+	if v.Description != nil {
+		description += "\n\n" + *v.Description
+	}
+
+	// CVSS определяет вероятность эксплуатации, критичность актива - последствия (шкала 1-4)
+	likelihood := 1
+	switch {
+	case score >= 9.0:
+		likelihood = 4
+	case score >= 7.0:
+		likelihood = 3
+	case score >= 4.0:
+		likelihood = 2
+	}
+	impact := assetRatingLevels[criticality] + 1
+
+	return s.riskService.CreateRisk(ctx, tenantID, title, &description, stringPtr(dto.RiskCategoryTechnical),
+		likelihood, impact, v.AssetOwnerID, &v.AssetID, nil, vulnerabilityID, nil, nil, nil)
+}
+
+// vulnerabilityCatalogEntryID returns the built-in "missing security updates" vulnerability used as
+// the scenario of created risks, or nil if it is not in the catalog
+func (s *AssetService) vulnerabilityCatalogEntryID(ctx context.Context, tenantID string) *string {
+	entries, err := s.riskService.GetCatalogEntries(ctx, tenantID, dto.RiskCatalogKindVulnerability,
+		repo.RiskCatalogFilter{Source: dto.RiskCatalogSourceBuiltin, Search: vulnerabilityRiskCatalogCode, ActiveOnly: true})
+	if err != nil {
+		log.Printf("WARN: asset_service.vulnerabilityCatalogEntryID: %v", err)
+		return nil
+	}
+	for _, entry := range entries {
+		if entry.Code == vulnerabilityRiskCatalogCode {
+			return &entry.ID
+		}
+	}
+	return nil
+}
+
+// Vulnerability lists
+
+// ListVulnerabilities returns the tenant vulnerabilities matching the filters
+func (s *AssetService) ListVulnerabilities(ctx context.Context, tenantID string, filters map[string]interface{}) ([]dto.AssetVulnerabilityResponse, error) {
+	vulnerabilities, err := s.assetRepo.ListAssetVulnerabilities(ctx, tenantID, filters)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]dto.AssetVulnerabilityResponse, 0, len(vulnerabilities))
+	for _, v := range vulnerabilities {
+		responses = append(responses, assetVulnerabilityResponse(v))
+	}
+	return responses, nil
+}
+
+// GetAssetVulnerabilities returns the vulnerabilities of the asset with a summary of the open ones
+func (s *AssetService) GetAssetVulnerabilities(ctx context.Context, assetID, tenantID, status string) (*dto.AssetVulnerabilityListResponse, error) {
+	if _, err := s.getTenantAsset(ctx, assetID, tenantID); err != nil {
+		return nil, err
+	}
+	vulnerabilities, err := s.assetRepo.ListAssetVulnerabilities(ctx, tenantID, map[string]interface{}{"asset_id": assetID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AssetVulnerabilityListResponse{
+		Summary:         dto.AssetVulnerabilitySummary{BySeverity: make(map[string]int)},
+		Vulnerabilities: []dto.AssetVulnerabilityResponse{},
+	}
+	for _, v := range vulnerabilities {
+		if v.Status == dto.AssetVulnerabilityOpen {
+			response.Summary.Open++
+			if v.Severity != nil {
+				response.Summary.BySeverity[*v.Severity]++
+			}
+			if v.CVSSScore != nil && (response.Summary.MaxCVSS == nil || *v.CVSSScore > *response.Summary.MaxCVSS) {
+				response.Summary.MaxCVSS = v.CVSSScore
+			}
+		}
+		if status == "" || v.Status == status {
+			response.Vulnerabilities = append(response.Vulnerabilities, assetVulnerabilityResponse(v))
+		}
+	}
+	return response, nil
+}
+
+func assetVulnerabilityResponse(v repo.AssetVulnerability) dto.AssetVulnerabilityResponse {
+	return dto.AssetVulnerabilityResponse{
+		ID:              v.ID,
+		CVEID:           v.CVEID,
+		Description:     v.Description,
+		CVSSScore:       v.CVSSScore,
+		Severity:        v.Severity,
+		Status:          v.Status,
+		AssetID:         v.AssetID,
+		AssetName:       v.AssetName,
+		InventoryNumber: v.InventoryNumber,
+		SoftwareID:      v.SoftwareID,
+		SoftwareName:    v.SoftwareName,
+		SoftwareVersion: v.SoftwareVersion,
+		CPEVendor:       v.Vendor,
+		CPEProduct:      v.Product,
+		RiskID:          v.RiskID,
+		FirstDetectedAt: v.FirstDetectedAt,
+		LastDetectedAt:  v.LastDetectedAt,
+		FixedAt:         v.FixedAt,
+	}
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"risknexus/backend/internal/repo"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"1.0.0", "1.0", 0},
+		{"01.2", "1.2", 0},
+		{"1.0.1", "1.0", 1},
+		{"1.0", "1.0.1", -1},
+		{"1.10", "1.9", 1},
+		{"2.0", "10.0", -1},
+		{"115.0.2", "115.0.10", -1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0", "1.0rc1", 1},
+		{"1.0rc1", "1.0rc2", -1},
+		{"1.2a", "1.2b", -1},
+		{"1.2", "1.a", 1},
+		{"2.4.58-1ubuntu1", "2.4.58", 1},
+		{"V8.5.4", "v8.5.4", 0},
+		{"", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, compareVersions(tt.a, tt.b))
+		})
+	}
+}
+
+func TestCPEVersionAffected(t *testing.T) {
+	tests := []struct {
+		name    string
+		match   repo.CVECPEMatch
+		version string
+		want    bool
+	}{
+		{"exact version", repo.CVECPEMatch{Version: stringPtr("1.2.3")}, "1.2.3", true},
+		{"exact version with trailing zero", repo.CVECPEMatch{Version: stringPtr("1.2")}, "1.2.0", true},
+		{"other version", repo.CVECPEMatch{Version: stringPtr("1.2.3")}, "1.2.4", false},
+		{"exact version, unknown installed", repo.CVECPEMatch{Version: stringPtr("1.2.3")}, "", false},
+		{"any version", repo.CVECPEMatch{}, "5.0", true},
+		{"any version, unknown installed", repo.CVECPEMatch{}, "", true},
+		{"range start including", repo.CVECPEMatch{VersionStartIncluding: stringPtr("2.0"), VersionEndExcluding: stringPtr("2.5")}, "2.0", true},
+		{"range inside", repo.CVECPEMatch{VersionStartIncluding: stringPtr("2.0"), VersionEndExcluding: stringPtr("2.5")}, "2.4.9", true},
+		{"range below start", repo.CVECPEMatch{VersionStartIncluding: stringPtr("2.0"), VersionEndExcluding: stringPtr("2.5")}, "1.9", false},
+		{"range end excluding", repo.CVECPEMatch{VersionStartIncluding: stringPtr("2.0"), VersionEndExcluding: stringPtr("2.5")}, "2.5", false},
+		{"range, unknown installed", repo.CVECPEMatch{VersionStartIncluding: stringPtr("2.0"), VersionEndExcluding: stringPtr("2.5")}, "", false},
+		{"start excluding", repo.CVECPEMatch{VersionStartExcluding: stringPtr("2.0")}, "2.0", false},
+		{"after start excluding", repo.CVECPEMatch{VersionStartExcluding: stringPtr("2.0")}, "2.0.1", true},
+		{"end including", repo.CVECPEMatch{VersionEndIncluding: stringPtr("3.0")}, "3.0", true},
+		{"after end including", repo.CVECPEMatch{VersionEndIncluding: stringPtr("3.0")}, "3.0.1", false},
+		{"pre-release before end", repo.CVECPEMatch{VersionEndExcluding: stringPtr("3.0")}, "3.0rc1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cpeVersionAffected(tt.match, tt.version))
+		})
+	}
+}
+
+func TestCPEProductCandidates(t *testing.T) {
+	assert.Equal(t,
+		[]string{"apache", "apache_http", "apache_http_server", "http", "http_server", "server"},
+		cpeProductCandidates([]string{"apache", "http", "server"}))
+	assert.Len(t, cpeProductCandidates([]string{"a", "b", "c", "d", "e"}), 14, "candidates are limited to maxCPEProductTokens words")
+	assert.Empty(t, cpeProductCandidates(nil))
+}
+
+func TestCPEVendorInName(t *testing.T) {
+	inName := map[string]bool{"oracle": true, "corporation": true, "java": true}
+
+	tests := []struct {
+		vendor string
+		want   bool
+	}{
+		{"oracle", true},
+		{"oracle_corporation", true},
+		{"microsoft", false},
+		{"oracle_america", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vendor, func(t *testing.T) {
+			assert.Equal(t, tt.want, cpeVendorInName(tt.vendor, inName))
+		})
+	}
+}
@@ -294,12 +294,11 @@ func (s *DocumentService) UploadDocument(ctx context.Context, tenantID string, f
 	}
 
 	// Вычисляем хеш файла
-	hash := sha256.New()
 	file.Seek(0, 0) // Возвращаемся к началу файла
-	if _, err := io.Copy(hash, file); err != nil {
-		return nil, fmt.Errorf("failed to calculate hash: %w", err)
+	fileHash, err := calculateFileHash(file)
+	if err != nil {
+		return nil, err
 	}
-	fileHash := fmt.Sprintf("%x", hash.Sum(nil))
 
 	// Создаем документ в БД
 	documentID := uuid.New().String()
@@ -899,12 +898,11 @@ func (s *DocumentService) CreateDocumentVersion(ctx context.Context, documentID,
 	}
 
 	// Вычисляем хеш файла
-	hash := sha256.New()
 	file.Seek(0, 0) // Возвращаемся к началу файла
-	if _, err := io.Copy(hash, file); err != nil {
-		return nil, fmt.Errorf("failed to calculate hash: %w", err)
+	fileHash, err := calculateFileHash(file)
+	if err != nil {
+		return nil, err
 	}
-	fileHash := fmt.Sprintf("%x", hash.Sum(nil))
 
 	// Создаем версию документа в БД
 	versionID := uuid.New().String()
@@ -1170,6 +1168,15 @@ func (s *DocumentService) RemoveDocumentLink(ctx context.Context, documentID, mo
 	return nil
 }
 
+// calculateFileHash вычисляет SHA-256 содержимого файла
+func calculateFileHash(r io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", fmt.Errorf("failed to calculate hash: %w", err)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
 // buildStoragePath создает структурированный путь к файлу
 func (s *DocumentService) buildStoragePath(tenantID, module, category, fileName string) string {
 	// Определяем базовую структуру папок
@@ -1460,6 +1467,9 @@ func resolveModuleAndCategoryFromRequest(req dto.UploadDocumentDTO) (string, str
 			case "passport":
 				module = "assets"
 				category = "passport"
+			case dto.DocumentTypeTransferAct, dto.DocumentTypeWriteoffAct, dto.DocumentTypeRepairLog:
+				module = "assets"
+				category = tagValue
 			case "compliance":
 				module = "compliance"
 				category = "compliance"
@@ -1472,7 +1482,10 @@ func resolveModuleAndCategoryFromRequest(req dto.UploadDocumentDTO) (string, str
 		switch req.LinkedTo.Module {
 		case "assets":
 			module = "assets"
-			category = "assets"
+			// Акты жизненного цикла сохраняют свой тип как категорию документа актива
+			if !isAssetActCategory(category) {
+				category = "assets"
+			}
 		case "risks":
 			module = "risks"
 			category = "risks"
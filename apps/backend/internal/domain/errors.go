@@ -25,6 +25,100 @@ var (
 	ErrEmptyField       = errors.New("field cannot be empty")
 	ErrFieldTooLong     = errors.New("field exceeds maximum length")
 	ErrFieldTooShort    = errors.New("field is too short")
+
+	// Ошибки рисков
+	ErrRiskNotFound              = errors.New("risk not found")
+	ErrTreatmentPlanNotFound     = errors.New("treatment plan not found")
+	ErrTreatmentTaskNotFound     = errors.New("treatment task not found")
+	ErrTreatmentPlanClosed       = errors.New("treatment plan is completed or cancelled")
+	ErrTreatmentEvidenceRequired = errors.New("completion evidence is required to close a task")
+
+	// Ошибки принятия рисков
+	ErrRiskAcceptanceNotFound     = errors.New("risk acceptance not found")
+	ErrRiskStrategyNotAccept      = errors.New("risk strategy must be 'accept' to request acceptance")
+	ErrRiskAcceptancePending      = errors.New("risk already has a pending acceptance request")
+	ErrRiskAcceptanceNotPending   = errors.New("risk acceptance is not pending")
+	ErrRiskAcceptanceNotApproved  = errors.New("risk acceptance is not approved")
+	ErrRiskAcceptanceSelfApproval = errors.New("requester cannot approve their own risk acceptance")
+	ErrRiskAcceptanceApproverRole = errors.New("user does not have the role required to approve this risk acceptance")
+	ErrRiskAcceptanceRequired     = errors.New("risk can only be accepted through an approved risk acceptance")
+
+	// Ошибки пересмотра рисков
+	ErrReviewIntervalNotFound = errors.New("review interval not found")
+
+	// Ошибки ключевых индикаторов риска
+	ErrKRINotFound   = errors.New("key risk indicator not found")
+	ErrKRICodeExists = errors.New("key risk indicator with this code already exists")
+	ErrKRIInactive   = errors.New("key risk indicator is inactive")
+
+	// Ошибки количественной оценки рисков
+	ErrRiskQuantificationNotFound = errors.New("risk has no quantitative inputs")
+
+	// Ошибки импорта рисков
+	ErrRiskImportInvalidRows = errors.New("import file contains invalid rows")
+
+	// Ошибки каталогов угроз и уязвимостей
+	ErrCatalogEntryNotFound   = errors.New("catalog entry not found")
+	ErrCatalogEntryCodeExists = errors.New("catalog entry with this code already exists")
+	ErrCatalogEntryBuiltin    = errors.New("built-in catalog entries cannot be modified")
+
+	// Ошибки аппетита к риску
+	ErrRiskAppetiteNotFound = errors.New("risk appetite statement not found")
+	ErrRiskOutOfTolerance   = errors.New("risk exceeds tolerance and requires an active treatment plan or approved acceptance")
+
+	// Ошибки инцидентов
+	ErrIncidentTransitionNotAllowed = errors.New("incident status transition is not allowed")
+	ErrIncidentStatusConflict       = errors.New("incident status was changed by another request")
+	ErrIncidentCalendarNotFound     = errors.New("business calendar not found")
+	ErrAlertSourceNotFound          = errors.New("alert source not found")
+	ErrAlertSourceUnauthorized      = errors.New("invalid or inactive alert source token")
+	ErrInvalidAlertPayload          = errors.New("invalid alert payload")
+	ErrIncidentPlaybookNotFound     = errors.New("incident playbook not found")
+	ErrIncidentActionNotFound       = errors.New("incident action not found")
+	ErrRegulatoryProfileNotFound    = errors.New("regulatory profile not found")
+	ErrIncidentNotificationNotFound = errors.New("incident notification not found")
+	ErrNotificationTemplateNotFound = errors.New("notification template not found")
+	ErrIncidentReviewNotFound       = errors.New("incident review not found")
+	ErrReviewFollowUpNotFound       = errors.New("review follow-up not found")
+	ErrIncidentEvidenceNotFound     = errors.New("incident evidence not found")
+	ErrIncidentEvidenceSealed       = errors.New("incident evidence is sealed")
+	ErrEvidenceDocumentProtected    = errors.New("document is held as incident evidence")
+	ErrIncidentLinkNotFound         = errors.New("incident link not found")
+	ErrIncidentMergeNotFound        = errors.New("incident merge not found")
+	ErrIncidentAlreadyMerged        = errors.New("incident is merged into another incident")
+	ErrIncidentMergeReverted        = errors.New("incident merge has already been reverted")
+	ErrIncidentMergeRevertExpired   = errors.New("incident merge can no longer be reverted")
+	ErrIncidentMailboxNotFound      = errors.New("incident mailbox not found")
+
+	// Ошибки активов
+	ErrAssetNotFound              = errors.New("asset not found")
+	ErrDiscoveryAgentNotFound     = errors.New("discovery agent not found")
+	ErrDiscoveryAgentUnauthorized = errors.New("invalid or inactive discovery agent token")
+	ErrInvalidDiscoveryReport     = errors.New("invalid discovery report")
+	ErrDiscoveryReportNotFound    = errors.New("discovery report not found")
+	ErrDiscoveryReportResolved    = errors.New("discovery report is not pending reconciliation")
+	ErrAssetImportInvalidRows     = errors.New("import file contains invalid or duplicate rows")
+	ErrAssetRelationshipNotFound  = errors.New("asset relationship not found")
+	ErrAssetRelationshipExists    = errors.New("asset relationship already exists")
+	ErrAssetRelationshipCycle     = errors.New("relationship would make the asset depend on itself")
+	ErrInvalidVulnerabilityFeed   = errors.New("invalid vulnerability feed")
+
+	// Ошибки операций жизненного цикла активов
+	ErrAssetLifecycleOperationNotFound   = errors.New("asset lifecycle operation not found")
+	ErrAssetLifecycleOperationNotPending = errors.New("asset lifecycle operation is not pending approval")
+	ErrAssetLifecyclePendingExists       = errors.New("asset already has a lifecycle operation pending approval")
+	ErrAssetLifecycleSelfApproval        = errors.New("requester cannot approve their own lifecycle operation")
+	ErrAssetLifecycleNotRequester        = errors.New("only the requester can cancel the lifecycle operation")
+
+	// Ошибки инвентаризации активов
+	ErrInventoryCampaignNotFound        = errors.New("inventory campaign not found")
+	ErrInventoryItemNotFound            = errors.New("inventory item not found")
+	ErrInventoryCampaignStatus          = errors.New("operation is not allowed in the current inventory campaign status")
+	ErrInventoryNotAuditor              = errors.New("user is not an auditor of the inventory campaign")
+	ErrInventoryUnresolvedDiscrepancies = errors.New("inventory campaign has unresolved discrepancies")
+
+	// Ошибки уведомлений пользователей
+	ErrUserNotificationNotFound = errors.New("notification not found")
 )
 
 // ValidationError представляет ошибку валидации
@@ -0,0 +1,580 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// defaultAlertFieldMapping lists, per format, the alert paths tried for each incident field.
+// Several comma-separated paths are tried in order; for hostname and ip all found values are used.
+var defaultAlertFieldMapping = map[string]map[string]string{
+	dto.AlertFormatJSON: {
+		dto.AlertFieldTitle:       "title,name,rule.name,alert.name,signature",
+		dto.AlertFieldDescription: "description,message,msg,alert.description",
+		dto.AlertFieldSeverity:    "severity,level,priority,alert.severity",
+		dto.AlertFieldCategory:    "category",
+		dto.AlertFieldHostname:    "hostname,host,host.name,host.hostname,agent.hostname",
+		dto.AlertFieldIP:          "ip,src_ip,dst_ip,source.ip,destination.ip,host.ip",
+		dto.AlertFieldDetectedAt:  "timestamp,@timestamp,time,detected_at",
+	},
+	dto.AlertFormatCEF: {
+		dto.AlertFieldTitle:       "name",
+		dto.AlertFieldDescription: "msg",
+		dto.AlertFieldSeverity:    "severity",
+		dto.AlertFieldCategory:    "cat",
+		dto.AlertFieldHostname:    "dhost,shost,dvchost",
+		dto.AlertFieldIP:          "dst,src,dvc",
+		dto.AlertFieldDetectedAt:  "rt,end,start",
+	},
+	dto.AlertFormatSyslog: {
+		dto.AlertFieldTitle:       "cef.name",
+		dto.AlertFieldDescription: "cef.msg,message",
+		dto.AlertFieldSeverity:    "cef.severity,severity",
+		dto.AlertFieldCategory:    "cef.cat",
+		dto.AlertFieldHostname:    "cef.dhost,cef.shost,hostname",
+		dto.AlertFieldIP:          "cef.dst,cef.src",
+		dto.AlertFieldDetectedAt:  "cef.rt,timestamp",
+	},
+}
+
+var defaultAlertCorrelationFields = []string{dto.AlertFieldTitle, dto.AlertFieldHostname, dto.AlertFieldIP}
+
+// alertSeverityKeywords maps textual severities of common SIEM and syslog vocabularies to criticality
+var alertSeverityKeywords = map[string]string{
+	"critical": dto.IncidentCriticalityCritical, "crit": dto.IncidentCriticalityCritical,
+	"emergency": dto.IncidentCriticalityCritical, "emerg": dto.IncidentCriticalityCritical,
+	"alert": dto.IncidentCriticalityCritical, "fatal": dto.IncidentCriticalityCritical,
+	"very-high": dto.IncidentCriticalityCritical, "very high": dto.IncidentCriticalityCritical,
+	"high": dto.IncidentCriticalityHigh, "error": dto.IncidentCriticalityHigh, "err": dto.IncidentCriticalityHigh,
+	"major": dto.IncidentCriticalityHigh, "severe": dto.IncidentCriticalityHigh,
+	"medium": dto.IncidentCriticalityMedium, "moderate": dto.IncidentCriticalityMedium,
+	"warning": dto.IncidentCriticalityMedium, "warn": dto.IncidentCriticalityMedium,
+	"low": dto.IncidentCriticalityLow, "minor": dto.IncidentCriticalityLow, "notice": dto.IncidentCriticalityLow,
+	"info": dto.IncidentCriticalityLow, "informational": dto.IncidentCriticalityLow, "debug": dto.IncidentCriticalityLow,
+}
+
+var incidentCategories = map[string]bool{
+	dto.IncidentCategoryTechnicalFailure:   true,
+	dto.IncidentCategoryDataBreach:         true,
+	dto.IncidentCategoryUnauthorizedAccess: true,
+	dto.IncidentCategoryPhysical:           true,
+	dto.IncidentCategoryMalware:            true,
+	dto.IncidentCategorySocialEngineering:  true,
+	dto.IncidentCategoryOther:              true,
+}
+
+const maxAlertRawPayload = 64 * 1024
+
+// normalizedAlert is an alert converted to incident fields by the source mapping rules
+type normalizedAlert struct {
+	title       string
+	description string
+	severity    string
+	criticality string
+	category    string
+	hostnames   []string
+	ips         []string
+	detectedAt  time.Time
+}
+
+// Alert sources
+
+func (s *IncidentService) ListAlertSources(ctx context.Context, tenantID string) ([]*repo.IncidentAlertSource, error) {
+	log.Printf("DEBUG: incident_service.ListAlertSources tenant=%s", tenantID)
+
+	sources, err := s.incidentRepo.ListAlertSources(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListAlertSources ListAlertSources: %v", err)
+		return nil, err
+	}
+	return sources, nil
+}
+
+// CreateAlertSource registers a source and returns it together with its token, which is not stored in plain text
+func (s *IncidentService) CreateAlertSource(ctx context.Context, tenantID string, req dto.IncidentAlertSourceRequest, createdBy string) (*repo.IncidentAlertSource, string, error) {
+	log.Printf("DEBUG: incident_service.CreateAlertSource tenant=%s name=%s", tenantID, req.Name)
+
+	token, err := newAccessToken(alertSourceTokenPrefix)
+	if err != nil {
+		log.Printf("ERROR: incident_service.CreateAlertSource newAccessToken: %v", err)
+		return nil, "", err
+	}
+
+	now := time.Now()
+	source := &repo.IncidentAlertSource{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		ReporterID: createdBy,
+		IsActive:   true,
+		CreatedAt:  now,
+	}
+	source.TokenHash, source.TokenPrefix = token.Hash, token.Prefix
+	if err := s.applyAlertSourceRequest(ctx, source, req); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.incidentRepo.SaveAlertSource(ctx, source); err != nil {
+		log.Printf("ERROR: incident_service.CreateAlertSource SaveAlertSource: %v", err)
+		return nil, "", err
+	}
+
+	log.Printf("INFO: incident_service.CreateAlertSource created id=%s", source.ID)
+	return source, token.Value, nil
+}
+
+func (s *IncidentService) UpdateAlertSource(ctx context.Context, id, tenantID string, req dto.IncidentAlertSourceRequest) (*repo.IncidentAlertSource, error) {
+	log.Printf("DEBUG: incident_service.UpdateAlertSource id=%s tenant=%s", id, tenantID)
+
+	source, err := s.getAlertSource(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyAlertSourceRequest(ctx, source, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveAlertSource(ctx, source); err != nil {
+		log.Printf("ERROR: incident_service.UpdateAlertSource SaveAlertSource: %v", err)
+		return nil, err
+	}
+	return source, nil
+}
+
+// RotateAlertSourceToken issues a new token; the previous one stops working immediately
+func (s *IncidentService) RotateAlertSourceToken(ctx context.Context, id, tenantID string) (*repo.IncidentAlertSource, string, error) {
+	log.Printf("DEBUG: incident_service.RotateAlertSourceToken id=%s tenant=%s", id, tenantID)
+
+	source, err := s.getAlertSource(ctx, id, tenantID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := newAccessToken(alertSourceTokenPrefix)
+	if err != nil {
+		log.Printf("ERROR: incident_service.RotateAlertSourceToken newAccessToken: %v", err)
+		return nil, "", err
+	}
+	source.TokenHash, source.TokenPrefix = token.Hash, token.Prefix
+	source.UpdatedAt = time.Now()
+
+	if err := s.incidentRepo.SaveAlertSource(ctx, source); err != nil {
+		log.Printf("ERROR: incident_service.RotateAlertSourceToken SaveAlertSource: %v", err)
+		return nil, "", err
+	}
+	return source, token.Value, nil
+}
+
+func (s *IncidentService) DeleteAlertSource(ctx context.Context, id, tenantID string) error {
+	log.Printf("DEBUG: incident_service.DeleteAlertSource id=%s tenant=%s", id, tenantID)
+
+	if _, err := s.getAlertSource(ctx, id, tenantID); err != nil {
+		return err
+	}
+
+	if err := s.incidentRepo.DeleteAlertSource(ctx, id, tenantID); err != nil {
+		log.Printf("ERROR: incident_service.DeleteAlertSource DeleteAlertSource: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetIncidentAlerts returns the alerts attached to an incident
+func (s *IncidentService) GetIncidentAlerts(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentAlert, error) {
+	log.Printf("DEBUG: incident_service.GetIncidentAlerts incident=%s", incidentID)
+
+	// Verify incident exists
+	if _, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID); err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentAlerts GetByID: %v", err)
+		return nil, err
+	}
+
+	alerts, err := s.incidentRepo.ListAlerts(ctx, incidentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentAlerts ListAlerts: %v", err)
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (s *IncidentService) getAlertSource(ctx context.Context, id, tenantID string) (*repo.IncidentAlertSource, error) {
+	source, err := s.incidentRepo.GetAlertSource(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.getAlertSource GetAlertSource: %v", err)
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrAlertSourceNotFound
+	}
+	return source, nil
+}
+
+func (s *IncidentService) applyAlertSourceRequest(ctx context.Context, source *repo.IncidentAlertSource, req dto.IncidentAlertSourceRequest) error {
+	if req.AssignedTo != nil && *req.AssignedTo != "" {
+		user, err := s.userRepo.GetByID(ctx, *req.AssignedTo)
+		if err != nil {
+			log.Printf("ERROR: incident_service.applyAlertSourceRequest GetByID assigned user: %v", err)
+			return err
+		}
+		if user == nil {
+			return NewValidationError("assigned_to", "assigned user not found")
+		}
+		source.AssignedTo = req.AssignedTo
+	} else {
+		source.AssignedTo = nil
+	}
+
+	source.Name = req.Name
+	source.Format = req.Format
+	source.IncidentSource = req.IncidentSource
+	if source.IncidentSource == "" {
+		source.IncidentSource = dto.IncidentSourceSIEM
+	}
+	source.FieldMapping = req.FieldMapping
+	if source.FieldMapping == nil {
+		source.FieldMapping = map[string]string{}
+	}
+	// Значения важности сравниваются без учёта регистра
+	source.SeverityMapping = make(map[string]string, len(req.SeverityMapping))
+	for value, criticality := range req.SeverityMapping {
+		source.SeverityMapping[strings.ToLower(strings.TrimSpace(value))] = criticality
+	}
+	source.CorrelationFields = req.CorrelationFields
+	if len(source.CorrelationFields) == 0 {
+		source.CorrelationFields = defaultAlertCorrelationFields
+	}
+	source.DedupWindowMinutes = req.DedupWindowMinutes
+	if source.DedupWindowMinutes == 0 {
+		source.DedupWindowMinutes = 24 * 60
+	}
+	source.DefaultCategory = req.DefaultCategory
+	if source.DefaultCategory == "" {
+		source.DefaultCategory = dto.IncidentCategoryOther
+	}
+	source.DefaultCriticality = req.DefaultCriticality
+	if source.DefaultCriticality == "" {
+		source.DefaultCriticality = dto.IncidentCriticalityMedium
+	}
+	if req.IsActive != nil {
+		source.IsActive = *req.IsActive
+	}
+	source.UpdatedAt = time.Now()
+	return nil
+}
+
+// Ingestion
+
+// IngestAlerts authenticates the source by token, converts the alerts to incidents and attaches
+// repeated alerts to the open incident with the same correlation key
+func (s *IncidentService) IngestAlerts(ctx context.Context, token string, body []byte) (*dto.IncidentAlertIngestResponse, error) {
+	if token == "" {
+		return nil, ErrAlertSourceUnauthorized
+	}
+	source, err := s.incidentRepo.GetAlertSourceByTokenHash(ctx, hashAccessToken(token))
+	if err != nil {
+		log.Printf("ERROR: incident_service.IngestAlerts GetAlertSourceByTokenHash: %v", err)
+		return nil, err
+	}
+	if source == nil || !source.IsActive {
+		log.Printf("WARN: incident_service.IngestAlerts rejected unknown or inactive token")
+		return nil, ErrAlertSourceUnauthorized
+	}
+	log.Printf("DEBUG: incident_service.IngestAlerts source=%s tenant=%s format=%s", source.ID, source.TenantID, source.Format)
+
+	alerts, err := parseAlertPayload(source.Format, body)
+	if err != nil {
+		log.Printf("WARN: incident_service.IngestAlerts parse: %v", err)
+		return nil, err
+	}
+
+	// Пакеты обрабатываются последовательно, чтобы одновременные оповещения с одним ключом не создали два инцидента
+	s.ingestMu.Lock()
+	defer s.ingestMu.Unlock()
+
+	response := &dto.IncidentAlertIngestResponse{
+		Received: len(alerts),
+		Results:  make([]dto.IncidentAlertResult, 0, len(alerts)),
+	}
+	for i, alert := range alerts {
+		result, err := s.ingestAlert(ctx, source, alert)
+		if err != nil {
+			log.Printf("ERROR: incident_service.IngestAlerts alert %d: %v", i+1, err)
+			response.Failed++
+			response.Errors = append(response.Errors, fmt.Sprintf("alert %d: %v", i+1, err))
+			continue
+		}
+		if result.Duplicate {
+			response.Duplicates++
+		} else {
+			response.Created++
+		}
+		response.Results = append(response.Results, *result)
+	}
+
+	log.Printf("INFO: incident_service.IngestAlerts source=%s received=%d created=%d duplicates=%d failed=%d",
+		source.ID, response.Received, response.Created, response.Duplicates, response.Failed)
+	return response, nil
+}
+
+func (s *IncidentService) ingestAlert(ctx context.Context, source *repo.IncidentAlertSource, alert parsedAlert) (*dto.IncidentAlertResult, error) {
+	now := time.Now()
+	normalized := normalizeAlert(source, alert.fields, now)
+	correlationKey := alertCorrelationKey(source, normalized, alert.fields)
+
+	assets, err := s.assetRepo.FindByNetworkIdentity(ctx, source.TenantID, normalized.ips, normalized.hostnames)
+	if err != nil {
+		return nil, err
+	}
+	assetIDs := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		assetIDs = append(assetIDs, asset.ID)
+	}
+
+	since := now.Add(-time.Duration(source.DedupWindowMinutes) * time.Minute)
+	incidentID, err := s.incidentRepo.FindOpenIncidentByCorrelation(ctx, source.TenantID, correlationKey, since)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicate := incidentID != ""
+	if duplicate {
+		s.linkAlertAssets(ctx, incidentID, assetIDs)
+	} else {
+		req := dto.CreateIncidentRequest{IncidentRequest: dto.IncidentRequest{
+			Title:       normalized.title,
+			Category:    normalized.category,
+			Criticality: normalized.criticality,
+			Source:      source.IncidentSource,
+			AssetIDs:    assetIDs,
+			AssignedTo:  source.AssignedTo,
+			DetectedAt:  &normalized.detectedAt,
+		}}
+		if normalized.description != "" {
+			req.Description = &normalized.description
+		}
+		incident, err := s.CreateIncident(ctx, source.TenantID, req, source.ReporterID)
+		if err != nil {
+			return nil, err
+		}
+		incidentID = incident.ID
+	}
+
+	record := &repo.IncidentAlert{
+		ID:             uuid.New().String(),
+		TenantID:       source.TenantID,
+		SourceID:       source.ID,
+		IncidentID:     &incidentID,
+		CorrelationKey: correlationKey,
+		Title:          normalized.title,
+		Criticality:    normalized.criticality,
+		Fields:         alert.fields,
+		RawPayload:     truncateRunes(alert.raw, maxAlertRawPayload),
+		IsDuplicate:    duplicate,
+		ReceivedAt:     now,
+	}
+	if normalized.severity != "" {
+		record.Severity = &normalized.severity
+	}
+	if err := s.incidentRepo.AddAlert(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return &dto.IncidentAlertResult{
+		AlertID:     record.ID,
+		IncidentID:  incidentID,
+		Duplicate:   duplicate,
+		Title:       normalized.title,
+		Criticality: normalized.criticality,
+		AssetIDs:    assetIDs,
+	}, nil
+}
+
+// linkAlertAssets adds assets seen in a repeated alert that are not yet linked to the incident
+func (s *IncidentService) linkAlertAssets(ctx context.Context, incidentID string, assetIDs []string) {
+	if len(assetIDs) == 0 {
+		return
+	}
+	current, err := s.incidentRepo.GetAssets(ctx, incidentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.linkAlertAssets GetAssets: %v", err)
+		return
+	}
+	linked := make(map[string]bool, len(current))
+	for _, asset := range current {
+		linked[asset.ID] = true
+	}
+	for _, assetID := range assetIDs {
+		if linked[assetID] {
+			continue
+		}
+		if err := s.incidentRepo.AddAsset(ctx, incidentID, assetID); err != nil {
+			log.Printf("ERROR: incident_service.linkAlertAssets AddAsset: %v", err)
+		}
+	}
+}
+
+// normalizeAlert applies the source mapping rules to the alert fields
+func normalizeAlert(source *repo.IncidentAlertSource, fields map[string]string, now time.Time) normalizedAlert {
+	paths := func(target string) []string {
+		mapping, ok := source.FieldMapping[target]
+		if !ok {
+			mapping = defaultAlertFieldMapping[source.Format][target]
+		}
+		var result []string
+		for _, path := range strings.Split(mapping, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				result = append(result, path)
+			}
+		}
+		return result
+	}
+	first := func(target string) string {
+		for _, path := range paths(target) {
+			if value := strings.TrimSpace(fields[path]); value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	all := func(target string) []string {
+		seen := make(map[string]bool)
+		var values []string
+		for _, path := range paths(target) {
+			for _, value := range strings.Split(fields[path], ",") {
+				value = strings.TrimSpace(value)
+				if value != "" && !seen[value] {
+					seen[value] = true
+					values = append(values, value)
+				}
+			}
+		}
+		return values
+	}
+
+	alert := normalizedAlert{
+		title:       first(dto.AlertFieldTitle),
+		description: truncateRunes(first(dto.AlertFieldDescription), 2000),
+		severity:    first(dto.AlertFieldSeverity),
+		category:    strings.ToLower(first(dto.AlertFieldCategory)),
+		detectedAt:  now,
+	}
+
+	if alert.title == "" {
+		alert.title = alert.description
+	}
+	if alert.title == "" {
+		alert.title = "Оповещение от " + source.Name
+	}
+	alert.title = truncateRunes(strings.Join(strings.Fields(alert.title), " "), 255)
+
+	if !incidentCategories[alert.category] {
+		alert.category = source.DefaultCategory
+	}
+	alert.criticality = alertCriticality(alert.severity, source)
+
+	for _, value := range all(dto.AlertFieldIP) {
+		if ip := net.ParseIP(value); ip != nil {
+			alert.ips = append(alert.ips, ip.String())
+		}
+	}
+	// Источники часто передают IP-адрес вместо имени узла
+	for _, value := range all(dto.AlertFieldHostname) {
+		if ip := net.ParseIP(value); ip != nil {
+			alert.ips = append(alert.ips, ip.String())
+		} else {
+			alert.hostnames = append(alert.hostnames, value)
+		}
+	}
+
+	if value := first(dto.AlertFieldDetectedAt); value != "" {
+		// Время из будущего (рассинхронизация часов) не принимается
+		if detectedAt, ok := parseAlertTime(value, now); ok && !detectedAt.After(now) {
+			alert.detectedAt = detectedAt
+		}
+	}
+	return alert
+}
+
+// alertCriticality converts an alert severity using the source mapping, CEF 0-10 numbers or common keywords
+func alertCriticality(severity string, source *repo.IncidentAlertSource) string {
+	value := strings.ToLower(strings.TrimSpace(severity))
+	if value == "" {
+		return source.DefaultCriticality
+	}
+	if criticality, ok := source.SeverityMapping[value]; ok {
+		return criticality
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		switch {
+		case number >= 9:
+			return dto.IncidentCriticalityCritical
+		case number >= 7:
+			return dto.IncidentCriticalityHigh
+		case number >= 4:
+			return dto.IncidentCriticalityMedium
+		default:
+			return dto.IncidentCriticalityLow
+		}
+	}
+	if criticality, ok := alertSeverityKeywords[value]; ok {
+		return criticality
+	}
+	return source.DefaultCriticality
+}
+
+// alertCorrelationKey hashes the configured correlation fields; incident field names take the mapped
+// value, anything else is read from the alert as a path
+func alertCorrelationKey(source *repo.IncidentAlertSource, alert normalizedAlert, fields map[string]string) string {
+	parts := []string{source.ID}
+	for _, field := range source.CorrelationFields {
+		var value string
+		switch field {
+		case dto.AlertFieldTitle:
+			value = strings.ToLower(alert.title)
+		case dto.AlertFieldSeverity:
+			value = alert.criticality
+		case dto.AlertFieldCategory:
+			value = alert.category
+		case dto.AlertFieldHostname:
+			value = sortedLowerJoin(alert.hostnames)
+		case dto.AlertFieldIP:
+			value = sortedLowerJoin(alert.ips)
+		default:
+			value = fields[field]
+		}
+		parts = append(parts, field+"="+value)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedLowerJoin(values []string) string {
+	lowered := make([]string, 0, len(values))
+	for _, value := range values {
+		lowered = append(lowered, strings.ToLower(value))
+	}
+	sort.Strings(lowered)
+	return strings.Join(lowered, ",")
+}
+
+func truncateRunes(value string, limit int) string {
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	runes := []rune(value)
+	return string(runes[:limit])
+}
@@ -0,0 +1,360 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+)
+
+// maxAlertsPerRequest limits the size of one ingestion batch
+const maxAlertsPerRequest = 500
+
+// parsedAlert is an alert flattened to dotted field paths together with its original text
+type parsedAlert struct {
+	fields map[string]string
+	raw    string
+}
+
+// parseAlertPayload splits a request body into alerts of the given format
+func parseAlertPayload(format string, body []byte) ([]parsedAlert, error) {
+	body = bytes.TrimPrefix(bytes.TrimSpace(body), []byte("\xef\xbb\xbf"))
+	if len(body) == 0 {
+		return nil, fmt.Errorf("%w: empty body", ErrInvalidAlertPayload)
+	}
+
+	var alerts []parsedAlert
+	var err error
+	switch format {
+	case dto.AlertFormatJSON:
+		alerts, err = parseJSONAlerts(body)
+	case dto.AlertFormatCEF:
+		alerts, err = parseAlertLines(body, parseCEFAlert)
+	case dto.AlertFormatSyslog:
+		alerts, err = parseAlertLines(body, parseSyslogAlert)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidAlertPayload, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(alerts) == 0 {
+		return nil, fmt.Errorf("%w: no alerts found", ErrInvalidAlertPayload)
+	}
+	if len(alerts) > maxAlertsPerRequest {
+		return nil, fmt.Errorf("%w: too many alerts in one request (%d, max %d)", ErrInvalidAlertPayload, len(alerts), maxAlertsPerRequest)
+	}
+	return alerts, nil
+}
+
+// parseJSONAlerts accepts an object, an array of objects, {"alerts": [...]} or newline-delimited JSON
+func parseJSONAlerts(body []byte) ([]parsedAlert, error) {
+	var document interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return parseAlertLines(body, parseJSONAlert)
+	}
+
+	if object, ok := document.(map[string]interface{}); ok {
+		if nested, ok := object["alerts"].([]interface{}); ok {
+			document = nested
+		}
+	}
+
+	var items []interface{}
+	switch value := document.(type) {
+	case []interface{}:
+		items = value
+	case map[string]interface{}:
+		items = []interface{}{value}
+	default:
+		return nil, fmt.Errorf("%w: expected a JSON object or array", ErrInvalidAlertPayload)
+	}
+
+	alerts := make([]parsedAlert, 0, len(items))
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: alert %d is not a JSON object", ErrInvalidAlertPayload, i+1)
+		}
+		raw, _ := json.Marshal(object)
+		fields := make(map[string]string)
+		flattenAlertJSON("", object, fields)
+		alerts = append(alerts, parsedAlert{fields: fields, raw: string(raw)})
+	}
+	return alerts, nil
+}
+
+func parseJSONAlert(line string) (map[string]string, error) {
+	var object map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string)
+	flattenAlertJSON("", object, fields)
+	return fields, nil
+}
+
+// flattenAlertJSON turns nested objects into dotted paths, e.g. {"host": {"ip": "..."}} -> host.ip
+func flattenAlertJSON(prefix string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenAlertJSON(path, item, fields)
+		}
+	case []interface{}:
+		scalars := make([]string, 0, len(v))
+		for i, item := range v {
+			flattenAlertJSON(fmt.Sprintf("%s.%d", prefix, i), item, fields)
+			if _, ok := item.(map[string]interface{}); !ok {
+				if _, ok := item.([]interface{}); !ok && item != nil {
+					scalars = append(scalars, fmt.Sprint(item))
+				}
+			}
+		}
+		// Массив простых значений доступен и целиком: "ips": ["a", "b"] -> ips = "a,b"
+		if len(scalars) > 0 {
+			fields[prefix] = strings.Join(scalars, ",")
+		}
+	case nil:
+	default:
+		fields[prefix] = fmt.Sprint(v)
+	}
+}
+
+// parseAlertLines parses newline-delimited alerts, one per non-empty line
+func parseAlertLines(body []byte, parse func(line string) (map[string]string, error)) ([]parsedAlert, error) {
+	var alerts []parsedAlert
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields, err := parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidAlertPayload, lineNumber, err)
+		}
+		alerts = append(alerts, parsedAlert{fields: fields, raw: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlertPayload, err)
+	}
+	return alerts, nil
+}
+
+var cefHeaderFields = []string{"version", "device_vendor", "device_product", "device_version", "signature_id", "name", "severity"}
+
+// cefExtensionKey matches the start of a key=value pair; keys cannot contain a backslash, so escaped \= never matches
+var cefExtensionKey = regexp.MustCompile(`(?:^|\s)([A-Za-z0-9_.\-\[\]]+)=`)
+
+// parseCEFAlert parses "CEF:Version|Vendor|Product|Version|SignatureID|Name|Severity|Extension",
+// optionally preceded by a syslog header
+func parseCEFAlert(line string) (map[string]string, error) {
+	start := strings.Index(line, "CEF:")
+	if start < 0 {
+		return nil, fmt.Errorf("CEF header not found")
+	}
+	line = line[start+len("CEF:"):]
+
+	fields := make(map[string]string)
+	var current strings.Builder
+	headerIndex := 0
+	i := 0
+	for ; i < len(line) && headerIndex < len(cefHeaderFields); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && (line[i+1] == '|' || line[i+1] == '\\'):
+			current.WriteByte(line[i+1])
+			i++
+		case line[i] == '|':
+			fields[cefHeaderFields[headerIndex]] = strings.TrimSpace(current.String())
+			current.Reset()
+			headerIndex++
+		default:
+			current.WriteByte(line[i])
+		}
+	}
+	if headerIndex < len(cefHeaderFields) {
+		return nil, fmt.Errorf("CEF header has %d of %d fields", headerIndex, len(cefHeaderFields))
+	}
+
+	extension := line[i:]
+	matches := cefExtensionKey.FindAllStringSubmatchIndex(extension, -1)
+	for n, match := range matches {
+		key := extension[match[2]:match[3]]
+		valueEnd := len(extension)
+		if n+1 < len(matches) {
+			valueEnd = matches[n+1][0]
+		}
+		fields[key] = unescapeCEFValue(strings.TrimSpace(extension[match[1]:valueEnd]))
+	}
+	return fields, nil
+}
+
+func unescapeCEFValue(value string) string {
+	replacer := strings.NewReplacer(`\=`, "=", `\\`, `\`, `\n`, "\n", `\r`, "\r")
+	return replacer.Replace(value)
+}
+
+var syslogSeverityNames = []string{"emergency", "alert", "critical", "error", "warning", "notice", "informational", "debug"}
+
+// parseSyslogAlert parses RFC 5424 and RFC 3164 messages; a CEF message body is parsed into cef.* fields
+func parseSyslogAlert(line string) (map[string]string, error) {
+	if !strings.HasPrefix(line, "<") {
+		return nil, fmt.Errorf("syslog priority not found")
+	}
+	end := strings.Index(line, ">")
+	if end < 2 || end > 4 {
+		return nil, fmt.Errorf("invalid syslog priority")
+	}
+	priority, err := strconv.Atoi(line[1:end])
+	if err != nil || priority > 191 {
+		return nil, fmt.Errorf("invalid syslog priority")
+	}
+
+	fields := map[string]string{
+		"facility":      strconv.Itoa(priority / 8),
+		"severity_code": strconv.Itoa(priority % 8),
+		"severity":      syslogSeverityNames[priority%8],
+	}
+	rest := line[end+1:]
+
+	if strings.HasPrefix(rest, "1 ") {
+		// RFC 5424: VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		parts := strings.SplitN(rest[2:], " ", 5)
+		if len(parts) < 5 {
+			return nil, fmt.Errorf("incomplete RFC 5424 header")
+		}
+		names := []string{"timestamp", "hostname", "app_name", "proc_id"}
+		for n, name := range names {
+			if parts[n] != "-" {
+				fields[name] = parts[n]
+			}
+		}
+		remainder := parts[4]
+		if idx := strings.Index(remainder, " "); idx >= 0 {
+			fields["msg_id"] = remainder[:idx]
+			remainder = remainder[idx+1:]
+		} else {
+			fields["msg_id"] = remainder
+			remainder = ""
+		}
+		if fields["msg_id"] == "-" {
+			delete(fields, "msg_id")
+		}
+		structuredData, message := splitSyslogStructuredData(remainder)
+		if structuredData != "" {
+			fields["structured_data"] = structuredData
+		}
+		fields["message"] = strings.TrimPrefix(message, "\xef\xbb\xbf")
+	} else {
+		// RFC 3164: Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+		if len(rest) >= 16 {
+			if _, err := time.Parse(time.Stamp, rest[:15]); err == nil {
+				fields["timestamp"] = rest[:15]
+				rest = strings.TrimSpace(rest[16:])
+				if idx := strings.Index(rest, " "); idx > 0 {
+					fields["hostname"] = rest[:idx]
+					rest = rest[idx+1:]
+				}
+			}
+		}
+		if idx := strings.Index(rest, ": "); idx > 0 && !strings.Contains(rest[:idx], " ") {
+			tag := rest[:idx]
+			if pid := strings.Index(tag, "["); pid > 0 && strings.HasSuffix(tag, "]") {
+				fields["proc_id"] = tag[pid+1 : len(tag)-1]
+				tag = tag[:pid]
+			}
+			fields["app_name"] = tag
+			rest = rest[idx+2:]
+		}
+		fields["message"] = rest
+	}
+
+	if strings.Contains(fields["message"], "CEF:") {
+		if cef, err := parseCEFAlert(fields["message"]); err == nil {
+			for key, value := range cef {
+				fields["cef."+key] = value
+			}
+		}
+	}
+	return fields, nil
+}
+
+// splitSyslogStructuredData separates "[id k="v"][...] message" or "- message"
+func splitSyslogStructuredData(value string) (string, string) {
+	if strings.HasPrefix(value, "- ") || value == "-" {
+		return "", strings.TrimPrefix(strings.TrimPrefix(value, "-"), " ")
+	}
+	if !strings.HasPrefix(value, "[") {
+		return "", value
+	}
+	depth, escaped, inQuotes := 0, false, false
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == '[' && !inQuotes:
+			depth++
+		case r == ']' && !inQuotes:
+			depth--
+			if depth == 0 && (i+1 == len(value) || value[i+1] != '[') {
+				return value[:i+1], strings.TrimPrefix(value[i+1:], " ")
+			}
+		}
+	}
+	return value, ""
+}
+
+// parseAlertTime understands RFC 3339, common SIEM layouts, syslog timestamps and Unix time in seconds or milliseconds
+func parseAlertTime(value string, now time.Time) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if number > 1e12 {
+			return time.UnixMilli(number), true
+		}
+		return time.Unix(number, 0), true
+	}
+
+	layouts := []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "Jan 02 2006 15:04:05", "Jan _2 2006 15:04:05"}
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+
+	// Метка времени RFC 3164 не содержит года
+	if parsed, err := time.ParseInLocation(time.Stamp, value, now.Location()); err == nil {
+		parsed = parsed.AddDate(now.Year(), 0, 0)
+		if parsed.After(now.Add(24 * time.Hour)) {
+			parsed = parsed.AddDate(-1, 0, 0)
+		}
+		return parsed, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,341 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAlertPayload_JSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []map[string]string
+	}{
+		{
+			name:       "single object with nesting",
+			body:       `{"title":"Brute force","host":{"name":"srv-01","ip":"10.0.0.5"},"count":3}`,
+			wantFields: []map[string]string{{"title": "Brute force", "host.name": "srv-01", "host.ip": "10.0.0.5", "count": "3"}},
+		},
+		{
+			name: "array",
+			body: `[{"title":"A"},{"title":"B"}]`,
+			wantFields: []map[string]string{
+				{"title": "A"},
+				{"title": "B"},
+			},
+		},
+		{
+			name:       "alerts wrapper",
+			body:       `{"alerts":[{"title":"Wrapped","ips":["10.0.0.1","10.0.0.2"]}]}`,
+			wantFields: []map[string]string{{"title": "Wrapped", "ips": "10.0.0.1,10.0.0.2", "ips.0": "10.0.0.1", "ips.1": "10.0.0.2"}},
+		},
+		{
+			name: "newline-delimited",
+			body: "{\"title\":\"first\"}\n\n{\"title\":\"second\",\"empty\":null}\n",
+			wantFields: []map[string]string{
+				{"title": "first"},
+				{"title": "second"},
+			},
+		},
+		{
+			name:       "byte order mark",
+			body:       "\xef\xbb\xbf{\"title\":\"bom\"}",
+			wantFields: []map[string]string{{"title": "bom"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, err := parseAlertPayload(dto.AlertFormatJSON, []byte(tt.body))
+			require.NoError(t, err)
+			require.Len(t, alerts, len(tt.wantFields))
+			for i, want := range tt.wantFields {
+				assert.Equal(t, want, alerts[i].fields)
+				assert.NotEmpty(t, alerts[i].raw)
+			}
+		})
+	}
+}
+
+func TestParseAlertPayload_Errors(t *testing.T) {
+	tooMany := strings.Repeat("{\"title\":\"x\"}\n", maxAlertsPerRequest+1)
+
+	tests := []struct {
+		name   string
+		format string
+		body   string
+	}{
+		{"empty body", dto.AlertFormatJSON, "  \n "},
+		{"unsupported format", "xml", "<alert/>"},
+		{"json scalar", dto.AlertFormatJSON, `"text"`},
+		{"json array of scalars", dto.AlertFormatJSON, `[1, 2]`},
+		{"broken ndjson line", dto.AlertFormatJSON, "{\"title\":\"ok\"}\n{broken"},
+		{"empty alerts wrapper", dto.AlertFormatJSON, `{"alerts":[]}`},
+		{"cef without header", dto.AlertFormatCEF, "just text"},
+		{"cef short header", dto.AlertFormatCEF, "CEF:0|Vendor|Product"},
+		{"syslog without priority", dto.AlertFormatSyslog, "Mar  1 10:00:00 host app: msg"},
+		{"syslog priority out of range", dto.AlertFormatSyslog, "<200>1 - - - - - - msg"},
+		{"too many alerts", dto.AlertFormatJSON, tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseAlertPayload(tt.format, []byte(tt.body))
+			assert.ErrorIs(t, err, ErrInvalidAlertPayload)
+		})
+	}
+}
+
+func TestParseCEFAlert(t *testing.T) {
+	line := `Mar  1 10:00:00 fw01 CEF:0|Security|IDS\|Pro|1.0|100|Port scan detected|8|src=10.0.0.1 dst=10.0.0.2 msg=Scan of ports 1\=65535 from host cs1Label=Rule name cs1=Block all`
+
+	fields, err := parseCEFAlert(line)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"version":        "0",
+		"device_vendor":  "Security",
+		"device_product": "IDS|Pro",
+		"device_version": "1.0",
+		"signature_id":   "100",
+		"name":           "Port scan detected",
+		"severity":       "8",
+		"src":            "10.0.0.1",
+		"dst":            "10.0.0.2",
+		"msg":            "Scan of ports 1=65535 from host",
+		"cs1Label":       "Rule name",
+		"cs1":            "Block all",
+	}, fields)
+}
+
+func TestParseSyslogAlert(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want map[string]string
+	}{
+		{
+			name: "rfc 5424 with structured data",
+			line: `<165>1 2026-03-01T10:00:00Z host01 sshd 4321 ID47 [exampleSDID@32473 iut="3" eventSource="App"] Failed password`,
+			want: map[string]string{
+				"facility": "20", "severity_code": "5", "severity": "notice",
+				"timestamp": "2026-03-01T10:00:00Z", "hostname": "host01", "app_name": "sshd", "proc_id": "4321",
+				"msg_id": "ID47", "structured_data": `[exampleSDID@32473 iut="3" eventSource="App"]`, "message": "Failed password",
+			},
+		},
+		{
+			name: "rfc 5424 nil values",
+			line: "<11>1 - - - - - - disk failure",
+			want: map[string]string{
+				"facility": "1", "severity_code": "3", "severity": "error", "message": "disk failure",
+			},
+		},
+		{
+			name: "rfc 3164",
+			line: "<34>Oct 11 22:14:15 mymachine su[230]: 'su root' failed",
+			want: map[string]string{
+				"facility": "4", "severity_code": "2", "severity": "critical",
+				"timestamp": "Oct 11 22:14:15", "hostname": "mymachine", "app_name": "su", "proc_id": "230",
+				"message": "'su root' failed",
+			},
+		},
+		{
+			name: "rfc 3164 without header",
+			line: "<13>plain message text",
+			want: map[string]string{
+				"facility": "1", "severity_code": "5", "severity": "notice", "message": "plain message text",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := parseSyslogAlert(tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestParseSyslogAlert_CEFBody(t *testing.T) {
+	fields, err := parseSyslogAlert("<132>Mar  1 10:00:00 fw01 CEF:0|Vendor|FW|1|42|Blocked connection|5|src=192.168.1.7")
+	require.NoError(t, err)
+
+	assert.Equal(t, "fw01", fields["hostname"])
+	assert.Equal(t, "Blocked connection", fields["cef.name"])
+	assert.Equal(t, "5", fields["cef.severity"])
+	assert.Equal(t, "192.168.1.7", fields["cef.src"])
+}
+
+func TestParseAlertTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Time
+		wantOK bool
+	}{
+		{"2026-03-10T09:30:00Z", time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC), true},
+		{"2026-03-10T09:30:00.123+03:00", time.Date(2026, 3, 10, 6, 30, 0, 123e6, time.UTC), true},
+		{"2026-03-10 09:30:00", time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC), true},
+		{"1773135000", time.Unix(1773135000, 0), true},
+		{"1773135000123", time.UnixMilli(1773135000123), true},
+		{"Mar 10 2026 09:30:00", time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC), true},
+		{"Mar  9 23:00:00", time.Date(2026, 3, 9, 23, 0, 0, 0, time.UTC), true},
+		{"Dec 31 23:00:00", time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC), true},
+		{"", time.Time{}, false},
+		{"yesterday", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseAlertTime(tt.value, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
+
+func TestAlertCriticality(t *testing.T) {
+	source := &repo.IncidentAlertSource{
+		DefaultCriticality: dto.IncidentCriticalityMedium,
+		SeverityMapping:    map[string]string{"p1": dto.IncidentCriticalityCritical, "high": dto.IncidentCriticalityCritical},
+	}
+
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{"", dto.IncidentCriticalityMedium},
+		{"P1", dto.IncidentCriticalityCritical},
+		{"high", dto.IncidentCriticalityCritical},
+		{"10", dto.IncidentCriticalityCritical},
+		{"9", dto.IncidentCriticalityCritical},
+		{"7.5", dto.IncidentCriticalityHigh},
+		{"4", dto.IncidentCriticalityMedium},
+		{"0", dto.IncidentCriticalityLow},
+		{"Error", dto.IncidentCriticalityHigh},
+		{"warning", dto.IncidentCriticalityMedium},
+		{"informational", dto.IncidentCriticalityLow},
+		{"unheard-of", dto.IncidentCriticalityMedium},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.severity), func(t *testing.T) {
+			assert.Equal(t, tt.want, alertCriticality(tt.severity, source))
+		})
+	}
+}
+
+func TestNormalizeAlert(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	source := &repo.IncidentAlertSource{
+		Name:               "Wazuh",
+		Format:             dto.AlertFormatJSON,
+		FieldMapping:       map[string]string{dto.AlertFieldTitle: "rule.description"},
+		DefaultCategory:    dto.IncidentCategoryOther,
+		DefaultCriticality: dto.IncidentCriticalityLow,
+	}
+
+	t.Run("mapped fields", func(t *testing.T) {
+		alert := normalizeAlert(source, map[string]string{
+			"rule.description": "  Multiple   authentication failures ",
+			"title":            "ignored because of the custom mapping",
+			"severity":         "8",
+			"category":         "Malware",
+			"host":             "10.0.0.9",
+			"host.name":        "web-01",
+			"src_ip":           "10.0.0.1,not-an-ip",
+			"timestamp":        "2026-03-10T11:00:00Z",
+		}, now)
+
+		assert.Equal(t, "Multiple authentication failures", alert.title)
+		assert.Equal(t, dto.IncidentCriticalityHigh, alert.criticality)
+		assert.Equal(t, dto.IncidentCategoryMalware, alert.category)
+		assert.Equal(t, []string{"web-01"}, alert.hostnames)
+		assert.Equal(t, []string{"10.0.0.1", "10.0.0.9"}, alert.ips)
+		assert.Equal(t, time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC), alert.detectedAt)
+	})
+
+	t.Run("fallbacks", func(t *testing.T) {
+		alert := normalizeAlert(source, map[string]string{
+			"category":  "unknown",
+			"timestamp": "2026-03-11T11:00:00Z",
+		}, now)
+
+		assert.Equal(t, "Оповещение от Wazuh", alert.title)
+		assert.Equal(t, dto.IncidentCriticalityLow, alert.criticality)
+		assert.Equal(t, dto.IncidentCategoryOther, alert.category)
+		assert.Equal(t, now, alert.detectedAt, "time from the future is ignored")
+	})
+
+	t.Run("description becomes the title", func(t *testing.T) {
+		alert := normalizeAlert(source, map[string]string{"message": "Disk is full"}, now)
+		assert.Equal(t, "Disk is full", alert.title)
+		assert.Equal(t, "Disk is full", alert.description)
+	})
+}
+
+func TestAlertCorrelationKey(t *testing.T) {
+	source := &repo.IncidentAlertSource{ID: "source-1", CorrelationFields: defaultAlertCorrelationFields}
+	base := normalizedAlert{title: "Brute force", hostnames: []string{"srv-01", "srv-02"}, ips: []string{"10.0.0.1"}}
+	key := alertCorrelationKey(source, base, nil)
+
+	tests := []struct {
+		name      string
+		source    *repo.IncidentAlertSource
+		alert     normalizedAlert
+		fields    map[string]string
+		wantEqual bool
+	}{
+		{
+			name:      "case and order do not matter",
+			source:    source,
+			alert:     normalizedAlert{title: "BRUTE FORCE", hostnames: []string{"SRV-02", "srv-01"}, ips: []string{"10.0.0.1"}},
+			wantEqual: true,
+		},
+		{
+			name:      "fields outside the correlation set are ignored",
+			source:    source,
+			alert:     normalizedAlert{title: "Brute force", hostnames: []string{"srv-01", "srv-02"}, ips: []string{"10.0.0.1"}, category: "malware"},
+			wantEqual: true,
+		},
+		{
+			name:   "different host",
+			source: source,
+			alert:  normalizedAlert{title: "Brute force", hostnames: []string{"srv-03"}, ips: []string{"10.0.0.1"}},
+		},
+		{
+			name:   "different source",
+			source: &repo.IncidentAlertSource{ID: "source-2", CorrelationFields: defaultAlertCorrelationFields},
+			alert:  base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := alertCorrelationKey(tt.source, tt.alert, tt.fields)
+			assert.Len(t, got, 64)
+			if tt.wantEqual {
+				assert.Equal(t, key, got)
+			} else {
+				assert.NotEqual(t, key, got)
+			}
+		})
+	}
+
+	t.Run("raw alert paths", func(t *testing.T) {
+		byRule := &repo.IncidentAlertSource{ID: "source-1", CorrelationFields: []string{"rule.id"}}
+		first := alertCorrelationKey(byRule, base, map[string]string{"rule.id": "5710"})
+		assert.Equal(t, first, alertCorrelationKey(byRule, normalizedAlert{title: "other"}, map[string]string{"rule.id": "5710"}))
+		assert.NotEqual(t, first, alertCorrelationKey(byRule, base, map[string]string{"rule.id": "5711"}))
+	})
+}
@@ -0,0 +1,365 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+func (s *IncidentService) ListIncidentEvidence(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentEvidence, error) {
+	log.Printf("DEBUG: incident_service.ListIncidentEvidence incident=%s", incidentID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListIncidentEvidence GetByID: %v", err)
+		return nil, err
+	}
+
+	items, err := s.incidentRepo.ListEvidence(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListIncidentEvidence ListEvidence: %v", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// CollectEvidence stores the file in document storage and records the SHA-256 computed on upload
+func (s *IncidentService) CollectEvidence(ctx context.Context, incidentID, tenantID string, file multipart.File, header *multipart.FileHeader, req dto.IncidentEvidenceRequest, userID, ipAddress string) (*repo.IncidentEvidence, error) {
+	log.Printf("DEBUG: incident_service.CollectEvidence incident=%s file=%s user=%s", incidentID, header.Filename, userID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.CollectEvidence GetByID: %v", err)
+		return nil, err
+	}
+
+	collectedAt := time.Now()
+	if req.CollectedAt != nil {
+		collectedAt, err = time.Parse(time.RFC3339, *req.CollectedAt)
+		if err != nil {
+			return nil, NewValidationError("collected_at", "invalid date format")
+		}
+		if collectedAt.After(time.Now()) {
+			return nil, NewValidationError("collected_at", "collection time cannot be in the future")
+		}
+	}
+
+	uploadReq := dto.UploadDocumentDTO{
+		Name:        fmt.Sprintf("%s - %s", incident.Title, req.Title),
+		Description: req.Description,
+		Tags:        []string{"#инциденты", "#доказательства"},
+		LinkedTo: &dto.DocumentLinkDTO{
+			Module:   "incidents",
+			EntityID: incident.ID,
+		},
+	}
+	document, err := s.documentStorageService.UploadDocument(ctx, tenantID, file, header, uploadReq, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.CollectEvidence UploadDocument: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	evidence := &repo.IncidentEvidence{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		IncidentID:   incident.ID,
+		DocumentID:   document.ID,
+		Title:        strings.TrimSpace(req.Title),
+		Description:  req.Description,
+		EvidenceType: req.EvidenceType,
+		Source:       req.Source,
+		FileName:     header.Filename,
+		FileSize:     document.FileSize,
+		SHA256:       document.FileHash,
+		CollectedBy:  &userID,
+		CollectedAt:  collectedAt,
+		CustodianID:  &userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	entry := newEvidenceCustody(evidence, dto.EvidenceCustodyCollected, userID, ipAddress)
+	entry.SHA256 = &evidence.SHA256
+	entry.Details = incidentStringPtr(fmt.Sprintf("%s (%d bytes)", evidence.FileName, evidence.FileSize))
+
+	if err := s.incidentRepo.CreateEvidence(ctx, evidence, entry); err != nil {
+		log.Printf("ERROR: incident_service.CollectEvidence CreateEvidence: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.CollectEvidence collected evidence=%s sha256=%s", evidence.ID, evidence.SHA256)
+	return evidence, nil
+}
+
+// GetEvidence returns the item with its chain of custody; the view itself is recorded as an access
+func (s *IncidentService) GetEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*repo.IncidentEvidence, []*repo.IncidentEvidenceCustody, error) {
+	evidence, err := s.getEvidence(ctx, incidentID, evidenceID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.incidentRepo.AddEvidenceCustody(ctx, newEvidenceCustody(evidence, dto.EvidenceCustodyAccessed, userID, ipAddress)); err != nil {
+		log.Printf("ERROR: incident_service.GetEvidence AddEvidenceCustody: %v", err)
+		return nil, nil, err
+	}
+
+	custody, err := s.incidentRepo.GetEvidenceCustody(ctx, evidence.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetEvidence GetEvidenceCustody: %v", err)
+		return nil, nil, err
+	}
+	return evidence, custody, nil
+}
+
+func (s *IncidentService) UpdateEvidence(ctx context.Context, incidentID, evidenceID, tenantID string, req dto.IncidentEvidenceUpdateRequest, userID, ipAddress string) (*repo.IncidentEvidence, error) {
+	log.Printf("DEBUG: incident_service.UpdateEvidence evidence=%s user=%s", evidenceID, userID)
+
+	evidence, err := s.getEvidence(ctx, incidentID, evidenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if evidence.IsSealed {
+		return nil, ErrIncidentEvidenceSealed
+	}
+
+	var changed []string
+	if req.Title != nil {
+		evidence.Title = strings.TrimSpace(*req.Title)
+		changed = append(changed, "title")
+	}
+	if req.Description != nil {
+		evidence.Description = req.Description
+		changed = append(changed, "description")
+	}
+	if req.EvidenceType != nil {
+		evidence.EvidenceType = *req.EvidenceType
+		changed = append(changed, "evidence_type")
+	}
+	if req.Source != nil {
+		evidence.Source = req.Source
+		changed = append(changed, "source")
+	}
+	evidence.UpdatedAt = time.Now()
+
+	entry := newEvidenceCustody(evidence, dto.EvidenceCustodyUpdated, userID, ipAddress)
+	entry.Details = incidentStringPtr(strings.Join(changed, ", "))
+	if err := s.incidentRepo.UpdateEvidence(ctx, evidence, entry); err != nil {
+		log.Printf("ERROR: incident_service.UpdateEvidence UpdateEvidence: %v", err)
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// SealEvidence makes the item immutable after confirming the stored file still matches its hash
+func (s *IncidentService) SealEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*repo.IncidentEvidence, error) {
+	log.Printf("DEBUG: incident_service.SealEvidence evidence=%s user=%s", evidenceID, userID)
+
+	evidence, err := s.getEvidence(ctx, incidentID, evidenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if evidence.IsSealed {
+		return nil, ErrIncidentEvidenceSealed
+	}
+
+	status, actual := s.rehashEvidence(ctx, evidence)
+	if status != dto.EvidenceVerificationValid {
+		return nil, NewValidationError("sha256", fmt.Sprintf("stored file failed integrity check: %s", status))
+	}
+
+	now := time.Now()
+	evidence.IsSealed = true
+	evidence.SealedBy = &userID
+	evidence.SealedAt = &now
+	evidence.LastVerifiedAt = &now
+	evidence.LastVerificationStatus = &status
+	evidence.UpdatedAt = now
+
+	entry := newEvidenceCustody(evidence, dto.EvidenceCustodySealed, userID, ipAddress)
+	entry.SHA256 = actual
+	if err := s.incidentRepo.UpdateEvidence(ctx, evidence, entry); err != nil {
+		log.Printf("ERROR: incident_service.SealEvidence UpdateEvidence: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.SealEvidence sealed evidence=%s", evidence.ID)
+	return evidence, nil
+}
+
+// TransferEvidence hands the item over to another custodian of the same tenant
+func (s *IncidentService) TransferEvidence(ctx context.Context, incidentID, evidenceID, tenantID string, req dto.IncidentEvidenceTransferRequest, userID, ipAddress string) (*repo.IncidentEvidence, error) {
+	log.Printf("DEBUG: incident_service.TransferEvidence evidence=%s to=%s user=%s", evidenceID, req.ToUserID, userID)
+
+	evidence, err := s.getEvidence(ctx, incidentID, evidenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := s.userRepo.GetByID(ctx, req.ToUserID)
+	if err != nil || recipient == nil || recipient.TenantID != tenantID || !recipient.IsActive {
+		return nil, NewValidationError("to_user_id", "user not found")
+	}
+	if evidence.CustodianID != nil && *evidence.CustodianID == recipient.ID {
+		return nil, NewValidationError("to_user_id", "user is already the custodian")
+	}
+
+	entry := newEvidenceCustody(evidence, dto.EvidenceCustodyTransferred, userID, ipAddress)
+	entry.FromCustodianID = evidence.CustodianID
+	entry.ToCustodianID = &recipient.ID
+	entry.Details = req.Reason
+
+	evidence.CustodianID = &recipient.ID
+	evidence.UpdatedAt = time.Now()
+	if err := s.incidentRepo.UpdateEvidence(ctx, evidence, entry); err != nil {
+		log.Printf("ERROR: incident_service.TransferEvidence UpdateEvidence: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.TransferEvidence evidence=%s custodian=%s", evidence.ID, recipient.ID)
+	return evidence, nil
+}
+
+// ExportEvidence returns the stored file and records the export in the chain of custody
+func (s *IncidentService) ExportEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*repo.IncidentEvidence, *dto.DocumentDownloadDTO, error) {
+	log.Printf("DEBUG: incident_service.ExportEvidence evidence=%s user=%s", evidenceID, userID)
+
+	evidence, err := s.getEvidence(ctx, incidentID, evidenceID, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	download, err := s.documentStorageService.DownloadDocument(ctx, evidence.DocumentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ExportEvidence DownloadDocument: %v", err)
+		return nil, nil, err
+	}
+
+	entry := newEvidenceCustody(evidence, dto.EvidenceCustodyExported, userID, ipAddress)
+	actual, err := calculateFileHash(bytes.NewReader(download.Content))
+	if err == nil {
+		entry.SHA256 = &actual
+	}
+	if err := s.incidentRepo.AddEvidenceCustody(ctx, entry); err != nil {
+		log.Printf("ERROR: incident_service.ExportEvidence AddEvidenceCustody: %v", err)
+		return nil, nil, err
+	}
+
+	download.FileName = evidence.FileName
+	return evidence, download, nil
+}
+
+func (s *IncidentService) VerifyEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*dto.IncidentEvidenceVerificationResult, error) {
+	evidence, err := s.getEvidence(ctx, incidentID, evidenceID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.verifyEvidence(ctx, evidence, userID, ipAddress)
+}
+
+// VerifyIncidentEvidence rehashes every stored file of the incident
+func (s *IncidentService) VerifyIncidentEvidence(ctx context.Context, incidentID, tenantID, userID, ipAddress string) ([]dto.IncidentEvidenceVerificationResult, error) {
+	items, err := s.ListIncidentEvidence(ctx, incidentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.IncidentEvidenceVerificationResult, 0, len(items))
+	for _, evidence := range items {
+		result, err := s.verifyEvidence(ctx, evidence, userID, ipAddress)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+func (s *IncidentService) verifyEvidence(ctx context.Context, evidence *repo.IncidentEvidence, userID, ipAddress string) (*dto.IncidentEvidenceVerificationResult, error) {
+	log.Printf("DEBUG: incident_service.verifyEvidence evidence=%s", evidence.ID)
+
+	status, actual := s.rehashEvidence(ctx, evidence)
+	now := time.Now()
+	evidence.LastVerifiedAt = &now
+	evidence.LastVerificationStatus = &status
+	evidence.UpdatedAt = now
+
+	entry := newEvidenceCustody(evidence, dto.EvidenceCustodyVerified, userID, ipAddress)
+	entry.SHA256 = actual
+	entry.Details = &status
+	if err := s.incidentRepo.UpdateEvidence(ctx, evidence, entry); err != nil {
+		log.Printf("ERROR: incident_service.verifyEvidence UpdateEvidence: %v", err)
+		return nil, err
+	}
+
+	if status != dto.EvidenceVerificationValid {
+		log.Printf("WARN: incident_service.verifyEvidence evidence=%s integrity check failed: %s", evidence.ID, status)
+	}
+	return &dto.IncidentEvidenceVerificationResult{
+		EvidenceID:     evidence.ID,
+		Title:          evidence.Title,
+		Status:         status,
+		ExpectedSHA256: evidence.SHA256,
+		ActualSHA256:   actual,
+		VerifiedAt:     now,
+	}, nil
+}
+
+// rehashEvidence reads the stored file and compares its SHA-256 with the one captured at collection
+func (s *IncidentService) rehashEvidence(ctx context.Context, evidence *repo.IncidentEvidence) (string, *string) {
+	download, err := s.documentStorageService.DownloadDocument(ctx, evidence.DocumentID, evidence.TenantID)
+	if err != nil {
+		log.Printf("WARN: incident_service.rehashEvidence evidence=%s file unavailable: %v", evidence.ID, err)
+		return dto.EvidenceVerificationMissing, nil
+	}
+
+	actual, err := calculateFileHash(bytes.NewReader(download.Content))
+	if err != nil {
+		log.Printf("WARN: incident_service.rehashEvidence evidence=%s: %v", evidence.ID, err)
+		return dto.EvidenceVerificationMissing, nil
+	}
+	if actual != evidence.SHA256 {
+		return dto.EvidenceVerificationMismatch, &actual
+	}
+	return dto.EvidenceVerificationValid, &actual
+}
+
+func (s *IncidentService) getEvidence(ctx context.Context, incidentID, evidenceID, tenantID string) (*repo.IncidentEvidence, error) {
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.getEvidence GetByID: %v", err)
+		return nil, err
+	}
+
+	evidence, err := s.incidentRepo.GetEvidence(ctx, evidenceID, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.getEvidence GetEvidence: %v", err)
+		return nil, err
+	}
+	if evidence == nil {
+		return nil, ErrIncidentEvidenceNotFound
+	}
+	return evidence, nil
+}
+
+func newEvidenceCustody(evidence *repo.IncidentEvidence, action, userID, ipAddress string) *repo.IncidentEvidenceCustody {
+	entry := &repo.IncidentEvidenceCustody{
+		ID:          uuid.New().String(),
+		EvidenceID:  evidence.ID,
+		Action:      action,
+		PerformedBy: &userID,
+		PerformedAt: time.Now(),
+	}
+	if ipAddress != "" {
+		entry.IPAddress = &ipAddress
+	}
+	return entry
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvidenceContent = []byte("memory dump")
+
+// fakeEvidenceRepo keeps a single evidence item and its chain of custody in memory
+type fakeEvidenceRepo struct {
+	IncidentRepoInterface
+
+	evidence *repo.IncidentEvidence
+	custody  []*repo.IncidentEvidenceCustody
+}
+
+func (r *fakeEvidenceRepo) GetByID(ctx context.Context, id, tenantID string) (*repo.Incident, error) {
+	return &repo.Incident{ID: id, TenantID: tenantID}, nil
+}
+
+func (r *fakeEvidenceRepo) GetEvidence(ctx context.Context, id, incidentID string) (*repo.IncidentEvidence, error) {
+	if r.evidence.ID != id || r.evidence.IncidentID != incidentID {
+		return nil, nil
+	}
+	copied := *r.evidence
+	return &copied, nil
+}
+
+func (r *fakeEvidenceRepo) UpdateEvidence(ctx context.Context, evidence *repo.IncidentEvidence, entry *repo.IncidentEvidenceCustody) error {
+	stored := *evidence
+	r.evidence = &stored
+	r.custody = append(r.custody, entry)
+	return nil
+}
+
+func (r *fakeEvidenceRepo) AddEvidenceCustody(ctx context.Context, entry *repo.IncidentEvidenceCustody) error {
+	r.custody = append(r.custody, entry)
+	return nil
+}
+
+func (r *fakeEvidenceRepo) IsEvidenceDocument(ctx context.Context, documentID string) (bool, error) {
+	return r.evidence.DocumentID == documentID, nil
+}
+
+// fakeEvidenceStorage serves the stored file of the evidence; nil content means the file is gone
+type fakeEvidenceStorage struct {
+	DocumentStorageServiceInterface
+
+	content []byte
+}
+
+func (s *fakeEvidenceStorage) DownloadDocument(ctx context.Context, id, tenantID string) (*dto.DocumentDownloadDTO, error) {
+	if s.content == nil {
+		return nil, errors.New("file not found")
+	}
+	return &dto.DocumentDownloadDTO{Content: s.content, FileName: "stored-name.bin"}, nil
+}
+
+func newEvidenceTestService(t *testing.T, stored []byte) (*IncidentService, *fakeEvidenceRepo) {
+	hash, err := calculateFileHash(bytes.NewReader(testEvidenceContent))
+	require.NoError(t, err)
+
+	incidentRepo := &fakeEvidenceRepo{evidence: &repo.IncidentEvidence{
+		ID:          "evidence-1",
+		TenantID:    "tenant-1",
+		IncidentID:  "incident-1",
+		DocumentID:  "document-1",
+		Title:       "Memory dump",
+		FileName:    "dump.raw",
+		SHA256:      hash,
+		CustodianID: incidentStringPtr("user-1"),
+	}}
+	return NewIncidentService(incidentRepo, nil, nil, nil, &fakeEvidenceStorage{content: stored}), incidentRepo
+}
+
+func TestSealEvidence(t *testing.T) {
+	service, incidentRepo := newEvidenceTestService(t, testEvidenceContent)
+	ctx := context.Background()
+
+	evidence, err := service.SealEvidence(ctx, "incident-1", "evidence-1", "tenant-1", "user-1", "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, evidence.IsSealed)
+	assert.Equal(t, "user-1", *evidence.SealedBy)
+	require.NotNil(t, evidence.SealedAt)
+	assert.Equal(t, dto.EvidenceVerificationValid, *evidence.LastVerificationStatus)
+
+	require.Len(t, incidentRepo.custody, 1)
+	entry := incidentRepo.custody[0]
+	assert.Equal(t, dto.EvidenceCustodySealed, entry.Action)
+	assert.Equal(t, evidence.SHA256, *entry.SHA256, "the seal records the hash it was checked against")
+	assert.Equal(t, "10.0.0.1", *entry.IPAddress)
+
+	_, err = service.SealEvidence(ctx, "incident-1", "evidence-1", "tenant-1", "user-1", "")
+	assert.ErrorIs(t, err, ErrIncidentEvidenceSealed, "sealing is done once")
+
+	title := "Renamed"
+	_, err = service.UpdateEvidence(ctx, "incident-1", "evidence-1", "tenant-1", dto.IncidentEvidenceUpdateRequest{Title: &title}, "user-1", "")
+	assert.ErrorIs(t, err, ErrIncidentEvidenceSealed, "sealed evidence is read-only")
+	assert.Equal(t, "Memory dump", incidentRepo.evidence.Title)
+	assert.Len(t, incidentRepo.custody, 1)
+}
+
+func TestSealEvidence_IntegrityCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     []byte
+		wantStatus string
+	}{
+		{name: "file changed in storage", stored: []byte("tampered dump"), wantStatus: dto.EvidenceVerificationMismatch},
+		{name: "file missing in storage", wantStatus: dto.EvidenceVerificationMissing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, incidentRepo := newEvidenceTestService(t, tt.stored)
+
+			_, err := service.SealEvidence(context.Background(), "incident-1", "evidence-1", "tenant-1", "user-1", "")
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "sha256", validationErr.Field)
+			assert.Contains(t, validationErr.Message, tt.wantStatus)
+
+			assert.False(t, incidentRepo.evidence.IsSealed, "evidence that fails the check is not sealed")
+			assert.Empty(t, incidentRepo.custody)
+		})
+	}
+}
+
+func TestVerifyEvidence(t *testing.T) {
+	service, incidentRepo := newEvidenceTestService(t, []byte("tampered dump"))
+
+	result, err := service.VerifyEvidence(context.Background(), "incident-1", "evidence-1", "tenant-1", "user-2", "")
+	require.NoError(t, err)
+	assert.Equal(t, dto.EvidenceVerificationMismatch, result.Status)
+	assert.Equal(t, incidentRepo.evidence.SHA256, result.ExpectedSHA256)
+	require.NotNil(t, result.ActualSHA256)
+	assert.NotEqual(t, result.ExpectedSHA256, *result.ActualSHA256)
+
+	assert.Equal(t, dto.EvidenceVerificationMismatch, *incidentRepo.evidence.LastVerificationStatus)
+	require.Len(t, incidentRepo.custody, 1)
+	assert.Equal(t, dto.EvidenceCustodyVerified, incidentRepo.custody[0].Action)
+	assert.Equal(t, "user-2", *incidentRepo.custody[0].PerformedBy)
+}
+
+func TestExportEvidence(t *testing.T) {
+	service, incidentRepo := newEvidenceTestService(t, testEvidenceContent)
+
+	_, download, err := service.ExportEvidence(context.Background(), "incident-1", "evidence-1", "tenant-1", "user-2", "")
+	require.NoError(t, err)
+	assert.Equal(t, "dump.raw", download.FileName, "the export keeps the collected file name")
+	assert.Equal(t, testEvidenceContent, download.Content)
+
+	require.Len(t, incidentRepo.custody, 1)
+	assert.Equal(t, dto.EvidenceCustodyExported, incidentRepo.custody[0].Action)
+	assert.Equal(t, incidentRepo.evidence.SHA256, *incidentRepo.custody[0].SHA256)
+}
+
+func TestEvidenceDocumentProtected(t *testing.T) {
+	service, _ := newEvidenceTestService(t, testEvidenceContent)
+	ctx := context.Background()
+
+	err := service.UnlinkDocumentFromIncident(ctx, "incident-1", "document-1", "tenant-1", "user-1")
+	assert.ErrorIs(t, err, ErrEvidenceDocumentProtected)
+
+	err = service.DeleteIncidentDocument(ctx, "incident-1", "document-1", "tenant-1", "user-1")
+	assert.ErrorIs(t, err, ErrEvidenceDocumentProtected)
+}
+
+func TestGetEvidence_NotFound(t *testing.T) {
+	service, _ := newEvidenceTestService(t, testEvidenceContent)
+
+	_, _, err := service.GetEvidence(context.Background(), "incident-2", "evidence-1", "tenant-1", "user-1", "")
+	assert.ErrorIs(t, err, ErrIncidentEvidenceNotFound, "evidence of another incident is not found")
+}
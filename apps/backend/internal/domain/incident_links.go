@@ -0,0 +1,455 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Merges can be reverted within this period
+	incidentMergeRevertWindow = 7 * 24 * time.Hour
+
+	// Guards the parent chain walk against corrupted data
+	maxIncidentHierarchyDepth = 50
+)
+
+// SetAuditRepo connects the audit log used to record incident merges
+func (s *IncidentService) SetAuditRepo(auditRepo *repo.AuditRepo) {
+	s.auditRepo = auditRepo
+}
+
+// GetIncidentRelations returns the parent, children, related incidents and merges of an incident
+func (s *IncidentService) GetIncidentRelations(ctx context.Context, incidentID, tenantID string) (*dto.IncidentRelationsResponse, error) {
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.IncidentRelationsResponse{
+		Children:        []dto.IncidentSummary{},
+		Related:         []dto.IncidentLinkResponse{},
+		MergedIncidents: []dto.IncidentSummary{},
+		Merges:          []dto.IncidentMergeResponse{},
+	}
+
+	if incident.ParentID != nil {
+		if response.Parent, err = s.getIncidentSummary(ctx, *incident.ParentID, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	if incident.MergedIntoID != nil {
+		if response.MergedInto, err = s.getIncidentSummary(ctx, *incident.MergedIntoID, tenantID); err != nil {
+			return nil, err
+		}
+	}
+
+	children, err := s.incidentRepo.ListChildIncidents(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentRelations ListChildIncidents: %v", err)
+		return nil, err
+	}
+	for _, child := range children {
+		response.Children = append(response.Children, toIncidentSummary(child))
+	}
+
+	merged, err := s.incidentRepo.ListMergedIncidents(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentRelations ListMergedIncidents: %v", err)
+		return nil, err
+	}
+	for _, duplicate := range merged {
+		response.MergedIncidents = append(response.MergedIncidents, toIncidentSummary(duplicate))
+	}
+
+	links, err := s.incidentRepo.ListLinks(ctx, incidentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentRelations ListLinks: %v", err)
+		return nil, err
+	}
+	for _, link := range links {
+		linkResponse, err := s.toIncidentLinkResponse(ctx, link, incidentID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		response.Related = append(response.Related, *linkResponse)
+	}
+
+	merges, err := s.incidentRepo.ListMerges(ctx, incidentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentRelations ListMerges: %v", err)
+		return nil, err
+	}
+	for _, merge := range merges {
+		mergeResponse, err := s.toIncidentMergeResponse(ctx, merge)
+		if err != nil {
+			return nil, err
+		}
+		response.Merges = append(response.Merges, *mergeResponse)
+	}
+
+	return response, nil
+}
+
+// SetIncidentParent places the incident under a parent (major event) or detaches it when ParentID is empty
+// and returns the updated relations
+func (s *IncidentService) SetIncidentParent(ctx context.Context, incidentID, tenantID string, req dto.IncidentParentRequest, userID string) (*dto.IncidentRelationsResponse, error) {
+	log.Printf("DEBUG: incident_service.SetIncidentParent incident=%s parent=%v", incidentID, req.ParentID)
+
+	if _, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID); err != nil {
+		return nil, err
+	}
+
+	var parentID *string
+	if req.ParentID != nil && *req.ParentID != "" {
+		parentID = req.ParentID
+		if *parentID == incidentID {
+			return nil, NewValidationError("parent_id", "incident cannot be its own parent")
+		}
+
+		// Идем вверх по цепочке родителей: инцидент не должен оказаться предком своего родителя
+		current := *parentID
+		for depth := 0; ; depth++ {
+			if depth >= maxIncidentHierarchyDepth {
+				return nil, NewValidationError("parent_id", "incident hierarchy is too deep")
+			}
+			ancestor, err := s.incidentRepo.GetByID(ctx, current, tenantID)
+			if err != nil {
+				return nil, err
+			}
+			if depth == 0 && ancestor.MergedIntoID != nil {
+				return nil, fmt.Errorf("%w: parent %s", ErrIncidentAlreadyMerged, ancestor.ID)
+			}
+			if ancestor.ParentID == nil {
+				break
+			}
+			if *ancestor.ParentID == incidentID {
+				return nil, NewValidationError("parent_id", "parent would create a cycle in the incident hierarchy")
+			}
+			current = *ancestor.ParentID
+		}
+	}
+
+	if err := s.incidentRepo.SetParent(ctx, incidentID, tenantID, parentID); err != nil {
+		log.Printf("ERROR: incident_service.SetIncidentParent SetParent: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.SetIncidentParent incident=%s parent=%v by %s", incidentID, parentID, userID)
+	return s.GetIncidentRelations(ctx, incidentID, tenantID)
+}
+
+// LinkIncidents adds a "related to" link; the link is symmetric and shown on both incidents
+func (s *IncidentService) LinkIncidents(ctx context.Context, incidentID, tenantID string, req dto.IncidentLinkRequest, userID string) (*dto.IncidentLinkResponse, error) {
+	if _, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID); err != nil {
+		return nil, err
+	}
+	if req.RelatedIncidentID == incidentID {
+		return nil, NewValidationError("related_incident_id", "incident cannot be linked to itself")
+	}
+	if _, err := s.incidentRepo.GetByID(ctx, req.RelatedIncidentID, tenantID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.incidentRepo.ListLinks(ctx, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if link.IncidentID == req.RelatedIncidentID || link.RelatedIncidentID == req.RelatedIncidentID {
+			return s.toIncidentLinkResponse(ctx, link, incidentID, tenantID)
+		}
+	}
+
+	link := &repo.IncidentLink{
+		ID:                uuid.New().String(),
+		TenantID:          tenantID,
+		IncidentID:        incidentID,
+		RelatedIncidentID: req.RelatedIncidentID,
+		Comment:           req.Comment,
+		CreatedBy:         &userID,
+		CreatedAt:         time.Now(),
+	}
+	// Пара хранится упорядоченной, чтобы A-B и B-A были одной связью
+	if link.RelatedIncidentID < link.IncidentID {
+		link.IncidentID, link.RelatedIncidentID = link.RelatedIncidentID, link.IncidentID
+	}
+
+	if err := s.incidentRepo.CreateLink(ctx, link); err != nil {
+		log.Printf("ERROR: incident_service.LinkIncidents CreateLink: %v", err)
+		return nil, err
+	}
+
+	return s.toIncidentLinkResponse(ctx, link, incidentID, tenantID)
+}
+
+func (s *IncidentService) UnlinkIncidents(ctx context.Context, incidentID, linkID, tenantID string) error {
+	if _, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID); err != nil {
+		return err
+	}
+
+	link, err := s.incidentRepo.GetLink(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link == nil || link.TenantID != tenantID || (link.IncidentID != incidentID && link.RelatedIncidentID != incidentID) {
+		return ErrIncidentLinkNotFound
+	}
+
+	if err := s.incidentRepo.DeleteLink(ctx, linkID); err != nil {
+		log.Printf("ERROR: incident_service.UnlinkIncidents DeleteLink: %v", err)
+		return err
+	}
+	return nil
+}
+
+// MergeIncidents merges duplicates into the primary incident: comments, actions, assets, risks and documents
+// are moved to it and each duplicate is closed with a reference to the primary incident.
+// Every merge is recorded in the audit log and can be reverted within incidentMergeRevertWindow.
+func (s *IncidentService) MergeIncidents(ctx context.Context, primaryID, tenantID string, req dto.IncidentMergeRequest, userID string) ([]dto.IncidentMergeResponse, error) {
+	log.Printf("DEBUG: incident_service.MergeIncidents primary=%s duplicates=%v", primaryID, req.IncidentIDs)
+
+	primary, err := s.incidentRepo.GetByID(ctx, primaryID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if primary.MergedIntoID != nil {
+		return nil, fmt.Errorf("%w: %s", ErrIncidentAlreadyMerged, primary.ID)
+	}
+
+	// Сначала проверяем все дубликаты, чтобы не объединить только часть из них
+	var duplicates []*repo.Incident
+	seen := map[string]bool{}
+	for _, id := range req.IncidentIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if id == primaryID {
+			return nil, NewValidationError("incident_ids", "incident cannot be merged into itself")
+		}
+		duplicate, err := s.incidentRepo.GetByID(ctx, id, tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate.MergedIntoID != nil {
+			return nil, fmt.Errorf("%w: %s", ErrIncidentAlreadyMerged, duplicate.ID)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	var result []dto.IncidentMergeResponse
+	for _, duplicate := range duplicates {
+		now := time.Now()
+		merge := &repo.IncidentMerge{
+			ID:                 uuid.New().String(),
+			TenantID:           tenantID,
+			PrimaryIncidentID:  primary.ID,
+			MergedIncidentID:   duplicate.ID,
+			Reason:             req.Reason,
+			PreviousStatus:     duplicate.Status,
+			PreviousClosedAt:   duplicate.ClosedAt,
+			PreviousResolution: duplicate.Resolution,
+			MergedBy:           &userID,
+			MergedAt:           now,
+		}
+
+		var change *repo.IncidentStatusChange
+		if duplicate.Status != dto.IncidentStatusClosed {
+			fromStatus := duplicate.Status
+			change = &repo.IncidentStatusChange{
+				ID:         uuid.New().String(),
+				IncidentID: duplicate.ID,
+				FromStatus: &fromStatus,
+				ToStatus:   dto.IncidentStatusClosed,
+				Comment:    incidentStringPtr(fmt.Sprintf("Merged into incident: %s", primary.Title)),
+				ChangedBy:  &userID,
+				ChangedAt:  now,
+			}
+			duplicate.Status = dto.IncidentStatusClosed
+			duplicate.ClosedAt = &now
+		}
+		duplicate.Resolution = incidentStringPtr(fmt.Sprintf("Duplicate of incident: %s", primary.Title))
+		duplicate.MergedIntoID = &primary.ID
+		duplicate.UpdatedAt = now
+
+		if err := s.incidentRepo.MergeIncident(ctx, merge, duplicate, change); err != nil {
+			log.Printf("ERROR: incident_service.MergeIncidents MergeIncident %s: %v", duplicate.ID, err)
+			return nil, err
+		}
+
+		s.logIncidentAudit(ctx, tenantID, userID, "merge_incident", primary.ID, map[string]interface{}{
+			"merge_id":           merge.ID,
+			"merged_incident_id": duplicate.ID,
+			"reason":             req.Reason,
+			"comments":           len(merge.CommentIDs),
+			"actions":            len(merge.ActionIDs),
+			"assets":             len(merge.AssetLinkIDs),
+			"risks":              len(merge.RiskLinkIDs),
+			"documents":          len(merge.DocumentLinkIDs),
+		})
+
+		mergeResponse, err := s.toIncidentMergeResponse(ctx, merge)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *mergeResponse)
+
+		log.Printf("INFO: incident_service.MergeIncidents merged %s into %s by %s", duplicate.ID, primary.ID, userID)
+	}
+
+	return result, nil
+}
+
+// RevertIncidentMerge moves the merged rows back to the duplicate and restores its status;
+// incidentID may be either side of the merge
+func (s *IncidentService) RevertIncidentMerge(ctx context.Context, incidentID, mergeID, tenantID, userID string) (*dto.IncidentMergeResponse, error) {
+	log.Printf("DEBUG: incident_service.RevertIncidentMerge incident=%s merge=%s", incidentID, mergeID)
+
+	merge, err := s.incidentRepo.GetMerge(ctx, mergeID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if merge == nil || (merge.PrimaryIncidentID != incidentID && merge.MergedIncidentID != incidentID) {
+		return nil, ErrIncidentMergeNotFound
+	}
+	if merge.RevertedAt != nil {
+		return nil, ErrIncidentMergeReverted
+	}
+	if time.Since(merge.MergedAt) > incidentMergeRevertWindow {
+		return nil, ErrIncidentMergeRevertExpired
+	}
+
+	primary, err := s.incidentRepo.GetByID(ctx, merge.PrimaryIncidentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if primary.MergedIntoID != nil {
+		// Основной инцидент сам был объединен позже; сначала нужно отменить то объединение
+		return nil, fmt.Errorf("%w: %s", ErrIncidentAlreadyMerged, primary.ID)
+	}
+
+	duplicate, err := s.incidentRepo.GetByID(ctx, merge.MergedIncidentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var change *repo.IncidentStatusChange
+	if duplicate.Status != merge.PreviousStatus {
+		fromStatus := duplicate.Status
+		change = &repo.IncidentStatusChange{
+			ID:         uuid.New().String(),
+			IncidentID: duplicate.ID,
+			FromStatus: &fromStatus,
+			ToStatus:   merge.PreviousStatus,
+			IsReopen:   IsIncidentReopen(fromStatus, merge.PreviousStatus),
+			Comment:    incidentStringPtr(fmt.Sprintf("Merge into incident reverted: %s", primary.Title)),
+			ChangedBy:  &userID,
+			ChangedAt:  now,
+		}
+	}
+	duplicate.Status = merge.PreviousStatus
+	duplicate.ClosedAt = merge.PreviousClosedAt
+	duplicate.Resolution = merge.PreviousResolution
+	duplicate.MergedIntoID = nil
+	duplicate.UpdatedAt = now
+
+	merge.RevertedBy = &userID
+	merge.RevertedAt = &now
+
+	if err := s.incidentRepo.RevertMerge(ctx, merge, duplicate, change); err != nil {
+		log.Printf("ERROR: incident_service.RevertIncidentMerge RevertMerge: %v", err)
+		return nil, err
+	}
+
+	s.logIncidentAudit(ctx, tenantID, userID, "revert_incident_merge", primary.ID, map[string]interface{}{
+		"merge_id":           merge.ID,
+		"merged_incident_id": duplicate.ID,
+	})
+
+	log.Printf("INFO: incident_service.RevertIncidentMerge merge=%s reverted by %s", merge.ID, userID)
+	return s.toIncidentMergeResponse(ctx, merge)
+}
+
+func (s *IncidentService) logIncidentAudit(ctx context.Context, tenantID, userID, action, incidentID string, payload interface{}) {
+	if s.auditRepo == nil {
+		return
+	}
+	if err := s.auditRepo.LogAction(ctx, tenantID, userID, action, "incident", &incidentID, payload); err != nil {
+		log.Printf("WARN: incident_service.logIncidentAudit %s: %v", action, err)
+	}
+}
+
+func (s *IncidentService) getIncidentSummary(ctx context.Context, id, tenantID string) (*dto.IncidentSummary, error) {
+	incident, err := s.incidentRepo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	summary := toIncidentSummary(incident)
+	return &summary, nil
+}
+
+func toIncidentSummary(incident *repo.Incident) dto.IncidentSummary {
+	return dto.IncidentSummary{
+		ID:          incident.ID,
+		Title:       incident.Title,
+		Status:      incident.Status,
+		Criticality: incident.Criticality,
+		DetectedAt:  incident.DetectedAt,
+	}
+}
+
+func (s *IncidentService) toIncidentLinkResponse(ctx context.Context, link *repo.IncidentLink, incidentID, tenantID string) (*dto.IncidentLinkResponse, error) {
+	otherID := link.RelatedIncidentID
+	if otherID == incidentID {
+		otherID = link.IncidentID
+	}
+	other, err := s.getIncidentSummary(ctx, otherID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.IncidentLinkResponse{
+		ID:        link.ID,
+		Incident:  *other,
+		Comment:   link.Comment,
+		CreatedBy: link.CreatedBy,
+		CreatedAt: link.CreatedAt,
+	}, nil
+}
+
+func (s *IncidentService) toIncidentMergeResponse(ctx context.Context, merge *repo.IncidentMerge) (*dto.IncidentMergeResponse, error) {
+	merged, err := s.getIncidentSummary(ctx, merge.MergedIncidentID, merge.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	revertibleUntil := merge.MergedAt.Add(incidentMergeRevertWindow)
+	return &dto.IncidentMergeResponse{
+		ID:                merge.ID,
+		PrimaryIncidentID: merge.PrimaryIncidentID,
+		MergedIncident:    *merged,
+		Reason:            merge.Reason,
+		Moved: dto.IncidentMergeMoved{
+			Comments:  len(merge.CommentIDs),
+			Actions:   len(merge.ActionIDs),
+			Assets:    len(merge.AssetLinkIDs),
+			Risks:     len(merge.RiskLinkIDs),
+			Documents: len(merge.DocumentLinkIDs),
+		},
+		MergedBy:        merge.MergedBy,
+		MergedAt:        merge.MergedAt,
+		RevertibleUntil: revertibleUntil,
+		CanRevert:       merge.RevertedAt == nil && time.Now().Before(revertibleUntil),
+		RevertedBy:      merge.RevertedBy,
+		RevertedAt:      merge.RevertedAt,
+	}, nil
+}
@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMergeRepo keeps incidents and merges in memory; the repository moves rows of the duplicate
+// to the primary incident and records their ids, here simulated by movedComments
+type fakeMergeRepo struct {
+	IncidentRepoInterface
+
+	incidents     map[string]*repo.Incident
+	merges        map[string]*repo.IncidentMerge
+	movedComments map[string][]string
+	changes       []*repo.IncidentStatusChange
+}
+
+func newFakeMergeRepo(incidents ...*repo.Incident) *fakeMergeRepo {
+	r := &fakeMergeRepo{
+		incidents:     map[string]*repo.Incident{},
+		merges:        map[string]*repo.IncidentMerge{},
+		movedComments: map[string][]string{},
+	}
+	for _, incident := range incidents {
+		r.incidents[incident.ID] = incident
+	}
+	return r
+}
+
+func (r *fakeMergeRepo) GetByID(ctx context.Context, id, tenantID string) (*repo.Incident, error) {
+	incident, ok := r.incidents[id]
+	if !ok || incident.TenantID != tenantID {
+		return nil, errors.New("incident not found")
+	}
+	copied := *incident
+	return &copied, nil
+}
+
+func (r *fakeMergeRepo) GetMerge(ctx context.Context, mergeID, tenantID string) (*repo.IncidentMerge, error) {
+	merge, ok := r.merges[mergeID]
+	if !ok || merge.TenantID != tenantID {
+		return nil, nil
+	}
+	copied := *merge
+	return &copied, nil
+}
+
+func (r *fakeMergeRepo) MergeIncident(ctx context.Context, merge *repo.IncidentMerge, duplicate *repo.Incident, change *repo.IncidentStatusChange) error {
+	merge.CommentIDs = r.movedComments[duplicate.ID]
+	stored := *merge
+	r.merges[merge.ID] = &stored
+	r.incidents[duplicate.ID] = duplicate
+	if change != nil {
+		r.changes = append(r.changes, change)
+	}
+	return nil
+}
+
+func (r *fakeMergeRepo) RevertMerge(ctx context.Context, merge *repo.IncidentMerge, duplicate *repo.Incident, change *repo.IncidentStatusChange) error {
+	stored := *merge
+	r.merges[merge.ID] = &stored
+	r.incidents[duplicate.ID] = duplicate
+	if change != nil {
+		r.changes = append(r.changes, change)
+	}
+	return nil
+}
+
+const testMergeTenant = "tenant-1"
+
+func testMergeIncident(id, status string) *repo.Incident {
+	return &repo.Incident{ID: id, TenantID: testMergeTenant, Title: "Incident " + id, Status: status}
+}
+
+func TestMergeIncidents_Validation(t *testing.T) {
+	mergedInto := "other"
+	merged := testMergeIncident("merged", dto.IncidentStatusClosed)
+	merged.MergedIntoID = &mergedInto
+
+	tests := []struct {
+		name      string
+		primaryID string
+		ids       []string
+		wantErr   error
+		wantField string
+	}{
+		{name: "merge into itself", primaryID: "primary", ids: []string{"primary"}, wantField: "incident_ids"},
+		{name: "duplicate already merged", primaryID: "primary", ids: []string{"dup", "merged"}, wantErr: ErrIncidentAlreadyMerged},
+		{name: "primary already merged", primaryID: "merged", ids: []string{"dup"}, wantErr: ErrIncidentAlreadyMerged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeMergeRepo(testMergeIncident("primary", dto.IncidentStatusInProgress), testMergeIncident("dup", dto.IncidentStatusNew), merged)
+			service := NewIncidentService(fake, nil, nil, nil, nil)
+
+			_, err := service.MergeIncidents(context.Background(), tt.primaryID, testMergeTenant, dto.IncidentMergeRequest{IncidentIDs: tt.ids}, "user-1")
+			if tt.wantField != "" {
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Empty(t, fake.merges, "nothing is merged when any duplicate is invalid")
+		})
+	}
+}
+
+func TestMergeAndRevertIncident(t *testing.T) {
+	closedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	resolution := "Fixed by restart"
+
+	tests := []struct {
+		name           string
+		duplicate      *repo.Incident
+		wantMergeClose bool
+	}{
+		{
+			name:           "open duplicate is closed and reopened",
+			duplicate:      testMergeIncident("dup", dto.IncidentStatusInProgress),
+			wantMergeClose: true,
+		},
+		{
+			name: "closed duplicate keeps its closing data",
+			duplicate: &repo.Incident{
+				ID: "dup", TenantID: testMergeTenant, Title: "Incident dup", Status: dto.IncidentStatusClosed,
+				ClosedAt: &closedAt, Resolution: &resolution,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := *tt.duplicate
+			fake := newFakeMergeRepo(testMergeIncident("primary", dto.IncidentStatusInProgress), tt.duplicate)
+			fake.movedComments["dup"] = []string{"comment-1", "comment-2"}
+			service := NewIncidentService(fake, nil, nil, nil, nil)
+			ctx := context.Background()
+
+			merges, err := service.MergeIncidents(ctx, "primary", testMergeTenant, dto.IncidentMergeRequest{IncidentIDs: []string{"dup", "dup"}}, "user-1")
+			require.NoError(t, err)
+			require.Len(t, merges, 1, "repeated ids are merged once")
+
+			response := merges[0]
+			assert.Equal(t, "primary", response.PrimaryIncidentID)
+			assert.Equal(t, 2, response.Moved.Comments)
+			assert.True(t, response.CanRevert)
+			assert.Equal(t, response.MergedAt.Add(incidentMergeRevertWindow), response.RevertibleUntil)
+
+			stored := fake.merges[response.ID]
+			require.NotNil(t, stored)
+			assert.Equal(t, original.Status, stored.PreviousStatus)
+			assert.Equal(t, original.ClosedAt, stored.PreviousClosedAt)
+			assert.Equal(t, original.Resolution, stored.PreviousResolution)
+
+			merged := fake.incidents["dup"]
+			assert.Equal(t, dto.IncidentStatusClosed, merged.Status)
+			require.NotNil(t, merged.MergedIntoID)
+			assert.Equal(t, "primary", *merged.MergedIntoID)
+			assert.Equal(t, "Duplicate of incident: Incident primary", *merged.Resolution)
+			if tt.wantMergeClose {
+				require.Len(t, fake.changes, 1)
+				assert.Equal(t, dto.IncidentStatusClosed, fake.changes[0].ToStatus)
+			} else {
+				assert.Empty(t, fake.changes, "closed duplicate gets no status change")
+				assert.Equal(t, &closedAt, merged.ClosedAt)
+			}
+
+			// Повторное объединение уже объединенного инцидента запрещено
+			_, err = service.MergeIncidents(ctx, "primary", testMergeTenant, dto.IncidentMergeRequest{IncidentIDs: []string{"dup"}}, "user-1")
+			assert.ErrorIs(t, err, ErrIncidentAlreadyMerged)
+
+			reverted, err := service.RevertIncidentMerge(ctx, "dup", response.ID, testMergeTenant, "user-2")
+			require.NoError(t, err)
+			assert.False(t, reverted.CanRevert)
+			require.NotNil(t, reverted.RevertedBy)
+			assert.Equal(t, "user-2", *reverted.RevertedBy)
+
+			restored := fake.incidents["dup"]
+			assert.Equal(t, original.Status, restored.Status)
+			assert.Equal(t, original.ClosedAt, restored.ClosedAt)
+			assert.Equal(t, original.Resolution, restored.Resolution)
+			assert.Nil(t, restored.MergedIntoID)
+			if tt.wantMergeClose {
+				require.Len(t, fake.changes, 2)
+				assert.Equal(t, original.Status, fake.changes[1].ToStatus)
+				assert.True(t, fake.changes[1].IsReopen)
+			} else {
+				assert.Empty(t, fake.changes)
+			}
+
+			_, err = service.RevertIncidentMerge(ctx, "primary", response.ID, testMergeTenant, "user-2")
+			assert.ErrorIs(t, err, ErrIncidentMergeReverted)
+		})
+	}
+}
+
+func TestRevertIncidentMerge_Errors(t *testing.T) {
+	mergedInto := "primary"
+	laterMergedInto := "newer"
+
+	tests := []struct {
+		name    string
+		merge   repo.IncidentMerge
+		primary *repo.Incident
+		target  string
+		wantErr error
+	}{
+		{
+			name:    "unknown incident",
+			merge:   repo.IncidentMerge{MergedAt: time.Now()},
+			target:  "unrelated",
+			wantErr: ErrIncidentMergeNotFound,
+		},
+		{
+			name:    "revert window expired",
+			merge:   repo.IncidentMerge{MergedAt: time.Now().Add(-incidentMergeRevertWindow - time.Minute)},
+			target:  "dup",
+			wantErr: ErrIncidentMergeRevertExpired,
+		},
+		{
+			name:    "primary merged later",
+			merge:   repo.IncidentMerge{MergedAt: time.Now()},
+			primary: &repo.Incident{ID: "primary", TenantID: testMergeTenant, Status: dto.IncidentStatusClosed, MergedIntoID: &laterMergedInto},
+			target:  "dup",
+			wantErr: ErrIncidentAlreadyMerged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := tt.primary
+			if primary == nil {
+				primary = testMergeIncident("primary", dto.IncidentStatusInProgress)
+			}
+			duplicate := testMergeIncident("dup", dto.IncidentStatusClosed)
+			duplicate.MergedIntoID = &mergedInto
+
+			fake := newFakeMergeRepo(primary, duplicate)
+			merge := tt.merge
+			merge.ID, merge.TenantID = "merge-1", testMergeTenant
+			merge.PrimaryIncidentID, merge.MergedIncidentID = "primary", "dup"
+			merge.PreviousStatus = dto.IncidentStatusNew
+			fake.merges[merge.ID] = &merge
+			service := NewIncidentService(fake, nil, nil, nil, nil)
+
+			_, err := service.RevertIncidentMerge(context.Background(), tt.target, merge.ID, testMergeTenant, "user-1")
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, dto.IncidentStatusClosed, fake.incidents["dup"].Status, "duplicate is left untouched")
+		})
+	}
+}
@@ -0,0 +1,563 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Messages downloaded from one mailbox per poll; the rest are picked up by the next poll
+	maxMailMessagesPerPoll = 50
+
+	maxMailMessageLog = 200
+
+	// Limits of the incident description and comment fields
+	maxMailBodyLength = 2000
+)
+
+// Mailboxes
+
+func (s *IncidentService) ListMailboxes(ctx context.Context, tenantID string) ([]*repo.IncidentMailbox, error) {
+	log.Printf("DEBUG: incident_service.ListMailboxes tenant=%s", tenantID)
+
+	mailboxes, err := s.incidentRepo.ListMailboxes(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListMailboxes ListMailboxes: %v", err)
+		return nil, err
+	}
+	return mailboxes, nil
+}
+
+func (s *IncidentService) CreateMailbox(ctx context.Context, tenantID string, req dto.IncidentMailboxRequest, createdBy string) (*repo.IncidentMailbox, error) {
+	log.Printf("DEBUG: incident_service.CreateMailbox tenant=%s name=%s", tenantID, req.Name)
+
+	if req.Password == "" {
+		return nil, NewValidationError("password", "password is required")
+	}
+
+	mailbox := &repo.IncidentMailbox{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		IsActive:  true,
+		CreatedBy: &createdBy,
+		CreatedAt: time.Now(),
+	}
+	if err := s.applyMailboxRequest(ctx, mailbox, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveMailbox(ctx, mailbox); err != nil {
+		log.Printf("ERROR: incident_service.CreateMailbox SaveMailbox: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.CreateMailbox created id=%s", mailbox.ID)
+	return mailbox, nil
+}
+
+func (s *IncidentService) UpdateMailbox(ctx context.Context, id, tenantID string, req dto.IncidentMailboxRequest) (*repo.IncidentMailbox, error) {
+	log.Printf("DEBUG: incident_service.UpdateMailbox id=%s tenant=%s", id, tenantID)
+
+	mailbox, err := s.getMailbox(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyMailboxRequest(ctx, mailbox, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveMailbox(ctx, mailbox); err != nil {
+		log.Printf("ERROR: incident_service.UpdateMailbox SaveMailbox: %v", err)
+		return nil, err
+	}
+	return mailbox, nil
+}
+
+func (s *IncidentService) DeleteMailbox(ctx context.Context, id, tenantID string) error {
+	log.Printf("DEBUG: incident_service.DeleteMailbox id=%s tenant=%s", id, tenantID)
+
+	if _, err := s.getMailbox(ctx, id, tenantID); err != nil {
+		return err
+	}
+
+	if err := s.incidentRepo.DeleteMailbox(ctx, id, tenantID); err != nil {
+		log.Printf("ERROR: incident_service.DeleteMailbox DeleteMailbox: %v", err)
+		return err
+	}
+	return nil
+}
+
+// GetMailboxMessages returns the latest messages processed from a mailbox
+func (s *IncidentService) GetMailboxMessages(ctx context.Context, id, tenantID string) ([]*repo.IncidentMailMessage, error) {
+	if _, err := s.getMailbox(ctx, id, tenantID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.incidentRepo.ListMailMessages(ctx, id, maxMailMessageLog)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetMailboxMessages ListMailMessages: %v", err)
+		return nil, err
+	}
+	return messages, nil
+}
+
+// PollMailbox polls one mailbox immediately, e.g. to check its settings
+func (s *IncidentService) PollMailbox(ctx context.Context, id, tenantID string) (*dto.IncidentMailPollResult, error) {
+	mailbox, err := s.getMailbox(ctx, id, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.pollMailbox(ctx, mailbox), nil
+}
+
+func (s *IncidentService) getMailbox(ctx context.Context, id, tenantID string) (*repo.IncidentMailbox, error) {
+	mailbox, err := s.incidentRepo.GetMailbox(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.getMailbox GetMailbox: %v", err)
+		return nil, err
+	}
+	if mailbox == nil {
+		return nil, ErrIncidentMailboxNotFound
+	}
+	return mailbox, nil
+}
+
+func (s *IncidentService) applyMailboxRequest(ctx context.Context, mailbox *repo.IncidentMailbox, req dto.IncidentMailboxRequest) error {
+	for field, userID := range map[string]*string{"assigned_to": req.AssignedTo, "fallback_reporter_id": req.FallbackReporterID} {
+		if userID == nil || *userID == "" {
+			continue
+		}
+		user, err := s.userRepo.GetByID(ctx, *userID)
+		if err != nil {
+			log.Printf("ERROR: incident_service.applyMailboxRequest GetByID %s: %v", field, err)
+			return err
+		}
+		if user == nil || user.TenantID != mailbox.TenantID {
+			return NewValidationError(field, "user not found")
+		}
+	}
+
+	mailbox.Name = req.Name
+	mailbox.Host = req.Host
+	mailbox.UseTLS = true
+	if req.UseTLS != nil {
+		mailbox.UseTLS = *req.UseTLS
+	}
+	mailbox.Port = req.Port
+	if mailbox.Port == 0 {
+		mailbox.Port = 995
+		if !mailbox.UseTLS {
+			mailbox.Port = 110
+		}
+	}
+	mailbox.Username = req.Username
+	if req.Password != "" {
+		if s.mailboxCipher == nil {
+			return errMailboxSecretKeyMissing
+		}
+		password, err := s.mailboxCipher.encrypt(req.Password)
+		if err != nil {
+			return err
+		}
+		mailbox.Password = password
+	}
+	mailbox.DefaultCategory = req.DefaultCategory
+	if mailbox.DefaultCategory == "" {
+		mailbox.DefaultCategory = dto.IncidentCategoryOther
+	}
+	mailbox.DefaultCriticality = req.DefaultCriticality
+	if mailbox.DefaultCriticality == "" {
+		mailbox.DefaultCriticality = dto.IncidentCriticalityMedium
+	}
+	mailbox.AssignedTo = nonEmptyStringPtr(req.AssignedTo)
+	mailbox.FallbackReporterID = nonEmptyStringPtr(req.FallbackReporterID)
+	mailbox.DeleteAfterImport = req.DeleteAfterImport
+	if req.IsActive != nil {
+		mailbox.IsActive = *req.IsActive
+	}
+	mailbox.UpdatedAt = time.Now()
+	return nil
+}
+
+func nonEmptyStringPtr(value *string) *string {
+	if value == nil || *value == "" {
+		return nil
+	}
+	return value
+}
+
+// SetMailboxSecretKey configures the key that encrypts mailbox passwords at rest
+func (s *IncidentService) SetMailboxSecretKey(secret string) error {
+	mailboxCipher, err := newSecretCipher(secret)
+	if err != nil {
+		return err
+	}
+	s.mailboxCipher = mailboxCipher
+	return nil
+}
+
+// encryptPlaintextMailboxPasswords encrypts passwords saved before encryption was introduced
+func (s *IncidentService) encryptPlaintextMailboxPasswords(ctx context.Context) {
+	if s.mailboxCipher == nil {
+		return
+	}
+	mailboxes, err := s.incidentRepo.ListPlaintextPasswordMailboxes(ctx)
+	if err != nil {
+		log.Printf("ERROR: IncidentService mail poller ListPlaintextPasswordMailboxes: %v", err)
+		return
+	}
+	for _, mailbox := range mailboxes {
+		password, err := s.mailboxCipher.encrypt(mailbox.Password)
+		if err != nil {
+			log.Printf("ERROR: IncidentService mail poller encrypt password of mailbox %s: %v", mailbox.ID, err)
+			continue
+		}
+		mailbox.Password = password
+		if err := s.incidentRepo.SaveMailbox(ctx, mailbox); err != nil {
+			log.Printf("ERROR: IncidentService mail poller SaveMailbox %s: %v", mailbox.ID, err)
+		}
+	}
+}
+
+// Polling
+
+// RunMailPoller polls the active mailboxes of all tenants until the context is cancelled
+func (s *IncidentService) RunMailPoller(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.encryptPlaintextMailboxPasswords(ctx)
+
+	s.pollMailboxes(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.pollMailboxes(ctx)
+		}
+	}
+}
+
+func (s *IncidentService) pollMailboxes(ctx context.Context) {
+	mailboxes, err := s.incidentRepo.ListActiveMailboxes(ctx)
+	if err != nil {
+		log.Printf("ERROR: IncidentService mail poller ListActiveMailboxes: %v", err)
+		return
+	}
+
+	for _, mailbox := range mailboxes {
+		result := s.pollMailbox(ctx, mailbox)
+		if result.Received > 0 {
+			log.Printf("INFO: IncidentService mail poller mailbox=%s received=%d incidents=%d comments=%d rejected=%d failed=%d",
+				mailbox.ID, result.Received, result.Incidents, result.Comments, result.Rejected, result.Failed)
+		}
+	}
+}
+
+// pollMailbox downloads the messages not seen before, turns them into incidents or comments
+// and records the outcome of every message so that it is not processed twice
+func (s *IncidentService) pollMailbox(ctx context.Context, mailbox *repo.IncidentMailbox) *dto.IncidentMailPollResult {
+	s.mailPollMu.Lock()
+	defer s.mailPollMu.Unlock()
+
+	result := &dto.IncidentMailPollResult{MailboxID: mailbox.ID, PolledAt: time.Now()}
+	err := s.fetchMailbox(ctx, mailbox, result)
+
+	var lastError *string
+	if err != nil {
+		log.Printf("ERROR: incident_service.pollMailbox mailbox=%s: %v", mailbox.ID, err)
+		lastError = incidentStringPtr(err.Error())
+		result.Errors = append(result.Errors, err.Error())
+	}
+	if err := s.incidentRepo.SetMailboxPollResult(ctx, mailbox.ID, result.PolledAt, lastError); err != nil {
+		log.Printf("WARN: incident_service.pollMailbox SetMailboxPollResult: %v", err)
+	}
+	return result
+}
+
+func (s *IncidentService) fetchMailbox(ctx context.Context, mailbox *repo.IncidentMailbox, result *dto.IncidentMailPollResult) error {
+	if s.mailboxCipher == nil {
+		return errMailboxSecretKeyMissing
+	}
+	password, err := s.mailboxCipher.decrypt(mailbox.Password)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialPOP3(ctx, mailbox.Host, mailbox.Port, mailbox.UseTLS)
+	if err != nil {
+		return err
+	}
+	defer client.close()
+
+	if err := client.login(mailbox.Username, password); err != nil {
+		return err
+	}
+
+	messages, err := client.list()
+	if err != nil {
+		return err
+	}
+
+	uidls := make([]string, 0, len(messages))
+	for _, message := range messages {
+		uidls = append(uidls, message.uidl)
+	}
+	processed, err := s.incidentRepo.ListProcessedMailUIDLs(ctx, mailbox.ID, uidls)
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		if message.uidl == "" || result.Received >= maxMailMessagesPerPoll {
+			continue
+		}
+		if processed[message.uidl] {
+			// Письмо уже обработано, но не удалено: например, удаление включили позже
+			if mailbox.DeleteAfterImport {
+				if err := client.dele(message.number); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		var raw []byte
+		if message.size <= maxMailMessageSize {
+			if raw, err = client.retr(message.number); err != nil {
+				return err
+			}
+		}
+
+		// Письмо фиксируется до создания инцидента: если его уже взял другой экземпляр бэкенда,
+		// вставка не пройдет по (mailbox_id, uidl). Прерванная обработка оставляет статус processing,
+		// а не повторный инцидент.
+		claimed, err := s.incidentRepo.ClaimMailMessage(ctx, newMailRecord(mailbox, message.uidl, nil))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		result.Received++
+
+		var record *repo.IncidentMailMessage
+		if raw == nil {
+			record = newMailRecord(mailbox, message.uidl, nil)
+			record.Status = dto.IncidentMailStatusRejected
+			record.Error = incidentStringPtr(fmt.Sprintf("message is too large (%d bytes)", message.size))
+		} else {
+			record = s.processMailMessage(ctx, mailbox, message.uidl, raw)
+		}
+
+		switch record.Status {
+		case dto.IncidentMailStatusIncident:
+			result.Incidents++
+		case dto.IncidentMailStatusComment:
+			result.Comments++
+		case dto.IncidentMailStatusRejected:
+			result.Rejected++
+		default:
+			result.Failed++
+		}
+		if record.Error != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", record.FromAddress, *record.Error))
+		}
+
+		if err := s.incidentRepo.FinishMailMessage(ctx, record); err != nil {
+			return err
+		}
+		if mailbox.DeleteAfterImport {
+			if err := client.dele(message.number); err != nil {
+				return err
+			}
+		}
+	}
+
+	return client.quit()
+}
+
+func newMailRecord(mailbox *repo.IncidentMailbox, uidl string, parsed *parsedMail) *repo.IncidentMailMessage {
+	record := &repo.IncidentMailMessage{
+		ID:          uuid.New().String(),
+		TenantID:    mailbox.TenantID,
+		MailboxID:   mailbox.ID,
+		UIDL:        uidl,
+		FromAddress: "unknown",
+		ProcessedAt: time.Now(),
+	}
+	if parsed != nil {
+		record.FromAddress = truncateRunes(parsed.from.Address, 320)
+		record.SentAt = parsed.sentAt
+		if parsed.messageID != "" {
+			record.MessageID = incidentStringPtr(truncateRunes(parsed.messageID, 998))
+		}
+		if parsed.inReplyTo != "" {
+			record.InReplyTo = incidentStringPtr(truncateRunes(parsed.inReplyTo, 998))
+		}
+		if parsed.subject != "" {
+			record.Subject = &parsed.subject
+		}
+	}
+	return record
+}
+
+// processMailMessage creates an incident from a new message or a comment from a reply on the thread
+// of an incident; sender addresses are mapped to tenant users
+func (s *IncidentService) processMailMessage(ctx context.Context, mailbox *repo.IncidentMailbox, uidl string, raw []byte) *repo.IncidentMailMessage {
+	parsed, err := parseMailMessage(raw)
+	if err != nil {
+		record := newMailRecord(mailbox, uidl, nil)
+		record.Status = dto.IncidentMailStatusRejected
+		record.Error = incidentStringPtr(err.Error())
+		return record
+	}
+	record := newMailRecord(mailbox, uidl, parsed)
+
+	fail := func(status string, err error) *repo.IncidentMailMessage {
+		log.Printf("WARN: incident_service.processMailMessage mailbox=%s from=%s: %v", mailbox.ID, parsed.from.Address, err)
+		record.Status = status
+		record.Error = incidentStringPtr(err.Error())
+		return record
+	}
+
+	reporterID, err := s.resolveMailReporter(ctx, mailbox, parsed.from.Address)
+	if err != nil {
+		return fail(dto.IncidentMailStatusFailed, err)
+	}
+	if reporterID == "" {
+		return fail(dto.IncidentMailStatusRejected, fmt.Errorf("sender %s is not a user of the organization", parsed.from.Address))
+	}
+
+	incidentID := ""
+	if len(parsed.references) > 0 {
+		incidentID, err = s.incidentRepo.FindIncidentByMailReference(ctx, mailbox.TenantID, parsed.references)
+		if err != nil {
+			return fail(dto.IncidentMailStatusFailed, err)
+		}
+	}
+
+	truncated := false
+	if incidentID != "" {
+		text := stripQuotedReply(parsed.body)
+		if text == "" {
+			text = "(empty message)"
+		}
+		if len(parsed.attachments) > 0 {
+			text += fmt.Sprintf("\n\nAttachments: %d", len(parsed.attachments))
+		}
+		truncated = len([]rune(text)) > maxMailBodyLength
+
+		comment, err := s.AddComment(ctx, incidentID, mailbox.TenantID, dto.IncidentCommentRequest{
+			Comment: truncateRunes(text, maxMailBodyLength),
+		}, reporterID)
+		if err != nil {
+			return fail(dto.IncidentMailStatusFailed, err)
+		}
+		record.Status = dto.IncidentMailStatusComment
+		record.CommentID = &comment.ID
+	} else {
+		title := parsed.subject
+		if title == "" {
+			title = fmt.Sprintf("Incident reported by %s", parsed.from.Address)
+		}
+		detectedAt := time.Now()
+		if parsed.sentAt != nil && parsed.sentAt.Before(detectedAt) {
+			detectedAt = *parsed.sentAt
+		}
+
+		req := dto.CreateIncidentRequest{IncidentRequest: dto.IncidentRequest{
+			Title:       truncateRunes(title, 255),
+			Category:    mailbox.DefaultCategory,
+			Criticality: mailbox.DefaultCriticality,
+			Source:      dto.IncidentSourceUserReport,
+			AssignedTo:  mailbox.AssignedTo,
+			DetectedAt:  &detectedAt,
+		}}
+		if parsed.body != "" {
+			truncated = len([]rune(parsed.body)) > maxMailBodyLength
+			description := truncateRunes(parsed.body, maxMailBodyLength)
+			req.Description = &description
+		}
+
+		incident, err := s.CreateIncident(ctx, mailbox.TenantID, req, reporterID)
+		if err != nil {
+			return fail(dto.IncidentMailStatusFailed, err)
+		}
+		incidentID = incident.ID
+		record.Status = dto.IncidentMailStatusIncident
+	}
+	record.IncidentID = &incidentID
+
+	attachments := parsed.attachments
+	if truncated {
+		// Полный текст письма не поместился в описание или комментарий, сохраняем исходное письмо
+		attachments = append(attachments, mailAttachment{fileName: "message.eml", contentType: "message/rfc822", content: raw})
+	}
+	for _, attachment := range attachments {
+		if err := s.saveMailAttachment(ctx, incidentID, mailbox.TenantID, parsed, attachment, reporterID); err != nil {
+			log.Printf("WARN: incident_service.processMailMessage attachment %s: %v", attachment.fileName, err)
+			continue
+		}
+		record.Attachments++
+	}
+
+	return record
+}
+
+// resolveMailReporter returns the active tenant user with the sender address, the mailbox fallback reporter,
+// or an empty string when the message should be rejected
+func (s *IncidentService) resolveMailReporter(ctx context.Context, mailbox *repo.IncidentMailbox, address string) (string, error) {
+	user, err := s.userRepo.GetByEmail(ctx, mailbox.TenantID, address)
+	if err != nil {
+		return "", err
+	}
+	if user != nil && user.IsActive {
+		return user.ID, nil
+	}
+	if mailbox.FallbackReporterID != nil {
+		return *mailbox.FallbackReporterID, nil
+	}
+	return "", nil
+}
+
+func (s *IncidentService) saveMailAttachment(ctx context.Context, incidentID, tenantID string, parsed *parsedMail, attachment mailAttachment, uploadedBy string) error {
+	header := &multipart.FileHeader{
+		Filename: attachment.fileName,
+		Size:     int64(len(attachment.content)),
+		Header:   textproto.MIMEHeader{"Content-Type": {attachment.contentType}},
+	}
+	description := fmt.Sprintf("Email attachment from %s", parsed.from.Address)
+	if parsed.subject != "" {
+		description += ": " + parsed.subject
+	}
+
+	req := dto.UploadDocumentDTO{
+		Name:        strings.TrimSuffix(attachment.fileName, "."),
+		Description: &description,
+	}
+	_, err := s.UploadIncidentDocument(ctx, incidentID, tenantID, memoryFile{bytes.NewReader(attachment.content)}, header, req, uploadedBy)
+	return err
+}
+
+// memoryFile adapts an in-memory attachment to multipart.File
+type memoryFile struct {
+	*bytes.Reader
+}
+
+func (memoryFile) Close() error {
+	return nil
+}
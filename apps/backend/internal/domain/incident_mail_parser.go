@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	maxMailMessageSize = 25 << 20
+	maxMailAttachments = 20
+	maxMailPartDepth   = 10
+)
+
+// parsedMail holds the parts of an email used for incident intake
+type parsedMail struct {
+	messageID   string
+	inReplyTo   string
+	references  []string // In-Reply-To and References ids, without angle brackets
+	from        *mail.Address
+	subject     string
+	sentAt      *time.Time
+	body        string
+	attachments []mailAttachment
+}
+
+type mailAttachment struct {
+	fileName    string
+	contentType string
+	content     []byte
+}
+
+var mailWordDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+// parseMailMessage parses an RFC 5322 message: decodes headers, picks the text body (plain text preferred
+// over HTML) and collects attachments from nested multipart parts
+func parseMailMessage(raw []byte) (*parsedMail, error) {
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	addressParser := mail.AddressParser{WordDecoder: mailWordDecoder}
+	from, err := addressParser.Parse(message.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid From header: %w", err)
+	}
+	from.Address = strings.ToLower(from.Address)
+
+	parsed := &parsedMail{
+		messageID: trimMessageID(message.Header.Get("Message-Id")),
+		inReplyTo: trimMessageID(message.Header.Get("In-Reply-To")),
+		from:      from,
+		subject:   strings.TrimSpace(decodeMailHeader(message.Header.Get("Subject"))),
+	}
+	for _, id := range strings.Fields(message.Header.Get("In-Reply-To") + " " + message.Header.Get("References")) {
+		if id = trimMessageID(id); id != "" {
+			parsed.references = append(parsed.references, id)
+		}
+	}
+	if sentAt, err := message.Header.Date(); err == nil {
+		parsed.sentAt = &sentAt
+	}
+
+	var plain, htmlBody string
+	header := textproto.MIMEHeader(message.Header)
+	if err := walkMailPart(header, message.Body, 0, parsed, &plain, &htmlBody); err != nil {
+		return nil, err
+	}
+
+	parsed.body = strings.TrimSpace(plain)
+	if parsed.body == "" && htmlBody != "" {
+		parsed.body = htmlToText(htmlBody)
+	}
+	return parsed, nil
+}
+
+func walkMailPart(header textproto.MIMEHeader, body io.Reader, depth int, parsed *parsedMail, plain, htmlBody *string) error {
+	if depth > maxMailPartDepth {
+		return errors.New("email nesting is too deep")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("invalid multipart body: %w", err)
+			}
+			if err := walkMailPart(part.Header, part, depth+1, parsed, plain, htmlBody); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("invalid part encoding: %w", err)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	fileName := dispositionParams["filename"]
+	if fileName == "" {
+		fileName = params["name"]
+	}
+	fileName = decodeMailHeader(fileName)
+
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+	if disposition == "attachment" || fileName != "" || !isText {
+		if len(parsed.attachments) >= maxMailAttachments || len(content) == 0 {
+			return nil
+		}
+		if fileName == "" {
+			fileName = "attachment"
+			if mediaType == "message/rfc822" {
+				fileName = "message.eml"
+			} else if extensions, _ := mime.ExtensionsByType(mediaType); len(extensions) > 0 {
+				fileName += extensions[0]
+			}
+		}
+		parsed.attachments = append(parsed.attachments, mailAttachment{
+			fileName:    fileName,
+			contentType: mediaType,
+			content:     content,
+		})
+		return nil
+	}
+
+	text := decodeMailCharset(params["charset"], content)
+	if mediaType == "text/plain" && *plain == "" {
+		*plain = text
+	} else if mediaType == "text/html" && *htmlBody == "" {
+		*htmlBody = text
+	}
+	return nil
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		// Декодер base64 пропускает переводы строк
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+func decodeMailCharset(label string, content []byte) string {
+	if label == "" || strings.EqualFold(label, "utf-8") || strings.EqualFold(label, "us-ascii") {
+		return strings.ToValidUTF8(string(content), "")
+	}
+	reader, err := charset.NewReaderLabel(label, bytes.NewReader(content))
+	if err != nil {
+		return strings.ToValidUTF8(string(content), "")
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return strings.ToValidUTF8(string(content), "")
+	}
+	return string(decoded)
+}
+
+func decodeMailHeader(value string) string {
+	decoded, err := mailWordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+func trimMessageID(id string) string {
+	return strings.Trim(strings.TrimSpace(id), "<>")
+}
+
+var (
+	mailHTMLHiddenPattern = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	mailHTMLBreakPattern  = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/li|/h[1-6])[^>]*>`)
+	mailHTMLTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	mailBlankLinesPattern = regexp.MustCompile(`\n{3,}`)
+)
+
+func htmlToText(value string) string {
+	value = mailHTMLHiddenPattern.ReplaceAllString(value, "")
+	value = mailHTMLBreakPattern.ReplaceAllString(value, "\n")
+	value = mailHTMLTagPattern.ReplaceAllString(value, "")
+	value = html.UnescapeString(value)
+
+	lines := strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(mailBlankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// mailReplyHeaderPattern matches the line mail clients put above the quoted original message
+var mailReplyHeaderPattern = regexp.MustCompile(`(?i)^(on .+ wrote:|.+ (написал|написала|написал\(а\)|пишет):|-+ ?(original message|исходное сообщение|пересылаемое сообщение) ?-+)$`)
+
+// stripQuotedReply keeps only the new text of a reply, dropping the quoted original message
+func stripQuotedReply(body string) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") || mailReplyHeaderPattern.MatchString(trimmed) {
+			if text := strings.TrimSpace(strings.Join(lines[:i], "\n")); text != "" {
+				return text
+			}
+			break
+		}
+	}
+	return strings.TrimSpace(body)
+}
@@ -0,0 +1,256 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mailFixture joins header and body lines with CRLF as a mail server delivers them
+func mailFixture(lines ...string) []byte {
+	return []byte(strings.Join(lines, "\r\n"))
+}
+
+func TestParseMailMessage_Headers(t *testing.T) {
+	raw := mailFixture(
+		"From: =?UTF-8?B?0JjQstCw0L0g0J/QtdGC0YDQvtCy?= <Ivan.Petrov@Example.COM>",
+		"To: soc@example.com",
+		"Subject: =?UTF-8?B?0KTQuNGI0LjQvdCz0L7QstC+0LUg0L/QuNGB0YzQvNC+?=",
+		"Date: Mon, 02 Mar 2026 10:15:00 +0300",
+		"Message-ID: <abc123@example.com>",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		"Получил подозрительное письмо.",
+	)
+
+	parsed, err := parseMailMessage(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ivan.petrov@example.com", parsed.from.Address)
+	assert.Equal(t, "Иван Петров", parsed.from.Name)
+	assert.Equal(t, "Фишинговое письмо", parsed.subject)
+	assert.Equal(t, "abc123@example.com", parsed.messageID)
+	require.NotNil(t, parsed.sentAt)
+	assert.Equal(t, "2026-03-02T07:15:00Z", parsed.sentAt.UTC().Format("2006-01-02T15:04:05Z"))
+	assert.Equal(t, "Получил подозрительное письмо.", parsed.body)
+	assert.Empty(t, parsed.references)
+}
+
+func TestParseMailMessage_Threading(t *testing.T) {
+	tests := []struct {
+		name           string
+		headers        []string
+		wantInReplyTo  string
+		wantReferences []string
+	}{
+		{
+			name:           "in-reply-to only",
+			headers:        []string{"In-Reply-To: <first@example.com>"},
+			wantInReplyTo:  "first@example.com",
+			wantReferences: []string{"first@example.com"},
+		},
+		{
+			name: "in-reply-to and references chain",
+			headers: []string{
+				"In-Reply-To: <second@example.com>",
+				"References: <first@example.com>",
+				" <second@example.com>",
+			},
+			wantInReplyTo:  "second@example.com",
+			wantReferences: []string{"second@example.com", "first@example.com", "second@example.com"},
+		},
+		{
+			name:           "references without in-reply-to",
+			headers:        []string{"References: <root@example.com> <child@example.com>"},
+			wantReferences: []string{"root@example.com", "child@example.com"},
+		},
+		{
+			name: "new thread",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"From: user@example.com", "Subject: Re: incident"}, tt.headers...)
+			lines = append(lines, "", "ok")
+
+			parsed, err := parseMailMessage(mailFixture(lines...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantInReplyTo, parsed.inReplyTo)
+			assert.Equal(t, tt.wantReferences, parsed.references)
+		})
+	}
+}
+
+func TestParseMailMessage_Charsets(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		body    string
+		want    string
+	}{
+		{
+			name:    "windows-1251 8bit",
+			headers: []string{"Content-Type: text/plain; charset=windows-1251", "Content-Transfer-Encoding: 8bit"},
+			body:    "\xcf\xf0\xe8\xe2\xe5\xf2", // "Привет"
+			want:    "Привет",
+		},
+		{
+			name:    "koi8-r quoted-printable",
+			headers: []string{"Content-Type: text/plain; charset=koi8-r", "Content-Transfer-Encoding: quoted-printable"},
+			body:    "=F0=D2=C9=D7=C5=D4",
+			want:    "Привет",
+		},
+		{
+			name:    "utf-8 base64",
+			headers: []string{"Content-Type: text/plain; charset=UTF-8", "Content-Transfer-Encoding: base64"},
+			body:    "0J/RgNC40LLQtdGC",
+			want:    "Привет",
+		},
+		{
+			name:    "missing content type",
+			headers: nil,
+			body:    "plain ascii",
+			want:    "plain ascii",
+		},
+		{
+			name:    "unknown charset falls back to utf-8",
+			headers: []string{"Content-Type: text/plain; charset=x-unknown"},
+			body:    "text",
+			want:    "text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := append([]string{"From: user@example.com", "MIME-Version: 1.0"}, tt.headers...)
+			lines = append(lines, "", tt.body)
+
+			parsed, err := parseMailMessage(mailFixture(lines...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, parsed.body)
+		})
+	}
+}
+
+func TestParseMailMessage_HTMLFallback(t *testing.T) {
+	raw := mailFixture(
+		"From: user@example.com",
+		"Content-Type: text/html; charset=utf-8",
+		"",
+		"<html><head><style>p{}</style></head><body><p>Line&nbsp;one</p><p>Line &amp; two<br>three</p></body></html>",
+	)
+
+	parsed, err := parseMailMessage(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "Line one\nLine & two\nthree", parsed.body)
+}
+
+func TestParseMailMessage_Attachments(t *testing.T) {
+	raw := mailFixture(
+		"From: user@example.com",
+		"Subject: logs",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="outer"`,
+		"",
+		"--outer",
+		`Content-Type: multipart/alternative; boundary="inner"`,
+		"",
+		"--inner",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		"See attached",
+		"--inner",
+		"Content-Type: text/html; charset=utf-8",
+		"",
+		"<p>See attached HTML</p>",
+		"--inner--",
+		"--outer",
+		"Content-Type: application/octet-stream",
+		`Content-Disposition: attachment; filename="=?UTF-8?B?0LvQvtCzLnR4dA==?="`,
+		"Content-Transfer-Encoding: base64",
+		"",
+		"aGVsbG8gd29ybGQ=",
+		"--outer",
+		"Content-Type: image/png",
+		"Content-Transfer-Encoding: base64",
+		"",
+		"iVBORw0KGgo=",
+		"--outer",
+		"Content-Type: message/rfc822",
+		"",
+		"From: other@example.com",
+		"",
+		"forwarded",
+		"--outer",
+		"Content-Type: text/plain",
+		"Content-Disposition: attachment",
+		"",
+		"",
+		"--outer--",
+	)
+
+	parsed, err := parseMailMessage(raw)
+	require.NoError(t, err)
+
+	assert.Equal(t, "See attached", parsed.body, "plain text is preferred over HTML")
+	require.Len(t, parsed.attachments, 3, "empty attachments are skipped")
+
+	assert.Equal(t, "лог.txt", parsed.attachments[0].fileName)
+	assert.Equal(t, "application/octet-stream", parsed.attachments[0].contentType)
+	assert.Equal(t, "hello world", string(parsed.attachments[0].content))
+
+	assert.Equal(t, "image/png", parsed.attachments[1].contentType)
+	assert.Equal(t, "attachment.png", parsed.attachments[1].fileName)
+	assert.Equal(t, "\x89PNG\r\n\x1a\n", string(parsed.attachments[1].content))
+
+	assert.Equal(t, "message.eml", parsed.attachments[2].fileName)
+	assert.Contains(t, string(parsed.attachments[2].content), "forwarded")
+}
+
+func TestParseMailMessage_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{"no headers", []byte("just text without a header block")},
+		{"missing from", mailFixture("Subject: hi", "", "body")},
+		{"broken base64", mailFixture(
+			"From: user@example.com",
+			"Content-Type: text/plain",
+			"Content-Transfer-Encoding: base64",
+			"",
+			"!!! not base64 !!!",
+		)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseMailMessage(tt.raw)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStripQuotedReply(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"no quote", "Just text", "Just text"},
+		{"angle quoted", "New text\r\n\r\n> old text\r\n> more", "New text"},
+		{"english header", "Fixed now\n\nOn Mon, 2 Mar 2026 at 10:00, SOC <soc@example.com> wrote:\nold", "Fixed now"},
+		{"russian header", "Спасибо\n\n02.03.2026, 10:00, Иван пишет:\nстарое", "Спасибо"},
+		{"outlook separator", "Done\n-----Original Message-----\nFrom: x", "Done"},
+		{"only quote keeps body", "> quoted only", "> quoted only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, stripQuotedReply(tt.body))
+		})
+	}
+}
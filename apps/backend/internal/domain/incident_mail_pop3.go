@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pop3Client is a minimal POP3 client (RFC 1939) with implicit TLS, enough to download and delete messages
+type pop3Client struct {
+	conn net.Conn
+	text *textproto.Conn
+}
+
+// pop3Message is a message listed by the server: its number in this session, unique id and size in bytes
+type pop3Message struct {
+	number int
+	uidl   string
+	size   int64
+}
+
+const pop3SessionTimeout = 5 * time.Minute
+
+func dialPOP3(ctx context.Context, host string, port int, useTLS bool) (*pop3Client, error) {
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if useTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Ограничиваем всю сессию, чтобы зависший сервер не блокировал опрос остальных ящиков
+	conn.SetDeadline(time.Now().Add(pop3SessionTimeout))
+
+	client := &pop3Client{conn: conn, text: textproto.NewConn(conn)}
+	if _, err := client.readResponse(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("pop3 greeting: %w", err)
+	}
+	return client, nil
+}
+
+func (c *pop3Client) readResponse() (string, error) {
+	line, err := c.text.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(line, "+OK") {
+		return strings.TrimSpace(strings.TrimPrefix(line, "+OK")), nil
+	}
+	return "", fmt.Errorf("pop3 server error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+}
+
+func (c *pop3Client) cmd(format string, args ...interface{}) (string, error) {
+	if err := c.text.PrintfLine(format, args...); err != nil {
+		return "", err
+	}
+	return c.readResponse()
+}
+
+func (c *pop3Client) login(username, password string) error {
+	if _, err := c.cmd("USER %s", username); err != nil {
+		return err
+	}
+	if _, err := c.cmd("PASS %s", password); err != nil {
+		return fmt.Errorf("pop3 login failed: %w", err)
+	}
+	return nil
+}
+
+// list returns the messages in the mailbox with their sizes and unique ids
+func (c *pop3Client) list() ([]pop3Message, error) {
+	if _, err := c.cmd("LIST"); err != nil {
+		return nil, err
+	}
+	lines, err := c.text.ReadDotLines()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []pop3Message
+	index := make(map[int]int, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		number, err1 := strconv.Atoi(fields[0])
+		size, err2 := strconv.ParseInt(fields[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("pop3 invalid LIST line: %q", line)
+		}
+		index[number] = len(messages)
+		messages = append(messages, pop3Message{number: number, size: size})
+	}
+
+	if _, err := c.cmd("UIDL"); err != nil {
+		return nil, fmt.Errorf("pop3 server does not support UIDL: %w", err)
+	}
+	lines, err = c.text.ReadDotLines()
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		number, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("pop3 invalid UIDL line: %q", line)
+		}
+		if i, ok := index[number]; ok {
+			messages[i].uidl = fields[1]
+		}
+	}
+
+	return messages, nil
+}
+
+// retr downloads a message; the body is dot-unstuffed and uses LF line endings
+func (c *pop3Client) retr(number int) ([]byte, error) {
+	if _, err := c.cmd("RETR %d", number); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(c.text.DotReader())
+}
+
+// dele marks a message for deletion; the server deletes it on QUIT
+func (c *pop3Client) dele(number int) error {
+	_, err := c.cmd("DELE %d", number)
+	return err
+}
+
+func (c *pop3Client) quit() error {
+	_, err := c.cmd("QUIT")
+	c.text.Close()
+	return err
+}
+
+func (c *pop3Client) close() {
+	c.text.Close()
+}
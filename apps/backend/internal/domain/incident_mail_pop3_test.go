@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePOP3Server serves a single POP3 session with the given messages and records the received commands
+func fakePOP3Server(t *testing.T, messages []string) (host string, port int, commands <-chan []string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var log []string
+		defer func() { received <- log }()
+
+		reader := bufio.NewReader(conn)
+		write := func(lines ...string) {
+			for _, line := range lines {
+				conn.Write([]byte(line + "\r\n"))
+			}
+		}
+
+		write("+OK ready")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimSpace(line)
+			log = append(log, command)
+
+			fields := strings.Fields(command)
+			switch strings.ToUpper(fields[0]) {
+			case "USER":
+				write("+OK")
+			case "PASS":
+				if fields[1] != "secret" {
+					write("-ERR invalid password")
+					continue
+				}
+				write("+OK logged in")
+			case "LIST":
+				write("+OK")
+				for i, message := range messages {
+					write(strconv.Itoa(i+1) + " " + strconv.Itoa(len(message)))
+				}
+				write(".")
+			case "UIDL":
+				write("+OK")
+				for i := range messages {
+					write(strconv.Itoa(i+1) + " uid-" + strconv.Itoa(i+1))
+				}
+				write(".")
+			case "RETR":
+				number, _ := strconv.Atoi(fields[1])
+				write("+OK")
+				for _, body := range strings.Split(messages[number-1], "\r\n") {
+					if strings.HasPrefix(body, ".") {
+						body = "." + body
+					}
+					write(body)
+				}
+				write(".")
+			case "DELE":
+				write("+OK deleted")
+			case "QUIT":
+				write("+OK bye")
+				return
+			default:
+				write("-ERR unknown command")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func TestPOP3Client_Session(t *testing.T) {
+	message := string(mailFixture(
+		"From: reporter@example.com",
+		"Subject: Suspicious login",
+		"Message-ID: <m1@example.com>",
+		"",
+		"Line one",
+		".dot-stuffed line",
+	))
+	host, port, commands := fakePOP3Server(t, []string{message})
+
+	client, err := dialPOP3(context.Background(), host, port, false)
+	require.NoError(t, err)
+
+	require.NoError(t, client.login("soc@example.com", "secret"))
+
+	messages, err := client.list()
+	require.NoError(t, err)
+	require.Equal(t, []pop3Message{{number: 1, uidl: "uid-1", size: int64(len(message))}}, messages)
+
+	raw, err := client.retr(1)
+	require.NoError(t, err)
+
+	parsed, err := parseMailMessage(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "reporter@example.com", parsed.from.Address)
+	assert.Equal(t, "Suspicious login", parsed.subject)
+	assert.Equal(t, "m1@example.com", parsed.messageID)
+	assert.Equal(t, "Line one\n.dot-stuffed line", parsed.body)
+
+	require.NoError(t, client.dele(1))
+	require.NoError(t, client.quit())
+
+	assert.Equal(t, []string{"USER soc@example.com", "PASS secret", "LIST", "UIDL", "RETR 1", "DELE 1", "QUIT"}, <-commands)
+}
+
+func TestPOP3Client_LoginFailure(t *testing.T) {
+	host, port, _ := fakePOP3Server(t, nil)
+
+	client, err := dialPOP3(context.Background(), host, port, false)
+	require.NoError(t, err)
+	defer client.close()
+
+	err = client.login("soc@example.com", "wrong")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid password")
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Mailbox passwords are stored encrypted with AES-GCM; the prefix marks the format version
+// and tells encrypted values from passwords saved before encryption was introduced
+const mailboxSecretPrefix = "enc:v1:"
+
+var errMailboxSecretKeyMissing = errors.New("mailbox secret key is not configured")
+
+// secretCipher encrypts credentials that the server has to use in clear text, such as POP3 passwords
+type secretCipher struct {
+	aead cipher.AEAD
+}
+
+// newSecretCipher derives a 256-bit AES key from the configured secret
+func newSecretCipher(secret string) (*secretCipher, error) {
+	if secret == "" {
+		return nil, errMailboxSecretKeyMissing
+	}
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &secretCipher{aead: aead}, nil
+}
+
+func (c *secretCipher) encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return mailboxSecretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt returns values without the prefix unchanged: they were saved before encryption was enabled
+func (c *secretCipher) decrypt(stored string) (string, error) {
+	if !isEncryptedSecret(stored) {
+		return stored, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, mailboxSecretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("decode mailbox secret: %w", err)
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", errors.New("decode mailbox secret: value is too short")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt mailbox secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func isEncryptedSecret(stored string) bool {
+	return strings.HasPrefix(stored, mailboxSecretPrefix)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretCipher_RoundTrip(t *testing.T) {
+	c, err := newSecretCipher("test-key")
+	require.NoError(t, err)
+
+	for _, password := range []string{"", "p@ss", "пароль с пробелами"} {
+		encrypted, err := c.encrypt(password)
+		require.NoError(t, err)
+		assert.True(t, isEncryptedSecret(encrypted))
+
+		decrypted, err := c.decrypt(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, password, decrypted)
+	}
+
+	first, _ := c.encrypt("same")
+	second, _ := c.encrypt("same")
+	assert.NotEqual(t, first, second, "nonce must be random")
+}
+
+func TestSecretCipher_Decrypt(t *testing.T) {
+	c, err := newSecretCipher("test-key")
+	require.NoError(t, err)
+	other, err := newSecretCipher("other-key")
+	require.NoError(t, err)
+
+	encrypted, err := c.encrypt("secret")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		stored  string
+		want    string
+		wantErr bool
+	}{
+		{name: "legacy plaintext", stored: "plain-password", want: "plain-password"},
+		{name: "encrypted", stored: encrypted, want: "secret"},
+		{name: "invalid base64", stored: mailboxSecretPrefix + "%%%", wantErr: true},
+		{name: "too short", stored: mailboxSecretPrefix + "AAAA", wantErr: true},
+		{name: "tampered", stored: encrypted[:len(encrypted)-4] + "AAAA", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.decrypt(tt.stored)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = other.decrypt(encrypted)
+	assert.Error(t, err, "a different key must not decrypt the value")
+}
+
+func TestNewSecretCipher_EmptyKey(t *testing.T) {
+	_, err := newSecretCipher("")
+	assert.ErrorIs(t, err, errMailboxSecretKeyMissing)
+}
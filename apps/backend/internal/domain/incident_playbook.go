@@ -0,0 +1,325 @@
+package domain
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// GetPlaybooks returns the playbook in effect for every category that has one:
+// the tenant's own playbook if configured, otherwise the built-in one
+func (s *IncidentService) GetPlaybooks(ctx context.Context, tenantID string) ([]*repo.IncidentPlaybook, error) {
+	log.Printf("DEBUG: incident_service.GetPlaybooks tenant=%s", tenantID)
+
+	playbooks, err := s.incidentRepo.ListPlaybooks(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetPlaybooks ListPlaybooks: %v", err)
+		return nil, err
+	}
+
+	// Репозиторий отдает плейбук организации раньше встроенного
+	seen := make(map[string]bool)
+	effective := make([]*repo.IncidentPlaybook, 0, len(playbooks))
+	for _, playbook := range playbooks {
+		if seen[playbook.Category] {
+			continue
+		}
+		seen[playbook.Category] = true
+		effective = append(effective, playbook)
+	}
+
+	return effective, nil
+}
+
+func (s *IncidentService) GetPlaybook(ctx context.Context, tenantID, category string) (*repo.IncidentPlaybook, error) {
+	log.Printf("DEBUG: incident_service.GetPlaybook tenant=%s category=%s", tenantID, category)
+
+	if !incidentCategories[category] {
+		return nil, NewValidationError("category", "unknown incident category "+category)
+	}
+
+	playbook, err := s.incidentRepo.GetPlaybookForCategory(ctx, tenantID, category)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetPlaybook GetPlaybookForCategory: %v", err)
+		return nil, err
+	}
+	if playbook == nil {
+		return nil, ErrIncidentPlaybookNotFound
+	}
+
+	return playbook, nil
+}
+
+// SetPlaybook replaces the tenant's playbook for the category
+func (s *IncidentService) SetPlaybook(ctx context.Context, tenantID, category string, req dto.IncidentPlaybookRequest) (*repo.IncidentPlaybook, error) {
+	log.Printf("DEBUG: incident_service.SetPlaybook tenant=%s category=%s steps=%d", tenantID, category, len(req.Steps))
+
+	if !incidentCategories[category] {
+		return nil, NewValidationError("category", "unknown incident category "+category)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	now := time.Now()
+	playbook := &repo.IncidentPlaybook{
+		ID:          uuid.New().String(),
+		TenantID:    &tenantID,
+		Category:    category,
+		Name:        req.Name,
+		Description: req.Description,
+		IsActive:    isActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for i, step := range req.Steps {
+		playbook.Steps = append(playbook.Steps, &repo.IncidentPlaybookStep{
+			ID:             uuid.New().String(),
+			StepOrder:      i + 1,
+			ActionType:     step.ActionType,
+			Title:          step.Title,
+			Description:    step.Description,
+			AssigneeRole:   step.AssigneeRole,
+			DueOffsetHours: step.DueOffsetHours,
+		})
+	}
+
+	if err := s.incidentRepo.SavePlaybook(ctx, playbook); err != nil {
+		log.Printf("ERROR: incident_service.SetPlaybook SavePlaybook: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.SetPlaybook saved id=%s category=%s", playbook.ID, category)
+	return playbook, nil
+}
+
+// ResetPlaybook drops the tenant's playbook so the built-in one applies again.
+// Returns the playbook now in effect, or ErrIncidentPlaybookNotFound if the category has none.
+func (s *IncidentService) ResetPlaybook(ctx context.Context, tenantID, category string) (*repo.IncidentPlaybook, error) {
+	log.Printf("DEBUG: incident_service.ResetPlaybook tenant=%s category=%s", tenantID, category)
+
+	if !incidentCategories[category] {
+		return nil, NewValidationError("category", "unknown incident category "+category)
+	}
+
+	if err := s.incidentRepo.DeletePlaybook(ctx, tenantID, category); err != nil {
+		log.Printf("ERROR: incident_service.ResetPlaybook DeletePlaybook: %v", err)
+		return nil, err
+	}
+
+	return s.GetPlaybook(ctx, tenantID, category)
+}
+
+// instantiatePlaybook creates actions for the steps of the playbook matching the incident category.
+// Steps the incident already has are skipped; pending steps of a previous category's playbook are cancelled.
+func (s *IncidentService) instantiatePlaybook(ctx context.Context, incident *repo.Incident, createdBy string) (int, error) {
+	playbook, err := s.incidentRepo.GetPlaybookForCategory(ctx, incident.TenantID, incident.Category)
+	if err != nil {
+		return 0, err
+	}
+
+	keepPlaybookID := ""
+	if playbook != nil && playbook.IsActive {
+		keepPlaybookID = playbook.ID
+	}
+	cancelled, err := s.incidentRepo.CancelPendingPlaybookActions(ctx, incident.ID, keepPlaybookID)
+	if err != nil {
+		return 0, err
+	}
+	if cancelled > 0 {
+		log.Printf("INFO: incident_service.instantiatePlaybook incident=%s cancelled %d steps of previous playbook", incident.ID, cancelled)
+	}
+
+	if keepPlaybookID == "" || len(playbook.Steps) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	actions := make([]*repo.IncidentAction, 0, len(playbook.Steps))
+	for _, step := range playbook.Steps {
+		assignedTo := incident.AssignedTo
+		if step.AssigneeRole != nil {
+			roleUser, err := s.incidentRepo.FindUserByRoleName(ctx, incident.TenantID, *step.AssigneeRole, incident.AssignedTo)
+			if err != nil {
+				return 0, err
+			}
+			if roleUser != nil {
+				assignedTo = roleUser
+			} else {
+				log.Printf("WARN: incident_service.instantiatePlaybook no active user with role %q, step %d falls back to incident assignee", *step.AssigneeRole, step.StepOrder)
+			}
+		}
+
+		var dueDate *time.Time
+		if step.DueOffsetHours != nil {
+			due := now.Add(time.Duration(*step.DueOffsetHours) * time.Hour)
+			dueDate = &due
+		}
+
+		stepOrder := step.StepOrder
+		actions = append(actions, &repo.IncidentAction{
+			ID:          uuid.New().String(),
+			IncidentID:  incident.ID,
+			ActionType:  step.ActionType,
+			Title:       step.Title,
+			Description: step.Description,
+			AssignedTo:  assignedTo,
+			DueDate:     dueDate,
+			Status:      dto.ActionStatusPending,
+			CreatedBy:   createdBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			PlaybookID:  &playbook.ID,
+			StepOrder:   &stepOrder,
+		})
+	}
+
+	created, err := s.incidentRepo.AddPlaybookActions(ctx, actions)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Printf("INFO: incident_service.instantiatePlaybook incident=%s playbook=%s created %d of %d steps", incident.ID, playbook.ID, created, len(actions))
+	return created, nil
+}
+
+// ApplyIncidentPlaybook instantiates the category playbook on demand, e.g. for incidents registered
+// before the playbook was configured
+func (s *IncidentService) ApplyIncidentPlaybook(ctx context.Context, incidentID, tenantID, userID string) (*dto.IncidentPlaybookProgressResponse, error) {
+	log.Printf("DEBUG: incident_service.ApplyIncidentPlaybook incident=%s user=%s", incidentID, userID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ApplyIncidentPlaybook GetByID: %v", err)
+		return nil, err
+	}
+
+	if _, err := s.instantiatePlaybook(ctx, incident, userID); err != nil {
+		log.Printf("ERROR: incident_service.ApplyIncidentPlaybook instantiatePlaybook: %v", err)
+		return nil, err
+	}
+
+	return s.GetIncidentPlaybook(ctx, incidentID, tenantID)
+}
+
+// GetIncidentPlaybook returns the playbook steps of the incident with completion progress.
+// Cancelled steps are listed but not counted.
+func (s *IncidentService) GetIncidentPlaybook(ctx context.Context, incidentID, tenantID string) (*dto.IncidentPlaybookProgressResponse, error) {
+	log.Printf("DEBUG: incident_service.GetIncidentPlaybook incident=%s", incidentID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentPlaybook GetByID: %v", err)
+		return nil, err
+	}
+
+	actions, err := s.incidentRepo.GetActions(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentPlaybook GetActions: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	progress := &dto.IncidentPlaybookProgressResponse{
+		IncidentID: incident.ID,
+		Steps:      []dto.IncidentActionResponse{},
+	}
+	for _, action := range actions {
+		if action.PlaybookID == nil {
+			continue
+		}
+		progress.Steps = append(progress.Steps, incidentActionToResponse(action))
+		if action.Status == dto.ActionStatusCancelled {
+			continue
+		}
+
+		// Текущий плейбук - тот, чьи шаги не отменены
+		progress.PlaybookID = action.PlaybookID
+		progress.PlaybookName = action.PlaybookName
+		progress.TotalSteps++
+		switch {
+		case action.Status == dto.ActionStatusCompleted:
+			progress.CompletedSteps++
+		case action.DueDate != nil && action.DueDate.Before(now):
+			progress.OverdueSteps++
+		}
+	}
+
+	if progress.TotalSteps > 0 {
+		progress.Progress = float64(progress.CompletedSteps) / float64(progress.TotalSteps) * 100
+		progress.IsComplete = progress.CompletedSteps == progress.TotalSteps
+	}
+
+	return progress, nil
+}
+
+// UpdateActionStatus moves an action (playbook step) to a new status and tracks its completion time
+func (s *IncidentService) UpdateActionStatus(ctx context.Context, incidentID, actionID, tenantID string, req dto.IncidentActionStatusRequest, updatedBy string) (*repo.IncidentAction, error) {
+	log.Printf("DEBUG: incident_service.UpdateActionStatus action=%s status=%s user=%s", actionID, req.Status, updatedBy)
+
+	if _, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID); err != nil {
+		log.Printf("ERROR: incident_service.UpdateActionStatus GetByID: %v", err)
+		return nil, err
+	}
+
+	action, err := s.incidentRepo.GetAction(ctx, actionID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.UpdateActionStatus GetAction: %v", err)
+		return nil, err
+	}
+	if action == nil || action.IncidentID != incidentID {
+		return nil, ErrIncidentActionNotFound
+	}
+
+	setActionStatus(action, req.Status)
+	action.UpdatedAt = time.Now()
+
+	if err := s.incidentRepo.UpdateAction(ctx, action); err != nil {
+		log.Printf("ERROR: incident_service.UpdateActionStatus UpdateAction: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.UpdateActionStatus action=%s status=%s", action.ID, action.Status)
+	return action, nil
+}
+
+// setActionStatus keeps completed_at consistent with the status
+func setActionStatus(action *repo.IncidentAction, status string) {
+	if status == action.Status {
+		return
+	}
+	action.Status = status
+	if status == dto.ActionStatusCompleted {
+		now := time.Now()
+		action.CompletedAt = &now
+	} else {
+		action.CompletedAt = nil
+	}
+}
+
+func incidentActionToResponse(action *repo.IncidentAction) dto.IncidentActionResponse {
+	return dto.IncidentActionResponse{
+		ID:           action.ID,
+		IncidentID:   action.IncidentID,
+		ActionType:   action.ActionType,
+		Title:        action.Title,
+		Description:  action.Description,
+		AssignedTo:   action.AssignedTo,
+		DueDate:      action.DueDate,
+		CompletedAt:  action.CompletedAt,
+		Status:       action.Status,
+		CreatedBy:    action.CreatedBy,
+		CreatedAt:    action.CreatedAt,
+		UpdatedAt:    action.UpdatedAt,
+		PlaybookID:   action.PlaybookID,
+		PlaybookName: action.PlaybookName,
+		StepOrder:    action.StepOrder,
+	}
+}
@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlaybookRepo keeps incident actions in memory and applies the same skip and cancel
+// rules as the SQL of AddPlaybookActions and CancelPendingPlaybookActions
+type fakePlaybookRepo struct {
+	IncidentRepoInterface
+
+	playbooks map[string]*repo.IncidentPlaybook // by category
+	roleUsers map[string]string                 // role name -> user id
+	actions   []*repo.IncidentAction
+}
+
+func (r *fakePlaybookRepo) GetPlaybookForCategory(ctx context.Context, tenantID, category string) (*repo.IncidentPlaybook, error) {
+	return r.playbooks[category], nil
+}
+
+func (r *fakePlaybookRepo) FindUserByRoleName(ctx context.Context, tenantID, roleName string, preferredUserID *string) (*string, error) {
+	userID, ok := r.roleUsers[roleName]
+	if !ok {
+		return nil, nil
+	}
+	return &userID, nil
+}
+
+func (r *fakePlaybookRepo) CancelPendingPlaybookActions(ctx context.Context, incidentID, keepPlaybookID string) (int64, error) {
+	var cancelled int64
+	for _, action := range r.actions {
+		if action.IncidentID == incidentID && action.PlaybookID != nil && *action.PlaybookID != keepPlaybookID && action.Status == dto.ActionStatusPending {
+			action.Status = dto.ActionStatusCancelled
+			cancelled++
+		}
+	}
+	return cancelled, nil
+}
+
+func (r *fakePlaybookRepo) AddPlaybookActions(ctx context.Context, actions []*repo.IncidentAction) (int, error) {
+	created := 0
+	for _, action := range actions {
+		if r.hasStep(action) {
+			continue
+		}
+		r.actions = append(r.actions, action)
+		created++
+	}
+	return created, nil
+}
+
+func (r *fakePlaybookRepo) hasStep(action *repo.IncidentAction) bool {
+	for _, existing := range r.actions {
+		if existing.IncidentID == action.IncidentID && *existing.PlaybookID == *action.PlaybookID && *existing.StepOrder == *action.StepOrder {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakePlaybookRepo) GetByID(ctx context.Context, id, tenantID string) (*repo.Incident, error) {
+	return &repo.Incident{ID: id, TenantID: tenantID}, nil
+}
+
+func (r *fakePlaybookRepo) GetActions(ctx context.Context, incidentID string) ([]*repo.IncidentAction, error) {
+	return r.actions, nil
+}
+
+func (r *fakePlaybookRepo) playbookActions(playbookID string) []*repo.IncidentAction {
+	var actions []*repo.IncidentAction
+	for _, action := range r.actions {
+		if *action.PlaybookID == playbookID {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+func testPlaybook(id, category string, steps ...*repo.IncidentPlaybookStep) *repo.IncidentPlaybook {
+	for i, step := range steps {
+		step.StepOrder = i + 1
+	}
+	return &repo.IncidentPlaybook{ID: id, Category: category, Name: category + " playbook", IsActive: true, Steps: steps}
+}
+
+func TestInstantiatePlaybook(t *testing.T) {
+	malware := testPlaybook("playbook-malware", dto.IncidentCategoryMalware,
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeContainment, Title: "Isolate host", AssigneeRole: incidentStringPtr("SOC"), DueOffsetHours: intPtr(4)},
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeInvestigation, Title: "Collect samples", AssigneeRole: incidentStringPtr("Forensics")},
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeRecovery, Title: "Restore from backup"},
+	)
+	incidentRepo := &fakePlaybookRepo{
+		playbooks: map[string]*repo.IncidentPlaybook{dto.IncidentCategoryMalware: malware},
+		roleUsers: map[string]string{"SOC": "user-soc"},
+	}
+	service := NewIncidentService(incidentRepo, nil, nil, nil, nil)
+	incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Category: dto.IncidentCategoryMalware, AssignedTo: incidentStringPtr("user-assignee")}
+
+	before := time.Now()
+	created, err := service.instantiatePlaybook(context.Background(), incident, "user-1")
+	require.NoError(t, err)
+	require.Equal(t, 3, created)
+	require.Len(t, incidentRepo.actions, 3)
+
+	isolate, samples, restore := incidentRepo.actions[0], incidentRepo.actions[1], incidentRepo.actions[2]
+	assert.Equal(t, "user-soc", *isolate.AssignedTo, "the step goes to a holder of its role")
+	assert.Equal(t, "user-assignee", *samples.AssignedTo, "nobody holds the role, the incident assignee takes the step")
+	assert.Equal(t, "user-assignee", *restore.AssignedTo)
+
+	require.NotNil(t, isolate.DueDate)
+	assert.WithinDuration(t, before.Add(4*time.Hour), *isolate.DueDate, time.Minute)
+	assert.Nil(t, samples.DueDate)
+
+	for i, action := range incidentRepo.actions {
+		assert.Equal(t, dto.ActionStatusPending, action.Status)
+		assert.Equal(t, "playbook-malware", *action.PlaybookID)
+		assert.Equal(t, i+1, *action.StepOrder)
+		assert.Equal(t, "user-1", action.CreatedBy)
+	}
+
+	created, err = service.instantiatePlaybook(context.Background(), incident, "user-1")
+	require.NoError(t, err)
+	assert.Zero(t, created, "applying the playbook again skips the steps the incident already has")
+	assert.Len(t, incidentRepo.actions, 3)
+}
+
+func TestInstantiatePlaybook_CategoryChange(t *testing.T) {
+	malware := testPlaybook("playbook-malware", dto.IncidentCategoryMalware,
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeContainment, Title: "Isolate host"},
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeInvestigation, Title: "Collect samples"},
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeRecovery, Title: "Restore from backup"},
+	)
+	breach := testPlaybook("playbook-breach", dto.IncidentCategoryDataBreach,
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeInvestigation, Title: "Scope leaked records"},
+	)
+	inactive := testPlaybook("playbook-physical", dto.IncidentCategoryPhysical,
+		&repo.IncidentPlaybookStep{ActionType: dto.ActionTypeContainment, Title: "Lock the server room"},
+	)
+	inactive.IsActive = false
+
+	tests := []struct {
+		name        string
+		category    string
+		wantCreated int
+	}{
+		{name: "playbook of the new category", category: dto.IncidentCategoryDataBreach, wantCreated: 1},
+		{name: "inactive playbook", category: dto.IncidentCategoryPhysical},
+		{name: "category without playbook", category: dto.IncidentCategoryOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incidentRepo := &fakePlaybookRepo{playbooks: map[string]*repo.IncidentPlaybook{
+				dto.IncidentCategoryMalware:    malware,
+				dto.IncidentCategoryDataBreach: breach,
+				dto.IncidentCategoryPhysical:   inactive,
+			}}
+			service := NewIncidentService(incidentRepo, nil, nil, nil, nil)
+			incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Category: dto.IncidentCategoryMalware}
+
+			_, err := service.instantiatePlaybook(context.Background(), incident, "user-1")
+			require.NoError(t, err)
+			incidentRepo.actions[0].Status = dto.ActionStatusCompleted
+			incidentRepo.actions[1].Status = dto.ActionStatusInProgress
+
+			incident.Category = tt.category
+			created, err := service.instantiatePlaybook(context.Background(), incident, "user-1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCreated, created)
+
+			previous := incidentRepo.playbookActions("playbook-malware")
+			assert.Equal(t, dto.ActionStatusCompleted, previous[0].Status, "finished steps are kept")
+			assert.Equal(t, dto.ActionStatusInProgress, previous[1].Status, "started steps are kept")
+			assert.Equal(t, dto.ActionStatusCancelled, previous[2].Status, "pending steps of the previous playbook are cancelled")
+			assert.Empty(t, incidentRepo.playbookActions("playbook-physical"), "an inactive playbook creates no steps")
+		})
+	}
+}
+
+func TestGetIncidentPlaybook(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	step := func(playbookID, status string, dueDate *time.Time) *repo.IncidentAction {
+		return &repo.IncidentAction{IncidentID: "incident-1", PlaybookID: incidentStringPtr(playbookID), Status: status, DueDate: dueDate}
+	}
+
+	incidentRepo := &fakePlaybookRepo{actions: []*repo.IncidentAction{
+		step("playbook-malware", dto.ActionStatusCancelled, &past),
+		{IncidentID: "incident-1", Title: "Manual action", Status: dto.ActionStatusPending},
+		step("playbook-breach", dto.ActionStatusCompleted, &past),
+		step("playbook-breach", dto.ActionStatusPending, &past),
+		step("playbook-breach", dto.ActionStatusInProgress, &future),
+		step("playbook-breach", dto.ActionStatusCompleted, nil),
+	}}
+	service := NewIncidentService(incidentRepo, nil, nil, nil, nil)
+
+	progress, err := service.GetIncidentPlaybook(context.Background(), "incident-1", "tenant-1")
+	require.NoError(t, err)
+
+	assert.Len(t, progress.Steps, 5, "cancelled steps are listed, manual actions are not")
+	require.NotNil(t, progress.PlaybookID)
+	assert.Equal(t, "playbook-breach", *progress.PlaybookID)
+	assert.Equal(t, 4, progress.TotalSteps, "cancelled steps are not counted")
+	assert.Equal(t, 2, progress.CompletedSteps)
+	assert.Equal(t, 1, progress.OverdueSteps, "a completed step is never overdue")
+	assert.InDelta(t, 50.0, progress.Progress, 0.001)
+	assert.False(t, progress.IsComplete)
+}
+
+func TestSetActionStatus(t *testing.T) {
+	action := &repo.IncidentAction{Status: dto.ActionStatusPending}
+
+	setActionStatus(action, dto.ActionStatusCompleted)
+	require.NotNil(t, action.CompletedAt)
+	completedAt := *action.CompletedAt
+
+	setActionStatus(action, dto.ActionStatusCompleted)
+	assert.Equal(t, completedAt, *action.CompletedAt, "repeating the status keeps the completion time")
+
+	setActionStatus(action, dto.ActionStatusInProgress)
+	assert.Equal(t, dto.ActionStatusInProgress, action.Status)
+	assert.Nil(t, action.CompletedAt, "reopening a step clears its completion time")
+}
@@ -0,0 +1,569 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// regulatoryPresets are ready-made profiles for the common notification regimes
+var regulatoryPresets = []dto.IncidentRegulatoryProfileRequest{
+	{
+		Name:       "GDPR",
+		Framework:  dto.RegulatoryFrameworkGDPR,
+		Categories: []string{dto.IncidentCategoryDataBreach},
+		Obligations: []dto.IncidentRegulatoryObligationRequest{
+			{
+				Title:         "Уведомление надзорного органа (ст. 33 GDPR)",
+				RecipientType: dto.NotificationRecipientRegulator,
+				Recipient:     "Надзорный орган по защите данных",
+				DeadlineHours: 72,
+			},
+			{
+				Title:         "Уведомление субъектов данных (ст. 34 GDPR)",
+				RecipientType: dto.NotificationRecipientDataSubjects,
+				Recipient:     "Затронутые субъекты данных",
+				DeadlineHours: 72,
+				Description:   stringPtr("Требуется при высоком риске для прав и свобод субъектов"),
+			},
+		},
+	},
+	{
+		Name:       "152-ФЗ",
+		Framework:  dto.RegulatoryFramework152FZ,
+		Categories: []string{dto.IncidentCategoryDataBreach},
+		Obligations: []dto.IncidentRegulatoryObligationRequest{
+			{
+				Title:         "Первичное уведомление Роскомнадзора (ч. 3.1 ст. 21 152-ФЗ)",
+				RecipientType: dto.NotificationRecipientRegulator,
+				Recipient:     "Роскомнадзор",
+				DeadlineHours: 24,
+			},
+			{
+				Title:         "Уведомление Роскомнадзора о результатах внутреннего расследования",
+				RecipientType: dto.NotificationRecipientRegulator,
+				Recipient:     "Роскомнадзор",
+				DeadlineHours: 72,
+			},
+		},
+	},
+	{
+		Name:      "ГосСОПКА",
+		Framework: dto.RegulatoryFrameworkGosSOPKA,
+		Categories: []string{
+			dto.IncidentCategoryDataBreach,
+			dto.IncidentCategoryUnauthorizedAccess,
+			dto.IncidentCategoryMalware,
+		},
+		Obligations: []dto.IncidentRegulatoryObligationRequest{
+			{
+				Title:         "Информирование НКЦКИ о компьютерном инциденте",
+				RecipientType: dto.NotificationRecipientCERT,
+				Recipient:     "НКЦКИ",
+				DeadlineHours: 24,
+				Description:   stringPtr("Для значимых объектов КИИ срок - 3 часа"),
+			},
+		},
+	},
+}
+
+// SetTemplateService connects document templates used to draft notifications
+func (s *IncidentService) SetTemplateService(templateService TemplateRendererInterface) {
+	s.templateService = templateService
+}
+
+func (s *IncidentService) ListRegulatoryProfiles(ctx context.Context, tenantID string) ([]*repo.IncidentRegulatoryProfile, error) {
+	log.Printf("DEBUG: incident_service.ListRegulatoryProfiles tenant=%s", tenantID)
+
+	profiles, err := s.incidentRepo.ListRegulatoryProfiles(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListRegulatoryProfiles ListRegulatoryProfiles: %v", err)
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// GetRegulatoryPresets returns the built-in profiles a tenant can start from
+func (s *IncidentService) GetRegulatoryPresets() []dto.IncidentRegulatoryProfileRequest {
+	return regulatoryPresets
+}
+
+func (s *IncidentService) CreateRegulatoryProfile(ctx context.Context, tenantID string, req dto.IncidentRegulatoryProfileRequest) (*repo.IncidentRegulatoryProfile, error) {
+	log.Printf("DEBUG: incident_service.CreateRegulatoryProfile tenant=%s name=%s", tenantID, req.Name)
+
+	profile := &repo.IncidentRegulatoryProfile{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.applyRegulatoryProfileRequest(ctx, profile, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveRegulatoryProfile(ctx, profile); err != nil {
+		log.Printf("ERROR: incident_service.CreateRegulatoryProfile SaveRegulatoryProfile: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.CreateRegulatoryProfile created id=%s", profile.ID)
+	return profile, nil
+}
+
+// CreateRegulatoryProfileFromPreset adds one of the built-in profiles to the tenant
+func (s *IncidentService) CreateRegulatoryProfileFromPreset(ctx context.Context, tenantID, framework string) (*repo.IncidentRegulatoryProfile, error) {
+	for _, preset := range regulatoryPresets {
+		if preset.Framework == framework {
+			return s.CreateRegulatoryProfile(ctx, tenantID, preset)
+		}
+	}
+	return nil, NewValidationError("framework", "no preset for framework "+framework)
+}
+
+func (s *IncidentService) UpdateRegulatoryProfile(ctx context.Context, id, tenantID string, req dto.IncidentRegulatoryProfileRequest) (*repo.IncidentRegulatoryProfile, error) {
+	log.Printf("DEBUG: incident_service.UpdateRegulatoryProfile id=%s", id)
+
+	profile, err := s.incidentRepo.GetRegulatoryProfile(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.UpdateRegulatoryProfile GetRegulatoryProfile: %v", err)
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrRegulatoryProfileNotFound
+	}
+
+	if err := s.applyRegulatoryProfileRequest(ctx, profile, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveRegulatoryProfile(ctx, profile); err != nil {
+		log.Printf("ERROR: incident_service.UpdateRegulatoryProfile SaveRegulatoryProfile: %v", err)
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *IncidentService) DeleteRegulatoryProfile(ctx context.Context, id, tenantID string) error {
+	log.Printf("DEBUG: incident_service.DeleteRegulatoryProfile id=%s", id)
+
+	profile, err := s.incidentRepo.GetRegulatoryProfile(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.DeleteRegulatoryProfile GetRegulatoryProfile: %v", err)
+		return err
+	}
+	if profile == nil {
+		return ErrRegulatoryProfileNotFound
+	}
+
+	// Уже созданные уведомления по инцидентам сохраняются
+	return s.incidentRepo.DeleteRegulatoryProfile(ctx, id, tenantID)
+}
+
+// applyRegulatoryProfileRequest copies the request into the profile. Obligations sent with the ID of an existing
+// obligation of this profile keep it; all others get a new ID.
+func (s *IncidentService) applyRegulatoryProfileRequest(ctx context.Context, profile *repo.IncidentRegulatoryProfile, req dto.IncidentRegulatoryProfileRequest) error {
+	existing := make(map[string]bool)
+	for _, obligation := range profile.Obligations {
+		existing[obligation.ID] = true
+	}
+
+	categories := req.Categories
+	if len(categories) == 0 {
+		categories = []string{dto.IncidentCategoryDataBreach}
+	}
+
+	obligations := make([]*repo.IncidentRegulatoryObligation, 0, len(req.Obligations))
+	for i, o := range req.Obligations {
+		if o.TemplateID != nil && s.templateService != nil {
+			if _, err := s.templateService.GetTemplate(ctx, *o.TemplateID, profile.TenantID); err != nil {
+				return NewValidationError("template_id", "template not found: "+*o.TemplateID)
+			}
+		}
+
+		id := uuid.New().String()
+		if o.ID != nil && existing[*o.ID] {
+			id = *o.ID
+		}
+		obligations = append(obligations, &repo.IncidentRegulatoryObligation{
+			ID:            id,
+			ProfileID:     profile.ID,
+			ProfileName:   req.Name,
+			Position:      i + 1,
+			Title:         o.Title,
+			RecipientType: o.RecipientType,
+			Recipient:     o.Recipient,
+			DeadlineHours: o.DeadlineHours,
+			TemplateID:    o.TemplateID,
+			Description:   o.Description,
+		})
+	}
+
+	profile.Name = req.Name
+	profile.Framework = req.Framework
+	profile.Categories = categories
+	profile.IsActive = req.IsActive == nil || *req.IsActive
+	profile.Obligations = obligations
+	profile.UpdatedAt = time.Now()
+	return nil
+}
+
+// syncRegulatoryNotifications brings the incident's notifications in line with the obligations that apply to it:
+// new obligations start a countdown from DetectedAt, unsent ones follow changes of DetectedAt and deadlines,
+// and unsent ones that no longer apply (e.g. after recategorization) are marked as not required
+func (s *IncidentService) syncRegulatoryNotifications(ctx context.Context, incident *repo.Incident) error {
+	obligations, err := s.incidentRepo.ListApplicableObligations(ctx, incident.TenantID, incident.Category)
+	if err != nil {
+		return err
+	}
+	notifications, err := s.incidentRepo.ListIncidentNotifications(ctx, incident.ID)
+	if err != nil {
+		return err
+	}
+
+	applicable := make(map[string]*repo.IncidentRegulatoryObligation, len(obligations))
+	for _, obligation := range obligations {
+		applicable[obligation.ID] = obligation
+	}
+
+	tracked := make(map[string]bool, len(notifications))
+	now := time.Now()
+	for _, n := range notifications {
+		if n.ObligationID == nil {
+			continue
+		}
+		tracked[*n.ObligationID] = true
+		if n.Status != dto.NotificationStatusPending && n.Status != dto.NotificationStatusDraft {
+			continue
+		}
+
+		obligation, ok := applicable[*n.ObligationID]
+		if !ok {
+			n.Status = dto.NotificationStatusNotRequired
+			if n.Notes == nil {
+				n.Notes = stringPtr("Обязательство не применяется к категории инцидента")
+			}
+		} else {
+			dueAt := incident.DetectedAt.Add(time.Duration(obligation.DeadlineHours) * time.Hour)
+			if dueAt.Equal(n.DueAt) {
+				continue
+			}
+			n.DueAt = dueAt
+		}
+		n.UpdatedAt = now
+		if err := s.incidentRepo.UpdateIncidentNotification(ctx, n); err != nil {
+			return err
+		}
+	}
+
+	var added []*repo.IncidentNotification
+	for _, obligation := range obligations {
+		if tracked[obligation.ID] {
+			continue
+		}
+		obligationID := obligation.ID
+		added = append(added, &repo.IncidentNotification{
+			ID:            uuid.New().String(),
+			TenantID:      incident.TenantID,
+			IncidentID:    incident.ID,
+			ObligationID:  &obligationID,
+			ProfileName:   obligation.ProfileName,
+			Title:         obligation.Title,
+			RecipientType: obligation.RecipientType,
+			Recipient:     obligation.Recipient,
+			DueAt:         incident.DetectedAt.Add(time.Duration(obligation.DeadlineHours) * time.Hour),
+			Status:        dto.NotificationStatusPending,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	created, err := s.incidentRepo.AddIncidentNotifications(ctx, added)
+	if err != nil {
+		return err
+	}
+	if created > 0 {
+		earliest := added[0].DueAt
+		for _, n := range added[1:] {
+			if n.DueAt.Before(earliest) {
+				earliest = n.DueAt
+			}
+		}
+		s.notifyIncidentOwner(ctx, incident, dto.UserNotificationRegulatoryDeadlineStarted,
+			"Запущены сроки уведомления регулятора",
+			fmt.Sprintf("По инциденту «%s» требуется уведомить регулятора: обязательств %d, ближайший срок %s.",
+				incident.Title, created, earliest.Format("02.01.2006 15:04")))
+	}
+	return nil
+}
+
+// RunRegulatoryDeadlineMonitor periodically alerts the incident owner about regulatory notifications
+// that enter the last quarter of their deadline or become overdue, until ctx is cancelled
+func (s *IncidentService) RunRegulatoryDeadlineMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.checkRegulatoryDeadlines(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkRegulatoryDeadlines(ctx)
+		}
+	}
+}
+
+func (s *IncidentService) checkRegulatoryDeadlines(ctx context.Context) {
+	if s.notifier == nil {
+		return
+	}
+
+	now := time.Now()
+	notifications, err := s.incidentRepo.ListNotificationsForDeadlineAlerts(ctx, now)
+	if err != nil {
+		log.Printf("ERROR: IncidentService regulatory deadline monitor ListNotificationsForDeadlineAlerts: %v", err)
+		return
+	}
+
+	for _, n := range notifications {
+		incident, err := s.incidentRepo.GetByID(ctx, n.IncidentID, n.TenantID)
+		if err != nil {
+			log.Printf("ERROR: IncidentService regulatory deadline monitor GetByID %s: %v", n.IncidentID, err)
+			continue
+		}
+
+		overdue := n.DueAt.Before(now)
+		if overdue {
+			s.notifyIncidentOwner(ctx, incident, dto.UserNotificationRegulatoryDeadlineOverdue,
+				"Просрочено уведомление регулятора",
+				fmt.Sprintf("Срок «%s» (%s) по инциденту «%s» истёк %s, уведомление не отправлено.",
+					n.Title, n.ProfileName, incident.Title, n.DueAt.Format("02.01.2006 15:04")))
+		} else {
+			s.notifyIncidentOwner(ctx, incident, dto.UserNotificationRegulatoryDeadlineDueSoon,
+				"Истекает срок уведомления регулятора",
+				fmt.Sprintf("Срок «%s» (%s) по инциденту «%s» истекает %s.",
+					n.Title, n.ProfileName, incident.Title, n.DueAt.Format("02.01.2006 15:04")))
+		}
+
+		if err := s.incidentRepo.MarkNotificationDeadlineAlerted(ctx, n.ID, overdue, now); err != nil {
+			log.Printf("ERROR: IncidentService regulatory deadline monitor MarkNotificationDeadlineAlerted %s: %v", n.ID, err)
+		}
+	}
+}
+
+// notifyIncidentOwner puts a notification into the inbox of the incident assignee, or the reporter if unassigned
+func (s *IncidentService) notifyIncidentOwner(ctx context.Context, incident *repo.Incident, notificationType, title, message string) {
+	if s.notifier == nil {
+		return
+	}
+	recipient := incident.ReportedBy
+	if incident.AssignedTo != nil && *incident.AssignedTo != "" {
+		recipient = *incident.AssignedTo
+	}
+	if err := s.notifier.Notify(ctx, incident.TenantID, recipient, notificationType, title, message, "incident", incident.ID); err != nil {
+		log.Printf("ERROR: incident_service.notifyIncidentOwner Notify: %v", err)
+	}
+}
+
+// GetIncidentNotifications returns the regulatory notifications of the incident, creating any that are missing
+func (s *IncidentService) GetIncidentNotifications(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentNotification, error) {
+	log.Printf("DEBUG: incident_service.GetIncidentNotifications incident=%s", incidentID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentNotifications GetByID: %v", err)
+		return nil, err
+	}
+
+	if err := s.syncRegulatoryNotifications(ctx, incident); err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentNotifications syncRegulatoryNotifications: %v", err)
+		return nil, err
+	}
+
+	notifications, err := s.incidentRepo.ListIncidentNotifications(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentNotifications ListIncidentNotifications: %v", err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GenerateNotificationDraft renders the obligation's template (or the built-in one) with the incident data
+func (s *IncidentService) GenerateNotificationDraft(ctx context.Context, incidentID, notificationID, tenantID string) (*repo.IncidentNotification, error) {
+	log.Printf("DEBUG: incident_service.GenerateNotificationDraft incident=%s notification=%s", incidentID, notificationID)
+
+	if s.templateService == nil {
+		return nil, fmt.Errorf("template service is not configured")
+	}
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateNotificationDraft GetByID: %v", err)
+		return nil, err
+	}
+
+	notification, err := s.incidentRepo.GetIncidentNotification(ctx, notificationID, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateNotificationDraft GetIncidentNotification: %v", err)
+		return nil, err
+	}
+	if notification == nil {
+		return nil, ErrIncidentNotificationNotFound
+	}
+
+	content, err := s.notificationTemplate(ctx, notification)
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := s.incidentRepo.GetAssets(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateNotificationDraft GetAssets: %v", err)
+		return nil, err
+	}
+	assetNames := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		assetNames = append(assetNames, asset.Name)
+	}
+
+	data := map[string]interface{}{
+		"incident_title":       incident.Title,
+		"incident_description": safeString(incident.Description),
+		"incident_category":    incident.Category,
+		"incident_criticality": incident.Criticality,
+		"detected_at":          incident.DetectedAt.Format("02.01.2006 15:04"),
+		"affected_assets":      strings.Join(assetNames, ", "),
+		"root_cause":           safeString(incident.RootCause),
+		"resolution":           safeString(incident.Resolution),
+		"notification_title":   notification.Title,
+		"recipient":            notification.Recipient,
+		"deadline":             notification.DueAt.Format("02.01.2006 15:04"),
+		"current_date":         time.Now().Format("02.01.2006"),
+		"current_datetime":     time.Now().Format("02.01.2006 15:04"),
+	}
+	draft := s.templateService.RenderTemplate(content, data)
+
+	notification.DraftContent = &draft
+	if notification.Status == dto.NotificationStatusPending {
+		notification.Status = dto.NotificationStatusDraft
+	}
+	notification.UpdatedAt = time.Now()
+
+	if err := s.incidentRepo.UpdateIncidentNotification(ctx, notification); err != nil {
+		log.Printf("ERROR: incident_service.GenerateNotificationDraft UpdateIncidentNotification: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.GenerateNotificationDraft drafted notification=%s", notification.ID)
+	return notification, nil
+}
+
+// notificationTemplate returns the content of the obligation's template, falling back to the built-in one
+func (s *IncidentService) notificationTemplate(ctx context.Context, notification *repo.IncidentNotification) (string, error) {
+	if notification.TemplateID != nil {
+		template, err := s.templateService.GetTemplate(ctx, *notification.TemplateID, notification.TenantID)
+		if err == nil {
+			return template.Content, nil
+		}
+		log.Printf("WARN: incident_service.notificationTemplate template %s unavailable, using built-in: %v", *notification.TemplateID, err)
+	}
+
+	content, err := templatesFS.ReadFile("templates/breach_notification.html")
+	if err != nil {
+		return "", ErrNotificationTemplateNotFound
+	}
+	return string(content), nil
+}
+
+// UpdateIncidentNotification records sending of a notification or edits its draft
+func (s *IncidentService) UpdateIncidentNotification(ctx context.Context, incidentID, notificationID, tenantID string, req dto.IncidentNotificationUpdateRequest, updatedBy string) (*repo.IncidentNotification, error) {
+	log.Printf("DEBUG: incident_service.UpdateIncidentNotification notification=%s user=%s", notificationID, updatedBy)
+
+	if _, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID); err != nil {
+		log.Printf("ERROR: incident_service.UpdateIncidentNotification GetByID: %v", err)
+		return nil, err
+	}
+
+	notification, err := s.incidentRepo.GetIncidentNotification(ctx, notificationID, incidentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.UpdateIncidentNotification GetIncidentNotification: %v", err)
+		return nil, err
+	}
+	if notification == nil {
+		return nil, ErrIncidentNotificationNotFound
+	}
+
+	if req.DraftContent != nil {
+		notification.DraftContent = req.DraftContent
+	}
+	if req.SentTo != nil {
+		notification.SentTo = req.SentTo
+	}
+	if req.Channel != nil {
+		notification.Channel = req.Channel
+	}
+	if req.ReferenceNumber != nil {
+		notification.ReferenceNumber = req.ReferenceNumber
+	}
+	if req.Notes != nil {
+		notification.Notes = req.Notes
+	}
+
+	if req.Status != nil {
+		notification.Status = *req.Status
+		if *req.Status == dto.NotificationStatusSent {
+			sentAt := time.Now()
+			if req.SentAt != nil {
+				sentAt = *req.SentAt
+			}
+			notification.SentAt = &sentAt
+			notification.SentBy = &updatedBy
+			if notification.SentTo == nil {
+				recipient := notification.Recipient
+				notification.SentTo = &recipient
+			}
+		} else {
+			notification.SentAt = nil
+			notification.SentBy = nil
+		}
+	} else if req.SentAt != nil && notification.Status == dto.NotificationStatusSent {
+		// Уточнение времени отправки уже отправленного уведомления
+		notification.SentAt = req.SentAt
+	}
+	notification.UpdatedAt = time.Now()
+
+	if err := s.incidentRepo.UpdateIncidentNotification(ctx, notification); err != nil {
+		log.Printf("ERROR: incident_service.UpdateIncidentNotification UpdateIncidentNotification: %v", err)
+		return nil, err
+	}
+
+	if notification.Status == dto.NotificationStatusSent && notification.SentAt.After(notification.DueAt) {
+		log.Printf("WARN: incident_service.UpdateIncidentNotification notification=%s sent after deadline %s", notification.ID, notification.DueAt.Format(time.RFC3339))
+	}
+	return notification, nil
+}
+
+// ListOverdueNotifications returns the tenant's unsent notifications whose deadline has passed
+func (s *IncidentService) ListOverdueNotifications(ctx context.Context, tenantID string) ([]*repo.IncidentNotification, error) {
+	log.Printf("DEBUG: incident_service.ListOverdueNotifications tenant=%s", tenantID)
+
+	notifications, err := s.incidentRepo.ListOverdueNotifications(ctx, tenantID, time.Now())
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListOverdueNotifications ListOverdueNotifications: %v", err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegulatoryRepo serves obligations per incident category and keeps notifications in memory
+type fakeRegulatoryRepo struct {
+	IncidentRepoInterface
+
+	incident      *repo.Incident
+	obligations   map[string][]*repo.IncidentRegulatoryObligation // by category
+	notifications []*repo.IncidentNotification
+	alerted       map[string]bool // notification id -> overdue
+}
+
+func (r *fakeRegulatoryRepo) GetByID(ctx context.Context, id, tenantID string) (*repo.Incident, error) {
+	return r.incident, nil
+}
+
+func (r *fakeRegulatoryRepo) ListApplicableObligations(ctx context.Context, tenantID, category string) ([]*repo.IncidentRegulatoryObligation, error) {
+	return r.obligations[category], nil
+}
+
+func (r *fakeRegulatoryRepo) ListIncidentNotifications(ctx context.Context, incidentID string) ([]*repo.IncidentNotification, error) {
+	return r.notifications, nil
+}
+
+func (r *fakeRegulatoryRepo) AddIncidentNotifications(ctx context.Context, notifications []*repo.IncidentNotification) (int, error) {
+	r.notifications = append(r.notifications, notifications...)
+	return len(notifications), nil
+}
+
+func (r *fakeRegulatoryRepo) UpdateIncidentNotification(ctx context.Context, notification *repo.IncidentNotification) error {
+	return nil
+}
+
+func (r *fakeRegulatoryRepo) GetIncidentNotification(ctx context.Context, id, incidentID string) (*repo.IncidentNotification, error) {
+	for _, n := range r.notifications {
+		if n.ID == id && n.IncidentID == incidentID {
+			return n, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRegulatoryRepo) ListNotificationsForDeadlineAlerts(ctx context.Context, now time.Time) ([]*repo.IncidentNotification, error) {
+	return r.notifications, nil
+}
+
+func (r *fakeRegulatoryRepo) MarkNotificationDeadlineAlerted(ctx context.Context, id string, overdue bool, alertedAt time.Time) error {
+	r.alerted[id] = overdue
+	return nil
+}
+
+// notificationByObligation returns the incident notification of the obligation
+func (r *fakeRegulatoryRepo) notificationByObligation(obligationID string) *repo.IncidentNotification {
+	for _, n := range r.notifications {
+		if n.ObligationID != nil && *n.ObligationID == obligationID {
+			return n
+		}
+	}
+	return nil
+}
+
+// fakeUserNotifier records inbox notifications
+type fakeUserNotifier struct {
+	sent []fakeUserNotification
+}
+
+type fakeUserNotification struct {
+	userID, notificationType, message string
+}
+
+func (n *fakeUserNotifier) Notify(ctx context.Context, tenantID, userID, notificationType, title, message, entity, entityID string) error {
+	n.sent = append(n.sent, fakeUserNotification{userID: userID, notificationType: notificationType, message: message})
+	return nil
+}
+
+func newRegulatoryTestService(incident *repo.Incident) (*IncidentService, *fakeRegulatoryRepo, *fakeUserNotifier) {
+	obligation := func(id string, deadlineHours int) *repo.IncidentRegulatoryObligation {
+		return &repo.IncidentRegulatoryObligation{ID: id, Title: id, RecipientType: dto.NotificationRecipientRegulator, Recipient: "Роскомнадзор", DeadlineHours: deadlineHours}
+	}
+	incidentRepo := &fakeRegulatoryRepo{
+		incident: incident,
+		obligations: map[string][]*repo.IncidentRegulatoryObligation{
+			dto.IncidentCategoryDataBreach: {obligation("gdpr-art33", 72), obligation("152fz-initial", 24)},
+			dto.IncidentCategoryMalware:    {obligation("gossopka", 24)},
+		},
+		alerted: map[string]bool{},
+	}
+	notifier := &fakeUserNotifier{}
+	service := NewIncidentService(incidentRepo, nil, nil, nil, nil)
+	service.SetNotifier(notifier)
+	return service, incidentRepo, notifier
+}
+
+func TestSyncRegulatoryNotifications(t *testing.T) {
+	detectedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Title: "Leak", Category: dto.IncidentCategoryDataBreach,
+		DetectedAt: detectedAt, ReportedBy: "user-reporter", AssignedTo: incidentStringPtr("user-assignee")}
+	service, incidentRepo, notifier := newRegulatoryTestService(incident)
+	ctx := context.Background()
+
+	require.NoError(t, service.syncRegulatoryNotifications(ctx, incident))
+	require.Len(t, incidentRepo.notifications, 2)
+	gdpr := incidentRepo.notificationByObligation("gdpr-art33")
+	initial := incidentRepo.notificationByObligation("152fz-initial")
+	assert.Equal(t, detectedAt.Add(72*time.Hour), gdpr.DueAt, "the countdown starts at DetectedAt, not at registration")
+	assert.Equal(t, detectedAt.Add(24*time.Hour), initial.DueAt)
+	assert.Equal(t, dto.NotificationStatusPending, gdpr.Status)
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "user-assignee", notifier.sent[0].userID)
+	assert.Equal(t, dto.UserNotificationRegulatoryDeadlineStarted, notifier.sent[0].notificationType)
+	assert.Contains(t, notifier.sent[0].message, "03.03.2026 09:00", "the earliest deadline is announced")
+
+	require.NoError(t, service.syncRegulatoryNotifications(ctx, incident))
+	assert.Len(t, incidentRepo.notifications, 2, "tracked obligations are not added again")
+	assert.Len(t, notifier.sent, 1)
+
+	initial.Status = dto.NotificationStatusSent
+	incident.DetectedAt = detectedAt.Add(-6 * time.Hour)
+	require.NoError(t, service.syncRegulatoryNotifications(ctx, incident))
+	assert.Equal(t, incident.DetectedAt.Add(72*time.Hour), gdpr.DueAt, "an unsent deadline follows a corrected DetectedAt")
+	assert.Equal(t, detectedAt.Add(24*time.Hour), initial.DueAt, "a sent notification keeps its deadline")
+}
+
+func TestSyncRegulatoryNotifications_Recategorized(t *testing.T) {
+	incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Title: "Leak", Category: dto.IncidentCategoryDataBreach,
+		DetectedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), ReportedBy: "user-reporter"}
+	service, incidentRepo, notifier := newRegulatoryTestService(incident)
+	ctx := context.Background()
+
+	require.NoError(t, service.syncRegulatoryNotifications(ctx, incident))
+	incidentRepo.notificationByObligation("152fz-initial").Status = dto.NotificationStatusSent
+	incidentRepo.notificationByObligation("gdpr-art33").Status = dto.NotificationStatusDraft
+
+	incident.Category = dto.IncidentCategoryMalware
+	require.NoError(t, service.syncRegulatoryNotifications(ctx, incident))
+
+	gdpr := incidentRepo.notificationByObligation("gdpr-art33")
+	assert.Equal(t, dto.NotificationStatusNotRequired, gdpr.Status, "unsent notifications of obligations that no longer apply are dropped")
+	require.NotNil(t, gdpr.Notes)
+	assert.Equal(t, dto.NotificationStatusSent, incidentRepo.notificationByObligation("152fz-initial").Status, "sent notifications stay on record")
+	require.NotNil(t, incidentRepo.notificationByObligation("gossopka"), "the obligations of the new category start")
+
+	require.Len(t, notifier.sent, 2)
+	assert.Equal(t, "user-reporter", notifier.sent[1].userID, "an unassigned incident notifies the reporter")
+}
+
+func TestCheckRegulatoryDeadlines(t *testing.T) {
+	now := time.Now()
+	incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Title: "Leak", ReportedBy: "user-reporter"}
+	service, incidentRepo, notifier := newRegulatoryTestService(incident)
+	incidentRepo.notifications = []*repo.IncidentNotification{
+		{ID: "overdue", IncidentID: "incident-1", TenantID: "tenant-1", Title: "Роскомнадзор", DueAt: now.Add(-time.Hour)},
+		{ID: "due-soon", IncidentID: "incident-1", TenantID: "tenant-1", Title: "GDPR", DueAt: now.Add(time.Hour)},
+	}
+
+	service.checkRegulatoryDeadlines(context.Background())
+
+	require.Len(t, notifier.sent, 2)
+	assert.Equal(t, dto.UserNotificationRegulatoryDeadlineOverdue, notifier.sent[0].notificationType)
+	assert.Equal(t, dto.UserNotificationRegulatoryDeadlineDueSoon, notifier.sent[1].notificationType)
+	assert.Equal(t, map[string]bool{"overdue": true, "due-soon": false}, incidentRepo.alerted)
+}
+
+func TestUpdateIncidentNotification_Sent(t *testing.T) {
+	incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1"}
+	service, incidentRepo, _ := newRegulatoryTestService(incident)
+	incidentRepo.notifications = []*repo.IncidentNotification{
+		{ID: "notification-1", IncidentID: "incident-1", Recipient: "Роскомнадзор", Status: dto.NotificationStatusDraft, DueAt: time.Now().Add(time.Hour)},
+	}
+	ctx := context.Background()
+	sent, draft := dto.NotificationStatusSent, dto.NotificationStatusDraft
+
+	notification, err := service.UpdateIncidentNotification(ctx, "incident-1", "notification-1", "tenant-1",
+		dto.IncidentNotificationUpdateRequest{Status: &sent}, "user-1")
+	require.NoError(t, err)
+	require.NotNil(t, notification.SentAt)
+	assert.WithinDuration(t, time.Now(), *notification.SentAt, time.Minute)
+	assert.Equal(t, "user-1", *notification.SentBy)
+	assert.Equal(t, "Роскомнадзор", *notification.SentTo, "the obligation recipient is recorded by default")
+
+	sentAt := time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)
+	notification, err = service.UpdateIncidentNotification(ctx, "incident-1", "notification-1", "tenant-1",
+		dto.IncidentNotificationUpdateRequest{SentAt: &sentAt}, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, sentAt, *notification.SentAt, "the sending time of a sent notification can be corrected")
+	assert.Equal(t, "user-1", *notification.SentBy)
+
+	notification, err = service.UpdateIncidentNotification(ctx, "incident-1", "notification-1", "tenant-1",
+		dto.IncidentNotificationUpdateRequest{Status: &draft}, "user-1")
+	require.NoError(t, err)
+	assert.Nil(t, notification.SentAt)
+	assert.Nil(t, notification.SentBy)
+
+	_, err = service.UpdateIncidentNotification(ctx, "incident-1", "missing", "tenant-1", dto.IncidentNotificationUpdateRequest{}, "user-1")
+	assert.ErrorIs(t, err, ErrIncidentNotificationNotFound)
+}
+
+func TestRegulatoryPresets(t *testing.T) {
+	deadlines := map[string][]int{}
+	for _, preset := range regulatoryPresets {
+		assert.Contains(t, preset.Categories, dto.IncidentCategoryDataBreach)
+		for _, obligation := range preset.Obligations {
+			deadlines[preset.Framework] = append(deadlines[preset.Framework], obligation.DeadlineHours)
+		}
+	}
+
+	assert.Equal(t, []int{72, 72}, deadlines[dto.RegulatoryFrameworkGDPR])
+	assert.Equal(t, []int{24, 72}, deadlines[dto.RegulatoryFramework152FZ])
+	assert.Equal(t, []int{24}, deadlines[dto.RegulatoryFrameworkGosSOPKA])
+}
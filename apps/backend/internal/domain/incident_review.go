@@ -0,0 +1,571 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// SetRiskService connects risk creation used by review follow-ups
+func (s *IncidentService) SetRiskService(riskService IncidentRiskServiceInterface) {
+	s.riskService = riskService
+}
+
+// GetIncidentReview returns the post-incident review, or ErrIncidentReviewNotFound if it was not started yet
+func (s *IncidentService) GetIncidentReview(ctx context.Context, incidentID, tenantID string) (*repo.IncidentReview, error) {
+	log.Printf("DEBUG: incident_service.GetIncidentReview incident=%s", incidentID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentReview GetByID: %v", err)
+		return nil, err
+	}
+
+	review, err := s.incidentRepo.GetReview(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentReview GetReview: %v", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrIncidentReviewNotFound
+	}
+	return review, nil
+}
+
+// SaveIncidentReview creates or updates the review of a resolved or closed incident
+func (s *IncidentService) SaveIncidentReview(ctx context.Context, incidentID, tenantID string, req dto.IncidentReviewRequest, userID string) (*repo.IncidentReview, error) {
+	log.Printf("DEBUG: incident_service.SaveIncidentReview incident=%s user=%s", incidentID, userID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.SaveIncidentReview GetByID: %v", err)
+		return nil, err
+	}
+	if incident.Status != dto.IncidentStatusResolved && incident.Status != dto.IncidentStatusClosed {
+		return nil, NewValidationError("status", "review is available only for resolved or closed incidents")
+	}
+
+	review, err := s.incidentRepo.GetReview(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.SaveIncidentReview GetReview: %v", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if review == nil {
+		review = &repo.IncidentReview{
+			ID:         uuid.New().String(),
+			TenantID:   tenantID,
+			IncidentID: incident.ID,
+			Status:     dto.IncidentReviewStatusDraft,
+			RootCause:  incident.RootCause,
+			CreatedBy:  &userID,
+			CreatedAt:  now,
+		}
+	}
+
+	if req.Summary != nil {
+		review.Summary = req.Summary
+	}
+	if req.RootCause != nil {
+		review.RootCause = req.RootCause
+	}
+	if req.Impact != nil {
+		review.Impact = req.Impact
+	}
+	if req.WhatWentWell != nil {
+		review.WhatWentWell = req.WhatWentWell
+	}
+	if req.WhatWentWrong != nil {
+		review.WhatWentWrong = req.WhatWentWrong
+	}
+	if req.LessonsLearned != nil {
+		review.LessonsLearned = req.LessonsLearned
+	}
+	if req.ReviewDate != nil {
+		reviewDate, err := time.Parse("2006-01-02", *req.ReviewDate)
+		if err != nil {
+			return nil, NewValidationError("review_date", "invalid date format")
+		}
+		review.ReviewDate = &reviewDate
+	}
+	if req.FacilitatorID != nil {
+		review.FacilitatorID = req.FacilitatorID
+	}
+	if req.Participants != nil {
+		review.Participants = req.Participants
+	}
+	if review.Participants == nil {
+		review.Participants = []string{}
+	}
+	if req.Factors != nil {
+		review.Factors = make([]*repo.IncidentReviewFactor, 0, len(req.Factors))
+		for i, factor := range req.Factors {
+			review.Factors = append(review.Factors, &repo.IncidentReviewFactor{
+				ID:          uuid.New().String(),
+				Position:    i + 1,
+				FactorType:  factor.FactorType,
+				Description: strings.TrimSpace(factor.Description),
+			})
+		}
+	}
+
+	if req.Status != nil && *req.Status != review.Status {
+		review.Status = *req.Status
+		if review.Status == dto.IncidentReviewStatusCompleted {
+			review.CompletedAt = &now
+		} else {
+			review.CompletedAt = nil
+		}
+	}
+	if review.Status == dto.IncidentReviewStatusCompleted && (review.RootCause == nil || strings.TrimSpace(*review.RootCause) == "") {
+		return nil, NewValidationError("root_cause", "root cause is required to complete the review")
+	}
+	review.UpdatedAt = now
+
+	if err := s.incidentRepo.SaveReview(ctx, review); err != nil {
+		log.Printf("ERROR: incident_service.SaveIncidentReview SaveReview: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.SaveIncidentReview saved review=%s status=%s", review.ID, review.Status)
+	return review, nil
+}
+
+// AddReviewFollowUp records a follow-up; risk follow-ups create a linked risk, control follow-ups add a control to a risk
+func (s *IncidentService) AddReviewFollowUp(ctx context.Context, incidentID, tenantID string, req dto.IncidentReviewFollowUpRequest, userID string) (*repo.IncidentReviewFollowUp, error) {
+	log.Printf("DEBUG: incident_service.AddReviewFollowUp incident=%s type=%s user=%s", incidentID, req.FollowUpType, userID)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.AddReviewFollowUp GetByID: %v", err)
+		return nil, err
+	}
+
+	review, err := s.incidentRepo.GetReview(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.AddReviewFollowUp GetReview: %v", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrIncidentReviewNotFound
+	}
+
+	now := time.Now()
+	followUp := &repo.IncidentReviewFollowUp{
+		ID:           uuid.New().String(),
+		ReviewID:     review.ID,
+		FollowUpType: req.FollowUpType,
+		Title:        strings.TrimSpace(req.Title),
+		Description:  req.Description,
+		OwnerID:      req.OwnerID,
+		Status:       dto.ReviewFollowUpStatusOpen,
+		CreatedBy:    &userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if req.DueDate != nil {
+		dueDate, err := time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			return nil, NewValidationError("due_date", "invalid date format")
+		}
+		followUp.DueDate = &dueDate
+	}
+
+	if followUp.FollowUpType != dto.ReviewFollowUpTypeAction && s.riskService == nil {
+		return nil, fmt.Errorf("risk service is not configured")
+	}
+	if followUp.FollowUpType == dto.ReviewFollowUpTypeControl {
+		if err := s.checkFollowUpControlRisk(ctx, tenantID, req.RiskID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The follow-up is stored before the risk or control it creates, so a rejected follow-up
+	// leaves nothing behind and a failed risk or control removes the follow-up again
+	if err := s.incidentRepo.AddReviewFollowUp(ctx, followUp); err != nil {
+		log.Printf("ERROR: incident_service.AddReviewFollowUp AddReviewFollowUp: %v", err)
+		return nil, err
+	}
+
+	var riskID, controlID string
+	switch req.FollowUpType {
+	case dto.ReviewFollowUpTypeRisk:
+		riskID, err = s.createFollowUpRisk(ctx, incident, followUp, req)
+	case dto.ReviewFollowUpTypeControl:
+		riskID = *req.RiskID
+		controlID, err = s.createFollowUpControl(ctx, riskID, followUp, req, userID)
+	}
+	if err != nil {
+		s.removeReviewFollowUp(ctx, followUp)
+		return nil, err
+	}
+
+	if riskID != "" {
+		followUp.RiskID = &riskID
+		if controlID != "" {
+			followUp.ControlID = &controlID
+		}
+		if err := s.incidentRepo.LinkReviewFollowUp(ctx, followUp); err != nil {
+			log.Printf("ERROR: incident_service.AddReviewFollowUp LinkReviewFollowUp: %v", err)
+			if followUp.FollowUpType == dto.ReviewFollowUpTypeRisk {
+				s.removeFollowUpRisk(ctx, riskID)
+			}
+			s.removeReviewFollowUp(ctx, followUp)
+			return nil, err
+		}
+	}
+
+	log.Printf("INFO: incident_service.AddReviewFollowUp added follow-up=%s type=%s", followUp.ID, followUp.FollowUpType)
+	return followUp, nil
+}
+
+// createFollowUpRisk registers a new risk and links it to the incident
+func (s *IncidentService) createFollowUpRisk(ctx context.Context, incident *repo.Incident, followUp *repo.IncidentReviewFollowUp, req dto.IncidentReviewFollowUpRequest) (string, error) {
+	// The incident has already happened, so the default likelihood is high; impact follows the incident criticality
+	likelihood := 3
+	if req.Likelihood != nil {
+		likelihood = *req.Likelihood
+	}
+	impact := incidentCriticalityImpact(incident.Criticality)
+	if req.Impact != nil {
+		impact = *req.Impact
+	}
+	category := req.RiskCategory
+	if category == nil {
+		category = stringPtr("technical")
+	}
+	description := followUp.Description
+	if description == nil {
+		description = stringPtr(fmt.Sprintf("Риск выявлен по итогам разбора инцидента «%s»", incident.Title))
+	}
+
+	risk, err := s.riskService.CreateRisk(ctx, incident.TenantID, followUp.Title, description, category,
+		likelihood, impact, followUp.OwnerID, incident.AssetID, nil, nil, nil, nil, followUp.DueDate)
+	if err != nil {
+		log.Printf("ERROR: incident_service.createFollowUpRisk CreateRisk: %v", err)
+		return "", err
+	}
+
+	if err := s.incidentRepo.AddRisk(ctx, incident.ID, risk.ID); err != nil {
+		log.Printf("ERROR: incident_service.createFollowUpRisk AddRisk: %v", err)
+		s.removeFollowUpRisk(ctx, risk.ID)
+		return "", err
+	}
+	return risk.ID, nil
+}
+
+// removeFollowUpRisk deletes a risk created for a follow-up that could not be saved
+func (s *IncidentService) removeFollowUpRisk(ctx context.Context, riskID string) {
+	if err := s.riskService.DeleteRisk(ctx, riskID); err != nil {
+		log.Printf("ERROR: incident_service.removeFollowUpRisk DeleteRisk %s: %v", riskID, err)
+	}
+}
+
+// removeReviewFollowUp deletes a follow-up whose risk or control could not be created
+func (s *IncidentService) removeReviewFollowUp(ctx context.Context, followUp *repo.IncidentReviewFollowUp) {
+	if err := s.incidentRepo.DeleteReviewFollowUp(ctx, followUp.ID, followUp.ReviewID); err != nil {
+		log.Printf("ERROR: incident_service.removeReviewFollowUp DeleteReviewFollowUp %s: %v", followUp.ID, err)
+	}
+}
+
+// checkFollowUpControlRisk checks that a control follow-up names an existing risk of the tenant
+func (s *IncidentService) checkFollowUpControlRisk(ctx context.Context, tenantID string, riskID *string) error {
+	if riskID == nil {
+		return NewValidationError("risk_id", "risk is required for a control follow-up")
+	}
+	risk, err := s.riskRepo.GetByIDWithTenant(ctx, *riskID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.checkFollowUpControlRisk GetByIDWithTenant: %v", err)
+		return err
+	}
+	if risk == nil {
+		return NewValidationError("risk_id", "risk not found")
+	}
+	return nil
+}
+
+// createFollowUpControl adds a planned control to a risk of the tenant
+func (s *IncidentService) createFollowUpControl(ctx context.Context, riskID string, followUp *repo.IncidentReviewFollowUp, req dto.IncidentReviewFollowUpRequest, userID string) (string, error) {
+	controlType := "preventive"
+	if req.ControlType != nil {
+		controlType = *req.ControlType
+	}
+	implementationStatus := "planned"
+	if req.ImplementationStatus != nil {
+		implementationStatus = *req.ImplementationStatus
+	}
+
+	controlID := uuid.New().String()
+	err := s.riskService.AddControl(ctx, riskID, controlID, followUp.Title, controlType, implementationStatus,
+		nil, followUp.Description, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.createFollowUpControl AddControl: %v", err)
+		return "", err
+	}
+	return controlID, nil
+}
+
+// incidentCriticalityImpact maps incident criticality onto the 1-4 risk impact scale
+func incidentCriticalityImpact(criticality string) int {
+	switch criticality {
+	case dto.IncidentCriticalityCritical:
+		return 4
+	case dto.IncidentCriticalityHigh:
+		return 3
+	case dto.IncidentCriticalityMedium:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (s *IncidentService) UpdateReviewFollowUp(ctx context.Context, incidentID, followUpID, tenantID string, req dto.IncidentReviewFollowUpUpdateRequest) (*repo.IncidentReviewFollowUp, error) {
+	log.Printf("DEBUG: incident_service.UpdateReviewFollowUp follow-up=%s", followUpID)
+
+	followUp, err := s.getReviewFollowUp(ctx, incidentID, followUpID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		followUp.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		followUp.Description = req.Description
+	}
+	if req.OwnerID != nil {
+		followUp.OwnerID = req.OwnerID
+	}
+	if req.DueDate != nil {
+		dueDate, err := time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			return nil, NewValidationError("due_date", "invalid date format")
+		}
+		followUp.DueDate = &dueDate
+	}
+	if req.Status != nil {
+		followUp.Status = *req.Status
+	}
+	followUp.UpdatedAt = time.Now()
+
+	if err := s.incidentRepo.UpdateReviewFollowUp(ctx, followUp); err != nil {
+		log.Printf("ERROR: incident_service.UpdateReviewFollowUp UpdateReviewFollowUp: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.UpdateReviewFollowUp updated follow-up=%s status=%s", followUp.ID, followUp.Status)
+	return followUp, nil
+}
+
+// DeleteReviewFollowUp removes the follow-up; risks and controls it created are kept
+func (s *IncidentService) DeleteReviewFollowUp(ctx context.Context, incidentID, followUpID, tenantID string) error {
+	log.Printf("DEBUG: incident_service.DeleteReviewFollowUp follow-up=%s", followUpID)
+
+	followUp, err := s.getReviewFollowUp(ctx, incidentID, followUpID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.incidentRepo.DeleteReviewFollowUp(ctx, followUp.ID, followUp.ReviewID); err != nil {
+		log.Printf("ERROR: incident_service.DeleteReviewFollowUp DeleteReviewFollowUp: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *IncidentService) getReviewFollowUp(ctx context.Context, incidentID, followUpID, tenantID string) (*repo.IncidentReviewFollowUp, error) {
+	review, err := s.GetIncidentReview(ctx, incidentID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	followUp, err := s.incidentRepo.GetReviewFollowUp(ctx, followUpID, review.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.getReviewFollowUp GetReviewFollowUp: %v", err)
+		return nil, err
+	}
+	if followUp == nil {
+		return nil, ErrReviewFollowUpNotFound
+	}
+	return followUp, nil
+}
+
+// reviewReportEntry is a row of the report timeline
+type reviewReportEntry struct {
+	at     time.Time
+	Time   string
+	Event  string
+	Author string
+}
+
+// GenerateIncidentReviewReport renders the review with the incident timeline as PDF, or as HTML when format is "html"
+func (s *IncidentService) GenerateIncidentReviewReport(ctx context.Context, incidentID, tenantID, format string) ([]byte, error) {
+	log.Printf("DEBUG: incident_service.GenerateIncidentReviewReport incident=%s format=%s", incidentID, format)
+
+	incident, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport GetByID: %v", err)
+		return nil, err
+	}
+
+	review, err := s.incidentRepo.GetReview(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport GetReview: %v", err)
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrIncidentReviewNotFound
+	}
+
+	assets, err := s.incidentRepo.GetAssets(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport GetAssets: %v", err)
+		return nil, err
+	}
+	history, err := s.incidentRepo.GetStatusHistory(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport GetStatusHistory: %v", err)
+		return nil, err
+	}
+	comments, err := s.incidentRepo.GetComments(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport GetComments: %v", err)
+		return nil, err
+	}
+	actions, err := s.incidentRepo.GetActions(ctx, incident.ID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport GetActions: %v", err)
+		return nil, err
+	}
+
+	names := map[string]string{}
+	userName := func(id *string) string {
+		if id == nil {
+			return ""
+		}
+		if name, ok := names[*id]; ok {
+			return name
+		}
+		name := ""
+		if user, err := s.userRepo.GetByID(ctx, *id); err == nil && user != nil {
+			name = strings.TrimSpace(safeString(user.FirstName) + " " + safeString(user.LastName))
+			if name == "" {
+				name = user.Email
+			}
+		}
+		names[*id] = name
+		return name
+	}
+	formatTime := func(t *time.Time, layout string) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Format(layout)
+	}
+
+	assetNames := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		assetNames = append(assetNames, asset.Name)
+	}
+
+	timeline := make([]reviewReportEntry, 0, len(history)+len(comments))
+	for _, change := range history {
+		event := "Статус: " + change.ToStatus
+		if change.FromStatus != nil {
+			event = fmt.Sprintf("Статус: %s → %s", *change.FromStatus, change.ToStatus)
+		}
+		if change.Comment != nil && *change.Comment != "" {
+			event += "\n" + *change.Comment
+		}
+		timeline = append(timeline, reviewReportEntry{at: change.ChangedAt, Event: event, Author: userName(change.ChangedBy)})
+	}
+	for _, comment := range comments {
+		timeline = append(timeline, reviewReportEntry{at: comment.CreatedAt, Event: "Комментарий: " + comment.Comment, Author: userName(&comment.UserID)})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].at.Before(timeline[j].at) })
+	for i := range timeline {
+		timeline[i].Time = timeline[i].at.Format("02.01.2006 15:04")
+	}
+
+	type reportAction struct{ Title, Type, Status, CompletedAt string }
+	reportActions := make([]reportAction, 0, len(actions))
+	for _, action := range actions {
+		reportActions = append(reportActions, reportAction{
+			Title:       action.Title,
+			Type:        action.ActionType,
+			Status:      action.Status,
+			CompletedAt: formatTime(action.CompletedAt, "02.01.2006 15:04"),
+		})
+	}
+
+	type reportFollowUp struct{ Title, Type, Owner, DueDate, Status string }
+	followUps := make([]reportFollowUp, 0, len(review.FollowUps))
+	for _, followUp := range review.FollowUps {
+		followUps = append(followUps, reportFollowUp{
+			Title:   followUp.Title,
+			Type:    followUp.FollowUpType,
+			Owner:   userName(followUp.OwnerID),
+			DueDate: formatTime(followUp.DueDate, "02.01.2006"),
+			Status:  followUp.Status,
+		})
+	}
+
+	data := map[string]interface{}{
+		"Incident":       incident,
+		"Review":         review,
+		"DetectedAt":     incident.DetectedAt.Format("02.01.2006 15:04"),
+		"ResolvedAt":     formatTime(incident.ResolvedAt, "02.01.2006 15:04"),
+		"ReviewDate":     formatTime(review.ReviewDate, "02.01.2006"),
+		"Assets":         strings.Join(assetNames, ", "),
+		"Participants":   strings.Join(review.Participants, ", "),
+		"Summary":        safeString(review.Summary),
+		"RootCause":      safeString(review.RootCause),
+		"Impact":         safeString(review.Impact),
+		"WhatWentWell":   safeString(review.WhatWentWell),
+		"WhatWentWrong":  safeString(review.WhatWentWrong),
+		"LessonsLearned": safeString(review.LessonsLearned),
+		"Factors":        review.Factors,
+		"Timeline":       timeline,
+		"Actions":        reportActions,
+		"FollowUps":      followUps,
+		"GeneratedAt":    time.Now().Format("02.01.2006 15:04"),
+	}
+
+	tmpl, err := template.ParseFS(templatesFS, "templates/incident_review_report.html")
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport ParseFS: %v", err)
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport Execute: %v", err)
+		return nil, err
+	}
+
+	if format == "html" {
+		return buf.Bytes(), nil
+	}
+
+	if s.templateService == nil {
+		return nil, fmt.Errorf("template service is not configured")
+	}
+	pdf, err := s.templateService.GeneratePDFFromHTML(ctx, buf.String())
+	if err != nil {
+		log.Printf("ERROR: incident_service.GenerateIncidentReviewReport GeneratePDFFromHTML: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.GenerateIncidentReviewReport generated report for incident=%s", incident.ID)
+	return pdf, nil
+}
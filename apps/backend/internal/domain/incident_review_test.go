@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errFollowUpStorage = errors.New("storage failure")
+
+// fakeFollowUpRepo stores follow-ups in memory and fails the configured step
+type fakeFollowUpRepo struct {
+	IncidentRepoInterface
+
+	failAdd, failLink bool
+	followUps         map[string]*repo.IncidentReviewFollowUp
+	incidentRisks     []string
+}
+
+func (r *fakeFollowUpRepo) GetByID(ctx context.Context, id, tenantID string) (*repo.Incident, error) {
+	return &repo.Incident{ID: id, TenantID: tenantID, Title: "Phishing", Criticality: dto.IncidentCriticalityHigh}, nil
+}
+
+func (r *fakeFollowUpRepo) GetReview(ctx context.Context, incidentID string) (*repo.IncidentReview, error) {
+	return &repo.IncidentReview{ID: "review-1", IncidentID: incidentID}, nil
+}
+
+func (r *fakeFollowUpRepo) AddReviewFollowUp(ctx context.Context, followUp *repo.IncidentReviewFollowUp) error {
+	if r.failAdd {
+		return errFollowUpStorage
+	}
+	stored := *followUp
+	r.followUps[followUp.ID] = &stored
+	return nil
+}
+
+func (r *fakeFollowUpRepo) LinkReviewFollowUp(ctx context.Context, followUp *repo.IncidentReviewFollowUp) error {
+	if r.failLink {
+		return errFollowUpStorage
+	}
+	stored := r.followUps[followUp.ID]
+	stored.RiskID, stored.ControlID = followUp.RiskID, followUp.ControlID
+	return nil
+}
+
+func (r *fakeFollowUpRepo) DeleteReviewFollowUp(ctx context.Context, id, reviewID string) error {
+	delete(r.followUps, id)
+	return nil
+}
+
+func (r *fakeFollowUpRepo) AddRisk(ctx context.Context, incidentID, riskID string) error {
+	r.incidentRisks = append(r.incidentRisks, riskID)
+	return nil
+}
+
+// fakeFollowUpRiskService records created risks and controls
+type fakeFollowUpRiskService struct {
+	failCreate bool
+	risks      map[string]bool
+	controls   []string
+}
+
+func (s *fakeFollowUpRiskService) CreateRisk(ctx context.Context, tenantID, title string, description, category *string, likelihood, impact int, ownerUserID, assetID, threatID, vulnerabilityID *string, methodology, strategy *string, dueDate *time.Time) (*repo.Risk, error) {
+	if s.failCreate {
+		return nil, errFollowUpStorage
+	}
+	id := "risk-" + title
+	s.risks[id] = true
+	return &repo.Risk{ID: id, TenantID: tenantID, Title: title}, nil
+}
+
+func (s *fakeFollowUpRiskService) AddControl(ctx context.Context, riskID string, controlID, controlName, controlType, implementationStatus string, effectiveness, description *string, createdBy string) error {
+	s.controls = append(s.controls, controlID)
+	return nil
+}
+
+func (s *fakeFollowUpRiskService) DeleteRisk(ctx context.Context, id string) error {
+	delete(s.risks, id)
+	return nil
+}
+
+type fakeFollowUpRiskRepo struct{}
+
+func (fakeFollowUpRiskRepo) GetByIDWithTenant(ctx context.Context, id, tenantID string) (*repo.Risk, error) {
+	if id != "risk-existing" || tenantID != "tenant-1" {
+		return nil, nil
+	}
+	return &repo.Risk{ID: id, TenantID: tenantID}, nil
+}
+
+func TestAddReviewFollowUp(t *testing.T) {
+	existingRisk := "risk-existing"
+	otherRisk := "risk-other-tenant"
+
+	tests := []struct {
+		name          string
+		req           dto.IncidentReviewFollowUpRequest
+		failAdd       bool
+		failLink      bool
+		failCreate    bool
+		wantErr       error
+		wantField     string
+		wantFollowUps int
+		wantRisks     int
+		wantControls  int
+	}{
+		{
+			name:          "risk follow-up",
+			req:           dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeRisk, Title: "Weak MFA"},
+			wantFollowUps: 1,
+			wantRisks:     1,
+		},
+		{
+			name:          "control follow-up",
+			req:           dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeControl, Title: "Enable MFA", RiskID: &existingRisk},
+			wantFollowUps: 1,
+			wantControls:  1,
+		},
+		{
+			name:          "action follow-up",
+			req:           dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeAction, Title: "Train staff"},
+			wantFollowUps: 1,
+		},
+		{
+			name:    "follow-up insert fails before the risk is created",
+			req:     dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeRisk, Title: "Weak MFA"},
+			failAdd: true,
+			wantErr: errFollowUpStorage,
+		},
+		{
+			name:       "risk creation fails and the follow-up is removed",
+			req:        dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeRisk, Title: "Weak MFA"},
+			failCreate: true,
+			wantErr:    errFollowUpStorage,
+		},
+		{
+			name:     "linking fails and the risk is removed",
+			req:      dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeRisk, Title: "Weak MFA"},
+			failLink: true,
+			wantErr:  errFollowUpStorage,
+		},
+		{
+			name:      "control without risk",
+			req:       dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeControl, Title: "Enable MFA"},
+			wantField: "risk_id",
+		},
+		{
+			name:      "control for a risk of another tenant",
+			req:       dto.IncidentReviewFollowUpRequest{FollowUpType: dto.ReviewFollowUpTypeControl, Title: "Enable MFA", RiskID: &otherRisk},
+			wantField: "risk_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incidentRepo := &fakeFollowUpRepo{failAdd: tt.failAdd, failLink: tt.failLink, followUps: map[string]*repo.IncidentReviewFollowUp{}}
+			riskService := &fakeFollowUpRiskService{failCreate: tt.failCreate, risks: map[string]bool{}}
+			service := NewIncidentService(incidentRepo, nil, nil, fakeFollowUpRiskRepo{}, nil)
+			service.SetRiskService(riskService)
+
+			followUp, err := service.AddReviewFollowUp(context.Background(), "incident-1", "tenant-1", tt.req, "user-1")
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantField != "":
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+			default:
+				require.NoError(t, err)
+				stored := incidentRepo.followUps[followUp.ID]
+				require.NotNil(t, stored)
+				assert.Equal(t, followUp.RiskID, stored.RiskID)
+				assert.Equal(t, followUp.ControlID, stored.ControlID)
+			}
+
+			assert.Len(t, incidentRepo.followUps, tt.wantFollowUps)
+			assert.Len(t, riskService.risks, tt.wantRisks, "no risk is left without a follow-up")
+			assert.Len(t, riskService.controls, tt.wantControls)
+		})
+	}
+}
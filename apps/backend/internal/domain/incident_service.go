@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"mime/multipart"
+	"sync"
 	"time"
 
 	"risknexus/backend/internal/dto"
@@ -20,6 +21,16 @@ type IncidentService struct {
 	assetRepo              AssetRepoInterface
 	riskRepo               RiskRepoInterface
 	documentStorageService DocumentStorageServiceInterface
+	templateService        TemplateRendererInterface
+	riskService            IncidentRiskServiceInterface
+	auditRepo              *repo.AuditRepo
+	notifier               UserNotifierInterface
+	mailboxCipher          *secretCipher
+
+	// ingestMu serializes alert ingestion so correlation-key deduplication sees earlier alerts
+	ingestMu sync.Mutex
+	// mailPollMu serializes mailbox polls so a message is not imported twice by concurrent polls
+	mailPollMu sync.Mutex
 }
 
 func NewIncidentService(incidentRepo IncidentRepoInterface, userRepo UserRepoInterface, assetRepo AssetRepoInterface, riskRepo RiskRepoInterface, documentStorageService DocumentStorageServiceInterface) *IncidentService {
@@ -97,12 +108,20 @@ func (s *IncidentService) CreateIncident(ctx context.Context, tenantID string, r
 		UpdatedAt:   time.Now(),
 	}
 
+	if err := s.applySLADeadlines(ctx, &incident); err != nil {
+		log.Printf("ERROR: incident_service.CreateIncident applySLADeadlines: %v", err)
+		return nil, err
+	}
+
 	err := s.incidentRepo.Create(ctx, &incident)
 	if err != nil {
 		log.Printf("ERROR: incident_service.CreateIncident Create: %v", err)
 		return nil, err
 	}
 
+	// Time from detection to registration
+	s.addIncidentMetric(ctx, incident.ID, "mttd", incident.DetectedAt, incident.CreatedAt)
+
 	// Add asset relations
 	for _, assetID := range req.AssetIDs {
 		err := s.incidentRepo.AddAsset(ctx, incident.ID, assetID)
@@ -121,6 +140,16 @@ func (s *IncidentService) CreateIncident(ctx context.Context, tenantID string, r
 		}
 	}
 
+	// Response steps from the category playbook; the incident is registered even if this fails
+	if _, err := s.instantiatePlaybook(ctx, &incident, reportedBy); err != nil {
+		log.Printf("ERROR: incident_service.CreateIncident instantiatePlaybook: %v", err)
+	}
+
+	// Regulatory notification deadlines start from detection
+	if err := s.syncRegulatoryNotifications(ctx, &incident); err != nil {
+		log.Printf("ERROR: incident_service.CreateIncident syncRegulatoryNotifications: %v", err)
+	}
+
 	log.Printf("INFO: incident_service.CreateIncident created id=%s", incident.ID)
 	return &incident, nil
 }
@@ -167,37 +196,57 @@ func (s *IncidentService) UpdateIncident(ctx context.Context, id, tenantID strin
 	if req.Description != nil {
 		incident.Description = req.Description
 	}
+	categoryChanged := req.Category != nil && *req.Category != incident.Category
 	if req.Category != nil {
 		incident.Category = *req.Category
 	}
-	if req.Criticality != nil {
+	if req.Criticality != nil && *req.Criticality != incident.Criticality {
 		incident.Criticality = *req.Criticality
-	}
-	if req.Status != nil {
-		incident.Status = *req.Status
-		// Set resolved/closed timestamps
-		if *req.Status == dto.IncidentStatusResolved && incident.ResolvedAt == nil {
-			now := time.Now()
-			incident.ResolvedAt = &now
-		}
-		if *req.Status == dto.IncidentStatusClosed && incident.ClosedAt == nil {
-			now := time.Now()
-			incident.ClosedAt = &now
+		// Сроки SLA пересчитываются по политике новой критичности
+		if err := s.applySLADeadlines(ctx, incident); err != nil {
+			log.Printf("ERROR: incident_service.UpdateIncident applySLADeadlines: %v", err)
+			return nil, err
 		}
 	}
 	if req.AssignedTo != nil {
 		incident.AssignedTo = req.AssignedTo
 	}
+	detectedChanged := req.DetectedAt != nil && !req.DetectedAt.Equal(incident.DetectedAt)
 	if req.DetectedAt != nil {
 		incident.DetectedAt = *req.DetectedAt
 	}
+	if req.RootCause != nil {
+		incident.RootCause = req.RootCause
+	}
+	if req.Resolution != nil {
+		incident.Resolution = req.Resolution
+	}
 
 	incident.UpdatedAt = time.Now()
 
-	err = s.incidentRepo.Update(ctx, incident)
-	if err != nil {
-		log.Printf("ERROR: incident_service.UpdateIncident Update: %v", err)
-		return nil, err
+	// Status changes go through the workflow and are saved together with the timeline entry
+	if req.Status != nil && *req.Status != incident.Status {
+		change, err := s.prepareStatusTransition(ctx, incident, *req.Status, incidentStatusInput{Comment: req.Comment}, updatedBy)
+		if err != nil {
+			log.Printf("WARN: incident_service.UpdateIncident transition rejected: %v", err)
+			return nil, err
+		}
+		changed, err := s.incidentRepo.ChangeStatus(ctx, incident, change)
+		if err != nil {
+			log.Printf("ERROR: incident_service.UpdateIncident ChangeStatus: %v", err)
+			return nil, err
+		}
+		if !changed {
+			log.Printf("WARN: incident_service.UpdateIncident status of %s changed concurrently", incident.ID)
+			return nil, ErrIncidentStatusConflict
+		}
+		s.recordSLAStatusChange(ctx, incident, change)
+	} else {
+		err = s.incidentRepo.Update(ctx, incident)
+		if err != nil {
+			log.Printf("ERROR: incident_service.UpdateIncident Update: %v", err)
+			return nil, err
+		}
 	}
 
 	// Update asset relations if provided
@@ -288,6 +337,18 @@ func (s *IncidentService) UpdateIncident(ctx context.Context, id, tenantID strin
 		}
 	}
 
+	// A new category brings its own playbook
+	if categoryChanged {
+		if _, err := s.instantiatePlaybook(ctx, incident, updatedBy); err != nil {
+			log.Printf("ERROR: incident_service.UpdateIncident instantiatePlaybook: %v", err)
+		}
+	}
+	if categoryChanged || detectedChanged {
+		if err := s.syncRegulatoryNotifications(ctx, incident); err != nil {
+			log.Printf("ERROR: incident_service.UpdateIncident syncRegulatoryNotifications: %v", err)
+		}
+	}
+
 	log.Printf("INFO: incident_service.UpdateIncident updated id=%s", incident.ID)
 	return incident, nil
 }
@@ -444,22 +505,13 @@ func (s *IncidentService) UpdateAction(ctx context.Context, actionID, tenantID s
 	log.Printf("DEBUG: incident_service.UpdateAction action=%s user=%s", actionID, updatedBy)
 
 	// Get existing action
-	actions, err := s.incidentRepo.GetActions(ctx, "")
+	action, err := s.incidentRepo.GetAction(ctx, actionID)
 	if err != nil {
-		log.Printf("ERROR: incident_service.UpdateAction GetActions: %v", err)
+		log.Printf("ERROR: incident_service.UpdateAction GetAction: %v", err)
 		return nil, err
 	}
-
-	var action *repo.IncidentAction
-	for _, a := range actions {
-		if a.ID == actionID {
-			action = a
-			break
-		}
-	}
-
 	if action == nil {
-		return nil, errors.New("action not found")
+		return nil, ErrIncidentActionNotFound
 	}
 
 	// Verify incident belongs to tenant
@@ -544,25 +596,41 @@ func (s *IncidentService) UpdateIncidentStatus(ctx context.Context, id, tenantID
 		return nil, err
 	}
 
-	// Update status
-	incident.Status = req.Status
-	incident.UpdatedAt = time.Now()
-
-	// Set resolved/closed timestamps
-	if req.Status == dto.IncidentStatusResolved && incident.ResolvedAt == nil {
-		now := time.Now()
-		incident.ResolvedAt = &now
+	// Validate assigned user exists if provided
+	if req.AssignedTo != nil && *req.AssignedTo != "" {
+		user, err := s.userRepo.GetByID(ctx, *req.AssignedTo)
+		if err != nil {
+			log.Printf("ERROR: incident_service.UpdateIncidentStatus GetByID assigned user: %v", err)
+			return nil, err
+		}
+		if user == nil {
+			log.Printf("WARN: incident_service.UpdateIncidentStatus assigned user not found id=%s", *req.AssignedTo)
+			return nil, errors.New("assigned user not found")
+		}
 	}
-	if req.Status == dto.IncidentStatusClosed && incident.ClosedAt == nil {
-		now := time.Now()
-		incident.ClosedAt = &now
+
+	// Check the transition against the workflow and stamp resolved/closed timestamps
+	change, err := s.prepareStatusTransition(ctx, incident, req.Status, incidentStatusInput{
+		Comment:    req.Comment,
+		AssignedTo: req.AssignedTo,
+		RootCause:  req.RootCause,
+		Resolution: req.Resolution,
+	}, updatedBy)
+	if err != nil {
+		log.Printf("WARN: incident_service.UpdateIncidentStatus transition rejected: %v", err)
+		return nil, err
 	}
 
-	err = s.incidentRepo.Update(ctx, incident)
+	changed, err := s.incidentRepo.ChangeStatus(ctx, incident, change)
 	if err != nil {
-		log.Printf("ERROR: incident_service.UpdateIncidentStatus Update: %v", err)
+		log.Printf("ERROR: incident_service.UpdateIncidentStatus ChangeStatus: %v", err)
 		return nil, err
 	}
+	if !changed {
+		log.Printf("WARN: incident_service.UpdateIncidentStatus status of %s changed concurrently", id)
+		return nil, ErrIncidentStatusConflict
+	}
+	s.recordSLAStatusChange(ctx, incident, change)
 
 	log.Printf("INFO: incident_service.UpdateIncidentStatus updated id=%s status=%s", id, req.Status)
 	return incident, nil
@@ -669,6 +737,16 @@ func (s *IncidentService) UnlinkDocumentFromIncident(ctx context.Context, incide
 		return errors.New("incident not found")
 	}
 
+	// Документы доказательств хранятся с журналом хранения и не отвязываются
+	isEvidence, err := s.incidentRepo.IsEvidenceDocument(ctx, documentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.UnlinkDocumentFromIncident IsEvidenceDocument: %v", err)
+		return err
+	}
+	if isEvidence {
+		return ErrEvidenceDocumentProtected
+	}
+
 	// Отвязываем документ от инцидента
 	err = s.documentStorageService.UnlinkDocumentFromModule(ctx, documentID, "incidents", incidentID, unlinkedBy)
 	if err != nil {
@@ -692,6 +770,16 @@ func (s *IncidentService) DeleteIncidentDocument(ctx context.Context, incidentID
 		return errors.New("incident not found")
 	}
 
+	// Документы доказательств хранятся с журналом хранения и не отвязываются
+	isEvidence, err := s.incidentRepo.IsEvidenceDocument(ctx, documentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.DeleteIncidentDocument IsEvidenceDocument: %v", err)
+		return err
+	}
+	if isEvidence {
+		return ErrEvidenceDocumentProtected
+	}
+
 	// Получаем информацию о документе
 	_, err = s.documentStorageService.GetDocument(ctx, documentID, tenantID)
 	if err != nil {
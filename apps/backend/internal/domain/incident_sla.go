@@ -0,0 +1,697 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	_ "time/tzdata" // календари SLA используют часовые пояса IANA
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// defaultIncidentSLAPolicies are used for criticality levels the tenant has not configured
+var defaultIncidentSLAPolicies = map[string]repo.IncidentSLAPolicy{
+	dto.IncidentCriticalityCritical: {ResponseMinutes: 15, ResolutionMinutes: 4 * 60, WarningPercent: 80},
+	dto.IncidentCriticalityHigh:     {ResponseMinutes: 60, ResolutionMinutes: 24 * 60, WarningPercent: 80},
+	dto.IncidentCriticalityMedium:   {ResponseMinutes: 4 * 60, ResolutionMinutes: 3 * 9 * 60, WarningPercent: 80, BusinessHoursOnly: true},
+	dto.IncidentCriticalityLow:      {ResponseMinutes: 9 * 60, ResolutionMinutes: 5 * 9 * 60, WarningPercent: 80, BusinessHoursOnly: true},
+}
+
+var incidentCriticalityOrder = []string{
+	dto.IncidentCriticalityCritical,
+	dto.IncidentCriticalityHigh,
+	dto.IncidentCriticalityMedium,
+	dto.IncidentCriticalityLow,
+}
+
+// defaultIncidentBusinessCalendar is used by business-hours policies when the tenant has no default calendar
+var defaultIncidentBusinessCalendar = repo.IncidentBusinessCalendar{
+	Name:      "Стандартный рабочий график",
+	Timezone:  "Europe/Moscow",
+	WorkDays:  []int64{1, 2, 3, 4, 5},
+	WorkStart: "09:00",
+	WorkEnd:   "18:00",
+	Holidays:  []string{},
+}
+
+// maxSLACalendarDays limits the walk over a calendar that has (almost) no working time
+const maxSLACalendarDays = 3 * 366
+
+// incidentSLA is the effective policy of an incident together with the clock it is measured with
+type incidentSLA struct {
+	policy repo.IncidentSLAPolicy
+	clock  *incidentSLAClock
+}
+
+// incidentSLAClock measures SLA time either round the clock or within the working hours of a calendar
+type incidentSLAClock struct {
+	businessHours bool
+	location      *time.Location
+	workStart     int // minutes since midnight
+	workEnd       int
+	workDays      map[time.Weekday]bool
+	holidays      map[string]bool
+}
+
+func newIncidentSLAClock(calendar *repo.IncidentBusinessCalendar) *incidentSLAClock {
+	if calendar == nil {
+		return &incidentSLAClock{}
+	}
+
+	location, err := time.LoadLocation(calendar.Timezone)
+	if err != nil {
+		log.Printf("WARN: incident_service.newIncidentSLAClock unknown timezone %q, using UTC", calendar.Timezone)
+		location = time.UTC
+	}
+
+	clock := &incidentSLAClock{
+		businessHours: true,
+		location:      location,
+		workStart:     parseClockMinutes(calendar.WorkStart),
+		workEnd:       parseClockMinutes(calendar.WorkEnd),
+		workDays:      make(map[time.Weekday]bool),
+		holidays:      make(map[string]bool),
+	}
+	for _, day := range calendar.WorkDays {
+		clock.workDays[time.Weekday(day%7)] = true // ISO 7 (воскресенье) -> time.Sunday
+	}
+	for _, holiday := range calendar.Holidays {
+		clock.holidays[holiday] = true
+	}
+	return clock
+}
+
+func parseClockMinutes(value string) int {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hours, &minutes); err != nil {
+		return 0
+	}
+	return hours*60 + minutes
+}
+
+// workingHours returns the working interval of the day, ok is false for days off
+func (c *incidentSLAClock) workingHours(day time.Time) (time.Time, time.Time, bool) {
+	if !c.workDays[day.Weekday()] || c.holidays[day.Format("2006-01-02")] {
+		return time.Time{}, time.Time{}, false
+	}
+	open := time.Date(day.Year(), day.Month(), day.Day(), c.workStart/60, c.workStart%60, 0, 0, c.location)
+	close := time.Date(day.Year(), day.Month(), day.Day(), c.workEnd/60, c.workEnd%60, 0, 0, c.location)
+	return open, close, true
+}
+
+// Add returns the moment the given amount of SLA minutes has passed since from
+func (c *incidentSLAClock) Add(from time.Time, minutes int) time.Time {
+	remaining := time.Duration(minutes) * time.Minute
+	if !c.businessHours {
+		return from.Add(remaining)
+	}
+
+	current := from.In(c.location)
+	for i := 0; i < maxSLACalendarDays; i++ {
+		if open, close, ok := c.workingHours(current); ok {
+			if current.Before(open) {
+				current = open
+			}
+			if current.Before(close) {
+				available := close.Sub(current)
+				if remaining <= available {
+					return current.Add(remaining)
+				}
+				remaining -= available
+			}
+		}
+		current = time.Date(current.Year(), current.Month(), current.Day()+1, 0, 0, 0, 0, c.location)
+	}
+
+	// В календаре нет рабочего времени - считаем по астрономическому времени
+	return from.Add(time.Duration(minutes) * time.Minute)
+}
+
+// Between returns the SLA minutes elapsed between from and to
+func (c *incidentSLAClock) Between(from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+	if !c.businessHours {
+		return int(to.Sub(from).Minutes())
+	}
+
+	var total time.Duration
+	current := from.In(c.location)
+	for i := 0; i < maxSLACalendarDays && current.Before(to); i++ {
+		if open, close, ok := c.workingHours(current); ok {
+			start, end := open, close
+			if current.After(start) {
+				start = current
+			}
+			if to.Before(end) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		current = time.Date(current.Year(), current.Month(), current.Day()+1, 0, 0, 0, 0, c.location)
+	}
+	return int(total.Minutes())
+}
+
+// SLA policies
+
+// GetSLAPolicies returns the effective policy for every criticality level; built-in defaults have an empty ID
+func (s *IncidentService) GetSLAPolicies(ctx context.Context, tenantID string) ([]*repo.IncidentSLAPolicy, error) {
+	log.Printf("DEBUG: incident_service.GetSLAPolicies tenant=%s", tenantID)
+
+	policies, err := s.incidentRepo.ListSLAPolicies(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetSLAPolicies ListSLAPolicies: %v", err)
+		return nil, err
+	}
+
+	byCriticality := make(map[string]*repo.IncidentSLAPolicy, len(policies))
+	for _, policy := range policies {
+		byCriticality[policy.Criticality] = policy
+	}
+
+	result := make([]*repo.IncidentSLAPolicy, 0, len(incidentCriticalityOrder))
+	for _, criticality := range incidentCriticalityOrder {
+		if policy, ok := byCriticality[criticality]; ok {
+			result = append(result, policy)
+			continue
+		}
+		policy := defaultIncidentSLAPolicies[criticality]
+		policy.TenantID = tenantID
+		policy.Criticality = criticality
+		result = append(result, &policy)
+	}
+	return result, nil
+}
+
+// SetSLAPolicies saves the tenant policies; deadlines of existing incidents are kept
+func (s *IncidentService) SetSLAPolicies(ctx context.Context, tenantID string, req dto.IncidentSLAPoliciesRequest) ([]*repo.IncidentSLAPolicy, error) {
+	log.Printf("DEBUG: incident_service.SetSLAPolicies tenant=%s policies=%d", tenantID, len(req.Policies))
+
+	seen := make(map[string]bool)
+	for _, p := range req.Policies {
+		if seen[p.Criticality] {
+			return nil, NewValidationError("policies", "duplicate policy for criticality "+p.Criticality)
+		}
+		seen[p.Criticality] = true
+
+		if p.CalendarID != nil && *p.CalendarID != "" {
+			calendar, err := s.incidentRepo.GetBusinessCalendar(ctx, *p.CalendarID, tenantID)
+			if err != nil {
+				log.Printf("ERROR: incident_service.SetSLAPolicies GetBusinessCalendar: %v", err)
+				return nil, err
+			}
+			if calendar == nil {
+				return nil, ErrIncidentCalendarNotFound
+			}
+		}
+	}
+
+	for _, p := range req.Policies {
+		warningPercent := p.WarningPercent
+		if warningPercent == 0 {
+			warningPercent = defaultIncidentSLAPolicies[p.Criticality].WarningPercent
+		}
+		calendarID := p.CalendarID
+		if calendarID != nil && *calendarID == "" {
+			calendarID = nil
+		}
+
+		policy := &repo.IncidentSLAPolicy{
+			ID:                uuid.New().String(),
+			TenantID:          tenantID,
+			Criticality:       p.Criticality,
+			ResponseMinutes:   p.ResponseMinutes,
+			ResolutionMinutes: p.ResolutionMinutes,
+			WarningPercent:    warningPercent,
+			BusinessHoursOnly: p.BusinessHoursOnly,
+			CalendarID:        calendarID,
+			UpdatedAt:         time.Now(),
+		}
+		if err := s.incidentRepo.UpsertSLAPolicy(ctx, policy); err != nil {
+			log.Printf("ERROR: incident_service.SetSLAPolicies UpsertSLAPolicy: %v", err)
+			return nil, err
+		}
+	}
+
+	return s.GetSLAPolicies(ctx, tenantID)
+}
+
+// Business calendars
+
+func (s *IncidentService) ListBusinessCalendars(ctx context.Context, tenantID string) ([]*repo.IncidentBusinessCalendar, error) {
+	log.Printf("DEBUG: incident_service.ListBusinessCalendars tenant=%s", tenantID)
+
+	calendars, err := s.incidentRepo.ListBusinessCalendars(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListBusinessCalendars ListBusinessCalendars: %v", err)
+		return nil, err
+	}
+	return calendars, nil
+}
+
+func (s *IncidentService) CreateBusinessCalendar(ctx context.Context, tenantID string, req dto.IncidentBusinessCalendarRequest) (*repo.IncidentBusinessCalendar, error) {
+	log.Printf("DEBUG: incident_service.CreateBusinessCalendar tenant=%s name=%s", tenantID, req.Name)
+
+	calendar := &repo.IncidentBusinessCalendar{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		CreatedAt: time.Now(),
+	}
+	if err := applyBusinessCalendarRequest(calendar, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveBusinessCalendar(ctx, calendar); err != nil {
+		log.Printf("ERROR: incident_service.CreateBusinessCalendar SaveBusinessCalendar: %v", err)
+		return nil, err
+	}
+
+	log.Printf("INFO: incident_service.CreateBusinessCalendar created id=%s", calendar.ID)
+	return calendar, nil
+}
+
+func (s *IncidentService) UpdateBusinessCalendar(ctx context.Context, id, tenantID string, req dto.IncidentBusinessCalendarRequest) (*repo.IncidentBusinessCalendar, error) {
+	log.Printf("DEBUG: incident_service.UpdateBusinessCalendar id=%s tenant=%s", id, tenantID)
+
+	calendar, err := s.incidentRepo.GetBusinessCalendar(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.UpdateBusinessCalendar GetBusinessCalendar: %v", err)
+		return nil, err
+	}
+	if calendar == nil {
+		return nil, ErrIncidentCalendarNotFound
+	}
+
+	if err := applyBusinessCalendarRequest(calendar, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveBusinessCalendar(ctx, calendar); err != nil {
+		log.Printf("ERROR: incident_service.UpdateBusinessCalendar SaveBusinessCalendar: %v", err)
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+func (s *IncidentService) DeleteBusinessCalendar(ctx context.Context, id, tenantID string) error {
+	log.Printf("DEBUG: incident_service.DeleteBusinessCalendar id=%s tenant=%s", id, tenantID)
+
+	calendar, err := s.incidentRepo.GetBusinessCalendar(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.DeleteBusinessCalendar GetBusinessCalendar: %v", err)
+		return err
+	}
+	if calendar == nil {
+		return ErrIncidentCalendarNotFound
+	}
+
+	// Политики, ссылавшиеся на календарь, переходят на календарь по умолчанию (ON DELETE SET NULL)
+	if err := s.incidentRepo.DeleteBusinessCalendar(ctx, id, tenantID); err != nil {
+		log.Printf("ERROR: incident_service.DeleteBusinessCalendar DeleteBusinessCalendar: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyBusinessCalendarRequest copies the request onto the calendar, filling omitted values from the default calendar
+func applyBusinessCalendarRequest(calendar *repo.IncidentBusinessCalendar, req dto.IncidentBusinessCalendarRequest) error {
+	calendar.Name = req.Name
+	calendar.Timezone = req.Timezone
+	if calendar.Timezone == "" {
+		calendar.Timezone = defaultIncidentBusinessCalendar.Timezone
+	}
+	calendar.WorkDays = req.WorkDays
+	if len(calendar.WorkDays) == 0 {
+		calendar.WorkDays = defaultIncidentBusinessCalendar.WorkDays
+	}
+	calendar.WorkStart = req.WorkStart
+	if calendar.WorkStart == "" {
+		calendar.WorkStart = defaultIncidentBusinessCalendar.WorkStart
+	}
+	calendar.WorkEnd = req.WorkEnd
+	if calendar.WorkEnd == "" {
+		calendar.WorkEnd = defaultIncidentBusinessCalendar.WorkEnd
+	}
+	calendar.Holidays = req.Holidays
+	if calendar.Holidays == nil {
+		calendar.Holidays = []string{}
+	}
+	calendar.IsDefault = req.IsDefault
+	calendar.UpdatedAt = time.Now()
+
+	// HH:MM сравниваются лексикографически
+	if calendar.WorkStart >= calendar.WorkEnd {
+		return NewValidationError("work_end", "work_end must be later than work_start")
+	}
+	return nil
+}
+
+// Deadlines and timers
+
+// resolveIncidentSLA returns the effective policy and clock for a criticality level
+func (s *IncidentService) resolveIncidentSLA(ctx context.Context, tenantID, criticality string) (*incidentSLA, error) {
+	policies, err := s.incidentRepo.ListSLAPolicies(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := defaultIncidentSLAPolicies[criticality]
+	policy.TenantID = tenantID
+	policy.Criticality = criticality
+	for _, p := range policies {
+		if p.Criticality == criticality {
+			policy = *p
+			break
+		}
+	}
+
+	if !policy.BusinessHoursOnly {
+		return &incidentSLA{policy: policy, clock: newIncidentSLAClock(nil)}, nil
+	}
+
+	var calendar *repo.IncidentBusinessCalendar
+	if policy.CalendarID != nil {
+		calendar, err = s.incidentRepo.GetBusinessCalendar(ctx, *policy.CalendarID, tenantID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if calendar == nil {
+		calendar, err = s.incidentRepo.GetDefaultBusinessCalendar(ctx, tenantID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if calendar == nil {
+		calendar = &defaultIncidentBusinessCalendar
+	}
+
+	return &incidentSLA{policy: policy, clock: newIncidentSLAClock(calendar)}, nil
+}
+
+// applySLADeadlines sets the response and resolution deadlines counted from the registration of the incident
+func (s *IncidentService) applySLADeadlines(ctx context.Context, incident *repo.Incident) error {
+	sla, err := s.resolveIncidentSLA(ctx, incident.TenantID, incident.Criticality)
+	if err != nil {
+		return err
+	}
+
+	responseDueAt := sla.clock.Add(incident.CreatedAt, sla.policy.ResponseMinutes)
+	resolutionDueAt := sla.clock.Add(incident.CreatedAt, sla.policy.ResolutionMinutes)
+	incident.ResponseDueAt = &responseDueAt
+	incident.ResolutionDueAt = &resolutionDueAt
+	return nil
+}
+
+// GetIncidentSLA returns the live response and resolution timers of an incident
+func (s *IncidentService) GetIncidentSLA(ctx context.Context, id, tenantID string) (*dto.IncidentSLAResponse, error) {
+	log.Printf("DEBUG: incident_service.GetIncidentSLA id=%s", id)
+
+	incident, err := s.incidentRepo.GetByID(ctx, id, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentSLA GetByID: %v", err)
+		return nil, err
+	}
+
+	sla, err := s.resolveIncidentSLA(ctx, tenantID, incident.Criticality)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentSLA resolveIncidentSLA: %v", err)
+		return nil, err
+	}
+
+	// Инциденты, зарегистрированные до появления SLA, получают сроки по текущей политике
+	if incident.ResponseDueAt == nil || incident.ResolutionDueAt == nil {
+		if err := s.applySLADeadlines(ctx, incident); err != nil {
+			log.Printf("ERROR: incident_service.GetIncidentSLA applySLADeadlines: %v", err)
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	return &dto.IncidentSLAResponse{
+		IncidentID:        incident.ID,
+		Criticality:       incident.Criticality,
+		BusinessHoursOnly: sla.policy.BusinessHoursOnly,
+		Response:          sla.timer(incident, dto.IncidentSLATargetResponse, now),
+		Resolution:        sla.timer(incident, dto.IncidentSLATargetResolution, now),
+		CalculatedAt:      now,
+	}, nil
+}
+
+// timer calculates the state of one SLA target at the given moment
+func (sla *incidentSLA) timer(incident *repo.Incident, target string, now time.Time) dto.IncidentSLATimerResponse {
+	dueAt, stoppedAt, breached := incident.ResponseDueAt, incident.RespondedAt, incident.ResponseBreached
+	if target == dto.IncidentSLATargetResolution {
+		dueAt, stoppedAt, breached = incident.ResolutionDueAt, incidentResolvedAt(incident), incident.ResolutionBreached
+	}
+
+	timer := dto.IncidentSLATimerResponse{
+		Target:    target,
+		Status:    dto.IncidentSLAStatusOK,
+		DueAt:     dueAt,
+		StoppedAt: stoppedAt,
+	}
+	if dueAt == nil {
+		return timer
+	}
+
+	end := now
+	if stoppedAt != nil {
+		end = *stoppedAt
+	}
+	timer.TargetMinutes = sla.clock.Between(incident.CreatedAt, *dueAt)
+	timer.ElapsedMinutes = sla.clock.Between(incident.CreatedAt, end)
+	if timer.TargetMinutes > timer.ElapsedMinutes {
+		timer.RemainingMinutes = timer.TargetMinutes - timer.ElapsedMinutes
+	}
+	if timer.TargetMinutes > 0 {
+		timer.PercentElapsed = float64(timer.ElapsedMinutes) * 100 / float64(timer.TargetMinutes)
+	}
+
+	switch {
+	case stoppedAt != nil && (breached || stoppedAt.After(*dueAt)):
+		timer.Status = dto.IncidentSLAStatusBreached
+	case stoppedAt != nil:
+		timer.Status = dto.IncidentSLAStatusMet
+	case breached || !now.Before(*dueAt):
+		timer.Status = dto.IncidentSLAStatusBreached
+	case timer.PercentElapsed >= float64(sla.policy.WarningPercent):
+		timer.Status = dto.IncidentSLAStatusWarning
+	}
+	return timer
+}
+
+// incidentResolvedAt returns when the resolution timer stopped, nil while the incident is in work
+func incidentResolvedAt(incident *repo.Incident) *time.Time {
+	if !isIncidentFinished(incident.Status) {
+		return nil
+	}
+	if incident.ResolvedAt != nil {
+		return incident.ResolvedAt
+	}
+	return incident.ClosedAt
+}
+
+// recordSLAStatusChange stores response and resolution metrics after a status change and
+// records breaches that happened between two monitor runs
+func (s *IncidentService) recordSLAStatusChange(ctx context.Context, incident *repo.Incident, change *repo.IncidentStatusChange) {
+	if change.FromStatus != nil && *change.FromStatus == dto.IncidentStatusNew &&
+		incident.RespondedAt != nil && incident.RespondedAt.Equal(change.ChangedAt) {
+		s.addIncidentMetric(ctx, incident.ID, "response_time", incident.CreatedAt, *incident.RespondedAt)
+		if incident.ResponseDueAt != nil && incident.RespondedAt.After(*incident.ResponseDueAt) && !incident.ResponseBreached {
+			s.recordSLAEvent(ctx, incident, dto.IncidentSLATargetResponse, dto.IncidentSLAEventBreach, *incident.ResponseDueAt)
+		}
+	}
+
+	if (change.FromStatus != nil && isIncidentFinished(*change.FromStatus)) || !isIncidentFinished(change.ToStatus) {
+		return
+	}
+	resolvedAt := incidentResolvedAt(incident)
+	if resolvedAt == nil {
+		return
+	}
+	s.addIncidentMetric(ctx, incident.ID, "mttr", incident.DetectedAt, *resolvedAt)
+	s.addIncidentMetric(ctx, incident.ID, "resolution_time", incident.CreatedAt, *resolvedAt)
+	if incident.ResolutionDueAt != nil && resolvedAt.After(*incident.ResolutionDueAt) && !incident.ResolutionBreached {
+		s.recordSLAEvent(ctx, incident, dto.IncidentSLATargetResolution, dto.IncidentSLAEventBreach, *incident.ResolutionDueAt)
+	}
+}
+
+// addIncidentMetric stores a metric once per incident so reopened incidents are not counted twice
+func (s *IncidentService) addIncidentMetric(ctx context.Context, incidentID, metricType string, from, to time.Time) {
+	metrics, err := s.incidentRepo.GetMetrics(ctx, incidentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.addIncidentMetric GetMetrics: %v", err)
+		return
+	}
+	for _, metric := range metrics {
+		if metric.MetricType == metricType {
+			return
+		}
+	}
+
+	value := 0
+	if to.After(from) {
+		value = int(to.Sub(from).Minutes())
+	}
+	err = s.incidentRepo.AddMetric(ctx, &repo.IncidentMetrics{
+		ID:           uuid.New().String(),
+		IncidentID:   incidentID,
+		MetricType:   metricType,
+		ValueMinutes: value,
+		CalculatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("ERROR: incident_service.addIncidentMetric AddMetric %s: %v", metricType, err)
+	}
+}
+
+// SLA monitoring
+
+// RunSLAMonitor periodically raises warning and breach events for running SLA timers until ctx is cancelled
+func (s *IncidentService) RunSLAMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.checkSLATimers(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkSLATimers(ctx)
+		}
+	}
+}
+
+func (s *IncidentService) checkSLATimers(ctx context.Context) {
+	incidents, err := s.incidentRepo.ListIncidentsWithRunningSLA(ctx)
+	if err != nil {
+		log.Printf("ERROR: IncidentService SLA monitor ListIncidentsWithRunningSLA: %v", err)
+		return
+	}
+
+	now := time.Now()
+	slas := make(map[string]*incidentSLA)
+	events := 0
+	for _, incident := range incidents {
+		key := incident.TenantID + "/" + incident.Criticality
+		sla, ok := slas[key]
+		if !ok {
+			sla, err = s.resolveIncidentSLA(ctx, incident.TenantID, incident.Criticality)
+			if err != nil {
+				log.Printf("ERROR: IncidentService SLA monitor resolveIncidentSLA: %v", err)
+				continue
+			}
+			slas[key] = sla
+		}
+
+		for _, target := range []string{dto.IncidentSLATargetResponse, dto.IncidentSLATargetResolution} {
+			timer := sla.timer(incident, target, now)
+			if timer.StoppedAt != nil || timer.DueAt == nil {
+				continue
+			}
+			switch timer.Status {
+			case dto.IncidentSLAStatusWarning:
+				if s.recordSLAEvent(ctx, incident, target, dto.IncidentSLAEventWarning, *timer.DueAt) {
+					events++
+				}
+			case dto.IncidentSLAStatusBreached:
+				if s.recordSLAEvent(ctx, incident, target, dto.IncidentSLAEventBreach, *timer.DueAt) {
+					events++
+				}
+			}
+		}
+	}
+
+	if events > 0 {
+		log.Printf("DEBUG: IncidentService SLA monitor raised %d SLA events", events)
+	}
+}
+
+// SetNotifier connects the inbox used for SLA and regulatory deadline alerts
+func (s *IncidentService) SetNotifier(notifier UserNotifierInterface) {
+	s.notifier = notifier
+}
+
+// recordSLAEvent stores the event and notifies the responsible user; returns false if it had already been raised
+func (s *IncidentService) recordSLAEvent(ctx context.Context, incident *repo.Incident, target, eventType string, dueAt time.Time) bool {
+	recipient := incident.AssignedTo
+	if recipient == nil || *recipient == "" {
+		recipient = &incident.ReportedBy
+	}
+
+	event := &repo.IncidentSLAEvent{
+		ID:              uuid.New().String(),
+		TenantID:        incident.TenantID,
+		IncidentID:      incident.ID,
+		Target:          target,
+		EventType:       eventType,
+		DueAt:           dueAt,
+		RecipientUserID: recipient,
+		CreatedAt:       time.Now(),
+	}
+	inserted, err := s.incidentRepo.RecordSLAEvent(ctx, event)
+	if err != nil {
+		log.Printf("ERROR: incident_service.recordSLAEvent RecordSLAEvent: %v", err)
+		return false
+	}
+	if !inserted {
+		return false
+	}
+
+	if eventType == dto.IncidentSLAEventBreach {
+		if target == dto.IncidentSLATargetResponse {
+			incident.ResponseBreached = true
+		} else {
+			incident.ResolutionBreached = true
+		}
+	}
+
+	s.notifySLAEvent(ctx, incident, *recipient, target, eventType, dueAt)
+	return true
+}
+
+// notifySLAEvent puts the SLA warning or breach into the responsible user's inbox
+func (s *IncidentService) notifySLAEvent(ctx context.Context, incident *repo.Incident, recipient, target, eventType string, dueAt time.Time) {
+	if s.notifier == nil {
+		return
+	}
+
+	targetName := "реакции"
+	if target == dto.IncidentSLATargetResolution {
+		targetName = "решения"
+	}
+	notificationType := dto.UserNotificationIncidentSLAWarning
+	title := "Срок SLA инцидента истекает"
+	message := fmt.Sprintf("Срок %s по инциденту «%s» истекает %s.", targetName, incident.Title, dueAt.Format("02.01.2006 15:04"))
+	if eventType == dto.IncidentSLAEventBreach {
+		notificationType = dto.UserNotificationIncidentSLABreach
+		title = "Нарушен срок SLA инцидента"
+		message = fmt.Sprintf("Срок %s по инциденту «%s» истёк %s.", targetName, incident.Title, dueAt.Format("02.01.2006 15:04"))
+	}
+
+	if err := s.notifier.Notify(ctx, incident.TenantID, recipient, notificationType, title, message, "incident", incident.ID); err != nil {
+		log.Printf("ERROR: incident_service.notifySLAEvent Notify: %v", err)
+	}
+}
+
+func (s *IncidentService) ListSLAEvents(ctx context.Context, tenantID string, filters map[string]interface{}, limit int) ([]*repo.IncidentSLAEvent, error) {
+	log.Printf("DEBUG: incident_service.ListSLAEvents tenant=%s", tenantID)
+
+	events, err := s.incidentRepo.ListSLAEvents(ctx, tenantID, filters, limit)
+	if err != nil {
+		log.Printf("ERROR: incident_service.ListSLAEvents ListSLAEvents: %v", err)
+		return nil, err
+	}
+	return events, nil
+}
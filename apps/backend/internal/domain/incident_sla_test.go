@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSLACalendar is a Moscow five-day week with 2026-03-09 (Monday) as a holiday
+func testSLACalendar() *repo.IncidentBusinessCalendar {
+	return &repo.IncidentBusinessCalendar{
+		Timezone:  "Europe/Moscow",
+		WorkDays:  []int64{1, 2, 3, 4, 5},
+		WorkStart: "09:00",
+		WorkEnd:   "18:00",
+		Holidays:  []string{"2026-03-09"},
+	}
+}
+
+func moscowTime(t *testing.T, day, clock string) time.Time {
+	t.Helper()
+	location, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", day+" "+clock, location)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestIncidentSLAClock_BusinessHours(t *testing.T) {
+	clock := newIncidentSLAClock(testSLACalendar())
+
+	tests := []struct {
+		name    string
+		from    string
+		minutes int
+		want    string
+	}{
+		{"within working day", "2026-03-10 10:00", 60, "2026-03-10 11:00"},
+		{"ends exactly at close", "2026-03-10 17:00", 60, "2026-03-10 18:00"},
+		{"starts before open", "2026-03-10 08:00", 30, "2026-03-10 09:30"},
+		{"starts after close", "2026-03-10 19:00", 60, "2026-03-11 10:00"},
+		{"carries over to next day", "2026-03-10 17:30", 90, "2026-03-11 10:00"},
+		{"skips weekend and holiday", "2026-03-06 17:00", 120, "2026-03-10 10:00"},
+		{"starts on weekend", "2026-03-07 12:00", 15, "2026-03-10 09:15"},
+		{"several working days", "2026-03-10 09:00", 3 * 9 * 60, "2026-03-12 18:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := moscowTime(t, tt.from[:10], tt.from[11:])
+			want := moscowTime(t, tt.want[:10], tt.want[11:])
+
+			got := clock.Add(from, tt.minutes)
+			assert.True(t, want.Equal(got), "Add: want %v, got %v", want, got)
+
+			// Between is the inverse of Add when the start is inside working hours
+			start := clock.Add(from, 0)
+			assert.Equal(t, tt.minutes, clock.Between(start, got))
+		})
+	}
+}
+
+func TestIncidentSLAClock_Between(t *testing.T) {
+	clock := newIncidentSLAClock(testSLACalendar())
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     int
+	}{
+		{"reversed interval", "2026-03-10 12:00", "2026-03-10 11:00", 0},
+		{"same moment", "2026-03-10 12:00", "2026-03-10 12:00", 0},
+		{"outside working hours", "2026-03-10 18:30", "2026-03-11 08:30", 0},
+		{"weekend only", "2026-03-07 00:00", "2026-03-08 23:59", 0},
+		{"over weekend and holiday", "2026-03-06 17:00", "2026-03-10 10:00", 120},
+		{"full week", "2026-03-02 00:00", "2026-03-09 00:00", 5 * 9 * 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := moscowTime(t, tt.from[:10], tt.from[11:])
+			to := moscowTime(t, tt.to[:10], tt.to[11:])
+			assert.Equal(t, tt.want, clock.Between(from, to))
+		})
+	}
+}
+
+func TestIncidentSLAClock_RoundTheClock(t *testing.T) {
+	clock := newIncidentSLAClock(nil)
+	from := time.Date(2026, 3, 7, 23, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, from.Add(90*time.Minute), clock.Add(from, 90))
+	assert.Equal(t, 90, clock.Between(from, from.Add(90*time.Minute)))
+	assert.Equal(t, 0, clock.Between(from, from.Add(-time.Hour)))
+}
+
+func TestIncidentSLAClock_CalendarWithoutWorkingTime(t *testing.T) {
+	clock := newIncidentSLAClock(&repo.IncidentBusinessCalendar{Timezone: "Not/AZone", WorkStart: "09:00", WorkEnd: "18:00"})
+	from := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.UTC, clock.location, "unknown timezone falls back to UTC")
+	assert.Equal(t, from.Add(time.Hour), clock.Add(from, 60))
+}
+
+func TestParseClockMinutes(t *testing.T) {
+	assert.Equal(t, 9*60, parseClockMinutes("09:00"))
+	assert.Equal(t, 17*60+45, parseClockMinutes("17:45"))
+	assert.Equal(t, 0, parseClockMinutes("invalid"))
+}
+
+func TestIncidentSLATimer(t *testing.T) {
+	sla := &incidentSLA{policy: repo.IncidentSLAPolicy{WarningPercent: 80}, clock: newIncidentSLAClock(nil)}
+	created := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	due := created.Add(100 * time.Minute)
+	at := func(minutes int) *time.Time {
+		moment := created.Add(time.Duration(minutes) * time.Minute)
+		return &moment
+	}
+
+	tests := []struct {
+		name          string
+		incident      repo.Incident
+		now           time.Time
+		wantStatus    string
+		wantElapsed   int
+		wantRemaining int
+	}{
+		{
+			name:          "in time",
+			incident:      repo.Incident{CreatedAt: created, ResponseDueAt: &due},
+			now:           *at(50),
+			wantStatus:    dto.IncidentSLAStatusOK,
+			wantElapsed:   50,
+			wantRemaining: 50,
+		},
+		{
+			name:          "warning threshold reached",
+			incident:      repo.Incident{CreatedAt: created, ResponseDueAt: &due},
+			now:           *at(80),
+			wantStatus:    dto.IncidentSLAStatusWarning,
+			wantElapsed:   80,
+			wantRemaining: 20,
+		},
+		{
+			name:        "deadline passed",
+			incident:    repo.Incident{CreatedAt: created, ResponseDueAt: &due},
+			now:         *at(100),
+			wantStatus:  dto.IncidentSLAStatusBreached,
+			wantElapsed: 100,
+		},
+		{
+			name:          "breach already recorded",
+			incident:      repo.Incident{CreatedAt: created, ResponseDueAt: &due, ResponseBreached: true},
+			now:           *at(10),
+			wantStatus:    dto.IncidentSLAStatusBreached,
+			wantElapsed:   10,
+			wantRemaining: 90,
+		},
+		{
+			name:          "responded in time",
+			incident:      repo.Incident{CreatedAt: created, ResponseDueAt: &due, RespondedAt: at(30)},
+			now:           *at(500),
+			wantStatus:    dto.IncidentSLAStatusMet,
+			wantElapsed:   30,
+			wantRemaining: 70,
+		},
+		{
+			name:        "responded late",
+			incident:    repo.Incident{CreatedAt: created, ResponseDueAt: &due, RespondedAt: at(120)},
+			now:         *at(500),
+			wantStatus:  dto.IncidentSLAStatusBreached,
+			wantElapsed: 120,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timer := sla.timer(&tt.incident, dto.IncidentSLATargetResponse, tt.now)
+			assert.Equal(t, tt.wantStatus, timer.Status)
+			assert.Equal(t, 100, timer.TargetMinutes)
+			assert.Equal(t, tt.wantElapsed, timer.ElapsedMinutes)
+			assert.Equal(t, tt.wantRemaining, timer.RemainingMinutes)
+		})
+	}
+
+	t.Run("no deadline", func(t *testing.T) {
+		timer := sla.timer(&repo.Incident{CreatedAt: created}, dto.IncidentSLATargetResolution, *at(10))
+		assert.Equal(t, dto.IncidentSLAStatusOK, timer.Status)
+		assert.Nil(t, timer.DueAt)
+		assert.Zero(t, timer.TargetMinutes)
+	})
+
+	t.Run("resolution stops when the incident is resolved", func(t *testing.T) {
+		incident := repo.Incident{
+			CreatedAt:       created,
+			Status:          dto.IncidentStatusResolved,
+			ResolutionDueAt: &due,
+			ResolvedAt:      at(60),
+		}
+		timer := sla.timer(&incident, dto.IncidentSLATargetResolution, *at(500))
+		assert.Equal(t, dto.IncidentSLAStatusMet, timer.Status)
+		assert.Equal(t, 60, timer.ElapsedMinutes)
+	})
+}
@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+const (
+	// Default number of buckets when the period is not specified
+	defaultTrendBuckets = 12
+
+	maxTrendWeeks  = 104
+	maxTrendMonths = 60
+)
+
+// GetIncidentTrends builds week or month series of incident counts and MTTR/MTTD percentiles,
+// with an optional comparison against the previous period or the same period a year earlier
+func (s *IncidentService) GetIncidentTrends(ctx context.Context, tenantID string, req dto.IncidentTrendRequest) (*dto.IncidentTrendResponse, error) {
+	log.Printf("DEBUG: incident_service.GetIncidentTrends tenant=%s interval=%s from=%s to=%s", tenantID, req.Interval, req.From, req.To)
+
+	if req.Interval == "" {
+		req.Interval = dto.IncidentTrendIntervalMonth
+	}
+	if req.Compare == "" {
+		req.Compare = dto.IncidentTrendComparePreviousPeriod
+	}
+
+	from, to, err := trendPeriod(req)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := map[string]interface{}{
+		"category":    req.Category,
+		"criticality": req.Criticality,
+		"asset_id":    req.AssetID,
+		"assigned_to": req.AssignedTo,
+	}
+
+	rows, err := s.incidentRepo.ListTrendIncidents(ctx, tenantID, from, to, filters)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetIncidentTrends ListTrendIncidents: %v", err)
+		return nil, err
+	}
+
+	response := &dto.IncidentTrendResponse{
+		Interval: req.Interval,
+		Filters:  req,
+		Series:   buildTrendSeries(rows, req.Interval, from, to),
+		Summary:  summarizeTrendRows(rows, from, to),
+	}
+
+	if req.Compare != dto.IncidentTrendCompareNone {
+		prevFrom, prevTo := from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0)
+		if req.Compare == dto.IncidentTrendComparePreviousPeriod {
+			buckets := len(response.Series)
+			prevFrom, prevTo = addTrendBuckets(from, req.Interval, -buckets), from
+		}
+
+		prevRows, err := s.incidentRepo.ListTrendIncidents(ctx, tenantID, prevFrom, prevTo, filters)
+		if err != nil {
+			log.Printf("ERROR: incident_service.GetIncidentTrends ListTrendIncidents previous: %v", err)
+			return nil, err
+		}
+		response.Comparison = compareTrendSummaries(req.Compare, summarizeTrendRows(prevRows, prevFrom, prevTo), response.Summary)
+	}
+
+	return response, nil
+}
+
+// trendPeriod resolves the requested dates into bucket-aligned [from, to)
+func trendPeriod(req dto.IncidentTrendRequest) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := truncateToTrendBucket(now, req.Interval)
+	to = addTrendBuckets(to, req.Interval, 1)
+	if req.To != "" {
+		toDate, err := time.ParseInLocation("2006-01-02", req.To, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, NewValidationError("to", "invalid date format")
+		}
+		to = toDate.AddDate(0, 0, 1)
+	}
+
+	from := addTrendBuckets(truncateToTrendBucket(to.Add(-time.Nanosecond), req.Interval), req.Interval, -(defaultTrendBuckets - 1))
+	if req.From != "" {
+		fromDate, err := time.ParseInLocation("2006-01-02", req.From, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, NewValidationError("from", "invalid date format")
+		}
+		from = truncateToTrendBucket(fromDate, req.Interval)
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, NewValidationError("from", "must be before to")
+	}
+
+	limit := maxTrendMonths
+	if req.Interval == dto.IncidentTrendIntervalWeek {
+		limit = maxTrendWeeks
+	}
+	if addTrendBuckets(from, req.Interval, limit).Before(to) {
+		return time.Time{}, time.Time{}, NewValidationError("from", "period is too long for the selected interval")
+	}
+
+	return from, to, nil
+}
+
+// truncateToTrendBucket returns the start of the week (Monday) or month containing t
+func truncateToTrendBucket(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if interval == dto.IncidentTrendIntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return day.AddDate(0, 0, 1-day.Day())
+}
+
+func addTrendBuckets(t time.Time, interval string, n int) time.Time {
+	if interval == dto.IncidentTrendIntervalWeek {
+		return t.AddDate(0, 0, 7*n)
+	}
+	return t.AddDate(0, n, 0)
+}
+
+// trendAccumulator collects counts and durations for a bucket or a whole period
+type trendAccumulator struct {
+	detected, resolved, closed, breaches int
+	mttr, mttd                           []float64
+}
+
+func (a *trendAccumulator) addDetected(row *repo.IncidentTrendRow) {
+	a.detected++
+	if row.ResponseBreached || row.ResolutionBreached {
+		a.breaches++
+	}
+	if row.MTTDMinutes != nil {
+		a.mttd = append(a.mttd, float64(*row.MTTDMinutes)/60)
+	}
+}
+
+func (a *trendAccumulator) addResolved(row *repo.IncidentTrendRow) {
+	a.resolved++
+	if row.MTTRMinutes != nil {
+		a.mttr = append(a.mttr, float64(*row.MTTRMinutes)/60)
+	}
+}
+
+func inTrendRange(t *time.Time, from, to time.Time) bool {
+	return t != nil && !t.Before(from) && t.Before(to)
+}
+
+// buildTrendSeries attributes detection counts and MTTD to the detection bucket,
+// resolution counts and MTTR to the resolution bucket and closures to the closing bucket
+func buildTrendSeries(rows []*repo.IncidentTrendRow, interval string, from, to time.Time) []dto.IncidentTrendBucket {
+	var starts []time.Time
+	for start := from; start.Before(to); start = addTrendBuckets(start, interval, 1) {
+		starts = append(starts, start)
+	}
+	accumulators := make([]trendAccumulator, len(starts))
+
+	bucketOf := func(t time.Time) int {
+		return sort.Search(len(starts), func(i int) bool { return starts[i].After(t) }) - 1
+	}
+
+	for _, row := range rows {
+		if inTrendRange(&row.DetectedAt, from, to) {
+			accumulators[bucketOf(row.DetectedAt)].addDetected(row)
+		}
+		if inTrendRange(row.ResolvedAt, from, to) {
+			accumulators[bucketOf(*row.ResolvedAt)].addResolved(row)
+		}
+		if inTrendRange(row.ClosedAt, from, to) {
+			accumulators[bucketOf(*row.ClosedAt)].closed++
+		}
+	}
+
+	series := make([]dto.IncidentTrendBucket, 0, len(starts))
+	for i, start := range starts {
+		end := addTrendBuckets(start, interval, 1)
+		if end.After(to) {
+			end = to
+		}
+		acc := accumulators[i]
+		series = append(series, dto.IncidentTrendBucket{
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Detected:    acc.detected,
+			Resolved:    acc.resolved,
+			Closed:      acc.closed,
+			SLABreaches: acc.breaches,
+			MTTR:        durationStats(acc.mttr),
+			MTTD:        durationStats(acc.mttd),
+		})
+	}
+	return series
+}
+
+func summarizeTrendRows(rows []*repo.IncidentTrendRow, from, to time.Time) dto.IncidentTrendSummary {
+	var acc trendAccumulator
+	summary := dto.IncidentTrendSummary{
+		From:          from,
+		To:            to,
+		ByCategory:    map[string]int{},
+		ByCriticality: map[string]int{},
+	}
+
+	for _, row := range rows {
+		if inTrendRange(&row.DetectedAt, from, to) {
+			acc.addDetected(row)
+			summary.ByCategory[row.Category]++
+			summary.ByCriticality[row.Criticality]++
+		}
+		if inTrendRange(row.ResolvedAt, from, to) {
+			acc.addResolved(row)
+		}
+		if inTrendRange(row.ClosedAt, from, to) {
+			acc.closed++
+		}
+	}
+
+	summary.Detected = acc.detected
+	summary.Resolved = acc.resolved
+	summary.Closed = acc.closed
+	summary.SLABreaches = acc.breaches
+	summary.MTTR = durationStats(acc.mttr)
+	summary.MTTD = durationStats(acc.mttd)
+	return summary
+}
+
+func compareTrendSummaries(mode string, previous, current dto.IncidentTrendSummary) *dto.IncidentTrendComparison {
+	return &dto.IncidentTrendComparison{
+		Mode:              mode,
+		Previous:          previous,
+		DetectedChange:    percentChange(float64(previous.Detected), float64(current.Detected)),
+		ResolvedChange:    percentChange(float64(previous.Resolved), float64(current.Resolved)),
+		SLABreachesChange: percentChange(float64(previous.SLABreaches), float64(current.SLABreaches)),
+		MTTRAverageChange: optionalPercentChange(previous.MTTR.Average, current.MTTR.Average),
+		MTTRP90Change:     optionalPercentChange(previous.MTTR.P90, current.MTTR.P90),
+		MTTDAverageChange: optionalPercentChange(previous.MTTD.Average, current.MTTD.Average),
+	}
+}
+
+// percentChange returns nil when there is no baseline to compare against
+func percentChange(previous, current float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := roundHours((current - previous) * 100 / previous)
+	return &change
+}
+
+func optionalPercentChange(previous, current *float64) *float64 {
+	if previous == nil || current == nil {
+		return nil
+	}
+	return percentChange(*previous, *current)
+}
+
+// durationStats computes the average and percentiles (linear interpolation, as percentile_cont)
+func durationStats(values []float64) dto.IncidentDurationStats {
+	stats := dto.IncidentDurationStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+	average := roundHours(sum / float64(len(sorted)))
+	p50, p90, p95 := percentile(sorted, 0.5), percentile(sorted, 0.9), percentile(sorted, 0.95)
+
+	stats.Average = &average
+	stats.P50 = &p50
+	stats.P90 = &p90
+	stats.P95 = &p95
+	return stats
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	value := sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	return roundHours(value)
+}
+
+func roundHours(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func trendDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTruncateToTrendBucket(t *testing.T) {
+	tests := []struct {
+		name     string
+		at       time.Time
+		interval string
+		want     time.Time
+	}{
+		{"week from wednesday", time.Date(2026, 3, 11, 15, 30, 0, 0, time.UTC), dto.IncidentTrendIntervalWeek, trendDate(2026, 3, 9)},
+		{"week from monday", trendDate(2026, 3, 9), dto.IncidentTrendIntervalWeek, trendDate(2026, 3, 9)},
+		{"week from sunday", time.Date(2026, 3, 15, 23, 59, 0, 0, time.UTC), dto.IncidentTrendIntervalWeek, trendDate(2026, 3, 9)},
+		{"week across month boundary", trendDate(2026, 4, 2), dto.IncidentTrendIntervalWeek, trendDate(2026, 3, 30)},
+		{"month", time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC), dto.IncidentTrendIntervalMonth, trendDate(2026, 3, 1)},
+		{"month first day", trendDate(2026, 2, 1), dto.IncidentTrendIntervalMonth, trendDate(2026, 2, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, truncateToTrendBucket(tt.at, tt.interval))
+		})
+	}
+}
+
+func TestTrendPeriod(t *testing.T) {
+	local := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name      string
+		req       dto.IncidentTrendRequest
+		wantFrom  time.Time
+		wantTo    time.Time
+		wantField string
+	}{
+		{
+			name:     "months aligned to bucket start",
+			req:      dto.IncidentTrendRequest{Interval: dto.IncidentTrendIntervalMonth, From: "2026-01-15", To: "2026-03-31"},
+			wantFrom: local(2026, 1, 1),
+			wantTo:   local(2026, 4, 1),
+		},
+		{
+			name:     "weeks aligned to monday",
+			req:      dto.IncidentTrendRequest{Interval: dto.IncidentTrendIntervalWeek, From: "2026-03-04", To: "2026-03-15"},
+			wantFrom: local(2026, 3, 2),
+			wantTo:   local(2026, 3, 16),
+		},
+		{
+			name:     "default number of buckets before to",
+			req:      dto.IncidentTrendRequest{Interval: dto.IncidentTrendIntervalMonth, To: "2026-12-31"},
+			wantFrom: local(2026, 1, 1),
+			wantTo:   local(2027, 1, 1),
+		},
+		{
+			name:      "invalid from",
+			req:       dto.IncidentTrendRequest{Interval: dto.IncidentTrendIntervalMonth, From: "15.01.2026"},
+			wantField: "from",
+		},
+		{
+			name:      "invalid to",
+			req:       dto.IncidentTrendRequest{Interval: dto.IncidentTrendIntervalMonth, To: "2026/03/31"},
+			wantField: "to",
+		},
+		{
+			name:      "from after to",
+			req:       dto.IncidentTrendRequest{Interval: dto.IncidentTrendIntervalMonth, From: "2026-05-01", To: "2026-03-31"},
+			wantField: "from",
+		},
+		{
+			name:      "too many weeks",
+			req:       dto.IncidentTrendRequest{Interval: dto.IncidentTrendIntervalWeek, From: "2020-01-01", To: "2026-03-31"},
+			wantField: "from",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := trendPeriod(tt.req)
+			if tt.wantField != "" {
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.wantFrom.Equal(from), "from: want %v, got %v", tt.wantFrom, from)
+			assert.True(t, tt.wantTo.Equal(to), "to: want %v, got %v", tt.wantTo, to)
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		sorted []float64
+		p      float64
+		want   float64
+	}{
+		{"single value", []float64{7}, 0.9, 7},
+		{"median of even count", []float64{1, 2, 3, 4}, 0.5, 2.5},
+		{"median of odd count", []float64{1, 2, 3}, 0.5, 2},
+		{"interpolated p90", []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 0.9, 9.1},
+		{"p95 rounds to hundredths", []float64{0, 1, 2}, 0.95, 1.9},
+		{"lowest", []float64{3, 8}, 0, 3},
+		{"highest", []float64{3, 8}, 1, 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, percentile(tt.sorted, tt.p), 1e-9)
+		})
+	}
+}
+
+func TestDurationStats(t *testing.T) {
+	empty := durationStats(nil)
+	assert.Equal(t, 0, empty.Count)
+	assert.Nil(t, empty.Average)
+	assert.Nil(t, empty.P50)
+
+	values := []float64{10, 1, 4}
+	stats := durationStats(values)
+	assert.Equal(t, 3, stats.Count)
+	assert.Equal(t, 5.0, *stats.Average)
+	assert.Equal(t, 4.0, *stats.P50)
+	assert.Equal(t, 8.8, *stats.P90)
+	assert.Equal(t, 9.4, *stats.P95)
+	assert.Equal(t, []float64{10, 1, 4}, values, "input must not be reordered")
+}
+
+func TestPercentChange(t *testing.T) {
+	assert.Nil(t, percentChange(0, 5))
+	assert.Equal(t, 50.0, *percentChange(10, 15))
+	assert.Equal(t, -33.33, *percentChange(3, 2))
+	assert.Nil(t, optionalPercentChange(nil, percentChange(1, 2)))
+}
+
+func TestBuildTrendSeries(t *testing.T) {
+	from, to := trendDate(2026, 1, 1), trendDate(2026, 4, 1)
+	at := func(month time.Month, day int) *time.Time {
+		moment := time.Date(2026, month, day, 12, 0, 0, 0, time.UTC)
+		return &moment
+	}
+	minutes := func(value int) *int { return &value }
+
+	rows := []*repo.IncidentTrendRow{
+		// Обнаружен в январе, решен в феврале, закрыт в марте
+		{DetectedAt: *at(1, 10), ResolvedAt: at(2, 3), ClosedAt: at(3, 1), MTTDMinutes: minutes(60), MTTRMinutes: minutes(120)},
+		{DetectedAt: *at(1, 20), ResponseBreached: true, MTTDMinutes: minutes(180)},
+		{DetectedAt: *at(2, 28), ResolvedAt: at(2, 28), ResolutionBreached: true, MTTRMinutes: minutes(30)},
+		// Обнаружен до периода: учитывается только решение
+		{DetectedAt: time.Date(2025, 12, 30, 0, 0, 0, 0, time.UTC), ResolvedAt: at(3, 31), MTTRMinutes: minutes(600)},
+		// Закрыт после периода
+		{DetectedAt: *at(3, 5), ClosedAt: &to},
+	}
+
+	series := buildTrendSeries(rows, dto.IncidentTrendIntervalMonth, from, to)
+	require.Len(t, series, 3)
+
+	type bucketCounts struct {
+		start                                time.Time
+		detected, resolved, closed, breaches int
+		mttrCount, mttdCount                 int
+	}
+	want := []bucketCounts{
+		{start: trendDate(2026, 1, 1), detected: 2, breaches: 1, mttdCount: 2},
+		{start: trendDate(2026, 2, 1), detected: 1, resolved: 2, breaches: 1, mttrCount: 2},
+		{start: trendDate(2026, 3, 1), detected: 1, resolved: 1, closed: 1, mttrCount: 1},
+	}
+	for i, bucket := range series {
+		assert.Equal(t, want[i].start, bucket.PeriodStart, "bucket %d", i)
+		assert.Equal(t, want[i].detected, bucket.Detected, "bucket %d detected", i)
+		assert.Equal(t, want[i].resolved, bucket.Resolved, "bucket %d resolved", i)
+		assert.Equal(t, want[i].closed, bucket.Closed, "bucket %d closed", i)
+		assert.Equal(t, want[i].breaches, bucket.SLABreaches, "bucket %d breaches", i)
+		assert.Equal(t, want[i].mttrCount, bucket.MTTR.Count, "bucket %d mttr", i)
+		assert.Equal(t, want[i].mttdCount, bucket.MTTD.Count, "bucket %d mttd", i)
+	}
+	assert.Equal(t, trendDate(2026, 4, 1), series[2].PeriodEnd)
+	assert.Equal(t, 2.0, *series[0].MTTD.Average)
+	assert.Equal(t, 1.25, *series[1].MTTR.Average)
+
+	summary := summarizeTrendRows(rows, from, to)
+	assert.Equal(t, 4, summary.Detected)
+	assert.Equal(t, 3, summary.Resolved)
+	assert.Equal(t, 1, summary.Closed)
+	assert.Equal(t, 2, summary.SLABreaches)
+}
+
+func TestBuildTrendSeries_PartialLastWeek(t *testing.T) {
+	from, to := trendDate(2026, 3, 2), trendDate(2026, 3, 19)
+
+	series := buildTrendSeries(nil, dto.IncidentTrendIntervalWeek, from, to)
+	require.Len(t, series, 3)
+	assert.Equal(t, trendDate(2026, 3, 16), series[2].PeriodStart)
+	assert.Equal(t, to, series[2].PeriodEnd, "the last bucket is cut at the end of the period")
+	assert.Zero(t, series[2].Detected)
+}
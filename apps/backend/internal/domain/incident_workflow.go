@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// incidentStatusInput holds the values supplied together with a status change
+type incidentStatusInput struct {
+	Comment    *string
+	AssignedTo *string
+	RootCause  *string
+	Resolution *string
+}
+
+// GetStatusWorkflow returns the effective transitions and whether the default workflow is used
+func (s *IncidentService) GetStatusWorkflow(ctx context.Context, tenantID string) ([]*repo.IncidentStatusTransition, bool, error) {
+	log.Printf("DEBUG: incident_service.GetStatusWorkflow tenant=%s", tenantID)
+
+	transitions, err := s.incidentRepo.GetStatusTransitions(ctx, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetStatusWorkflow GetStatusTransitions: %v", err)
+		return nil, false, err
+	}
+
+	isDefault := len(transitions) == 0 || transitions[0].TenantID == nil
+	return transitions, isDefault, nil
+}
+
+// SetStatusWorkflow replaces the tenant transitions; an empty list restores the default workflow
+func (s *IncidentService) SetStatusWorkflow(ctx context.Context, tenantID string, req dto.IncidentWorkflowRequest) ([]*repo.IncidentStatusTransition, bool, error) {
+	log.Printf("DEBUG: incident_service.SetStatusWorkflow tenant=%s transitions=%d", tenantID, len(req.Transitions))
+
+	seen := make(map[string]bool)
+	transitions := make([]*repo.IncidentStatusTransition, 0, len(req.Transitions))
+	for _, t := range req.Transitions {
+		key := t.FromStatus + "->" + t.ToStatus
+		if seen[key] {
+			return nil, false, NewValidationError("transitions", "duplicate transition "+key)
+		}
+		seen[key] = true
+
+		requiredFields := t.RequiredFields
+		if requiredFields == nil {
+			requiredFields = []string{}
+		}
+		transitions = append(transitions, &repo.IncidentStatusTransition{
+			ID:             uuid.New().String(),
+			TenantID:       &tenantID,
+			FromStatus:     t.FromStatus,
+			ToStatus:       t.ToStatus,
+			RequiredFields: requiredFields,
+			CreatedAt:      time.Now(),
+		})
+	}
+
+	if err := s.incidentRepo.ReplaceStatusTransitions(ctx, tenantID, transitions); err != nil {
+		log.Printf("ERROR: incident_service.SetStatusWorkflow ReplaceStatusTransitions: %v", err)
+		return nil, false, err
+	}
+
+	return s.GetStatusWorkflow(ctx, tenantID)
+}
+
+// GetStatusTimeline returns the full status history of an incident
+func (s *IncidentService) GetStatusTimeline(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentStatusChange, error) {
+	log.Printf("DEBUG: incident_service.GetStatusTimeline incident=%s", incidentID)
+
+	// Verify incident exists
+	if _, err := s.incidentRepo.GetByID(ctx, incidentID, tenantID); err != nil {
+		log.Printf("ERROR: incident_service.GetStatusTimeline GetByID: %v", err)
+		return nil, err
+	}
+
+	history, err := s.incidentRepo.GetStatusHistory(ctx, incidentID)
+	if err != nil {
+		log.Printf("ERROR: incident_service.GetStatusTimeline GetStatusHistory: %v", err)
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// prepareStatusTransition checks the transition against the workflow, applies the supplied values
+// and timestamps to the incident and returns the timeline entry to be saved with it
+func (s *IncidentService) prepareStatusTransition(ctx context.Context, incident *repo.Incident, toStatus string, input incidentStatusInput, changedBy string) (*repo.IncidentStatusChange, error) {
+	fromStatus := incident.Status
+
+	transitions, err := s.incidentRepo.GetStatusTransitions(ctx, incident.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transition *repo.IncidentStatusTransition
+	for _, t := range transitions {
+		if t.FromStatus == fromStatus && t.ToStatus == toStatus {
+			transition = t
+			break
+		}
+	}
+	if transition == nil {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrIncidentTransitionNotAllowed, fromStatus, toStatus)
+	}
+
+	if input.AssignedTo != nil && *input.AssignedTo != "" {
+		incident.AssignedTo = input.AssignedTo
+	}
+	if input.RootCause != nil {
+		incident.RootCause = input.RootCause
+	}
+	if input.Resolution != nil {
+		incident.Resolution = input.Resolution
+	}
+
+	for _, field := range transition.RequiredFields {
+		var value *string
+		switch field {
+		case dto.IncidentFieldAssignedTo:
+			value = incident.AssignedTo
+		case dto.IncidentFieldRootCause:
+			value = incident.RootCause
+		case dto.IncidentFieldResolution:
+			value = incident.Resolution
+		case dto.IncidentFieldComment:
+			value = input.Comment
+		}
+		if value == nil || strings.TrimSpace(*value) == "" {
+			return nil, NewValidationError(field, fmt.Sprintf("%s is required to move incident from %s to %s", field, fromStatus, toStatus))
+		}
+	}
+
+	now := time.Now()
+	isReopen := IsIncidentReopen(fromStatus, toStatus)
+	if isReopen {
+		// Повторное открытие сбрасывает отметки о решении и закрытии
+		incident.ResolvedAt = nil
+		incident.ClosedAt = nil
+		incident.ReopenCount++
+	}
+	if fromStatus == dto.IncidentStatusNew && incident.RespondedAt == nil {
+		// Первый уход из статуса "new" останавливает таймер реакции SLA
+		incident.RespondedAt = &now
+	}
+	if toStatus == dto.IncidentStatusResolved {
+		incident.ResolvedAt = &now
+	}
+	if toStatus == dto.IncidentStatusClosed {
+		incident.ClosedAt = &now
+	}
+	incident.Status = toStatus
+	incident.UpdatedAt = now
+
+	return &repo.IncidentStatusChange{
+		ID:         uuid.New().String(),
+		IncidentID: incident.ID,
+		FromStatus: &fromStatus,
+		ToStatus:   toStatus,
+		IsReopen:   isReopen,
+		Comment:    input.Comment,
+		ChangedBy:  &changedBy,
+		ChangedAt:  now,
+	}, nil
+}
+
+// IsIncidentReopen reports whether a transition moves a resolved or closed incident back into work
+func IsIncidentReopen(fromStatus, toStatus string) bool {
+	return isIncidentFinished(fromStatus) && !isIncidentFinished(toStatus)
+}
+
+func isIncidentFinished(status string) bool {
+	return status == dto.IncidentStatusResolved || status == dto.IncidentStatusClosed
+}
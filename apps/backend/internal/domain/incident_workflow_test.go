@@ -0,0 +1,289 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWorkflowRepo serves a fixed workflow and stores status changes; concurrent sets the status
+// another request has already moved the incident to, so ChangeStatus loses the race
+type fakeWorkflowRepo struct {
+	IncidentRepoInterface
+
+	transitions []*repo.IncidentStatusTransition
+	incident    *repo.Incident
+	concurrent  string
+	changes     []*repo.IncidentStatusChange
+}
+
+func (r *fakeWorkflowRepo) GetStatusTransitions(ctx context.Context, tenantID string) ([]*repo.IncidentStatusTransition, error) {
+	return r.transitions, nil
+}
+
+func (r *fakeWorkflowRepo) GetByID(ctx context.Context, id, tenantID string) (*repo.Incident, error) {
+	if r.incident == nil || r.incident.ID != id || r.incident.TenantID != tenantID {
+		return nil, errors.New("incident not found")
+	}
+	copied := *r.incident
+	return &copied, nil
+}
+
+func (r *fakeWorkflowRepo) ChangeStatus(ctx context.Context, incident *repo.Incident, change *repo.IncidentStatusChange) (bool, error) {
+	if r.concurrent != "" {
+		r.incident.Status = r.concurrent
+	}
+	if change.FromStatus == nil || r.incident.Status != *change.FromStatus {
+		return false, nil
+	}
+	stored := *incident
+	r.incident = &stored
+	r.changes = append(r.changes, change)
+	return true, nil
+}
+
+// testIncidentWorkflow mirrors the default workflow of migration 041
+func testIncidentWorkflow() []*repo.IncidentStatusTransition {
+	transition := func(from, to string, required ...string) *repo.IncidentStatusTransition {
+		return &repo.IncidentStatusTransition{FromStatus: from, ToStatus: to, RequiredFields: required}
+	}
+	return []*repo.IncidentStatusTransition{
+		transition(dto.IncidentStatusNew, dto.IncidentStatusAssigned, dto.IncidentFieldAssignedTo),
+		transition(dto.IncidentStatusAssigned, dto.IncidentStatusInProgress),
+		transition(dto.IncidentStatusInProgress, dto.IncidentStatusResolved, dto.IncidentFieldRootCause, dto.IncidentFieldResolution),
+		transition(dto.IncidentStatusResolved, dto.IncidentStatusClosed),
+		transition(dto.IncidentStatusResolved, dto.IncidentStatusInProgress, dto.IncidentFieldComment),
+		transition(dto.IncidentStatusClosed, dto.IncidentStatusInProgress, dto.IncidentFieldComment),
+	}
+}
+
+func TestPrepareStatusTransition(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		incident  func(incident *repo.Incident)
+		input     incidentStatusInput
+		wantErr   error
+		wantField string
+	}{
+		{
+			name:  "assign with assignee",
+			from:  dto.IncidentStatusNew,
+			to:    dto.IncidentStatusAssigned,
+			input: incidentStatusInput{AssignedTo: incidentStringPtr("user-2")},
+		},
+		{
+			name:     "assign keeps existing assignee",
+			from:     dto.IncidentStatusNew,
+			to:       dto.IncidentStatusAssigned,
+			incident: func(incident *repo.Incident) { incident.AssignedTo = incidentStringPtr("user-1") },
+		},
+		{
+			name:      "assign without assignee",
+			from:      dto.IncidentStatusNew,
+			to:        dto.IncidentStatusAssigned,
+			input:     incidentStatusInput{AssignedTo: incidentStringPtr("")},
+			wantField: dto.IncidentFieldAssignedTo,
+		},
+		{
+			name: "start work",
+			from: dto.IncidentStatusAssigned,
+			to:   dto.IncidentStatusInProgress,
+		},
+		{
+			name: "resolve with root cause and resolution",
+			from: dto.IncidentStatusInProgress,
+			to:   dto.IncidentStatusResolved,
+			input: incidentStatusInput{
+				RootCause:  incidentStringPtr("Weak password"),
+				Resolution: incidentStringPtr("Password reset, MFA enabled"),
+			},
+		},
+		{
+			name:      "resolve with blank resolution",
+			from:      dto.IncidentStatusInProgress,
+			to:        dto.IncidentStatusResolved,
+			input:     incidentStatusInput{RootCause: incidentStringPtr("Weak password"), Resolution: incidentStringPtr("  ")},
+			wantField: dto.IncidentFieldResolution,
+		},
+		{
+			name:      "reopen without comment",
+			from:      dto.IncidentStatusClosed,
+			to:        dto.IncidentStatusInProgress,
+			wantField: dto.IncidentFieldComment,
+		},
+		{
+			name:    "skip assignment",
+			from:    dto.IncidentStatusNew,
+			to:      dto.IncidentStatusResolved,
+			wantErr: ErrIncidentTransitionNotAllowed,
+		},
+		{
+			name:    "close unresolved incident",
+			from:    dto.IncidentStatusInProgress,
+			to:      dto.IncidentStatusClosed,
+			wantErr: ErrIncidentTransitionNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewIncidentService(&fakeWorkflowRepo{transitions: testIncidentWorkflow()}, nil, nil, nil, nil)
+			incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Status: tt.from}
+			if tt.incident != nil {
+				tt.incident(incident)
+			}
+
+			change, err := service.prepareStatusTransition(context.Background(), incident, tt.to, tt.input, "user-1")
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.from, incident.Status, "a rejected transition leaves the status unchanged")
+			case tt.wantField != "":
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantField, validationErr.Field)
+				assert.Equal(t, tt.from, incident.Status)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.to, incident.Status)
+				require.NotNil(t, change.FromStatus)
+				assert.Equal(t, tt.from, *change.FromStatus)
+				assert.Equal(t, tt.to, change.ToStatus)
+				assert.Equal(t, "incident-1", change.IncidentID)
+				assert.False(t, change.IsReopen)
+			}
+		})
+	}
+}
+
+func TestPrepareStatusTransition_Timestamps(t *testing.T) {
+	service := NewIncidentService(&fakeWorkflowRepo{transitions: testIncidentWorkflow()}, nil, nil, nil, nil)
+	ctx := context.Background()
+	incident := &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Status: dto.IncidentStatusNew}
+
+	change, err := service.prepareStatusTransition(ctx, incident, dto.IncidentStatusAssigned, incidentStatusInput{AssignedTo: incidentStringPtr("user-2")}, "user-1")
+	require.NoError(t, err)
+	require.NotNil(t, incident.RespondedAt, "leaving new stops the response timer")
+	assert.Equal(t, change.ChangedAt, *incident.RespondedAt)
+	assert.Equal(t, "user-2", *incident.AssignedTo)
+	respondedAt := *incident.RespondedAt
+
+	_, err = service.prepareStatusTransition(ctx, incident, dto.IncidentStatusInProgress, incidentStatusInput{}, "user-2")
+	require.NoError(t, err)
+	_, err = service.prepareStatusTransition(ctx, incident, dto.IncidentStatusResolved, incidentStatusInput{
+		RootCause:  incidentStringPtr("Phishing"),
+		Resolution: incidentStringPtr("Mailbox cleaned"),
+	}, "user-2")
+	require.NoError(t, err)
+	require.NotNil(t, incident.ResolvedAt)
+	assert.Nil(t, incident.ClosedAt)
+	assert.Equal(t, "Phishing", *incident.RootCause)
+
+	_, err = service.prepareStatusTransition(ctx, incident, dto.IncidentStatusClosed, incidentStatusInput{}, "user-2")
+	require.NoError(t, err)
+	require.NotNil(t, incident.ClosedAt)
+	assert.Equal(t, respondedAt, *incident.RespondedAt, "the response time is recorded once")
+}
+
+func TestPrepareStatusTransition_Reopen(t *testing.T) {
+	resolvedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	closedAt := resolvedAt.Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		incident repo.Incident
+	}{
+		{
+			name:     "resolved",
+			incident: repo.Incident{Status: dto.IncidentStatusResolved, ResolvedAt: &resolvedAt},
+		},
+		{
+			name:     "closed and reopened before",
+			incident: repo.Incident{Status: dto.IncidentStatusClosed, ResolvedAt: &resolvedAt, ClosedAt: &closedAt, ReopenCount: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewIncidentService(&fakeWorkflowRepo{transitions: testIncidentWorkflow()}, nil, nil, nil, nil)
+			incident := tt.incident
+			incident.ID, incident.TenantID = "incident-1", "tenant-1"
+			reopenCount := incident.ReopenCount
+
+			change, err := service.prepareStatusTransition(context.Background(), &incident, dto.IncidentStatusInProgress,
+				incidentStatusInput{Comment: incidentStringPtr("The attacker is back")}, "user-1")
+			require.NoError(t, err)
+
+			assert.True(t, change.IsReopen)
+			assert.Equal(t, "The attacker is back", *change.Comment)
+			assert.Equal(t, dto.IncidentStatusInProgress, incident.Status)
+			assert.Nil(t, incident.ResolvedAt)
+			assert.Nil(t, incident.ClosedAt)
+			assert.Equal(t, reopenCount+1, incident.ReopenCount)
+		})
+	}
+}
+
+func TestIsIncidentReopen(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{dto.IncidentStatusResolved, dto.IncidentStatusInProgress, true},
+		{dto.IncidentStatusClosed, dto.IncidentStatusNew, true},
+		{dto.IncidentStatusClosed, dto.IncidentStatusAssigned, true},
+		{dto.IncidentStatusResolved, dto.IncidentStatusClosed, false},
+		{dto.IncidentStatusClosed, dto.IncidentStatusResolved, false},
+		{dto.IncidentStatusInProgress, dto.IncidentStatusResolved, false},
+		{dto.IncidentStatusNew, dto.IncidentStatusAssigned, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_to_"+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsIncidentReopen(tt.from, tt.to))
+		})
+	}
+}
+
+func TestUpdateIncidentStatus_ConcurrentTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		concurrent string
+		wantErr    error
+	}{
+		{name: "no concurrent change"},
+		{name: "status changed meanwhile", concurrent: dto.IncidentStatusNew, wantErr: ErrIncidentStatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incidentRepo := &fakeWorkflowRepo{
+				transitions: testIncidentWorkflow(),
+				incident:    &repo.Incident{ID: "incident-1", TenantID: "tenant-1", Status: dto.IncidentStatusAssigned},
+				concurrent:  tt.concurrent,
+			}
+			service := NewIncidentService(incidentRepo, nil, nil, nil, nil)
+
+			incident, err := service.UpdateIncidentStatus(context.Background(), "incident-1", "tenant-1",
+				dto.IncidentStatusUpdateRequest{Status: dto.IncidentStatusInProgress}, "user-1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, incidentRepo.changes, "the losing transition writes no timeline entry")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, dto.IncidentStatusInProgress, incident.Status)
+			assert.Equal(t, dto.IncidentStatusInProgress, incidentRepo.incident.Status)
+			require.Len(t, incidentRepo.changes, 1)
+		})
+	}
+}
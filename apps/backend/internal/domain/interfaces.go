@@ -50,6 +50,58 @@ type AssetServiceInterface interface {
 	DeleteAssetDocument(ctx context.Context, assetID, documentID, tenantID, deletedBy string) error
 	DeleteDocumentLink(ctx context.Context, documentID, tenantID, deletedBy string) error
 	DownloadDocumentFromStorage(ctx context.Context, documentID, tenantID string) (*dto.DocumentDownloadDTO, error)
+
+	// Discovery agents
+	ListDiscoveryAgents(ctx context.Context, tenantID string) ([]*repo.AssetDiscoveryAgent, error)
+	CreateDiscoveryAgent(ctx context.Context, tenantID string, req dto.AssetDiscoveryAgentRequest, createdBy string) (*repo.AssetDiscoveryAgent, string, error)
+	UpdateDiscoveryAgent(ctx context.Context, id, tenantID string, req dto.AssetDiscoveryAgentRequest) (*repo.AssetDiscoveryAgent, error)
+	RotateDiscoveryAgentToken(ctx context.Context, id, tenantID string) (*repo.AssetDiscoveryAgent, string, error)
+	DeleteDiscoveryAgent(ctx context.Context, id, tenantID string) error
+	IngestDiscoveryReport(ctx context.Context, token string, body []byte) (*repo.AssetDiscoveryReport, error)
+	ListDiscoveryReports(ctx context.Context, tenantID string, filters map[string]interface{}, page, pageSize int) ([]*repo.AssetDiscoveryReport, int64, error)
+	GetDiscoveryReport(ctx context.Context, id, tenantID string) (*repo.AssetDiscoveryReport, error)
+	ResolveDiscoveryReport(ctx context.Context, id, tenantID string, req dto.AssetDiscoveryResolveRequest, userID string) (*repo.AssetDiscoveryReport, error)
+
+	// Import
+	PreviewAssetImport(ctx context.Context, tenantID, filename string, data []byte, mapping map[string]string) (*dto.AssetImportPreviewResponse, error)
+	ImportAssets(ctx context.Context, tenantID, filename string, data []byte, mapping map[string]string, skipInvalid bool, importedBy string) (*dto.AssetImportResultResponse, *dto.AssetImportPreviewResponse, error)
+
+	// Relationships and dependency map
+	ListAssetRelationships(ctx context.Context, assetID, tenantID string) ([]dto.AssetRelationshipResponse, error)
+	CreateAssetRelationship(ctx context.Context, assetID, tenantID string, req dto.AssetRelationshipRequest, createdBy string) (*dto.AssetRelationshipResponse, error)
+	DeleteAssetRelationship(ctx context.Context, assetID, relationshipID, tenantID, deletedBy string) error
+	GetAssetGraph(ctx context.Context, assetID, tenantID string, depth int) (*dto.AssetGraphResponse, error)
+	GetAssetImpact(ctx context.Context, assetID, tenantID string) (*dto.AssetImpactResponse, error)
+
+	// Vulnerabilities
+	ImportVulnerabilityFeed(ctx context.Context, tenantID, filename string, data []byte, importedBy string) (*dto.VulnerabilityFeedImportResponse, error)
+	MatchAssetVulnerabilities(ctx context.Context, tenantID string, req dto.VulnerabilityMatchRequest, requestedBy string) (*dto.VulnerabilityMatchResponse, error)
+	ListVulnerabilities(ctx context.Context, tenantID string, filters map[string]interface{}) ([]dto.AssetVulnerabilityResponse, error)
+	GetAssetVulnerabilities(ctx context.Context, assetID, tenantID, status string) (*dto.AssetVulnerabilityListResponse, error)
+
+	// Lifecycle
+	RequestLifecycleOperation(ctx context.Context, assetID, tenantID string, req dto.AssetLifecycleOperationRequest, requestedBy string) (*dto.AssetLifecycleOperationResponse, error)
+	GetLifecycleOperation(ctx context.Context, assetID, operationID, tenantID string) (*dto.AssetLifecycleOperationResponse, error)
+	ListAssetLifecycleOperations(ctx context.Context, assetID, tenantID string) ([]dto.AssetLifecycleOperationResponse, error)
+	ListLifecycleOperations(ctx context.Context, tenantID string, filters map[string]interface{}) ([]dto.AssetLifecycleOperationResponse, error)
+	ApproveLifecycleOperation(ctx context.Context, assetID, operationID, tenantID string, req dto.AssetLifecycleDecisionRequest, approverID string) (*dto.AssetLifecycleOperationResponse, error)
+	RejectLifecycleOperation(ctx context.Context, assetID, operationID, tenantID string, req dto.AssetLifecycleDecisionRequest, approverID string) (*dto.AssetLifecycleOperationResponse, error)
+	CancelLifecycleOperation(ctx context.Context, assetID, operationID, tenantID, userID string) (*dto.AssetLifecycleOperationResponse, error)
+	GenerateLifecycleAct(ctx context.Context, assetID, operationID, tenantID string, generatePDF bool, userID string) (*dto.AssetLifecycleOperationResponse, error)
+
+	// Inventory campaigns
+	CreateInventoryCampaign(ctx context.Context, tenantID string, req dto.InventoryCampaignRequest, createdBy string) (*dto.InventoryCampaignResponse, error)
+	UpdateInventoryCampaign(ctx context.Context, campaignID, tenantID string, req dto.InventoryCampaignRequest) (*dto.InventoryCampaignResponse, error)
+	StartInventoryCampaign(ctx context.Context, campaignID, tenantID string) (*dto.InventoryCampaignResponse, error)
+	CancelInventoryCampaign(ctx context.Context, campaignID, tenantID string) (*dto.InventoryCampaignResponse, error)
+	GetInventoryCampaign(ctx context.Context, campaignID, tenantID string) (*dto.InventoryCampaignResponse, error)
+	ListInventoryCampaigns(ctx context.Context, tenantID string, filters map[string]interface{}) ([]dto.InventoryCampaignResponse, error)
+	ScanInventoryLabel(ctx context.Context, campaignID, tenantID string, req dto.InventoryScanRequest, userID string) (*dto.InventoryScanResponse, error)
+	ListInventoryItems(ctx context.Context, campaignID, tenantID string, filters map[string]interface{}) ([]dto.InventoryItemResponse, error)
+	ResolveInventoryDiscrepancy(ctx context.Context, campaignID, itemID, tenantID string, req dto.InventoryResolveRequest, userID string) (*dto.InventoryItemResponse, error)
+	CompleteInventoryCampaign(ctx context.Context, campaignID, tenantID, userID string) (*dto.InventoryCampaignResponse, error)
+	SignInventoryCampaign(ctx context.Context, campaignID, tenantID string, req dto.InventorySignRequest, userID string) (*dto.InventoryCampaignResponse, error)
+	GenerateInventoryReport(ctx context.Context, campaignID, tenantID, format string) ([]byte, error)
 }
 
 // RiskServiceInterface - интерфейс для RiskService
@@ -58,7 +110,7 @@ type RiskServiceInterface interface {
 	GetRisk(ctx context.Context, id string) (*repo.Risk, error)
 	ListRisks(ctx context.Context, tenantID string) ([]repo.Risk, error)
 	UpdateRisk(ctx context.Context, id, title string, description, category *string, likelihood, impact int, ownerID, assetID *string) error
-	UpdateRiskStatus(ctx context.Context, id, status string) error
+	UpdateRiskStatus(ctx context.Context, id, tenantID, status, updatedBy string) error
 	DeleteRisk(ctx context.Context, id string) error
 }
 
@@ -76,6 +128,107 @@ type IncidentServiceInterface interface {
 	GetActions(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentAction, error)
 	GetIncidentMetrics(ctx context.Context, tenantID string) (*repo.IncidentMetricsSummary, error)
 	UpdateIncidentStatus(ctx context.Context, id, tenantID string, req dto.IncidentStatusUpdateRequest, updatedBy string) (*repo.Incident, error)
+	GetStatusTimeline(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentStatusChange, error)
+	GetStatusWorkflow(ctx context.Context, tenantID string) ([]*repo.IncidentStatusTransition, bool, error)
+	SetStatusWorkflow(ctx context.Context, tenantID string, req dto.IncidentWorkflowRequest) ([]*repo.IncidentStatusTransition, bool, error)
+	GetSLAPolicies(ctx context.Context, tenantID string) ([]*repo.IncidentSLAPolicy, error)
+	SetSLAPolicies(ctx context.Context, tenantID string, req dto.IncidentSLAPoliciesRequest) ([]*repo.IncidentSLAPolicy, error)
+	ListBusinessCalendars(ctx context.Context, tenantID string) ([]*repo.IncidentBusinessCalendar, error)
+	CreateBusinessCalendar(ctx context.Context, tenantID string, req dto.IncidentBusinessCalendarRequest) (*repo.IncidentBusinessCalendar, error)
+	UpdateBusinessCalendar(ctx context.Context, id, tenantID string, req dto.IncidentBusinessCalendarRequest) (*repo.IncidentBusinessCalendar, error)
+	DeleteBusinessCalendar(ctx context.Context, id, tenantID string) error
+	GetIncidentSLA(ctx context.Context, id, tenantID string) (*dto.IncidentSLAResponse, error)
+	ListSLAEvents(ctx context.Context, tenantID string, filters map[string]interface{}, limit int) ([]*repo.IncidentSLAEvent, error)
+	ListAlertSources(ctx context.Context, tenantID string) ([]*repo.IncidentAlertSource, error)
+	CreateAlertSource(ctx context.Context, tenantID string, req dto.IncidentAlertSourceRequest, createdBy string) (*repo.IncidentAlertSource, string, error)
+	UpdateAlertSource(ctx context.Context, id, tenantID string, req dto.IncidentAlertSourceRequest) (*repo.IncidentAlertSource, error)
+	RotateAlertSourceToken(ctx context.Context, id, tenantID string) (*repo.IncidentAlertSource, string, error)
+	DeleteAlertSource(ctx context.Context, id, tenantID string) error
+	GetIncidentAlerts(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentAlert, error)
+	IngestAlerts(ctx context.Context, token string, body []byte) (*dto.IncidentAlertIngestResponse, error)
+	GetPlaybooks(ctx context.Context, tenantID string) ([]*repo.IncidentPlaybook, error)
+	GetPlaybook(ctx context.Context, tenantID, category string) (*repo.IncidentPlaybook, error)
+	SetPlaybook(ctx context.Context, tenantID, category string, req dto.IncidentPlaybookRequest) (*repo.IncidentPlaybook, error)
+	ResetPlaybook(ctx context.Context, tenantID, category string) (*repo.IncidentPlaybook, error)
+	GetIncidentPlaybook(ctx context.Context, incidentID, tenantID string) (*dto.IncidentPlaybookProgressResponse, error)
+	ApplyIncidentPlaybook(ctx context.Context, incidentID, tenantID, userID string) (*dto.IncidentPlaybookProgressResponse, error)
+	UpdateActionStatus(ctx context.Context, incidentID, actionID, tenantID string, req dto.IncidentActionStatusRequest, updatedBy string) (*repo.IncidentAction, error)
+	ListRegulatoryProfiles(ctx context.Context, tenantID string) ([]*repo.IncidentRegulatoryProfile, error)
+	GetRegulatoryPresets() []dto.IncidentRegulatoryProfileRequest
+	CreateRegulatoryProfile(ctx context.Context, tenantID string, req dto.IncidentRegulatoryProfileRequest) (*repo.IncidentRegulatoryProfile, error)
+	CreateRegulatoryProfileFromPreset(ctx context.Context, tenantID, framework string) (*repo.IncidentRegulatoryProfile, error)
+	UpdateRegulatoryProfile(ctx context.Context, id, tenantID string, req dto.IncidentRegulatoryProfileRequest) (*repo.IncidentRegulatoryProfile, error)
+	DeleteRegulatoryProfile(ctx context.Context, id, tenantID string) error
+	GetIncidentNotifications(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentNotification, error)
+	GenerateNotificationDraft(ctx context.Context, incidentID, notificationID, tenantID string) (*repo.IncidentNotification, error)
+	UpdateIncidentNotification(ctx context.Context, incidentID, notificationID, tenantID string, req dto.IncidentNotificationUpdateRequest, updatedBy string) (*repo.IncidentNotification, error)
+	ListOverdueNotifications(ctx context.Context, tenantID string) ([]*repo.IncidentNotification, error)
+	GetIncidentReview(ctx context.Context, incidentID, tenantID string) (*repo.IncidentReview, error)
+	SaveIncidentReview(ctx context.Context, incidentID, tenantID string, req dto.IncidentReviewRequest, userID string) (*repo.IncidentReview, error)
+	AddReviewFollowUp(ctx context.Context, incidentID, tenantID string, req dto.IncidentReviewFollowUpRequest, userID string) (*repo.IncidentReviewFollowUp, error)
+	UpdateReviewFollowUp(ctx context.Context, incidentID, followUpID, tenantID string, req dto.IncidentReviewFollowUpUpdateRequest) (*repo.IncidentReviewFollowUp, error)
+	DeleteReviewFollowUp(ctx context.Context, incidentID, followUpID, tenantID string) error
+	GenerateIncidentReviewReport(ctx context.Context, incidentID, tenantID, format string) ([]byte, error)
+	ListIncidentEvidence(ctx context.Context, incidentID, tenantID string) ([]*repo.IncidentEvidence, error)
+	CollectEvidence(ctx context.Context, incidentID, tenantID string, file multipart.File, header *multipart.FileHeader, req dto.IncidentEvidenceRequest, userID, ipAddress string) (*repo.IncidentEvidence, error)
+	GetEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*repo.IncidentEvidence, []*repo.IncidentEvidenceCustody, error)
+	UpdateEvidence(ctx context.Context, incidentID, evidenceID, tenantID string, req dto.IncidentEvidenceUpdateRequest, userID, ipAddress string) (*repo.IncidentEvidence, error)
+	SealEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*repo.IncidentEvidence, error)
+	TransferEvidence(ctx context.Context, incidentID, evidenceID, tenantID string, req dto.IncidentEvidenceTransferRequest, userID, ipAddress string) (*repo.IncidentEvidence, error)
+	ExportEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*repo.IncidentEvidence, *dto.DocumentDownloadDTO, error)
+	VerifyEvidence(ctx context.Context, incidentID, evidenceID, tenantID, userID, ipAddress string) (*dto.IncidentEvidenceVerificationResult, error)
+	VerifyIncidentEvidence(ctx context.Context, incidentID, tenantID, userID, ipAddress string) ([]dto.IncidentEvidenceVerificationResult, error)
+	GetIncidentTrends(ctx context.Context, tenantID string, req dto.IncidentTrendRequest) (*dto.IncidentTrendResponse, error)
+	GetIncidentRelations(ctx context.Context, incidentID, tenantID string) (*dto.IncidentRelationsResponse, error)
+	SetIncidentParent(ctx context.Context, incidentID, tenantID string, req dto.IncidentParentRequest, userID string) (*dto.IncidentRelationsResponse, error)
+	LinkIncidents(ctx context.Context, incidentID, tenantID string, req dto.IncidentLinkRequest, userID string) (*dto.IncidentLinkResponse, error)
+	UnlinkIncidents(ctx context.Context, incidentID, linkID, tenantID string) error
+	MergeIncidents(ctx context.Context, primaryID, tenantID string, req dto.IncidentMergeRequest, userID string) ([]dto.IncidentMergeResponse, error)
+	RevertIncidentMerge(ctx context.Context, incidentID, mergeID, tenantID, userID string) (*dto.IncidentMergeResponse, error)
+	ListMailboxes(ctx context.Context, tenantID string) ([]*repo.IncidentMailbox, error)
+	CreateMailbox(ctx context.Context, tenantID string, req dto.IncidentMailboxRequest, createdBy string) (*repo.IncidentMailbox, error)
+	UpdateMailbox(ctx context.Context, id, tenantID string, req dto.IncidentMailboxRequest) (*repo.IncidentMailbox, error)
+	DeleteMailbox(ctx context.Context, id, tenantID string) error
+	GetMailboxMessages(ctx context.Context, id, tenantID string) ([]*repo.IncidentMailMessage, error)
+	PollMailbox(ctx context.Context, id, tenantID string) (*dto.IncidentMailPollResult, error)
+}
+
+// TemplateRendererInterface - шаблоны документов, используемые модулем инцидентов
+type TemplateRendererInterface interface {
+	GetTemplate(ctx context.Context, id, tenantID string) (*dto.DocumentTemplateDTO, error)
+	RenderTemplate(template string, data map[string]interface{}) string
+	GeneratePDFFromHTML(ctx context.Context, html string) ([]byte, error)
+}
+
+// UserNotifierInterface - доставка уведомлений во входящие пользователя
+type UserNotifierInterface interface {
+	Notify(ctx context.Context, tenantID, userID, notificationType, title, message, entity, entityID string) error
+}
+
+// InventoryNumberGeneratorInterface - нумерация активов по правилам инвентарных номеров
+type InventoryNumberGeneratorInterface interface {
+	GenerateInventoryNumber(ctx context.Context, tenantID, assetType string, assetClass *string) (*dto.GenerateInventoryNumberResponse, error)
+}
+
+// AssetActGeneratorInterface - формирование актов передачи, ремонта и списания активов по шаблонам документов
+// и инвентаризационных описей в PDF
+type AssetActGeneratorInterface interface {
+	ListTemplates(ctx context.Context, tenantID string, filters map[string]interface{}) ([]dto.DocumentTemplateDTO, error)
+	FillTemplate(ctx context.Context, tenantID, userID string, req dto.FillTemplateRequest) (*dto.FillTemplateResponse, error)
+	GeneratePDFFromHTML(ctx context.Context, html string) ([]byte, error)
+}
+
+// IncidentRiskServiceInterface - создание рисков и мер защиты по итогам разбора инцидента
+type IncidentRiskServiceInterface interface {
+	CreateRisk(ctx context.Context, tenantID, title string, description, category *string, likelihood, impact int, ownerUserID, assetID, threatID, vulnerabilityID *string, methodology, strategy *string, dueDate *time.Time) (*repo.Risk, error)
+	AddControl(ctx context.Context, riskID string, controlID, controlName, controlType, implementationStatus string, effectiveness, description *string, createdBy string) error
+	DeleteRisk(ctx context.Context, id string) error
+}
+
+// AssetRiskServiceInterface - создание рисков по критическим уязвимостям установленного на активах ПО
+type AssetRiskServiceInterface interface {
+	CreateRisk(ctx context.Context, tenantID, title string, description, category *string, likelihood, impact int, ownerUserID, assetID, threatID, vulnerabilityID *string, methodology, strategy *string, dueDate *time.Time) (*repo.Risk, error)
+	GetCatalogEntries(ctx context.Context, tenantID, kind string, filter repo.RiskCatalogFilter) ([]repo.RiskCatalogEntry, error)
 }
 
 // AssetRepoInterface - интерфейс для AssetRepo
@@ -101,17 +254,84 @@ type AssetRepoInterface interface {
 	GetAssetsWithoutOwner(ctx context.Context, tenantID string) ([]repo.Asset, error)
 	GetAssetsWithoutPassport(ctx context.Context, tenantID string) ([]repo.Asset, error)
 	GetAssetsWithoutCriticality(ctx context.Context, tenantID string) ([]repo.Asset, error)
+	FindByNetworkIdentity(ctx context.Context, tenantID string, ipAddresses, hostnames []string) ([]repo.Asset, error)
 
 	// Document methods that are missing
 	AddDocumentWithFile(ctx context.Context, assetID, documentID, documentType, filePath, fileName, mimeType string, fileSize int64, createdBy string) error
 	GetDocumentFromStorage(ctx context.Context, documentID string) (*repo.AssetDocument, error)
 	LinkDocumentToAsset(ctx context.Context, assetID, documentID, storageDocumentID, documentType, createdBy string) error
 	GetDocumentStorage(ctx context.Context, tenantID string, req dto.DocumentStorageRequest) ([]dto.DocumentStorageResponse, int64, error)
+
+	// Discovery agents and reports
+	ListDiscoveryAgents(ctx context.Context, tenantID string) ([]*repo.AssetDiscoveryAgent, error)
+	GetDiscoveryAgent(ctx context.Context, id, tenantID string) (*repo.AssetDiscoveryAgent, error)
+	GetDiscoveryAgentByTokenHash(ctx context.Context, tokenHash string) (*repo.AssetDiscoveryAgent, error)
+	SaveDiscoveryAgent(ctx context.Context, agent *repo.AssetDiscoveryAgent) error
+	DeleteDiscoveryAgent(ctx context.Context, id, tenantID string) error
+	TouchDiscoveryAgent(ctx context.Context, id string, reportAt time.Time) error
+	FindDiscoveryCandidates(ctx context.Context, tenantID string, serials, macAddresses, hostnames []string) ([]repo.Asset, error)
+	UpdateSoftware(ctx context.Context, id, version string, installedAt *time.Time) error
+	DeleteSoftware(ctx context.Context, id string) error
+	SaveDiscoveryReport(ctx context.Context, report *repo.AssetDiscoveryReport) error
+	GetDiscoveryReport(ctx context.Context, id, tenantID string) (*repo.AssetDiscoveryReport, error)
+	ListDiscoveryReports(ctx context.Context, tenantID string, filters map[string]interface{}, page, pageSize int) ([]*repo.AssetDiscoveryReport, int64, error)
+
+	// Import
+	CreateAssets(ctx context.Context, assets []repo.Asset, createdBy, historyNote string) error
+	ResolveUserIDsByEmail(ctx context.Context, tenantID string, emails []string) (map[string]string, error)
+	FindImportDuplicates(ctx context.Context, tenantID string, inventoryNumbers, serials, macAddresses, ipAddresses []string) ([]repo.Asset, error)
+
+	// Relationships
+	CreateRelationship(ctx context.Context, rel *repo.AssetRelationship) error
+	GetRelationship(ctx context.Context, id, tenantID string) (*repo.AssetRelationship, error)
+	DeleteRelationship(ctx context.Context, id, tenantID string) error
+	ListRelationships(ctx context.Context, tenantID string) ([]repo.AssetRelationship, error)
+	ListRelationshipNodes(ctx context.Context, tenantID string) ([]repo.Asset, error)
+
+	// Vulnerabilities
+	UpsertCVEEntries(ctx context.Context, tenantID string, entries []repo.CVEEntry) error
+	UpsertCPEDictionary(ctx context.Context, tenantID string, entries []repo.CPEDictionaryEntry) error
+	CountVulnerabilityFeed(ctx context.Context, tenantID string) (int, int, error)
+	ListSoftwareForMatching(ctx context.Context, tenantID, assetID string) ([]repo.AssetSoftware, error)
+	FindCPEByTitles(ctx context.Context, tenantID string, titles []string) ([]repo.CPEDictionaryEntry, error)
+	FindCVEProducts(ctx context.Context, tenantID string, products []string) ([]repo.CPEProduct, error)
+	ListCPEMatches(ctx context.Context, tenantID string, productKeys []string) ([]repo.CVECPEMatch, error)
+	SaveVulnerabilityMatches(ctx context.Context, tenantID string, softwareIDs []string, found []repo.AssetVulnerability, detectedAt time.Time) (int, int, error)
+	ListAssetVulnerabilities(ctx context.Context, tenantID string, filters map[string]interface{}) ([]repo.AssetVulnerability, error)
+	SetVulnerabilityRisk(ctx context.Context, tenantID, assetID, cveEntryID, riskID string) error
+
+	// Lifecycle operations
+	CreateLifecycleOperation(ctx context.Context, op *repo.AssetLifecycleOperation) error
+	GetLifecycleOperation(ctx context.Context, id, tenantID string) (*repo.AssetLifecycleOperation, error)
+	HasPendingLifecycleOperation(ctx context.Context, assetID string) (bool, error)
+	ListLifecycleOperations(ctx context.Context, tenantID string, filters map[string]interface{}) ([]repo.AssetLifecycleOperation, error)
+	DecideLifecycleOperation(ctx context.Context, id, tenantID, status, decidedBy string, comment *string) (bool, error)
+	ApplyLifecycleOperation(ctx context.Context, op *repo.AssetLifecycleOperation, approvedBy string, comment *string) (bool, error)
+	SetLifecycleDocument(ctx context.Context, id, tenantID, documentID string) error
+
+	// Inventory campaigns
+	CreateInventoryCampaign(ctx context.Context, c *repo.InventoryCampaign) error
+	UpdateInventoryCampaign(ctx context.Context, c *repo.InventoryCampaign) (bool, error)
+	GetInventoryCampaign(ctx context.Context, id, tenantID string) (*repo.InventoryCampaign, error)
+	ListInventoryCampaigns(ctx context.Context, tenantID string, filters map[string]interface{}) ([]repo.InventoryCampaign, error)
+	ListDueInventoryCampaigns(ctx context.Context, day time.Time) ([]repo.InventoryCampaign, error)
+	CancelInventoryCampaign(ctx context.Context, id, tenantID string) (bool, error)
+	StartInventoryCampaign(ctx context.Context, c *repo.InventoryCampaign) (bool, error)
+	CompleteInventoryCampaign(ctx context.Context, c *repo.InventoryCampaign, completedBy string) (bool, error)
+	SignInventoryCampaign(ctx context.Context, c *repo.InventoryCampaign) (bool, error)
+	CountInventoryItems(ctx context.Context, campaignIDs []string) (map[string]dto.InventoryCampaignSummary, error)
+	ListInventoryItems(ctx context.Context, campaignID string, filters map[string]interface{}) ([]repo.InventoryItem, error)
+	GetInventoryItem(ctx context.Context, id, campaignID string) (*repo.InventoryItem, error)
+	GetInventoryItemByNumber(ctx context.Context, campaignID, inventoryNumber string) (*repo.InventoryItem, error)
+	FindAssetByInventoryNumber(ctx context.Context, tenantID, inventoryNumber string) (*repo.Asset, error)
+	SaveInventoryScan(ctx context.Context, item *repo.InventoryItem) error
+	ResolveInventoryItem(ctx context.Context, item *repo.InventoryItem, newLocation *string) error
 }
 
 // UserRepoInterface - интерфейс для UserRepo
 type UserRepoInterface interface {
 	GetByID(ctx context.Context, id string) (*repo.User, error)
+	GetByEmail(ctx context.Context, tenantID, email string) (*repo.User, error)
 	GetUserRoles(ctx context.Context, userID string) ([]string, error)
 	GetUserPermissions(ctx context.Context, userID string) ([]string, error)
 }
@@ -123,6 +343,10 @@ type IncidentRepoInterface interface {
 	Update(ctx context.Context, incident *repo.Incident) error
 	Delete(ctx context.Context, id, tenantID string) error
 	List(ctx context.Context, tenantID string, filters map[string]interface{}, limit, offset int) ([]*repo.Incident, int, error)
+	ChangeStatus(ctx context.Context, incident *repo.Incident, change *repo.IncidentStatusChange) (bool, error)
+	GetStatusHistory(ctx context.Context, incidentID string) ([]*repo.IncidentStatusChange, error)
+	GetStatusTransitions(ctx context.Context, tenantID string) ([]*repo.IncidentStatusTransition, error)
+	ReplaceStatusTransitions(ctx context.Context, tenantID string, transitions []*repo.IncidentStatusTransition) error
 	AddAsset(ctx context.Context, incidentID, assetID string) error
 	RemoveAsset(ctx context.Context, incidentID, assetID string) error
 	GetAssets(ctx context.Context, incidentID string) ([]*repo.Asset, error)
@@ -138,9 +362,85 @@ type IncidentRepoInterface interface {
 	UpdateAction(ctx context.Context, action *repo.IncidentAction) error
 	GetActions(ctx context.Context, incidentID string) ([]*repo.IncidentAction, error)
 	DeleteAction(ctx context.Context, actionID string) error
+	GetAction(ctx context.Context, actionID string) (*repo.IncidentAction, error)
+	ListPlaybooks(ctx context.Context, tenantID string) ([]*repo.IncidentPlaybook, error)
+	GetPlaybookForCategory(ctx context.Context, tenantID, category string) (*repo.IncidentPlaybook, error)
+	SavePlaybook(ctx context.Context, playbook *repo.IncidentPlaybook) error
+	DeletePlaybook(ctx context.Context, tenantID, category string) error
+	AddPlaybookActions(ctx context.Context, actions []*repo.IncidentAction) (int, error)
+	CancelPendingPlaybookActions(ctx context.Context, incidentID, keepPlaybookID string) (int64, error)
+	FindUserByRoleName(ctx context.Context, tenantID, roleName string, preferredUserID *string) (*string, error)
+	ListRegulatoryProfiles(ctx context.Context, tenantID string) ([]*repo.IncidentRegulatoryProfile, error)
+	GetRegulatoryProfile(ctx context.Context, id, tenantID string) (*repo.IncidentRegulatoryProfile, error)
+	SaveRegulatoryProfile(ctx context.Context, profile *repo.IncidentRegulatoryProfile) error
+	DeleteRegulatoryProfile(ctx context.Context, id, tenantID string) error
+	ListApplicableObligations(ctx context.Context, tenantID, category string) ([]*repo.IncidentRegulatoryObligation, error)
+	ListIncidentNotifications(ctx context.Context, incidentID string) ([]*repo.IncidentNotification, error)
+	ListOverdueNotifications(ctx context.Context, tenantID string, now time.Time) ([]*repo.IncidentNotification, error)
+	ListNotificationsForDeadlineAlerts(ctx context.Context, now time.Time) ([]*repo.IncidentNotification, error)
+	MarkNotificationDeadlineAlerted(ctx context.Context, id string, overdue bool, alertedAt time.Time) error
+	GetIncidentNotification(ctx context.Context, id, incidentID string) (*repo.IncidentNotification, error)
+	AddIncidentNotifications(ctx context.Context, notifications []*repo.IncidentNotification) (int, error)
+	UpdateIncidentNotification(ctx context.Context, notification *repo.IncidentNotification) error
+	GetReview(ctx context.Context, incidentID string) (*repo.IncidentReview, error)
+	SaveReview(ctx context.Context, review *repo.IncidentReview) error
+	GetReviewFollowUp(ctx context.Context, id, reviewID string) (*repo.IncidentReviewFollowUp, error)
+	AddReviewFollowUp(ctx context.Context, followUp *repo.IncidentReviewFollowUp) error
+	UpdateReviewFollowUp(ctx context.Context, followUp *repo.IncidentReviewFollowUp) error
+	LinkReviewFollowUp(ctx context.Context, followUp *repo.IncidentReviewFollowUp) error
+	DeleteReviewFollowUp(ctx context.Context, id, reviewID string) error
+	ListEvidence(ctx context.Context, incidentID string) ([]*repo.IncidentEvidence, error)
+	GetEvidence(ctx context.Context, id, incidentID string) (*repo.IncidentEvidence, error)
+	IsEvidenceDocument(ctx context.Context, documentID string) (bool, error)
+	CreateEvidence(ctx context.Context, evidence *repo.IncidentEvidence, entry *repo.IncidentEvidenceCustody) error
+	UpdateEvidence(ctx context.Context, evidence *repo.IncidentEvidence, entry *repo.IncidentEvidenceCustody) error
+	AddEvidenceCustody(ctx context.Context, entry *repo.IncidentEvidenceCustody) error
+	GetEvidenceCustody(ctx context.Context, evidenceID string) ([]*repo.IncidentEvidenceCustody, error)
+	SetParent(ctx context.Context, incidentID, tenantID string, parentID *string) error
+	ListChildIncidents(ctx context.Context, parentID, tenantID string) ([]*repo.Incident, error)
+	ListMergedIncidents(ctx context.Context, primaryID, tenantID string) ([]*repo.Incident, error)
+	ListLinks(ctx context.Context, incidentID string) ([]*repo.IncidentLink, error)
+	GetLink(ctx context.Context, linkID string) (*repo.IncidentLink, error)
+	CreateLink(ctx context.Context, link *repo.IncidentLink) error
+	DeleteLink(ctx context.Context, linkID string) error
+	GetMerge(ctx context.Context, mergeID, tenantID string) (*repo.IncidentMerge, error)
+	ListMerges(ctx context.Context, incidentID string) ([]*repo.IncidentMerge, error)
+	MergeIncident(ctx context.Context, merge *repo.IncidentMerge, duplicate *repo.Incident, change *repo.IncidentStatusChange) error
+	RevertMerge(ctx context.Context, merge *repo.IncidentMerge, duplicate *repo.Incident, change *repo.IncidentStatusChange) error
+	ListMailboxes(ctx context.Context, tenantID string) ([]*repo.IncidentMailbox, error)
+	ListActiveMailboxes(ctx context.Context) ([]*repo.IncidentMailbox, error)
+	ListPlaintextPasswordMailboxes(ctx context.Context) ([]*repo.IncidentMailbox, error)
+	GetMailbox(ctx context.Context, id, tenantID string) (*repo.IncidentMailbox, error)
+	SaveMailbox(ctx context.Context, mailbox *repo.IncidentMailbox) error
+	DeleteMailbox(ctx context.Context, id, tenantID string) error
+	SetMailboxPollResult(ctx context.Context, id string, polledAt time.Time, lastError *string) error
+	ListProcessedMailUIDLs(ctx context.Context, mailboxID string, uidls []string) (map[string]bool, error)
+	FindIncidentByMailReference(ctx context.Context, tenantID string, messageIDs []string) (string, error)
+	ClaimMailMessage(ctx context.Context, message *repo.IncidentMailMessage) (bool, error)
+	FinishMailMessage(ctx context.Context, message *repo.IncidentMailMessage) error
+	ListMailMessages(ctx context.Context, mailboxID string, limit int) ([]*repo.IncidentMailMessage, error)
+	ListSLAPolicies(ctx context.Context, tenantID string) ([]*repo.IncidentSLAPolicy, error)
+	UpsertSLAPolicy(ctx context.Context, policy *repo.IncidentSLAPolicy) error
+	ListBusinessCalendars(ctx context.Context, tenantID string) ([]*repo.IncidentBusinessCalendar, error)
+	GetBusinessCalendar(ctx context.Context, id, tenantID string) (*repo.IncidentBusinessCalendar, error)
+	GetDefaultBusinessCalendar(ctx context.Context, tenantID string) (*repo.IncidentBusinessCalendar, error)
+	SaveBusinessCalendar(ctx context.Context, calendar *repo.IncidentBusinessCalendar) error
+	DeleteBusinessCalendar(ctx context.Context, id, tenantID string) error
+	ListIncidentsWithRunningSLA(ctx context.Context) ([]*repo.Incident, error)
+	RecordSLAEvent(ctx context.Context, event *repo.IncidentSLAEvent) (bool, error)
+	ListSLAEvents(ctx context.Context, tenantID string, filters map[string]interface{}, limit int) ([]*repo.IncidentSLAEvent, error)
+	ListAlertSources(ctx context.Context, tenantID string) ([]*repo.IncidentAlertSource, error)
+	GetAlertSource(ctx context.Context, id, tenantID string) (*repo.IncidentAlertSource, error)
+	GetAlertSourceByTokenHash(ctx context.Context, tokenHash string) (*repo.IncidentAlertSource, error)
+	SaveAlertSource(ctx context.Context, source *repo.IncidentAlertSource) error
+	DeleteAlertSource(ctx context.Context, id, tenantID string) error
+	FindOpenIncidentByCorrelation(ctx context.Context, tenantID, correlationKey string, since time.Time) (string, error)
+	AddAlert(ctx context.Context, alert *repo.IncidentAlert) error
+	ListAlerts(ctx context.Context, incidentID string) ([]*repo.IncidentAlert, error)
 	AddMetric(ctx context.Context, metric *repo.IncidentMetrics) error
 	GetMetrics(ctx context.Context, incidentID string) ([]*repo.IncidentMetrics, error)
 	GetIncidentMetrics(ctx context.Context, tenantID string) (*repo.IncidentMetricsSummary, error)
+	ListTrendIncidents(ctx context.Context, tenantID string, from, to time.Time, filters map[string]interface{}) ([]*repo.IncidentTrendRow, error)
 }
 
 // RiskRepoInterface - интерфейс для RiskRepo
@@ -0,0 +1,446 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// defaultRiskAcceptanceRules applies until a tenant configures its own approvers.
+// A nil ApproverRole means any user with the risks.accept permission may approve.
+var defaultRiskAcceptanceRules = []repo.RiskAcceptanceRule{
+	{LevelLabel: dto.RiskLevelLabelLow, MaxValidityDays: 365},
+	{LevelLabel: dto.RiskLevelLabelMedium, MaxValidityDays: 365},
+	{LevelLabel: dto.RiskLevelLabelHigh, MaxValidityDays: 180},
+	{LevelLabel: dto.RiskLevelLabelCritical, ApproverRole: riskStringPtr("CISO"), MaxValidityDays: 90},
+}
+
+// Risk Acceptance Rules methods
+
+// GetAcceptanceRules returns the approver rule for every risk level, tenant overrides first
+func (s *RiskService) GetAcceptanceRules(ctx context.Context, tenantID string) ([]repo.RiskAcceptanceRule, error) {
+	configured, err := s.riskRepo.ListAcceptanceRules(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mergeAcceptanceRules(tenantID, configured), nil
+}
+
+// mergeAcceptanceRules fills the levels a tenant has not configured with the defaults
+func mergeAcceptanceRules(tenantID string, configured []repo.RiskAcceptanceRule) []repo.RiskAcceptanceRule {
+	rules := make([]repo.RiskAcceptanceRule, 0, len(defaultRiskAcceptanceRules))
+	for _, def := range defaultRiskAcceptanceRules {
+		rule := def
+		rule.TenantID = tenantID
+		for _, c := range configured {
+			if c.LevelLabel == def.LevelLabel {
+				rule = c
+				break
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (s *RiskService) SetAcceptanceRule(ctx context.Context, tenantID string, req dto.RiskAcceptanceRuleRequest, updatedBy string) error {
+	approverRole := req.ApproverRole
+	if approverRole != nil {
+		trimmed := strings.TrimSpace(*approverRole)
+		approverRole = &trimmed
+		if trimmed == "" {
+			approverRole = nil
+		}
+	}
+
+	rule := repo.RiskAcceptanceRule{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		LevelLabel:      req.LevelLabel,
+		ApproverRole:    approverRole,
+		MaxValidityDays: req.MaxValidityDays,
+	}
+	if err := s.riskRepo.UpsertAcceptanceRule(ctx, rule); err != nil {
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_acceptance_rule", "risk", nil, rule)
+	return nil
+}
+
+func (s *RiskService) getAcceptanceRule(ctx context.Context, tenantID, levelLabel string) (*repo.RiskAcceptanceRule, error) {
+	rules, err := s.GetAcceptanceRules(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].LevelLabel == levelLabel {
+			return &rules[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no acceptance rule for risk level %s", levelLabel)
+}
+
+// Risk Acceptances methods
+
+func (s *RiskService) GetAcceptances(ctx context.Context, riskID, tenantID string) ([]repo.RiskAcceptance, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.riskRepo.ListAcceptances(ctx, riskID)
+}
+
+// RequestAcceptance records a justification and expiry for a risk with the "accept"
+// strategy. The approver role is resolved from the tenant rules for the risk level.
+func (s *RiskService) RequestAcceptance(ctx context.Context, riskID, tenantID string, req dto.RiskAcceptanceRequest, requestedBy string) (*repo.RiskAcceptance, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if risk.Strategy == nil || *risk.Strategy != dto.RiskStrategyAccept {
+		return nil, ErrRiskStrategyNotAccept
+	}
+
+	pending, err := s.riskRepo.ListAcceptancesByStatus(ctx, riskID, dto.RiskAcceptanceStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		return nil, ErrRiskAcceptancePending
+	}
+
+	level, levelLabel := riskLevelWithLabel(risk)
+	rule, err := s.getAcceptanceRule(ctx, tenantID, levelLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := parseRiskDate("expires_at", &req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAcceptanceExpiry(*expiresAt, riskToday(), levelLabel, rule); err != nil {
+		return nil, err
+	}
+
+	acceptance := repo.RiskAcceptance{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		RiskID:        riskID,
+		Justification: req.Justification,
+		RiskLevel:     level,
+		LevelLabel:    levelLabel,
+		ApproverRole:  rule.ApproverRole,
+		Status:        dto.RiskAcceptanceStatusPending,
+		RequestedBy:   requestedBy,
+		ExpiresAt:     *expiresAt,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.riskRepo.CreateAcceptance(ctx, acceptance); err != nil {
+		return nil, err
+	}
+
+	s.recordAcceptanceHistory(ctx, risk, "", dto.RiskAcceptanceStatusPending, req.Justification, requestedBy)
+	s.auditRepo.LogAction(ctx, tenantID, requestedBy, "request_acceptance", "risk", &riskID, map[string]interface{}{
+		"acceptance_id": acceptance.ID,
+		"level_label":   levelLabel,
+		"approver_role": rule.ApproverRole,
+		"expires_at":    req.ExpiresAt,
+	})
+
+	return s.riskRepo.GetAcceptance(ctx, acceptance.ID)
+}
+
+// ApproveAcceptance approves a pending request and moves the risk to "accepted".
+// A previously approved acceptance of the same risk is superseded.
+func (s *RiskService) ApproveAcceptance(ctx context.Context, riskID, acceptanceID, tenantID string, req dto.RiskAcceptanceDecisionRequest, approverID string) (*repo.RiskAcceptance, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	acceptance, err := s.getRiskAcceptance(ctx, riskID, acceptanceID)
+	if err != nil {
+		return nil, err
+	}
+	if acceptance.Status != dto.RiskAcceptanceStatusPending {
+		return nil, ErrRiskAcceptanceNotPending
+	}
+	if acceptance.RequestedBy == approverID {
+		return nil, ErrRiskAcceptanceSelfApproval
+	}
+	if acceptance.ExpiresAt.Before(riskToday()) {
+		return nil, NewValidationError("expires_at", "acceptance expiry date has already passed")
+	}
+	if acceptance.ApproverRole != nil {
+		roles, err := s.userRepo.GetUserRoles(ctx, approverID)
+		if err != nil {
+			return nil, err
+		}
+		if !hasRole(roles, *acceptance.ApproverRole) {
+			return nil, ErrRiskAcceptanceApproverRole
+		}
+	}
+
+	approved, err := s.riskRepo.ListAcceptancesByStatus(ctx, riskID, dto.RiskAcceptanceStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	for _, previous := range approved {
+		previous.Status = dto.RiskAcceptanceStatusRevoked
+		if err := s.riskRepo.UpdateAcceptanceDecision(ctx, previous); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	acceptance.Status = dto.RiskAcceptanceStatusApproved
+	acceptance.ApproverUserID = &approverID
+	acceptance.DecisionComment = req.Comment
+	acceptance.DecidedAt = &now
+	if err := s.riskRepo.UpdateAcceptanceDecision(ctx, *acceptance); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, approverID, "approve_acceptance", "risk", &riskID, map[string]interface{}{
+		"acceptance_id": acceptance.ID,
+		"expires_at":    acceptance.ExpiresAt.Format("2006-01-02"),
+		"comment":       req.Comment,
+	})
+
+	reason := fmt.Sprintf("Принятие риска утверждено до %s", acceptance.ExpiresAt.Format("02.01.2006"))
+	if risk.Status != dto.RiskStatusAccepted {
+		if err := s.changeRiskStatus(ctx, risk, dto.RiskStatusAccepted, reason, approverID); err != nil {
+			return nil, err
+		}
+	} else {
+		s.recordAcceptanceHistory(ctx, risk, dto.RiskAcceptanceStatusPending, dto.RiskAcceptanceStatusApproved, reason, approverID)
+	}
+
+	return s.riskRepo.GetAcceptance(ctx, acceptance.ID)
+}
+
+func (s *RiskService) RejectAcceptance(ctx context.Context, riskID, acceptanceID, tenantID string, req dto.RiskAcceptanceDecisionRequest, approverID string) (*repo.RiskAcceptance, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	acceptance, err := s.getRiskAcceptance(ctx, riskID, acceptanceID)
+	if err != nil {
+		return nil, err
+	}
+	if acceptance.Status != dto.RiskAcceptanceStatusPending {
+		return nil, ErrRiskAcceptanceNotPending
+	}
+	if acceptance.ApproverRole != nil {
+		roles, err := s.userRepo.GetUserRoles(ctx, approverID)
+		if err != nil {
+			return nil, err
+		}
+		if !hasRole(roles, *acceptance.ApproverRole) {
+			return nil, ErrRiskAcceptanceApproverRole
+		}
+	}
+
+	now := time.Now()
+	acceptance.Status = dto.RiskAcceptanceStatusRejected
+	acceptance.ApproverUserID = &approverID
+	acceptance.DecisionComment = req.Comment
+	acceptance.DecidedAt = &now
+	if err := s.riskRepo.UpdateAcceptanceDecision(ctx, *acceptance); err != nil {
+		return nil, err
+	}
+
+	reason := "Принятие риска отклонено"
+	if !isBlank(req.Comment) {
+		reason += ": " + *req.Comment
+	}
+	s.recordAcceptanceHistory(ctx, risk, dto.RiskAcceptanceStatusPending, dto.RiskAcceptanceStatusRejected, reason, approverID)
+	s.auditRepo.LogAction(ctx, tenantID, approverID, "reject_acceptance", "risk", &riskID, map[string]interface{}{
+		"acceptance_id": acceptance.ID,
+		"comment":       req.Comment,
+	})
+
+	return s.riskRepo.GetAcceptance(ctx, acceptance.ID)
+}
+
+// RevokeAcceptance withdraws a pending or approved acceptance. Revoking an approved
+// acceptance sends an accepted risk back to analysis.
+func (s *RiskService) RevokeAcceptance(ctx context.Context, riskID, acceptanceID, tenantID string, req dto.RiskAcceptanceDecisionRequest, revokedBy string) (*repo.RiskAcceptance, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	acceptance, err := s.getRiskAcceptance(ctx, riskID, acceptanceID)
+	if err != nil {
+		return nil, err
+	}
+	if acceptance.Status != dto.RiskAcceptanceStatusPending && acceptance.Status != dto.RiskAcceptanceStatusApproved {
+		return nil, ErrRiskAcceptanceNotApproved
+	}
+
+	oldStatus := acceptance.Status
+	acceptance.Status = dto.RiskAcceptanceStatusRevoked
+	if req.Comment != nil {
+		acceptance.DecisionComment = req.Comment
+	}
+	if err := s.riskRepo.UpdateAcceptanceDecision(ctx, *acceptance); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, revokedBy, "revoke_acceptance", "risk", &riskID, map[string]interface{}{
+		"acceptance_id": acceptance.ID,
+		"comment":       req.Comment,
+	})
+
+	reason := "Принятие риска отозвано"
+	if !isBlank(req.Comment) {
+		reason += ": " + *req.Comment
+	}
+	if oldStatus == dto.RiskAcceptanceStatusApproved && risk.Status == dto.RiskStatusAccepted {
+		if err := s.changeRiskStatus(ctx, risk, dto.RiskStatusInAnalysis, reason, revokedBy); err != nil {
+			return nil, err
+		}
+	} else {
+		s.recordAcceptanceHistory(ctx, risk, oldStatus, dto.RiskAcceptanceStatusRevoked, reason, revokedBy)
+	}
+
+	return s.riskRepo.GetAcceptance(ctx, acceptance.ID)
+}
+
+// ProcessExpiredAcceptances marks approved acceptances past their expiry date as expired
+// and sends the affected risks back to analysis for re-review.
+func (s *RiskService) ProcessExpiredAcceptances(ctx context.Context) (int, error) {
+	expired, err := s.riskRepo.ListExpiredAcceptances(ctx, riskToday())
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, acceptance := range expired {
+		acceptance.Status = dto.RiskAcceptanceStatusExpired
+		if err := s.riskRepo.UpdateAcceptanceDecision(ctx, acceptance); err != nil {
+			log.Printf("ERROR: RiskService.ProcessExpiredAcceptances update %s: %v", acceptance.ID, err)
+			continue
+		}
+		processed++
+
+		// Истечение срока - системное событие: без автора в журнале аудита и истории риска
+		const changedBy = ""
+
+		s.auditRepo.LogAction(ctx, acceptance.TenantID, changedBy, "acceptance_expired", "risk", &acceptance.RiskID, map[string]interface{}{
+			"acceptance_id": acceptance.ID,
+			"expires_at":    acceptance.ExpiresAt.Format("2006-01-02"),
+		})
+
+		risk, err := s.riskRepo.GetByIDWithTenant(ctx, acceptance.RiskID, acceptance.TenantID)
+		if err != nil || risk == nil {
+			log.Printf("ERROR: RiskService.ProcessExpiredAcceptances get risk %s: %v", acceptance.RiskID, err)
+			continue
+		}
+
+		reason := fmt.Sprintf("Срок принятия риска истёк %s, требуется повторная оценка", acceptance.ExpiresAt.Format("02.01.2006"))
+		if risk.Status == dto.RiskStatusAccepted {
+			if err := s.changeRiskStatus(ctx, risk, dto.RiskStatusInAnalysis, reason, changedBy); err != nil {
+				log.Printf("ERROR: RiskService.ProcessExpiredAcceptances status %s: %v", risk.ID, err)
+			}
+		} else {
+			s.recordAcceptanceHistory(ctx, risk, dto.RiskAcceptanceStatusApproved, dto.RiskAcceptanceStatusExpired, reason, changedBy)
+		}
+	}
+
+	return processed, nil
+}
+
+// getActiveAcceptance returns the approved, not yet expired acceptance of a risk
+func (s *RiskService) getActiveAcceptance(ctx context.Context, riskID string) (*repo.RiskAcceptance, error) {
+	approved, err := s.riskRepo.ListAcceptancesByStatus(ctx, riskID, dto.RiskAcceptanceStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	today := riskToday()
+	for i := range approved {
+		if !approved[i].ExpiresAt.Before(today) {
+			return &approved[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *RiskService) getRiskAcceptance(ctx context.Context, riskID, acceptanceID string) (*repo.RiskAcceptance, error) {
+	acceptance, err := s.riskRepo.GetAcceptance(ctx, acceptanceID)
+	if err != nil {
+		return nil, err
+	}
+	if acceptance == nil || acceptance.RiskID != riskID {
+		return nil, ErrRiskAcceptanceNotFound
+	}
+	return acceptance, nil
+}
+
+// recordAcceptanceHistory writes an "acceptance" entry to risk_history when the risk
+// status itself does not change
+func (s *RiskService) recordAcceptanceHistory(ctx context.Context, risk *repo.Risk, oldValue, newValue, reason, changedBy string) {
+	var old *string
+	if oldValue != "" {
+		old = &oldValue
+	}
+	if err := s.riskRepo.AddHistory(ctx, repo.RiskHistory{
+		ID:           uuid.New().String(),
+		RiskID:       risk.ID,
+		FieldChanged: "acceptance",
+		OldValue:     old,
+		NewValue:     &newValue,
+		ChangeReason: &reason,
+		ChangedBy:    changedBy,
+		ChangedAt:    time.Now(),
+	}); err != nil {
+		log.Printf("WARNING: failed to record risk history for risk %s: %v", risk.ID, err)
+	}
+}
+
+// checkAcceptanceExpiry requires the expiry to lie after today and within the validity
+// the rule allows for the risk level
+func checkAcceptanceExpiry(expiresAt, today time.Time, levelLabel string, rule *repo.RiskAcceptanceRule) error {
+	if !expiresAt.After(today) {
+		return NewValidationError("expires_at", "must be in the future")
+	}
+	if expiresAt.After(today.AddDate(0, 0, rule.MaxValidityDays)) {
+		return NewValidationError("expires_at", fmt.Sprintf("acceptance of a %s risk can be valid for at most %d days", levelLabel, rule.MaxValidityDays))
+	}
+	return nil
+}
+
+// riskLevelWithLabel returns the current level of the risk and its label
+func riskLevelWithLabel(risk *repo.Risk) (*int, string) {
+	if risk.Likelihood != nil && risk.Impact != nil {
+		level, label := dto.CalculateRiskLevel(*risk.Likelihood, *risk.Impact)
+		return &level, label
+	}
+	if risk.Level != nil {
+		return risk.Level, dto.RiskLevelLabelFor(*risk.Level)
+	}
+	return nil, dto.RiskLevelLabelLow
+}
+
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// riskToday returns the current UTC date used for expiry and review due dates
+func riskToday() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
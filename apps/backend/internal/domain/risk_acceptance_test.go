@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAcceptanceRules(t *testing.T) {
+	configured := []repo.RiskAcceptanceRule{
+		{ID: "rule-1", TenantID: "tenant-1", LevelLabel: dto.RiskLevelLabelHigh, ApproverRole: riskStringPtr("Risk Manager"), MaxValidityDays: 30},
+	}
+
+	rules := mergeAcceptanceRules("tenant-1", configured)
+	require.Len(t, rules, len(defaultRiskAcceptanceRules))
+
+	byLevel := map[string]repo.RiskAcceptanceRule{}
+	for _, rule := range rules {
+		assert.Equal(t, "tenant-1", rule.TenantID)
+		byLevel[rule.LevelLabel] = rule
+	}
+
+	assert.Equal(t, "rule-1", byLevel[dto.RiskLevelLabelHigh].ID, "a tenant rule overrides the default")
+	assert.Equal(t, 30, byLevel[dto.RiskLevelLabelHigh].MaxValidityDays)
+	assert.Nil(t, byLevel[dto.RiskLevelLabelLow].ApproverRole, "low risks may be accepted by anyone with the permission")
+	assert.Equal(t, 365, byLevel[dto.RiskLevelLabelLow].MaxValidityDays)
+	require.NotNil(t, byLevel[dto.RiskLevelLabelCritical].ApproverRole)
+	assert.Equal(t, "CISO", *byLevel[dto.RiskLevelLabelCritical].ApproverRole)
+	assert.Equal(t, 90, byLevel[dto.RiskLevelLabelCritical].MaxValidityDays)
+
+	assert.Empty(t, defaultRiskAcceptanceRules[0].TenantID, "merging does not modify the defaults")
+}
+
+func TestRiskLevelWithLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		risk      repo.Risk
+		wantLevel *int
+		wantLabel string
+	}{
+		{"low", repo.Risk{Likelihood: intPtr(1), Impact: intPtr(2)}, intPtr(2), dto.RiskLevelLabelLow},
+		{"medium", repo.Risk{Likelihood: intPtr(2), Impact: intPtr(2)}, intPtr(4), dto.RiskLevelLabelMedium},
+		{"high", repo.Risk{Likelihood: intPtr(3), Impact: intPtr(2)}, intPtr(6), dto.RiskLevelLabelHigh},
+		{"critical", repo.Risk{Likelihood: intPtr(4), Impact: intPtr(2)}, intPtr(8), dto.RiskLevelLabelCritical},
+		{"likelihood and impact win over a stale level", repo.Risk{Likelihood: intPtr(1), Impact: intPtr(1), Level: intPtr(8)}, intPtr(1), dto.RiskLevelLabelLow},
+		{"stored level only", repo.Risk{Level: intPtr(7)}, intPtr(7), dto.RiskLevelLabelCritical},
+		{"not assessed", repo.Risk{}, nil, dto.RiskLevelLabelLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, label := riskLevelWithLabel(&tt.risk)
+			assert.Equal(t, tt.wantLevel, level)
+			assert.Equal(t, tt.wantLabel, label)
+		})
+	}
+}
+
+func TestCheckAcceptanceExpiry(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	rule := &repo.RiskAcceptanceRule{LevelLabel: dto.RiskLevelLabelCritical, MaxValidityDays: 90}
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		wantErr   bool
+	}{
+		{"tomorrow", today.AddDate(0, 0, 1), false},
+		{"last allowed day", today.AddDate(0, 0, 90), false},
+		{"today", today, true},
+		{"in the past", today.AddDate(0, 0, -1), true},
+		{"beyond the rule validity", today.AddDate(0, 0, 91), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAcceptanceExpiry(tt.expiresAt, today, dto.RiskLevelLabelCritical, rule)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "expires_at", validationErr.Field)
+		})
+	}
+}
+
+func TestHasRole(t *testing.T) {
+	roles := []string{"Analyst", "CISO"}
+
+	assert.True(t, hasRole(roles, "CISO"))
+	assert.True(t, hasRole(roles, "ciso"), "roles match case-insensitively")
+	assert.False(t, hasRole(roles, "Auditor"))
+	assert.False(t, hasRole(nil, "CISO"))
+}
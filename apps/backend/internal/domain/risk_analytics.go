@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+)
+
+// riskScaleMax is the upper bound of the likelihood and impact scales
+const riskScaleMax = 4
+
+// Risk Analytics methods
+
+// GetHeatMap returns the full likelihood x impact grid with the number of open risks per cell
+func (s *RiskService) GetHeatMap(ctx context.Context, tenantID string, filter dto.RiskAnalyticsFilter) (*dto.RiskHeatMapResponse, error) {
+	counts, err := s.riskRepo.GetHeatMapCounts(ctx, tenantID, riskAnalyticsFilters(filter))
+	if err != nil {
+		return nil, err
+	}
+	return buildRiskHeatMap(counts), nil
+}
+
+// buildRiskHeatMap spreads the per-cell counts over the full grid, including empty cells
+func buildRiskHeatMap(counts []repo.RiskHeatMapCell) *dto.RiskHeatMapResponse {
+	byCell := make(map[[2]int]int, len(counts))
+	for _, cell := range counts {
+		byCell[[2]int{cell.Likelihood, cell.Impact}] += cell.Count
+	}
+
+	response := &dto.RiskHeatMapResponse{Cells: make([]dto.RiskHeatMapCellResponse, 0, riskScaleMax*riskScaleMax)}
+	for likelihood := 1; likelihood <= riskScaleMax; likelihood++ {
+		for impact := 1; impact <= riskScaleMax; impact++ {
+			level, label := dto.CalculateRiskLevel(likelihood, impact)
+			count := byCell[[2]int{likelihood, impact}]
+			response.Cells = append(response.Cells, dto.RiskHeatMapCellResponse{
+				Likelihood: likelihood,
+				Impact:     impact,
+				Level:      level,
+				LevelLabel: label,
+				Count:      count,
+			})
+			response.Total += count
+		}
+	}
+	return response
+}
+
+// GetAnalyticsSummary returns risk counts by status and level for dashboards
+func (s *RiskService) GetAnalyticsSummary(ctx context.Context, tenantID string, filter dto.RiskAnalyticsFilter) (*dto.RiskAnalyticsSummaryResponse, error) {
+	filters := riskAnalyticsFilters(filter)
+	counts, err := s.riskRepo.GetStatusLevelCounts(ctx, tenantID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &dto.RiskAnalyticsSummaryResponse{ByStatus: map[string]int{}}
+	for _, count := range counts {
+		summary.Total += count.Count
+		summary.ByStatus[count.Status] += count.Count
+		if count.Status == dto.RiskStatusClosed {
+			continue
+		}
+		summary.Open += count.Count
+		if count.Level > 0 {
+			addRiskLevelCount(&summary.ByLevel, count.Level, count.Count)
+		}
+	}
+
+	overdue, err := s.riskRepo.ListOverdueTreatments(ctx, tenantID, filters, riskToday())
+	if err != nil {
+		return nil, err
+	}
+	summary.OverdueTreatments = len(overdue)
+
+	return summary, nil
+}
+
+// GetTopRisks returns the open risks with the highest level
+func (s *RiskService) GetTopRisks(ctx context.Context, tenantID string, filter dto.RiskAnalyticsFilter, limit int) ([]repo.Risk, error) {
+	return s.riskRepo.ListTopRisks(ctx, tenantID, riskAnalyticsFilters(filter), limit)
+}
+
+// GetOverdueTreatments returns open treatment tasks past their due date, oldest first
+func (s *RiskService) GetOverdueTreatments(ctx context.Context, tenantID string, filter dto.RiskAnalyticsFilter) ([]dto.RiskOverdueTreatmentResponse, error) {
+	today := riskToday()
+	overdue, err := s.riskRepo.ListOverdueTreatments(ctx, tenantID, riskAnalyticsFilters(filter), today)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.RiskOverdueTreatmentResponse, 0, len(overdue))
+	for _, o := range overdue {
+		responses = append(responses, dto.RiskOverdueTreatmentResponse{
+			TaskID:         o.TaskID,
+			TaskTitle:      o.TaskTitle,
+			TaskStatus:     o.TaskStatus,
+			DueDate:        o.DueDate,
+			DaysOverdue:    int(today.Sub(o.DueDate).Hours() / 24),
+			AssigneeUserID: o.AssigneeUserID,
+			AssigneeName:   o.AssigneeName,
+			PlanID:         o.PlanID,
+			PlanTitle:      o.PlanTitle,
+			RiskID:         o.RiskID,
+			RiskTitle:      o.RiskTitle,
+			RiskLevel:      o.RiskLevel,
+		})
+	}
+	return responses, nil
+}
+
+// GetLevelTrend returns the distribution of open risks by level at the end of each of the
+// last `periods` weeks or months. Past states are reconstructed from the current values by
+// rolling back the "level" and "status" entries of risk_history.
+func (s *RiskService) GetLevelTrend(ctx context.Context, tenantID string, filter dto.RiskAnalyticsFilter, period string, periods int) ([]dto.RiskLevelTrendPoint, error) {
+	now := time.Now().UTC()
+	points := riskTrendPeriods(now, period, periods)
+	if len(points) == 0 {
+		return points, nil
+	}
+
+	filters := riskAnalyticsFilters(filter)
+	risks, err := s.riskRepo.ListRisksForTrend(ctx, tenantID, filters, now)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.riskRepo.ListHistorySince(ctx, tenantID, []string{"level", "status"}, points[0].PeriodStart)
+	if err != nil {
+		return nil, err
+	}
+
+	countRiskLevelTrend(points, risks, history)
+	return points, nil
+}
+
+// countRiskLevelTrend adds every risk to the points it was open in. The history must be
+// ordered newest first; its entries are rolled back from the current level and status
+// until the end of each period is reached.
+func countRiskLevelTrend(points []dto.RiskLevelTrendPoint, risks []repo.Risk, history []repo.RiskHistory) {
+	historyByRisk := make(map[string][]repo.RiskHistory)
+	for _, entry := range history {
+		historyByRisk[entry.RiskID] = append(historyByRisk[entry.RiskID], entry)
+	}
+
+	for _, risk := range risks {
+		level, status := risk.Level, risk.Status
+		entries := historyByRisk[risk.ID] // newest first
+		next := 0
+
+		for i := len(points) - 1; i >= 0; i-- {
+			snapshotAt := points[i].PeriodEnd
+			for next < len(entries) && entries[next].ChangedAt.After(snapshotAt) {
+				switch entries[next].FieldChanged {
+				case "level":
+					level = parseHistoryInt(entries[next].OldValue)
+				case "status":
+					if entries[next].OldValue != nil {
+						status = *entries[next].OldValue
+					}
+				}
+				next++
+			}
+
+			if risk.CreatedAt.After(snapshotAt) || status == dto.RiskStatusClosed || level == nil {
+				continue
+			}
+			addRiskLevelCount(&points[i].RiskLevelCounts, *level, 1)
+		}
+	}
+}
+
+// riskTrendPeriods returns consecutive week or month periods ending with the current one.
+// The end of the current period is capped at now.
+func riskTrendPeriods(now time.Time, period string, periods int) []dto.RiskLevelTrendPoint {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var currentStart time.Time
+	step := func(t time.Time, n int) time.Time { return t.AddDate(0, n, 0) }
+	if period == dto.RiskTrendPeriodWeek {
+		offset := (int(today.Weekday()) + 6) % 7 // неделя начинается с понедельника
+		currentStart = today.AddDate(0, 0, -offset)
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) }
+	} else {
+		currentStart = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	points := make([]dto.RiskLevelTrendPoint, 0, periods)
+	for i := periods - 1; i >= 0; i-- {
+		start := step(currentStart, -i)
+		end := step(start, 1).Add(-time.Nanosecond)
+		if end.After(now) {
+			end = now
+		}
+		points = append(points, dto.RiskLevelTrendPoint{PeriodStart: start, PeriodEnd: end})
+	}
+	return points
+}
+
+func addRiskLevelCount(counts *dto.RiskLevelCounts, level, n int) {
+	switch dto.RiskLevelLabelFor(level) {
+	case dto.RiskLevelLabelLow:
+		counts.Low += n
+	case dto.RiskLevelLabelMedium:
+		counts.Medium += n
+	case dto.RiskLevelLabelHigh:
+		counts.High += n
+	default:
+		counts.Critical += n
+	}
+	counts.Total += n
+}
+
+func riskAnalyticsFilters(filter dto.RiskAnalyticsFilter) map[string]string {
+	return map[string]string{
+		"category":      filter.Category,
+		"owner_user_id": filter.OwnerUserID,
+		"asset_id":      filter.AssetID,
+	}
+}
+
+func parseHistoryInt(value *string) *int {
+	if value == nil {
+		return nil
+	}
+	parsed, err := strconv.Atoi(*value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
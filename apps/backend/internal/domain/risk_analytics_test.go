@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRiskHeatMap(t *testing.T) {
+	heatMap := buildRiskHeatMap([]repo.RiskHeatMapCell{
+		{Likelihood: 4, Impact: 2, Count: 3},
+		{Likelihood: 1, Impact: 1, Count: 2},
+	})
+
+	require.Len(t, heatMap.Cells, riskScaleMax*riskScaleMax, "empty cells are part of the grid")
+	assert.Equal(t, 5, heatMap.Total)
+
+	first := heatMap.Cells[0]
+	assert.Equal(t, dto.RiskHeatMapCellResponse{Likelihood: 1, Impact: 1, Level: 1, LevelLabel: dto.RiskLevelLabelLow, Count: 2}, first)
+	critical := heatMap.Cells[(4-1)*riskScaleMax+(2-1)]
+	assert.Equal(t, dto.RiskHeatMapCellResponse{Likelihood: 4, Impact: 2, Level: 8, LevelLabel: dto.RiskLevelLabelCritical, Count: 3}, critical)
+	assert.Zero(t, heatMap.Cells[riskScaleMax*riskScaleMax-1].Count)
+}
+
+func TestRiskTrendPeriods(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC) // среда
+
+	months := riskTrendPeriods(now, dto.RiskTrendPeriodMonth, 3)
+	require.Len(t, months, 3)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), months[0].PeriodStart)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond), months[0].PeriodEnd)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), months[1].PeriodStart)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), months[2].PeriodStart)
+	assert.Equal(t, now, months[2].PeriodEnd, "the current period ends now")
+
+	weeks := riskTrendPeriods(now, dto.RiskTrendPeriodWeek, 2)
+	require.Len(t, weeks, 2)
+	assert.Equal(t, time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), weeks[0].PeriodStart, "weeks start on Monday")
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), weeks[1].PeriodStart)
+	assert.Equal(t, weeks[1].PeriodStart.Add(-time.Nanosecond), weeks[0].PeriodEnd)
+
+	sunday := riskTrendPeriods(time.Date(2026, 3, 22, 8, 0, 0, 0, time.UTC), dto.RiskTrendPeriodWeek, 1)
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), sunday[0].PeriodStart, "Sunday belongs to the week started on Monday")
+
+	assert.Empty(t, riskTrendPeriods(now, dto.RiskTrendPeriodMonth, 0))
+}
+
+func TestCountRiskLevelTrend(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	at := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 10, 0, 0, 0, time.UTC) }
+	change := func(riskID, field, oldValue string, changedAt time.Time) repo.RiskHistory {
+		return repo.RiskHistory{RiskID: riskID, FieldChanged: field, OldValue: riskStringPtr(oldValue), ChangedAt: changedAt}
+	}
+
+	risks := []repo.Risk{
+		{ID: "escalated", Level: intPtr(8), Status: dto.RiskStatusInTreatment, CreatedAt: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "closed-in-march", Level: intPtr(6), Status: dto.RiskStatusClosed, CreatedAt: at(time.February, 15)},
+		{ID: "closed-long-ago", Level: intPtr(4), Status: dto.RiskStatusClosed, CreatedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "not-assessed", Status: dto.RiskStatusNew, CreatedAt: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "created-today", Level: intPtr(1), Status: dto.RiskStatusNew, CreatedAt: now.Add(-time.Hour)},
+	}
+	history := []repo.RiskHistory{ // newest first, as returned by ListHistorySince
+		change("escalated", "level", "4", at(time.March, 5)),
+		change("closed-in-march", "status", dto.RiskStatusInTreatment, at(time.March, 3)),
+		change("escalated", "level", "2", at(time.February, 10)),
+	}
+
+	points := riskTrendPeriods(now, dto.RiskTrendPeriodMonth, 3)
+	countRiskLevelTrend(points, risks, history)
+
+	assert.Equal(t, dto.RiskLevelCounts{Low: 1, Total: 1}, points[0].RiskLevelCounts, "January: escalated was low")
+	assert.Equal(t, dto.RiskLevelCounts{Medium: 1, High: 1, Total: 2}, points[1].RiskLevelCounts, "February: escalated medium, closed-in-march still open")
+	assert.Equal(t, dto.RiskLevelCounts{Low: 1, Critical: 1, Total: 2}, points[2].RiskLevelCounts, "March: current state")
+}
+
+func TestCountRiskLevelTrend_UnassessedInThePast(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	risks := []repo.Risk{{ID: "risk-1", Level: intPtr(3), Status: dto.RiskStatusInAnalysis, CreatedAt: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)}}
+	history := []repo.RiskHistory{{RiskID: "risk-1", FieldChanged: "level", ChangedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)}}
+
+	points := riskTrendPeriods(now, dto.RiskTrendPeriodMonth, 2)
+	countRiskLevelTrend(points, risks, history)
+
+	assert.Zero(t, points[0].Total, "a risk without a level before its first assessment is not counted")
+	assert.Equal(t, dto.RiskLevelCounts{Medium: 1, Total: 1}, points[1].RiskLevelCounts)
+}
+
+func TestParseHistoryInt(t *testing.T) {
+	assert.Equal(t, intPtr(6), parseHistoryInt(riskStringPtr("6")))
+	assert.Nil(t, parseHistoryInt(riskStringPtr("3x2")))
+	assert.Nil(t, parseHistoryInt(nil))
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// Risk Appetite methods
+
+func (s *RiskService) GetAppetiteStatements(ctx context.Context, tenantID string) ([]repo.RiskAppetiteStatement, error) {
+	return s.riskRepo.ListAppetiteStatements(ctx, tenantID)
+}
+
+// SetAppetiteStatement creates or replaces the appetite statement for a category
+func (s *RiskService) SetAppetiteStatement(ctx context.Context, tenantID string, req dto.RiskAppetiteStatementRequest, updatedBy string) (*repo.RiskAppetiteStatement, error) {
+	if req.AppetiteLevel > req.ToleranceLevel {
+		return nil, NewValidationError("tolerance_level", "tolerance level must not be lower than appetite level")
+	}
+
+	var category *string
+	if !isBlank(req.Category) {
+		trimmed := strings.TrimSpace(*req.Category)
+		category = &trimmed
+	}
+
+	statement, err := s.riskRepo.UpsertAppetiteStatement(ctx, repo.RiskAppetiteStatement{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		Category:       category,
+		Statement:      req.Statement,
+		AppetiteLevel:  req.AppetiteLevel,
+		ToleranceLevel: req.ToleranceLevel,
+		UpdatedBy:      &updatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "set_risk_appetite", "risk", nil, map[string]interface{}{
+		"statement_id":    statement.ID,
+		"category":        category,
+		"appetite_level":  statement.AppetiteLevel,
+		"tolerance_level": statement.ToleranceLevel,
+	})
+
+	return statement, nil
+}
+
+func (s *RiskService) DeleteAppetiteStatement(ctx context.Context, statementID, tenantID, deletedBy string) error {
+	statement, err := s.riskRepo.GetAppetiteStatement(ctx, statementID, tenantID)
+	if err != nil {
+		return err
+	}
+	if statement == nil {
+		return ErrRiskAppetiteNotFound
+	}
+
+	if err := s.riskRepo.DeleteAppetiteStatement(ctx, tenantID, statementID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRiskAppetiteNotFound
+		}
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, deletedBy, "delete_risk_appetite", "risk", nil, map[string]interface{}{
+		"statement_id": statementID,
+		"category":     statement.Category,
+	})
+	return nil
+}
+
+// GetOutOfAppetiteReport returns open risks exceeding tolerance (and, optionally, appetite)
+// together with whether they are covered by a treatment plan or an approved acceptance
+func (s *RiskService) GetOutOfAppetiteReport(ctx context.Context, tenantID string, includeAboveAppetite bool) ([]repo.Risk, map[string]repo.RiskResponseCoverage, error) {
+	above, err := s.riskRepo.ListRisksAboveAppetite(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var risks []repo.Risk
+	ids := make([]string, 0, len(above))
+	for _, risk := range above {
+		if !includeAboveAppetite && RiskAppetiteStatus(&risk) != dto.RiskAppetiteStatusOutOfTolerance {
+			continue
+		}
+		risks = append(risks, risk)
+		ids = append(ids, risk.ID)
+	}
+
+	coverage, err := s.riskRepo.GetRiskResponseCoverage(ctx, ids, riskToday())
+	if err != nil {
+		return nil, nil, err
+	}
+	return risks, coverage, nil
+}
+
+// RiskAppetiteStatus compares the risk level with the appetite of its category; empty if no statement applies
+func RiskAppetiteStatus(risk *repo.Risk) string {
+	if risk.Level == nil || risk.AppetiteLevel == nil || risk.ToleranceLevel == nil {
+		return ""
+	}
+	switch {
+	case *risk.Level > *risk.ToleranceLevel:
+		return dto.RiskAppetiteStatusOutOfTolerance
+	case *risk.Level > *risk.AppetiteLevel:
+		return dto.RiskAppetiteStatusAboveAppetite
+	default:
+		return dto.RiskAppetiteStatusWithin
+	}
+}
+
+// ensureRiskWithinTolerance blocks closing an out-of-tolerance risk that has neither treatment nor acceptance
+func (s *RiskService) ensureRiskWithinTolerance(ctx context.Context, risk *repo.Risk) error {
+	if RiskAppetiteStatus(risk) != dto.RiskAppetiteStatusOutOfTolerance {
+		return nil
+	}
+	coverage, err := s.riskRepo.GetRiskResponseCoverage(ctx, []string{risk.ID}, riskToday())
+	if err != nil {
+		return err
+	}
+	if c := coverage[risk.ID]; !c.HasTreatment && !c.HasAcceptance {
+		return ErrRiskOutOfTolerance
+	}
+	return nil
+}
+
+// checkRiskAppetite notifies when a risk newly exceeds the tolerance of its category
+func (s *RiskService) checkRiskAppetite(ctx context.Context, riskID string, wasOutOfTolerance bool) {
+	risk, err := s.riskRepo.GetByID(ctx, riskID)
+	if err != nil {
+		log.Printf("WARNING: failed to check risk appetite for risk %s: %v", riskID, err)
+		return
+	}
+	if risk == nil || wasOutOfTolerance || RiskAppetiteStatus(risk) != dto.RiskAppetiteStatusOutOfTolerance {
+		return
+	}
+
+	s.notifyRiskEscalation(ctx, risk, "appetite_exceeded", map[string]interface{}{
+		"level":           *risk.Level,
+		"appetite_level":  *risk.AppetiteLevel,
+		"tolerance_level": *risk.ToleranceLevel,
+	})
+}
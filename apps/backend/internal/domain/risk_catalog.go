@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// Risk Catalog methods
+
+func (s *RiskService) GetCatalogEntries(ctx context.Context, tenantID, kind string, filter repo.RiskCatalogFilter) ([]repo.RiskCatalogEntry, error) {
+	return s.riskRepo.ListCatalogEntries(ctx, tenantID, kind, filter)
+}
+
+func (s *RiskService) GetCatalogEntry(ctx context.Context, entryID, tenantID, kind string) (*repo.RiskCatalogEntry, error) {
+	entry, err := s.riskRepo.GetCatalogEntry(ctx, entryID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil || entry.Kind != kind {
+		return nil, ErrCatalogEntryNotFound
+	}
+	return entry, nil
+}
+
+// CreateCatalogEntry adds a custom or BDU FSTEC entry to the tenant catalog
+func (s *RiskService) CreateCatalogEntry(ctx context.Context, tenantID, kind string, req dto.RiskCatalogEntryRequest, createdBy string) (*repo.RiskCatalogEntry, error) {
+	if err := s.ensureCatalogCodeAvailable(ctx, tenantID, kind, strings.TrimSpace(req.Code), ""); err != nil {
+		return nil, err
+	}
+
+	entry := repo.RiskCatalogEntry{
+		ID:        uuid.New().String(),
+		TenantID:  &tenantID,
+		Kind:      kind,
+		IsActive:  true,
+		CreatedBy: &createdBy,
+		CreatedAt: time.Now(),
+	}
+	applyCatalogEntryRequest(&entry, req)
+	if err := s.riskRepo.CreateCatalogEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, createdBy, "create_catalog_entry", "risk", nil, map[string]interface{}{
+		"entry_id": entry.ID,
+		"kind":     kind,
+		"code":     entry.Code,
+	})
+
+	return &entry, nil
+}
+
+// UpdateCatalogEntry updates a tenant entry; built-in entries are read-only
+func (s *RiskService) UpdateCatalogEntry(ctx context.Context, entryID, tenantID, kind string, req dto.RiskCatalogEntryRequest, updatedBy string) (*repo.RiskCatalogEntry, error) {
+	entry, err := s.getTenantCatalogEntry(ctx, entryID, tenantID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureCatalogCodeAvailable(ctx, tenantID, kind, strings.TrimSpace(req.Code), entryID); err != nil {
+		return nil, err
+	}
+	applyCatalogEntryRequest(entry, req)
+
+	if err := s.riskRepo.UpdateCatalogEntry(ctx, *entry); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_catalog_entry", "risk", nil, map[string]interface{}{
+		"entry_id": entry.ID,
+		"kind":     kind,
+		"code":     entry.Code,
+	})
+
+	return entry, nil
+}
+
+// DeleteCatalogEntry removes a tenant entry; risks referencing it keep their asset and lose the link
+func (s *RiskService) DeleteCatalogEntry(ctx context.Context, entryID, tenantID, kind, deletedBy string) error {
+	entry, err := s.getTenantCatalogEntry(ctx, entryID, tenantID, kind)
+	if err != nil {
+		return err
+	}
+	if err := s.riskRepo.DeleteCatalogEntry(ctx, entryID, tenantID); err != nil {
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, deletedBy, "delete_catalog_entry", "risk", nil, map[string]interface{}{
+		"entry_id": entry.ID,
+		"kind":     kind,
+		"code":     entry.Code,
+	})
+	return nil
+}
+
+// SuggestCatalogEntries returns active threats and vulnerabilities applicable to an asset or asset type
+func (s *RiskService) SuggestCatalogEntries(ctx context.Context, tenantID, assetID, assetType string) (string, []repo.RiskCatalogEntry, []repo.RiskCatalogEntry, error) {
+	if assetID != "" {
+		resolved, err := s.riskRepo.GetAssetType(ctx, assetID, tenantID)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if resolved == "" {
+			return "", nil, nil, NewValidationError("asset_id", "asset not found")
+		}
+		assetType = resolved
+	}
+	if assetType == "" {
+		return "", nil, nil, NewValidationError("asset_type", "asset_id or asset_type is required")
+	}
+
+	filter := repo.RiskCatalogFilter{AssetType: assetType, ActiveOnly: true}
+	threats, err := s.riskRepo.ListCatalogEntries(ctx, tenantID, dto.RiskCatalogKindThreat, filter)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	vulnerabilities, err := s.riskRepo.ListCatalogEntries(ctx, tenantID, dto.RiskCatalogKindVulnerability, filter)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return assetType, threats, vulnerabilities, nil
+}
+
+// validateRiskScenario checks that the threat and vulnerability of a risk exist in the tenant's catalog
+func (s *RiskService) validateRiskScenario(ctx context.Context, tenantID string, threatID, vulnerabilityID *string) error {
+	for _, ref := range []struct {
+		field string
+		kind  string
+		id    *string
+	}{
+		{"threat_id", dto.RiskCatalogKindThreat, threatID},
+		{"vulnerability_id", dto.RiskCatalogKindVulnerability, vulnerabilityID},
+	} {
+		if isBlank(ref.id) {
+			continue
+		}
+		entry, err := s.riskRepo.GetCatalogEntry(ctx, *ref.id, tenantID)
+		if err != nil {
+			return err
+		}
+		if err := checkCatalogReference(ref.field, ref.kind, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCatalogReference rejects a missing entry and an entry of the other kind,
+// e.g. a vulnerability passed as threat_id
+func checkCatalogReference(field, kind string, entry *repo.RiskCatalogEntry) error {
+	if entry == nil || entry.Kind != kind {
+		return NewValidationError(field, kind+" not found in catalog")
+	}
+	return nil
+}
+
+func (s *RiskService) getTenantCatalogEntry(ctx context.Context, entryID, tenantID, kind string) (*repo.RiskCatalogEntry, error) {
+	entry, err := s.GetCatalogEntry(ctx, entryID, tenantID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if entry.TenantID == nil {
+		return nil, ErrCatalogEntryBuiltin
+	}
+	return entry, nil
+}
+
+func (s *RiskService) ensureCatalogCodeAvailable(ctx context.Context, tenantID, kind, code, excludeID string) error {
+	if code == "" {
+		return NewValidationError("code", "code is required")
+	}
+	exists, err := s.riskRepo.CatalogCodeExists(ctx, tenantID, kind, code, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrCatalogEntryCodeExists
+	}
+	return nil
+}
+
+// applyCatalogEntryRequest copies the editable fields; IsActive is kept when not sent
+func applyCatalogEntryRequest(entry *repo.RiskCatalogEntry, req dto.RiskCatalogEntryRequest) {
+	entry.Code = strings.TrimSpace(req.Code)
+	entry.Name = req.Name
+	entry.Description = req.Description
+	entry.Source = catalogSource(req.Source)
+	entry.AssetTypes = catalogAssetTypes(req.AssetTypes)
+	if req.IsActive != nil {
+		entry.IsActive = *req.IsActive
+	}
+	entry.UpdatedAt = time.Now()
+}
+
+func catalogSource(source string) string {
+	if source == "" {
+		return dto.RiskCatalogSourceCustom
+	}
+	return source
+}
+
+func catalogAssetTypes(assetTypes []string) []string {
+	if assetTypes == nil {
+		return []string{}
+	}
+	return assetTypes
+}
+
+// nilIfBlank normalizes empty optional references to nil
+func nilIfBlank(value *string) *string {
+	if isBlank(value) {
+		return nil
+	}
+	return value
+}
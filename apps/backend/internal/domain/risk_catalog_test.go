@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"testing"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyCatalogEntryRequest(t *testing.T) {
+	inactive := false
+
+	tests := []struct {
+		name           string
+		entry          repo.RiskCatalogEntry
+		req            dto.RiskCatalogEntryRequest
+		wantSource     string
+		wantAssetTypes []string
+		wantActive     bool
+	}{
+		{
+			name:           "new custom entry",
+			entry:          repo.RiskCatalogEntry{IsActive: true},
+			req:            dto.RiskCatalogEntryRequest{Code: " T-01 ", Name: "Phishing"},
+			wantSource:     dto.RiskCatalogSourceCustom,
+			wantAssetTypes: []string{},
+			wantActive:     true,
+		},
+		{
+			name:           "BDU FSTEC entry for servers",
+			entry:          repo.RiskCatalogEntry{IsActive: true},
+			req:            dto.RiskCatalogEntryRequest{Code: "УБИ.006", Name: "Угроза доступа", Source: dto.RiskCatalogSourceBDUFSTEC, AssetTypes: []string{"server"}},
+			wantSource:     dto.RiskCatalogSourceBDUFSTEC,
+			wantAssetTypes: []string{"server"},
+			wantActive:     true,
+		},
+		{
+			name:           "deactivate",
+			entry:          repo.RiskCatalogEntry{IsActive: true, Source: dto.RiskCatalogSourceBDUFSTEC},
+			req:            dto.RiskCatalogEntryRequest{Code: "T-01", Name: "Phishing", IsActive: &inactive},
+			wantSource:     dto.RiskCatalogSourceCustom,
+			wantAssetTypes: []string{},
+			wantActive:     false,
+		},
+		{
+			name:           "update keeps inactive entry inactive",
+			entry:          repo.RiskCatalogEntry{IsActive: false, AssetTypes: []string{"server"}},
+			req:            dto.RiskCatalogEntryRequest{Code: "T-01", Name: "Phishing"},
+			wantSource:     dto.RiskCatalogSourceCustom,
+			wantAssetTypes: []string{},
+			wantActive:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := tt.entry
+			applyCatalogEntryRequest(&entry, tt.req)
+
+			assert.Equal(t, tt.req.Name, entry.Name)
+			assert.NotContains(t, entry.Code, " ")
+			assert.Equal(t, tt.wantSource, entry.Source)
+			assert.Equal(t, tt.wantAssetTypes, entry.AssetTypes, "asset types are stored as an empty array, never NULL")
+			assert.Equal(t, tt.wantActive, entry.IsActive)
+			assert.False(t, entry.UpdatedAt.IsZero())
+		})
+	}
+}
+
+func TestCheckCatalogReference(t *testing.T) {
+	threat := &repo.RiskCatalogEntry{ID: "entry-1", Kind: dto.RiskCatalogKindThreat}
+
+	tests := []struct {
+		name      string
+		field     string
+		kind      string
+		entry     *repo.RiskCatalogEntry
+		wantField string
+	}{
+		{name: "threat", field: "threat_id", kind: dto.RiskCatalogKindThreat, entry: threat},
+		{name: "missing entry", field: "threat_id", kind: dto.RiskCatalogKindThreat, wantField: "threat_id"},
+		{name: "threat used as vulnerability", field: "vulnerability_id", kind: dto.RiskCatalogKindVulnerability, entry: threat, wantField: "vulnerability_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCatalogReference(tt.field, tt.kind, tt.entry)
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestNilIfBlank(t *testing.T) {
+	assert.Nil(t, nilIfBlank(nil))
+	assert.Nil(t, nilIfBlank(riskStringPtr("")))
+	assert.Equal(t, "entry-1", *nilIfBlank(riskStringPtr("entry-1")))
+}
+
+func TestRiskCatalogEntryRequest_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     dto.RiskCatalogEntryRequest
+		wantErr bool
+	}{
+		{name: "minimal", req: dto.RiskCatalogEntryRequest{Code: "T-01", Name: "Phishing"}},
+		{name: "asset types", req: dto.RiskCatalogEntryRequest{Code: "T-01", Name: "Phishing", AssetTypes: []string{"server", "workstation"}}},
+		{name: "missing code", req: dto.RiskCatalogEntryRequest{Name: "Phishing"}, wantErr: true},
+		{name: "unknown asset type", req: dto.RiskCatalogEntryRequest{Code: "T-01", Name: "Phishing", AssetTypes: []string{"laptop"}}, wantErr: true},
+		{name: "built-in source", req: dto.RiskCatalogEntryRequest{Code: "T-01", Name: "Phishing", Source: dto.RiskCatalogSourceBuiltin}, wantErr: true},
+	}
+
+	v := validator.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
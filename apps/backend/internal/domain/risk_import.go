@@ -0,0 +1,272 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// riskImportAliases lists accepted column headers per field in addition to the field name itself
+var riskImportAliases = map[string][]string{
+	dto.RiskImportFieldTitle:       {"name", "risk", "название", "наименование", "риск"},
+	dto.RiskImportFieldDescription: {"описание"},
+	dto.RiskImportFieldCategory:    {"категория"},
+	dto.RiskImportFieldLikelihood:  {"probability", "вероятность"},
+	dto.RiskImportFieldImpact:      {"consequence", "влияние", "ущерб", "последствия"},
+	dto.RiskImportFieldOwnerEmail:  {"owner", "email", "владелец", "ответственный"},
+	dto.RiskImportFieldAsset:       {"asset", "inventory number", "актив", "инвентарный номер"},
+	dto.RiskImportFieldMethodology: {"методология"},
+	dto.RiskImportFieldStrategy:    {"treatment", "стратегия", "обработка"},
+	dto.RiskImportFieldDueDate:     {"due", "deadline", "срок"},
+}
+
+// riskImportMethodologies maps lowercased values to the canonical methodology names
+var riskImportMethodologies = map[string]string{
+	"iso27005":    "ISO27005",
+	"iso 27005":   "ISO27005",
+	"nist":        "NIST",
+	"coso":        "COSO",
+	"custom":      "Custom",
+	"собственная": "Custom",
+}
+
+// riskImportBatch is a parsed and validated import file
+type riskImportBatch struct {
+	preview *dto.RiskImportPreviewResponse
+	risks   []repo.Risk // valid rows only, in file order
+}
+
+// Risk Import methods
+
+// PreviewRiskImport parses the file and validates every row without creating risks
+func (s *RiskService) PreviewRiskImport(ctx context.Context, tenantID, filename string, data []byte, mapping map[string]string) (*dto.RiskImportPreviewResponse, error) {
+	batch, err := s.prepareRiskImport(ctx, tenantID, filename, data, mapping)
+	if err != nil {
+		return nil, err
+	}
+	return batch.preview, nil
+}
+
+// ImportRisks creates the risks from the file in one transaction.
+// If any row is invalid nothing is imported unless skipInvalid is set; the preview is returned with ErrRiskImportInvalidRows.
+func (s *RiskService) ImportRisks(ctx context.Context, tenantID, filename string, data []byte, mapping map[string]string, skipInvalid bool, importedBy string) (*dto.RiskImportResultResponse, *dto.RiskImportPreviewResponse, error) {
+	batch, err := s.prepareRiskImport(ctx, tenantID, filename, data, mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+	if batch.preview.InvalidRows > 0 && !skipInvalid {
+		return nil, batch.preview, ErrRiskImportInvalidRows
+	}
+	if len(batch.risks) == 0 {
+		return nil, batch.preview, ErrRiskImportInvalidRows
+	}
+
+	if err := s.riskRepo.CreateRisks(ctx, batch.risks); err != nil {
+		return nil, nil, err
+	}
+
+	result := &dto.RiskImportResultResponse{
+		Imported: len(batch.risks),
+		Skipped:  batch.preview.InvalidRows,
+		RiskIDs:  make([]string, 0, len(batch.risks)),
+	}
+	// Те же действия после создания, что и в CreateRisk: срок пересмотра и проверка толерантности
+	for i := range batch.risks {
+		risk := &batch.risks[i]
+		result.RiskIDs = append(result.RiskIDs, risk.ID)
+		if err := s.scheduleReview(ctx, risk); err != nil {
+			log.Printf("WARNING: failed to schedule review for imported risk %s: %v", risk.ID, err)
+		}
+		s.checkRiskAppetite(ctx, risk.ID, false)
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, importedBy, "import", "risk", nil, map[string]interface{}{
+		"filename": filename,
+		"imported": result.Imported,
+		"skipped":  result.Skipped,
+		"risk_ids": result.RiskIDs,
+	})
+
+	return result, batch.preview, nil
+}
+
+func (s *RiskService) prepareRiskImport(ctx context.Context, tenantID, filename string, data []byte, explicit map[string]string) (*riskImportBatch, error) {
+	rows, err := readImportTable(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	headers := rows[0]
+
+	mapping, err := resolveImportMapping(headers, riskImportAliases, explicit)
+	if err != nil {
+		return nil, err
+	}
+	for _, required := range []string{dto.RiskImportFieldTitle, dto.RiskImportFieldLikelihood, dto.RiskImportFieldImpact} {
+		if _, ok := mapping[required]; !ok {
+			return nil, NewValidationError("mapping", fmt.Sprintf("no column mapped to required field %q", required))
+		}
+	}
+
+	owners, assets, err := s.resolveRiskImportReferences(ctx, tenantID, rows[1:], mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &riskImportBatch{
+		preview: &dto.RiskImportPreviewResponse{
+			Headers: headers,
+			Mapping: make(map[string]string, len(mapping)),
+			Rows:    make([]dto.RiskImportRowResponse, 0, len(rows)-1),
+		},
+	}
+	for field, index := range mapping {
+		batch.preview.Mapping[field] = headers[index]
+	}
+
+	now := time.Now()
+	for i, row := range rows[1:] {
+		if isEmptyRow(row) {
+			continue
+		}
+		rowResponse, risk := buildRiskImportRow(row, i+2, mapping, owners, assets)
+		batch.preview.TotalRows++
+		if rowResponse.Valid {
+			batch.preview.ValidRows++
+			risk.ID = uuid.New().String()
+			risk.TenantID = tenantID
+			risk.CreatedAt = now
+			risk.UpdatedAt = now
+			batch.risks = append(batch.risks, *risk)
+		} else {
+			batch.preview.InvalidRows++
+		}
+		batch.preview.Rows = append(batch.preview.Rows, rowResponse)
+	}
+	return batch, nil
+}
+
+// resolveRiskImportReferences looks up all owner emails and asset inventory numbers of the file at once
+func (s *RiskService) resolveRiskImportReferences(ctx context.Context, tenantID string, rows [][]string, mapping map[string]int) (map[string]string, map[string]string, error) {
+	var emails, numbers []string
+	for _, row := range rows {
+		if email := importCell(row, mapping, dto.RiskImportFieldOwnerEmail); email != "" {
+			emails = append(emails, strings.ToLower(email))
+		}
+		if number := importCell(row, mapping, dto.RiskImportFieldAsset); number != "" {
+			numbers = append(numbers, strings.ToUpper(number))
+		}
+	}
+
+	owners, err := s.riskRepo.ResolveUserIDsByEmail(ctx, tenantID, emails)
+	if err != nil {
+		return nil, nil, err
+	}
+	assets, err := s.riskRepo.ResolveAssetIDsByInventoryNumber(ctx, tenantID, numbers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return owners, assets, nil
+}
+
+// buildRiskImportRow converts a row into a RiskRequest, resolves references and validates it
+func buildRiskImportRow(row []string, rowNumber int, mapping map[string]int, owners, assets map[string]string) (dto.RiskImportRowResponse, *repo.Risk) {
+	response := dto.RiskImportRowResponse{
+		RowNumber:            rowNumber,
+		OwnerEmail:           importCell(row, mapping, dto.RiskImportFieldOwnerEmail),
+		AssetInventoryNumber: importCell(row, mapping, dto.RiskImportFieldAsset),
+		Errors:               []string{},
+	}
+	req := &response.Risk
+	req.Title = importCell(row, mapping, dto.RiskImportFieldTitle)
+	req.Description = optionalImportCell(row, mapping, dto.RiskImportFieldDescription)
+	req.Category = optionalImportCell(row, mapping, dto.RiskImportFieldCategory)
+
+	for _, score := range []struct {
+		field  string
+		target *int
+	}{
+		{dto.RiskImportFieldLikelihood, &req.Likelihood},
+		{dto.RiskImportFieldImpact, &req.Impact},
+	} {
+		field, target := score.field, score.target
+		value := importCell(row, mapping, field)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("%s: %q is not a number", field, value))
+			continue
+		}
+		*target = parsed
+	}
+
+	if value := optionalImportCell(row, mapping, dto.RiskImportFieldMethodology); value != nil {
+		if canonical, ok := riskImportMethodologies[strings.ToLower(*value)]; ok {
+			value = &canonical
+		}
+		req.Methodology = value
+	}
+	if value := optionalImportCell(row, mapping, dto.RiskImportFieldStrategy); value != nil {
+		strategy := strings.ToLower(*value)
+		req.Strategy = &strategy
+	}
+	if value := optionalImportCell(row, mapping, dto.RiskImportFieldDueDate); value != nil {
+		dueDate, err := parseImportDate(*value)
+		if err != nil {
+			response.Errors = append(response.Errors, fmt.Sprintf("due_date: %v", err))
+		} else {
+			req.DueDate = &dueDate
+		}
+	}
+
+	if response.OwnerEmail != "" {
+		if id, ok := owners[strings.ToLower(response.OwnerEmail)]; ok {
+			req.OwnerUserID = &id
+		} else {
+			response.Errors = append(response.Errors, fmt.Sprintf("owner_email: user %q not found", response.OwnerEmail))
+		}
+	}
+	if response.AssetInventoryNumber != "" {
+		if id, ok := assets[strings.ToUpper(response.AssetInventoryNumber)]; ok {
+			req.AssetID = &id
+		} else {
+			response.Errors = append(response.Errors, fmt.Sprintf("asset_inventory_number: asset %q not found", response.AssetInventoryNumber))
+		}
+	}
+
+	response.Errors = append(response.Errors, validateImportRequest(req)...)
+
+	response.Valid = len(response.Errors) == 0
+	if !response.Valid {
+		return response, nil
+	}
+
+	level, _ := dto.CalculateRiskLevel(req.Likelihood, req.Impact)
+	risk := &repo.Risk{
+		Title:       req.Title,
+		Description: req.Description,
+		Category:    req.Category,
+		Likelihood:  &req.Likelihood,
+		Impact:      &req.Impact,
+		Level:       &level,
+		Status:      dto.RiskStatusNew,
+		OwnerUserID: req.OwnerUserID,
+		AssetID:     req.AssetID,
+		Methodology: req.Methodology,
+		Strategy:    req.Strategy,
+	}
+	if req.DueDate != nil {
+		dueDate, _ := time.Parse("2006-01-02", *req.DueDate)
+		risk.DueDate = &dueDate
+	}
+	return response, risk
+}
@@ -0,0 +1,394 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// kriStatusRank orders KRI statuses from best to worst
+var kriStatusRank = map[string]int{
+	dto.KRIStatusUnknown: 0,
+	dto.KRIStatusGreen:   1,
+	dto.KRIStatusAmber:   2,
+	dto.KRIStatusRed:     3,
+}
+
+// kriFrequencyPeriod is the expected interval between measurements
+var kriFrequencyPeriod = map[string]time.Duration{
+	"daily":     24 * time.Hour,
+	"weekly":    7 * 24 * time.Hour,
+	"monthly":   31 * 24 * time.Hour,
+	"quarterly": 92 * 24 * time.Hour,
+}
+
+// Risk KRI methods
+
+func (s *RiskService) CreateKRI(ctx context.Context, tenantID string, req dto.RiskKRIRequest, createdBy string) (*repo.RiskKRI, error) {
+	if err := s.validateKRIRequest(ctx, tenantID, "", req); err != nil {
+		return nil, err
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	kri := repo.RiskKRI{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		Code:           strings.TrimSpace(req.Code),
+		Name:           req.Name,
+		Description:    req.Description,
+		Unit:           req.Unit,
+		Direction:      req.Direction,
+		AmberThreshold: *req.AmberThreshold,
+		RedThreshold:   *req.RedThreshold,
+		Frequency:      req.Frequency,
+		OwnerUserID:    req.OwnerUserID,
+		IsActive:       isActive,
+		CurrentStatus:  dto.KRIStatusUnknown,
+		CreatedBy:      &createdBy,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if err := s.riskRepo.CreateKRI(ctx, kri); err != nil {
+		return nil, err
+	}
+	if err := s.riskRepo.SetKRIRisks(ctx, kri.ID, req.RiskIDs); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, createdBy, "create_kri", "risk", nil, map[string]interface{}{
+		"kri_id":   kri.ID,
+		"code":     kri.Code,
+		"name":     kri.Name,
+		"risk_ids": req.RiskIDs,
+	})
+
+	return s.riskRepo.GetKRI(ctx, kri.ID, tenantID)
+}
+
+// GetKRIs returns the tenant's KRIs, optionally only those linked to a risk
+func (s *RiskService) GetKRIs(ctx context.Context, tenantID, riskID string) ([]repo.RiskKRI, error) {
+	if riskID != "" {
+		if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+			return nil, err
+		}
+	}
+	return s.riskRepo.ListKRIs(ctx, tenantID, riskID)
+}
+
+func (s *RiskService) GetKRI(ctx context.Context, kriID, tenantID string) (*repo.RiskKRI, error) {
+	kri, err := s.riskRepo.GetKRI(ctx, kriID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if kri == nil {
+		return nil, ErrKRINotFound
+	}
+	return kri, nil
+}
+
+// UpdateKRI updates the definition; changed thresholds re-evaluate the current value
+func (s *RiskService) UpdateKRI(ctx context.Context, kriID, tenantID string, req dto.RiskKRIRequest, updatedBy string) (*repo.RiskKRI, error) {
+	kri, err := s.GetKRI(ctx, kriID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateKRIRequest(ctx, tenantID, kriID, req); err != nil {
+		return nil, err
+	}
+
+	oldStatus := kri.CurrentStatus
+	kri.Code = strings.TrimSpace(req.Code)
+	kri.Name = req.Name
+	kri.Description = req.Description
+	kri.Unit = req.Unit
+	kri.Direction = req.Direction
+	kri.AmberThreshold = *req.AmberThreshold
+	kri.RedThreshold = *req.RedThreshold
+	kri.Frequency = req.Frequency
+	kri.OwnerUserID = req.OwnerUserID
+	if req.IsActive != nil {
+		kri.IsActive = *req.IsActive
+	}
+	if kri.CurrentValue != nil {
+		kri.CurrentStatus = evaluateKRIStatus(kri, *kri.CurrentValue)
+	}
+
+	if err := s.riskRepo.UpdateKRI(ctx, *kri); err != nil {
+		return nil, err
+	}
+	if err := s.riskRepo.SetKRIRisks(ctx, kri.ID, req.RiskIDs); err != nil {
+		return nil, err
+	}
+	kri.RiskIDs = req.RiskIDs
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_kri", "risk", nil, map[string]interface{}{
+		"kri_id":          kri.ID,
+		"code":            kri.Code,
+		"amber_threshold": kri.AmberThreshold,
+		"red_threshold":   kri.RedThreshold,
+		"risk_ids":        req.RiskIDs,
+	})
+
+	if kri.IsActive && kriStatusRank[kri.CurrentStatus] > kriStatusRank[oldStatus] && kri.CurrentValue != nil {
+		s.escalateKRIBreach(ctx, kri, oldStatus, *kri.CurrentValue, "Пороги KRI изменены", updatedBy)
+	}
+
+	return s.riskRepo.GetKRI(ctx, kri.ID, tenantID)
+}
+
+func (s *RiskService) DeleteKRI(ctx context.Context, kriID, tenantID, deletedBy string) error {
+	kri, err := s.GetKRI(ctx, kriID, tenantID)
+	if err != nil {
+		return err
+	}
+	if err := s.riskRepo.DeleteKRI(ctx, kri.ID); err != nil {
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, deletedBy, "delete_kri", "risk", nil, map[string]interface{}{
+		"kri_id": kri.ID,
+		"code":   kri.Code,
+	})
+	return nil
+}
+
+// Risk KRI Measurements methods
+
+func (s *RiskService) AddKRIMeasurement(ctx context.Context, kriID, tenantID string, req dto.RiskKRIMeasurementRequest, recordedBy string) (*repo.RiskKRIMeasurement, error) {
+	kri, err := s.GetKRI(ctx, kriID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordKRIMeasurement(ctx, kri, req, dto.KRISourceManual, recordedBy)
+}
+
+// PushKRIMeasurements records measurements sent by external systems, addressed by KRI code.
+// Each measurement is processed independently and gets its own result.
+func (s *RiskService) PushKRIMeasurements(ctx context.Context, tenantID string, req dto.RiskKRIPushRequest, recordedBy string) []dto.RiskKRIPushResult {
+	results := make([]dto.RiskKRIPushResult, 0, len(req.Measurements))
+	for _, item := range req.Measurements {
+		result := dto.RiskKRIPushResult{Code: item.Code}
+
+		kri, err := s.riskRepo.GetKRIByCode(ctx, strings.TrimSpace(item.Code), tenantID)
+		if err == nil && kri == nil {
+			err = ErrKRINotFound
+		}
+		if err == nil {
+			var measurement *repo.RiskKRIMeasurement
+			measurement, err = s.recordKRIMeasurement(ctx, kri, item.RiskKRIMeasurementRequest, dto.KRISourceAPI, recordedBy)
+			if err == nil {
+				result.Status = measurement.Status
+			}
+		}
+		if err != nil {
+			message := err.Error()
+			result.Error = &message
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// GetKRIMeasurements returns measurements for the history chart, oldest first
+func (s *RiskService) GetKRIMeasurements(ctx context.Context, kriID, tenantID string, from, to time.Time) ([]repo.RiskKRIMeasurement, error) {
+	if _, err := s.GetKRI(ctx, kriID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.riskRepo.ListKRIMeasurements(ctx, kriID, from, to)
+}
+
+func (s *RiskService) recordKRIMeasurement(ctx context.Context, kri *repo.RiskKRI, req dto.RiskKRIMeasurementRequest, source, recordedBy string) (*repo.RiskKRIMeasurement, error) {
+	if !kri.IsActive {
+		return nil, ErrKRIInactive
+	}
+
+	measuredAt := time.Now()
+	if !isBlank(req.MeasuredAt) {
+		parsed, err := parseKRITime(*req.MeasuredAt)
+		if err != nil {
+			return nil, err
+		}
+		if parsed.After(time.Now().Add(5 * time.Minute)) {
+			return nil, NewValidationError("measured_at", "must not be in the future")
+		}
+		measuredAt = parsed
+	}
+
+	measurement := repo.RiskKRIMeasurement{
+		ID:         uuid.New().String(),
+		KRIID:      kri.ID,
+		Value:      *req.Value,
+		Status:     evaluateKRIStatus(kri, *req.Value),
+		MeasuredAt: measuredAt,
+		Source:     source,
+		Comment:    req.Comment,
+		RecordedBy: &recordedBy,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.riskRepo.AddKRIMeasurement(ctx, measurement); err != nil {
+		return nil, err
+	}
+
+	// Back-filled measurements go to the history only; the current value is the latest one
+	if kri.LastMeasuredAt != nil && measuredAt.Before(*kri.LastMeasuredAt) {
+		return &measurement, nil
+	}
+
+	oldStatus := kri.CurrentStatus
+	if err := s.riskRepo.UpdateKRICurrent(ctx, kri.ID, &measurement.Value, measurement.Status, &measuredAt); err != nil {
+		return nil, err
+	}
+	kri.CurrentValue = &measurement.Value
+	kri.CurrentStatus = measurement.Status
+	kri.LastMeasuredAt = &measuredAt
+
+	if kriStatusRank[measurement.Status] > kriStatusRank[oldStatus] && measurement.Status != dto.KRIStatusGreen {
+		reason := ""
+		if req.Comment != nil {
+			reason = *req.Comment
+		}
+		s.escalateKRIBreach(ctx, kri, oldStatus, measurement.Value, reason, recordedBy)
+	}
+
+	return &measurement, nil
+}
+
+// escalateKRIBreach feeds a worsened KRI status into the escalation of every linked risk.
+// A red breach sends accepted and mitigated risks back to analysis.
+func (s *RiskService) escalateKRIBreach(ctx context.Context, kri *repo.RiskKRI, oldStatus string, value float64, comment, changedBy string) {
+	formattedValue := strconv.FormatFloat(value, 'f', -1, 64)
+	reason := fmt.Sprintf("KRI %s (%s): значение %s, статус %s", kri.Name, kri.Code, formattedValue, kri.CurrentStatus)
+	if comment != "" {
+		reason += ". " + comment
+	}
+
+	log.Printf("WARNING: KRI threshold breach - KRI ID: %s, Code: %s, Value: %s, Status: %s -> %s",
+		kri.ID, kri.Code, formattedValue, oldStatus, kri.CurrentStatus)
+
+	for _, riskID := range kri.RiskIDs {
+		risk, err := s.riskRepo.GetByIDWithTenant(ctx, riskID, kri.TenantID)
+		if err != nil || risk == nil {
+			log.Printf("ERROR: RiskService.escalateKRIBreach get risk %s: %v", riskID, err)
+			continue
+		}
+		if risk.Status == dto.RiskStatusClosed {
+			continue
+		}
+
+		if err := s.riskRepo.AddHistory(ctx, repo.RiskHistory{
+			ID:           uuid.New().String(),
+			RiskID:       risk.ID,
+			FieldChanged: "kri",
+			OldValue:     &oldStatus,
+			NewValue:     &kri.CurrentStatus,
+			ChangeReason: &reason,
+			ChangedBy:    changedBy,
+			ChangedAt:    time.Now(),
+		}); err != nil {
+			log.Printf("WARNING: failed to record risk history for risk %s: %v", risk.ID, err)
+		}
+
+		s.notifyRiskEscalation(ctx, risk, "kri_"+kri.CurrentStatus, map[string]interface{}{
+			"kri_id":          kri.ID,
+			"kri_code":        kri.Code,
+			"kri_name":        kri.Name,
+			"kri_value":       value,
+			"old_kri_status":  oldStatus,
+			"new_kri_status":  kri.CurrentStatus,
+			"amber_threshold": kri.AmberThreshold,
+			"red_threshold":   kri.RedThreshold,
+		})
+
+		if kri.CurrentStatus == dto.KRIStatusRed && (risk.Status == dto.RiskStatusAccepted || risk.Status == dto.RiskStatusMitigated) {
+			if err := s.changeRiskStatus(ctx, risk, dto.RiskStatusInAnalysis, reason, changedBy); err != nil {
+				log.Printf("ERROR: RiskService.escalateKRIBreach status %s: %v", risk.ID, err)
+			}
+		}
+	}
+}
+
+func (s *RiskService) validateKRIRequest(ctx context.Context, tenantID, kriID string, req dto.RiskKRIRequest) error {
+	switch req.Direction {
+	case dto.KRIDirectionHigherIsWorse:
+		if *req.AmberThreshold > *req.RedThreshold {
+			return NewValidationError("amber_threshold", "must not exceed red_threshold when higher values are worse")
+		}
+	case dto.KRIDirectionLowerIsWorse:
+		if *req.AmberThreshold < *req.RedThreshold {
+			return NewValidationError("amber_threshold", "must not be below red_threshold when lower values are worse")
+		}
+	}
+
+	existing, err := s.riskRepo.GetKRIByCode(ctx, strings.TrimSpace(req.Code), tenantID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != kriID {
+		return ErrKRICodeExists
+	}
+
+	for _, riskID := range req.RiskIDs {
+		if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateKRIStatus compares a value with the amber and red thresholds of the KRI
+func evaluateKRIStatus(kri *repo.RiskKRI, value float64) string {
+	if kri.Direction == dto.KRIDirectionLowerIsWorse {
+		switch {
+		case value <= kri.RedThreshold:
+			return dto.KRIStatusRed
+		case value <= kri.AmberThreshold:
+			return dto.KRIStatusAmber
+		}
+		return dto.KRIStatusGreen
+	}
+
+	switch {
+	case value >= kri.RedThreshold:
+		return dto.KRIStatusRed
+	case value >= kri.AmberThreshold:
+		return dto.KRIStatusAmber
+	}
+	return dto.KRIStatusGreen
+}
+
+// IsKRIStale reports whether an active KRI has missed its expected measurement
+func IsKRIStale(kri *repo.RiskKRI, now time.Time) bool {
+	if !kri.IsActive {
+		return false
+	}
+	period, ok := kriFrequencyPeriod[kri.Frequency]
+	if !ok {
+		return false
+	}
+	if kri.LastMeasuredAt == nil {
+		return now.Sub(kri.CreatedAt) > period
+	}
+	return now.Sub(*kri.LastMeasuredAt) > period
+}
+
+func parseKRITime(value string) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.Parse("2006-01-02", value); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, NewValidationError("measured_at", "invalid time format, use RFC3339 or YYYY-MM-DD")
+}
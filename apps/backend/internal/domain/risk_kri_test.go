@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateKRIStatus(t *testing.T) {
+	higher := &repo.RiskKRI{Direction: dto.KRIDirectionHigherIsWorse, AmberThreshold: 70, RedThreshold: 90}
+	lower := &repo.RiskKRI{Direction: dto.KRIDirectionLowerIsWorse, AmberThreshold: 95, RedThreshold: 90}
+
+	tests := []struct {
+		name  string
+		kri   *repo.RiskKRI
+		value float64
+		want  string
+	}{
+		{"higher is worse below amber", higher, 50, dto.KRIStatusGreen},
+		{"higher is worse at amber", higher, 70, dto.KRIStatusAmber},
+		{"higher is worse between thresholds", higher, 89.9, dto.KRIStatusAmber},
+		{"higher is worse at red", higher, 90, dto.KRIStatusRed},
+		{"higher is worse above red", higher, 150, dto.KRIStatusRed},
+		{"lower is worse above amber", lower, 99, dto.KRIStatusGreen},
+		{"lower is worse at amber", lower, 95, dto.KRIStatusAmber},
+		{"lower is worse between thresholds", lower, 92, dto.KRIStatusAmber},
+		{"lower is worse at red", lower, 90, dto.KRIStatusRed},
+		{"lower is worse below red", lower, 0, dto.KRIStatusRed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, evaluateKRIStatus(tt.kri, tt.value))
+		})
+	}
+}
+
+func TestIsKRIStale(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	hoursAgo := func(hours int) *time.Time {
+		at := now.Add(-time.Duration(hours) * time.Hour)
+		return &at
+	}
+
+	tests := []struct {
+		name string
+		kri  repo.RiskKRI
+		want bool
+	}{
+		{"inactive", repo.RiskKRI{IsActive: false, Frequency: "daily", LastMeasuredAt: hoursAgo(100)}, false},
+		{"unknown frequency", repo.RiskKRI{IsActive: true, Frequency: "ad_hoc", LastMeasuredAt: hoursAgo(10000)}, false},
+		{"daily measured recently", repo.RiskKRI{IsActive: true, Frequency: "daily", LastMeasuredAt: hoursAgo(23)}, false},
+		{"daily overdue", repo.RiskKRI{IsActive: true, Frequency: "daily", LastMeasuredAt: hoursAgo(25)}, true},
+		{"weekly within period", repo.RiskKRI{IsActive: true, Frequency: "weekly", LastMeasuredAt: hoursAgo(6 * 24)}, false},
+		{"never measured new", repo.RiskKRI{IsActive: true, Frequency: "weekly", CreatedAt: *hoursAgo(24)}, false},
+		{"never measured old", repo.RiskKRI{IsActive: true, Frequency: "monthly", CreatedAt: *hoursAgo(40 * 24)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsKRIStale(&tt.kri, now))
+		})
+	}
+}
+
+func TestParseKRITime(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2026-03-10T08:30:00Z", want: time.Date(2026, 3, 10, 8, 30, 0, 0, time.UTC)},
+		{value: "2026-03-10T11:30:00+03:00", want: time.Date(2026, 3, 10, 8, 30, 0, 0, time.UTC)},
+		{value: "2026-03-10", want: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{value: "10.03.2026", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseKRITime(tt.value)
+			if tt.wantErr {
+				var validationErr ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %v", got)
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// Risk Quantification methods
+
+func (s *RiskService) GetQuantification(ctx context.Context, riskID, tenantID string) (*repo.RiskQuantification, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+
+	quantification, err := s.riskRepo.GetQuantification(ctx, riskID)
+	if err != nil {
+		return nil, err
+	}
+	if quantification == nil {
+		return nil, ErrRiskQuantificationNotFound
+	}
+	return quantification, nil
+}
+
+// SetQuantification stores the FAIR inputs; the stored ALE is only refreshed by RunSimulation
+func (s *RiskService) SetQuantification(ctx context.Context, riskID, tenantID string, req dto.RiskQuantificationRequest, updatedBy string) (*repo.RiskQuantification, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+	if err := validateEstimateRange("lef", *req.LEFMin, *req.LEFMostLikely, *req.LEFMax); err != nil {
+		return nil, err
+	}
+	if err := validateEstimateRange("lm", *req.LMMin, *req.LMMostLikely, *req.LMMax); err != nil {
+		return nil, err
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = dto.RiskQuantificationDefaultCurrency
+	}
+
+	quantification := repo.RiskQuantification{
+		RiskID:        riskID,
+		TenantID:      tenantID,
+		LEFMin:        *req.LEFMin,
+		LEFMostLikely: *req.LEFMostLikely,
+		LEFMax:        *req.LEFMax,
+		LMMin:         *req.LMMin,
+		LMMostLikely:  *req.LMMostLikely,
+		LMMax:         *req.LMMax,
+		Currency:      currency,
+		UpdatedBy:     &updatedBy,
+	}
+	if err := s.riskRepo.UpsertQuantification(ctx, quantification); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_quantification", "risk", &riskID, map[string]interface{}{
+		"lef":      []float64{quantification.LEFMin, quantification.LEFMostLikely, quantification.LEFMax},
+		"lm":       []float64{quantification.LMMin, quantification.LMMostLikely, quantification.LMMax},
+		"currency": currency,
+	})
+
+	return s.riskRepo.GetQuantification(ctx, riskID)
+}
+
+func (s *RiskService) DeleteQuantification(ctx context.Context, riskID, tenantID, deletedBy string) error {
+	if _, err := s.GetQuantification(ctx, riskID, tenantID); err != nil {
+		return err
+	}
+	if err := s.riskRepo.DeleteQuantification(ctx, riskID); err != nil {
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, deletedBy, "delete_quantification", "risk", &riskID, nil)
+	return nil
+}
+
+// Risk Simulations methods
+
+// RunSimulation runs a Monte Carlo simulation of annual loss and stores the result on the risk
+func (s *RiskService) RunSimulation(ctx context.Context, riskID, tenantID string, req dto.RiskSimulationRequest, createdBy string) (*repo.RiskSimulation, error) {
+	quantification, err := s.GetQuantification(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	iterations := req.Iterations
+	if iterations == 0 {
+		iterations = dto.RiskSimulationDefaultIterations
+	}
+	if float64(iterations)*quantification.LEFMax > dto.RiskSimulationEventBudget {
+		return nil, NewValidationError("iterations", fmt.Sprintf("iterations × lef_max must not exceed %d", dto.RiskSimulationEventBudget))
+	}
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	losses := simulateAnnualLosses(*quantification, iterations, seed)
+
+	sim := repo.RiskSimulation{
+		ID:              uuid.New().String(),
+		RiskID:          riskID,
+		TenantID:        tenantID,
+		Iterations:      iterations,
+		Seed:            seed,
+		Inputs:          *quantification,
+		Currency:        quantification.Currency,
+		MeanLoss:        roundMoney(meanOf(losses)),
+		P10:             roundMoney(percentileOf(losses, 0.10)),
+		P50:             roundMoney(percentileOf(losses, 0.50)),
+		P90:             roundMoney(percentileOf(losses, 0.90)),
+		P95:             roundMoney(percentileOf(losses, 0.95)),
+		P99:             roundMoney(percentileOf(losses, 0.99)),
+		MaxLoss:         roundMoney(losses[len(losses)-1]),
+		LossProbability: exceedanceProbability(losses, 0),
+		ExceedanceCurve: lossExceedanceCurve(losses, dto.RiskSimulationExceedanceCurvePoints),
+		CreatedBy:       &createdBy,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.riskRepo.CreateSimulation(ctx, sim); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, createdBy, "run_simulation", "risk", &riskID, map[string]interface{}{
+		"simulation_id": sim.ID,
+		"iterations":    iterations,
+		"seed":          seed,
+		"mean_loss":     sim.MeanLoss,
+		"p90":           sim.P90,
+	})
+
+	return &sim, nil
+}
+
+func (s *RiskService) GetSimulations(ctx context.Context, riskID, tenantID string, limit int) ([]repo.RiskSimulation, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.riskRepo.ListSimulations(ctx, riskID, limit)
+}
+
+// simulateAnnualLosses returns sorted simulated annual losses.
+// Each year draws a loss event frequency from PERT(lef), a Poisson number of events
+// with that rate, and a PERT(lm) loss magnitude for each event.
+func simulateAnnualLosses(q repo.RiskQuantification, iterations int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	losses := make([]float64, iterations)
+	for i := range losses {
+		frequency := samplePERT(rng, q.LEFMin, q.LEFMostLikely, q.LEFMax)
+		events := samplePoisson(rng, frequency)
+		for e := 0; e < events; e++ {
+			losses[i] += samplePERT(rng, q.LMMin, q.LMMostLikely, q.LMMax)
+		}
+	}
+	sort.Float64s(losses)
+	return losses
+}
+
+func validateEstimateRange(field string, min, mostLikely, max float64) error {
+	if min > mostLikely || mostLikely > max {
+		return NewValidationError(field, "estimates must satisfy min <= most_likely <= max")
+	}
+	return nil
+}
+
+// samplePERT draws from the beta-PERT distribution over [min, max] with the given mode
+func samplePERT(rng *rand.Rand, min, mode, max float64) float64 {
+	if max <= min {
+		return min
+	}
+	alpha := 1 + 4*(mode-min)/(max-min)
+	beta := 1 + 4*(max-mode)/(max-min)
+	x := sampleGamma(rng, alpha)
+	y := sampleGamma(rng, beta)
+	return min + x/(x+y)*(max-min)
+}
+
+// sampleGamma uses Marsaglia and Tsang's method; PERT shapes are always >= 1
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// samplePoisson uses Knuth's method for small rates and a normal approximation otherwise
+func samplePoisson(rng *rand.Rand, lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	if lambda > 30 {
+		n := int(math.Round(lambda + math.Sqrt(lambda)*rng.NormFloat64()))
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+	limit := math.Exp(-lambda)
+	n := 0
+	for p := rng.Float64(); p > limit; p *= rng.Float64() {
+		n++
+	}
+	return n
+}
+
+func meanOf(sorted []float64) float64 {
+	var total float64
+	for _, value := range sorted {
+		total += value
+	}
+	return total / float64(len(sorted))
+}
+
+// percentileOf uses the nearest-rank method on sorted values
+func percentileOf(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// exceedanceProbability is the share of simulated years with a loss above threshold
+func exceedanceProbability(sorted []float64, threshold float64) float64 {
+	above := len(sorted) - sort.Search(len(sorted), func(i int) bool { return sorted[i] > threshold })
+	return math.Round(float64(above)/float64(len(sorted))*10000) / 10000
+}
+
+// lossExceedanceCurve samples the curve at evenly spaced losses from zero to the maximum
+func lossExceedanceCurve(sorted []float64, points int) []repo.LossExceedancePoint {
+	maxLoss := sorted[len(sorted)-1]
+	if maxLoss <= 0 {
+		return []repo.LossExceedancePoint{{Loss: 0, Probability: 0}}
+	}
+
+	curve := make([]repo.LossExceedancePoint, 0, points)
+	step := maxLoss / float64(points-1)
+	for i := 0; i < points; i++ {
+		loss := roundMoney(step * float64(i))
+		curve = append(curve, repo.LossExceedancePoint{
+			Loss:        loss,
+			Probability: exceedanceProbability(sorted, loss),
+		})
+	}
+	return curve
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplePERT(t *testing.T) {
+	tests := []struct {
+		name            string
+		min, mode, max  float64
+		wantMean, delta float64
+	}{
+		{"symmetric", 0, 50, 100, 50, 1},
+		{"skewed right", 10, 20, 100, (10 + 4*20 + 100) / 6.0, 1},
+		{"skewed left", 0, 90, 100, (0 + 4*90 + 100) / 6.0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(42))
+			var total float64
+			const n = 20000
+			for i := 0; i < n; i++ {
+				v := samplePERT(rng, tt.min, tt.mode, tt.max)
+				require.GreaterOrEqual(t, v, tt.min)
+				require.LessOrEqual(t, v, tt.max)
+				total += v
+			}
+			assert.InDelta(t, tt.wantMean, total/n, tt.delta)
+		})
+	}
+}
+
+func TestSamplePERT_DegenerateRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	assert.Equal(t, 5.0, samplePERT(rng, 5, 5, 5))
+}
+
+func TestSamplePERT_Deterministic(t *testing.T) {
+	a := rand.New(rand.NewSource(7))
+	b := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, samplePERT(a, 1, 3, 10), samplePERT(b, 1, 3, 10))
+	}
+}
+
+func TestSamplePoisson(t *testing.T) {
+	tests := []struct {
+		name   string
+		lambda float64
+	}{
+		{"knuth small rate", 0.5},
+		{"knuth moderate rate", 12},
+		{"normal approximation", 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(42))
+			const n = 20000
+			var total float64
+			for i := 0; i < n; i++ {
+				k := samplePoisson(rng, tt.lambda)
+				require.GreaterOrEqual(t, k, 0)
+				total += float64(k)
+			}
+			assert.InDelta(t, tt.lambda, total/n, 3*math.Sqrt(tt.lambda/n)+0.01)
+		})
+	}
+}
+
+func TestSamplePoisson_NonPositiveRate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	assert.Equal(t, 0, samplePoisson(rng, 0))
+	assert.Equal(t, 0, samplePoisson(rng, -3))
+}
+
+func TestPercentileOf(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 1},
+		{0.10, 1},
+		{0.50, 5},
+		{0.90, 9},
+		{0.95, 10},
+		{1, 10},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, percentileOf(sorted, tt.p), "p=%v", tt.p)
+	}
+}
+
+func TestExceedanceProbability(t *testing.T) {
+	sorted := []float64{0, 0, 10, 20, 30}
+	assert.Equal(t, 0.6, exceedanceProbability(sorted, 0))
+	assert.Equal(t, 0.4, exceedanceProbability(sorted, 10))
+	assert.Equal(t, 0.0, exceedanceProbability(sorted, 30))
+}
+
+func TestSimulateAnnualLosses(t *testing.T) {
+	q := repo.RiskQuantification{
+		LEFMin: 1, LEFMostLikely: 2, LEFMax: 4,
+		LMMin: 1000, LMMostLikely: 5000, LMMax: 20000,
+	}
+
+	losses := simulateAnnualLosses(q, 5000, 99)
+	require.Len(t, losses, 5000)
+	assert.True(t, sort.Float64sAreSorted(losses))
+	assert.Equal(t, losses, simulateAnnualLosses(q, 5000, 99), "same seed must reproduce the run")
+
+	// E[ALE] = E[LEF] * E[LM] with PERT means 2.17 and 6833
+	expected := (1 + 4*2 + 4) / 6.0 * (1000 + 4*5000 + 20000) / 6.0
+	assert.InDelta(t, expected, meanOf(losses), expected*0.05)
+
+	p10, p50, p90 := percentileOf(losses, 0.10), percentileOf(losses, 0.50), percentileOf(losses, 0.90)
+	assert.LessOrEqual(t, p10, p50)
+	assert.LessOrEqual(t, p50, p90)
+	assert.LessOrEqual(t, p90, losses[len(losses)-1])
+}
+
+func TestLossExceedanceCurve(t *testing.T) {
+	curve := lossExceedanceCurve([]float64{0, 50, 100}, 3)
+	require.Len(t, curve, 3)
+	assert.Equal(t, repo.LossExceedancePoint{Loss: 0, Probability: 0.6667}, curve[0])
+	assert.Equal(t, repo.LossExceedancePoint{Loss: 50, Probability: 0.3333}, curve[1])
+	assert.Equal(t, repo.LossExceedancePoint{Loss: 100, Probability: 0}, curve[2])
+
+	assert.Equal(t, []repo.LossExceedancePoint{{Loss: 0, Probability: 0}}, lossExceedanceCurve([]float64{0, 0}, 5))
+}
+
+func TestRiskQuantificationRequestValidation(t *testing.T) {
+	valid := func() dto.RiskQuantificationRequest {
+		return dto.RiskQuantificationRequest{
+			LEFMin: floatPtr(0.1), LEFMostLikely: floatPtr(0.5), LEFMax: floatPtr(2),
+			LMMin: floatPtr(10000), LMMostLikely: floatPtr(50000), LMMax: floatPtr(1e11),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(req *dto.RiskQuantificationRequest)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(req *dto.RiskQuantificationRequest) {}},
+		{name: "equal estimates", modify: func(req *dto.RiskQuantificationRequest) {
+			req.LMMin, req.LMMostLikely, req.LMMax = floatPtr(100), floatPtr(100), floatPtr(100)
+		}},
+		{name: "loss magnitude above column range", modify: func(req *dto.RiskQuantificationRequest) { req.LMMax = floatPtr(1e15) }, wantErr: true},
+		{name: "negative loss magnitude", modify: func(req *dto.RiskQuantificationRequest) { req.LMMin = floatPtr(-1) }, wantErr: true},
+		{name: "loss most likely below min", modify: func(req *dto.RiskQuantificationRequest) { req.LMMostLikely = floatPtr(5000) }, wantErr: true},
+		{name: "loss max below most likely", modify: func(req *dto.RiskQuantificationRequest) { req.LMMax = floatPtr(40000) }, wantErr: true},
+		{name: "frequency max below most likely", modify: func(req *dto.RiskQuantificationRequest) { req.LEFMax = floatPtr(0.2) }, wantErr: true},
+		{name: "frequency above limit", modify: func(req *dto.RiskQuantificationRequest) { req.LEFMax = floatPtr(20000) }, wantErr: true},
+		{name: "missing estimate", modify: func(req *dto.RiskQuantificationRequest) { req.LMMostLikely = nil }, wantErr: true},
+	}
+
+	v := validator.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+			err := v.Struct(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateEstimateRange(t *testing.T) {
+	tests := []struct {
+		name                 string
+		min, mostLikely, max float64
+		wantErr              bool
+	}{
+		{"ordered", 1, 2, 3, false},
+		{"all equal", 5, 5, 5, false},
+		{"most likely below min", 2, 1, 3, true},
+		{"max below most likely", 1, 3, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEstimateRange("lm", tt.min, tt.mostLikely, tt.max)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "lm", validationErr.Field)
+		})
+	}
+}
+
+// TestSimulateAnnualLosses_FitsStorage checks that the largest accepted inputs keep the simulated
+// annual losses inside the NUMERIC(18,2) columns they are stored in
+func TestSimulateAnnualLosses_FitsStorage(t *testing.T) {
+	q := repo.RiskQuantification{LEFMin: 10000, LEFMostLikely: 10000, LEFMax: 10000, LMMin: 1e11, LMMostLikely: 1e11, LMMax: 1e11}
+	losses := simulateAnnualLosses(q, 1000, 1)
+	require.Less(t, losses[len(losses)-1], 1e16)
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
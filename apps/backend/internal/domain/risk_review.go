@@ -0,0 +1,389 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// defaultRiskReviewIntervals applies to levels the tenant has not configured, in days
+var defaultRiskReviewIntervals = []repo.RiskReviewInterval{
+	{Scope: dto.RiskReviewScopeLevel, ScopeValue: dto.RiskLevelLabelLow, IntervalDays: 365},
+	{Scope: dto.RiskReviewScopeLevel, ScopeValue: dto.RiskLevelLabelMedium, IntervalDays: 180},
+	{Scope: dto.RiskReviewScopeLevel, ScopeValue: dto.RiskLevelLabelHigh, IntervalDays: 90},
+	{Scope: dto.RiskReviewScopeLevel, ScopeValue: dto.RiskLevelLabelCritical, IntervalDays: 30},
+}
+
+// Risk Review Intervals methods
+
+// GetReviewIntervals returns the tenant's category intervals and an interval for every level
+func (s *RiskService) GetReviewIntervals(ctx context.Context, tenantID string) ([]repo.RiskReviewInterval, error) {
+	configured, err := s.riskRepo.ListReviewIntervals(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return mergeReviewIntervals(tenantID, configured), nil
+}
+
+// mergeReviewIntervals keeps the configured category intervals and fills the levels
+// a tenant has not configured with the defaults
+func mergeReviewIntervals(tenantID string, configured []repo.RiskReviewInterval) []repo.RiskReviewInterval {
+	intervals := make([]repo.RiskReviewInterval, 0, len(configured)+len(defaultRiskReviewIntervals))
+	for _, interval := range configured {
+		if interval.Scope == dto.RiskReviewScopeCategory {
+			intervals = append(intervals, interval)
+		}
+	}
+	for _, def := range defaultRiskReviewIntervals {
+		interval := def
+		interval.TenantID = tenantID
+		for _, c := range configured {
+			if c.Scope == dto.RiskReviewScopeLevel && c.ScopeValue == def.ScopeValue {
+				interval = c
+				break
+			}
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals
+}
+
+// SetReviewInterval configures an interval and reschedules open risks of the tenant
+func (s *RiskService) SetReviewInterval(ctx context.Context, tenantID string, req dto.RiskReviewIntervalRequest, updatedBy string) error {
+	scopeValue := strings.TrimSpace(req.ScopeValue)
+	if req.Scope == dto.RiskReviewScopeLevel && !isRiskLevelLabel(scopeValue) {
+		return NewValidationError("scope_value", "must be one of Low, Medium, High, Critical")
+	}
+
+	interval := repo.RiskReviewInterval{
+		ID:           uuid.New().String(),
+		TenantID:     tenantID,
+		Scope:        req.Scope,
+		ScopeValue:   scopeValue,
+		IntervalDays: req.IntervalDays,
+	}
+	if err := s.riskRepo.UpsertReviewInterval(ctx, interval); err != nil {
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_review_interval", "risk", nil, interval)
+	return s.rescheduleTenantReviews(ctx, tenantID)
+}
+
+func (s *RiskService) DeleteReviewInterval(ctx context.Context, tenantID, intervalID, deletedBy string) error {
+	if err := s.riskRepo.DeleteReviewInterval(ctx, tenantID, intervalID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReviewIntervalNotFound
+		}
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, deletedBy, "delete_review_interval", "risk", &intervalID, nil)
+	return s.rescheduleTenantReviews(ctx, tenantID)
+}
+
+// Risk Reviews methods
+
+// ReviewRisk re-confirms or updates the likelihood and impact of a risk, records the
+// review in risk_history and schedules the next review
+func (s *RiskService) ReviewRisk(ctx context.Context, riskID, tenantID string, req dto.RiskReviewRequest, reviewerID string) (*repo.Risk, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if risk.Status == dto.RiskStatusClosed {
+		return nil, NewValidationError("status", "closed risks are not reviewed")
+	}
+
+	oldAssessment := formatRiskAssessment(risk.Likelihood, risk.Impact)
+	likelihood, impact := 0, 0
+	if risk.Likelihood != nil {
+		likelihood = *risk.Likelihood
+	}
+	if risk.Impact != nil {
+		impact = *risk.Impact
+	}
+	if req.Likelihood != nil {
+		likelihood = *req.Likelihood
+	}
+	if req.Impact != nil {
+		impact = *req.Impact
+	}
+	if likelihood == 0 || impact == 0 {
+		return nil, NewValidationError("likelihood", "likelihood and impact are required for an unassessed risk")
+	}
+
+	now := time.Now()
+	outcome := dto.RiskReviewOutcomeConfirmed
+	if risk.Likelihood == nil || risk.Impact == nil || *risk.Likelihood != likelihood || *risk.Impact != impact {
+		outcome = dto.RiskReviewOutcomeUpdated
+
+		oldLikelihood, oldImpact := risk.Likelihood, risk.Impact
+		oldLevel := risk.Level
+		wasOutOfTolerance := RiskAppetiteStatus(risk) == dto.RiskAppetiteStatusOutOfTolerance
+		level, _ := dto.CalculateRiskLevel(likelihood, impact)
+		risk.Likelihood = &likelihood
+		risk.Impact = &impact
+		risk.Level = &level
+		if err := s.riskRepo.Update(ctx, *risk); err != nil {
+			return nil, err
+		}
+		s.checkRiskAppetite(ctx, risk.ID, wasOutOfTolerance)
+
+		s.recordReviewFieldChange(ctx, risk.ID, "likelihood", oldLikelihood, likelihood, req.Comment, reviewerID, now)
+		s.recordReviewFieldChange(ctx, risk.ID, "impact", oldImpact, impact, req.Comment, reviewerID, now)
+		s.recordLevelChange(ctx, risk.ID, oldLevel, level, req.Comment, reviewerID)
+		if oldLevel != nil && *oldLevel != level {
+			s.checkRiskLevelEscalation(ctx, risk, *oldLevel, level)
+		}
+	}
+
+	newAssessment := formatRiskAssessment(risk.Likelihood, risk.Impact)
+	comment := req.Comment
+	if err := s.riskRepo.AddHistory(ctx, repo.RiskHistory{
+		ID:           uuid.New().String(),
+		RiskID:       risk.ID,
+		FieldChanged: "review",
+		OldValue:     oldAssessment,
+		NewValue:     newAssessment,
+		ChangeReason: &comment,
+		ChangedBy:    reviewerID,
+		ChangedAt:    now,
+	}); err != nil {
+		return nil, err
+	}
+
+	risk.LastReviewedAt = &now
+	risk.LastReviewedBy = &reviewerID
+	intervals, err := s.GetReviewIntervals(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	nextReview := nextRiskReviewDate(risk, intervals)
+	if err := s.riskRepo.RecordReview(ctx, risk.ID, reviewerID, now, &nextReview); err != nil {
+		return nil, err
+	}
+	risk.NextReviewDate = &nextReview
+
+	s.auditRepo.LogAction(ctx, tenantID, reviewerID, "review", "risk", &riskID, map[string]interface{}{
+		"outcome":          outcome,
+		"old_assessment":   oldAssessment,
+		"new_assessment":   newAssessment,
+		"comment":          req.Comment,
+		"next_review_date": nextReview.Format("2006-01-02"),
+	})
+
+	return risk, nil
+}
+
+// GetReviews returns the review entries of the risk history, newest first
+func (s *RiskService) GetReviews(ctx context.Context, riskID, tenantID string) ([]repo.RiskHistory, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.riskRepo.GetHistory(ctx, riskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []repo.RiskHistory
+	for _, entry := range history {
+		if entry.FieldChanged == "review" {
+			reviews = append(reviews, entry)
+		}
+	}
+	return reviews, nil
+}
+
+// GetReviewQueue returns open risks due for review within daysAhead days, overdue first
+func (s *RiskService) GetReviewQueue(ctx context.Context, tenantID string, daysAhead int) ([]repo.Risk, error) {
+	return s.riskRepo.ListReviewQueue(ctx, tenantID, riskToday().AddDate(0, 0, daysAhead))
+}
+
+// SetNotifier connects the inbox used to remind risk owners about due reviews
+func (s *RiskService) SetNotifier(notifier UserNotifierInterface) {
+	s.notifier = notifier
+}
+
+// ProcessReviewReminders schedules reviews for risks that have none and sends the owner
+// one reminder per due date for risks that are due for review. Risks without an owner
+// are not marked as reminded and stay in the review queue only.
+func (s *RiskService) ProcessReviewReminders(ctx context.Context) (int, error) {
+	unscheduled, err := s.riskRepo.ListRisksWithoutReviewDate(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := range unscheduled {
+		if err := s.scheduleReview(ctx, &unscheduled[i]); err != nil {
+			log.Printf("ERROR: RiskService.ProcessReviewReminders schedule %s: %v", unscheduled[i].ID, err)
+		}
+	}
+
+	if s.notifier == nil {
+		return 0, nil
+	}
+
+	today := riskToday()
+	due, err := s.riskRepo.ListRisksDueForReviewReminder(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	reminded := 0
+	for _, risk := range due {
+		if risk.OwnerUserID == nil || *risk.OwnerUserID == "" {
+			continue
+		}
+
+		dueDate := risk.NextReviewDate.Format("02.01.2006")
+		message := fmt.Sprintf("Риск «%s» необходимо пересмотреть до %s.", risk.Title, dueDate)
+		if daysOverdue := int(today.Sub(*risk.NextReviewDate).Hours() / 24); daysOverdue > 0 {
+			message = fmt.Sprintf("Пересмотр риска «%s» просрочен на %d дн. (срок %s).", risk.Title, daysOverdue, dueDate)
+		}
+		if err := s.notifier.Notify(ctx, risk.TenantID, *risk.OwnerUserID, dto.UserNotificationRiskReviewDue,
+			"Требуется пересмотр риска", message, "risk", risk.ID); err != nil {
+			log.Printf("ERROR: RiskService.ProcessReviewReminders notify owner of %s: %v", risk.ID, err)
+			continue
+		}
+
+		s.auditRepo.LogAction(ctx, risk.TenantID, "", "review_reminder", "risk", &risk.ID, map[string]interface{}{
+			"owner_user_id":    *risk.OwnerUserID,
+			"next_review_date": risk.NextReviewDate.Format("2006-01-02"),
+		})
+
+		if err := s.riskRepo.MarkReviewReminded(ctx, risk.ID, today); err != nil {
+			log.Printf("ERROR: RiskService.ProcessReviewReminders mark %s: %v", risk.ID, err)
+			continue
+		}
+		reminded++
+	}
+
+	return reminded, nil
+}
+
+// scheduleReview sets the next review date of a risk from its last review (or creation)
+func (s *RiskService) scheduleReview(ctx context.Context, risk *repo.Risk) error {
+	intervals, err := s.GetReviewIntervals(ctx, risk.TenantID)
+	if err != nil {
+		return err
+	}
+	nextReview := nextRiskReviewDate(risk, intervals)
+	if err := s.riskRepo.SetNextReviewDate(ctx, risk.ID, &nextReview); err != nil {
+		return err
+	}
+	risk.NextReviewDate = &nextReview
+	return nil
+}
+
+func (s *RiskService) rescheduleTenantReviews(ctx context.Context, tenantID string) error {
+	intervals, err := s.GetReviewIntervals(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	risks, err := s.riskRepo.ListWithFilters(ctx, tenantID, map[string]interface{}{}, "created_at", "asc")
+	if err != nil {
+		return err
+	}
+	for i := range risks {
+		if risks[i].Status == dto.RiskStatusClosed {
+			continue
+		}
+		nextReview := nextRiskReviewDate(&risks[i], intervals)
+		if risks[i].NextReviewDate != nil && risks[i].NextReviewDate.Equal(nextReview) {
+			continue
+		}
+		if err := s.riskRepo.SetNextReviewDate(ctx, risks[i].ID, &nextReview); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordLevelChange writes a "level" snapshot to risk_history
+func (s *RiskService) recordLevelChange(ctx context.Context, riskID string, oldLevel *int, newLevel int, reason, changedBy string) {
+	s.recordReviewFieldChange(ctx, riskID, "level", oldLevel, newLevel, reason, changedBy, time.Now())
+}
+
+func (s *RiskService) recordReviewFieldChange(ctx context.Context, riskID, field string, oldValue *int, newValue int, reason, changedBy string, changedAt time.Time) {
+	if oldValue != nil && *oldValue == newValue {
+		return
+	}
+	var old *string
+	if oldValue != nil {
+		old = riskStringPtr(strconv.Itoa(*oldValue))
+	}
+	if err := s.riskRepo.AddHistory(ctx, repo.RiskHistory{
+		ID:           uuid.New().String(),
+		RiskID:       riskID,
+		FieldChanged: field,
+		OldValue:     old,
+		NewValue:     riskStringPtr(strconv.Itoa(newValue)),
+		ChangeReason: &reason,
+		ChangedBy:    changedBy,
+		ChangedAt:    changedAt,
+	}); err != nil {
+		log.Printf("WARNING: failed to record risk history for risk %s: %v", riskID, err)
+	}
+}
+
+// riskReviewIntervalDays picks the category interval first, then the level interval
+func riskReviewIntervalDays(risk *repo.Risk, intervals []repo.RiskReviewInterval) int {
+	if risk.Category != nil {
+		for _, interval := range intervals {
+			if interval.Scope == dto.RiskReviewScopeCategory && strings.EqualFold(interval.ScopeValue, *risk.Category) {
+				return interval.IntervalDays
+			}
+		}
+	}
+	_, label := riskLevelWithLabel(risk)
+	for _, interval := range intervals {
+		if interval.Scope == dto.RiskReviewScopeLevel && interval.ScopeValue == label {
+			return interval.IntervalDays
+		}
+	}
+	return defaultRiskReviewIntervals[0].IntervalDays
+}
+
+func nextRiskReviewDate(risk *repo.Risk, intervals []repo.RiskReviewInterval) time.Time {
+	base := risk.CreatedAt
+	if risk.LastReviewedAt != nil {
+		base = *risk.LastReviewedAt
+	}
+	base = base.UTC()
+	return time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, time.UTC).
+		AddDate(0, 0, riskReviewIntervalDays(risk, intervals))
+}
+
+// formatRiskAssessment renders likelihood and impact as "LxI" for risk_history
+func formatRiskAssessment(likelihood, impact *int) *string {
+	if likelihood == nil || impact == nil {
+		return nil
+	}
+	return riskStringPtr(fmt.Sprintf("%dx%d", *likelihood, *impact))
+}
+
+func isRiskLevelLabel(label string) bool {
+	switch label {
+	case dto.RiskLevelLabelLow, dto.RiskLevelLabelMedium, dto.RiskLevelLabelHigh, dto.RiskLevelLabelCritical:
+		return true
+	}
+	return false
+}
+
+func sameString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeReviewIntervals(t *testing.T) {
+	configured := []repo.RiskReviewInterval{
+		{ID: "interval-1", Scope: dto.RiskReviewScopeCategory, ScopeValue: "Compliance", IntervalDays: 60},
+		{ID: "interval-2", Scope: dto.RiskReviewScopeLevel, ScopeValue: dto.RiskLevelLabelCritical, IntervalDays: 14},
+	}
+
+	intervals := mergeReviewIntervals("tenant-1", configured)
+	require.Len(t, intervals, 1+len(defaultRiskReviewIntervals))
+	assert.Equal(t, "interval-1", intervals[0].ID, "category intervals come first")
+
+	levels := map[string]repo.RiskReviewInterval{}
+	for _, interval := range intervals[1:] {
+		assert.Equal(t, dto.RiskReviewScopeLevel, interval.Scope)
+		levels[interval.ScopeValue] = interval
+	}
+	assert.Equal(t, 14, levels[dto.RiskLevelLabelCritical].IntervalDays)
+	assert.Equal(t, 90, levels[dto.RiskLevelLabelHigh].IntervalDays)
+	assert.Equal(t, "tenant-1", levels[dto.RiskLevelLabelHigh].TenantID)
+	assert.Empty(t, defaultRiskReviewIntervals[2].TenantID, "merging does not modify the defaults")
+}
+
+func TestRiskReviewIntervalDays(t *testing.T) {
+	intervals := mergeReviewIntervals("tenant-1", []repo.RiskReviewInterval{
+		{Scope: dto.RiskReviewScopeCategory, ScopeValue: "Compliance", IntervalDays: 60},
+	})
+
+	tests := []struct {
+		name string
+		risk repo.Risk
+		want int
+	}{
+		{"low level", repo.Risk{Likelihood: intPtr(1), Impact: intPtr(1)}, 365},
+		{"medium level", repo.Risk{Likelihood: intPtr(2), Impact: intPtr(2)}, 180},
+		{"high level", repo.Risk{Likelihood: intPtr(2), Impact: intPtr(3)}, 90},
+		{"critical level", repo.Risk{Likelihood: intPtr(4), Impact: intPtr(2)}, 30},
+		{"category wins over level", repo.Risk{Category: riskStringPtr("Compliance"), Likelihood: intPtr(4), Impact: intPtr(2)}, 60},
+		{"category matches case-insensitively", repo.Risk{Category: riskStringPtr("compliance"), Likelihood: intPtr(1), Impact: intPtr(1)}, 60},
+		{"unconfigured category falls back to level", repo.Risk{Category: riskStringPtr("IT"), Likelihood: intPtr(4), Impact: intPtr(2)}, 30},
+		{"not assessed", repo.Risk{}, 365},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, riskReviewIntervalDays(&tt.risk, intervals))
+		})
+	}
+
+	assert.Equal(t, defaultRiskReviewIntervals[0].IntervalDays, riskReviewIntervalDays(&repo.Risk{Level: intPtr(8)}, nil),
+		"a risk is still scheduled when no interval matches")
+}
+
+func TestNextRiskReviewDate(t *testing.T) {
+	intervals := mergeReviewIntervals("tenant-1", nil)
+	createdAt := time.Date(2026, 1, 15, 18, 30, 0, 0, time.UTC)
+	reviewedAt := time.Date(2026, 3, 1, 23, 30, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	tests := []struct {
+		name string
+		risk repo.Risk
+		want time.Time
+	}{
+		{
+			name: "from creation",
+			risk: repo.Risk{Likelihood: intPtr(4), Impact: intPtr(2), CreatedAt: createdAt},
+			want: time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "from last review in UTC",
+			risk: repo.Risk{Likelihood: intPtr(4), Impact: intPtr(2), CreatedAt: createdAt, LastReviewedAt: &reviewedAt},
+			want: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "low risk once a year",
+			risk: repo.Risk{Likelihood: intPtr(1), Impact: intPtr(1), CreatedAt: createdAt},
+			want: time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextRiskReviewDate(&tt.risk, intervals))
+		})
+	}
+}
+
+func TestFormatRiskAssessment(t *testing.T) {
+	assert.Equal(t, "3x2", *formatRiskAssessment(intPtr(3), intPtr(2)))
+	assert.Nil(t, formatRiskAssessment(intPtr(3), nil))
+	assert.Nil(t, formatRiskAssessment(nil, nil))
+}
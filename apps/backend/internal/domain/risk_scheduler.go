@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunScheduler periodically runs the background jobs of the risk module until ctx is cancelled
+func (s *RiskService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.runScheduledJobs(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runScheduledJobs(ctx)
+		}
+	}
+}
+
+func (s *RiskService) runScheduledJobs(ctx context.Context) {
+	expired, err := s.ProcessExpiredAcceptances(ctx)
+	if err != nil {
+		log.Printf("ERROR: RiskService scheduler expired acceptances: %v", err)
+	} else if expired > 0 {
+		log.Printf("DEBUG: RiskService scheduler expired %d risk acceptances", expired)
+	}
+
+	reminded, err := s.ProcessReviewReminders(ctx)
+	if err != nil {
+		log.Printf("ERROR: RiskService scheduler review reminders: %v", err)
+	} else if reminded > 0 {
+		log.Printf("DEBUG: RiskService scheduler sent %d risk review reminders", reminded)
+	}
+}
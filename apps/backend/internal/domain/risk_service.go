@@ -17,38 +17,48 @@ import (
 type RiskService struct {
 	riskRepo               *repo.RiskRepo
 	auditRepo              *repo.AuditRepo
+	userRepo               UserRepoInterface
 	documentStorageService DocumentStorageServiceInterface
+	notifier               UserNotifierInterface
 }
 
-func NewRiskService(riskRepo *repo.RiskRepo, auditRepo *repo.AuditRepo, documentStorageService DocumentStorageServiceInterface) *RiskService {
+func NewRiskService(riskRepo *repo.RiskRepo, auditRepo *repo.AuditRepo, userRepo UserRepoInterface, documentStorageService DocumentStorageServiceInterface) *RiskService {
 	return &RiskService{
 		riskRepo:               riskRepo,
 		auditRepo:              auditRepo,
+		userRepo:               userRepo,
 		documentStorageService: documentStorageService,
 	}
 }
 
-func (s *RiskService) CreateRisk(ctx context.Context, tenantID, title string, description, category *string, likelihood, impact int, ownerUserID, assetID *string, methodology, strategy *string, dueDate *time.Time) (*repo.Risk, error) {
+func (s *RiskService) CreateRisk(ctx context.Context, tenantID, title string, description, category *string, likelihood, impact int, ownerUserID, assetID, threatID, vulnerabilityID *string, methodology, strategy *string, dueDate *time.Time) (*repo.Risk, error) {
+	threatID, vulnerabilityID = nilIfBlank(threatID), nilIfBlank(vulnerabilityID)
+	if err := s.validateRiskScenario(ctx, tenantID, threatID, vulnerabilityID); err != nil {
+		return nil, err
+	}
+
 	// Calculate risk level automatically
 	level, _ := dto.CalculateRiskLevel(likelihood, impact)
 
 	risk := repo.Risk{
-		ID:          uuid.New().String(),
-		TenantID:    tenantID,
-		Title:       title,
-		Description: description,
-		Category:    category,
-		Likelihood:  &likelihood,
-		Impact:      &impact,
-		Level:       &level,
-		Status:      dto.RiskStatusNew,
-		OwnerUserID: ownerUserID,
-		AssetID:     assetID,
-		Methodology: methodology,
-		Strategy:    strategy,
-		DueDate:     dueDate,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		Title:           title,
+		Description:     description,
+		Category:        category,
+		Likelihood:      &likelihood,
+		Impact:          &impact,
+		Level:           &level,
+		Status:          dto.RiskStatusNew,
+		OwnerUserID:     ownerUserID,
+		AssetID:         assetID,
+		ThreatID:        threatID,
+		VulnerabilityID: vulnerabilityID,
+		Methodology:     methodology,
+		Strategy:        strategy,
+		DueDate:         dueDate,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
 	err := s.riskRepo.Create(ctx, risk)
@@ -56,6 +66,11 @@ func (s *RiskService) CreateRisk(ctx context.Context, tenantID, title string, de
 		return nil, err
 	}
 
+	if err := s.scheduleReview(ctx, &risk); err != nil {
+		log.Printf("WARNING: failed to schedule review for risk %s: %v", risk.ID, err)
+	}
+	s.checkRiskAppetite(ctx, risk.ID, false)
+
 	// Log audit
 	s.auditRepo.LogAction(ctx, tenantID, "system", "create", "risk", &risk.ID, risk)
 
@@ -70,7 +85,7 @@ func (s *RiskService) ListRisks(ctx context.Context, tenantID string, filters ma
 	return s.riskRepo.ListWithFilters(ctx, tenantID, filters, sortField, sortDirection)
 }
 
-func (s *RiskService) UpdateRisk(ctx context.Context, id, title string, description, category *string, likelihood, impact int, ownerUserID, assetID *string, methodology, strategy *string, dueDate *time.Time) error {
+func (s *RiskService) UpdateRisk(ctx context.Context, id, title string, description, category *string, likelihood, impact int, ownerUserID, assetID, threatID, vulnerabilityID *string, methodology, strategy *string, dueDate *time.Time, updatedBy string) error {
 	risk, err := s.riskRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -79,8 +94,15 @@ func (s *RiskService) UpdateRisk(ctx context.Context, id, title string, descript
 		return nil
 	}
 
+	threatID, vulnerabilityID = nilIfBlank(threatID), nilIfBlank(vulnerabilityID)
+	if err := s.validateRiskScenario(ctx, risk.TenantID, threatID, vulnerabilityID); err != nil {
+		return err
+	}
+
 	// Calculate new risk level if likelihood or impact changed
 	oldLevel := risk.Level
+	oldCategory := risk.Category
+	wasOutOfTolerance := RiskAppetiteStatus(risk) == dto.RiskAppetiteStatusOutOfTolerance
 	level, _ := dto.CalculateRiskLevel(likelihood, impact)
 
 	risk.Title = title
@@ -91,6 +113,8 @@ func (s *RiskService) UpdateRisk(ctx context.Context, id, title string, descript
 	risk.Level = &level
 	risk.OwnerUserID = ownerUserID
 	risk.AssetID = assetID
+	risk.ThreatID = threatID
+	risk.VulnerabilityID = vulnerabilityID
 	risk.Methodology = methodology
 	risk.Strategy = strategy
 	risk.DueDate = dueDate
@@ -101,6 +125,14 @@ func (s *RiskService) UpdateRisk(ctx context.Context, id, title string, descript
 		return err
 	}
 
+	// Периодичность пересмотра зависит от категории и уровня
+	if (oldLevel == nil || *oldLevel != level) || !sameString(oldCategory, category) {
+		if err := s.scheduleReview(ctx, risk); err != nil {
+			log.Printf("WARNING: failed to reschedule review for risk %s: %v", id, err)
+		}
+		s.checkRiskAppetite(ctx, id, wasOutOfTolerance)
+	}
+
 	// Log audit with level change if applicable
 	auditData := map[string]interface{}{
 		"title":      title,
@@ -118,29 +150,43 @@ func (s *RiskService) UpdateRisk(ctx context.Context, id, title string, descript
 		s.checkRiskLevelEscalation(ctx, risk, *oldLevel, level)
 	}
 
+	// Снимок уровня в истории используется для аналитики динамики рисков
+	s.recordLevelChange(ctx, risk.ID, oldLevel, level, "Изменена оценка риска", updatedBy)
+
 	s.auditRepo.LogAction(ctx, risk.TenantID, "system", "update", "risk", &id, auditData)
 
 	return nil
 }
 
-func (s *RiskService) UpdateRiskStatus(ctx context.Context, id, status string) error {
-	risk, err := s.riskRepo.GetByID(ctx, id)
+func (s *RiskService) UpdateRiskStatus(ctx context.Context, id, tenantID, status, updatedBy string) error {
+	risk, err := s.getTenantRisk(ctx, id, tenantID)
 	if err != nil {
 		return err
 	}
-	if risk == nil {
+	if risk.Status == status {
 		return nil
 	}
 
-	err = s.riskRepo.UpdateStatus(ctx, id, status)
-	if err != nil {
-		return err
+	// Статус "принят" выставляется только через утверждённое решение о принятии
+	if status == dto.RiskStatusAccepted && risk.Status != dto.RiskStatusAccepted {
+		active, err := s.getActiveAcceptance(ctx, id)
+		if err != nil {
+			return err
+		}
+		if active == nil {
+			return ErrRiskAcceptanceRequired
+		}
 	}
 
-	// Log audit
-	s.auditRepo.LogAction(ctx, risk.TenantID, "system", "update_status", "risk", &id, map[string]string{"status": status})
+	// Риск вне толерантности нельзя закрыть без плана обработки или принятия
+	if status == dto.RiskStatusMitigated || status == dto.RiskStatusClosed {
+		if err := s.ensureRiskWithinTolerance(ctx, risk); err != nil {
+			return err
+		}
+	}
 
-	return nil
+	// Запись статуса в истории нужна для восстановления динамики рисков в аналитике
+	return s.changeRiskStatus(ctx, risk, status, "Изменён статус риска", updatedBy)
 }
 
 func (s *RiskService) DeleteRisk(ctx context.Context, id string) error {
@@ -400,30 +446,40 @@ func (s *RiskService) checkRiskLevelEscalation(ctx context.Context, risk *repo.R
 		log.Printf("WARNING: Risk level escalation detected - Risk ID: %s, Old Level: %d, New Level: %d, Type: %s",
 			risk.ID, oldLevel, newLevel, escalationType)
 
-		// Create notification data
-		notificationData := map[string]interface{}{
-			"risk_id":         risk.ID,
-			"risk_title":      risk.Title,
-			"old_level":       oldLevel,
-			"new_level":       newLevel,
-			"escalation_type": escalationType,
-			"tenant_id":       risk.TenantID,
-		}
-
-		// Log audit for escalation
-		s.auditRepo.LogAction(ctx, risk.TenantID, "system", "risk_escalation", "risk", &risk.ID, notificationData)
-
-		// TODO: In a real implementation, you would:
-		// 1. Send email notifications to risk owners and management
-		// 2. Create in-app notifications
-		// 3. Send Slack/Teams notifications
-		// 4. Trigger automated workflows
+		s.notifyRiskEscalation(ctx, risk, escalationType, map[string]interface{}{
+			"old_level": oldLevel,
+			"new_level": newLevel,
+		})
 
 		log.Printf("NOTIFICATION: Risk '%s' escalated to %s level (%d). Consider immediate action.",
 			risk.Title, escalationType, newLevel)
 	}
 }
 
+// notifyRiskEscalation records a risk escalation event. Level changes and KRI threshold
+// breaches both go through here.
+func (s *RiskService) notifyRiskEscalation(ctx context.Context, risk *repo.Risk, escalationType string, details map[string]interface{}) {
+	// Create notification data
+	notificationData := map[string]interface{}{
+		"risk_id":         risk.ID,
+		"risk_title":      risk.Title,
+		"escalation_type": escalationType,
+		"tenant_id":       risk.TenantID,
+	}
+	for key, value := range details {
+		notificationData[key] = value
+	}
+
+	// Log audit for escalation
+	s.auditRepo.LogAction(ctx, risk.TenantID, "system", "risk_escalation", "risk", &risk.ID, notificationData)
+
+	// TODO: In a real implementation, you would:
+	// 1. Send email notifications to risk owners and management
+	// 2. Create in-app notifications
+	// 3. Send Slack/Teams notifications
+	// 4. Trigger automated workflows
+}
+
 // Risk Document methods - использование централизованного хранилища
 func (s *RiskService) UploadRiskDocument(ctx context.Context, riskID, tenantID string, file multipart.File, header *multipart.FileHeader, req dto.UploadDocumentDTO, uploadedBy string) (*dto.DocumentDTO, error) {
 	log.Printf("DEBUG: risk_service.UploadRiskDocument riskID=%s", riskID)
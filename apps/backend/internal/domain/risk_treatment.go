@@ -0,0 +1,576 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// Risk Treatment Plans methods
+
+func (s *RiskService) CreateTreatmentPlan(ctx context.Context, riskID, tenantID string, req dto.RiskTreatmentPlanRequest, createdBy string) (*repo.RiskTreatmentPlan, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, err := parseRiskDate("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	dueDate, err := parseRiskDate("due_date", req.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	status := dto.TreatmentPlanStatusDraft
+	if req.Status != nil {
+		status = *req.Status
+	}
+	currency := dto.DefaultTreatmentCurrency
+	if req.Currency != nil {
+		currency = *req.Currency
+	}
+
+	plan := repo.RiskTreatmentPlan{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		RiskID:      riskID,
+		Title:       req.Title,
+		Description: req.Description,
+		Strategy:    req.Strategy,
+		Status:      status,
+		OwnerUserID: req.OwnerUserID,
+		Budget:      req.Budget,
+		Currency:    currency,
+		StartDate:   startDate,
+		DueDate:     dueDate,
+		CreatedBy:   &createdBy,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.riskRepo.CreateTreatmentPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, createdBy, "create_treatment_plan", "risk", &riskID, map[string]interface{}{
+		"plan_id":  plan.ID,
+		"title":    plan.Title,
+		"strategy": plan.Strategy,
+		"status":   plan.Status,
+	})
+
+	if err := s.recalculateTreatment(ctx, risk, createdBy); err != nil {
+		return nil, err
+	}
+
+	return s.riskRepo.GetTreatmentPlan(ctx, plan.ID)
+}
+
+func (s *RiskService) GetTreatmentPlans(ctx context.Context, riskID, tenantID string) ([]repo.RiskTreatmentPlan, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.riskRepo.ListTreatmentPlans(ctx, riskID)
+}
+
+func (s *RiskService) GetTreatmentPlan(ctx context.Context, riskID, planID, tenantID string) (*repo.RiskTreatmentPlan, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.getRiskTreatmentPlan(ctx, riskID, planID)
+}
+
+func (s *RiskService) GetTreatmentTasks(ctx context.Context, riskID, planID, tenantID string) ([]repo.RiskTreatmentTask, error) {
+	if _, err := s.GetTreatmentPlan(ctx, riskID, planID, tenantID); err != nil {
+		return nil, err
+	}
+	return s.riskRepo.ListTreatmentTasks(ctx, planID)
+}
+
+func (s *RiskService) UpdateTreatmentPlan(ctx context.Context, riskID, planID, tenantID string, req dto.RiskTreatmentPlanRequest, updatedBy string) (*repo.RiskTreatmentPlan, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.getRiskTreatmentPlan(ctx, riskID, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, err := parseRiskDate("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	dueDate, err := parseRiskDate("due_date", req.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Title = req.Title
+	plan.Description = req.Description
+	plan.Strategy = req.Strategy
+	plan.OwnerUserID = req.OwnerUserID
+	plan.Budget = req.Budget
+	plan.StartDate = startDate
+	plan.DueDate = dueDate
+	if req.Currency != nil {
+		plan.Currency = *req.Currency
+	}
+	if req.Status != nil {
+		plan.Status = *req.Status
+	}
+	plan.UpdatedAt = time.Now()
+
+	if err := s.riskRepo.UpdateTreatmentPlan(ctx, *plan); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_treatment_plan", "risk", &riskID, map[string]interface{}{
+		"plan_id":  plan.ID,
+		"title":    plan.Title,
+		"strategy": plan.Strategy,
+		"status":   plan.Status,
+	})
+
+	if err := s.recalculateTreatment(ctx, risk, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return s.riskRepo.GetTreatmentPlan(ctx, planID)
+}
+
+func (s *RiskService) DeleteTreatmentPlan(ctx context.Context, riskID, planID, tenantID, deletedBy string) error {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getRiskTreatmentPlan(ctx, riskID, planID); err != nil {
+		return err
+	}
+
+	if err := s.riskRepo.DeleteTreatmentPlan(ctx, planID); err != nil {
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, deletedBy, "delete_treatment_plan", "risk", &riskID, map[string]interface{}{
+		"plan_id": planID,
+	})
+
+	return s.recalculateTreatment(ctx, risk, deletedBy)
+}
+
+// Risk Treatment Tasks methods
+
+func (s *RiskService) AddTreatmentTask(ctx context.Context, riskID, planID, tenantID string, req dto.RiskTreatmentTaskRequest, createdBy string) (*repo.RiskTreatmentTask, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.getRiskTreatmentPlan(ctx, riskID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if isTreatmentPlanClosed(plan.Status) {
+		return nil, ErrTreatmentPlanClosed
+	}
+
+	dueDate, err := parseRiskDate("due_date", req.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	task := repo.RiskTreatmentTask{
+		ID:             uuid.New().String(),
+		PlanID:         planID,
+		Title:          req.Title,
+		Description:    req.Description,
+		AssigneeUserID: req.AssigneeUserID,
+		DueDate:        dueDate,
+		Status:         dto.TreatmentTaskStatusOpen,
+		Cost:           req.Cost,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if err := s.riskRepo.AddTreatmentTask(ctx, task); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, createdBy, "add_treatment_task", "risk", &riskID, map[string]interface{}{
+		"plan_id": planID,
+		"task_id": task.ID,
+		"title":   task.Title,
+	})
+
+	if err := s.recalculateTreatment(ctx, risk, createdBy); err != nil {
+		return nil, err
+	}
+
+	return s.riskRepo.GetTreatmentTask(ctx, task.ID)
+}
+
+func (s *RiskService) UpdateTreatmentTask(ctx context.Context, riskID, planID, taskID, tenantID string, req dto.RiskTreatmentTaskRequest, updatedBy string) (*repo.RiskTreatmentTask, error) {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getRiskTreatmentPlan(ctx, riskID, planID); err != nil {
+		return nil, err
+	}
+	task, err := s.getPlanTreatmentTask(ctx, planID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate, err := parseRiskDate("due_date", req.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Title = req.Title
+	task.Description = req.Description
+	task.AssigneeUserID = req.AssigneeUserID
+	task.DueDate = dueDate
+	task.Cost = req.Cost
+	task.UpdatedAt = time.Now()
+
+	if err := s.riskRepo.UpdateTreatmentTask(ctx, *task); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_treatment_task", "risk", &riskID, map[string]interface{}{
+		"plan_id": planID,
+		"task_id": taskID,
+		"title":   task.Title,
+	})
+
+	return s.riskRepo.GetTreatmentTask(ctx, taskID)
+}
+
+// UpdateTreatmentTaskStatus moves a task through open → in_progress → done/cancelled.
+// Closing a task as done requires completion evidence (a note or a linked document).
+func (s *RiskService) UpdateTreatmentTaskStatus(ctx context.Context, riskID, planID, taskID, tenantID string, req dto.RiskTreatmentTaskStatusRequest, updatedBy string) (*repo.RiskTreatmentTask, error) {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.getRiskTreatmentPlan(ctx, riskID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status == dto.TreatmentPlanStatusCancelled {
+		return nil, ErrTreatmentPlanClosed
+	}
+	task, err := s.getPlanTreatmentTask(ctx, planID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Evidence != nil {
+		task.Evidence = req.Evidence
+	}
+	if req.EvidenceDocumentID != nil {
+		task.EvidenceDocumentID = req.EvidenceDocumentID
+	}
+
+	oldStatus := task.Status
+	task.Status = req.Status
+	switch req.Status {
+	case dto.TreatmentTaskStatusDone:
+		if isBlank(task.Evidence) && isBlank(task.EvidenceDocumentID) {
+			return nil, ErrTreatmentEvidenceRequired
+		}
+		now := time.Now()
+		task.CompletedAt = &now
+		task.CompletedBy = &updatedBy
+	case dto.TreatmentTaskStatusCancelled:
+		now := time.Now()
+		task.CompletedAt = &now
+		task.CompletedBy = &updatedBy
+	default:
+		task.CompletedAt = nil
+		task.CompletedBy = nil
+	}
+	task.UpdatedAt = time.Now()
+
+	if err := s.riskRepo.UpdateTreatmentTask(ctx, *task); err != nil {
+		return nil, err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "update_treatment_task_status", "risk", &riskID, map[string]interface{}{
+		"plan_id":    planID,
+		"task_id":    taskID,
+		"old_status": oldStatus,
+		"new_status": task.Status,
+	})
+
+	if err := s.recalculateTreatment(ctx, risk, updatedBy); err != nil {
+		return nil, err
+	}
+
+	return s.riskRepo.GetTreatmentTask(ctx, taskID)
+}
+
+func (s *RiskService) DeleteTreatmentTask(ctx context.Context, riskID, planID, taskID, tenantID, deletedBy string) error {
+	risk, err := s.getTenantRisk(ctx, riskID, tenantID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getRiskTreatmentPlan(ctx, riskID, planID); err != nil {
+		return err
+	}
+	if _, err := s.getPlanTreatmentTask(ctx, planID, taskID); err != nil {
+		return err
+	}
+
+	if err := s.riskRepo.DeleteTreatmentTask(ctx, taskID); err != nil {
+		return err
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, deletedBy, "delete_treatment_task", "risk", &riskID, map[string]interface{}{
+		"plan_id": planID,
+		"task_id": taskID,
+	})
+
+	return s.recalculateTreatment(ctx, risk, deletedBy)
+}
+
+func (s *RiskService) ReorderTreatmentTasks(ctx context.Context, riskID, planID, tenantID string, taskIDs []string, updatedBy string) error {
+	if _, err := s.getTenantRisk(ctx, riskID, tenantID); err != nil {
+		return err
+	}
+	if _, err := s.getRiskTreatmentPlan(ctx, riskID, planID); err != nil {
+		return err
+	}
+
+	if err := s.riskRepo.ReorderTreatmentTasks(ctx, planID, taskIDs); err != nil {
+		return fmt.Errorf("failed to reorder treatment tasks: %w", err)
+	}
+
+	s.auditRepo.LogAction(ctx, tenantID, updatedBy, "reorder_treatment_tasks", "risk", &riskID, map[string]interface{}{
+		"plan_id":  planID,
+		"task_ids": taskIDs,
+	})
+
+	return nil
+}
+
+// recalculateTreatment recomputes plan progress and statuses, rolls the progress up
+// to the risk and moves the risk between in_treatment and mitigated as mitigation
+// tasks are closed or reopened.
+func (s *RiskService) recalculateTreatment(ctx context.Context, risk *repo.Risk, changedBy string) error {
+	plans, err := s.riskRepo.ListTreatmentPlans(ctx, risk.ID)
+	if err != nil {
+		return err
+	}
+
+	var rollup treatmentRollup
+	for _, plan := range plans {
+		if plan.Status == dto.TreatmentPlanStatusDraft || plan.Status == dto.TreatmentPlanStatusCancelled {
+			continue
+		}
+
+		tasks, err := s.riskRepo.ListTreatmentTasks(ctx, plan.ID)
+		if err != nil {
+			return err
+		}
+
+		progress, open, done := treatmentTaskProgress(tasks)
+		newStatus := nextTreatmentPlanStatus(plan.Status, open, done)
+		if progress != plan.Progress || newStatus != plan.Status {
+			if newStatus != dto.TreatmentPlanStatusCompleted {
+				plan.CompletedAt = nil
+			} else if plan.CompletedAt == nil {
+				now := time.Now()
+				plan.CompletedAt = &now
+			}
+			plan.Progress = progress
+			plan.Status = newStatus
+			if err := s.riskRepo.UpdateTreatmentPlan(ctx, plan); err != nil {
+				return err
+			}
+		}
+		rollup.add(plan)
+	}
+
+	riskProgress := rollup.progress()
+	if err := s.riskRepo.UpdateTreatmentProgress(ctx, risk.ID, riskProgress); err != nil {
+		return err
+	}
+	risk.TreatmentProgress = riskProgress
+
+	newStatus := rollup.riskStatus(risk.Status)
+	if newStatus != risk.Status {
+		if err := s.changeRiskStatus(ctx, risk, newStatus, "Автоматически по плану обработки риска", changedBy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// changeRiskStatus updates the status and records the change in risk_history and audit_log
+func (s *RiskService) changeRiskStatus(ctx context.Context, risk *repo.Risk, newStatus, reason, changedBy string) error {
+	oldStatus := risk.Status
+	if err := s.riskRepo.UpdateStatus(ctx, risk.ID, newStatus); err != nil {
+		return err
+	}
+	risk.Status = newStatus
+
+	if err := s.riskRepo.AddHistory(ctx, repo.RiskHistory{
+		ID:           uuid.New().String(),
+		RiskID:       risk.ID,
+		FieldChanged: "status",
+		OldValue:     &oldStatus,
+		NewValue:     &newStatus,
+		ChangeReason: &reason,
+		ChangedBy:    changedBy,
+		ChangedAt:    time.Now(),
+	}); err != nil {
+		log.Printf("WARNING: failed to record risk history for risk %s: %v", risk.ID, err)
+	}
+
+	s.auditRepo.LogAction(ctx, risk.TenantID, changedBy, "update_status", "risk", &risk.ID, map[string]string{
+		"old_status": oldStatus,
+		"status":     newStatus,
+		"reason":     reason,
+	})
+
+	return nil
+}
+
+// treatmentTaskProgress returns the completion percentage and the number of open
+// and done tasks. Cancelled tasks do not count towards progress.
+func treatmentTaskProgress(tasks []repo.RiskTreatmentTask) (progress, open, done int) {
+	for _, task := range tasks {
+		switch task.Status {
+		case dto.TreatmentTaskStatusDone:
+			done++
+		case dto.TreatmentTaskStatusCancelled:
+		default:
+			open++
+		}
+	}
+	if open+done == 0 {
+		return 0, open, done
+	}
+	return done * 100 / (open + done), open, done
+}
+
+// nextTreatmentPlanStatus completes a plan once all its remaining tasks are done and
+// reactivates a completed plan when a task is reopened or added
+func nextTreatmentPlanStatus(status string, open, done int) string {
+	switch {
+	case open == 0 && done > 0:
+		return dto.TreatmentPlanStatusCompleted
+	case status == dto.TreatmentPlanStatusCompleted:
+		return dto.TreatmentPlanStatusActive
+	}
+	return status
+}
+
+// treatmentRollup accumulates the active and completed plans of a risk
+type treatmentRollup struct {
+	progressSum    int
+	trackedPlans   int
+	hasActivePlan  bool
+	hasMitigation  bool
+	mitigationOpen bool
+}
+
+func (r *treatmentRollup) add(plan repo.RiskTreatmentPlan) {
+	r.progressSum += plan.Progress
+	r.trackedPlans++
+	if plan.Status == dto.TreatmentPlanStatusActive {
+		r.hasActivePlan = true
+	}
+	if plan.Strategy == dto.RiskStrategyMitigate {
+		r.hasMitigation = true
+		if plan.Status != dto.TreatmentPlanStatusCompleted {
+			r.mitigationOpen = true
+		}
+	}
+}
+
+// progress is the average progress of the tracked plans, nil when there are none
+func (r treatmentRollup) progress() *int {
+	if r.trackedPlans == 0 {
+		return nil
+	}
+	avg := r.progressSum / r.trackedPlans
+	return &avg
+}
+
+// riskStatus moves the risk to mitigated once every mitigation plan is completed, back to
+// in_treatment when one is reopened, and into treatment when a plan becomes active
+func (r treatmentRollup) riskStatus(status string) string {
+	switch {
+	case r.hasMitigation && !r.mitigationOpen && status != dto.RiskStatusClosed:
+		return dto.RiskStatusMitigated
+	case status == dto.RiskStatusMitigated && r.mitigationOpen:
+		return dto.RiskStatusInTreatment
+	case r.hasActivePlan && (status == dto.RiskStatusNew || status == dto.RiskStatusInAnalysis):
+		return dto.RiskStatusInTreatment
+	}
+	return status
+}
+
+func isTreatmentPlanClosed(status string) bool {
+	return status == dto.TreatmentPlanStatusCompleted || status == dto.TreatmentPlanStatusCancelled
+}
+
+func (s *RiskService) getTenantRisk(ctx context.Context, riskID, tenantID string) (*repo.Risk, error) {
+	risk, err := s.riskRepo.GetByIDWithTenant(ctx, riskID, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if risk == nil {
+		return nil, ErrRiskNotFound
+	}
+	return risk, nil
+}
+
+func (s *RiskService) getRiskTreatmentPlan(ctx context.Context, riskID, planID string) (*repo.RiskTreatmentPlan, error) {
+	plan, err := s.riskRepo.GetTreatmentPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || plan.RiskID != riskID {
+		return nil, ErrTreatmentPlanNotFound
+	}
+	return plan, nil
+}
+
+func (s *RiskService) getPlanTreatmentTask(ctx context.Context, planID, taskID string) (*repo.RiskTreatmentTask, error) {
+	task, err := s.riskRepo.GetTreatmentTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || task.PlanID != planID {
+		return nil, ErrTreatmentTaskNotFound
+	}
+	return task, nil
+}
+
+// parseRiskDate parses an optional YYYY-MM-DD date from a request field
+func parseRiskDate(field string, value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse("2006-01-02", *value)
+	if err != nil {
+		return nil, NewValidationError(field, "invalid date format, use YYYY-MM-DD")
+	}
+	return &parsed, nil
+}
+
+func isBlank(value *string) bool {
+	return value == nil || *value == ""
+}
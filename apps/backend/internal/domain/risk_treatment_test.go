@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"testing"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func treatmentTasks(statuses ...string) []repo.RiskTreatmentTask {
+	tasks := make([]repo.RiskTreatmentTask, len(statuses))
+	for i, status := range statuses {
+		tasks[i].Status = status
+	}
+	return tasks
+}
+
+func TestTreatmentTaskProgress(t *testing.T) {
+	tests := []struct {
+		name         string
+		tasks        []repo.RiskTreatmentTask
+		wantProgress int
+		wantOpen     int
+		wantDone     int
+	}{
+		{name: "no tasks"},
+		{
+			name:     "nothing done",
+			tasks:    treatmentTasks(dto.TreatmentTaskStatusOpen, dto.TreatmentTaskStatusInProgress),
+			wantOpen: 2,
+		},
+		{
+			name:         "one of three done",
+			tasks:        treatmentTasks(dto.TreatmentTaskStatusDone, dto.TreatmentTaskStatusOpen, dto.TreatmentTaskStatusInProgress),
+			wantProgress: 33,
+			wantOpen:     2,
+			wantDone:     1,
+		},
+		{
+			name:         "cancelled tasks are not counted",
+			tasks:        treatmentTasks(dto.TreatmentTaskStatusDone, dto.TreatmentTaskStatusCancelled, dto.TreatmentTaskStatusOpen),
+			wantProgress: 50,
+			wantOpen:     1,
+			wantDone:     1,
+		},
+		{
+			name:         "all remaining done",
+			tasks:        treatmentTasks(dto.TreatmentTaskStatusDone, dto.TreatmentTaskStatusCancelled, dto.TreatmentTaskStatusDone),
+			wantProgress: 100,
+			wantDone:     2,
+		},
+		{
+			name:  "only cancelled",
+			tasks: treatmentTasks(dto.TreatmentTaskStatusCancelled),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress, open, done := treatmentTaskProgress(tt.tasks)
+			assert.Equal(t, tt.wantProgress, progress)
+			assert.Equal(t, tt.wantOpen, open)
+			assert.Equal(t, tt.wantDone, done)
+		})
+	}
+}
+
+func TestNextTreatmentPlanStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		open, done int
+		want       string
+	}{
+		{"active with open tasks", dto.TreatmentPlanStatusActive, 2, 1, dto.TreatmentPlanStatusActive},
+		{"active, last task done", dto.TreatmentPlanStatusActive, 0, 3, dto.TreatmentPlanStatusCompleted},
+		{"active without tasks", dto.TreatmentPlanStatusActive, 0, 0, dto.TreatmentPlanStatusActive},
+		{"completed, task reopened", dto.TreatmentPlanStatusCompleted, 1, 2, dto.TreatmentPlanStatusActive},
+		{"completed, tasks cancelled", dto.TreatmentPlanStatusCompleted, 0, 0, dto.TreatmentPlanStatusActive},
+		{"completed stays completed", dto.TreatmentPlanStatusCompleted, 0, 2, dto.TreatmentPlanStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextTreatmentPlanStatus(tt.status, tt.open, tt.done))
+		})
+	}
+}
+
+func TestTreatmentRollup(t *testing.T) {
+	plan := func(strategy, status string, progress int) repo.RiskTreatmentPlan {
+		return repo.RiskTreatmentPlan{Strategy: strategy, Status: status, Progress: progress}
+	}
+
+	tests := []struct {
+		name         string
+		plans        []repo.RiskTreatmentPlan
+		riskStatus   string
+		wantProgress *int
+		wantStatus   string
+	}{
+		{
+			name:       "no tracked plans",
+			riskStatus: dto.RiskStatusNew,
+			wantStatus: dto.RiskStatusNew,
+		},
+		{
+			name:         "active plan starts treatment",
+			plans:        []repo.RiskTreatmentPlan{plan(dto.RiskStrategyMitigate, dto.TreatmentPlanStatusActive, 40)},
+			riskStatus:   dto.RiskStatusInAnalysis,
+			wantProgress: intPtr(40),
+			wantStatus:   dto.RiskStatusInTreatment,
+		},
+		{
+			name: "all mitigation plans completed",
+			plans: []repo.RiskTreatmentPlan{
+				plan(dto.RiskStrategyMitigate, dto.TreatmentPlanStatusCompleted, 100),
+				plan(dto.RiskStrategyMitigate, dto.TreatmentPlanStatusCompleted, 100),
+			},
+			riskStatus:   dto.RiskStatusInTreatment,
+			wantProgress: intPtr(100),
+			wantStatus:   dto.RiskStatusMitigated,
+		},
+		{
+			name: "one mitigation plan still active",
+			plans: []repo.RiskTreatmentPlan{
+				plan(dto.RiskStrategyMitigate, dto.TreatmentPlanStatusCompleted, 100),
+				plan(dto.RiskStrategyMitigate, dto.TreatmentPlanStatusActive, 25),
+			},
+			riskStatus:   dto.RiskStatusInTreatment,
+			wantProgress: intPtr(62),
+			wantStatus:   dto.RiskStatusInTreatment,
+		},
+		{
+			name:         "reopened task returns mitigated risk to treatment",
+			plans:        []repo.RiskTreatmentPlan{plan(dto.RiskStrategyMitigate, dto.TreatmentPlanStatusActive, 80)},
+			riskStatus:   dto.RiskStatusMitigated,
+			wantProgress: intPtr(80),
+			wantStatus:   dto.RiskStatusInTreatment,
+		},
+		{
+			name:         "completed transfer plan does not mitigate",
+			plans:        []repo.RiskTreatmentPlan{plan(dto.RiskStrategyTransfer, dto.TreatmentPlanStatusCompleted, 100)},
+			riskStatus:   dto.RiskStatusInTreatment,
+			wantProgress: intPtr(100),
+			wantStatus:   dto.RiskStatusInTreatment,
+		},
+		{
+			name:         "closed risk stays closed",
+			plans:        []repo.RiskTreatmentPlan{plan(dto.RiskStrategyMitigate, dto.TreatmentPlanStatusCompleted, 100)},
+			riskStatus:   dto.RiskStatusClosed,
+			wantProgress: intPtr(100),
+			wantStatus:   dto.RiskStatusClosed,
+		},
+		{
+			name:         "accepted risk keeps its status while a plan is active",
+			plans:        []repo.RiskTreatmentPlan{plan(dto.RiskStrategyAccept, dto.TreatmentPlanStatusActive, 0)},
+			riskStatus:   dto.RiskStatusAccepted,
+			wantProgress: intPtr(0),
+			wantStatus:   dto.RiskStatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rollup treatmentRollup
+			for _, p := range tt.plans {
+				rollup.add(p)
+			}
+			assert.Equal(t, tt.wantProgress, rollup.progress())
+			assert.Equal(t, tt.wantStatus, rollup.riskStatus(tt.riskStatus))
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
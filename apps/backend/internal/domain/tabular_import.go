@@ -0,0 +1,393 @@
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// maxImportRows limits the size of spreadsheets accepted by import endpoints
+const maxImportRows = 5000
+
+const (
+	// xlsxMaxColumns is the number of worksheet columns in Excel, A to XFD
+	xlsxMaxColumns       = 16384
+	xlsxMaxColumnLetters = 3
+)
+
+// importValidator applies the request DTO rules to imported rows and reports fields by their JSON names
+var importValidator = newImportValidator()
+
+func newImportValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// readImportTable parses a CSV or XLSX file into rows of cells; the first row is the header.
+// Only the first worksheet of an XLSX workbook is read.
+func readImportTable(filename string, data []byte) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".xlsx":
+		rows, err = readXLSXRows(data)
+	case ".csv", ".txt":
+		rows, err = readCSVRows(data)
+	default:
+		return nil, NewValidationError("file", "unsupported file type, use .csv or .xlsx")
+	}
+	if err != nil {
+		return nil, NewValidationError("file", err.Error())
+	}
+
+	// Drop trailing empty rows left by spreadsheet editors
+	for len(rows) > 0 && isEmptyRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) < 2 {
+		return nil, NewValidationError("file", "file must contain a header row and at least one data row")
+	}
+	if len(rows)-1 > maxImportRows {
+		return nil, NewValidationError("file", fmt.Sprintf("file contains more than %d rows", maxImportRows))
+	}
+	return rows, nil
+}
+
+// readCSVRows accepts comma or semicolon separated UTF-8 files with an optional BOM
+func readCSVRows(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("file must be UTF-8 encoded")
+	}
+
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	return rows, nil
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var sb strings.Builder
+	for _, run := range t.Runs {
+		sb.WriteString(run.Text)
+	}
+	return sb.String()
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref       string       `xml:"r,attr"`
+			Type      string       `xml:"t,attr"`
+			Value     string       `xml:"v"`
+			InlineStr xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSXRows(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX file: %w", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstXLSXSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(file, &shared); err != nil {
+			return nil, fmt.Errorf("invalid XLSX shared strings: %w", err)
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid XLSX file: worksheet %s not found", sheetPath)
+	}
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(file, &sheet); err != nil {
+		return nil, fmt.Errorf("invalid XLSX worksheet: %w", err)
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, sheetRow := range sheet.Rows {
+		var row []string
+		for i, cell := range sheetRow.Cells {
+			column, err := xlsxColumnIndex(cell.Ref)
+			if err != nil {
+				return nil, err
+			}
+			if column < 0 {
+				column = i
+			}
+			for len(row) <= column {
+				row = append(row, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(value)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("invalid XLSX shared string reference in cell %s", cell.Ref)
+				}
+				value = shared.Items[index].String()
+			case "inlineStr":
+				value = cell.InlineStr.String()
+			}
+			row[column] = strings.TrimSpace(value)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// firstXLSXSheetPath resolves the first worksheet through the workbook relationships
+func firstXLSXSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, hasRels := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !hasRels {
+		return fallback, nil
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeZipXML(workbookFile, &workbook); err != nil {
+		return "", fmt.Errorf("invalid XLSX workbook: %w", err)
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("XLSX workbook has no worksheets")
+	}
+
+	var rels xlsxRelationships
+	if err := decodeZipXML(relsFile, &rels); err != nil {
+		return "", fmt.Errorf("invalid XLSX workbook relationships: %w", err)
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].RelID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return fallback, nil
+}
+
+func decodeZipXML(file *zip.File, target interface{}) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return xml.NewDecoder(io.LimitReader(reader, 64<<20)).Decode(target)
+}
+
+// xlsxColumnIndex converts a cell reference such as "AB12" to a zero-based column index;
+// -1 means the reference has no column. Columns past XFD, the last one Excel allows, are rejected
+// so that a crafted reference cannot make the reader allocate a huge row.
+func xlsxColumnIndex(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		if letters == xlsxMaxColumnLetters {
+			return 0, fmt.Errorf("invalid XLSX cell reference %.16q", ref)
+		}
+		column = column*26 + int(r-'A') + 1
+		letters++
+	}
+	if letters == 0 {
+		return -1, nil
+	}
+	if column > xlsxMaxColumns {
+		return 0, fmt.Errorf("invalid XLSX cell reference %.16q", ref)
+	}
+	return column - 1, nil
+}
+
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeImportHeader lowercases a header and collapses separators for alias matching
+func normalizeImportHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	return strings.Join(strings.FieldsFunc(header, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '.'
+	}), " ")
+}
+
+// resolveImportMapping maps each field to a column index using explicit header names first and aliases second
+func resolveImportMapping(headers []string, aliases map[string][]string, explicit map[string]string) (map[string]int, error) {
+	byHeader := make(map[string]int, len(headers))
+	for i, header := range headers {
+		normalized := normalizeImportHeader(header)
+		if _, exists := byHeader[normalized]; !exists && normalized != "" {
+			byHeader[normalized] = i
+		}
+	}
+
+	mapping := make(map[string]int)
+	for field, header := range explicit {
+		if _, known := aliases[field]; !known {
+			return nil, NewValidationError("mapping", fmt.Sprintf("unknown field %q", field))
+		}
+		if header == "" {
+			continue
+		}
+		index, ok := byHeader[normalizeImportHeader(header)]
+		if !ok {
+			return nil, NewValidationError("mapping", fmt.Sprintf("column %q for field %q not found in file", header, field))
+		}
+		mapping[field] = index
+	}
+
+	for field, fieldAliases := range aliases {
+		if _, mapped := explicit[field]; mapped {
+			continue
+		}
+		for _, alias := range append([]string{field}, fieldAliases...) {
+			if index, ok := byHeader[normalizeImportHeader(alias)]; ok {
+				mapping[field] = index
+				break
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// importCell returns the trimmed value of a mapped field, or "" if unmapped or missing
+func importCell(row []string, mapping map[string]int, field string) string {
+	index, ok := mapping[field]
+	if !ok || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// parseImportDate accepts YYYY-MM-DD, DD.MM.YYYY and Excel serial dates and returns YYYY-MM-DD
+func parseImportDate(value string) (string, error) {
+	for _, layout := range []string{"2006-01-02", "02.01.2006", "2006-01-02T15:04:05Z07:00"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.Format("2006-01-02"), nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 && serial < 100000 {
+		excelEpoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+		return excelEpoch.AddDate(0, 0, int(serial)).Format("2006-01-02"), nil
+	}
+	return "", fmt.Errorf("invalid date %q, use YYYY-MM-DD", value)
+}
+
+func optionalImportCell(row []string, mapping map[string]int, field string) *string {
+	value := importCell(row, mapping, field)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// validateImportRequest validates a request DTO built from a row and describes every failed rule
+func validateImportRequest(req interface{}) []string {
+	err := importValidator.Struct(req)
+	if err == nil {
+		return nil
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []string{err.Error()}
+	}
+	messages := make([]string, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		messages = append(messages, describeImportFieldError(fieldErr))
+	}
+	return messages
+}
+
+func describeImportFieldError(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return fieldErr.Field() + ": is required"
+	case "min", "max", "gte", "lte":
+		return fmt.Sprintf("%s: must satisfy %s=%s", fieldErr.Field(), fieldErr.Tag(), fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf("%s: must be one of %s", fieldErr.Field(), fieldErr.Param())
+	default:
+		return fmt.Sprintf("%s: failed %s validation", fieldErr.Field(), fieldErr.Tag())
+	}
+}
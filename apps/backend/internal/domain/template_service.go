@@ -391,6 +391,41 @@ func (s *TemplateService) GetAvailableVariables() dto.TemplateVariablesResponse
 		{Name: "current_datetime", Placeholder: "{{current_datetime}}", Description: "Текущая дата и время", Example: "08.10.2025 14:30", Category: "date"},
 		{Name: "created_at", Placeholder: "{{created_at}}", Description: "Дата создания актива", Example: "01.01.2025", Category: "date"},
 		{Name: "updated_at", Placeholder: "{{updated_at}}", Description: "Дата обновления актива", Example: "08.10.2025", Category: "date"},
+
+		// Incident notification fields
+		{Name: "incident_title", Placeholder: "{{incident_title}}", Description: "Наименование инцидента", Example: "Утечка клиентской базы", Category: "incident"},
+		{Name: "incident_description", Placeholder: "{{incident_description}}", Description: "Описание инцидента", Example: "Выгрузка базы на внешний ресурс", Category: "incident"},
+		{Name: "incident_category", Placeholder: "{{incident_category}}", Description: "Категория инцидента", Example: "data_breach", Category: "incident"},
+		{Name: "incident_criticality", Placeholder: "{{incident_criticality}}", Description: "Критичность инцидента", Example: "high", Category: "incident"},
+		{Name: "detected_at", Placeholder: "{{detected_at}}", Description: "Дата и время обнаружения", Example: "08.10.2025 14:30", Category: "incident"},
+		{Name: "affected_assets", Placeholder: "{{affected_assets}}", Description: "Затронутые активы", Example: "CRM, Сервер БД", Category: "incident"},
+		{Name: "root_cause", Placeholder: "{{root_cause}}", Description: "Причина инцидента", Example: "Компрометация учетной записи", Category: "incident"},
+		{Name: "resolution", Placeholder: "{{resolution}}", Description: "Принятые меры", Example: "Доступ заблокирован", Category: "incident"},
+		{Name: "notification_title", Placeholder: "{{notification_title}}", Description: "Наименование уведомления", Example: "Уведомление Роскомнадзора", Category: "incident"},
+		{Name: "recipient", Placeholder: "{{recipient}}", Description: "Получатель уведомления", Example: "Роскомнадзор", Category: "incident"},
+		{Name: "deadline", Placeholder: "{{deadline}}", Description: "Срок направления уведомления", Example: "09.10.2025 14:30", Category: "incident"},
+
+		// Asset lifecycle act fields
+		{Name: "act_title", Placeholder: "{{act_title}}", Description: "Наименование акта", Example: "Акт приема-передачи", Category: "lifecycle"},
+		{Name: "act_number", Placeholder: "{{act_number}}", Description: "Номер акта", Example: "2025-0001", Category: "lifecycle"},
+		{Name: "act_date", Placeholder: "{{act_date}}", Description: "Дата утверждения операции", Example: "08.10.2025", Category: "lifecycle"},
+		{Name: "operation_reason", Placeholder: "{{operation_reason}}", Description: "Основание операции", Example: "Перевод сотрудника в другой отдел", Category: "lifecycle"},
+		{Name: "from_owner_name", Placeholder: "{{from_owner_name}}", Description: "Прежний владелец", Example: "Иванов И.И.", Category: "lifecycle"},
+		{Name: "to_owner_name", Placeholder: "{{to_owner_name}}", Description: "Новый владелец", Example: "Сидоров С.С.", Category: "lifecycle"},
+		{Name: "from_responsible_name", Placeholder: "{{from_responsible_name}}", Description: "Прежний ответственный", Example: "Петров П.П.", Category: "lifecycle"},
+		{Name: "to_responsible_name", Placeholder: "{{to_responsible_name}}", Description: "Новый ответственный", Example: "Кузнецов К.К.", Category: "lifecycle"},
+		{Name: "from_location", Placeholder: "{{from_location}}", Description: "Прежнее местоположение", Example: "Кабинет 101", Category: "lifecycle"},
+		{Name: "to_location", Placeholder: "{{to_location}}", Description: "Новое местоположение", Example: "Кабинет 205", Category: "lifecycle"},
+		{Name: "from_status", Placeholder: "{{from_status}}", Description: "Статус до операции", Example: "В эксплуатации", Category: "lifecycle"},
+		{Name: "to_status", Placeholder: "{{to_status}}", Description: "Статус после операции", Example: "В ремонте", Category: "lifecycle"},
+		{Name: "requested_by_name", Placeholder: "{{requested_by_name}}", Description: "Инициатор операции", Example: "Иванов И.И.", Category: "lifecycle"},
+		{Name: "approved_by_name", Placeholder: "{{approved_by_name}}", Description: "Утвердивший операцию", Example: "Смирнов А.А.", Category: "lifecycle"},
+		{Name: "decision_comment", Placeholder: "{{decision_comment}}", Description: "Комментарий утверждающего", Example: "Согласовано", Category: "lifecycle"},
+		{Name: "contractor", Placeholder: "{{contractor}}", Description: "Исполнитель ремонта", Example: "ООО \"Сервис\"", Category: "lifecycle"},
+		{Name: "expected_return_date", Placeholder: "{{expected_return_date}}", Description: "Плановая дата возврата из ремонта", Example: "20.10.2025", Category: "lifecycle"},
+		{Name: "repair_result", Placeholder: "{{repair_result}}", Description: "Выполненные работы", Example: "Замена блока питания", Category: "lifecycle"},
+		{Name: "writeoff_method", Placeholder: "{{writeoff_method}}", Description: "Способ списания", Example: "Утилизация", Category: "lifecycle"},
+		{Name: "commission", Placeholder: "{{commission}}", Description: "Состав комиссии по списанию", Example: "Иванов И.И., Петров П.П.", Category: "lifecycle"},
 	}
 
 	return dto.TemplateVariablesResponse{
@@ -414,6 +449,10 @@ func (s *TemplateService) InitializeDefaultTemplates(ctx context.Context, tenant
 		{"passport_printer.html", "Паспорт принтера/МФУ", "Паспорт для печатающих устройств", "passport_device"},
 		{"passport_network.html", "Паспорт сетевого оборудования", "Паспорт для роутеров, коммутаторов и другого сетевого оборудования", "passport_device"},
 		{"passport_storage.html", "Паспорт съемного носителя", "Паспорт для USB-флешек, внешних HDD и других носителей", "passport_device"},
+		{"breach_notification.html", "Уведомление об инциденте", "Уведомление регулятора или субъектов данных об инциденте", "breach_notification"},
+		{"transfer_act.html", "Акт приема-передачи", "Акт передачи актива другому владельцу, ответственному или в другое место", "transfer_act"},
+		{"writeoff_act.html", "Акт о списании", "Акт списания актива комиссией", "writeoff_act"},
+		{"repair_log.html", "Акт ремонта", "Акт передачи актива в ремонт и возврата из ремонта", "repair_log"},
 	}
 
 	for _, tmpl := range templates {
@@ -657,11 +696,19 @@ func (s *TemplateService) saveDocumentAsBytes(ctx context.Context, tenantID, use
 	}
 
 	// Prepare upload request with correct tags for proper categorization
+	description := fmt.Sprintf("Сгенерированный паспорт для актива %s (%s)", asset.Name, asset.InventoryNumber)
+	tags := []string{"#passport", "#активы"} // #passport first for correct category detection
+	if isAssetActCategory(documentType) {
+		// Acts keep their type as the document category
+		description = fmt.Sprintf("%s для актива %s (%s)", assetActTitles[documentType], asset.Name, asset.InventoryNumber)
+		tags = []string{"#активы", "#" + documentType}
+	}
+
 	uploadReq := dto.UploadDocumentDTO{
 		Name:        title,
-		Description: templateStringPtr(fmt.Sprintf("Сгенерированный паспорт для актива %s (%s)", asset.Name, asset.InventoryNumber)),
+		Description: templateStringPtr(description),
 		FolderID:    nil,
-		Tags:        []string{"#passport", "#активы"}, // #passport first for correct category detection
+		Tags:        tags,
 		LinkedTo: &dto.DocumentLinkDTO{
 			Module:   "assets",
 			EntityID: assetID,
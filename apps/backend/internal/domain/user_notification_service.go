@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"risknexus/backend/internal/repo"
+
+	"github.com/google/uuid"
+)
+
+// UserNotificationService delivers in-app notifications to user inboxes
+type UserNotificationService struct {
+	notificationRepo *repo.UserNotificationRepo
+}
+
+func NewUserNotificationService(notificationRepo *repo.UserNotificationRepo) *UserNotificationService {
+	return &UserNotificationService{notificationRepo: notificationRepo}
+}
+
+// Notify puts a notification into the user's inbox
+func (s *UserNotificationService) Notify(ctx context.Context, tenantID, userID, notificationType, title, message, entity, entityID string) error {
+	notification := repo.UserNotification{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		UserID:    userID,
+		Type:      notificationType,
+		Title:     title,
+		Message:   message,
+		CreatedAt: time.Now(),
+	}
+	if entity != "" {
+		notification.Entity = &entity
+	}
+	if entityID != "" {
+		notification.EntityID = &entityID
+	}
+	return s.notificationRepo.Create(ctx, notification)
+}
+
+func (s *UserNotificationService) ListNotifications(ctx context.Context, userID, tenantID string, unreadOnly bool, limit int) ([]repo.UserNotification, int, error) {
+	notifications, err := s.notificationRepo.ListByUser(ctx, userID, tenantID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID, tenantID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, unread, nil
+}
+
+func (s *UserNotificationService) MarkRead(ctx context.Context, id, userID, tenantID string) error {
+	updated, err := s.notificationRepo.MarkRead(ctx, id, userID, tenantID)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrUserNotificationNotFound
+	}
+	return nil
+}
+
+func (s *UserNotificationService) MarkAllRead(ctx context.Context, userID, tenantID string) error {
+	_, err := s.notificationRepo.MarkRead(ctx, "", userID, tenantID)
+	return err
+}
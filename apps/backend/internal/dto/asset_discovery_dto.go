@@ -0,0 +1,102 @@
+package dto
+
+import "time"
+
+// Asset discovery report formats
+const (
+	AssetDiscoveryFormatNative  = "native"
+	AssetDiscoveryFormatOsquery = "osquery"
+	AssetDiscoveryFormatOCS     = "ocs"
+)
+
+// Asset discovery report statuses
+const (
+	AssetDiscoveryStatusApplied = "applied"
+	AssetDiscoveryStatusPending = "pending"
+	AssetDiscoveryStatusIgnored = "ignored"
+)
+
+// How a discovery report was matched to an asset
+const (
+	AssetDiscoveryMatchSerial   = "serial"
+	AssetDiscoveryMatchMAC      = "mac"
+	AssetDiscoveryMatchHostname = "hostname"
+	AssetDiscoveryMatchManual   = "manual"
+	AssetDiscoveryMatchCreated  = "created"
+)
+
+// AssetDiscoveryAgentRequest represents the request to register or update a discovery agent
+type AssetDiscoveryAgentRequest struct {
+	Name                  string  `json:"name" validate:"required,min=1,max=255"`
+	ReporterID            *string `json:"reporter_id,omitempty" validate:"omitempty,uuid"`
+	RemoveMissingSoftware *bool   `json:"remove_missing_software,omitempty"`
+	IsActive              *bool   `json:"is_active,omitempty"`
+}
+
+// AssetDiscoveryAgentResponse represents a discovery agent; the token is returned only on creation and rotation
+type AssetDiscoveryAgentResponse struct {
+	ID                    string     `json:"id"`
+	Name                  string     `json:"name"`
+	TokenPrefix           string     `json:"token_prefix"`
+	Token                 string     `json:"token,omitempty"`
+	ReporterID            string     `json:"reporter_id"`
+	RemoveMissingSoftware bool       `json:"remove_missing_software"`
+	IsActive              bool       `json:"is_active"`
+	LastReportAt          *time.Time `json:"last_report_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// AssetDiscoveryInventory is the native report format; osquery and OCS reports are converted to it
+type AssetDiscoveryInventory struct {
+	Hostname     string                   `json:"hostname"`
+	SerialNumber string                   `json:"serial_number"`
+	Manufacturer string                   `json:"manufacturer"`
+	Model        string                   `json:"model"`
+	CPU          string                   `json:"cpu"`
+	RAM          string                   `json:"ram"`
+	HDDInfo      string                   `json:"hdd_info"`
+	NetworkCard  string                   `json:"network_card"`
+	OS           string                   `json:"os"`
+	MACAddresses []string                 `json:"mac_addresses"`
+	IPAddresses  []string                 `json:"ip_addresses"`
+	Software     []AssetDiscoverySoftware `json:"software"`
+}
+
+// AssetDiscoverySoftware represents an installed package in a discovery report
+type AssetDiscoverySoftware struct {
+	Name        string     `json:"name"`
+	Version     string     `json:"version,omitempty"`
+	InstalledAt *time.Time `json:"installed_at,omitempty"`
+}
+
+// AssetDiscoveryReportResponse represents a received report and the outcome of its matching
+type AssetDiscoveryReportResponse struct {
+	ID              string                   `json:"id"`
+	AgentID         *string                  `json:"agent_id"`
+	Format          string                   `json:"format"`
+	Hostname        *string                  `json:"hostname"`
+	SerialNumber    *string                  `json:"serial_number"`
+	MACAddresses    []string                 `json:"mac_addresses"`
+	IPAddresses     []string                 `json:"ip_addresses"`
+	Inventory       *AssetDiscoveryInventory `json:"inventory,omitempty"`
+	Status          string                   `json:"status"`
+	AssetID         *string                  `json:"asset_id"`
+	MatchedBy       *string                  `json:"matched_by"`
+	CandidateIDs    []string                 `json:"candidate_ids"`
+	ChangedFields   []string                 `json:"changed_fields"`
+	SoftwareAdded   int                      `json:"software_added"`
+	SoftwareUpdated int                      `json:"software_updated"`
+	SoftwareRemoved int                      `json:"software_removed"`
+	ReceivedAt      time.Time                `json:"received_at"`
+	ResolvedBy      *string                  `json:"resolved_by"`
+	ResolvedAt      *time.Time               `json:"resolved_at"`
+}
+
+// AssetDiscoveryResolveRequest resolves a report from the reconciliation queue: link it to an existing asset,
+// create a new asset from it or ignore it
+type AssetDiscoveryResolveRequest struct {
+	Action  string              `json:"action" validate:"required,oneof=link create ignore"`
+	AssetID *string             `json:"asset_id,omitempty" validate:"required_if=Action link,omitempty,uuid"`
+	Asset   *CreateAssetRequest `json:"asset,omitempty" validate:"required_if=Action create"`
+}
@@ -0,0 +1,85 @@
+package dto
+
+// Asset import sources
+const (
+	AssetImportSourceSpreadsheet = "spreadsheet"
+	AssetImportSourceNmap        = "nmap"
+)
+
+// AssetImportService - открытый сервис хоста из результатов сканирования nmap
+type AssetImportService struct {
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+	Name     string `json:"name,omitempty"`
+	Product  string `json:"product,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// AssetImportDuplicate - существующий актив или строка файла, с которыми совпала строка импорта
+type AssetImportDuplicate struct {
+	AssetID         string `json:"asset_id,omitempty"`
+	InventoryNumber string `json:"inventory_number,omitempty"`
+	Name            string `json:"name,omitempty"`
+	RowNumber       int    `json:"row_number,omitempty"` // заполняется для дубликатов внутри файла
+	MatchedBy       string `json:"matched_by"`
+}
+
+// AssetImportRowResponse - результат проверки одной строки файла импорта (или одного хоста nmap)
+type AssetImportRowResponse struct {
+	RowNumber       int                   `json:"row_number"` // номер строки в файле (заголовок - строка 1) или порядковый номер хоста
+	Asset           CreateAssetRequest    `json:"asset"`
+	InventoryNumber string                `json:"inventory_number,omitempty"` // пусто - номер будет сгенерирован при импорте
+	OwnerEmail      string                `json:"owner_email,omitempty"`
+	Hostnames       []string              `json:"hostnames,omitempty"`
+	OS              string                `json:"os,omitempty"`
+	Services        []AssetImportService  `json:"services,omitempty"`
+	DuplicateOf     *AssetImportDuplicate `json:"duplicate_of,omitempty"`
+	Valid           bool                  `json:"valid"`
+	Errors          []string              `json:"errors"`
+}
+
+// AssetImportPreviewResponse - предварительный просмотр импорта активов
+type AssetImportPreviewResponse struct {
+	Source        string                   `json:"source"`
+	Headers       []string                 `json:"headers,omitempty"`
+	Mapping       map[string]string        `json:"mapping,omitempty"` // поле актива -> заголовок колонки
+	TotalRows     int                      `json:"total_rows"`
+	ValidRows     int                      `json:"valid_rows"`
+	InvalidRows   int                      `json:"invalid_rows"`
+	DuplicateRows int                      `json:"duplicate_rows"`
+	Rows          []AssetImportRowResponse `json:"rows"`
+}
+
+// AssetImportResultResponse - результат импорта активов
+type AssetImportResultResponse struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	AssetIDs []string `json:"asset_ids"`
+}
+
+// Asset import fields
+const (
+	AssetImportFieldName            = "name"
+	AssetImportFieldType            = "type"
+	AssetImportFieldClass           = "class"
+	AssetImportFieldInventoryNumber = "inventory_number"
+	AssetImportFieldOwnerEmail      = "owner_email"
+	AssetImportFieldLocation        = "location"
+	AssetImportFieldCriticality     = "criticality"
+	AssetImportFieldConfidentiality = "confidentiality"
+	AssetImportFieldIntegrity       = "integrity"
+	AssetImportFieldAvailability    = "availability"
+	AssetImportFieldStatus          = "status"
+	AssetImportFieldSerialNumber    = "serial_number"
+	AssetImportFieldPCNumber        = "pc_number"
+	AssetImportFieldManufacturer    = "manufacturer"
+	AssetImportFieldModel           = "model"
+	AssetImportFieldCPU             = "cpu"
+	AssetImportFieldRAM             = "ram"
+	AssetImportFieldHDDInfo         = "hdd_info"
+	AssetImportFieldNetworkCard     = "network_card"
+	AssetImportFieldIPAddress       = "ip_address"
+	AssetImportFieldMACAddress      = "mac_address"
+	AssetImportFieldPurchaseYear    = "purchase_year"
+	AssetImportFieldWarrantyUntil   = "warranty_until"
+)
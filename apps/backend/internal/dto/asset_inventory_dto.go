@@ -0,0 +1,122 @@
+package dto
+
+import "time"
+
+// Inventory campaign statuses
+const (
+	InventoryCampaignPlanned    = "planned"
+	InventoryCampaignInProgress = "in_progress"
+	InventoryCampaignCompleted  = "completed"
+	InventoryCampaignSigned     = "signed"
+	InventoryCampaignCancelled  = "cancelled"
+)
+
+// Inventory item results
+const (
+	InventoryResultPending    = "pending"    // ожидаемый актив еще не отсканирован
+	InventoryResultFound      = "found"      // найден на учетном месте
+	InventoryResultMoved      = "moved"      // найден в другом месте
+	InventoryResultMissing    = "missing"    // не найден к завершению кампании
+	InventoryResultUnexpected = "unexpected" // отсканирован актив вне области проверки или неизвестный номер
+)
+
+// Inventory discrepancy resolutions
+const (
+	InventoryResolutionConfirmedMissing = "confirmed_missing" // недостача подтверждена
+	InventoryResolutionFound            = "found"             // актив найден после завершения сканирования
+	InventoryResolutionLocationUpdated  = "location_updated"  // местоположение актива исправлено на фактическое
+	InventoryResolutionReturned         = "returned"          // актив возвращен на учетное место
+	InventoryResolutionIgnored          = "ignored"
+)
+
+// InventoryCampaignRequest represents the request to plan or update an inventory campaign
+type InventoryCampaignRequest struct {
+	Name             string   `json:"name" validate:"required,min=3,max=255"`
+	Description      *string  `json:"description,omitempty" validate:"omitempty,max=2000"`
+	ScopeLocations   []string `json:"scope_locations" validate:"omitempty,max=50,dive,min=1,max=255"`
+	ScopeDepartments []string `json:"scope_departments" validate:"omitempty,max=50,dive,min=1,max=255"`
+	ScopeTypes       []string `json:"scope_types" validate:"omitempty,dive,oneof=server workstation application database document network_device other"`
+	AuditorIDs       []string `json:"auditor_ids" validate:"required,min=1,max=20,dive,uuid"`
+	ScheduledStart   string   `json:"scheduled_start" validate:"required,datetime=2006-01-02"`
+	ScheduledEnd     *string  `json:"scheduled_end,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// InventoryScanRequest represents a scanned QR or barcode label
+type InventoryScanRequest struct {
+	Code     string  `json:"code" validate:"required,max=1000"`               // содержимое этикетки: инвентарный номер или ссылка с ним
+	Location *string `json:"location,omitempty" validate:"omitempty,max=255"` // фактическое местоположение, где выполнено сканирование
+}
+
+// InventoryResolveRequest represents the resolution of a discrepancy
+type InventoryResolveRequest struct {
+	Resolution string  `json:"resolution" validate:"required,oneof=confirmed_missing found location_updated returned ignored"`
+	Comment    *string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+// InventorySignRequest represents the signing of the final inventory report
+type InventorySignRequest struct {
+	Comment     *string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+	GeneratePDF bool    `json:"generate_pdf"`
+}
+
+// InventoryCampaignSummary counts the items of a campaign by result
+type InventoryCampaignSummary struct {
+	Expected   int `json:"expected"`
+	Pending    int `json:"pending"`
+	Found      int `json:"found"`
+	Moved      int `json:"moved"`
+	Missing    int `json:"missing"`
+	Unexpected int `json:"unexpected"`
+	Unresolved int `json:"unresolved"` // расхождения без решения; опись подписывается после их разбора
+}
+
+// InventoryCampaignResponse represents an inventory campaign with its progress
+type InventoryCampaignResponse struct {
+	ID               string                   `json:"id"`
+	Name             string                   `json:"name"`
+	Description      *string                  `json:"description"`
+	Status           string                   `json:"status"`
+	ScopeLocations   []string                 `json:"scope_locations"`
+	ScopeDepartments []string                 `json:"scope_departments"`
+	ScopeTypes       []string                 `json:"scope_types"`
+	AuditorIDs       []string                 `json:"auditor_ids"`
+	ScheduledStart   string                   `json:"scheduled_start"`
+	ScheduledEnd     *string                  `json:"scheduled_end"`
+	StartedAt        *time.Time               `json:"started_at"`
+	CompletedAt      *time.Time               `json:"completed_at"`
+	SignedBy         *string                  `json:"signed_by"`
+	SignedAt         *time.Time               `json:"signed_at"`
+	SignComment      *string                  `json:"sign_comment"`
+	ReportDocumentID *string                  `json:"report_document_id"`
+	ReportSHA256     *string                  `json:"report_sha256"`
+	CreatedBy        string                   `json:"created_by"`
+	CreatedAt        time.Time                `json:"created_at"`
+	UpdatedAt        time.Time                `json:"updated_at"`
+	Summary          InventoryCampaignSummary `json:"summary"`
+}
+
+// InventoryItemResponse represents a position of the inventory list
+type InventoryItemResponse struct {
+	ID                string     `json:"id"`
+	AssetID           *string    `json:"asset_id"`
+	InventoryNumber   string     `json:"inventory_number"`
+	AssetName         *string    `json:"asset_name"`
+	Expected          bool       `json:"expected"`
+	ExpectedLocation  *string    `json:"expected_location"`
+	Result            string     `json:"result"`
+	ScannedCode       *string    `json:"scanned_code"`
+	ScannedLocation   *string    `json:"scanned_location"`
+	ScannedBy         *string    `json:"scanned_by"`
+	ScannedAt         *time.Time `json:"scanned_at"`
+	ScanCount         int        `json:"scan_count"`
+	Resolution        *string    `json:"resolution"`
+	ResolutionComment *string    `json:"resolution_comment"`
+	ResolvedBy        *string    `json:"resolved_by"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+}
+
+// InventoryScanResponse represents the result of a scan
+type InventoryScanResponse struct {
+	Item      InventoryItemResponse `json:"item"`
+	Duplicate bool                  `json:"duplicate"` // позиция уже была отсканирована ранее
+}
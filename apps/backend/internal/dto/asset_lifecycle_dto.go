@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+// Asset lifecycle operation types
+const (
+	AssetLifecycleTransfer       = "transfer"        // передача другому владельцу, ответственному или в другое место
+	AssetLifecycleRepair         = "repair"          // передача в ремонт
+	AssetLifecycleRepairComplete = "repair_complete" // возврат из ремонта
+	AssetLifecycleWriteoff       = "writeoff"        // списание
+)
+
+// Asset lifecycle operation statuses
+const (
+	AssetLifecycleStatusPending   = "pending"
+	AssetLifecycleStatusApproved  = "approved"
+	AssetLifecycleStatusRejected  = "rejected"
+	AssetLifecycleStatusCancelled = "cancelled"
+)
+
+// AssetLifecycleOperationRequest represents the request for a transfer, repair or write-off of an asset
+type AssetLifecycleOperationRequest struct {
+	OperationType       string  `json:"operation_type" validate:"required,oneof=transfer repair repair_complete writeoff"`
+	Reason              string  `json:"reason" validate:"required,min=3,max=2000"`
+	ToOwnerID           *string `json:"to_owner_id,omitempty" validate:"omitempty,uuid"`
+	ToResponsibleUserID *string `json:"to_responsible_user_id,omitempty" validate:"omitempty,uuid"`
+	ToLocation          *string `json:"to_location,omitempty" validate:"omitempty,max=255"`
+	ToStatus            *string `json:"to_status,omitempty" validate:"omitempty,oneof=active storage"` // для передачи и возврата из ремонта
+	TemplateID          *string `json:"template_id,omitempty" validate:"omitempty,uuid"`
+	AssetLifecycleDetails
+}
+
+// AssetLifecycleDetails holds the operation-specific data printed in the act
+type AssetLifecycleDetails struct {
+	Contractor         string   `json:"contractor,omitempty" validate:"omitempty,max=255"`                       // исполнитель ремонта
+	ExpectedReturnDate string   `json:"expected_return_date,omitempty" validate:"omitempty,datetime=2006-01-02"` // плановая дата возврата из ремонта
+	RepairResult       string   `json:"repair_result,omitempty" validate:"omitempty,max=2000"`                   // выполненные работы
+	WriteoffMethod     string   `json:"writeoff_method,omitempty" validate:"omitempty,oneof=disposal sale donation recycling other"`
+	Commission         []string `json:"commission,omitempty" validate:"omitempty,max=10,dive,min=1,max=255"` // члены комиссии по списанию
+}
+
+// AssetLifecycleDecisionRequest represents an approval or rejection of a lifecycle operation
+type AssetLifecycleDecisionRequest struct {
+	Comment     *string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+	GeneratePDF bool    `json:"generate_pdf"` // формировать акт в PDF вместо HTML
+}
+
+// AssetLifecycleOperationResponse represents a lifecycle operation of an asset
+type AssetLifecycleOperationResponse struct {
+	ID                    string                `json:"id"`
+	AssetID               string                `json:"asset_id"`
+	AssetName             string                `json:"asset_name"`
+	InventoryNumber       string                `json:"inventory_number"`
+	OperationType         string                `json:"operation_type"`
+	Status                string                `json:"status"`
+	Reason                string                `json:"reason"`
+	FromStatus            string                `json:"from_status"`
+	ToStatus              *string               `json:"to_status"`
+	FromOwnerID           *string               `json:"from_owner_id"`
+	ToOwnerID             *string               `json:"to_owner_id"`
+	FromResponsibleUserID *string               `json:"from_responsible_user_id"`
+	ToResponsibleUserID   *string               `json:"to_responsible_user_id"`
+	FromLocation          *string               `json:"from_location"`
+	ToLocation            *string               `json:"to_location"`
+	Details               AssetLifecycleDetails `json:"details"`
+	TemplateID            *string               `json:"template_id"`
+	ActNumber             *string               `json:"act_number"`
+	DocumentID            *string               `json:"document_id"`
+	RequestedBy           string                `json:"requested_by"`
+	ApproverUserID        *string               `json:"approver_user_id"`
+	DecisionComment       *string               `json:"decision_comment"`
+	DecidedAt             *time.Time            `json:"decided_at"`
+	CreatedAt             time.Time             `json:"created_at"`
+	UpdatedAt             time.Time             `json:"updated_at"`
+	ActError              string                `json:"act_error,omitempty"` // акт не сформирован, операция при этом применена
+}
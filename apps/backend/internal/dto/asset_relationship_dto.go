@@ -0,0 +1,101 @@
+package dto
+
+import "time"
+
+// Asset relationship types; a relationship reads as "source <type> target"
+const (
+	AssetRelationRunsOn       = "runs_on"
+	AssetRelationDependsOn    = "depends_on"
+	AssetRelationStoresDataOf = "stores_data_of"
+	AssetRelationConnectedTo  = "connected_to"
+)
+
+// Asset relationship directions relative to the requested asset
+const (
+	AssetRelationOutgoing = "outgoing"
+	AssetRelationIncoming = "incoming"
+)
+
+// AssetRelationshipRequest represents the request to link the asset (source) to another asset (target)
+type AssetRelationshipRequest struct {
+	TargetAssetID    string  `json:"target_asset_id" validate:"required,uuid"`
+	RelationshipType string  `json:"relationship_type" validate:"required,oneof=runs_on depends_on stores_data_of connected_to"`
+	Description      *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+}
+
+// AssetRelationshipResponse represents a relationship as seen from one of its assets
+type AssetRelationshipResponse struct {
+	ID               string         `json:"id"`
+	RelationshipType string         `json:"relationship_type"`
+	Direction        string         `json:"direction"`
+	RelatedAsset     AssetGraphNode `json:"related_asset"`
+	Description      *string        `json:"description"`
+	CreatedBy        *string        `json:"created_by"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
+// AssetGraphNode is an asset in the dependency map with its own and effective (inherited) CIA ratings
+type AssetGraphNode struct {
+	ID              string             `json:"id"`
+	InventoryNumber string             `json:"inventory_number"`
+	Name            string             `json:"name"`
+	Type            string             `json:"type"`
+	Status          string             `json:"status"`
+	Criticality     string             `json:"criticality"`
+	Confidentiality string             `json:"confidentiality"`
+	Integrity       string             `json:"integrity"`
+	Availability    string             `json:"availability"`
+	Effective       *AssetEffectiveCIA `json:"effective,omitempty"`
+}
+
+// AssetEffectiveCIA holds the ratings raised by the assets that rely on this one
+type AssetEffectiveCIA struct {
+	Criticality     string              `json:"criticality"`
+	Confidentiality string              `json:"confidentiality"`
+	Integrity       string              `json:"integrity"`
+	Availability    string              `json:"availability"`
+	Raised          bool                `json:"raised"`
+	InheritedFrom   []AssetRatingSource `json:"inherited_from"`
+}
+
+// AssetRatingSource is the asset a raised rating was inherited from
+type AssetRatingSource struct {
+	Attribute       string `json:"attribute"` // criticality, confidentiality, integrity или availability
+	Level           string `json:"level"`
+	AssetID         string `json:"asset_id"`
+	InventoryNumber string `json:"inventory_number"`
+	Name            string `json:"name"`
+}
+
+// AssetGraphEdge is a relationship in the dependency map
+type AssetGraphEdge struct {
+	ID               string `json:"id"`
+	SourceAssetID    string `json:"source_asset_id"`
+	TargetAssetID    string `json:"target_asset_id"`
+	RelationshipType string `json:"relationship_type"`
+}
+
+// AssetGraphResponse is the neighbourhood of an asset up to the requested depth
+type AssetGraphResponse struct {
+	RootAssetID string           `json:"root_asset_id"`
+	Depth       int              `json:"depth"`
+	Nodes       []AssetGraphNode `json:"nodes"`
+	Edges       []AssetGraphEdge `json:"edges"`
+}
+
+// AssetImpactEntry is an asset affected by the failure of the analysed asset
+type AssetImpactEntry struct {
+	Asset            AssetGraphNode `json:"asset"`
+	Depth            int            `json:"depth"`
+	ViaAssetID       string         `json:"via_asset_id"` // актив, через отказ которого затронут данный
+	RelationshipType string         `json:"relationship_type"`
+}
+
+// AssetImpactResponse lists everything affected when the asset fails
+type AssetImpactResponse struct {
+	Asset         AssetGraphNode     `json:"asset"`
+	Affected      []AssetImpactEntry `json:"affected"`
+	TotalAffected int                `json:"total_affected"`
+	ByCriticality map[string]int     `json:"by_criticality"`
+	HighestImpact string             `json:"highest_impact"` // наивысшая критичность среди затронутых активов (с учетом наследования)
+}
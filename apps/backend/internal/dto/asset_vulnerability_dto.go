@@ -0,0 +1,90 @@
+package dto
+
+import "time"
+
+// Vulnerability feed formats
+const (
+	VulnerabilityFeedNVD           = "nvd"            // CVE feed: NVD JSON 1.1 (CVE_Items) или ответ API 2.0 (vulnerabilities)
+	VulnerabilityFeedCPEDictionary = "cpe_dictionary" // словарь CPE: official-cpe-dictionary XML или ответ CPE API 2.0
+)
+
+// Asset vulnerability statuses
+const (
+	AssetVulnerabilityOpen  = "open"
+	AssetVulnerabilityFixed = "fixed"
+)
+
+// CVE severities (CVSS v3 qualitative rating)
+const (
+	CVESeverityNone     = "none"
+	CVESeverityLow      = "low"
+	CVESeverityMedium   = "medium"
+	CVESeverityHigh     = "high"
+	CVESeverityCritical = "critical"
+)
+
+// VulnerabilityFeedImportResponse represents the result of a feed import and of the matching run after it
+type VulnerabilityFeedImportResponse struct {
+	Format            string                      `json:"format"`
+	CVEs              int                         `json:"cves"`
+	CPEMatches        int                         `json:"cpe_matches"`
+	DictionaryEntries int                         `json:"dictionary_entries"`
+	Skipped           int                         `json:"skipped"` // записи без идентификатора, отклоненные (REJECTED) CVE
+	TotalCVEs         int                         `json:"total_cves"`
+	TotalDictionary   int                         `json:"total_dictionary"`
+	Matching          *VulnerabilityMatchResponse `json:"matching,omitempty"`
+}
+
+// VulnerabilityMatchRequest represents the request to match installed software against the CVE database
+type VulnerabilityMatchRequest struct {
+	AssetID        *string  `json:"asset_id,omitempty" validate:"omitempty,uuid"`
+	CreateRisks    bool     `json:"create_risks"`
+	MinCVSS        *float64 `json:"min_cvss,omitempty" validate:"omitempty,min=0,max=10"`                 // по умолчанию 9.0 (critical)
+	MinCriticality *string  `json:"min_criticality,omitempty" validate:"omitempty,oneof=low medium high"` // по умолчанию high
+}
+
+// VulnerabilityMatchResponse represents the result of a matching run
+type VulnerabilityMatchResponse struct {
+	CheckedSoftware    int      `json:"checked_software"`
+	RecognizedSoftware int      `json:"recognized_software"` // ПО, для которого найден продукт CPE
+	Vulnerabilities    int      `json:"vulnerabilities"`     // открытые уязвимости проверенного ПО
+	Detected           int      `json:"detected"`            // впервые обнаруженные
+	Fixed              int      `json:"fixed"`
+	RisksCreated       int      `json:"risks_created"`
+	RiskIDs            []string `json:"risk_ids"`
+}
+
+// AssetVulnerabilityResponse represents a CVE found in software installed on an asset
+type AssetVulnerabilityResponse struct {
+	ID              string     `json:"id"`
+	CVEID           string     `json:"cve_id"`
+	Description     *string    `json:"description"`
+	CVSSScore       *float64   `json:"cvss_score"`
+	Severity        *string    `json:"severity"`
+	Status          string     `json:"status"`
+	AssetID         string     `json:"asset_id"`
+	AssetName       string     `json:"asset_name"`
+	InventoryNumber string     `json:"inventory_number"`
+	SoftwareID      string     `json:"software_id"`
+	SoftwareName    string     `json:"software_name"`
+	SoftwareVersion *string    `json:"software_version"`
+	CPEVendor       string     `json:"cpe_vendor"`
+	CPEProduct      string     `json:"cpe_product"`
+	RiskID          *string    `json:"risk_id"`
+	FirstDetectedAt time.Time  `json:"first_detected_at"`
+	LastDetectedAt  time.Time  `json:"last_detected_at"`
+	FixedAt         *time.Time `json:"fixed_at"`
+}
+
+// AssetVulnerabilitySummary counts the open vulnerabilities of an asset by severity
+type AssetVulnerabilitySummary struct {
+	Open       int            `json:"open"`
+	BySeverity map[string]int `json:"by_severity"`
+	MaxCVSS    *float64       `json:"max_cvss"`
+}
+
+// AssetVulnerabilityListResponse represents the vulnerability list of an asset
+type AssetVulnerabilityListResponse struct {
+	Summary         AssetVulnerabilitySummary    `json:"summary"`
+	Vulnerabilities []AssetVulnerabilityResponse `json:"vulnerabilities"`
+}
@@ -0,0 +1,91 @@
+package dto
+
+import "time"
+
+// IncidentAlertSourceRequest - настройка источника оповещений (SIEM, мониторинг, агент)
+type IncidentAlertSourceRequest struct {
+	Name               string            `json:"name" validate:"required,min=1,max=255"`
+	Format             string            `json:"format" validate:"required,oneof=json cef syslog"`
+	IncidentSource     string            `json:"incident_source" validate:"omitempty,oneof=automatic_agent monitoring siem"`
+	FieldMapping       map[string]string `json:"field_mapping" validate:"omitempty,dive,keys,oneof=title description severity category hostname ip detected_at,endkeys,max=500"`
+	SeverityMapping    map[string]string `json:"severity_mapping" validate:"omitempty,dive,keys,min=1,max=50,endkeys,oneof=low medium high critical"`
+	CorrelationFields  []string          `json:"correlation_fields" validate:"omitempty,max=10,dive,min=1,max=100"`
+	DedupWindowMinutes int               `json:"dedup_window_minutes" validate:"omitempty,min=1,max=43200"`
+	DefaultCategory    string            `json:"default_category" validate:"omitempty,oneof=technical_failure data_breach unauthorized_access physical malware social_engineering other"`
+	DefaultCriticality string            `json:"default_criticality" validate:"omitempty,oneof=low medium high critical"`
+	AssignedTo         *string           `json:"assigned_to" validate:"omitempty,uuid4"`
+	IsActive           *bool             `json:"is_active,omitempty"`
+}
+
+// IncidentAlertSourceResponse - источник оповещений; токен возвращается только при создании и перевыпуске
+type IncidentAlertSourceResponse struct {
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	Format             string            `json:"format"`
+	IncidentSource     string            `json:"incident_source"`
+	TokenPrefix        string            `json:"token_prefix"`
+	Token              string            `json:"token,omitempty"`
+	FieldMapping       map[string]string `json:"field_mapping"`
+	SeverityMapping    map[string]string `json:"severity_mapping"`
+	CorrelationFields  []string          `json:"correlation_fields"`
+	DedupWindowMinutes int               `json:"dedup_window_minutes"`
+	DefaultCategory    string            `json:"default_category"`
+	DefaultCriticality string            `json:"default_criticality"`
+	AssignedTo         *string           `json:"assigned_to"`
+	ReporterID         string            `json:"reporter_id"`
+	IsActive           bool              `json:"is_active"`
+	LastReceivedAt     *time.Time        `json:"last_received_at"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// IncidentAlertResult - результат обработки одного оповещения
+type IncidentAlertResult struct {
+	AlertID     string   `json:"alert_id"`
+	IncidentID  string   `json:"incident_id"`
+	Duplicate   bool     `json:"duplicate"`
+	Title       string   `json:"title"`
+	Criticality string   `json:"criticality"`
+	AssetIDs    []string `json:"asset_ids"`
+}
+
+// IncidentAlertIngestResponse - итог приёма пакета оповещений
+type IncidentAlertIngestResponse struct {
+	Received   int                   `json:"received"`
+	Created    int                   `json:"created"`
+	Duplicates int                   `json:"duplicates"`
+	Failed     int                   `json:"failed"`
+	Results    []IncidentAlertResult `json:"results"`
+	Errors     []string              `json:"errors,omitempty"`
+}
+
+// IncidentAlertResponse - оповещение, привязанное к инциденту
+type IncidentAlertResponse struct {
+	ID          string            `json:"id"`
+	SourceID    string            `json:"source_id"`
+	SourceName  string            `json:"source_name"`
+	Title       string            `json:"title"`
+	Severity    *string           `json:"severity"`
+	Criticality string            `json:"criticality"`
+	Fields      map[string]string `json:"fields"`
+	RawPayload  string            `json:"raw_payload"`
+	IsDuplicate bool              `json:"is_duplicate"`
+	ReceivedAt  time.Time         `json:"received_at"`
+}
+
+// Alert ingestion constants
+const (
+	// Payload formats
+	AlertFormatJSON   = "json"
+	AlertFormatCEF    = "cef"
+	AlertFormatSyslog = "syslog"
+
+	// Incident fields an alert can be mapped to
+	AlertFieldTitle       = "title"
+	AlertFieldDescription = "description"
+	AlertFieldSeverity    = "severity"
+	AlertFieldCategory    = "category"
+	AlertFieldHostname    = "hostname"
+	AlertFieldIP          = "ip"
+	AlertFieldDetectedAt  = "detected_at"
+)
@@ -31,29 +31,42 @@ type UpdateIncidentRequest struct {
 	RiskIDs     []string   `json:"risk_ids,omitempty" validate:"omitempty,dive,uuid4"`
 	AssignedTo  *string    `json:"assigned_to,omitempty" validate:"omitempty,uuid4"`
 	DetectedAt  *time.Time `json:"detected_at,omitempty"`
+	RootCause   *string    `json:"root_cause,omitempty" validate:"omitempty,max=4000"`
+	Resolution  *string    `json:"resolution_summary,omitempty" validate:"omitempty,max=4000"`
+	Comment     *string    `json:"status_comment,omitempty" validate:"omitempty,max=2000"` // комментарий к смене статуса
 }
 
 // IncidentResponse - ответ с данными инцидента
 type IncidentResponse struct {
-	ID           string      `json:"id"`
-	TenantID     string      `json:"tenant_id"`
-	Title        string      `json:"title"`
-	Description  *string     `json:"description"`
-	Category     string      `json:"category"`
-	Status       string      `json:"status"`
-	Criticality  string      `json:"criticality"`
-	Source       string      `json:"source"`
-	ReportedBy   string      `json:"reported_by"`
-	AssignedTo   *string     `json:"assigned_to"`
-	DetectedAt   time.Time   `json:"detected_at"`
-	ResolvedAt   *time.Time  `json:"resolved_at,omitempty"`
-	ClosedAt     *time.Time  `json:"closed_at,omitempty"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	Assets       []AssetInfo `json:"assets,omitempty"`
-	Risks        []RiskInfo  `json:"risks,omitempty"`
-	ReportedName *string     `json:"reported_name,omitempty"`
-	AssignedName *string     `json:"assigned_name,omitempty"`
+	ID                 string      `json:"id"`
+	TenantID           string      `json:"tenant_id"`
+	Title              string      `json:"title"`
+	Description        *string     `json:"description"`
+	Category           string      `json:"category"`
+	Status             string      `json:"status"`
+	Criticality        string      `json:"criticality"`
+	Source             string      `json:"source"`
+	ReportedBy         string      `json:"reported_by"`
+	AssignedTo         *string     `json:"assigned_to"`
+	DetectedAt         time.Time   `json:"detected_at"`
+	ResolvedAt         *time.Time  `json:"resolved_at,omitempty"`
+	ClosedAt           *time.Time  `json:"closed_at,omitempty"`
+	RootCause          *string     `json:"root_cause"`
+	Resolution         *string     `json:"resolution_summary"`
+	ReopenCount        int         `json:"reopen_count"`
+	RespondedAt        *time.Time  `json:"responded_at,omitempty"`
+	ResponseDueAt      *time.Time  `json:"response_due_at,omitempty"`
+	ResolutionDueAt    *time.Time  `json:"resolution_due_at,omitempty"`
+	ResponseBreached   bool        `json:"response_breached"`
+	ResolutionBreached bool        `json:"resolution_breached"`
+	ParentID           *string     `json:"parent_id,omitempty"`
+	MergedIntoID       *string     `json:"merged_into_id,omitempty"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+	Assets             []AssetInfo `json:"assets,omitempty"`
+	Risks              []RiskInfo  `json:"risks,omitempty"`
+	ReportedName       *string     `json:"reported_name,omitempty"`
+	AssignedName       *string     `json:"assigned_name,omitempty"`
 }
 
 // AssetInfo - информация об активе
@@ -82,8 +95,50 @@ type IncidentListRequest struct {
 }
 
 // IncidentStatusUpdateRequest - запрос на обновление статуса инцидента
+// Поля, обязательные для перехода, можно передать вместе со сменой статуса
 type IncidentStatusUpdateRequest struct {
-	Status string `json:"status" validate:"required,oneof=new assigned in_progress resolved closed"`
+	Status     string  `json:"status" validate:"required,oneof=new assigned in_progress resolved closed"`
+	Comment    *string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+	AssignedTo *string `json:"assigned_to,omitempty" validate:"omitempty,uuid4"`
+	RootCause  *string `json:"root_cause,omitempty" validate:"omitempty,max=4000"`
+	Resolution *string `json:"resolution_summary,omitempty" validate:"omitempty,max=4000"`
+}
+
+// IncidentStatusTransitionRequest - разрешённый переход между статусами
+type IncidentStatusTransitionRequest struct {
+	FromStatus     string   `json:"from_status" validate:"required,oneof=new assigned in_progress resolved closed"`
+	ToStatus       string   `json:"to_status" validate:"required,oneof=new assigned in_progress resolved closed,nefield=FromStatus"`
+	RequiredFields []string `json:"required_fields" validate:"omitempty,dive,oneof=assigned_to root_cause resolution_summary comment"`
+}
+
+// IncidentWorkflowRequest - запрос на замену модели состояний организации (пустой список - модель по умолчанию)
+type IncidentWorkflowRequest struct {
+	Transitions []IncidentStatusTransitionRequest `json:"transitions" validate:"omitempty,dive"`
+}
+
+// IncidentStatusTransitionResponse - разрешённый переход между статусами
+type IncidentStatusTransitionResponse struct {
+	FromStatus     string   `json:"from_status"`
+	ToStatus       string   `json:"to_status"`
+	RequiredFields []string `json:"required_fields"`
+	IsReopen       bool     `json:"is_reopen"`
+}
+
+// IncidentWorkflowResponse - действующая модель состояний инцидента
+type IncidentWorkflowResponse struct {
+	IsDefault   bool                               `json:"is_default"`
+	Transitions []IncidentStatusTransitionResponse `json:"transitions"`
+}
+
+// IncidentStatusChangeResponse - запись истории статусов инцидента
+type IncidentStatusChangeResponse struct {
+	ID         string    `json:"id"`
+	FromStatus *string   `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	IsReopen   bool      `json:"is_reopen"`
+	Comment    *string   `json:"comment"`
+	ChangedBy  *string   `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
 }
 
 // IncidentCommentRequest - запрос на добавление комментария
@@ -128,6 +183,9 @@ type IncidentActionResponse struct {
 	UpdatedAt    time.Time  `json:"updated_at"`
 	AssignedName *string    `json:"assigned_name,omitempty"`
 	CreatedName  *string    `json:"created_name,omitempty"`
+	PlaybookID   *string    `json:"playbook_id,omitempty"`
+	PlaybookName *string    `json:"playbook_name,omitempty"`
+	StepOrder    *int       `json:"step_order,omitempty"`
 }
 
 // IncidentMetricsResponse - ответ с метриками инцидента
@@ -140,6 +198,83 @@ type IncidentMetricsResponse struct {
 	ByCriticality   map[string]int `json:"by_criticality"`
 	ByCategory      map[string]int `json:"by_category"`
 	ByStatus        map[string]int `json:"by_status"`
+
+	AverageResponseTime float64 `json:"average_response_time_minutes"`
+	ResponseBreaches    int     `json:"response_breaches"`
+	ResolutionBreaches  int     `json:"resolution_breaches"`
+	OpenBreached        int     `json:"open_breached"`
+	SLACompliance       float64 `json:"sla_compliance_percent"`
+}
+
+// IncidentSLAPolicyRequest - сроки SLA для уровня критичности
+type IncidentSLAPolicyRequest struct {
+	Criticality       string  `json:"criticality" validate:"required,oneof=low medium high critical"`
+	ResponseMinutes   int     `json:"response_minutes" validate:"required,min=1"`
+	ResolutionMinutes int     `json:"resolution_minutes" validate:"required,min=1,gtefield=ResponseMinutes"`
+	WarningPercent    int     `json:"warning_percent" validate:"omitempty,min=1,max=99"`
+	BusinessHoursOnly bool    `json:"business_hours_only"`
+	CalendarID        *string `json:"calendar_id" validate:"omitempty,uuid"`
+}
+
+// IncidentSLAPoliciesRequest - запрос на обновление политик SLA организации
+type IncidentSLAPoliciesRequest struct {
+	Policies []IncidentSLAPolicyRequest `json:"policies" validate:"required,min=1,dive"`
+}
+
+// IncidentSLAPolicyResponse - действующая политика SLA
+type IncidentSLAPolicyResponse struct {
+	Criticality       string  `json:"criticality"`
+	ResponseMinutes   int     `json:"response_minutes"`
+	ResolutionMinutes int     `json:"resolution_minutes"`
+	WarningPercent    int     `json:"warning_percent"`
+	BusinessHoursOnly bool    `json:"business_hours_only"`
+	CalendarID        *string `json:"calendar_id"`
+	IsDefault         bool    `json:"is_default"`
+}
+
+// IncidentBusinessCalendarRequest - календарь рабочего времени
+type IncidentBusinessCalendarRequest struct {
+	Name      string   `json:"name" validate:"required,min=1,max=255"`
+	Timezone  string   `json:"timezone" validate:"omitempty,timezone"`
+	WorkDays  []int64  `json:"work_days" validate:"omitempty,dive,min=1,max=7"`
+	WorkStart string   `json:"work_start" validate:"omitempty,datetime=15:04"`
+	WorkEnd   string   `json:"work_end" validate:"omitempty,datetime=15:04"`
+	Holidays  []string `json:"holidays" validate:"omitempty,dive,datetime=2006-01-02"`
+	IsDefault bool     `json:"is_default"`
+}
+
+// IncidentSLATimerResponse - состояние таймера SLA
+type IncidentSLATimerResponse struct {
+	Target           string     `json:"target"` // response или resolution
+	Status           string     `json:"status"` // ok, warning, breached, met
+	DueAt            *time.Time `json:"due_at"`
+	StoppedAt        *time.Time `json:"stopped_at,omitempty"`
+	TargetMinutes    int        `json:"target_minutes"`
+	ElapsedMinutes   int        `json:"elapsed_minutes"`
+	RemainingMinutes int        `json:"remaining_minutes"`
+	PercentElapsed   float64    `json:"percent_elapsed"`
+}
+
+// IncidentSLAResponse - SLA инцидента с живыми таймерами
+type IncidentSLAResponse struct {
+	IncidentID        string                   `json:"incident_id"`
+	Criticality       string                   `json:"criticality"`
+	BusinessHoursOnly bool                     `json:"business_hours_only"`
+	Response          IncidentSLATimerResponse `json:"response"`
+	Resolution        IncidentSLATimerResponse `json:"resolution"`
+	CalculatedAt      time.Time                `json:"calculated_at"`
+}
+
+// IncidentSLAEventResponse - предупреждение или нарушение SLA
+type IncidentSLAEventResponse struct {
+	ID              string    `json:"id"`
+	IncidentID      string    `json:"incident_id"`
+	IncidentTitle   string    `json:"incident_title"`
+	Target          string    `json:"target"`
+	EventType       string    `json:"event_type"`
+	DueAt           time.Time `json:"due_at"`
+	RecipientUserID *string   `json:"recipient_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // Incident constants
@@ -180,6 +315,22 @@ const (
 	ActionTypeRecovery      = "recovery"
 	ActionTypePrevention    = "prevention"
 
+	// Fields that a status transition can require
+	IncidentFieldAssignedTo = "assigned_to"
+	IncidentFieldRootCause  = "root_cause"
+	IncidentFieldResolution = "resolution_summary"
+	IncidentFieldComment    = "comment"
+
+	// SLA timers
+	IncidentSLATargetResponse   = "response"
+	IncidentSLATargetResolution = "resolution"
+	IncidentSLAStatusOK         = "ok"
+	IncidentSLAStatusWarning    = "warning"
+	IncidentSLAStatusBreached   = "breached"
+	IncidentSLAStatusMet        = "met"
+	IncidentSLAEventWarning     = "warning"
+	IncidentSLAEventBreach      = "breach"
+
 	// Action status
 	ActionStatusPending    = "pending"
 	ActionStatusInProgress = "in_progress"
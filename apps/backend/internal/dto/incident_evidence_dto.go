@@ -0,0 +1,89 @@
+package dto
+
+import "time"
+
+// IncidentEvidenceRequest - метаданные доказательства, передаваемые вместе с файлом
+type IncidentEvidenceRequest struct {
+	Title        string  `json:"title" validate:"required,min=1,max=255"`
+	Description  *string `json:"description,omitempty" validate:"omitempty,max=5000"`
+	EvidenceType string  `json:"evidence_type" validate:"required,oneof=file log screenshot memory_dump disk_image network_capture email other"`
+	Source       *string `json:"source,omitempty" validate:"omitempty,max=255"`
+	CollectedAt  *string `json:"collected_at,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// IncidentEvidenceUpdateRequest - изменение метаданных незапечатанного доказательства
+type IncidentEvidenceUpdateRequest struct {
+	Title        *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description  *string `json:"description,omitempty" validate:"omitempty,max=5000"`
+	EvidenceType *string `json:"evidence_type,omitempty" validate:"omitempty,oneof=file log screenshot memory_dump disk_image network_capture email other"`
+	Source       *string `json:"source,omitempty" validate:"omitempty,max=255"`
+}
+
+// IncidentEvidenceTransferRequest - передача доказательства другому ответственному
+type IncidentEvidenceTransferRequest struct {
+	ToUserID string  `json:"to_user_id" validate:"required,uuid"`
+	Reason   *string `json:"reason,omitempty" validate:"omitempty,max=2000"`
+}
+
+// IncidentEvidenceResponse - доказательство по инциденту
+type IncidentEvidenceResponse struct {
+	ID                     string                            `json:"id"`
+	IncidentID             string                            `json:"incident_id"`
+	DocumentID             string                            `json:"document_id"`
+	Title                  string                            `json:"title"`
+	Description            *string                           `json:"description"`
+	EvidenceType           string                            `json:"evidence_type"`
+	Source                 *string                           `json:"source"`
+	FileName               string                            `json:"file_name"`
+	FileSize               int64                             `json:"file_size"`
+	SHA256                 string                            `json:"sha256"`
+	CollectedBy            *string                           `json:"collected_by"`
+	CollectedAt            time.Time                         `json:"collected_at"`
+	CustodianID            *string                           `json:"custodian_id"`
+	IsSealed               bool                              `json:"is_sealed"`
+	SealedBy               *string                           `json:"sealed_by"`
+	SealedAt               *time.Time                        `json:"sealed_at"`
+	LastVerifiedAt         *time.Time                        `json:"last_verified_at"`
+	LastVerificationStatus *string                           `json:"last_verification_status"`
+	Custody                []IncidentEvidenceCustodyResponse `json:"custody,omitempty"`
+	CreatedAt              time.Time                         `json:"created_at"`
+	UpdatedAt              time.Time                         `json:"updated_at"`
+}
+
+// IncidentEvidenceCustodyResponse - запись журнала хранения доказательства
+type IncidentEvidenceCustodyResponse struct {
+	ID              string    `json:"id"`
+	Action          string    `json:"action"`
+	PerformedBy     *string   `json:"performed_by"`
+	FromCustodianID *string   `json:"from_custodian_id"`
+	ToCustodianID   *string   `json:"to_custodian_id"`
+	SHA256          *string   `json:"sha256"`
+	Details         *string   `json:"details"`
+	IPAddress       *string   `json:"ip_address"`
+	PerformedAt     time.Time `json:"performed_at"`
+}
+
+// IncidentEvidenceVerificationResult - результат повторного хеширования хранимого файла
+type IncidentEvidenceVerificationResult struct {
+	EvidenceID     string    `json:"evidence_id"`
+	Title          string    `json:"title"`
+	Status         string    `json:"status"`
+	ExpectedSHA256 string    `json:"expected_sha256"`
+	ActualSHA256   *string   `json:"actual_sha256"`
+	VerifiedAt     time.Time `json:"verified_at"`
+}
+
+// Evidence constants
+const (
+	EvidenceCustodyCollected   = "collected"
+	EvidenceCustodyAccessed    = "accessed"
+	EvidenceCustodyExported    = "exported"
+	EvidenceCustodyTransferred = "transferred"
+	EvidenceCustodyUpdated     = "updated"
+	EvidenceCustodySealed      = "sealed"
+	EvidenceCustodyVerified    = "verified"
+
+	EvidenceVerificationValid    = "valid"
+	EvidenceVerificationMismatch = "mismatch"
+	EvidenceVerificationMissing  = "missing"
+)
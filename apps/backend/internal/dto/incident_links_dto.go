@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// IncidentParentRequest - назначение родительского инцидента; пустое значение снимает связь
+type IncidentParentRequest struct {
+	ParentID *string `json:"parent_id" validate:"omitempty,uuid"`
+}
+
+// IncidentLinkRequest - связь "related to" с другим инцидентом
+type IncidentLinkRequest struct {
+	RelatedIncidentID string  `json:"related_incident_id" validate:"required,uuid"`
+	Comment           *string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+// IncidentMergeRequest - объединение дубликатов в инцидент
+type IncidentMergeRequest struct {
+	IncidentIDs []string `json:"incident_ids" validate:"required,min=1,max=20,dive,uuid"`
+	Reason      *string  `json:"reason,omitempty" validate:"omitempty,max=2000"`
+}
+
+// IncidentSummary - краткие сведения о связанном инциденте
+type IncidentSummary struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	Criticality string    `json:"criticality"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// IncidentLinkResponse - связь "related to"; Incident - инцидент на другой стороне связи
+type IncidentLinkResponse struct {
+	ID        string          `json:"id"`
+	Incident  IncidentSummary `json:"incident"`
+	Comment   *string         `json:"comment"`
+	CreatedBy *string         `json:"created_by"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// IncidentMergeMoved - количество перенесенных в основной инцидент записей
+type IncidentMergeMoved struct {
+	Comments  int `json:"comments"`
+	Actions   int `json:"actions"`
+	Assets    int `json:"assets"`
+	Risks     int `json:"risks"`
+	Documents int `json:"documents"`
+}
+
+// IncidentMergeResponse - запись об объединении инцидентов
+type IncidentMergeResponse struct {
+	ID                string             `json:"id"`
+	PrimaryIncidentID string             `json:"primary_incident_id"`
+	MergedIncident    IncidentSummary    `json:"merged_incident"`
+	Reason            *string            `json:"reason"`
+	Moved             IncidentMergeMoved `json:"moved"`
+	MergedBy          *string            `json:"merged_by"`
+	MergedAt          time.Time          `json:"merged_at"`
+	RevertibleUntil   time.Time          `json:"revertible_until"`
+	CanRevert         bool               `json:"can_revert"`
+	RevertedBy        *string            `json:"reverted_by"`
+	RevertedAt        *time.Time         `json:"reverted_at"`
+}
+
+// IncidentRelationsResponse - иерархия, связи и объединения инцидента
+type IncidentRelationsResponse struct {
+	Parent          *IncidentSummary        `json:"parent"`
+	Children        []IncidentSummary       `json:"children"`
+	Related         []IncidentLinkResponse  `json:"related"`
+	MergedInto      *IncidentSummary        `json:"merged_into"`
+	MergedIncidents []IncidentSummary       `json:"merged_incidents"`
+	Merges          []IncidentMergeResponse `json:"merges"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+import "time"
+
+// IncidentMailboxRequest - настройка почтового ящика POP3 для приема сообщений об инцидентах;
+// пустой пароль при изменении оставляет прежний
+type IncidentMailboxRequest struct {
+	Name               string  `json:"name" validate:"required,min=1,max=255"`
+	Host               string  `json:"host" validate:"required,hostname_rfc1123|ip"`
+	Port               int     `json:"port" validate:"omitempty,min=1,max=65535"`
+	UseTLS             *bool   `json:"use_tls,omitempty"`
+	Username           string  `json:"username" validate:"required,min=1,max=255"`
+	Password           string  `json:"password" validate:"omitempty,max=1000"`
+	DefaultCategory    string  `json:"default_category" validate:"omitempty,oneof=technical_failure data_breach unauthorized_access physical malware social_engineering other"`
+	DefaultCriticality string  `json:"default_criticality" validate:"omitempty,oneof=low medium high critical"`
+	AssignedTo         *string `json:"assigned_to" validate:"omitempty,uuid4"`
+	FallbackReporterID *string `json:"fallback_reporter_id" validate:"omitempty,uuid4"`
+	DeleteAfterImport  bool    `json:"delete_after_import"`
+	IsActive           *bool   `json:"is_active,omitempty"`
+}
+
+// IncidentMailboxResponse - почтовый ящик без пароля
+type IncidentMailboxResponse struct {
+	ID                 string     `json:"id"`
+	Name               string     `json:"name"`
+	Host               string     `json:"host"`
+	Port               int        `json:"port"`
+	UseTLS             bool       `json:"use_tls"`
+	Username           string     `json:"username"`
+	HasPassword        bool       `json:"has_password"`
+	DefaultCategory    string     `json:"default_category"`
+	DefaultCriticality string     `json:"default_criticality"`
+	AssignedTo         *string    `json:"assigned_to"`
+	FallbackReporterID *string    `json:"fallback_reporter_id"`
+	DeleteAfterImport  bool       `json:"delete_after_import"`
+	IsActive           bool       `json:"is_active"`
+	LastPolledAt       *time.Time `json:"last_polled_at"`
+	LastError          *string    `json:"last_error"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// IncidentMailMessageResponse - результат обработки письма
+type IncidentMailMessageResponse struct {
+	ID          string     `json:"id"`
+	MessageID   *string    `json:"message_id"`
+	FromAddress string     `json:"from_address"`
+	Subject     *string    `json:"subject"`
+	Status      string     `json:"status"`
+	IncidentID  *string    `json:"incident_id"`
+	CommentID   *string    `json:"comment_id"`
+	Attachments int        `json:"attachments"`
+	Error       *string    `json:"error"`
+	SentAt      *time.Time `json:"sent_at"`
+	ProcessedAt time.Time  `json:"processed_at"`
+}
+
+// IncidentMailPollResult - итог опроса почтового ящика
+type IncidentMailPollResult struct {
+	MailboxID string    `json:"mailbox_id"`
+	Received  int       `json:"received"`
+	Incidents int       `json:"incidents"`
+	Comments  int       `json:"comments"`
+	Rejected  int       `json:"rejected"`
+	Failed    int       `json:"failed"`
+	Errors    []string  `json:"errors,omitempty"`
+	PolledAt  time.Time `json:"polled_at"`
+}
+
+// Incident mail message statuses
+const (
+	IncidentMailStatusProcessing = "processing" // письмо взято в обработку, итог еще не записан
+	IncidentMailStatusIncident   = "incident"
+	IncidentMailStatusComment    = "comment"
+	IncidentMailStatusRejected   = "rejected"
+	IncidentMailStatusFailed     = "failed"
+)
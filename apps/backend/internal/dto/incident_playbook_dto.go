@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// IncidentPlaybookStepRequest - шаг плейбука реагирования
+type IncidentPlaybookStepRequest struct {
+	ActionType     string  `json:"action_type" validate:"required,oneof=investigation containment eradication recovery prevention"`
+	Title          string  `json:"title" validate:"required,min=1,max=255"`
+	Description    *string `json:"description,omitempty" validate:"omitempty,max=2000"`
+	AssigneeRole   *string `json:"assignee_role,omitempty" validate:"omitempty,min=1,max=100"`
+	DueOffsetHours *int    `json:"due_offset_hours,omitempty" validate:"omitempty,min=1,max=8760"`
+}
+
+// IncidentPlaybookRequest - плейбук организации для категории инцидентов; шаги выполняются в порядке перечисления
+type IncidentPlaybookRequest struct {
+	Name        string                        `json:"name" validate:"required,min=1,max=255"`
+	Description *string                       `json:"description,omitempty" validate:"omitempty,max=2000"`
+	IsActive    *bool                         `json:"is_active,omitempty"`
+	Steps       []IncidentPlaybookStepRequest `json:"steps" validate:"required,min=1,max=50,dive"`
+}
+
+// IncidentPlaybookStepResponse - шаг плейбука
+type IncidentPlaybookStepResponse struct {
+	StepOrder      int     `json:"step_order"`
+	ActionType     string  `json:"action_type"`
+	Title          string  `json:"title"`
+	Description    *string `json:"description"`
+	AssigneeRole   *string `json:"assignee_role"`
+	DueOffsetHours *int    `json:"due_offset_hours"`
+}
+
+// IncidentPlaybookResponse - плейбук реагирования; is_default - встроенный плейбук системы
+type IncidentPlaybookResponse struct {
+	ID          string                         `json:"id"`
+	Category    string                         `json:"category"`
+	Name        string                         `json:"name"`
+	Description *string                        `json:"description"`
+	IsActive    bool                           `json:"is_active"`
+	IsDefault   bool                           `json:"is_default"`
+	Steps       []IncidentPlaybookStepResponse `json:"steps"`
+	UpdatedAt   time.Time                      `json:"updated_at"`
+}
+
+// IncidentActionStatusRequest - изменение статуса действия (шага плейбука)
+type IncidentActionStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending in_progress completed cancelled"`
+}
+
+// IncidentPlaybookProgressResponse - ход выполнения плейбука по инциденту
+type IncidentPlaybookProgressResponse struct {
+	IncidentID     string                   `json:"incident_id"`
+	PlaybookID     *string                  `json:"playbook_id"`
+	PlaybookName   *string                  `json:"playbook_name"`
+	TotalSteps     int                      `json:"total_steps"`
+	CompletedSteps int                      `json:"completed_steps"`
+	OverdueSteps   int                      `json:"overdue_steps"`
+	Progress       float64                  `json:"progress_percent"`
+	IsComplete     bool                     `json:"is_complete"`
+	Steps          []IncidentActionResponse `json:"steps"`
+}
@@ -0,0 +1,114 @@
+package dto
+
+import "time"
+
+// IncidentRegulatoryObligationRequest - обязательство по уведомлению; id передается для сохранения связи с уже созданными уведомлениями
+type IncidentRegulatoryObligationRequest struct {
+	ID            *string `json:"id,omitempty" validate:"omitempty,uuid"`
+	Title         string  `json:"title" validate:"required,min=1,max=255"`
+	RecipientType string  `json:"recipient_type" validate:"required,oneof=regulator data_subjects cert other"`
+	Recipient     string  `json:"recipient" validate:"required,min=1,max=255"`
+	DeadlineHours int     `json:"deadline_hours" validate:"required,min=1,max=8760"`
+	TemplateID    *string `json:"template_id,omitempty" validate:"omitempty,uuid"`
+	Description   *string `json:"description,omitempty" validate:"omitempty,max=2000"`
+}
+
+// IncidentRegulatoryProfileRequest - регуляторный профиль организации
+type IncidentRegulatoryProfileRequest struct {
+	Name        string                                `json:"name" validate:"required,min=1,max=255"`
+	Framework   string                                `json:"framework" validate:"required,oneof=gdpr 152fz gossopka custom"`
+	Categories  []string                              `json:"categories" validate:"omitempty,max=7,dive,oneof=technical_failure data_breach unauthorized_access physical malware social_engineering other"`
+	IsActive    *bool                                 `json:"is_active,omitempty"`
+	Obligations []IncidentRegulatoryObligationRequest `json:"obligations" validate:"required,min=1,max=20,dive"`
+}
+
+// IncidentRegulatoryObligationResponse - обязательство по уведомлению
+type IncidentRegulatoryObligationResponse struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	RecipientType string  `json:"recipient_type"`
+	Recipient     string  `json:"recipient"`
+	DeadlineHours int     `json:"deadline_hours"`
+	TemplateID    *string `json:"template_id"`
+	Description   *string `json:"description"`
+}
+
+// IncidentRegulatoryProfileResponse - регуляторный профиль организации
+type IncidentRegulatoryProfileResponse struct {
+	ID          string                                 `json:"id"`
+	Name        string                                 `json:"name"`
+	Framework   string                                 `json:"framework"`
+	Categories  []string                               `json:"categories"`
+	IsActive    bool                                   `json:"is_active"`
+	Obligations []IncidentRegulatoryObligationResponse `json:"obligations"`
+	CreatedAt   time.Time                              `json:"created_at"`
+	UpdatedAt   time.Time                              `json:"updated_at"`
+}
+
+// IncidentNotificationUpdateRequest - отметка об отправке уведомления или правка черновика
+type IncidentNotificationUpdateRequest struct {
+	Status          *string    `json:"status,omitempty" validate:"omitempty,oneof=pending draft sent not_required"`
+	DraftContent    *string    `json:"draft_content,omitempty" validate:"omitempty,max=100000"`
+	SentAt          *time.Time `json:"sent_at,omitempty"`
+	SentTo          *string    `json:"sent_to,omitempty" validate:"omitempty,max=500"`
+	Channel         *string    `json:"channel,omitempty" validate:"omitempty,max=100"`
+	ReferenceNumber *string    `json:"reference_number,omitempty" validate:"omitempty,max=100"`
+	Notes           *string    `json:"notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+// IncidentNotificationResponse - уведомление по инциденту со сроком исполнения
+type IncidentNotificationResponse struct {
+	ID               string     `json:"id"`
+	IncidentID       string     `json:"incident_id"`
+	IncidentTitle    string     `json:"incident_title"`
+	ObligationID     *string    `json:"obligation_id"`
+	ProfileName      string     `json:"profile_name"`
+	Title            string     `json:"title"`
+	RecipientType    string     `json:"recipient_type"`
+	Recipient        string     `json:"recipient"`
+	DueAt            time.Time  `json:"due_at"`
+	Status           string     `json:"status"`
+	IsOverdue        bool       `json:"is_overdue"`
+	IsLate           bool       `json:"is_late"`
+	RemainingMinutes *int       `json:"remaining_minutes"`
+	DraftContent     *string    `json:"draft_content"`
+	SentAt           *time.Time `json:"sent_at"`
+	SentBy           *string    `json:"sent_by"`
+	SentTo           *string    `json:"sent_to"`
+	Channel          *string    `json:"channel"`
+	ReferenceNumber  *string    `json:"reference_number"`
+	Notes            *string    `json:"notes"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// IncidentNotificationsResponse - уведомления по инциденту и число просроченных обязательств
+type IncidentNotificationsResponse struct {
+	IncidentID    string                         `json:"incident_id"`
+	Pending       int                            `json:"pending"`
+	Overdue       int                            `json:"overdue"`
+	Notifications []IncidentNotificationResponse `json:"notifications"`
+}
+
+// Regulatory notification constants
+const (
+	// Frameworks
+	RegulatoryFrameworkGDPR     = "gdpr"
+	RegulatoryFramework152FZ    = "152fz"
+	RegulatoryFrameworkGosSOPKA = "gossopka"
+	RegulatoryFrameworkCustom   = "custom"
+
+	// Recipient types
+	NotificationRecipientRegulator    = "regulator"
+	NotificationRecipientDataSubjects = "data_subjects"
+	NotificationRecipientCERT         = "cert"
+	NotificationRecipientOther        = "other"
+
+	// Notification status
+	NotificationStatusPending     = "pending"
+	NotificationStatusDraft       = "draft"
+	NotificationStatusSent        = "sent"
+	NotificationStatusNotRequired = "not_required"
+
+	// Template type for notification drafts
+	TemplateTypeBreachNotification = "breach_notification"
+)
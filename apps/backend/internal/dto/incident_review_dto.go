@@ -0,0 +1,110 @@
+package dto
+
+import "time"
+
+// IncidentReviewFactorRequest - способствующий фактор инцидента
+type IncidentReviewFactorRequest struct {
+	FactorType  string `json:"factor_type" validate:"required,oneof=people process technology external other"`
+	Description string `json:"description" validate:"required,min=1,max=2000"`
+}
+
+// IncidentReviewRequest - разбор инцидента (lessons learned); факторы заменяются целиком
+type IncidentReviewRequest struct {
+	Status         *string                       `json:"status,omitempty" validate:"omitempty,oneof=draft completed"`
+	Summary        *string                       `json:"summary,omitempty" validate:"omitempty,max=10000"`
+	RootCause      *string                       `json:"root_cause,omitempty" validate:"omitempty,max=10000"`
+	Impact         *string                       `json:"impact,omitempty" validate:"omitempty,max=10000"`
+	WhatWentWell   *string                       `json:"what_went_well,omitempty" validate:"omitempty,max=10000"`
+	WhatWentWrong  *string                       `json:"what_went_wrong,omitempty" validate:"omitempty,max=10000"`
+	LessonsLearned *string                       `json:"lessons_learned,omitempty" validate:"omitempty,max=10000"`
+	ReviewDate     *string                       `json:"review_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	FacilitatorID  *string                       `json:"facilitator_id,omitempty" validate:"omitempty,uuid"`
+	Participants   []string                      `json:"participants" validate:"omitempty,max=50,dive,min=1,max=255"`
+	Factors        []IncidentReviewFactorRequest `json:"factors" validate:"omitempty,max=50,dive"`
+}
+
+// IncidentReviewFollowUpRequest - последующая мера; для type=risk создается риск, для type=control - мера защиты риска risk_id
+type IncidentReviewFollowUpRequest struct {
+	FollowUpType string  `json:"followup_type" validate:"required,oneof=action risk control"`
+	Title        string  `json:"title" validate:"required,min=1,max=255"`
+	Description  *string `json:"description,omitempty" validate:"omitempty,max=5000"`
+	OwnerID      *string `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+	DueDate      *string `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+
+	// Параметры нового риска
+	RiskCategory *string `json:"risk_category,omitempty" validate:"omitempty,oneof=technical operational compliance financial reputational"`
+	Likelihood   *int    `json:"likelihood,omitempty" validate:"omitempty,min=1,max=4"`
+	Impact       *int    `json:"impact,omitempty" validate:"omitempty,min=1,max=4"`
+
+	// Параметры новой меры защиты
+	RiskID               *string `json:"risk_id,omitempty" validate:"omitempty,uuid"`
+	ControlType          *string `json:"control_type,omitempty" validate:"omitempty,oneof=preventive detective corrective"`
+	ImplementationStatus *string `json:"implementation_status,omitempty" validate:"omitempty,oneof=planned in_progress implemented not_applicable"`
+}
+
+// IncidentReviewFollowUpUpdateRequest - изменение последующей меры
+type IncidentReviewFollowUpUpdateRequest struct {
+	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=5000"`
+	OwnerID     *string `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+	DueDate     *string `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Status      *string `json:"status,omitempty" validate:"omitempty,oneof=open in_progress done cancelled"`
+}
+
+// IncidentReviewFactorResponse - способствующий фактор инцидента
+type IncidentReviewFactorResponse struct {
+	ID          string `json:"id"`
+	FactorType  string `json:"factor_type"`
+	Description string `json:"description"`
+}
+
+// IncidentReviewFollowUpResponse - последующая мера по итогам разбора
+type IncidentReviewFollowUpResponse struct {
+	ID           string     `json:"id"`
+	FollowUpType string     `json:"followup_type"`
+	Title        string     `json:"title"`
+	Description  *string    `json:"description"`
+	OwnerID      *string    `json:"owner_id"`
+	DueDate      *time.Time `json:"due_date"`
+	Status       string     `json:"status"`
+	RiskID       *string    `json:"risk_id"`
+	ControlID    *string    `json:"control_id"`
+	CreatedBy    *string    `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IncidentReviewResponse - разбор инцидента
+type IncidentReviewResponse struct {
+	ID             string                           `json:"id"`
+	IncidentID     string                           `json:"incident_id"`
+	Status         string                           `json:"status"`
+	Summary        *string                          `json:"summary"`
+	RootCause      *string                          `json:"root_cause"`
+	Impact         *string                          `json:"impact"`
+	WhatWentWell   *string                          `json:"what_went_well"`
+	WhatWentWrong  *string                          `json:"what_went_wrong"`
+	LessonsLearned *string                          `json:"lessons_learned"`
+	ReviewDate     *time.Time                       `json:"review_date"`
+	FacilitatorID  *string                          `json:"facilitator_id"`
+	Participants   []string                         `json:"participants"`
+	Factors        []IncidentReviewFactorResponse   `json:"factors"`
+	FollowUps      []IncidentReviewFollowUpResponse `json:"follow_ups"`
+	CompletedAt    *time.Time                       `json:"completed_at"`
+	CreatedBy      *string                          `json:"created_by"`
+	CreatedAt      time.Time                        `json:"created_at"`
+	UpdatedAt      time.Time                        `json:"updated_at"`
+}
+
+// Incident review constants
+const (
+	IncidentReviewStatusDraft     = "draft"
+	IncidentReviewStatusCompleted = "completed"
+
+	ReviewFollowUpTypeAction  = "action"
+	ReviewFollowUpTypeRisk    = "risk"
+	ReviewFollowUpTypeControl = "control"
+
+	ReviewFollowUpStatusOpen      = "open"
+	ReviewFollowUpStatusCancelled = "cancelled"
+)
@@ -0,0 +1,81 @@
+package dto
+
+import "time"
+
+// IncidentTrendRequest - параметры временных рядов по инцидентам
+type IncidentTrendRequest struct {
+	Interval    string `json:"interval" validate:"omitempty,oneof=week month"`
+	From        string `json:"from" validate:"omitempty,datetime=2006-01-02"`
+	To          string `json:"to" validate:"omitempty,datetime=2006-01-02"`
+	Category    string `json:"category" validate:"omitempty,oneof=technical_failure data_breach unauthorized_access physical malware social_engineering other"`
+	Criticality string `json:"criticality" validate:"omitempty,oneof=low medium high critical"`
+	AssetID     string `json:"asset_id" validate:"omitempty,uuid"`
+	AssignedTo  string `json:"assigned_to" validate:"omitempty,uuid"`
+	Compare     string `json:"compare" validate:"omitempty,oneof=previous_period previous_year none"`
+}
+
+// IncidentDurationStats - статистика длительности в часах
+type IncidentDurationStats struct {
+	Count   int      `json:"count"`
+	Average *float64 `json:"average_hours"`
+	P50     *float64 `json:"p50_hours"`
+	P90     *float64 `json:"p90_hours"`
+	P95     *float64 `json:"p95_hours"`
+}
+
+// IncidentTrendBucket - значения за один интервал (неделя или месяц)
+type IncidentTrendBucket struct {
+	PeriodStart time.Time             `json:"period_start"`
+	PeriodEnd   time.Time             `json:"period_end"`
+	Detected    int                   `json:"detected"`
+	Resolved    int                   `json:"resolved"`
+	Closed      int                   `json:"closed"`
+	SLABreaches int                   `json:"sla_breaches"`
+	MTTR        IncidentDurationStats `json:"mttr"`
+	MTTD        IncidentDurationStats `json:"mttd"`
+}
+
+// IncidentTrendSummary - итоги за период
+type IncidentTrendSummary struct {
+	From          time.Time             `json:"from"`
+	To            time.Time             `json:"to"`
+	Detected      int                   `json:"detected"`
+	Resolved      int                   `json:"resolved"`
+	Closed        int                   `json:"closed"`
+	SLABreaches   int                   `json:"sla_breaches"`
+	MTTR          IncidentDurationStats `json:"mttr"`
+	MTTD          IncidentDurationStats `json:"mttd"`
+	ByCategory    map[string]int        `json:"by_category"`
+	ByCriticality map[string]int        `json:"by_criticality"`
+}
+
+// IncidentTrendComparison - сравнение текущего периода с предыдущим; изменения в процентах
+type IncidentTrendComparison struct {
+	Mode              string               `json:"mode"`
+	Previous          IncidentTrendSummary `json:"previous"`
+	DetectedChange    *float64             `json:"detected_change_percent"`
+	ResolvedChange    *float64             `json:"resolved_change_percent"`
+	SLABreachesChange *float64             `json:"sla_breaches_change_percent"`
+	MTTRAverageChange *float64             `json:"mttr_average_change_percent"`
+	MTTRP90Change     *float64             `json:"mttr_p90_change_percent"`
+	MTTDAverageChange *float64             `json:"mttd_average_change_percent"`
+}
+
+// IncidentTrendResponse - временные ряды по инцидентам с итогами и сравнением периодов
+type IncidentTrendResponse struct {
+	Interval   string                   `json:"interval"`
+	Filters    IncidentTrendRequest     `json:"filters"`
+	Series     []IncidentTrendBucket    `json:"series"`
+	Summary    IncidentTrendSummary     `json:"summary"`
+	Comparison *IncidentTrendComparison `json:"comparison,omitempty"`
+}
+
+// Incident trend constants
+const (
+	IncidentTrendIntervalWeek  = "week"
+	IncidentTrendIntervalMonth = "month"
+
+	IncidentTrendComparePreviousPeriod = "previous_period"
+	IncidentTrendComparePreviousYear   = "previous_year"
+	IncidentTrendCompareNone           = "none"
+)
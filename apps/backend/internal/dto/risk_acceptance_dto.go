@@ -0,0 +1,59 @@
+package dto
+
+import "time"
+
+// RiskAcceptanceRequest - запрос на формальное принятие риска
+type RiskAcceptanceRequest struct {
+	Justification string `json:"justification" validate:"required,min=10,max=4000"`
+	ExpiresAt     string `json:"expires_at" validate:"required,datetime=2006-01-02"`
+}
+
+// RiskAcceptanceDecisionRequest - решение утверждающего (утвердить/отклонить/отозвать)
+type RiskAcceptanceDecisionRequest struct {
+	Comment *string `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+// RiskAcceptanceResponse - ответ с данными решения о принятии риска
+type RiskAcceptanceResponse struct {
+	ID              string     `json:"id"`
+	RiskID          string     `json:"risk_id"`
+	Justification   string     `json:"justification"`
+	RiskLevel       *int       `json:"risk_level"`
+	LevelLabel      string     `json:"level_label"`
+	ApproverRole    *string    `json:"approver_role"`
+	Status          string     `json:"status"`
+	RequestedBy     string     `json:"requested_by"`
+	RequestedByName *string    `json:"requested_by_name"`
+	ApproverUserID  *string    `json:"approver_user_id"`
+	ApproverName    *string    `json:"approver_name"`
+	DecisionComment *string    `json:"decision_comment"`
+	DecidedAt       *time.Time `json:"decided_at"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	IsExpired       bool       `json:"is_expired"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// RiskAcceptanceRuleRequest - настройка утверждающего для уровня риска
+type RiskAcceptanceRuleRequest struct {
+	LevelLabel      string  `json:"level_label" validate:"required,oneof=Low Medium High Critical"`
+	ApproverRole    *string `json:"approver_role,omitempty" validate:"omitempty,max=100"`
+	MaxValidityDays int     `json:"max_validity_days" validate:"required,min=1,max=1825"`
+}
+
+// RiskAcceptanceRuleResponse - правило утверждения для уровня риска
+type RiskAcceptanceRuleResponse struct {
+	LevelLabel      string  `json:"level_label"`
+	ApproverRole    *string `json:"approver_role"`
+	MaxValidityDays int     `json:"max_validity_days"`
+	IsDefault       bool    `json:"is_default"`
+}
+
+// Risk acceptance status constants
+const (
+	RiskAcceptanceStatusPending  = "pending"
+	RiskAcceptanceStatusApproved = "approved"
+	RiskAcceptanceStatusRejected = "rejected"
+	RiskAcceptanceStatusExpired  = "expired"
+	RiskAcceptanceStatusRevoked  = "revoked"
+)
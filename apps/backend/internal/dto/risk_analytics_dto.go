@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// RiskAnalyticsFilter - фильтры аналитики рисков
+type RiskAnalyticsFilter struct {
+	Category    string `query:"category" validate:"omitempty,max=100"`
+	OwnerUserID string `query:"owner_user_id" validate:"omitempty,uuid"`
+	AssetID     string `query:"asset_id" validate:"omitempty,uuid"`
+}
+
+// RiskHeatMapCellResponse - ячейка тепловой карты рисков
+type RiskHeatMapCellResponse struct {
+	Likelihood int    `json:"likelihood"`
+	Impact     int    `json:"impact"`
+	Level      int    `json:"level"`
+	LevelLabel string `json:"level_label"`
+	Count      int    `json:"count"`
+}
+
+// RiskHeatMapResponse - тепловая карта рисков (вероятность x влияние)
+type RiskHeatMapResponse struct {
+	Cells []RiskHeatMapCellResponse `json:"cells"`
+	Total int                       `json:"total"`
+}
+
+// RiskLevelCounts - количество рисков по уровням
+type RiskLevelCounts struct {
+	Low      int `json:"low"`
+	Medium   int `json:"medium"`
+	High     int `json:"high"`
+	Critical int `json:"critical"`
+	Total    int `json:"total"`
+}
+
+// RiskLevelTrendPoint - распределение рисков по уровням на конец периода
+type RiskLevelTrendPoint struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	RiskLevelCounts
+}
+
+// RiskAnalyticsSummaryResponse - сводные показатели по рискам
+type RiskAnalyticsSummaryResponse struct {
+	Total             int             `json:"total"`
+	Open              int             `json:"open"`
+	ByStatus          map[string]int  `json:"by_status"`
+	ByLevel           RiskLevelCounts `json:"by_level"`
+	OverdueTreatments int             `json:"overdue_treatments"`
+}
+
+// RiskOverdueTreatmentResponse - просроченная задача плана обработки риска
+type RiskOverdueTreatmentResponse struct {
+	TaskID         string    `json:"task_id"`
+	TaskTitle      string    `json:"task_title"`
+	TaskStatus     string    `json:"task_status"`
+	DueDate        time.Time `json:"due_date"`
+	DaysOverdue    int       `json:"days_overdue"`
+	AssigneeUserID *string   `json:"assignee_user_id"`
+	AssigneeName   *string   `json:"assignee_name"`
+	PlanID         string    `json:"plan_id"`
+	PlanTitle      string    `json:"plan_title"`
+	RiskID         string    `json:"risk_id"`
+	RiskTitle      string    `json:"risk_title"`
+	RiskLevel      *int      `json:"risk_level"`
+}
+
+// Risk trend period constants
+const (
+	RiskTrendPeriodWeek  = "week"
+	RiskTrendPeriodMonth = "month"
+)
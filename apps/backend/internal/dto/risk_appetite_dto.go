@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+// RiskAppetiteStatementRequest - запрос на установку аппетита к риску для категории (пустая категория - значение по умолчанию)
+type RiskAppetiteStatementRequest struct {
+	Category       *string `json:"category,omitempty" validate:"omitempty,max=100"`
+	Statement      string  `json:"statement" validate:"required,min=1,max=4000"`
+	AppetiteLevel  int     `json:"appetite_level" validate:"required,min=1,max=16"`
+	ToleranceLevel int     `json:"tolerance_level" validate:"required,min=1,max=16"`
+}
+
+// RiskAppetiteStatementResponse - заявление об аппетите к риску
+type RiskAppetiteStatementResponse struct {
+	ID             string    `json:"id"`
+	Category       *string   `json:"category"`
+	Statement      string    `json:"statement"`
+	AppetiteLevel  int       `json:"appetite_level"`
+	ToleranceLevel int       `json:"tolerance_level"`
+	UpdatedBy      *string   `json:"updated_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// RiskAppetiteReportRequest - параметры отчёта о рисках вне аппетита
+type RiskAppetiteReportRequest struct {
+	IncludeAboveAppetite bool `query:"include_above_appetite"` // включать риски выше аппетита, но в пределах толерантности
+}
+
+// RiskAppetiteReportItem - риск, превышающий аппетит или толерантность
+type RiskAppetiteReportItem struct {
+	Risk           RiskResponse `json:"risk"`
+	AppetiteStatus string       `json:"appetite_status"`
+	HasTreatment   bool         `json:"has_treatment"`
+	HasAcceptance  bool         `json:"has_acceptance"`
+	RequiresAction bool         `json:"requires_action"` // превышена толерантность, но нет ни плана обработки, ни принятия
+}
+
+// RiskAppetiteReportResponse - отчёт о рисках вне аппетита
+type RiskAppetiteReportResponse struct {
+	Items           []RiskAppetiteReportItem `json:"items"`
+	OutOfTolerance  int                      `json:"out_of_tolerance"`
+	AboveAppetite   int                      `json:"above_appetite"`
+	RequiringAction int                      `json:"requiring_action"`
+}
+
+// Risk appetite status constants
+const (
+	RiskAppetiteStatusWithin         = "within"
+	RiskAppetiteStatusAboveAppetite  = "above_appetite"
+	RiskAppetiteStatusOutOfTolerance = "out_of_tolerance"
+)
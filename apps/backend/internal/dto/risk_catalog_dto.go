@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// RiskCatalogEntryRequest - запрос на создание/обновление угрозы или уязвимости в каталоге организации
+type RiskCatalogEntryRequest struct {
+	Code        string   `json:"code" validate:"required,min=1,max=50"`
+	Name        string   `json:"name" validate:"required,min=1,max=500"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=4000"`
+	Source      string   `json:"source,omitempty" validate:"omitempty,oneof=bdu_fstec custom"`
+	AssetTypes  []string `json:"asset_types" validate:"omitempty,dive,oneof=server workstation application database document network_device other"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+// RiskCatalogEntryResponse - угроза или уязвимость из каталога
+type RiskCatalogEntryResponse struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Source      string    `json:"source"`
+	AssetTypes  []string  `json:"asset_types"`
+	IsActive    bool      `json:"is_active"`
+	IsBuiltin   bool      `json:"is_builtin"` // встроенные записи доступны только для чтения
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RiskCatalogListRequest - фильтры каталога угроз/уязвимостей
+type RiskCatalogListRequest struct {
+	AssetType string `query:"asset_type" validate:"omitempty,oneof=server workstation application database document network_device other"`
+	Source    string `query:"source" validate:"omitempty,oneof=builtin bdu_fstec custom"`
+	Search    string `query:"search" validate:"omitempty,max=255"`
+	Active    bool   `query:"active"`
+}
+
+// RiskCatalogSuggestionsResponse - угрозы и уязвимости, применимые к типу актива
+type RiskCatalogSuggestionsResponse struct {
+	AssetType       string                     `json:"asset_type"`
+	Threats         []RiskCatalogEntryResponse `json:"threats"`
+	Vulnerabilities []RiskCatalogEntryResponse `json:"vulnerabilities"`
+}
+
+// Risk catalog kind constants
+const (
+	RiskCatalogKindThreat        = "threat"
+	RiskCatalogKindVulnerability = "vulnerability"
+)
+
+// Risk catalog source constants
+const (
+	RiskCatalogSourceBuiltin  = "builtin"
+	RiskCatalogSourceBDUFSTEC = "bdu_fstec"
+	RiskCatalogSourceCustom   = "custom"
+)
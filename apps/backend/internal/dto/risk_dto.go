@@ -7,34 +7,37 @@ import (
 // CalculateRiskLevel - вычисляет уровень риска на основе likelihood и impact (1-4 шкала)
 func CalculateRiskLevel(likelihood, impact int) (int, string) {
 	level := likelihood * impact
+	return level, RiskLevelLabelFor(level)
+}
 
-	var label string
+// RiskLevelLabelFor - возвращает метку для значения уровня риска (likelihood * impact)
+func RiskLevelLabelFor(level int) string {
 	switch {
 	case level <= 2:
-		label = RiskLevelLabelLow
+		return RiskLevelLabelLow
 	case level <= 4:
-		label = RiskLevelLabelMedium
+		return RiskLevelLabelMedium
 	case level <= 6:
-		label = RiskLevelLabelHigh
+		return RiskLevelLabelHigh
 	default: // 7-8
-		label = RiskLevelLabelCritical
+		return RiskLevelLabelCritical
 	}
-
-	return level, label
 }
 
 // RiskRequest - базовый запрос для риска
 type RiskRequest struct {
-	Title       string  `json:"title" validate:"required,min=1,max=255"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	Category    *string `json:"category,omitempty" validate:"omitempty,max=100"`
-	Likelihood  int     `json:"likelihood" validate:"required,min=1,max=4"`
-	Impact      int     `json:"impact" validate:"required,min=1,max=4"`
-	OwnerUserID *string `json:"owner_user_id,omitempty" validate:"omitempty,uuid4"`
-	AssetID     *string `json:"asset_id,omitempty" validate:"omitempty,uuid4"`
-	Methodology *string `json:"methodology,omitempty" validate:"omitempty,oneof=ISO27005 NIST COSO Custom"`
-	Strategy    *string `json:"strategy,omitempty" validate:"omitempty,oneof=accept mitigate transfer avoid"`
-	DueDate     *string `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Title           string  `json:"title" validate:"required,min=1,max=255"`
+	Description     *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Category        *string `json:"category,omitempty" validate:"omitempty,max=100"`
+	Likelihood      int     `json:"likelihood" validate:"required,min=1,max=4"`
+	Impact          int     `json:"impact" validate:"required,min=1,max=4"`
+	OwnerUserID     *string `json:"owner_user_id,omitempty" validate:"omitempty,uuid4"`
+	AssetID         *string `json:"asset_id,omitempty" validate:"omitempty,uuid4"`
+	ThreatID        *string `json:"threat_id,omitempty" validate:"omitempty,uuid"`
+	VulnerabilityID *string `json:"vulnerability_id,omitempty" validate:"omitempty,uuid"`
+	Methodology     *string `json:"methodology,omitempty" validate:"omitempty,oneof=ISO27005 NIST COSO Custom"`
+	Strategy        *string `json:"strategy,omitempty" validate:"omitempty,oneof=accept mitigate transfer avoid"`
+	DueDate         *string `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
 }
 
 // CreateRiskRequest - запрос на создание риска
@@ -44,39 +47,54 @@ type CreateRiskRequest struct {
 
 // UpdateRiskRequest - запрос на обновление риска
 type UpdateRiskRequest struct {
-	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	Category    *string `json:"category,omitempty" validate:"omitempty,max=100"`
-	Likelihood  *int    `json:"likelihood,omitempty" validate:"omitempty,min=1,max=4"`
-	Impact      *int    `json:"impact,omitempty" validate:"omitempty,min=1,max=4"`
-	OwnerUserID *string `json:"owner_user_id,omitempty" validate:"omitempty,uuid4"`
-	AssetID     *string `json:"asset_id,omitempty" validate:"omitempty,uuid4"`
-	Methodology *string `json:"methodology,omitempty" validate:"omitempty,oneof=ISO27005 NIST COSO Custom"`
-	Strategy    *string `json:"strategy,omitempty" validate:"omitempty,oneof=accept mitigate transfer avoid"`
-	DueDate     *string `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Title           *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Description     *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Category        *string `json:"category,omitempty" validate:"omitempty,max=100"`
+	Likelihood      *int    `json:"likelihood,omitempty" validate:"omitempty,min=1,max=4"`
+	Impact          *int    `json:"impact,omitempty" validate:"omitempty,min=1,max=4"`
+	OwnerUserID     *string `json:"owner_user_id,omitempty" validate:"omitempty,uuid4"`
+	AssetID         *string `json:"asset_id,omitempty" validate:"omitempty,uuid4"`
+	ThreatID        *string `json:"threat_id,omitempty" validate:"omitempty,uuid"`
+	VulnerabilityID *string `json:"vulnerability_id,omitempty" validate:"omitempty,uuid"`
+	Methodology     *string `json:"methodology,omitempty" validate:"omitempty,oneof=ISO27005 NIST COSO Custom"`
+	Strategy        *string `json:"strategy,omitempty" validate:"omitempty,oneof=accept mitigate transfer avoid"`
+	DueDate         *string `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
 }
 
 // RiskResponse - ответ с данными риска
 type RiskResponse struct {
-	ID          string     `json:"id"`
-	TenantID    string     `json:"tenant_id"`
-	Title       string     `json:"title"`
-	Description *string    `json:"description"`
-	Category    *string    `json:"category"`
-	Likelihood  *int       `json:"likelihood"`
-	Impact      *int       `json:"impact"`
-	Level       *int       `json:"level"`
-	Status      string     `json:"status"`
-	OwnerUserID *string    `json:"owner_user_id"`
-	AssetID     *string    `json:"asset_id"`
-	Methodology *string    `json:"methodology"`
-	Strategy    *string    `json:"strategy"`
-	DueDate     *time.Time `json:"due_date"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	OwnerName   *string    `json:"owner_name,omitempty"`
-	AssetName   *string    `json:"asset_name,omitempty"`
-	LevelLabel  *string    `json:"level_label,omitempty"`
+	ID                string     `json:"id"`
+	TenantID          string     `json:"tenant_id"`
+	Title             string     `json:"title"`
+	Description       *string    `json:"description"`
+	Category          *string    `json:"category"`
+	Likelihood        *int       `json:"likelihood"`
+	Impact            *int       `json:"impact"`
+	Level             *int       `json:"level"`
+	Status            string     `json:"status"`
+	OwnerUserID       *string    `json:"owner_user_id"`
+	AssetID           *string    `json:"asset_id"`
+	ThreatID          *string    `json:"threat_id"`
+	VulnerabilityID   *string    `json:"vulnerability_id"`
+	Methodology       *string    `json:"methodology"`
+	Strategy          *string    `json:"strategy"`
+	DueDate           *time.Time `json:"due_date"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	OwnerName         *string    `json:"owner_name,omitempty"`
+	AssetName         *string    `json:"asset_name,omitempty"`
+	LevelLabel        *string    `json:"level_label,omitempty"`
+	TreatmentProgress *int       `json:"treatment_progress"`
+	NextReviewDate    *time.Time `json:"next_review_date"`
+	LastReviewedAt    *time.Time `json:"last_reviewed_at"`
+	LastReviewedBy    *string    `json:"last_reviewed_by"`
+	IsReviewOverdue   bool       `json:"is_review_overdue"`
+	ALEMean           *float64   `json:"ale_mean"`
+	ALEP90            *float64   `json:"ale_p90"`
+	QuantifiedAt      *time.Time `json:"quantified_at"`
+	AppetiteLevel     *int       `json:"appetite_level"`
+	ToleranceLevel    *int       `json:"tolerance_level"`
+	AppetiteStatus    *string    `json:"appetite_status"`
 }
 
 // RiskListRequest - запрос на получение списка рисков
@@ -0,0 +1,42 @@
+package dto
+
+// RiskImportRowResponse - результат проверки одной строки файла импорта
+type RiskImportRowResponse struct {
+	RowNumber            int         `json:"row_number"` // номер строки в файле, заголовок - строка 1
+	Risk                 RiskRequest `json:"risk"`
+	OwnerEmail           string      `json:"owner_email,omitempty"`
+	AssetInventoryNumber string      `json:"asset_inventory_number,omitempty"`
+	Valid                bool        `json:"valid"`
+	Errors               []string    `json:"errors"`
+}
+
+// RiskImportPreviewResponse - предварительный просмотр импорта рисков
+type RiskImportPreviewResponse struct {
+	Headers     []string                `json:"headers"`
+	Mapping     map[string]string       `json:"mapping"` // поле риска -> заголовок колонки
+	TotalRows   int                     `json:"total_rows"`
+	ValidRows   int                     `json:"valid_rows"`
+	InvalidRows int                     `json:"invalid_rows"`
+	Rows        []RiskImportRowResponse `json:"rows"`
+}
+
+// RiskImportResultResponse - результат импорта рисков
+type RiskImportResultResponse struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	RiskIDs  []string `json:"risk_ids"`
+}
+
+// Risk import fields
+const (
+	RiskImportFieldTitle       = "title"
+	RiskImportFieldDescription = "description"
+	RiskImportFieldCategory    = "category"
+	RiskImportFieldLikelihood  = "likelihood"
+	RiskImportFieldImpact      = "impact"
+	RiskImportFieldOwnerEmail  = "owner_email"
+	RiskImportFieldAsset       = "asset_inventory_number"
+	RiskImportFieldMethodology = "methodology"
+	RiskImportFieldStrategy    = "strategy"
+	RiskImportFieldDueDate     = "due_date"
+)
@@ -0,0 +1,99 @@
+package dto
+
+import "time"
+
+// RiskKRIRequest - запрос на создание/обновление ключевого индикатора риска
+type RiskKRIRequest struct {
+	Code           string   `json:"code" validate:"required,min=1,max=50"`
+	Name           string   `json:"name" validate:"required,min=1,max=255"`
+	Description    *string  `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Unit           *string  `json:"unit,omitempty" validate:"omitempty,max=50"`
+	Direction      string   `json:"direction" validate:"required,oneof=higher_is_worse lower_is_worse"`
+	AmberThreshold *float64 `json:"amber_threshold" validate:"required"`
+	RedThreshold   *float64 `json:"red_threshold" validate:"required"`
+	Frequency      string   `json:"frequency" validate:"required,oneof=daily weekly monthly quarterly"`
+	OwnerUserID    *string  `json:"owner_user_id,omitempty" validate:"omitempty,uuid"`
+	IsActive       *bool    `json:"is_active,omitempty"`
+	RiskIDs        []string `json:"risk_ids" validate:"omitempty,dive,uuid"`
+}
+
+// RiskKRIResponse - ответ с данными ключевого индикатора риска
+type RiskKRIResponse struct {
+	ID             string     `json:"id"`
+	Code           string     `json:"code"`
+	Name           string     `json:"name"`
+	Description    *string    `json:"description"`
+	Unit           *string    `json:"unit"`
+	Direction      string     `json:"direction"`
+	AmberThreshold float64    `json:"amber_threshold"`
+	RedThreshold   float64    `json:"red_threshold"`
+	Frequency      string     `json:"frequency"`
+	OwnerUserID    *string    `json:"owner_user_id"`
+	OwnerName      *string    `json:"owner_name"`
+	IsActive       bool       `json:"is_active"`
+	CurrentValue   *float64   `json:"current_value"`
+	CurrentStatus  string     `json:"current_status"`
+	LastMeasuredAt *time.Time `json:"last_measured_at"`
+	IsStale        bool       `json:"is_stale"`
+	RiskIDs        []string   `json:"risk_ids"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// RiskKRIMeasurementRequest - ручной ввод измерения KRI
+type RiskKRIMeasurementRequest struct {
+	Value      *float64 `json:"value" validate:"required"`
+	MeasuredAt *string  `json:"measured_at,omitempty"` // RFC3339 или YYYY-MM-DD, по умолчанию - текущее время
+	Comment    *string  `json:"comment,omitempty" validate:"omitempty,max=2000"`
+}
+
+// RiskKRIPushMeasurement - измерение, переданное внешней системой по коду KRI
+type RiskKRIPushMeasurement struct {
+	Code string `json:"code" validate:"required,max=50"`
+	RiskKRIMeasurementRequest
+}
+
+// RiskKRIPushRequest - пакетная передача измерений KRI через API
+type RiskKRIPushRequest struct {
+	Measurements []RiskKRIPushMeasurement `json:"measurements" validate:"required,min=1,max=500,dive"`
+}
+
+// RiskKRIMeasurementResponse - измерение KRI
+type RiskKRIMeasurementResponse struct {
+	ID         string    `json:"id"`
+	KRIID      string    `json:"kri_id"`
+	Value      float64   `json:"value"`
+	Status     string    `json:"status"`
+	MeasuredAt time.Time `json:"measured_at"`
+	Source     string    `json:"source"`
+	Comment    *string   `json:"comment"`
+	RecordedBy *string   `json:"recorded_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RiskKRIPushResult - результат обработки одного измерения из пакета
+type RiskKRIPushResult struct {
+	Code   string  `json:"code"`
+	Status string  `json:"status,omitempty"`
+	Error  *string `json:"error,omitempty"`
+}
+
+// KRI direction constants
+const (
+	KRIDirectionHigherIsWorse = "higher_is_worse"
+	KRIDirectionLowerIsWorse  = "lower_is_worse"
+)
+
+// KRI status constants
+const (
+	KRIStatusUnknown = "unknown"
+	KRIStatusGreen   = "green"
+	KRIStatusAmber   = "amber"
+	KRIStatusRed     = "red"
+)
+
+// KRI measurement source constants
+const (
+	KRISourceManual = "manual"
+	KRISourceAPI    = "api"
+)
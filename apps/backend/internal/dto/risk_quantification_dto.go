@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// RiskQuantificationRequest - входные параметры количественной оценки (FAIR): оценки min / наиболее вероятное / max
+type RiskQuantificationRequest struct {
+	LEFMin        *float64 `json:"lef_min" validate:"required,min=0,max=10000"` // частота событий потерь, раз в год
+	LEFMostLikely *float64 `json:"lef_most_likely" validate:"required,min=0,max=10000,gtefield=LEFMin"`
+	LEFMax        *float64 `json:"lef_max" validate:"required,min=0,max=10000,gtefield=LEFMostLikely"`
+	LMMin         *float64 `json:"lm_min" validate:"required,min=0,max=1e11"` // величина потерь одного события; при LEF <= 10000 годовые потери укладываются в NUMERIC(18,2)
+	LMMostLikely  *float64 `json:"lm_most_likely" validate:"required,min=0,max=1e11,gtefield=LMMin"`
+	LMMax         *float64 `json:"lm_max" validate:"required,min=0,max=1e11,gtefield=LMMostLikely"`
+	Currency      string   `json:"currency,omitempty" validate:"omitempty,len=3,uppercase"`
+}
+
+// RiskQuantificationResponse - входные параметры количественной оценки и последний результат моделирования
+type RiskQuantificationResponse struct {
+	RiskID           string                  `json:"risk_id"`
+	LEFMin           float64                 `json:"lef_min"`
+	LEFMostLikely    float64                 `json:"lef_most_likely"`
+	LEFMax           float64                 `json:"lef_max"`
+	LMMin            float64                 `json:"lm_min"`
+	LMMostLikely     float64                 `json:"lm_most_likely"`
+	LMMax            float64                 `json:"lm_max"`
+	Currency         string                  `json:"currency"`
+	UpdatedBy        *string                 `json:"updated_by"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+	LatestSimulation *RiskSimulationResponse `json:"latest_simulation"`
+}
+
+// RiskSimulationRequest - параметры запуска моделирования Монте-Карло
+type RiskSimulationRequest struct {
+	Iterations int    `json:"iterations,omitempty" validate:"omitempty,min=1000,max=100000"`
+	Seed       *int64 `json:"seed,omitempty"` // для воспроизводимости результата
+}
+
+// LossExceedancePointResponse - точка кривой превышения потерь
+type LossExceedancePointResponse struct {
+	Loss        float64 `json:"loss"`
+	Probability float64 `json:"probability"`
+}
+
+// RiskSimulationResponse - результат моделирования годовых потерь (ALE)
+type RiskSimulationResponse struct {
+	ID              string                        `json:"id"`
+	RiskID          string                        `json:"risk_id"`
+	Iterations      int                           `json:"iterations"`
+	Seed            int64                         `json:"seed"`
+	Currency        string                        `json:"currency"`
+	MeanLoss        float64                       `json:"mean_loss"`
+	Percentiles     map[string]float64            `json:"percentiles"`
+	MaxLoss         float64                       `json:"max_loss"`
+	LossProbability float64                       `json:"loss_probability"`
+	ExceedanceCurve []LossExceedancePointResponse `json:"exceedance_curve"`
+	Inputs          map[string]float64            `json:"inputs"`
+	CreatedBy       *string                       `json:"created_by"`
+	CreatedAt       time.Time                     `json:"created_at"`
+}
+
+// Quantification defaults
+const (
+	RiskQuantificationDefaultCurrency   = "RUB"
+	RiskSimulationDefaultIterations     = 10000
+	RiskSimulationExceedanceCurvePoints = 50
+	// RiskSimulationEventBudget ограничивает iterations × lef_max: число выборок величины потерь за один запуск
+	RiskSimulationEventBudget = 20_000_000
+)
@@ -53,9 +53,10 @@ type RiskHistoryResponse struct {
 	OldValue      *string   `json:"old_value"`
 	NewValue      *string   `json:"new_value"`
 	ChangeReason  *string   `json:"change_reason"`
-	ChangedBy     string    `json:"changed_by"`
+	ChangedBy     string    `json:"changed_by"` // пусто для системных записей
 	ChangedAt     time.Time `json:"changed_at"`
 	ChangedByName *string   `json:"changed_by_name"`
+	IsSystem      bool      `json:"is_system"`
 }
 
 // RiskAttachmentRequest - запрос для добавления вложения к риску
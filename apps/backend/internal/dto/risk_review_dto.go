@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+// RiskReviewRequest - запрос на периодический пересмотр риска
+// Если вероятность и влияние не переданы, текущая оценка подтверждается
+type RiskReviewRequest struct {
+	Likelihood *int   `json:"likelihood,omitempty" validate:"omitempty,min=1,max=4"`
+	Impact     *int   `json:"impact,omitempty" validate:"omitempty,min=1,max=4"`
+	Comment    string `json:"comment" validate:"required,min=1,max=2000"`
+}
+
+// RiskReviewResponse - запись о пересмотре риска (из истории риска)
+type RiskReviewResponse struct {
+	ID             string    `json:"id"`
+	RiskID         string    `json:"risk_id"`
+	Outcome        string    `json:"outcome"`
+	OldAssessment  *string   `json:"old_assessment"`
+	NewAssessment  *string   `json:"new_assessment"`
+	Comment        *string   `json:"comment"`
+	ReviewedBy     string    `json:"reviewed_by"`
+	ReviewedByName *string   `json:"reviewed_by_name"`
+	ReviewedAt     time.Time `json:"reviewed_at"`
+}
+
+// RiskReviewIntervalRequest - настройка периодичности пересмотра для категории или уровня
+type RiskReviewIntervalRequest struct {
+	Scope        string `json:"scope" validate:"required,oneof=category level"`
+	ScopeValue   string `json:"scope_value" validate:"required,min=1,max=100"`
+	IntervalDays int    `json:"interval_days" validate:"required,min=1,max=1825"`
+}
+
+// RiskReviewIntervalResponse - периодичность пересмотра рисков
+type RiskReviewIntervalResponse struct {
+	ID           *string `json:"id"`
+	Scope        string  `json:"scope"`
+	ScopeValue   string  `json:"scope_value"`
+	IntervalDays int     `json:"interval_days"`
+	IsDefault    bool    `json:"is_default"`
+}
+
+// Risk review scope constants
+const (
+	RiskReviewScopeCategory = "category"
+	RiskReviewScopeLevel    = "level"
+)
+
+// Risk review outcome constants
+const (
+	RiskReviewOutcomeConfirmed = "confirmed"
+	RiskReviewOutcomeUpdated   = "updated"
+)
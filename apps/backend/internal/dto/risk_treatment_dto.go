@@ -0,0 +1,102 @@
+package dto
+
+import "time"
+
+// RiskTreatmentPlanRequest - запрос на создание/обновление плана обработки риска
+type RiskTreatmentPlanRequest struct {
+	Title       string   `json:"title" validate:"required,min=1,max=255"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Strategy    string   `json:"strategy" validate:"required,oneof=accept mitigate transfer avoid"`
+	Status      *string  `json:"status,omitempty" validate:"omitempty,oneof=draft active completed cancelled"`
+	OwnerUserID *string  `json:"owner_user_id,omitempty" validate:"omitempty,uuid4"`
+	Budget      *float64 `json:"budget,omitempty" validate:"omitempty,min=0"`
+	Currency    *string  `json:"currency,omitempty" validate:"omitempty,len=3"`
+	StartDate   *string  `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	DueDate     *string  `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// RiskTreatmentPlanResponse - ответ с данными плана обработки риска
+type RiskTreatmentPlanResponse struct {
+	ID          string                      `json:"id"`
+	RiskID      string                      `json:"risk_id"`
+	Title       string                      `json:"title"`
+	Description *string                     `json:"description"`
+	Strategy    string                      `json:"strategy"`
+	Status      string                      `json:"status"`
+	OwnerUserID *string                     `json:"owner_user_id"`
+	OwnerName   *string                     `json:"owner_name"`
+	Budget      *float64                    `json:"budget"`
+	Spent       float64                     `json:"spent"`
+	Currency    string                      `json:"currency"`
+	StartDate   *time.Time                  `json:"start_date"`
+	DueDate     *time.Time                  `json:"due_date"`
+	Progress    int                         `json:"progress"`
+	TasksTotal  int                         `json:"tasks_total"`
+	TasksDone   int                         `json:"tasks_done"`
+	CompletedAt *time.Time                  `json:"completed_at"`
+	CreatedBy   *string                     `json:"created_by"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+	Tasks       []RiskTreatmentTaskResponse `json:"tasks"`
+}
+
+// RiskTreatmentTaskRequest - запрос на создание/обновление задачи плана обработки
+type RiskTreatmentTaskRequest struct {
+	Title          string   `json:"title" validate:"required,min=1,max=255"`
+	Description    *string  `json:"description,omitempty" validate:"omitempty,max=2000"`
+	AssigneeUserID *string  `json:"assignee_user_id,omitempty" validate:"omitempty,uuid4"`
+	DueDate        *string  `json:"due_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Cost           *float64 `json:"cost,omitempty" validate:"omitempty,min=0"`
+}
+
+// RiskTreatmentTaskStatusRequest - запрос на смену статуса задачи (с подтверждением выполнения)
+type RiskTreatmentTaskStatusRequest struct {
+	Status             string  `json:"status" validate:"required,oneof=open in_progress done cancelled"`
+	Evidence           *string `json:"evidence,omitempty" validate:"omitempty,max=2000"`
+	EvidenceDocumentID *string `json:"evidence_document_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// RiskTreatmentTaskOrderRequest - запрос на изменение порядка задач плана
+type RiskTreatmentTaskOrderRequest struct {
+	TaskIDs []string `json:"task_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// RiskTreatmentTaskResponse - ответ с данными задачи плана обработки
+type RiskTreatmentTaskResponse struct {
+	ID                 string     `json:"id"`
+	PlanID             string     `json:"plan_id"`
+	Position           int        `json:"position"`
+	Title              string     `json:"title"`
+	Description        *string    `json:"description"`
+	AssigneeUserID     *string    `json:"assignee_user_id"`
+	AssigneeName       *string    `json:"assignee_name"`
+	DueDate            *time.Time `json:"due_date"`
+	Status             string     `json:"status"`
+	Cost               *float64   `json:"cost"`
+	Evidence           *string    `json:"evidence"`
+	EvidenceDocumentID *string    `json:"evidence_document_id"`
+	CompletedBy        *string    `json:"completed_by"`
+	CompletedAt        *time.Time `json:"completed_at"`
+	IsOverdue          bool       `json:"is_overdue"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// Treatment plan status constants
+const (
+	TreatmentPlanStatusDraft     = "draft"
+	TreatmentPlanStatusActive    = "active"
+	TreatmentPlanStatusCompleted = "completed"
+	TreatmentPlanStatusCancelled = "cancelled"
+)
+
+// Treatment task status constants
+const (
+	TreatmentTaskStatusOpen       = "open"
+	TreatmentTaskStatusInProgress = "in_progress"
+	TreatmentTaskStatusDone       = "done"
+	TreatmentTaskStatusCancelled  = "cancelled"
+)
+
+// DefaultTreatmentCurrency - валюта бюджета плана по умолчанию
+const DefaultTreatmentCurrency = "RUB"
@@ -21,7 +21,7 @@ type DocumentTemplateDTO struct {
 type CreateTemplateRequest struct {
 	Name         string  `json:"name" validate:"required,min=3,max=255"`
 	Description  *string `json:"description"`
-	TemplateType string  `json:"template_type" validate:"required,oneof=passport_pc passport_monitor passport_device transfer_act writeoff_act repair_log other"`
+	TemplateType string  `json:"template_type" validate:"required,oneof=passport_pc passport_monitor passport_device transfer_act writeoff_act repair_log breach_notification other"`
 	Content      string  `json:"content" validate:"required"`
 }
 
@@ -29,7 +29,7 @@ type CreateTemplateRequest struct {
 type UpdateTemplateRequest struct {
 	Name         *string `json:"name" validate:"omitempty,min=3,max=255"`
 	Description  *string `json:"description"`
-	TemplateType *string `json:"template_type" validate:"omitempty,oneof=passport_pc passport_monitor passport_device transfer_act writeoff_act repair_log other"`
+	TemplateType *string `json:"template_type" validate:"omitempty,oneof=passport_pc passport_monitor passport_device transfer_act writeoff_act repair_log breach_notification other"`
 	Content      *string `json:"content"`
 	IsActive     *bool   `json:"is_active"`
 }
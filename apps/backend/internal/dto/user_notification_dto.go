@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// UserNotificationResponse - уведомление во входящих пользователя
+type UserNotificationResponse struct {
+	ID        string     `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	Entity    *string    `json:"entity"`
+	EntityID  *string    `json:"entity_id"`
+	IsRead    bool       `json:"is_read"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+// User notification types
+const (
+	UserNotificationRiskReviewDue      = "risk_review_due"
+	UserNotificationIncidentSLAWarning = "incident_sla_warning"
+	UserNotificationIncidentSLABreach  = "incident_sla_breach"
+
+	UserNotificationRegulatoryDeadlineStarted = "regulatory_deadline_started"
+	UserNotificationRegulatoryDeadlineDueSoon = "regulatory_deadline_due_soon"
+	UserNotificationRegulatoryDeadlineOverdue = "regulatory_deadline_overdue"
+)
+
+// UserNotificationDefaultLimit - число уведомлений в выдаче по умолчанию
+const UserNotificationDefaultLimit = 50
@@ -0,0 +1,247 @@
+package http
+
+import (
+	"errors"
+	"log"
+
+	"risknexus/backend/internal/domain"
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterPublic registers the discovery report endpoint; agents authenticate with their own token instead of a user session
+func (h *AssetHandler) RegisterPublic(r fiber.Router) {
+	r.Post("/assets/discovery/report", h.ingestDiscoveryReport)
+}
+
+func (h *AssetHandler) ingestDiscoveryReport(c *fiber.Ctx) error {
+	report, err := h.assetService.IngestDiscoveryReport(c.Context(), publicToken(c, "X-Agent-Token"), c.Body())
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.ingestDiscoveryReport service error: %v", err)
+		switch {
+		case errors.Is(err, domain.ErrDiscoveryAgentUnauthorized):
+			return c.Status(401).JSON(fiber.Map{"error": "Invalid or inactive agent token"})
+		case errors.Is(err, domain.ErrInvalidDiscoveryReport):
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid discovery report", "details": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to process discovery report"})
+		}
+	}
+
+	return c.Status(202).JSON(convertToDiscoveryReportResponse(report, false))
+}
+
+// Discovery agent endpoints
+func (h *AssetHandler) listDiscoveryAgents(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	agents, err := h.assetService.ListDiscoveryAgents(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listDiscoveryAgents service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get discovery agents"})
+	}
+
+	responses := make([]dto.AssetDiscoveryAgentResponse, 0, len(agents))
+	for _, agent := range agents {
+		responses = append(responses, convertToDiscoveryAgentResponse(agent, ""))
+	}
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *AssetHandler) createDiscoveryAgent(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.AssetDiscoveryAgentRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.createDiscoveryAgent invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.createDiscoveryAgent validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	agent, token, err := h.assetService.CreateDiscoveryAgent(c.Context(), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.createDiscoveryAgent service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to create discovery agent")
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": convertToDiscoveryAgentResponse(agent, token)})
+}
+
+func (h *AssetHandler) updateDiscoveryAgent(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	agentID := c.Params("agent_id")
+
+	var req dto.AssetDiscoveryAgentRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.updateDiscoveryAgent invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.updateDiscoveryAgent validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	agent, err := h.assetService.UpdateDiscoveryAgent(c.Context(), agentID, tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.updateDiscoveryAgent service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to update discovery agent")
+	}
+
+	return c.JSON(fiber.Map{"data": convertToDiscoveryAgentResponse(agent, "")})
+}
+
+func (h *AssetHandler) rotateDiscoveryAgentToken(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	agentID := c.Params("agent_id")
+
+	agent, token, err := h.assetService.RotateDiscoveryAgentToken(c.Context(), agentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.rotateDiscoveryAgentToken service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to rotate discovery agent token")
+	}
+
+	return c.JSON(fiber.Map{"data": convertToDiscoveryAgentResponse(agent, token)})
+}
+
+func (h *AssetHandler) deleteDiscoveryAgent(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	agentID := c.Params("agent_id")
+
+	if err := h.assetService.DeleteDiscoveryAgent(c.Context(), agentID, tenantID); err != nil {
+		log.Printf("ERROR: AssetHandler.deleteDiscoveryAgent service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to delete discovery agent")
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Discovery agent deleted successfully"})
+}
+
+// Discovery report endpoints
+func (h *AssetHandler) listDiscoveryReports(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	page := c.QueryInt("page", 1)
+	pageSize := c.QueryInt("page_size", 20)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 20
+	}
+
+	filters := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if assetID := c.Query("asset_id"); assetID != "" {
+		filters["asset_id"] = assetID
+	}
+
+	reports, total, err := h.assetService.ListDiscoveryReports(c.Context(), tenantID, filters, page, pageSize)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listDiscoveryReports service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to get discovery reports"})
+	}
+
+	responses := make([]dto.AssetDiscoveryReportResponse, 0, len(reports))
+	for _, report := range reports {
+		responses = append(responses, convertToDiscoveryReportResponse(report, false))
+	}
+	return c.JSON(fiber.Map{
+		"data": responses,
+		"pagination": fiber.Map{
+			"page":        page,
+			"page_size":   pageSize,
+			"total":       total,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+func (h *AssetHandler) getDiscoveryReport(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	reportID := c.Params("report_id")
+
+	report, err := h.assetService.GetDiscoveryReport(c.Context(), reportID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.getDiscoveryReport service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get discovery report")
+	}
+
+	return c.JSON(fiber.Map{"data": convertToDiscoveryReportResponse(report, true)})
+}
+
+func (h *AssetHandler) resolveDiscoveryReport(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	reportID := c.Params("report_id")
+
+	var req dto.AssetDiscoveryResolveRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.resolveDiscoveryReport invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.resolveDiscoveryReport validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	report, err := h.assetService.ResolveDiscoveryReport(c.Context(), reportID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.resolveDiscoveryReport service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to resolve discovery report")
+	}
+
+	return c.JSON(fiber.Map{"data": convertToDiscoveryReportResponse(report, false)})
+}
+
+func convertToDiscoveryAgentResponse(agent *repo.AssetDiscoveryAgent, token string) dto.AssetDiscoveryAgentResponse {
+	return dto.AssetDiscoveryAgentResponse{
+		ID:                    agent.ID,
+		Name:                  agent.Name,
+		TokenPrefix:           agent.TokenPrefix,
+		Token:                 token,
+		ReporterID:            agent.ReporterID,
+		RemoveMissingSoftware: agent.RemoveMissingSoftware,
+		IsActive:              agent.IsActive,
+		LastReportAt:          agent.LastReportAt,
+		CreatedAt:             agent.CreatedAt,
+		UpdatedAt:             agent.UpdatedAt,
+	}
+}
+
+func convertToDiscoveryReportResponse(report *repo.AssetDiscoveryReport, withInventory bool) dto.AssetDiscoveryReportResponse {
+	response := dto.AssetDiscoveryReportResponse{
+		ID:              report.ID,
+		AgentID:         report.AgentID,
+		Format:          report.Format,
+		Hostname:        report.Hostname,
+		SerialNumber:    report.SerialNumber,
+		MACAddresses:    report.MACAddresses,
+		IPAddresses:     report.IPAddresses,
+		Status:          report.Status,
+		AssetID:         report.AssetID,
+		MatchedBy:       report.MatchedBy,
+		CandidateIDs:    report.CandidateIDs,
+		ChangedFields:   report.ChangedFields,
+		SoftwareAdded:   report.SoftwareAdded,
+		SoftwareUpdated: report.SoftwareUpdated,
+		SoftwareRemoved: report.SoftwareRemoved,
+		ReceivedAt:      report.ReceivedAt,
+		ResolvedBy:      report.ResolvedBy,
+		ResolvedAt:      report.ResolvedAt,
+	}
+	if withInventory {
+		inventory := report.Inventory
+		response.Inventory = &inventory
+	}
+	return response
+}
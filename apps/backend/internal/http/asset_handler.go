@@ -2,6 +2,7 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -33,6 +34,33 @@ func (h *AssetHandler) Register(r fiber.Router) {
 	assets.Post("/", RequirePermission("assets.create"), h.createAsset)
 	assets.Get("/export", RequirePermission("assets.export"), h.exportAssets)
 	assets.Post("/inventory", RequirePermission("assets.inventory"), h.performInventory)
+	assets.Post("/import/preview", RequirePermission("assets.create"), h.previewAssetImport)
+	assets.Post("/import", RequirePermission("assets.create"), h.importAssets)
+	// Discovery agents and reconciliation queue (должны быть ПЕРЕД /:id)
+	assets.Get("/discovery/agents", RequirePermission("assets.view"), h.listDiscoveryAgents)
+	assets.Post("/discovery/agents", RequirePermission("assets.edit"), h.createDiscoveryAgent)
+	assets.Put("/discovery/agents/:agent_id", RequirePermission("assets.edit"), h.updateDiscoveryAgent)
+	assets.Delete("/discovery/agents/:agent_id", RequirePermission("assets.edit"), h.deleteDiscoveryAgent)
+	assets.Post("/discovery/agents/:agent_id/rotate-token", RequirePermission("assets.edit"), h.rotateDiscoveryAgentToken)
+	assets.Get("/discovery/reports", RequirePermission("assets.view"), h.listDiscoveryReports)
+	assets.Get("/discovery/reports/:report_id", RequirePermission("assets.view"), h.getDiscoveryReport)
+	assets.Post("/discovery/reports/:report_id/resolve", RequirePermission("assets.edit"), h.resolveDiscoveryReport)
+	assets.Get("/vulnerabilities", RequirePermission("assets.view"), h.listVulnerabilities)
+	assets.Post("/vulnerabilities/feeds", RequirePermission("assets.edit"), h.importVulnerabilityFeed)
+	assets.Post("/vulnerabilities/match", RequirePermission("assets.edit"), h.matchVulnerabilities)
+	assets.Get("/lifecycle/operations", RequirePermission("assets.view"), h.listLifecycleOperations)
+	assets.Get("/inventory/campaigns", RequirePermission("assets.inventory"), h.listInventoryCampaigns)
+	assets.Post("/inventory/campaigns", RequirePermission("assets.inventory"), h.createInventoryCampaign)
+	assets.Get("/inventory/campaigns/:campaign_id", RequirePermission("assets.inventory"), h.getInventoryCampaign)
+	assets.Put("/inventory/campaigns/:campaign_id", RequirePermission("assets.inventory"), h.updateInventoryCampaign)
+	assets.Post("/inventory/campaigns/:campaign_id/start", RequirePermission("assets.inventory"), h.startInventoryCampaign)
+	assets.Post("/inventory/campaigns/:campaign_id/cancel", RequirePermission("assets.inventory"), h.cancelInventoryCampaign)
+	assets.Post("/inventory/campaigns/:campaign_id/scans", RequirePermission("assets.inventory"), h.scanInventoryLabel)
+	assets.Get("/inventory/campaigns/:campaign_id/items", RequirePermission("assets.inventory"), h.listInventoryItems)
+	assets.Post("/inventory/campaigns/:campaign_id/items/:item_id/resolve", RequirePermission("assets.inventory"), h.resolveInventoryDiscrepancy)
+	assets.Post("/inventory/campaigns/:campaign_id/complete", RequirePermission("assets.inventory"), h.completeInventoryCampaign)
+	assets.Post("/inventory/campaigns/:campaign_id/sign", RequirePermission("assets.inventory"), h.signInventoryCampaign)
+	assets.Get("/inventory/campaigns/:campaign_id/report", RequirePermission("assets.inventory"), h.getInventoryReport)
 	assets.Get("/:id", RequirePermission("assets.view"), h.getAsset)
 	assets.Put("/:id", RequirePermission("assets.edit"), h.updateAsset)
 	assets.Delete("/:id", RequirePermission("assets.delete"), h.deleteAsset)
@@ -56,6 +84,19 @@ func (h *AssetHandler) Register(r fiber.Router) {
 	assets.Get("/:id/incidents", RequirePermission("assets.view"), h.getAssetIncidents)
 	assets.Get("/:id/can-add-risk", RequirePermission("assets.view"), h.canAddRisk)
 	assets.Get("/:id/can-add-incident", RequirePermission("assets.view"), h.canAddIncident)
+	assets.Get("/:id/relationships", RequirePermission("assets.view"), h.listAssetRelationships)
+	assets.Post("/:id/relationships", RequirePermission("assets.edit"), h.createAssetRelationship)
+	assets.Delete("/:id/relationships/:relationship_id", RequirePermission("assets.edit"), h.deleteAssetRelationship)
+	assets.Get("/:id/graph", RequirePermission("assets.view"), h.getAssetGraph)
+	assets.Get("/:id/impact", RequirePermission("assets.view"), h.getAssetImpact)
+	assets.Get("/:id/vulnerabilities", RequirePermission("assets.view"), h.getAssetVulnerabilities)
+	assets.Get("/:id/lifecycle", RequirePermission("assets.view"), h.listAssetLifecycleOperations)
+	assets.Post("/:id/lifecycle", RequirePermission("assets.edit"), h.requestLifecycleOperation)
+	assets.Get("/:id/lifecycle/:operation_id", RequirePermission("assets.view"), h.getLifecycleOperation)
+	assets.Post("/:id/lifecycle/:operation_id/approve", RequirePermission("assets.lifecycle:approve"), h.approveLifecycleOperation)
+	assets.Post("/:id/lifecycle/:operation_id/reject", RequirePermission("assets.lifecycle:approve"), h.rejectLifecycleOperation)
+	assets.Post("/:id/lifecycle/:operation_id/cancel", RequirePermission("assets.edit"), h.cancelLifecycleOperation)
+	assets.Post("/:id/lifecycle/:operation_id/act", RequirePermission("assets.lifecycle:approve"), h.generateLifecycleAct)
 	assets.Get("/inventory/without-owner", RequirePermission("assets.inventory"), h.getAssetsWithoutOwner)
 	assets.Get("/inventory/without-passport", RequirePermission("assets.inventory"), h.getAssetsWithoutPassport)
 	assets.Get("/inventory/without-criticality", RequirePermission("assets.inventory"), h.getAssetsWithoutCriticality)
@@ -874,3 +915,36 @@ func (h *AssetHandler) unlinkAssetDocument(c *fiber.Ctx) error {
 	log.Printf("DEBUG: AssetHandler.unlinkAssetDocument success id=%s", id)
 	return c.Status(200).JSON(fiber.Map{"message": "Document unlinked successfully"})
 }
+
+// assetErrorResponse maps asset module errors to HTTP statuses
+func assetErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	var validationErr domain.ValidationError
+	switch {
+	case errors.Is(err, domain.ErrAssetNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Asset not found"})
+	case errors.Is(err, domain.ErrDiscoveryAgentNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Discovery agent not found"})
+	case errors.Is(err, domain.ErrDiscoveryReportNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Discovery report not found"})
+	case errors.Is(err, domain.ErrAssetRelationshipNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Asset relationship not found"})
+	case errors.Is(err, domain.ErrAssetLifecycleOperationNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Lifecycle operation not found"})
+	case errors.Is(err, domain.ErrInventoryCampaignNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Inventory campaign not found"})
+	case errors.Is(err, domain.ErrInventoryItemNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Inventory item not found"})
+	case errors.Is(err, domain.ErrAssetLifecycleSelfApproval), errors.Is(err, domain.ErrAssetLifecycleNotRequester),
+		errors.Is(err, domain.ErrInventoryNotAuditor):
+		return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrDiscoveryReportResolved), errors.Is(err, domain.ErrAssetRelationshipExists),
+		errors.Is(err, domain.ErrAssetRelationshipCycle), errors.Is(err, domain.ErrAssetLifecycleOperationNotPending),
+		errors.Is(err, domain.ErrAssetLifecyclePendingExists), errors.Is(err, domain.ErrInventoryCampaignStatus),
+		errors.Is(err, domain.ErrInventoryUnresolvedDiscrepancies):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &validationErr):
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	default:
+		return c.Status(500).JSON(fiber.Map{"error": fallback})
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"errors"
+	"log"
+
+	"risknexus/backend/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Asset Import endpoints; .csv/.xlsx files are read as registers, .xml files as nmap scan reports
+func (h *AssetHandler) previewAssetImport(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	filename, data, mapping, err := readImportForm(c)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.previewAssetImport invalid form: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	log.Printf("DEBUG: AssetHandler.previewAssetImport file=%s size=%d user=%s", filename, len(data), userID)
+
+	preview, err := h.assetService.PreviewAssetImport(c.Context(), tenantID, filename, data, mapping)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.previewAssetImport service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to preview asset import")
+	}
+
+	return c.JSON(fiber.Map{"data": preview})
+}
+
+func (h *AssetHandler) importAssets(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	filename, data, mapping, err := readImportForm(c)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.importAssets invalid form: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	skipInvalid := c.FormValue("skip_invalid") == "true"
+
+	log.Printf("DEBUG: AssetHandler.importAssets file=%s size=%d skip_invalid=%t user=%s", filename, len(data), skipInvalid, userID)
+
+	result, preview, err := h.assetService.ImportAssets(c.Context(), tenantID, filename, data, mapping, skipInvalid, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAssetImportInvalidRows) {
+			return c.Status(422).JSON(fiber.Map{"error": err.Error(), "data": preview})
+		}
+		log.Printf("ERROR: AssetHandler.importAssets service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to import assets")
+	}
+
+	log.Printf("DEBUG: AssetHandler.importAssets imported=%d skipped=%d", result.Imported, result.Skipped)
+	return c.Status(201).JSON(fiber.Map{"data": result})
+}
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"fmt"
+	"log"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Inventory campaign endpoints
+func (h *AssetHandler) listInventoryCampaigns(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	filters := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if c.QueryBool("mine") {
+		filters["auditor_id"] = userID
+	}
+
+	campaigns, err := h.assetService.ListInventoryCampaigns(c.Context(), tenantID, filters)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listInventoryCampaigns service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get inventory campaigns")
+	}
+
+	return c.JSON(fiber.Map{"data": campaigns})
+}
+
+func (h *AssetHandler) getInventoryCampaign(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	campaign, err := h.assetService.GetInventoryCampaign(c.Context(), c.Params("campaign_id"), tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.getInventoryCampaign service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get inventory campaign")
+	}
+
+	return c.JSON(fiber.Map{"data": campaign})
+}
+
+func (h *AssetHandler) createInventoryCampaign(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.InventoryCampaignRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.createInventoryCampaign invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.createInventoryCampaign validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	campaign, err := h.assetService.CreateInventoryCampaign(c.Context(), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.createInventoryCampaign service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to create inventory campaign")
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": campaign})
+}
+
+func (h *AssetHandler) updateInventoryCampaign(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var req dto.InventoryCampaignRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.updateInventoryCampaign invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.updateInventoryCampaign validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	campaign, err := h.assetService.UpdateInventoryCampaign(c.Context(), c.Params("campaign_id"), tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.updateInventoryCampaign service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to update inventory campaign")
+	}
+
+	return c.JSON(fiber.Map{"data": campaign})
+}
+
+func (h *AssetHandler) startInventoryCampaign(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	campaign, err := h.assetService.StartInventoryCampaign(c.Context(), c.Params("campaign_id"), tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.startInventoryCampaign service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to start inventory campaign")
+	}
+
+	return c.JSON(fiber.Map{"data": campaign})
+}
+
+func (h *AssetHandler) cancelInventoryCampaign(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	campaign, err := h.assetService.CancelInventoryCampaign(c.Context(), c.Params("campaign_id"), tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.cancelInventoryCampaign service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to cancel inventory campaign")
+	}
+
+	return c.JSON(fiber.Map{"data": campaign})
+}
+
+func (h *AssetHandler) scanInventoryLabel(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.InventoryScanRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.scanInventoryLabel invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.scanInventoryLabel validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	scan, err := h.assetService.ScanInventoryLabel(c.Context(), c.Params("campaign_id"), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.scanInventoryLabel service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to record inventory scan")
+	}
+
+	return c.JSON(fiber.Map{"data": scan})
+}
+
+func (h *AssetHandler) listInventoryItems(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filters := make(map[string]interface{})
+	if result := c.Query("result"); result != "" {
+		filters["result"] = result
+	}
+	if c.QueryBool("unresolved") {
+		filters["unresolved"] = true
+	}
+
+	items, err := h.assetService.ListInventoryItems(c.Context(), c.Params("campaign_id"), tenantID, filters)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listInventoryItems service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get inventory items")
+	}
+
+	return c.JSON(fiber.Map{"data": items})
+}
+
+func (h *AssetHandler) resolveInventoryDiscrepancy(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.InventoryResolveRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.resolveInventoryDiscrepancy invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.resolveInventoryDiscrepancy validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	item, err := h.assetService.ResolveInventoryDiscrepancy(c.Context(), c.Params("campaign_id"), c.Params("item_id"), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.resolveInventoryDiscrepancy service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to resolve inventory discrepancy")
+	}
+
+	return c.JSON(fiber.Map{"data": item})
+}
+
+func (h *AssetHandler) completeInventoryCampaign(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	campaign, err := h.assetService.CompleteInventoryCampaign(c.Context(), c.Params("campaign_id"), tenantID, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.completeInventoryCampaign service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to complete inventory campaign")
+	}
+
+	return c.JSON(fiber.Map{"data": campaign})
+}
+
+func (h *AssetHandler) signInventoryCampaign(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.InventorySignRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			log.Printf("ERROR: AssetHandler.signInventoryCampaign invalid body: %v", err)
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.signInventoryCampaign validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	campaign, err := h.assetService.SignInventoryCampaign(c.Context(), c.Params("campaign_id"), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.signInventoryCampaign service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to sign inventory campaign")
+	}
+
+	return c.JSON(fiber.Map{"data": campaign})
+}
+
+func (h *AssetHandler) getInventoryReport(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	campaignID := c.Params("campaign_id")
+	format := c.Query("format", "pdf")
+	if format != "pdf" && format != "html" {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid format, expected pdf or html"})
+	}
+
+	report, err := h.assetService.GenerateInventoryReport(c.Context(), campaignID, tenantID, format)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.getInventoryReport service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to generate inventory report")
+	}
+
+	if format == "html" {
+		c.Set("Content-Type", "text/html; charset=utf-8")
+	} else {
+		c.Set("Content-Type", "application/pdf")
+	}
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"inventory-%s.%s\"", campaignID, format))
+	return c.Send(report)
+}
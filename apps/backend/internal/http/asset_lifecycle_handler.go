@@ -0,0 +1,169 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Lifecycle operation endpoints
+func (h *AssetHandler) listLifecycleOperations(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filters := make(map[string]interface{})
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if operationType := c.Query("operation_type"); operationType != "" {
+		filters["operation_type"] = operationType
+	}
+	if assetID := c.Query("asset_id"); assetID != "" {
+		filters["asset_id"] = assetID
+	}
+
+	operations, err := h.assetService.ListLifecycleOperations(c.Context(), tenantID, filters)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listLifecycleOperations service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get lifecycle operations")
+	}
+
+	return c.JSON(fiber.Map{"data": operations})
+}
+
+func (h *AssetHandler) listAssetLifecycleOperations(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	assetID := c.Params("id")
+
+	operations, err := h.assetService.ListAssetLifecycleOperations(c.Context(), assetID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listAssetLifecycleOperations service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get lifecycle operations")
+	}
+
+	return c.JSON(fiber.Map{"data": operations})
+}
+
+func (h *AssetHandler) getLifecycleOperation(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	operation, err := h.assetService.GetLifecycleOperation(c.Context(), c.Params("id"), c.Params("operation_id"), tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.getLifecycleOperation service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get lifecycle operation")
+	}
+
+	return c.JSON(fiber.Map{"data": operation})
+}
+
+func (h *AssetHandler) requestLifecycleOperation(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	assetID := c.Params("id")
+
+	var req dto.AssetLifecycleOperationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.requestLifecycleOperation invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.requestLifecycleOperation validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	operation, err := h.assetService.RequestLifecycleOperation(c.Context(), assetID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.requestLifecycleOperation service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to request lifecycle operation")
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": operation})
+}
+
+func (h *AssetHandler) approveLifecycleOperation(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	req, ok := h.parseLifecycleDecision(c)
+	if !ok {
+		return nil
+	}
+
+	operation, err := h.assetService.ApproveLifecycleOperation(c.Context(), c.Params("id"), c.Params("operation_id"), tenantID, *req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.approveLifecycleOperation service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to approve lifecycle operation")
+	}
+
+	return c.JSON(fiber.Map{"data": operation})
+}
+
+func (h *AssetHandler) rejectLifecycleOperation(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	req, ok := h.parseLifecycleDecision(c)
+	if !ok {
+		return nil
+	}
+
+	operation, err := h.assetService.RejectLifecycleOperation(c.Context(), c.Params("id"), c.Params("operation_id"), tenantID, *req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.rejectLifecycleOperation service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to reject lifecycle operation")
+	}
+
+	return c.JSON(fiber.Map{"data": operation})
+}
+
+func (h *AssetHandler) cancelLifecycleOperation(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	operation, err := h.assetService.CancelLifecycleOperation(c.Context(), c.Params("id"), c.Params("operation_id"), tenantID, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.cancelLifecycleOperation service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to cancel lifecycle operation")
+	}
+
+	return c.JSON(fiber.Map{"data": operation})
+}
+
+func (h *AssetHandler) generateLifecycleAct(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	req, ok := h.parseLifecycleDecision(c)
+	if !ok {
+		return nil
+	}
+
+	operation, err := h.assetService.GenerateLifecycleAct(c.Context(), c.Params("id"), c.Params("operation_id"), tenantID, req.GeneratePDF, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.generateLifecycleAct service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to generate lifecycle act")
+	}
+
+	return c.JSON(fiber.Map{"data": operation})
+}
+
+// parseLifecycleDecision reads the optional decision body; when it returns false the error response is already sent
+func (h *AssetHandler) parseLifecycleDecision(c *fiber.Ctx) (*dto.AssetLifecycleDecisionRequest, bool) {
+	var req dto.AssetLifecycleDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			log.Printf("ERROR: AssetHandler.parseLifecycleDecision invalid body: %v", err)
+			c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+			return nil, false
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.parseLifecycleDecision validation failed: %v", err)
+		c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+		return nil, false
+	}
+	return &req, true
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Asset relationship endpoints
+func (h *AssetHandler) listAssetRelationships(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	assetID := c.Params("id")
+
+	relationships, err := h.assetService.ListAssetRelationships(c.Context(), assetID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listAssetRelationships service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get asset relationships")
+	}
+
+	return c.JSON(fiber.Map{"data": relationships})
+}
+
+func (h *AssetHandler) createAssetRelationship(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	assetID := c.Params("id")
+
+	var req dto.AssetRelationshipRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: AssetHandler.createAssetRelationship invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.createAssetRelationship validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	relationship, err := h.assetService.CreateAssetRelationship(c.Context(), assetID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.createAssetRelationship service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to create asset relationship")
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": relationship})
+}
+
+func (h *AssetHandler) deleteAssetRelationship(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	assetID := c.Params("id")
+	relationshipID := c.Params("relationship_id")
+
+	if err := h.assetService.DeleteAssetRelationship(c.Context(), assetID, relationshipID, tenantID, userID); err != nil {
+		log.Printf("ERROR: AssetHandler.deleteAssetRelationship service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to delete asset relationship")
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Asset relationship deleted successfully"})
+}
+
+// Dependency map endpoints
+func (h *AssetHandler) getAssetGraph(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	assetID := c.Params("id")
+
+	graph, err := h.assetService.GetAssetGraph(c.Context(), assetID, tenantID, c.QueryInt("depth", 0))
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.getAssetGraph service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get asset graph")
+	}
+
+	return c.JSON(fiber.Map{"data": graph})
+}
+
+func (h *AssetHandler) getAssetImpact(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	assetID := c.Params("id")
+
+	impact, err := h.assetService.GetAssetImpact(c.Context(), assetID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.getAssetImpact service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get asset impact analysis")
+	}
+
+	return c.JSON(fiber.Map{"data": impact})
+}
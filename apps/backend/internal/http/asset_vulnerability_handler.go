@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"log"
+	"strconv"
+
+	"risknexus/backend/internal/domain"
+	"risknexus/backend/internal/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxVulnerabilityFeedFileSize limits uploaded CVE feeds and CPE dictionaries to 100 MB;
+// large feeds are expected as .json.gz or .xml.gz
+const maxVulnerabilityFeedFileSize = 100 << 20
+
+// Vulnerability feed endpoints
+func (h *AssetHandler) importVulnerabilityFeed(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "No file provided"})
+	}
+	if file.Size > maxVulnerabilityFeedFileSize {
+		return c.Status(400).JSON(fiber.Map{"error": "File is too large, maximum size is 100 MB"})
+	}
+	src, err := file.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to open file"})
+	}
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to read file"})
+	}
+
+	log.Printf("DEBUG: AssetHandler.importVulnerabilityFeed file=%s size=%d user=%s", file.Filename, len(data), userID)
+
+	result, err := h.assetService.ImportVulnerabilityFeed(c.Context(), tenantID, file.Filename, data, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.importVulnerabilityFeed service error: %v", err)
+		if errors.Is(err, domain.ErrInvalidVulnerabilityFeed) {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid vulnerability feed", "details": err.Error()})
+		}
+		return assetErrorResponse(c, err, "Failed to import vulnerability feed")
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": result})
+}
+
+func (h *AssetHandler) matchVulnerabilities(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.VulnerabilityMatchRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			log.Printf("ERROR: AssetHandler.matchVulnerabilities invalid body: %v", err)
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: AssetHandler.matchVulnerabilities validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	result, err := h.assetService.MatchAssetVulnerabilities(c.Context(), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.matchVulnerabilities service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to match vulnerabilities")
+	}
+
+	return c.JSON(fiber.Map{"data": result})
+}
+
+// Vulnerability list endpoints
+func (h *AssetHandler) listVulnerabilities(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filters := make(map[string]interface{})
+	if assetID := c.Query("asset_id"); assetID != "" {
+		filters["asset_id"] = assetID
+	}
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+	if severity := c.Query("severity"); severity != "" {
+		filters["severity"] = severity
+	}
+	if minCVSS := c.Query("min_cvss"); minCVSS != "" {
+		score, err := strconv.ParseFloat(minCVSS, 64)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid min_cvss"})
+		}
+		filters["min_cvss"] = score
+	}
+	if c.QueryBool("without_risk") {
+		filters["without_risk"] = true
+	}
+
+	vulnerabilities, err := h.assetService.ListVulnerabilities(c.Context(), tenantID, filters)
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.listVulnerabilities service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get vulnerabilities")
+	}
+
+	return c.JSON(fiber.Map{"data": vulnerabilities})
+}
+
+func (h *AssetHandler) getAssetVulnerabilities(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	assetID := c.Params("id")
+
+	vulnerabilities, err := h.assetService.GetAssetVulnerabilities(c.Context(), assetID, tenantID, c.Query("status"))
+	if err != nil {
+		log.Printf("ERROR: AssetHandler.getAssetVulnerabilities service error: %v", err)
+		return assetErrorResponse(c, err, "Failed to get asset vulnerabilities")
+	}
+
+	return c.JSON(fiber.Map{"data": vulnerabilities})
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultBodyLimit - лимит тела запроса по умолчанию (как у Fiber)
+const DefaultBodyLimit = fiber.DefaultBodyLimit
+
+// MaxVulnerabilityFeedUploadSize - лимит тела запроса загрузки фидов CVE: файл и служебные части multipart
+const MaxVulnerabilityFeedUploadSize = maxVulnerabilityFeedFileSize + 1<<20
+
+// BodyLimitMiddleware - проверяет Content-Length до чтения тела запроса.
+// Используется вместе с fiber.Config{StreamRequestBody: true}: тела больше лимита сервера
+// не буферизуются, а читаются обработчиком из потока, поэтому лимит применяется здесь.
+// routeLimits задаёт увеличенный лимит для отдельных путей (например, загрузки фидов).
+func BodyLimitMiddleware(defaultLimit int, routeLimits map[string]int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		contentLength := c.Request().Header.ContentLength()
+		if contentLength == -1 {
+			// chunked-тело нельзя ограничить заранее
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusLengthRequired).JSON(fiber.Map{"error": "Content-Length header required"})
+		}
+
+		limit := defaultLimit
+		if routeLimit, ok := routeLimits[c.Path()]; ok {
+			limit = routeLimit
+		}
+		if contentLength > limit {
+			// Непрочитанное тело остаётся в соединении, поэтому его закрываем
+			c.Context().SetConnectionClose()
+			log.Printf("WARNING: BodyLimitMiddleware rejected %s %s content_length=%d limit=%d", c.Method(), c.Path(), contentLength, limit)
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "Request body too large"})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"errors"
+	"log"
+
+	"risknexus/backend/internal/domain"
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterPublic registers the alert ingestion webhook; sources authenticate with their own token instead of a user session
+func (h *IncidentHandler) RegisterPublic(r fiber.Router) {
+	r.Post("/incidents/alerts/ingest", h.ingestAlerts)
+}
+
+func (h *IncidentHandler) ingestAlerts(c *fiber.Ctx) error {
+	response, err := h.incidentService.IngestAlerts(c.Context(), publicToken(c, "X-Alert-Token"), c.Body())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.ingestAlerts IngestAlerts: %v", err)
+		switch {
+		case errors.Is(err, domain.ErrAlertSourceUnauthorized):
+			return c.Status(401).JSON(fiber.Map{"error": "Invalid or inactive alert source token"})
+		case errors.Is(err, domain.ErrInvalidAlertPayload):
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid alert payload", "details": err.Error()})
+		default:
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to ingest alerts"})
+		}
+	}
+
+	return c.Status(202).JSON(response)
+}
+
+// Alert source endpoints
+func (h *IncidentHandler) listAlertSources(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	sources, err := h.incidentService.ListAlertSources(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listAlertSources ListAlertSources: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get alert sources",
+		})
+	}
+
+	responses := make([]dto.IncidentAlertSourceResponse, 0, len(sources))
+	for _, source := range sources {
+		responses = append(responses, convertToAlertSourceResponse(source, ""))
+	}
+	return c.JSON(responses)
+}
+
+func (h *IncidentHandler) createAlertSource(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.IncidentAlertSourceRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.createAlertSource BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.createAlertSource validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	source, token, err := h.incidentService.CreateAlertSource(c.Context(), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.createAlertSource CreateAlertSource: %v", err)
+		return incidentErrorResponse(c, err, "Failed to create alert source")
+	}
+
+	return c.Status(201).JSON(convertToAlertSourceResponse(source, token))
+}
+
+func (h *IncidentHandler) updateAlertSource(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	sourceID := c.Params("source_id")
+
+	var req dto.IncidentAlertSourceRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateAlertSource BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateAlertSource validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	source, err := h.incidentService.UpdateAlertSource(c.Context(), sourceID, tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateAlertSource UpdateAlertSource: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update alert source")
+	}
+
+	return c.JSON(convertToAlertSourceResponse(source, ""))
+}
+
+func (h *IncidentHandler) rotateAlertSourceToken(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	sourceID := c.Params("source_id")
+
+	source, token, err := h.incidentService.RotateAlertSourceToken(c.Context(), sourceID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.rotateAlertSourceToken RotateAlertSourceToken: %v", err)
+		return incidentErrorResponse(c, err, "Failed to rotate alert source token")
+	}
+
+	return c.JSON(convertToAlertSourceResponse(source, token))
+}
+
+func (h *IncidentHandler) deleteAlertSource(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	sourceID := c.Params("source_id")
+
+	if err := h.incidentService.DeleteAlertSource(c.Context(), sourceID, tenantID); err != nil {
+		log.Printf("ERROR: incident_handler.deleteAlertSource DeleteAlertSource: %v", err)
+		return incidentErrorResponse(c, err, "Failed to delete alert source")
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+func (h *IncidentHandler) getIncidentAlerts(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	alerts, err := h.incidentService.GetIncidentAlerts(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentAlerts GetIncidentAlerts: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident alerts")
+	}
+
+	responses := make([]dto.IncidentAlertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		responses = append(responses, dto.IncidentAlertResponse{
+			ID:          alert.ID,
+			SourceID:    alert.SourceID,
+			SourceName:  alert.SourceName,
+			Title:       alert.Title,
+			Severity:    alert.Severity,
+			Criticality: alert.Criticality,
+			Fields:      alert.Fields,
+			RawPayload:  alert.RawPayload,
+			IsDuplicate: alert.IsDuplicate,
+			ReceivedAt:  alert.ReceivedAt,
+		})
+	}
+
+	return c.JSON(responses)
+}
+
+func convertToAlertSourceResponse(source *repo.IncidentAlertSource, token string) dto.IncidentAlertSourceResponse {
+	return dto.IncidentAlertSourceResponse{
+		ID:                 source.ID,
+		Name:               source.Name,
+		Format:             source.Format,
+		IncidentSource:     source.IncidentSource,
+		TokenPrefix:        source.TokenPrefix,
+		Token:              token,
+		FieldMapping:       source.FieldMapping,
+		SeverityMapping:    source.SeverityMapping,
+		CorrelationFields:  source.CorrelationFields,
+		DedupWindowMinutes: source.DedupWindowMinutes,
+		DefaultCategory:    source.DefaultCategory,
+		DefaultCriticality: source.DefaultCriticality,
+		AssignedTo:         source.AssignedTo,
+		ReporterID:         source.ReporterID,
+		IsActive:           source.IsActive,
+		LastReceivedAt:     source.LastReceivedAt,
+		CreatedAt:          source.CreatedAt,
+		UpdatedAt:          source.UpdatedAt,
+	}
+}
@@ -0,0 +1,256 @@
+package http
+
+import (
+	"fmt"
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Evidence chain of custody endpoints
+func (h *IncidentHandler) listIncidentEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	items, err := h.incidentService.ListIncidentEvidence(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listIncidentEvidence ListIncidentEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident evidence")
+	}
+
+	responses := make([]dto.IncidentEvidenceResponse, 0, len(items))
+	for _, evidence := range items {
+		responses = append(responses, convertToEvidenceResponse(evidence, nil))
+	}
+	return c.JSON(responses)
+}
+
+func (h *IncidentHandler) collectEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Printf("ERROR: incident_handler.collectEvidence FormFile: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "No file provided"})
+	}
+
+	req := dto.IncidentEvidenceRequest{
+		Title:        c.FormValue("title"),
+		Description:  getStringPtr(c.FormValue("description")),
+		EvidenceType: c.FormValue("evidence_type", "file"),
+		Source:       getStringPtr(c.FormValue("source")),
+		CollectedAt:  getStringPtr(c.FormValue("collected_at")),
+	}
+	if req.Title == "" {
+		req.Title = fileHeader.Filename
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.collectEvidence validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Failed to open file"})
+	}
+	defer src.Close()
+
+	evidence, err := h.incidentService.CollectEvidence(c.Context(), incidentID, tenantID, src, fileHeader, req, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.collectEvidence CollectEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to collect evidence")
+	}
+
+	return c.Status(201).JSON(convertToEvidenceResponse(evidence, nil))
+}
+
+func (h *IncidentHandler) getEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	evidenceID := c.Params("evidence_id")
+
+	evidence, custody, err := h.incidentService.GetEvidence(c.Context(), incidentID, evidenceID, tenantID, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getEvidence GetEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get evidence")
+	}
+
+	return c.JSON(convertToEvidenceResponse(evidence, custody))
+}
+
+func (h *IncidentHandler) updateEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	evidenceID := c.Params("evidence_id")
+
+	var req dto.IncidentEvidenceUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateEvidence BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateEvidence validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	evidence, err := h.incidentService.UpdateEvidence(c.Context(), incidentID, evidenceID, tenantID, req, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateEvidence UpdateEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update evidence")
+	}
+
+	return c.JSON(convertToEvidenceResponse(evidence, nil))
+}
+
+func (h *IncidentHandler) sealEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	evidenceID := c.Params("evidence_id")
+
+	evidence, err := h.incidentService.SealEvidence(c.Context(), incidentID, evidenceID, tenantID, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.sealEvidence SealEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to seal evidence")
+	}
+
+	return c.JSON(convertToEvidenceResponse(evidence, nil))
+}
+
+func (h *IncidentHandler) transferEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	evidenceID := c.Params("evidence_id")
+
+	var req dto.IncidentEvidenceTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.transferEvidence BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.transferEvidence validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	evidence, err := h.incidentService.TransferEvidence(c.Context(), incidentID, evidenceID, tenantID, req, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.transferEvidence TransferEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to transfer evidence")
+	}
+
+	return c.JSON(convertToEvidenceResponse(evidence, nil))
+}
+
+func (h *IncidentHandler) downloadEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	evidenceID := c.Params("evidence_id")
+
+	evidence, download, err := h.incidentService.ExportEvidence(c.Context(), incidentID, evidenceID, tenantID, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.downloadEvidence ExportEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to export evidence")
+	}
+
+	c.Set("Content-Type", download.MimeType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", download.FileName))
+	c.Set("X-Evidence-SHA256", evidence.SHA256)
+	return c.Send(download.Content)
+}
+
+func (h *IncidentHandler) verifyEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	evidenceID := c.Params("evidence_id")
+
+	result, err := h.incidentService.VerifyEvidence(c.Context(), incidentID, evidenceID, tenantID, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.verifyEvidence VerifyEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to verify evidence")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *IncidentHandler) verifyIncidentEvidence(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	results, err := h.incidentService.VerifyIncidentEvidence(c.Context(), incidentID, tenantID, userID, c.IP())
+	if err != nil {
+		log.Printf("ERROR: incident_handler.verifyIncidentEvidence VerifyIncidentEvidence: %v", err)
+		return incidentErrorResponse(c, err, "Failed to verify evidence")
+	}
+
+	return c.JSON(results)
+}
+
+func convertToEvidenceResponse(evidence *repo.IncidentEvidence, custody []*repo.IncidentEvidenceCustody) dto.IncidentEvidenceResponse {
+	response := dto.IncidentEvidenceResponse{
+		ID:                     evidence.ID,
+		IncidentID:             evidence.IncidentID,
+		DocumentID:             evidence.DocumentID,
+		Title:                  evidence.Title,
+		Description:            evidence.Description,
+		EvidenceType:           evidence.EvidenceType,
+		Source:                 evidence.Source,
+		FileName:               evidence.FileName,
+		FileSize:               evidence.FileSize,
+		SHA256:                 evidence.SHA256,
+		CollectedBy:            evidence.CollectedBy,
+		CollectedAt:            evidence.CollectedAt,
+		CustodianID:            evidence.CustodianID,
+		IsSealed:               evidence.IsSealed,
+		SealedBy:               evidence.SealedBy,
+		SealedAt:               evidence.SealedAt,
+		LastVerifiedAt:         evidence.LastVerifiedAt,
+		LastVerificationStatus: evidence.LastVerificationStatus,
+		CreatedAt:              evidence.CreatedAt,
+		UpdatedAt:              evidence.UpdatedAt,
+	}
+
+	for _, entry := range custody {
+		response.Custody = append(response.Custody, dto.IncidentEvidenceCustodyResponse{
+			ID:              entry.ID,
+			Action:          entry.Action,
+			PerformedBy:     entry.PerformedBy,
+			FromCustodianID: entry.FromCustodianID,
+			ToCustodianID:   entry.ToCustodianID,
+			SHA256:          entry.SHA256,
+			Details:         entry.Details,
+			IPAddress:       entry.IPAddress,
+			PerformedAt:     entry.PerformedAt,
+		})
+	}
+	return response
+}
@@ -27,16 +27,78 @@ func (h *IncidentHandler) Register(r fiber.Router) {
 	incidents.Get("/", RequirePermission("incidents.view"), h.listIncidents)
 	incidents.Post("/", RequirePermission("incidents.create"), h.createIncident)
 	incidents.Get("/metrics", RequirePermission("incidents.report"), h.getIncidentMetrics)
+	incidents.Get("/metrics/trends", RequirePermission("incidents.report"), h.getIncidentTrends)
+	incidents.Get("/workflow", RequirePermission("incidents.view"), h.getStatusWorkflow)
+	incidents.Put("/workflow", RequirePermission("incidents.edit"), h.setStatusWorkflow)
+	incidents.Get("/sla/policies", RequirePermission("incidents.view"), h.getSLAPolicies)
+	incidents.Put("/sla/policies", RequirePermission("incidents.edit"), h.setSLAPolicies)
+	incidents.Get("/sla/calendars", RequirePermission("incidents.view"), h.listBusinessCalendars)
+	incidents.Post("/sla/calendars", RequirePermission("incidents.edit"), h.createBusinessCalendar)
+	incidents.Put("/sla/calendars/:calendar_id", RequirePermission("incidents.edit"), h.updateBusinessCalendar)
+	incidents.Delete("/sla/calendars/:calendar_id", RequirePermission("incidents.edit"), h.deleteBusinessCalendar)
+	incidents.Get("/sla/events", RequirePermission("incidents.view"), h.listSLAEvents)
+	incidents.Get("/alert-sources", RequirePermission("incidents.view"), h.listAlertSources)
+	incidents.Post("/alert-sources", RequirePermission("incidents.edit"), h.createAlertSource)
+	incidents.Put("/alert-sources/:source_id", RequirePermission("incidents.edit"), h.updateAlertSource)
+	incidents.Delete("/alert-sources/:source_id", RequirePermission("incidents.edit"), h.deleteAlertSource)
+	incidents.Post("/alert-sources/:source_id/rotate-token", RequirePermission("incidents.edit"), h.rotateAlertSourceToken)
+	incidents.Get("/mailboxes", RequirePermission("incidents.view"), h.listMailboxes)
+	incidents.Post("/mailboxes", RequirePermission("incidents.edit"), h.createMailbox)
+	incidents.Put("/mailboxes/:mailbox_id", RequirePermission("incidents.edit"), h.updateMailbox)
+	incidents.Delete("/mailboxes/:mailbox_id", RequirePermission("incidents.edit"), h.deleteMailbox)
+	incidents.Post("/mailboxes/:mailbox_id/poll", RequirePermission("incidents.edit"), h.pollMailbox)
+	incidents.Get("/mailboxes/:mailbox_id/messages", RequirePermission("incidents.view"), h.getMailboxMessages)
+	incidents.Get("/playbooks", RequirePermission("incidents.view"), h.listPlaybooks)
+	incidents.Get("/playbooks/:category", RequirePermission("incidents.view"), h.getPlaybook)
+	incidents.Put("/playbooks/:category", RequirePermission("incidents.edit"), h.setPlaybook)
+	incidents.Delete("/playbooks/:category", RequirePermission("incidents.edit"), h.resetPlaybook)
+	incidents.Get("/regulatory-profiles", RequirePermission("incidents.view"), h.listRegulatoryProfiles)
+	incidents.Post("/regulatory-profiles", RequirePermission("incidents.edit"), h.createRegulatoryProfile)
+	incidents.Get("/regulatory-profiles/presets", RequirePermission("incidents.view"), h.getRegulatoryPresets)
+	incidents.Post("/regulatory-profiles/presets/:framework", RequirePermission("incidents.edit"), h.createRegulatoryProfileFromPreset)
+	incidents.Put("/regulatory-profiles/:profile_id", RequirePermission("incidents.edit"), h.updateRegulatoryProfile)
+	incidents.Delete("/regulatory-profiles/:profile_id", RequirePermission("incidents.edit"), h.deleteRegulatoryProfile)
+	incidents.Get("/notifications/overdue", RequirePermission("incidents.view"), h.listOverdueNotifications)
 	incidents.Get("/:id", RequirePermission("incidents.view"), h.getIncident)
 	incidents.Put("/:id", RequirePermission("incidents.edit"), h.updateIncident)
 	incidents.Delete("/:id", RequirePermission("incidents.delete"), h.deleteIncident)
 	incidents.Put("/:id/status", RequirePermission("incidents.edit"), h.updateIncidentStatus)
+	incidents.Get("/:id/timeline", RequirePermission("incidents.view"), h.getStatusTimeline)
+	incidents.Get("/:id/sla", RequirePermission("incidents.view"), h.getIncidentSLA)
+	incidents.Get("/:id/alerts", RequirePermission("incidents.view"), h.getIncidentAlerts)
 	incidents.Post("/:id/comments", RequirePermission("incidents.edit"), h.addComment)
 	incidents.Get("/:id/comments", RequirePermission("incidents.view"), h.getComments)
 	incidents.Post("/:id/actions", RequirePermission("incidents.edit"), h.addAction)
 	incidents.Get("/:id/actions", RequirePermission("incidents.view"), h.getActions)
 	incidents.Put("/:id/actions/:actionId", RequirePermission("incidents.edit"), h.updateAction)
 	incidents.Delete("/:id/actions/:actionId", RequirePermission("incidents.delete"), h.deleteAction)
+	incidents.Put("/:id/actions/:actionId/status", RequirePermission("incidents.edit"), h.updateActionStatus)
+	incidents.Get("/:id/playbook", RequirePermission("incidents.view"), h.getIncidentPlaybook)
+	incidents.Post("/:id/playbook", RequirePermission("incidents.edit"), h.applyIncidentPlaybook)
+	incidents.Get("/:id/notifications", RequirePermission("incidents.view"), h.getIncidentNotifications)
+	incidents.Post("/:id/notifications/:notification_id/draft", RequirePermission("incidents.edit"), h.generateNotificationDraft)
+	incidents.Put("/:id/notifications/:notification_id", RequirePermission("incidents.edit"), h.updateIncidentNotification)
+	incidents.Get("/:id/review", RequirePermission("incidents.view"), h.getIncidentReview)
+	incidents.Put("/:id/review", RequirePermission("incidents.edit"), h.saveIncidentReview)
+	incidents.Get("/:id/review/report", RequirePermission("incidents.view"), h.getIncidentReviewReport)
+	incidents.Post("/:id/review/followups", RequirePermission("incidents.edit"), h.addReviewFollowUp)
+	incidents.Put("/:id/review/followups/:followup_id", RequirePermission("incidents.edit"), h.updateReviewFollowUp)
+	incidents.Delete("/:id/review/followups/:followup_id", RequirePermission("incidents.edit"), h.deleteReviewFollowUp)
+	incidents.Get("/:id/evidence", RequirePermission("incidents.view"), h.listIncidentEvidence)
+	incidents.Post("/:id/evidence", RequirePermission("incidents.edit"), h.collectEvidence)
+	incidents.Post("/:id/evidence/verify", RequirePermission("incidents.view"), h.verifyIncidentEvidence)
+	incidents.Get("/:id/evidence/:evidence_id", RequirePermission("incidents.view"), h.getEvidence)
+	incidents.Put("/:id/evidence/:evidence_id", RequirePermission("incidents.edit"), h.updateEvidence)
+	incidents.Get("/:id/evidence/:evidence_id/download", RequirePermission("incidents.view"), h.downloadEvidence)
+	incidents.Post("/:id/evidence/:evidence_id/seal", RequirePermission("incidents.edit"), h.sealEvidence)
+	incidents.Post("/:id/evidence/:evidence_id/transfer", RequirePermission("incidents.edit"), h.transferEvidence)
+	incidents.Post("/:id/evidence/:evidence_id/verify", RequirePermission("incidents.view"), h.verifyEvidence)
+	incidents.Get("/:id/relations", RequirePermission("incidents.view"), h.getIncidentRelations)
+	incidents.Put("/:id/parent", RequirePermission("incidents.edit"), h.setIncidentParent)
+	incidents.Post("/:id/links", RequirePermission("incidents.edit"), h.linkIncident)
+	incidents.Delete("/:id/links/:link_id", RequirePermission("incidents.edit"), h.unlinkIncident)
+	incidents.Post("/:id/merge", RequirePermission("incidents.edit"), h.mergeIncidents)
+	incidents.Post("/:id/merges/:merge_id/revert", RequirePermission("incidents.edit"), h.revertIncidentMerge)
 }
 
 func (h *IncidentHandler) listIncidents(c *fiber.Ctx) error {
@@ -80,21 +142,31 @@ func (h *IncidentHandler) listIncidents(c *fiber.Ctx) error {
 	var responses []dto.IncidentResponse
 	for _, incident := range incidents {
 		response := dto.IncidentResponse{
-			ID:          incident.ID,
-			TenantID:    incident.TenantID,
-			Title:       incident.Title,
-			Description: incident.Description,
-			Category:    incident.Category,
-			Status:      incident.Status,
-			Criticality: incident.Criticality,
-			Source:      incident.Source,
-			ReportedBy:  incident.ReportedBy,
-			AssignedTo:  incident.AssignedTo,
-			DetectedAt:  incident.DetectedAt,
-			ResolvedAt:  incident.ResolvedAt,
-			ClosedAt:    incident.ClosedAt,
-			CreatedAt:   incident.CreatedAt,
-			UpdatedAt:   incident.UpdatedAt,
+			ID:                 incident.ID,
+			TenantID:           incident.TenantID,
+			Title:              incident.Title,
+			Description:        incident.Description,
+			Category:           incident.Category,
+			Status:             incident.Status,
+			Criticality:        incident.Criticality,
+			Source:             incident.Source,
+			ReportedBy:         incident.ReportedBy,
+			AssignedTo:         incident.AssignedTo,
+			DetectedAt:         incident.DetectedAt,
+			ResolvedAt:         incident.ResolvedAt,
+			ClosedAt:           incident.ClosedAt,
+			RootCause:          incident.RootCause,
+			Resolution:         incident.Resolution,
+			ReopenCount:        incident.ReopenCount,
+			RespondedAt:        incident.RespondedAt,
+			ResponseDueAt:      incident.ResponseDueAt,
+			ResolutionDueAt:    incident.ResolutionDueAt,
+			ResponseBreached:   incident.ResponseBreached,
+			ResolutionBreached: incident.ResolutionBreached,
+			ParentID:           incident.ParentID,
+			MergedIntoID:       incident.MergedIntoID,
+			CreatedAt:          incident.CreatedAt,
+			UpdatedAt:          incident.UpdatedAt,
 		}
 		responses = append(responses, response)
 	}
@@ -140,21 +212,31 @@ func (h *IncidentHandler) createIncident(c *fiber.Ctx) error {
 	}
 
 	response := dto.IncidentResponse{
-		ID:          incident.ID,
-		TenantID:    incident.TenantID,
-		Title:       incident.Title,
-		Description: incident.Description,
-		Category:    incident.Category,
-		Status:      incident.Status,
-		Criticality: incident.Criticality,
-		Source:      incident.Source,
-		ReportedBy:  incident.ReportedBy,
-		AssignedTo:  incident.AssignedTo,
-		DetectedAt:  incident.DetectedAt,
-		ResolvedAt:  incident.ResolvedAt,
-		ClosedAt:    incident.ClosedAt,
-		CreatedAt:   incident.CreatedAt,
-		UpdatedAt:   incident.UpdatedAt,
+		ID:                 incident.ID,
+		TenantID:           incident.TenantID,
+		Title:              incident.Title,
+		Description:        incident.Description,
+		Category:           incident.Category,
+		Status:             incident.Status,
+		Criticality:        incident.Criticality,
+		Source:             incident.Source,
+		ReportedBy:         incident.ReportedBy,
+		AssignedTo:         incident.AssignedTo,
+		DetectedAt:         incident.DetectedAt,
+		ResolvedAt:         incident.ResolvedAt,
+		ClosedAt:           incident.ClosedAt,
+		RootCause:          incident.RootCause,
+		Resolution:         incident.Resolution,
+		ReopenCount:        incident.ReopenCount,
+		RespondedAt:        incident.RespondedAt,
+		ResponseDueAt:      incident.ResponseDueAt,
+		ResolutionDueAt:    incident.ResolutionDueAt,
+		ResponseBreached:   incident.ResponseBreached,
+		ResolutionBreached: incident.ResolutionBreached,
+		ParentID:           incident.ParentID,
+		MergedIntoID:       incident.MergedIntoID,
+		CreatedAt:          incident.CreatedAt,
+		UpdatedAt:          incident.UpdatedAt,
 	}
 
 	return c.Status(201).JSON(response)
@@ -173,21 +255,31 @@ func (h *IncidentHandler) getIncident(c *fiber.Ctx) error {
 	}
 
 	response := dto.IncidentResponse{
-		ID:          incident.ID,
-		TenantID:    incident.TenantID,
-		Title:       incident.Title,
-		Description: incident.Description,
-		Category:    incident.Category,
-		Status:      incident.Status,
-		Criticality: incident.Criticality,
-		Source:      incident.Source,
-		ReportedBy:  incident.ReportedBy,
-		AssignedTo:  incident.AssignedTo,
-		DetectedAt:  incident.DetectedAt,
-		ResolvedAt:  incident.ResolvedAt,
-		ClosedAt:    incident.ClosedAt,
-		CreatedAt:   incident.CreatedAt,
-		UpdatedAt:   incident.UpdatedAt,
+		ID:                 incident.ID,
+		TenantID:           incident.TenantID,
+		Title:              incident.Title,
+		Description:        incident.Description,
+		Category:           incident.Category,
+		Status:             incident.Status,
+		Criticality:        incident.Criticality,
+		Source:             incident.Source,
+		ReportedBy:         incident.ReportedBy,
+		AssignedTo:         incident.AssignedTo,
+		DetectedAt:         incident.DetectedAt,
+		ResolvedAt:         incident.ResolvedAt,
+		ClosedAt:           incident.ClosedAt,
+		RootCause:          incident.RootCause,
+		Resolution:         incident.Resolution,
+		ReopenCount:        incident.ReopenCount,
+		RespondedAt:        incident.RespondedAt,
+		ResponseDueAt:      incident.ResponseDueAt,
+		ResolutionDueAt:    incident.ResolutionDueAt,
+		ResponseBreached:   incident.ResponseBreached,
+		ResolutionBreached: incident.ResolutionBreached,
+		ParentID:           incident.ParentID,
+		MergedIntoID:       incident.MergedIntoID,
+		CreatedAt:          incident.CreatedAt,
+		UpdatedAt:          incident.UpdatedAt,
 	}
 
 	return c.JSON(response)
@@ -218,27 +310,35 @@ func (h *IncidentHandler) updateIncident(c *fiber.Ctx) error {
 	incident, err := h.incidentService.UpdateIncident(c.Context(), id, tenantID, req, userID)
 	if err != nil {
 		log.Printf("ERROR: incident_handler.updateIncident UpdateIncident: %v", err)
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to update incident",
-		})
+		return incidentErrorResponse(c, err, "Failed to update incident")
 	}
 
 	response := dto.IncidentResponse{
-		ID:          incident.ID,
-		TenantID:    incident.TenantID,
-		Title:       incident.Title,
-		Description: incident.Description,
-		Category:    incident.Category,
-		Status:      incident.Status,
-		Criticality: incident.Criticality,
-		Source:      incident.Source,
-		ReportedBy:  incident.ReportedBy,
-		AssignedTo:  incident.AssignedTo,
-		DetectedAt:  incident.DetectedAt,
-		ResolvedAt:  incident.ResolvedAt,
-		ClosedAt:    incident.ClosedAt,
-		CreatedAt:   incident.CreatedAt,
-		UpdatedAt:   incident.UpdatedAt,
+		ID:                 incident.ID,
+		TenantID:           incident.TenantID,
+		Title:              incident.Title,
+		Description:        incident.Description,
+		Category:           incident.Category,
+		Status:             incident.Status,
+		Criticality:        incident.Criticality,
+		Source:             incident.Source,
+		ReportedBy:         incident.ReportedBy,
+		AssignedTo:         incident.AssignedTo,
+		DetectedAt:         incident.DetectedAt,
+		ResolvedAt:         incident.ResolvedAt,
+		ClosedAt:           incident.ClosedAt,
+		RootCause:          incident.RootCause,
+		Resolution:         incident.Resolution,
+		ReopenCount:        incident.ReopenCount,
+		RespondedAt:        incident.RespondedAt,
+		ResponseDueAt:      incident.ResponseDueAt,
+		ResolutionDueAt:    incident.ResolutionDueAt,
+		ResponseBreached:   incident.ResponseBreached,
+		ResolutionBreached: incident.ResolutionBreached,
+		ParentID:           incident.ParentID,
+		MergedIntoID:       incident.MergedIntoID,
+		CreatedAt:          incident.CreatedAt,
+		UpdatedAt:          incident.UpdatedAt,
 	}
 
 	return c.JSON(response)
@@ -284,27 +384,35 @@ func (h *IncidentHandler) updateIncidentStatus(c *fiber.Ctx) error {
 	incident, err := h.incidentService.UpdateIncidentStatus(c.Context(), id, tenantID, req, userID)
 	if err != nil {
 		log.Printf("ERROR: incident_handler.updateIncidentStatus UpdateIncidentStatus: %v", err)
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to update incident status",
-		})
+		return incidentErrorResponse(c, err, "Failed to update incident status")
 	}
 
 	response := dto.IncidentResponse{
-		ID:          incident.ID,
-		TenantID:    incident.TenantID,
-		Title:       incident.Title,
-		Description: incident.Description,
-		Category:    incident.Category,
-		Status:      incident.Status,
-		Criticality: incident.Criticality,
-		Source:      incident.Source,
-		ReportedBy:  incident.ReportedBy,
-		AssignedTo:  incident.AssignedTo,
-		DetectedAt:  incident.DetectedAt,
-		ResolvedAt:  incident.ResolvedAt,
-		ClosedAt:    incident.ClosedAt,
-		CreatedAt:   incident.CreatedAt,
-		UpdatedAt:   incident.UpdatedAt,
+		ID:                 incident.ID,
+		TenantID:           incident.TenantID,
+		Title:              incident.Title,
+		Description:        incident.Description,
+		Category:           incident.Category,
+		Status:             incident.Status,
+		Criticality:        incident.Criticality,
+		Source:             incident.Source,
+		ReportedBy:         incident.ReportedBy,
+		AssignedTo:         incident.AssignedTo,
+		DetectedAt:         incident.DetectedAt,
+		ResolvedAt:         incident.ResolvedAt,
+		ClosedAt:           incident.ClosedAt,
+		RootCause:          incident.RootCause,
+		Resolution:         incident.Resolution,
+		ReopenCount:        incident.ReopenCount,
+		RespondedAt:        incident.RespondedAt,
+		ResponseDueAt:      incident.ResponseDueAt,
+		ResolutionDueAt:    incident.ResolutionDueAt,
+		ResponseBreached:   incident.ResponseBreached,
+		ResolutionBreached: incident.ResolutionBreached,
+		ParentID:           incident.ParentID,
+		MergedIntoID:       incident.MergedIntoID,
+		CreatedAt:          incident.CreatedAt,
+		UpdatedAt:          incident.UpdatedAt,
 	}
 
 	return c.JSON(response)
@@ -443,18 +551,21 @@ func (h *IncidentHandler) getActions(c *fiber.Ctx) error {
 	var responses []dto.IncidentActionResponse
 	for _, action := range actions {
 		response := dto.IncidentActionResponse{
-			ID:          action.ID,
-			IncidentID:  action.IncidentID,
-			ActionType:  action.ActionType,
-			Title:       action.Title,
-			Description: action.Description,
-			AssignedTo:  action.AssignedTo,
-			DueDate:     action.DueDate,
-			CompletedAt: action.CompletedAt,
-			Status:      action.Status,
-			CreatedBy:   action.CreatedBy,
-			CreatedAt:   action.CreatedAt,
-			UpdatedAt:   action.UpdatedAt,
+			ID:           action.ID,
+			IncidentID:   action.IncidentID,
+			ActionType:   action.ActionType,
+			Title:        action.Title,
+			Description:  action.Description,
+			AssignedTo:   action.AssignedTo,
+			DueDate:      action.DueDate,
+			CompletedAt:  action.CompletedAt,
+			Status:       action.Status,
+			CreatedBy:    action.CreatedBy,
+			CreatedAt:    action.CreatedAt,
+			UpdatedAt:    action.UpdatedAt,
+			PlaybookID:   action.PlaybookID,
+			PlaybookName: action.PlaybookName,
+			StepOrder:    action.StepOrder,
 		}
 		responses = append(responses, response)
 	}
@@ -487,24 +598,25 @@ func (h *IncidentHandler) updateAction(c *fiber.Ctx) error {
 	action, err := h.incidentService.UpdateAction(c.Context(), actionID, tenantID, req, userID)
 	if err != nil {
 		log.Printf("ERROR: incident_handler.updateAction UpdateAction: %v", err)
-		return c.Status(500).JSON(fiber.Map{
-			"error": "Failed to update action",
-		})
+		return incidentErrorResponse(c, err, "Failed to update action")
 	}
 
 	response := dto.IncidentActionResponse{
-		ID:          action.ID,
-		IncidentID:  action.IncidentID,
-		ActionType:  action.ActionType,
-		Title:       action.Title,
-		Description: action.Description,
-		AssignedTo:  action.AssignedTo,
-		DueDate:     action.DueDate,
-		CompletedAt: action.CompletedAt,
-		Status:      action.Status,
-		CreatedBy:   action.CreatedBy,
-		CreatedAt:   action.CreatedAt,
-		UpdatedAt:   action.UpdatedAt,
+		ID:           action.ID,
+		IncidentID:   action.IncidentID,
+		ActionType:   action.ActionType,
+		Title:        action.Title,
+		Description:  action.Description,
+		AssignedTo:   action.AssignedTo,
+		DueDate:      action.DueDate,
+		CompletedAt:  action.CompletedAt,
+		Status:       action.Status,
+		CreatedBy:    action.CreatedBy,
+		CreatedAt:    action.CreatedAt,
+		UpdatedAt:    action.UpdatedAt,
+		PlaybookID:   action.PlaybookID,
+		PlaybookName: action.PlaybookName,
+		StepOrder:    action.StepOrder,
 	}
 
 	return c.JSON(response)
@@ -538,7 +650,45 @@ func (h *IncidentHandler) getIncidentMetrics(c *fiber.Ctx) error {
 		ByCriticality:   metrics.ByCriticality,
 		ByCategory:      metrics.ByCategory,
 		ByStatus:        metrics.ByStatus,
+
+		AverageResponseTime: metrics.AverageResponseTime,
+		ResponseBreaches:    metrics.ResponseBreaches,
+		ResolutionBreaches:  metrics.ResolutionBreaches,
+		OpenBreached:        metrics.OpenBreached,
+		SLACompliance:       metrics.SLACompliance,
 	}
 
 	return c.JSON(response)
 }
+
+func (h *IncidentHandler) getIncidentTrends(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	req := dto.IncidentTrendRequest{
+		Interval:    c.Query("interval"),
+		From:        c.Query("from"),
+		To:          c.Query("to"),
+		Category:    c.Query("category"),
+		Criticality: c.Query("criticality"),
+		AssetID:     c.Query("asset_id"),
+		AssignedTo:  c.Query("assigned_to"),
+		Compare:     c.Query("compare"),
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentTrends validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request parameters",
+			"details": err.Error(),
+		})
+	}
+
+	trends, err := h.incidentService.GetIncidentTrends(c.Context(), tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentTrends GetIncidentTrends: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident trends")
+	}
+
+	return c.JSON(trends)
+}
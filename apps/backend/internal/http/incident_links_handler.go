@@ -0,0 +1,144 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Incident links, hierarchy and merge endpoints
+func (h *IncidentHandler) getIncidentRelations(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	relations, err := h.incidentService.GetIncidentRelations(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentRelations GetIncidentRelations: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident relations")
+	}
+
+	return c.JSON(relations)
+}
+
+func (h *IncidentHandler) setIncidentParent(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	var req dto.IncidentParentRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.setIncidentParent BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.setIncidentParent validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	relations, err := h.incidentService.SetIncidentParent(c.Context(), incidentID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.setIncidentParent SetIncidentParent: %v", err)
+		return incidentErrorResponse(c, err, "Failed to set parent incident")
+	}
+
+	return c.JSON(relations)
+}
+
+func (h *IncidentHandler) linkIncident(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	var req dto.IncidentLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.linkIncident BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.linkIncident validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	link, err := h.incidentService.LinkIncidents(c.Context(), incidentID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.linkIncident LinkIncidents: %v", err)
+		return incidentErrorResponse(c, err, "Failed to link incidents")
+	}
+
+	return c.Status(201).JSON(link)
+}
+
+func (h *IncidentHandler) unlinkIncident(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+	linkID := c.Params("link_id")
+
+	if err := h.incidentService.UnlinkIncidents(c.Context(), incidentID, linkID, tenantID); err != nil {
+		log.Printf("ERROR: incident_handler.unlinkIncident UnlinkIncidents: %v", err)
+		return incidentErrorResponse(c, err, "Failed to unlink incidents")
+	}
+
+	return c.SendStatus(204)
+}
+
+func (h *IncidentHandler) mergeIncidents(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	var req dto.IncidentMergeRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.mergeIncidents BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.mergeIncidents validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	merges, err := h.incidentService.MergeIncidents(c.Context(), incidentID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.mergeIncidents MergeIncidents: %v", err)
+		return incidentErrorResponse(c, err, "Failed to merge incidents")
+	}
+
+	return c.JSON(merges)
+}
+
+func (h *IncidentHandler) revertIncidentMerge(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	mergeID := c.Params("merge_id")
+
+	merge, err := h.incidentService.RevertIncidentMerge(c.Context(), incidentID, mergeID, tenantID, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.revertIncidentMerge RevertIncidentMerge: %v", err)
+		return incidentErrorResponse(c, err, "Failed to revert incident merge")
+	}
+
+	return c.JSON(merge)
+}
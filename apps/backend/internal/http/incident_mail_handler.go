@@ -0,0 +1,167 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Mailbox endpoints
+func (h *IncidentHandler) listMailboxes(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	mailboxes, err := h.incidentService.ListMailboxes(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listMailboxes ListMailboxes: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get mailboxes",
+		})
+	}
+
+	responses := make([]dto.IncidentMailboxResponse, 0, len(mailboxes))
+	for _, mailbox := range mailboxes {
+		responses = append(responses, convertToMailboxResponse(mailbox))
+	}
+	return c.JSON(responses)
+}
+
+func (h *IncidentHandler) createMailbox(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.IncidentMailboxRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.createMailbox BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.createMailbox validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	mailbox, err := h.incidentService.CreateMailbox(c.Context(), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.createMailbox CreateMailbox: %v", err)
+		return incidentErrorResponse(c, err, "Failed to create mailbox")
+	}
+
+	return c.Status(201).JSON(convertToMailboxResponse(mailbox))
+}
+
+func (h *IncidentHandler) updateMailbox(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	mailboxID := c.Params("mailbox_id")
+
+	var req dto.IncidentMailboxRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateMailbox BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateMailbox validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	mailbox, err := h.incidentService.UpdateMailbox(c.Context(), mailboxID, tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateMailbox UpdateMailbox: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update mailbox")
+	}
+
+	return c.JSON(convertToMailboxResponse(mailbox))
+}
+
+func (h *IncidentHandler) deleteMailbox(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	mailboxID := c.Params("mailbox_id")
+
+	if err := h.incidentService.DeleteMailbox(c.Context(), mailboxID, tenantID); err != nil {
+		log.Printf("ERROR: incident_handler.deleteMailbox DeleteMailbox: %v", err)
+		return incidentErrorResponse(c, err, "Failed to delete mailbox")
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+// pollMailbox polls a mailbox right away; connection errors are reported in the result
+func (h *IncidentHandler) pollMailbox(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	mailboxID := c.Params("mailbox_id")
+
+	result, err := h.incidentService.PollMailbox(c.Context(), mailboxID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.pollMailbox PollMailbox: %v", err)
+		return incidentErrorResponse(c, err, "Failed to poll mailbox")
+	}
+
+	return c.JSON(result)
+}
+
+func (h *IncidentHandler) getMailboxMessages(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	mailboxID := c.Params("mailbox_id")
+
+	messages, err := h.incidentService.GetMailboxMessages(c.Context(), mailboxID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getMailboxMessages GetMailboxMessages: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get mailbox messages")
+	}
+
+	responses := make([]dto.IncidentMailMessageResponse, 0, len(messages))
+	for _, message := range messages {
+		responses = append(responses, dto.IncidentMailMessageResponse{
+			ID:          message.ID,
+			MessageID:   message.MessageID,
+			FromAddress: message.FromAddress,
+			Subject:     message.Subject,
+			Status:      message.Status,
+			IncidentID:  message.IncidentID,
+			CommentID:   message.CommentID,
+			Attachments: message.Attachments,
+			Error:       message.Error,
+			SentAt:      message.SentAt,
+			ProcessedAt: message.ProcessedAt,
+		})
+	}
+
+	return c.JSON(responses)
+}
+
+func convertToMailboxResponse(mailbox *repo.IncidentMailbox) dto.IncidentMailboxResponse {
+	return dto.IncidentMailboxResponse{
+		ID:                 mailbox.ID,
+		Name:               mailbox.Name,
+		Host:               mailbox.Host,
+		Port:               mailbox.Port,
+		UseTLS:             mailbox.UseTLS,
+		Username:           mailbox.Username,
+		HasPassword:        mailbox.Password != "",
+		DefaultCategory:    mailbox.DefaultCategory,
+		DefaultCriticality: mailbox.DefaultCriticality,
+		AssignedTo:         mailbox.AssignedTo,
+		FallbackReporterID: mailbox.FallbackReporterID,
+		DeleteAfterImport:  mailbox.DeleteAfterImport,
+		IsActive:           mailbox.IsActive,
+		LastPolledAt:       mailbox.LastPolledAt,
+		LastError:          mailbox.LastError,
+		CreatedAt:          mailbox.CreatedAt,
+		UpdatedAt:          mailbox.UpdatedAt,
+	}
+}
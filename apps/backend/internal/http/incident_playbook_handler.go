@@ -0,0 +1,185 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Incident playbook endpoints
+func (h *IncidentHandler) listPlaybooks(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	playbooks, err := h.incidentService.GetPlaybooks(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listPlaybooks GetPlaybooks: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get playbooks",
+		})
+	}
+
+	responses := make([]dto.IncidentPlaybookResponse, 0, len(playbooks))
+	for _, playbook := range playbooks {
+		responses = append(responses, convertToPlaybookResponse(playbook))
+	}
+	return c.JSON(responses)
+}
+
+func (h *IncidentHandler) getPlaybook(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	category := c.Params("category")
+
+	playbook, err := h.incidentService.GetPlaybook(c.Context(), tenantID, category)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getPlaybook GetPlaybook: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get playbook")
+	}
+
+	return c.JSON(convertToPlaybookResponse(playbook))
+}
+
+func (h *IncidentHandler) setPlaybook(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	category := c.Params("category")
+
+	var req dto.IncidentPlaybookRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.setPlaybook BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.setPlaybook validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	playbook, err := h.incidentService.SetPlaybook(c.Context(), tenantID, category, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.setPlaybook SetPlaybook: %v", err)
+		return incidentErrorResponse(c, err, "Failed to save playbook")
+	}
+
+	return c.JSON(convertToPlaybookResponse(playbook))
+}
+
+func (h *IncidentHandler) resetPlaybook(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	category := c.Params("category")
+
+	playbook, err := h.incidentService.ResetPlaybook(c.Context(), tenantID, category)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.resetPlaybook ResetPlaybook: %v", err)
+		return incidentErrorResponse(c, err, "Failed to reset playbook")
+	}
+
+	return c.JSON(convertToPlaybookResponse(playbook))
+}
+
+func (h *IncidentHandler) getIncidentPlaybook(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	progress, err := h.incidentService.GetIncidentPlaybook(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentPlaybook GetIncidentPlaybook: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident playbook")
+	}
+
+	return c.JSON(progress)
+}
+
+func (h *IncidentHandler) applyIncidentPlaybook(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	progress, err := h.incidentService.ApplyIncidentPlaybook(c.Context(), incidentID, tenantID, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.applyIncidentPlaybook ApplyIncidentPlaybook: %v", err)
+		return incidentErrorResponse(c, err, "Failed to apply playbook")
+	}
+
+	return c.JSON(progress)
+}
+
+func (h *IncidentHandler) updateActionStatus(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	actionID := c.Params("actionId")
+
+	var req dto.IncidentActionStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateActionStatus BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateActionStatus validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	action, err := h.incidentService.UpdateActionStatus(c.Context(), incidentID, actionID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateActionStatus UpdateActionStatus: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update action status")
+	}
+
+	return c.JSON(dto.IncidentActionResponse{
+		ID:           action.ID,
+		IncidentID:   action.IncidentID,
+		ActionType:   action.ActionType,
+		Title:        action.Title,
+		Description:  action.Description,
+		AssignedTo:   action.AssignedTo,
+		DueDate:      action.DueDate,
+		CompletedAt:  action.CompletedAt,
+		Status:       action.Status,
+		CreatedBy:    action.CreatedBy,
+		CreatedAt:    action.CreatedAt,
+		UpdatedAt:    action.UpdatedAt,
+		PlaybookID:   action.PlaybookID,
+		PlaybookName: action.PlaybookName,
+		StepOrder:    action.StepOrder,
+	})
+}
+
+func convertToPlaybookResponse(playbook *repo.IncidentPlaybook) dto.IncidentPlaybookResponse {
+	steps := make([]dto.IncidentPlaybookStepResponse, 0, len(playbook.Steps))
+	for _, step := range playbook.Steps {
+		steps = append(steps, dto.IncidentPlaybookStepResponse{
+			StepOrder:      step.StepOrder,
+			ActionType:     step.ActionType,
+			Title:          step.Title,
+			Description:    step.Description,
+			AssigneeRole:   step.AssigneeRole,
+			DueOffsetHours: step.DueOffsetHours,
+		})
+	}
+
+	return dto.IncidentPlaybookResponse{
+		ID:          playbook.ID,
+		Category:    playbook.Category,
+		Name:        playbook.Name,
+		Description: playbook.Description,
+		IsActive:    playbook.IsActive,
+		IsDefault:   playbook.TenantID == nil,
+		Steps:       steps,
+		UpdatedAt:   playbook.UpdatedAt,
+	}
+}
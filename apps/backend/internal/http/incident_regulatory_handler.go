@@ -0,0 +1,273 @@
+package http
+
+import (
+	"log"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Regulatory profile endpoints
+func (h *IncidentHandler) listRegulatoryProfiles(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	profiles, err := h.incidentService.ListRegulatoryProfiles(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listRegulatoryProfiles ListRegulatoryProfiles: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get regulatory profiles",
+		})
+	}
+
+	responses := make([]dto.IncidentRegulatoryProfileResponse, 0, len(profiles))
+	for _, profile := range profiles {
+		responses = append(responses, convertToRegulatoryProfileResponse(profile))
+	}
+	return c.JSON(responses)
+}
+
+func (h *IncidentHandler) getRegulatoryPresets(c *fiber.Ctx) error {
+	return c.JSON(h.incidentService.GetRegulatoryPresets())
+}
+
+func (h *IncidentHandler) createRegulatoryProfile(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var req dto.IncidentRegulatoryProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.createRegulatoryProfile BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.createRegulatoryProfile validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	profile, err := h.incidentService.CreateRegulatoryProfile(c.Context(), tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.createRegulatoryProfile CreateRegulatoryProfile: %v", err)
+		return incidentErrorResponse(c, err, "Failed to create regulatory profile")
+	}
+
+	return c.Status(201).JSON(convertToRegulatoryProfileResponse(profile))
+}
+
+func (h *IncidentHandler) createRegulatoryProfileFromPreset(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	framework := c.Params("framework")
+
+	profile, err := h.incidentService.CreateRegulatoryProfileFromPreset(c.Context(), tenantID, framework)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.createRegulatoryProfileFromPreset CreateRegulatoryProfileFromPreset: %v", err)
+		return incidentErrorResponse(c, err, "Failed to create regulatory profile")
+	}
+
+	return c.Status(201).JSON(convertToRegulatoryProfileResponse(profile))
+}
+
+func (h *IncidentHandler) updateRegulatoryProfile(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	profileID := c.Params("profile_id")
+
+	var req dto.IncidentRegulatoryProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateRegulatoryProfile BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateRegulatoryProfile validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	profile, err := h.incidentService.UpdateRegulatoryProfile(c.Context(), profileID, tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateRegulatoryProfile UpdateRegulatoryProfile: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update regulatory profile")
+	}
+
+	return c.JSON(convertToRegulatoryProfileResponse(profile))
+}
+
+func (h *IncidentHandler) deleteRegulatoryProfile(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	profileID := c.Params("profile_id")
+
+	if err := h.incidentService.DeleteRegulatoryProfile(c.Context(), profileID, tenantID); err != nil {
+		log.Printf("ERROR: incident_handler.deleteRegulatoryProfile DeleteRegulatoryProfile: %v", err)
+		return incidentErrorResponse(c, err, "Failed to delete regulatory profile")
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+// Incident notification endpoints
+func (h *IncidentHandler) getIncidentNotifications(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	notifications, err := h.incidentService.GetIncidentNotifications(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentNotifications GetIncidentNotifications: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident notifications")
+	}
+
+	now := time.Now()
+	response := dto.IncidentNotificationsResponse{
+		IncidentID:    incidentID,
+		Notifications: make([]dto.IncidentNotificationResponse, 0, len(notifications)),
+	}
+	for _, notification := range notifications {
+		item := convertToNotificationResponse(notification, now)
+		if item.Status == dto.NotificationStatusPending || item.Status == dto.NotificationStatusDraft {
+			response.Pending++
+		}
+		if item.IsOverdue {
+			response.Overdue++
+		}
+		response.Notifications = append(response.Notifications, item)
+	}
+
+	return c.JSON(response)
+}
+
+func (h *IncidentHandler) generateNotificationDraft(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+	notificationID := c.Params("notification_id")
+
+	notification, err := h.incidentService.GenerateNotificationDraft(c.Context(), incidentID, notificationID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.generateNotificationDraft GenerateNotificationDraft: %v", err)
+		return incidentErrorResponse(c, err, "Failed to generate notification draft")
+	}
+
+	return c.JSON(convertToNotificationResponse(notification, time.Now()))
+}
+
+func (h *IncidentHandler) updateIncidentNotification(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+	notificationID := c.Params("notification_id")
+
+	var req dto.IncidentNotificationUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateIncidentNotification BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateIncidentNotification validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	notification, err := h.incidentService.UpdateIncidentNotification(c.Context(), incidentID, notificationID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateIncidentNotification UpdateIncidentNotification: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update notification")
+	}
+
+	return c.JSON(convertToNotificationResponse(notification, time.Now()))
+}
+
+func (h *IncidentHandler) listOverdueNotifications(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	notifications, err := h.incidentService.ListOverdueNotifications(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listOverdueNotifications ListOverdueNotifications: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get overdue notifications",
+		})
+	}
+
+	now := time.Now()
+	responses := make([]dto.IncidentNotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		responses = append(responses, convertToNotificationResponse(notification, now))
+	}
+	return c.JSON(responses)
+}
+
+func convertToRegulatoryProfileResponse(profile *repo.IncidentRegulatoryProfile) dto.IncidentRegulatoryProfileResponse {
+	obligations := make([]dto.IncidentRegulatoryObligationResponse, 0, len(profile.Obligations))
+	for _, obligation := range profile.Obligations {
+		obligations = append(obligations, dto.IncidentRegulatoryObligationResponse{
+			ID:            obligation.ID,
+			Title:         obligation.Title,
+			RecipientType: obligation.RecipientType,
+			Recipient:     obligation.Recipient,
+			DeadlineHours: obligation.DeadlineHours,
+			TemplateID:    obligation.TemplateID,
+			Description:   obligation.Description,
+		})
+	}
+
+	return dto.IncidentRegulatoryProfileResponse{
+		ID:          profile.ID,
+		Name:        profile.Name,
+		Framework:   profile.Framework,
+		Categories:  profile.Categories,
+		IsActive:    profile.IsActive,
+		Obligations: obligations,
+		CreatedAt:   profile.CreatedAt,
+		UpdatedAt:   profile.UpdatedAt,
+	}
+}
+
+func convertToNotificationResponse(n *repo.IncidentNotification, now time.Time) dto.IncidentNotificationResponse {
+	response := dto.IncidentNotificationResponse{
+		ID:              n.ID,
+		IncidentID:      n.IncidentID,
+		IncidentTitle:   n.IncidentTitle,
+		ObligationID:    n.ObligationID,
+		ProfileName:     n.ProfileName,
+		Title:           n.Title,
+		RecipientType:   n.RecipientType,
+		Recipient:       n.Recipient,
+		DueAt:           n.DueAt,
+		Status:          n.Status,
+		DraftContent:    n.DraftContent,
+		SentAt:          n.SentAt,
+		SentBy:          n.SentBy,
+		SentTo:          n.SentTo,
+		Channel:         n.Channel,
+		ReferenceNumber: n.ReferenceNumber,
+		Notes:           n.Notes,
+		UpdatedAt:       n.UpdatedAt,
+	}
+
+	switch n.Status {
+	case dto.NotificationStatusPending, dto.NotificationStatusDraft:
+		remaining := int(n.DueAt.Sub(now).Minutes())
+		response.RemainingMinutes = &remaining
+		response.IsOverdue = now.After(n.DueAt)
+	case dto.NotificationStatusSent:
+		response.IsLate = n.SentAt != nil && n.SentAt.After(n.DueAt)
+	}
+
+	return response
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"fmt"
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Post-incident review endpoints
+func (h *IncidentHandler) getIncidentReview(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	review, err := h.incidentService.GetIncidentReview(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentReview GetIncidentReview: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident review")
+	}
+
+	return c.JSON(convertToIncidentReviewResponse(review))
+}
+
+func (h *IncidentHandler) saveIncidentReview(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	var req dto.IncidentReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.saveIncidentReview BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.saveIncidentReview validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	review, err := h.incidentService.SaveIncidentReview(c.Context(), incidentID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.saveIncidentReview SaveIncidentReview: %v", err)
+		return incidentErrorResponse(c, err, "Failed to save incident review")
+	}
+
+	return c.JSON(convertToIncidentReviewResponse(review))
+}
+
+func (h *IncidentHandler) getIncidentReviewReport(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+	format := c.Query("format", "pdf")
+	if format != "pdf" && format != "html" {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid format, expected pdf or html",
+		})
+	}
+
+	report, err := h.incidentService.GenerateIncidentReviewReport(c.Context(), incidentID, tenantID, format)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentReviewReport GenerateIncidentReviewReport: %v", err)
+		return incidentErrorResponse(c, err, "Failed to generate incident review report")
+	}
+
+	if format == "html" {
+		c.Set("Content-Type", "text/html; charset=utf-8")
+	} else {
+		c.Set("Content-Type", "application/pdf")
+	}
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"incident-review-%s.%s\"", incidentID, format))
+	return c.Send(report)
+}
+
+func (h *IncidentHandler) addReviewFollowUp(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+	incidentID := c.Params("id")
+
+	var req dto.IncidentReviewFollowUpRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.addReviewFollowUp BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.addReviewFollowUp validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	followUp, err := h.incidentService.AddReviewFollowUp(c.Context(), incidentID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.addReviewFollowUp AddReviewFollowUp: %v", err)
+		return incidentErrorResponse(c, err, "Failed to add follow-up")
+	}
+
+	return c.Status(201).JSON(convertToReviewFollowUpResponse(followUp))
+}
+
+func (h *IncidentHandler) updateReviewFollowUp(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+	followUpID := c.Params("followup_id")
+
+	var req dto.IncidentReviewFollowUpUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateReviewFollowUp BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateReviewFollowUp validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	followUp, err := h.incidentService.UpdateReviewFollowUp(c.Context(), incidentID, followUpID, tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateReviewFollowUp UpdateReviewFollowUp: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update follow-up")
+	}
+
+	return c.JSON(convertToReviewFollowUpResponse(followUp))
+}
+
+func (h *IncidentHandler) deleteReviewFollowUp(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+	followUpID := c.Params("followup_id")
+
+	if err := h.incidentService.DeleteReviewFollowUp(c.Context(), incidentID, followUpID, tenantID); err != nil {
+		log.Printf("ERROR: incident_handler.deleteReviewFollowUp DeleteReviewFollowUp: %v", err)
+		return incidentErrorResponse(c, err, "Failed to delete follow-up")
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+func convertToIncidentReviewResponse(review *repo.IncidentReview) dto.IncidentReviewResponse {
+	factors := make([]dto.IncidentReviewFactorResponse, 0, len(review.Factors))
+	for _, factor := range review.Factors {
+		factors = append(factors, dto.IncidentReviewFactorResponse{
+			ID:          factor.ID,
+			FactorType:  factor.FactorType,
+			Description: factor.Description,
+		})
+	}
+
+	followUps := make([]dto.IncidentReviewFollowUpResponse, 0, len(review.FollowUps))
+	for _, followUp := range review.FollowUps {
+		followUps = append(followUps, convertToReviewFollowUpResponse(followUp))
+	}
+
+	return dto.IncidentReviewResponse{
+		ID:             review.ID,
+		IncidentID:     review.IncidentID,
+		Status:         review.Status,
+		Summary:        review.Summary,
+		RootCause:      review.RootCause,
+		Impact:         review.Impact,
+		WhatWentWell:   review.WhatWentWell,
+		WhatWentWrong:  review.WhatWentWrong,
+		LessonsLearned: review.LessonsLearned,
+		ReviewDate:     review.ReviewDate,
+		FacilitatorID:  review.FacilitatorID,
+		Participants:   review.Participants,
+		Factors:        factors,
+		FollowUps:      followUps,
+		CompletedAt:    review.CompletedAt,
+		CreatedBy:      review.CreatedBy,
+		CreatedAt:      review.CreatedAt,
+		UpdatedAt:      review.UpdatedAt,
+	}
+}
+
+func convertToReviewFollowUpResponse(followUp *repo.IncidentReviewFollowUp) dto.IncidentReviewFollowUpResponse {
+	return dto.IncidentReviewFollowUpResponse{
+		ID:           followUp.ID,
+		FollowUpType: followUp.FollowUpType,
+		Title:        followUp.Title,
+		Description:  followUp.Description,
+		OwnerID:      followUp.OwnerID,
+		DueDate:      followUp.DueDate,
+		Status:       followUp.Status,
+		RiskID:       followUp.RiskID,
+		ControlID:    followUp.ControlID,
+		CreatedBy:    followUp.CreatedBy,
+		CreatedAt:    followUp.CreatedAt,
+		UpdatedAt:    followUp.UpdatedAt,
+	}
+}
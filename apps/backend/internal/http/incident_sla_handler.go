@@ -0,0 +1,225 @@
+package http
+
+import (
+	"log"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Incident SLA endpoints
+func (h *IncidentHandler) getSLAPolicies(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	policies, err := h.incidentService.GetSLAPolicies(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getSLAPolicies GetSLAPolicies: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get SLA policies",
+		})
+	}
+
+	return c.JSON(convertToSLAPolicyResponses(policies))
+}
+
+func (h *IncidentHandler) setSLAPolicies(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var req dto.IncidentSLAPoliciesRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.setSLAPolicies BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.setSLAPolicies validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	policies, err := h.incidentService.SetSLAPolicies(c.Context(), tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.setSLAPolicies SetSLAPolicies: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update SLA policies")
+	}
+
+	return c.JSON(convertToSLAPolicyResponses(policies))
+}
+
+func (h *IncidentHandler) listBusinessCalendars(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	calendars, err := h.incidentService.ListBusinessCalendars(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listBusinessCalendars ListBusinessCalendars: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get business calendars",
+		})
+	}
+
+	if calendars == nil {
+		calendars = []*repo.IncidentBusinessCalendar{}
+	}
+	return c.JSON(calendars)
+}
+
+func (h *IncidentHandler) createBusinessCalendar(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var req dto.IncidentBusinessCalendarRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.createBusinessCalendar BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.createBusinessCalendar validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	calendar, err := h.incidentService.CreateBusinessCalendar(c.Context(), tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.createBusinessCalendar CreateBusinessCalendar: %v", err)
+		return incidentErrorResponse(c, err, "Failed to create business calendar")
+	}
+
+	return c.Status(201).JSON(calendar)
+}
+
+func (h *IncidentHandler) updateBusinessCalendar(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	calendarID := c.Params("calendar_id")
+
+	var req dto.IncidentBusinessCalendarRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.updateBusinessCalendar BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.updateBusinessCalendar validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	calendar, err := h.incidentService.UpdateBusinessCalendar(c.Context(), calendarID, tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.updateBusinessCalendar UpdateBusinessCalendar: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update business calendar")
+	}
+
+	return c.JSON(calendar)
+}
+
+func (h *IncidentHandler) deleteBusinessCalendar(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	calendarID := c.Params("calendar_id")
+
+	if err := h.incidentService.DeleteBusinessCalendar(c.Context(), calendarID, tenantID); err != nil {
+		log.Printf("ERROR: incident_handler.deleteBusinessCalendar DeleteBusinessCalendar: %v", err)
+		return incidentErrorResponse(c, err, "Failed to delete business calendar")
+	}
+
+	return c.Status(204).Send(nil)
+}
+
+func (h *IncidentHandler) listSLAEvents(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filters := make(map[string]interface{})
+	if incidentID := c.Query("incident_id"); incidentID != "" {
+		filters["incident_id"] = incidentID
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		filters["event_type"] = eventType
+	}
+	if c.QueryBool("mine") {
+		filters["recipient_user_id"] = c.Locals("user_id").(string)
+	}
+	if since := c.Query("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": "Invalid since parameter, expected RFC3339",
+			})
+		}
+		filters["since"] = sinceTime
+	}
+
+	limit := c.QueryInt("limit", 100)
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	events, err := h.incidentService.ListSLAEvents(c.Context(), tenantID, filters, limit)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.listSLAEvents ListSLAEvents: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get SLA events",
+		})
+	}
+
+	responses := make([]dto.IncidentSLAEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, dto.IncidentSLAEventResponse{
+			ID:              event.ID,
+			IncidentID:      event.IncidentID,
+			IncidentTitle:   event.IncidentTitle,
+			Target:          event.Target,
+			EventType:       event.EventType,
+			DueAt:           event.DueAt,
+			RecipientUserID: event.RecipientUserID,
+			CreatedAt:       event.CreatedAt,
+		})
+	}
+
+	return c.JSON(responses)
+}
+
+func (h *IncidentHandler) getIncidentSLA(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	sla, err := h.incidentService.GetIncidentSLA(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getIncidentSLA GetIncidentSLA: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident SLA")
+	}
+
+	return c.JSON(sla)
+}
+
+func convertToSLAPolicyResponses(policies []*repo.IncidentSLAPolicy) []dto.IncidentSLAPolicyResponse {
+	responses := make([]dto.IncidentSLAPolicyResponse, 0, len(policies))
+	for _, policy := range policies {
+		responses = append(responses, dto.IncidentSLAPolicyResponse{
+			Criticality:       policy.Criticality,
+			ResponseMinutes:   policy.ResponseMinutes,
+			ResolutionMinutes: policy.ResolutionMinutes,
+			WarningPercent:    policy.WarningPercent,
+			BusinessHoursOnly: policy.BusinessHoursOnly,
+			CalendarID:        policy.CalendarID,
+			IsDefault:         policy.ID == "",
+		})
+	}
+	return responses
+}
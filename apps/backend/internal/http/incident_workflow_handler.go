@@ -0,0 +1,146 @@
+package http
+
+import (
+	"errors"
+	"log"
+
+	"risknexus/backend/internal/domain"
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Incident status workflow endpoints
+func (h *IncidentHandler) getStatusWorkflow(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	transitions, isDefault, err := h.incidentService.GetStatusWorkflow(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getStatusWorkflow GetStatusWorkflow: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to get incident workflow",
+		})
+	}
+
+	return c.JSON(convertToWorkflowResponse(transitions, isDefault))
+}
+
+func (h *IncidentHandler) setStatusWorkflow(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var req dto.IncidentWorkflowRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: incident_handler.setStatusWorkflow BodyParser: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: incident_handler.setStatusWorkflow validation: %v", err)
+		return c.Status(400).JSON(fiber.Map{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+	}
+
+	transitions, isDefault, err := h.incidentService.SetStatusWorkflow(c.Context(), tenantID, req)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.setStatusWorkflow SetStatusWorkflow: %v", err)
+		return incidentErrorResponse(c, err, "Failed to update incident workflow")
+	}
+
+	return c.JSON(convertToWorkflowResponse(transitions, isDefault))
+}
+
+func (h *IncidentHandler) getStatusTimeline(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	incidentID := c.Params("id")
+
+	history, err := h.incidentService.GetStatusTimeline(c.Context(), incidentID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: incident_handler.getStatusTimeline GetStatusTimeline: %v", err)
+		return incidentErrorResponse(c, err, "Failed to get incident timeline")
+	}
+
+	responses := make([]dto.IncidentStatusChangeResponse, 0, len(history))
+	for _, change := range history {
+		responses = append(responses, dto.IncidentStatusChangeResponse{
+			ID:         change.ID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			IsReopen:   change.IsReopen,
+			Comment:    change.Comment,
+			ChangedBy:  change.ChangedBy,
+			ChangedAt:  change.ChangedAt,
+		})
+	}
+
+	return c.JSON(responses)
+}
+
+func convertToWorkflowResponse(transitions []*repo.IncidentStatusTransition, isDefault bool) dto.IncidentWorkflowResponse {
+	response := dto.IncidentWorkflowResponse{
+		IsDefault:   isDefault,
+		Transitions: make([]dto.IncidentStatusTransitionResponse, 0, len(transitions)),
+	}
+	for _, transition := range transitions {
+		requiredFields := transition.RequiredFields
+		if requiredFields == nil {
+			requiredFields = []string{}
+		}
+		response.Transitions = append(response.Transitions, dto.IncidentStatusTransitionResponse{
+			FromStatus:     transition.FromStatus,
+			ToStatus:       transition.ToStatus,
+			RequiredFields: requiredFields,
+			IsReopen:       domain.IsIncidentReopen(transition.FromStatus, transition.ToStatus),
+		})
+	}
+	return response
+}
+
+// incidentErrorResponse maps not found, workflow and validation errors; anything else is reported with the fallback message
+func incidentErrorResponse(c *fiber.Ctx, err error, fallback string) error {
+	var validationErr domain.ValidationError
+	switch {
+	case err.Error() == "incident not found":
+		return c.Status(404).JSON(fiber.Map{"error": "Incident not found"})
+	case errors.Is(err, domain.ErrIncidentCalendarNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Business calendar not found"})
+	case errors.Is(err, domain.ErrAlertSourceNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Alert source not found"})
+	case errors.Is(err, domain.ErrIncidentPlaybookNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Playbook not found"})
+	case errors.Is(err, domain.ErrIncidentActionNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Action not found"})
+	case errors.Is(err, domain.ErrRegulatoryProfileNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Regulatory profile not found"})
+	case errors.Is(err, domain.ErrIncidentNotificationNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Notification not found"})
+	case errors.Is(err, domain.ErrIncidentReviewNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Incident review not found"})
+	case errors.Is(err, domain.ErrReviewFollowUpNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Follow-up not found"})
+	case errors.Is(err, domain.ErrIncidentEvidenceNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Evidence not found"})
+	case errors.Is(err, domain.ErrIncidentLinkNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Incident link not found"})
+	case errors.Is(err, domain.ErrIncidentMergeNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Incident merge not found"})
+	case errors.Is(err, domain.ErrIncidentMailboxNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Mailbox not found"})
+	case errors.Is(err, domain.ErrIncidentEvidenceSealed), errors.Is(err, domain.ErrEvidenceDocumentProtected):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrIncidentAlreadyMerged), errors.Is(err, domain.ErrIncidentMergeReverted),
+		errors.Is(err, domain.ErrIncidentMergeRevertExpired):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrIncidentTransitionNotAllowed), errors.Is(err, domain.ErrIncidentStatusConflict):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &validationErr):
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request data", "details": err.Error()})
+	default:
+		return c.Status(500).JSON(fiber.Map{"error": fallback})
+	}
+}
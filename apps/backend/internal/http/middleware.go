@@ -128,3 +128,17 @@ func RequirePermission(permission string) fiber.Handler {
 		return c.Next()
 	}
 }
+
+// publicToken returns the machine token of a public endpoint from the given header or an
+// Authorization: Bearer header. Tokens are never read from the query string, where proxies
+// and access logs would keep them.
+func publicToken(c *fiber.Ctx, header string) string {
+	if token := strings.TrimSpace(c.Get(header)); token != "" {
+		return token
+	}
+	authHeader := c.Get("Authorization")
+	if token := strings.TrimPrefix(authHeader, "Bearer "); token != authHeader {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"log"
+	"time"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Risk Acceptance Rules endpoints
+func (h *RiskHandler) getAcceptanceRules(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	rules, err := h.riskService.GetAcceptanceRules(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getAcceptanceRules service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]dto.RiskAcceptanceRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		responses = append(responses, dto.RiskAcceptanceRuleResponse{
+			LevelLabel:      rule.LevelLabel,
+			ApproverRole:    rule.ApproverRole,
+			MaxValidityDays: rule.MaxValidityDays,
+			IsDefault:       rule.ID == "",
+		})
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) setAcceptanceRule(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskAcceptanceRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.setAcceptanceRule invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.setAcceptanceRule validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.setAcceptanceRule level=%s tenant=%s user=%s", req.LevelLabel, tenantID, userID)
+
+	if err := h.riskService.SetAcceptanceRule(c.Context(), tenantID, req, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.setAcceptanceRule service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return h.getAcceptanceRules(c)
+}
+
+// Risk Acceptances endpoints
+func (h *RiskHandler) getRiskAcceptances(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	acceptances, err := h.riskService.GetAcceptances(c.Context(), riskID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskAcceptances service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	responses := make([]dto.RiskAcceptanceResponse, 0, len(acceptances))
+	for i := range acceptances {
+		responses = append(responses, convertToRiskAcceptanceResponse(&acceptances[i]))
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) requestRiskAcceptance(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.requestRiskAcceptance riskID=%s user=%s", riskID, userID)
+
+	var req dto.RiskAcceptanceRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.requestRiskAcceptance invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.requestRiskAcceptance validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	acceptance, err := h.riskService.RequestAcceptance(c.Context(), riskID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.requestRiskAcceptance service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	log.Printf("DEBUG: RiskHandler.requestRiskAcceptance success acceptanceID=%s", acceptance.ID)
+	return c.Status(201).JSON(fiber.Map{"data": convertToRiskAcceptanceResponse(acceptance)})
+}
+
+func (h *RiskHandler) approveRiskAcceptance(c *fiber.Ctx) error {
+	return h.decideRiskAcceptance(c, "approve")
+}
+
+func (h *RiskHandler) rejectRiskAcceptance(c *fiber.Ctx) error {
+	return h.decideRiskAcceptance(c, "reject")
+}
+
+func (h *RiskHandler) revokeRiskAcceptance(c *fiber.Ctx) error {
+	return h.decideRiskAcceptance(c, "revoke")
+}
+
+func (h *RiskHandler) decideRiskAcceptance(c *fiber.Ctx, decision string) error {
+	riskID := c.Params("risk_id")
+	acceptanceID := c.Params("acceptance_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.decideRiskAcceptance %s riskID=%s acceptanceID=%s user=%s", decision, riskID, acceptanceID, userID)
+
+	var req dto.RiskAcceptanceDecisionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			log.Printf("ERROR: RiskHandler.decideRiskAcceptance invalid body: %v", err)
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.decideRiskAcceptance validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	var acceptance *repo.RiskAcceptance
+	var err error
+	switch decision {
+	case "approve":
+		acceptance, err = h.riskService.ApproveAcceptance(c.Context(), riskID, acceptanceID, tenantID, req, userID)
+	case "reject":
+		acceptance, err = h.riskService.RejectAcceptance(c.Context(), riskID, acceptanceID, tenantID, req, userID)
+	default:
+		acceptance, err = h.riskService.RevokeAcceptance(c.Context(), riskID, acceptanceID, tenantID, req, userID)
+	}
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.decideRiskAcceptance %s service error: %v", decision, err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToRiskAcceptanceResponse(acceptance)})
+}
+
+func convertToRiskAcceptanceResponse(acceptance *repo.RiskAcceptance) dto.RiskAcceptanceResponse {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	return dto.RiskAcceptanceResponse{
+		ID:              acceptance.ID,
+		RiskID:          acceptance.RiskID,
+		Justification:   acceptance.Justification,
+		RiskLevel:       acceptance.RiskLevel,
+		LevelLabel:      acceptance.LevelLabel,
+		ApproverRole:    acceptance.ApproverRole,
+		Status:          acceptance.Status,
+		RequestedBy:     acceptance.RequestedBy,
+		RequestedByName: acceptance.RequestedByName,
+		ApproverUserID:  acceptance.ApproverUserID,
+		ApproverName:    acceptance.ApproverName,
+		DecisionComment: acceptance.DecisionComment,
+		DecidedAt:       acceptance.DecidedAt,
+		ExpiresAt:       acceptance.ExpiresAt,
+		IsExpired:       acceptance.Status == dto.RiskAcceptanceStatusExpired || acceptance.ExpiresAt.Before(today),
+		CreatedAt:       acceptance.CreatedAt,
+		UpdatedAt:       acceptance.UpdatedAt,
+	}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Risk Analytics endpoints
+func (h *RiskHandler) parseAnalyticsFilter(c *fiber.Ctx) (dto.RiskAnalyticsFilter, error) {
+	var filter dto.RiskAnalyticsFilter
+	if err := c.QueryParser(&filter); err != nil {
+		return filter, err
+	}
+	return filter, h.validator.Struct(filter)
+}
+
+func (h *RiskHandler) getRiskAnalyticsSummary(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	summary, err := h.riskService.GetAnalyticsSummary(c.Context(), tenantID, filter)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskAnalyticsSummary service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": summary})
+}
+
+func (h *RiskHandler) getRiskHeatMap(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	heatMap, err := h.riskService.GetHeatMap(c.Context(), tenantID, filter)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskHeatMap service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": heatMap})
+}
+
+func (h *RiskHandler) getRiskLevelTrend(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	period := c.Query("period", dto.RiskTrendPeriodMonth)
+	if period != dto.RiskTrendPeriodMonth && period != dto.RiskTrendPeriodWeek {
+		return c.Status(400).JSON(fiber.Map{"error": "period must be week or month"})
+	}
+	periods := c.QueryInt("periods", 12)
+	if periods < 1 || periods > 104 {
+		return c.Status(400).JSON(fiber.Map{"error": "periods must be between 1 and 104"})
+	}
+
+	trend, err := h.riskService.GetLevelTrend(c.Context(), tenantID, filter, period, periods)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskLevelTrend service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": trend, "period": period})
+}
+
+func (h *RiskHandler) getTopRisks(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > 100 {
+		return c.Status(400).JSON(fiber.Map{"error": "limit must be between 1 and 100"})
+	}
+
+	risks, err := h.riskService.GetTopRisks(c.Context(), tenantID, filter, limit)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getTopRisks service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]dto.RiskResponse, 0, len(risks))
+	for i := range risks {
+		responses = append(responses, h.convertToRiskResponse(&risks[i]))
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) getOverdueTreatments(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	filter, err := h.parseAnalyticsFilter(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	overdue, err := h.riskService.GetOverdueTreatments(c.Context(), tenantID, filter)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getOverdueTreatments service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": overdue, "total": len(overdue)})
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Risk Appetite endpoints
+func (h *RiskHandler) getAppetiteStatements(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	statements, err := h.riskService.GetAppetiteStatements(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getAppetiteStatements service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	responses := make([]dto.RiskAppetiteStatementResponse, 0, len(statements))
+	for i := range statements {
+		responses = append(responses, convertToAppetiteStatementResponse(&statements[i]))
+	}
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) setAppetiteStatement(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskAppetiteStatementRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.setAppetiteStatement invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.setAppetiteStatement validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.setAppetiteStatement appetite=%d tolerance=%d user=%s", req.AppetiteLevel, req.ToleranceLevel, userID)
+
+	statement, err := h.riskService.SetAppetiteStatement(c.Context(), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.setAppetiteStatement service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToAppetiteStatementResponse(statement)})
+}
+
+func (h *RiskHandler) deleteAppetiteStatement(c *fiber.Ctx) error {
+	statementID := c.Params("statement_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.deleteAppetiteStatement statementID=%s user=%s", statementID, userID)
+
+	if err := h.riskService.DeleteAppetiteStatement(c.Context(), statementID, tenantID, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.deleteAppetiteStatement service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Risk appetite statement deleted successfully"})
+}
+
+// getOutOfAppetiteReport lists open risks exceeding tolerance; include_above_appetite adds risks above appetite
+func (h *RiskHandler) getOutOfAppetiteReport(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var req dto.RiskAppetiteReportRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid query parameters"})
+	}
+
+	risks, coverage, err := h.riskService.GetOutOfAppetiteReport(c.Context(), tenantID, req.IncludeAboveAppetite)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getOutOfAppetiteReport service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	report := dto.RiskAppetiteReportResponse{Items: make([]dto.RiskAppetiteReportItem, 0, len(risks))}
+	for i := range risks {
+		risk := h.convertToRiskResponse(&risks[i])
+		covered := coverage[risks[i].ID]
+		item := dto.RiskAppetiteReportItem{
+			Risk:          risk,
+			HasTreatment:  covered.HasTreatment,
+			HasAcceptance: covered.HasAcceptance,
+		}
+		if risk.AppetiteStatus != nil {
+			item.AppetiteStatus = *risk.AppetiteStatus
+		}
+
+		if item.AppetiteStatus == dto.RiskAppetiteStatusOutOfTolerance {
+			report.OutOfTolerance++
+			item.RequiresAction = !covered.HasTreatment && !covered.HasAcceptance
+			if item.RequiresAction {
+				report.RequiringAction++
+			}
+		} else {
+			report.AboveAppetite++
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	return c.JSON(fiber.Map{"data": report})
+}
+
+func convertToAppetiteStatementResponse(statement *repo.RiskAppetiteStatement) dto.RiskAppetiteStatementResponse {
+	return dto.RiskAppetiteStatementResponse{
+		ID:             statement.ID,
+		Category:       statement.Category,
+		Statement:      statement.Statement,
+		AppetiteLevel:  statement.AppetiteLevel,
+		ToleranceLevel: statement.ToleranceLevel,
+		UpdatedBy:      statement.UpdatedBy,
+		CreatedAt:      statement.CreatedAt,
+		UpdatedAt:      statement.UpdatedAt,
+	}
+}
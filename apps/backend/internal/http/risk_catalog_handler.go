@@ -0,0 +1,204 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// riskCatalogKinds maps the URL segment to the catalog kind
+var riskCatalogKinds = map[string]string{
+	"threats":         dto.RiskCatalogKindThreat,
+	"vulnerabilities": dto.RiskCatalogKindVulnerability,
+}
+
+// Risk Catalog endpoints
+func (h *RiskHandler) getCatalogEntries(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	kind, ok := riskCatalogKinds[c.Params("kind")]
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Catalog not found"})
+	}
+
+	var req dto.RiskCatalogListRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid query parameters"})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	entries, err := h.riskService.GetCatalogEntries(c.Context(), tenantID, kind, repo.RiskCatalogFilter{
+		AssetType:  req.AssetType,
+		Source:     req.Source,
+		Search:     req.Search,
+		ActiveOnly: req.Active,
+	})
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getCatalogEntries service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToCatalogEntryResponses(entries)})
+}
+
+func (h *RiskHandler) getCatalogEntry(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	kind, ok := riskCatalogKinds[c.Params("kind")]
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Catalog not found"})
+	}
+
+	entry, err := h.riskService.GetCatalogEntry(c.Context(), c.Params("entry_id"), tenantID, kind)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getCatalogEntry service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToCatalogEntryResponse(entry)})
+}
+
+func (h *RiskHandler) createCatalogEntry(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	kind, ok := riskCatalogKinds[c.Params("kind")]
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Catalog not found"})
+	}
+
+	var req dto.RiskCatalogEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.createCatalogEntry invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.createCatalogEntry validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.createCatalogEntry kind=%s code=%s user=%s", kind, req.Code, userID)
+
+	entry, err := h.riskService.CreateCatalogEntry(c.Context(), tenantID, kind, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.createCatalogEntry service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": convertToCatalogEntryResponse(entry)})
+}
+
+func (h *RiskHandler) updateCatalogEntry(c *fiber.Ctx) error {
+	entryID := c.Params("entry_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	kind, ok := riskCatalogKinds[c.Params("kind")]
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Catalog not found"})
+	}
+
+	var req dto.RiskCatalogEntryRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateCatalogEntry invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateCatalogEntry validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.updateCatalogEntry entryID=%s user=%s", entryID, userID)
+
+	entry, err := h.riskService.UpdateCatalogEntry(c.Context(), entryID, tenantID, kind, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.updateCatalogEntry service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToCatalogEntryResponse(entry)})
+}
+
+func (h *RiskHandler) deleteCatalogEntry(c *fiber.Ctx) error {
+	entryID := c.Params("entry_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	kind, ok := riskCatalogKinds[c.Params("kind")]
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Catalog not found"})
+	}
+
+	log.Printf("DEBUG: RiskHandler.deleteCatalogEntry entryID=%s user=%s", entryID, userID)
+
+	if err := h.riskService.DeleteCatalogEntry(c.Context(), entryID, tenantID, kind, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.deleteCatalogEntry service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Catalog entry deleted successfully"})
+}
+
+// getCatalogSuggestions returns threats and vulnerabilities applicable to an asset (asset_id) or asset type (asset_type)
+func (h *RiskHandler) getCatalogSuggestions(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	var req struct {
+		AssetID   string `query:"asset_id" validate:"omitempty,uuid"`
+		AssetType string `query:"asset_type" validate:"omitempty,oneof=server workstation application database document network_device other"`
+	}
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid query parameters"})
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	assetType, threats, vulnerabilities, err := h.riskService.SuggestCatalogEntries(c.Context(), tenantID, req.AssetID, req.AssetType)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getCatalogSuggestions service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": dto.RiskCatalogSuggestionsResponse{
+		AssetType:       assetType,
+		Threats:         convertToCatalogEntryResponses(threats),
+		Vulnerabilities: convertToCatalogEntryResponses(vulnerabilities),
+	}})
+}
+
+func convertToCatalogEntryResponses(entries []repo.RiskCatalogEntry) []dto.RiskCatalogEntryResponse {
+	responses := make([]dto.RiskCatalogEntryResponse, 0, len(entries))
+	for i := range entries {
+		responses = append(responses, convertToCatalogEntryResponse(&entries[i]))
+	}
+	return responses
+}
+
+func convertToCatalogEntryResponse(entry *repo.RiskCatalogEntry) dto.RiskCatalogEntryResponse {
+	assetTypes := entry.AssetTypes
+	if assetTypes == nil {
+		assetTypes = []string{}
+	}
+
+	return dto.RiskCatalogEntryResponse{
+		ID:          entry.ID,
+		Kind:        entry.Kind,
+		Code:        entry.Code,
+		Name:        entry.Name,
+		Description: entry.Description,
+		Source:      entry.Source,
+		AssetTypes:  assetTypes,
+		IsActive:    entry.IsActive,
+		IsBuiltin:   entry.TenantID == nil,
+		CreatedAt:   entry.CreatedAt,
+		UpdatedAt:   entry.UpdatedAt,
+	}
+}
@@ -29,6 +29,37 @@ func (h *RiskHandler) Register(r fiber.Router) {
 	risks := r.Group("/risks")
 	risks.Get("/", RequirePermission("risks.view"), h.listRisks)
 	risks.Post("/", RequirePermission("risks.create"), h.createRisk)
+	risks.Get("/acceptance-rules", RequirePermission("risks.view"), h.getAcceptanceRules)
+	risks.Put("/acceptance-rules", RequirePermission("risks.accept"), h.setAcceptanceRule)
+	risks.Get("/review-queue", RequirePermission("risks.view"), h.getReviewQueue)
+	risks.Get("/review-intervals", RequirePermission("risks.view"), h.getReviewIntervals)
+	risks.Put("/review-intervals", RequirePermission("risks.edit"), h.setReviewInterval)
+	risks.Delete("/review-intervals/:interval_id", RequirePermission("risks.edit"), h.deleteReviewInterval)
+	risks.Get("/analytics/summary", RequirePermission("risks.view"), h.getRiskAnalyticsSummary)
+	risks.Get("/analytics/heatmap", RequirePermission("risks.view"), h.getRiskHeatMap)
+	risks.Get("/analytics/level-trend", RequirePermission("risks.view"), h.getRiskLevelTrend)
+	risks.Get("/analytics/top", RequirePermission("risks.view"), h.getTopRisks)
+	risks.Get("/analytics/overdue-treatments", RequirePermission("risks.view"), h.getOverdueTreatments)
+	risks.Get("/appetite", RequirePermission("risks.view"), h.getAppetiteStatements)
+	risks.Put("/appetite", RequirePermission("risks.edit"), h.setAppetiteStatement)
+	risks.Get("/appetite/report", RequirePermission("risks.view"), h.getOutOfAppetiteReport)
+	risks.Delete("/appetite/:statement_id", RequirePermission("risks.edit"), h.deleteAppetiteStatement)
+	risks.Post("/import/preview", RequirePermission("risks.create"), h.previewRiskImport)
+	risks.Post("/import", RequirePermission("risks.create"), h.importRisks)
+	risks.Get("/catalog/suggestions", RequirePermission("risks.view"), h.getCatalogSuggestions)
+	risks.Get("/catalog/:kind", RequirePermission("risks.view"), h.getCatalogEntries)
+	risks.Post("/catalog/:kind", RequirePermission("risks.edit"), h.createCatalogEntry)
+	risks.Get("/catalog/:kind/:entry_id", RequirePermission("risks.view"), h.getCatalogEntry)
+	risks.Put("/catalog/:kind/:entry_id", RequirePermission("risks.edit"), h.updateCatalogEntry)
+	risks.Delete("/catalog/:kind/:entry_id", RequirePermission("risks.edit"), h.deleteCatalogEntry)
+	risks.Get("/kris", RequirePermission("risks.view"), h.getKRIs)
+	risks.Post("/kris", RequirePermission("risks.edit"), h.createKRI)
+	risks.Post("/kris/measurements", RequirePermission("risks.edit"), h.pushKRIMeasurements)
+	risks.Get("/kris/:kri_id", RequirePermission("risks.view"), h.getKRI)
+	risks.Put("/kris/:kri_id", RequirePermission("risks.edit"), h.updateKRI)
+	risks.Delete("/kris/:kri_id", RequirePermission("risks.edit"), h.deleteKRI)
+	risks.Get("/kris/:kri_id/measurements", RequirePermission("risks.view"), h.getKRIMeasurements)
+	risks.Post("/kris/:kri_id/measurements", RequirePermission("risks.edit"), h.addKRIMeasurement)
 	risks.Get("/:id", RequirePermission("risks.view"), h.getRisk)
 	risks.Put("/:id", RequirePermission("risks.edit"), h.updateRisk)
 	risks.Patch("/:id", RequirePermission("risks.edit"), h.updateRisk)
@@ -42,6 +73,9 @@ func (h *RiskHandler) Register(r fiber.Router) {
 	// History
 	riskID.Get("/history", RequirePermission("risks.view"), h.getRiskHistory)
 
+	// Status
+	riskID.Patch("/status", RequirePermission("risks.edit"), h.updateRiskStatus)
+
 	// Comments
 	riskID.Get("/comments", RequirePermission("risks.view"), h.getRiskComments)
 	riskID.Post("/comments", RequirePermission("risks.comment"), h.addRiskComment)
@@ -68,6 +102,39 @@ func (h *RiskHandler) Register(r fiber.Router) {
 	riskID.Post("/documents/link", RequirePermission("risks.edit"), h.linkRiskDocument)
 	riskID.Delete("/documents/:document_id", RequirePermission("risks.edit"), h.deleteRiskDocument)
 	riskID.Delete("/documents/:document_id/unlink", RequirePermission("risks.edit"), h.unlinkRiskDocument)
+
+	// Treatment plans
+	riskID.Get("/treatment-plans", RequirePermission("risks.view"), h.getTreatmentPlans)
+	riskID.Post("/treatment-plans", RequirePermission("risks.edit"), h.createTreatmentPlan)
+	riskID.Get("/treatment-plans/:plan_id", RequirePermission("risks.view"), h.getTreatmentPlan)
+	riskID.Put("/treatment-plans/:plan_id", RequirePermission("risks.edit"), h.updateTreatmentPlan)
+	riskID.Delete("/treatment-plans/:plan_id", RequirePermission("risks.edit"), h.deleteTreatmentPlan)
+	riskID.Post("/treatment-plans/:plan_id/tasks", RequirePermission("risks.edit"), h.addTreatmentTask)
+	riskID.Put("/treatment-plans/:plan_id/tasks/order", RequirePermission("risks.edit"), h.reorderTreatmentTasks)
+	riskID.Put("/treatment-plans/:plan_id/tasks/:task_id", RequirePermission("risks.edit"), h.updateTreatmentTask)
+	riskID.Patch("/treatment-plans/:plan_id/tasks/:task_id/status", RequirePermission("risks.edit"), h.updateTreatmentTaskStatus)
+	riskID.Delete("/treatment-plans/:plan_id/tasks/:task_id", RequirePermission("risks.edit"), h.deleteTreatmentTask)
+
+	// Acceptances
+	riskID.Get("/acceptances", RequirePermission("risks.view"), h.getRiskAcceptances)
+	riskID.Post("/acceptances", RequirePermission("risks.edit"), h.requestRiskAcceptance)
+	riskID.Post("/acceptances/:acceptance_id/approve", RequirePermission("risks.accept"), h.approveRiskAcceptance)
+	riskID.Post("/acceptances/:acceptance_id/reject", RequirePermission("risks.accept"), h.rejectRiskAcceptance)
+	riskID.Post("/acceptances/:acceptance_id/revoke", RequirePermission("risks.edit"), h.revokeRiskAcceptance)
+
+	// Reviews
+	riskID.Get("/reviews", RequirePermission("risks.view"), h.getRiskReviews)
+	riskID.Post("/reviews", RequirePermission("risks.edit"), h.reviewRisk)
+
+	// Key risk indicators
+	riskID.Get("/kris", RequirePermission("risks.view"), h.getKRIs)
+
+	// Quantitative analysis (FAIR)
+	riskID.Get("/quantification", RequirePermission("risks.view"), h.getRiskQuantification)
+	riskID.Put("/quantification", RequirePermission("risks.edit"), h.setRiskQuantification)
+	riskID.Delete("/quantification", RequirePermission("risks.edit"), h.deleteRiskQuantification)
+	riskID.Get("/quantification/simulations", RequirePermission("risks.view"), h.getRiskSimulations)
+	riskID.Post("/quantification/simulations", RequirePermission("risks.edit"), h.runRiskSimulation)
 }
 
 // convertToRiskResponse - преобразует Risk в RiskResponse с автоматическим расчетом уровня
@@ -78,24 +145,42 @@ func (h *RiskHandler) convertToRiskResponse(risk *repo.Risk) dto.RiskResponse {
 		levelLabel = &label
 	}
 
+	var appetiteStatus *string
+	if status := domain.RiskAppetiteStatus(risk); status != "" {
+		appetiteStatus = &status
+	}
+
 	return dto.RiskResponse{
-		ID:          risk.ID,
-		TenantID:    risk.TenantID,
-		Title:       risk.Title,
-		Description: risk.Description,
-		Category:    risk.Category,
-		Likelihood:  risk.Likelihood,
-		Impact:      risk.Impact,
-		Level:       risk.Level,
-		Status:      risk.Status,
-		OwnerUserID: risk.OwnerUserID,
-		AssetID:     risk.AssetID,
-		Methodology: risk.Methodology,
-		Strategy:    risk.Strategy,
-		DueDate:     risk.DueDate,
-		CreatedAt:   risk.CreatedAt,
-		UpdatedAt:   risk.UpdatedAt,
-		LevelLabel:  levelLabel,
+		ID:                risk.ID,
+		TenantID:          risk.TenantID,
+		Title:             risk.Title,
+		Description:       risk.Description,
+		Category:          risk.Category,
+		Likelihood:        risk.Likelihood,
+		Impact:            risk.Impact,
+		Level:             risk.Level,
+		Status:            risk.Status,
+		OwnerUserID:       risk.OwnerUserID,
+		AssetID:           risk.AssetID,
+		ThreatID:          risk.ThreatID,
+		VulnerabilityID:   risk.VulnerabilityID,
+		Methodology:       risk.Methodology,
+		Strategy:          risk.Strategy,
+		DueDate:           risk.DueDate,
+		CreatedAt:         risk.CreatedAt,
+		UpdatedAt:         risk.UpdatedAt,
+		LevelLabel:        levelLabel,
+		TreatmentProgress: risk.TreatmentProgress,
+		NextReviewDate:    risk.NextReviewDate,
+		LastReviewedAt:    risk.LastReviewedAt,
+		LastReviewedBy:    risk.LastReviewedBy,
+		IsReviewOverdue:   risk.NextReviewDate != nil && risk.Status != dto.RiskStatusClosed && risk.NextReviewDate.Before(time.Now().UTC().Truncate(24*time.Hour)),
+		ALEMean:           risk.ALEMean,
+		ALEP90:            risk.ALEP90,
+		QuantifiedAt:      risk.QuantifiedAt,
+		AppetiteLevel:     risk.AppetiteLevel,
+		ToleranceLevel:    risk.ToleranceLevel,
+		AppetiteStatus:    appetiteStatus,
 	}
 }
 
@@ -230,10 +315,10 @@ func (h *RiskHandler) createRisk(c *fiber.Ctx) error {
 		dueDate = &parsed
 	}
 
-	risk, err := h.riskService.CreateRisk(c.Context(), tenantID, req.Title, req.Description, req.Category, req.Likelihood, req.Impact, req.OwnerUserID, req.AssetID, req.Methodology, req.Strategy, dueDate)
+	risk, err := h.riskService.CreateRisk(c.Context(), tenantID, req.Title, req.Description, req.Category, req.Likelihood, req.Impact, req.OwnerUserID, req.AssetID, req.ThreatID, req.VulnerabilityID, req.Methodology, req.Strategy, dueDate)
 	if err != nil {
 		log.Printf("ERROR: RiskHandler.createRisk service error: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return riskErrorResponse(c, err)
 	}
 
 	log.Printf("DEBUG: RiskHandler.createRisk success id=%s", risk.ID)
@@ -315,16 +400,42 @@ func (h *RiskHandler) updateRisk(c *fiber.Ctx) error {
 		dueDate = &parsed
 	}
 
-	err = h.riskService.UpdateRisk(c.Context(), id, title, req.Description, req.Category, likelihood, impact, req.OwnerUserID, req.AssetID, req.Methodology, req.Strategy, dueDate)
+	err = h.riskService.UpdateRisk(c.Context(), id, title, req.Description, req.Category, likelihood, impact, req.OwnerUserID, req.AssetID, req.ThreatID, req.VulnerabilityID, req.Methodology, req.Strategy, dueDate, userID)
 	if err != nil {
 		log.Printf("ERROR: RiskHandler.updateRisk service error: %v", err)
-		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		return riskErrorResponse(c, err)
 	}
 
 	log.Printf("DEBUG: RiskHandler.updateRisk success id=%s", id)
 	return c.Status(200).JSON(fiber.Map{"message": "Risk updated successfully"})
 }
 
+func (h *RiskHandler) updateRiskStatus(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.updateRiskStatus riskID=%s user=%s", riskID, userID)
+
+	var req dto.RiskStatusUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateRiskStatus invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateRiskStatus validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	if err := h.riskService.UpdateRiskStatus(c.Context(), riskID, tenantID, req.Status, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.updateRiskStatus service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"message": "Risk status updated successfully"})
+}
+
 func (h *RiskHandler) deleteRisk(c *fiber.Ctx) error {
 	id := c.Params("id")
 	userID := c.Locals("user_id").(string)
@@ -352,7 +463,7 @@ func (h *RiskHandler) getRisksByAsset(c *fiber.Ctx) error {
 	filters := map[string]interface{}{
 		"asset_id": assetID,
 	}
-	
+
 	risks, err := h.riskService.ListRisks(c.Context(), tenantID, filters, "created_at", "desc")
 	if err != nil {
 		log.Printf("ERROR: RiskHandler.getRisksByAsset service error: %v", err)
@@ -396,6 +507,7 @@ func (h *RiskHandler) getRiskHistory(c *fiber.Ctx) error {
 			ChangedBy:     h.ChangedBy,
 			ChangedAt:     h.ChangedAt,
 			ChangedByName: h.ChangedByName,
+			IsSystem:      h.IsSystem,
 		})
 	}
 
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+
+	"risknexus/backend/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxImportFileSize limits uploaded import files to 10 MB
+const maxImportFileSize = 10 << 20
+
+// Risk Import endpoints
+func (h *RiskHandler) previewRiskImport(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	filename, data, mapping, err := readImportForm(c)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.previewRiskImport invalid form: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.previewRiskImport file=%s size=%d user=%s", filename, len(data), userID)
+
+	preview, err := h.riskService.PreviewRiskImport(c.Context(), tenantID, filename, data, mapping)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.previewRiskImport service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": preview})
+}
+
+func (h *RiskHandler) importRisks(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	filename, data, mapping, err := readImportForm(c)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.importRisks invalid form: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	skipInvalid := c.FormValue("skip_invalid") == "true"
+
+	log.Printf("DEBUG: RiskHandler.importRisks file=%s size=%d skip_invalid=%t user=%s", filename, len(data), skipInvalid, userID)
+
+	result, preview, err := h.riskService.ImportRisks(c.Context(), tenantID, filename, data, mapping, skipInvalid, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrRiskImportInvalidRows) {
+			return c.Status(422).JSON(fiber.Map{"error": err.Error(), "data": preview})
+		}
+		log.Printf("ERROR: RiskHandler.importRisks service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	log.Printf("DEBUG: RiskHandler.importRisks imported=%d skipped=%d", result.Imported, result.Skipped)
+	return c.Status(201).JSON(fiber.Map{"data": result})
+}
+
+// readImportForm reads the uploaded file and the optional JSON column mapping (field -> column header)
+func readImportForm(c *fiber.Ctx) (string, []byte, map[string]string, error) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return "", nil, nil, errors.New("No file provided")
+	}
+	if file.Size > maxImportFileSize {
+		return "", nil, nil, errors.New("File is too large, maximum size is 10 MB")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", nil, nil, errors.New("Failed to open file")
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return "", nil, nil, errors.New("Failed to read file")
+	}
+
+	var mapping map[string]string
+	if raw := c.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			return "", nil, nil, errors.New("Invalid mapping, expected a JSON object of field to column header")
+		}
+	}
+
+	return file.Filename, data, mapping, nil
+}
@@ -0,0 +1,246 @@
+package http
+
+import (
+	"log"
+	"time"
+
+	"risknexus/backend/internal/domain"
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Risk KRI endpoints
+func (h *RiskHandler) getKRIs(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	kris, err := h.riskService.GetKRIs(c.Context(), tenantID, c.Params("risk_id"))
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getKRIs service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	now := time.Now()
+	responses := make([]dto.RiskKRIResponse, 0, len(kris))
+	for i := range kris {
+		responses = append(responses, convertToKRIResponse(&kris[i], now))
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) getKRI(c *fiber.Ctx) error {
+	kriID := c.Params("kri_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	kri, err := h.riskService.GetKRI(c.Context(), kriID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getKRI service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToKRIResponse(kri, time.Now())})
+}
+
+func (h *RiskHandler) createKRI(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskKRIRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.createKRI invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.createKRI validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.createKRI code=%s user=%s", req.Code, userID)
+
+	kri, err := h.riskService.CreateKRI(c.Context(), tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.createKRI service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": convertToKRIResponse(kri, time.Now())})
+}
+
+func (h *RiskHandler) updateKRI(c *fiber.Ctx) error {
+	kriID := c.Params("kri_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskKRIRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateKRI invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateKRI validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.updateKRI kriID=%s user=%s", kriID, userID)
+
+	kri, err := h.riskService.UpdateKRI(c.Context(), kriID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.updateKRI service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToKRIResponse(kri, time.Now())})
+}
+
+func (h *RiskHandler) deleteKRI(c *fiber.Ctx) error {
+	kriID := c.Params("kri_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.deleteKRI kriID=%s user=%s", kriID, userID)
+
+	if err := h.riskService.DeleteKRI(c.Context(), kriID, tenantID, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.deleteKRI service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "KRI deleted successfully"})
+}
+
+// Risk KRI Measurements endpoints
+func (h *RiskHandler) getKRIMeasurements(c *fiber.Ctx) error {
+	kriID := c.Params("kri_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	to := time.Now()
+	from := to.AddDate(-1, 0, 0)
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid from format. Use YYYY-MM-DD"})
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid to format. Use YYYY-MM-DD"})
+		}
+		to = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	measurements, err := h.riskService.GetKRIMeasurements(c.Context(), kriID, tenantID, from, to)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getKRIMeasurements service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	responses := make([]dto.RiskKRIMeasurementResponse, 0, len(measurements))
+	for i := range measurements {
+		responses = append(responses, convertToKRIMeasurementResponse(&measurements[i]))
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) addKRIMeasurement(c *fiber.Ctx) error {
+	kriID := c.Params("kri_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskKRIMeasurementRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.addKRIMeasurement invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.addKRIMeasurement validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	measurement, err := h.riskService.AddKRIMeasurement(c.Context(), kriID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.addKRIMeasurement service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	log.Printf("DEBUG: RiskHandler.addKRIMeasurement kriID=%s status=%s", kriID, measurement.Status)
+	return c.Status(201).JSON(fiber.Map{"data": convertToKRIMeasurementResponse(measurement)})
+}
+
+// pushKRIMeasurements accepts a batch of measurements from external systems
+func (h *RiskHandler) pushKRIMeasurements(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskKRIPushRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.pushKRIMeasurements invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.pushKRIMeasurements validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	results := h.riskService.PushKRIMeasurements(c.Context(), tenantID, req, userID)
+
+	accepted := 0
+	for _, result := range results {
+		if result.Error == nil {
+			accepted++
+		}
+	}
+	log.Printf("DEBUG: RiskHandler.pushKRIMeasurements accepted=%d of %d user=%s", accepted, len(results), userID)
+
+	return c.JSON(fiber.Map{"data": results, "accepted": accepted, "rejected": len(results) - accepted})
+}
+
+func convertToKRIResponse(kri *repo.RiskKRI, now time.Time) dto.RiskKRIResponse {
+	riskIDs := kri.RiskIDs
+	if riskIDs == nil {
+		riskIDs = []string{}
+	}
+
+	return dto.RiskKRIResponse{
+		ID:             kri.ID,
+		Code:           kri.Code,
+		Name:           kri.Name,
+		Description:    kri.Description,
+		Unit:           kri.Unit,
+		Direction:      kri.Direction,
+		AmberThreshold: kri.AmberThreshold,
+		RedThreshold:   kri.RedThreshold,
+		Frequency:      kri.Frequency,
+		OwnerUserID:    kri.OwnerUserID,
+		OwnerName:      kri.OwnerName,
+		IsActive:       kri.IsActive,
+		CurrentValue:   kri.CurrentValue,
+		CurrentStatus:  kri.CurrentStatus,
+		LastMeasuredAt: kri.LastMeasuredAt,
+		IsStale:        domain.IsKRIStale(kri, now),
+		RiskIDs:        riskIDs,
+		CreatedAt:      kri.CreatedAt,
+		UpdatedAt:      kri.UpdatedAt,
+	}
+}
+
+func convertToKRIMeasurementResponse(measurement *repo.RiskKRIMeasurement) dto.RiskKRIMeasurementResponse {
+	return dto.RiskKRIMeasurementResponse{
+		ID:         measurement.ID,
+		KRIID:      measurement.KRIID,
+		Value:      measurement.Value,
+		Status:     measurement.Status,
+		MeasuredAt: measurement.MeasuredAt,
+		Source:     measurement.Source,
+		Comment:    measurement.Comment,
+		RecordedBy: measurement.RecordedBy,
+		CreatedAt:  measurement.CreatedAt,
+	}
+}
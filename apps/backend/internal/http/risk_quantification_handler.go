@@ -0,0 +1,181 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Risk Quantification endpoints
+func (h *RiskHandler) getRiskQuantification(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	quantification, err := h.riskService.GetQuantification(c.Context(), riskID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskQuantification service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	simulations, err := h.riskService.GetSimulations(c.Context(), riskID, tenantID, 1)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskQuantification simulations error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	response := convertToQuantificationResponse(quantification)
+	if len(simulations) > 0 {
+		latest := convertToSimulationResponse(&simulations[0])
+		response.LatestSimulation = &latest
+	}
+
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *RiskHandler) setRiskQuantification(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskQuantificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.setRiskQuantification invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.setRiskQuantification validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.setRiskQuantification riskID=%s user=%s", riskID, userID)
+
+	quantification, err := h.riskService.SetQuantification(c.Context(), riskID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.setRiskQuantification service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToQuantificationResponse(quantification)})
+}
+
+func (h *RiskHandler) deleteRiskQuantification(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.deleteRiskQuantification riskID=%s user=%s", riskID, userID)
+
+	if err := h.riskService.DeleteQuantification(c.Context(), riskID, tenantID, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.deleteRiskQuantification service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Quantification deleted successfully"})
+}
+
+// Risk Simulations endpoints
+func (h *RiskHandler) getRiskSimulations(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 || limit > 100 {
+		return c.Status(400).JSON(fiber.Map{"error": "limit must be between 1 and 100"})
+	}
+
+	simulations, err := h.riskService.GetSimulations(c.Context(), riskID, tenantID, limit)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskSimulations service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	responses := make([]dto.RiskSimulationResponse, 0, len(simulations))
+	for i := range simulations {
+		responses = append(responses, convertToSimulationResponse(&simulations[i]))
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) runRiskSimulation(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskSimulationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			log.Printf("ERROR: RiskHandler.runRiskSimulation invalid body: %v", err)
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.runRiskSimulation validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	simulation, err := h.riskService.RunSimulation(c.Context(), riskID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.runRiskSimulation service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	log.Printf("DEBUG: RiskHandler.runRiskSimulation riskID=%s iterations=%d mean=%.2f", riskID, simulation.Iterations, simulation.MeanLoss)
+	return c.Status(201).JSON(fiber.Map{"data": convertToSimulationResponse(simulation)})
+}
+
+func convertToQuantificationResponse(q *repo.RiskQuantification) dto.RiskQuantificationResponse {
+	return dto.RiskQuantificationResponse{
+		RiskID:        q.RiskID,
+		LEFMin:        q.LEFMin,
+		LEFMostLikely: q.LEFMostLikely,
+		LEFMax:        q.LEFMax,
+		LMMin:         q.LMMin,
+		LMMostLikely:  q.LMMostLikely,
+		LMMax:         q.LMMax,
+		Currency:      q.Currency,
+		UpdatedBy:     q.UpdatedBy,
+		UpdatedAt:     q.UpdatedAt,
+	}
+}
+
+func convertToSimulationResponse(sim *repo.RiskSimulation) dto.RiskSimulationResponse {
+	curve := make([]dto.LossExceedancePointResponse, 0, len(sim.ExceedanceCurve))
+	for _, point := range sim.ExceedanceCurve {
+		curve = append(curve, dto.LossExceedancePointResponse{Loss: point.Loss, Probability: point.Probability})
+	}
+
+	return dto.RiskSimulationResponse{
+		ID:         sim.ID,
+		RiskID:     sim.RiskID,
+		Iterations: sim.Iterations,
+		Seed:       sim.Seed,
+		Currency:   sim.Currency,
+		MeanLoss:   sim.MeanLoss,
+		Percentiles: map[string]float64{
+			"p10": sim.P10,
+			"p50": sim.P50,
+			"p90": sim.P90,
+			"p95": sim.P95,
+			"p99": sim.P99,
+		},
+		MaxLoss:         sim.MaxLoss,
+		LossProbability: sim.LossProbability,
+		ExceedanceCurve: curve,
+		Inputs: map[string]float64{
+			"lef_min":         sim.Inputs.LEFMin,
+			"lef_most_likely": sim.Inputs.LEFMostLikely,
+			"lef_max":         sim.Inputs.LEFMax,
+			"lm_min":          sim.Inputs.LMMin,
+			"lm_most_likely":  sim.Inputs.LMMostLikely,
+			"lm_max":          sim.Inputs.LMMax,
+		},
+		CreatedBy: sim.CreatedBy,
+		CreatedAt: sim.CreatedAt,
+	}
+}
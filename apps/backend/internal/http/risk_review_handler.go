@@ -0,0 +1,173 @@
+package http
+
+import (
+	"log"
+
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Risk Review Intervals endpoints
+func (h *RiskHandler) getReviewIntervals(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+
+	intervals, err := h.riskService.GetReviewIntervals(c.Context(), tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getReviewIntervals service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]dto.RiskReviewIntervalResponse, 0, len(intervals))
+	for _, interval := range intervals {
+		response := dto.RiskReviewIntervalResponse{
+			Scope:        interval.Scope,
+			ScopeValue:   interval.ScopeValue,
+			IntervalDays: interval.IntervalDays,
+			IsDefault:    interval.ID == "",
+		}
+		if interval.ID != "" {
+			id := interval.ID
+			response.ID = &id
+		}
+		responses = append(responses, response)
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) setReviewInterval(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskReviewIntervalRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.setReviewInterval invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.setReviewInterval validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.setReviewInterval scope=%s value=%s days=%d user=%s", req.Scope, req.ScopeValue, req.IntervalDays, userID)
+
+	if err := h.riskService.SetReviewInterval(c.Context(), tenantID, req, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.setReviewInterval service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return h.getReviewIntervals(c)
+}
+
+func (h *RiskHandler) deleteReviewInterval(c *fiber.Ctx) error {
+	intervalID := c.Params("interval_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.deleteReviewInterval intervalID=%s user=%s", intervalID, userID)
+
+	if err := h.riskService.DeleteReviewInterval(c.Context(), tenantID, intervalID, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.deleteReviewInterval service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Review interval deleted successfully"})
+}
+
+// Risk Review Queue endpoint
+func (h *RiskHandler) getReviewQueue(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	daysAhead := c.QueryInt("days_ahead", 0)
+	if daysAhead < 0 || daysAhead > 365 {
+		return c.Status(400).JSON(fiber.Map{"error": "days_ahead must be between 0 and 365"})
+	}
+
+	risks, err := h.riskService.GetReviewQueue(c.Context(), tenantID, daysAhead)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getReviewQueue service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	responses := make([]dto.RiskResponse, 0, len(risks))
+	for i := range risks {
+		responses = append(responses, h.convertToRiskResponse(&risks[i]))
+	}
+
+	return c.JSON(fiber.Map{"data": responses, "total": len(responses)})
+}
+
+// Risk Reviews endpoints
+func (h *RiskHandler) getRiskReviews(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	reviews, err := h.riskService.GetReviews(c.Context(), riskID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getRiskReviews service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	responses := make([]dto.RiskReviewResponse, 0, len(reviews))
+	for i := range reviews {
+		responses = append(responses, convertToRiskReviewResponse(&reviews[i]))
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) reviewRisk(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.reviewRisk riskID=%s user=%s", riskID, userID)
+
+	var req dto.RiskReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.reviewRisk invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.reviewRisk validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	risk, err := h.riskService.ReviewRisk(c.Context(), riskID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.reviewRisk service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	log.Printf("DEBUG: RiskHandler.reviewRisk success riskID=%s next=%v", riskID, risk.NextReviewDate)
+	return c.JSON(fiber.Map{"data": h.convertToRiskResponse(risk)})
+}
+
+func convertToRiskReviewResponse(entry *repo.RiskHistory) dto.RiskReviewResponse {
+	outcome := dto.RiskReviewOutcomeConfirmed
+	if !sameOptionalString(entry.OldValue, entry.NewValue) {
+		outcome = dto.RiskReviewOutcomeUpdated
+	}
+
+	return dto.RiskReviewResponse{
+		ID:             entry.ID,
+		RiskID:         entry.RiskID,
+		Outcome:        outcome,
+		OldAssessment:  entry.OldValue,
+		NewAssessment:  entry.NewValue,
+		Comment:        entry.ChangeReason,
+		ReviewedBy:     entry.ChangedBy,
+		ReviewedByName: entry.ChangedByName,
+		ReviewedAt:     entry.ChangedAt,
+	}
+}
+
+func sameOptionalString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
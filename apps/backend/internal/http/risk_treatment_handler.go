@@ -0,0 +1,377 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"risknexus/backend/internal/domain"
+	"risknexus/backend/internal/dto"
+	"risknexus/backend/internal/repo"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Risk Treatment Plans endpoints
+func (h *RiskHandler) getTreatmentPlans(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.getTreatmentPlans riskID=%s tenant=%s", riskID, tenantID)
+
+	plans, err := h.riskService.GetTreatmentPlans(c.Context(), riskID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getTreatmentPlans service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	responses := make([]dto.RiskTreatmentPlanResponse, 0, len(plans))
+	for i := range plans {
+		response, err := h.buildTreatmentPlanResponse(c, &plans[i])
+		if err != nil {
+			log.Printf("ERROR: RiskHandler.getTreatmentPlans tasks error: %v", err)
+			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+		}
+		responses = append(responses, response)
+	}
+
+	return c.JSON(fiber.Map{"data": responses})
+}
+
+func (h *RiskHandler) getTreatmentPlan(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	tenantID := c.Locals("tenant_id").(string)
+
+	plan, err := h.riskService.GetTreatmentPlan(c.Context(), riskID, planID, tenantID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getTreatmentPlan service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	response, err := h.buildTreatmentPlanResponse(c, plan)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.getTreatmentPlan tasks error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *RiskHandler) createTreatmentPlan(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.createTreatmentPlan riskID=%s user=%s", riskID, userID)
+
+	var req dto.RiskTreatmentPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.createTreatmentPlan invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.createTreatmentPlan validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	plan, err := h.riskService.CreateTreatmentPlan(c.Context(), riskID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.createTreatmentPlan service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	response, err := h.buildTreatmentPlanResponse(c, plan)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	log.Printf("DEBUG: RiskHandler.createTreatmentPlan success planID=%s", plan.ID)
+	return c.Status(201).JSON(fiber.Map{"data": response})
+}
+
+func (h *RiskHandler) updateTreatmentPlan(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.updateTreatmentPlan riskID=%s planID=%s user=%s", riskID, planID, userID)
+
+	var req dto.RiskTreatmentPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentPlan invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentPlan validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	plan, err := h.riskService.UpdateTreatmentPlan(c.Context(), riskID, planID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentPlan service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	response, err := h.buildTreatmentPlanResponse(c, plan)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": response})
+}
+
+func (h *RiskHandler) deleteTreatmentPlan(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.deleteTreatmentPlan riskID=%s planID=%s user=%s", riskID, planID, userID)
+
+	if err := h.riskService.DeleteTreatmentPlan(c.Context(), riskID, planID, tenantID, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.deleteTreatmentPlan service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Treatment plan deleted successfully"})
+}
+
+// Risk Treatment Tasks endpoints
+func (h *RiskHandler) addTreatmentTask(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.addTreatmentTask riskID=%s planID=%s user=%s", riskID, planID, userID)
+
+	var req dto.RiskTreatmentTaskRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.addTreatmentTask invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.addTreatmentTask validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	task, err := h.riskService.AddTreatmentTask(c.Context(), riskID, planID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.addTreatmentTask service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(201).JSON(fiber.Map{"data": convertToTreatmentTaskResponse(task)})
+}
+
+func (h *RiskHandler) updateTreatmentTask(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	taskID := c.Params("task_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.updateTreatmentTask taskID=%s user=%s", taskID, userID)
+
+	var req dto.RiskTreatmentTaskRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentTask invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentTask validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	task, err := h.riskService.UpdateTreatmentTask(c.Context(), riskID, planID, taskID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentTask service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToTreatmentTaskResponse(task)})
+}
+
+func (h *RiskHandler) updateTreatmentTaskStatus(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	taskID := c.Params("task_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.updateTreatmentTaskStatus taskID=%s user=%s", taskID, userID)
+
+	var req dto.RiskTreatmentTaskStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentTaskStatus invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentTaskStatus validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	task, err := h.riskService.UpdateTreatmentTaskStatus(c.Context(), riskID, planID, taskID, tenantID, req, userID)
+	if err != nil {
+		log.Printf("ERROR: RiskHandler.updateTreatmentTaskStatus service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.JSON(fiber.Map{"data": convertToTreatmentTaskResponse(task)})
+}
+
+func (h *RiskHandler) deleteTreatmentTask(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	taskID := c.Params("task_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	log.Printf("DEBUG: RiskHandler.deleteTreatmentTask taskID=%s user=%s", taskID, userID)
+
+	if err := h.riskService.DeleteTreatmentTask(c.Context(), riskID, planID, taskID, tenantID, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.deleteTreatmentTask service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Treatment task deleted successfully"})
+}
+
+func (h *RiskHandler) reorderTreatmentTasks(c *fiber.Ctx) error {
+	riskID := c.Params("risk_id")
+	planID := c.Params("plan_id")
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	var req dto.RiskTreatmentTaskOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		log.Printf("ERROR: RiskHandler.reorderTreatmentTasks invalid body: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		log.Printf("ERROR: RiskHandler.reorderTreatmentTasks validation failed: %v", err)
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	if err := h.riskService.ReorderTreatmentTasks(c.Context(), riskID, planID, tenantID, req.TaskIDs, userID); err != nil {
+		log.Printf("ERROR: RiskHandler.reorderTreatmentTasks service error: %v", err)
+		return riskErrorResponse(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "Treatment tasks reordered successfully"})
+}
+
+// buildTreatmentPlanResponse loads the plan's tasks and computes the budget and task totals
+func (h *RiskHandler) buildTreatmentPlanResponse(c *fiber.Ctx, plan *repo.RiskTreatmentPlan) (dto.RiskTreatmentPlanResponse, error) {
+	tenantID := c.Locals("tenant_id").(string)
+	tasks, err := h.riskService.GetTreatmentTasks(c.Context(), plan.RiskID, plan.ID, tenantID)
+	if err != nil {
+		return dto.RiskTreatmentPlanResponse{}, err
+	}
+
+	response := dto.RiskTreatmentPlanResponse{
+		ID:          plan.ID,
+		RiskID:      plan.RiskID,
+		Title:       plan.Title,
+		Description: plan.Description,
+		Strategy:    plan.Strategy,
+		Status:      plan.Status,
+		OwnerUserID: plan.OwnerUserID,
+		OwnerName:   plan.OwnerName,
+		Budget:      plan.Budget,
+		Currency:    plan.Currency,
+		StartDate:   plan.StartDate,
+		DueDate:     plan.DueDate,
+		Progress:    plan.Progress,
+		CompletedAt: plan.CompletedAt,
+		CreatedBy:   plan.CreatedBy,
+		CreatedAt:   plan.CreatedAt,
+		UpdatedAt:   plan.UpdatedAt,
+		Tasks:       make([]dto.RiskTreatmentTaskResponse, 0, len(tasks)),
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Status != dto.TreatmentTaskStatusCancelled {
+			response.TasksTotal++
+		}
+		if task.Status == dto.TreatmentTaskStatusDone {
+			response.TasksDone++
+			if task.Cost != nil {
+				response.Spent += *task.Cost
+			}
+		}
+		response.Tasks = append(response.Tasks, convertToTreatmentTaskResponse(task))
+	}
+
+	return response, nil
+}
+
+func convertToTreatmentTaskResponse(task *repo.RiskTreatmentTask) dto.RiskTreatmentTaskResponse {
+	isOpen := task.Status == dto.TreatmentTaskStatusOpen || task.Status == dto.TreatmentTaskStatusInProgress
+	return dto.RiskTreatmentTaskResponse{
+		ID:                 task.ID,
+		PlanID:             task.PlanID,
+		Position:           task.Position,
+		Title:              task.Title,
+		Description:        task.Description,
+		AssigneeUserID:     task.AssigneeUserID,
+		AssigneeName:       task.AssigneeName,
+		DueDate:            task.DueDate,
+		Status:             task.Status,
+		Cost:               task.Cost,
+		Evidence:           task.Evidence,
+		EvidenceDocumentID: task.EvidenceDocumentID,
+		CompletedBy:        task.CompletedBy,
+		CompletedAt:        task.CompletedAt,
+		IsOverdue:          isOpen && task.DueDate != nil && task.DueDate.Before(time.Now().Truncate(24*time.Hour)),
+		CreatedAt:          task.CreatedAt,
+		UpdatedAt:          task.UpdatedAt,
+	}
+}
+
+// riskErrorResponse maps risk domain errors to HTTP status codes
+func riskErrorResponse(c *fiber.Ctx, err error) error {
+	var validationErr domain.ValidationError
+	switch {
+	case errors.Is(err, domain.ErrRiskNotFound),
+		errors.Is(err, domain.ErrTreatmentPlanNotFound),
+		errors.Is(err, domain.ErrTreatmentTaskNotFound),
+		errors.Is(err, domain.ErrRiskAcceptanceNotFound),
+		errors.Is(err, domain.ErrReviewIntervalNotFound),
+		errors.Is(err, domain.ErrKRINotFound),
+		errors.Is(err, domain.ErrRiskQuantificationNotFound),
+		errors.Is(err, domain.ErrCatalogEntryNotFound),
+		errors.Is(err, domain.ErrRiskAppetiteNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrRiskAcceptanceSelfApproval),
+		errors.Is(err, domain.ErrRiskAcceptanceApproverRole),
+		errors.Is(err, domain.ErrCatalogEntryBuiltin):
+		return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrTreatmentPlanClosed),
+		errors.Is(err, domain.ErrRiskStrategyNotAccept),
+		errors.Is(err, domain.ErrRiskAcceptancePending),
+		errors.Is(err, domain.ErrRiskAcceptanceNotPending),
+		errors.Is(err, domain.ErrRiskAcceptanceNotApproved),
+		errors.Is(err, domain.ErrRiskAcceptanceRequired),
+		errors.Is(err, domain.ErrKRICodeExists),
+		errors.Is(err, domain.ErrKRIInactive),
+		errors.Is(err, domain.ErrCatalogEntryCodeExists),
+		errors.Is(err, domain.ErrRiskOutOfTolerance):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrTreatmentEvidenceRequired):
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &validationErr):
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	default:
+		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"risknexus/backend/internal/domain"
+	"risknexus/backend/internal/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserNotificationHandler struct {
+	notificationService *domain.UserNotificationService
+}
+
+func NewUserNotificationHandler(notificationService *domain.UserNotificationService) *UserNotificationHandler {
+	return &UserNotificationHandler{notificationService: notificationService}
+}
+
+// Register - входящие уведомления доступны каждому пользователю только для себя, отдельное право не требуется
+func (h *UserNotificationHandler) Register(r fiber.Router) {
+	notifications := r.Group("/notifications")
+	notifications.Get("/", h.listNotifications)
+	notifications.Post("/read-all", h.markAllRead)
+	notifications.Post("/:id/read", h.markRead)
+}
+
+func (h *UserNotificationHandler) listNotifications(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(dto.UserNotificationDefaultLimit)))
+	if err != nil || limit <= 0 || limit > 500 {
+		limit = dto.UserNotificationDefaultLimit
+	}
+	unreadOnly := c.QueryBool("unread_only", false)
+
+	notifications, unread, err := h.notificationService.ListNotifications(c.Context(), userID, tenantID, unreadOnly, limit)
+	if err != nil {
+		log.Printf("ERROR: UserNotificationHandler.listNotifications service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to list notifications"})
+	}
+
+	response := make([]dto.UserNotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		response = append(response, dto.UserNotificationResponse{
+			ID:        n.ID,
+			Type:      n.Type,
+			Title:     n.Title,
+			Message:   n.Message,
+			Entity:    n.Entity,
+			EntityID:  n.EntityID,
+			IsRead:    n.IsRead,
+			CreatedAt: n.CreatedAt,
+			ReadAt:    n.ReadAt,
+		})
+	}
+	return c.JSON(fiber.Map{"data": response, "unread": unread})
+}
+
+func (h *UserNotificationHandler) markRead(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	if err := h.notificationService.MarkRead(c.Context(), c.Params("id"), userID, tenantID); err != nil {
+		if errors.Is(err, domain.ErrUserNotificationNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Printf("ERROR: UserNotificationHandler.markRead service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update notification"})
+	}
+	return c.JSON(fiber.Map{"message": "Notification marked as read"})
+}
+
+func (h *UserNotificationHandler) markAllRead(c *fiber.Ctx) error {
+	tenantID := c.Locals("tenant_id").(string)
+	userID := c.Locals("user_id").(string)
+
+	if err := h.notificationService.MarkAllRead(c.Context(), userID, tenantID); err != nil {
+		log.Printf("ERROR: UserNotificationHandler.markAllRead service error: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update notifications"})
+	}
+	return c.JSON(fiber.Map{"message": "Notifications marked as read"})
+}
@@ -0,0 +1,315 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/lib/pq"
+)
+
+// AssetDiscoveryAgent is an inventory agent that reports endpoint hardware and software
+type AssetDiscoveryAgent struct {
+	ID                    string     `json:"id"`
+	TenantID              string     `json:"tenant_id"`
+	Name                  string     `json:"name"`
+	TokenHash             string     `json:"-"`
+	TokenPrefix           string     `json:"token_prefix"`
+	ReporterID            string     `json:"reporter_id"`
+	RemoveMissingSoftware bool       `json:"remove_missing_software"`
+	IsActive              bool       `json:"is_active"`
+	LastReportAt          *time.Time `json:"last_report_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+// AssetDiscoveryReport is a received inventory report; pending reports form the reconciliation queue
+type AssetDiscoveryReport struct {
+	ID              string                      `json:"id"`
+	TenantID        string                      `json:"tenant_id"`
+	AgentID         *string                     `json:"agent_id"`
+	Format          string                      `json:"format"`
+	Hostname        *string                     `json:"hostname"`
+	SerialNumber    *string                     `json:"serial_number"`
+	MACAddresses    []string                    `json:"mac_addresses"`
+	IPAddresses     []string                    `json:"ip_addresses"`
+	Inventory       dto.AssetDiscoveryInventory `json:"inventory"`
+	Status          string                      `json:"status"`
+	AssetID         *string                     `json:"asset_id"`
+	MatchedBy       *string                     `json:"matched_by"`
+	CandidateIDs    []string                    `json:"candidate_ids"`
+	ChangedFields   []string                    `json:"changed_fields"`
+	SoftwareAdded   int                         `json:"software_added"`
+	SoftwareUpdated int                         `json:"software_updated"`
+	SoftwareRemoved int                         `json:"software_removed"`
+	ReceivedAt      time.Time                   `json:"received_at"`
+	ResolvedBy      *string                     `json:"resolved_by"`
+	ResolvedAt      *time.Time                  `json:"resolved_at"`
+}
+
+// Discovery agents
+const discoveryAgentColumns = `id, tenant_id, name, token_hash, token_prefix, reporter_id, remove_missing_software,
+	is_active, last_report_at, created_at, updated_at`
+
+func scanDiscoveryAgent(row rowScanner) (*AssetDiscoveryAgent, error) {
+	var agent AssetDiscoveryAgent
+	err := row.Scan(&agent.ID, &agent.TenantID, &agent.Name, &agent.TokenHash, &agent.TokenPrefix,
+		&agent.ReporterID, &agent.RemoveMissingSoftware, &agent.IsActive, &agent.LastReportAt,
+		&agent.CreatedAt, &agent.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+func (r *AssetRepo) ListDiscoveryAgents(ctx context.Context, tenantID string) ([]*AssetDiscoveryAgent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+discoveryAgentColumns+`
+		FROM asset_discovery_agents WHERE tenant_id = $1 ORDER BY name
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var agents []*AssetDiscoveryAgent
+	for rows.Next() {
+		agent, err := scanDiscoveryAgent(rows)
+		if err != nil {
+			return nil, err
+		}
+		agents = append(agents, agent)
+	}
+	return agents, rows.Err()
+}
+
+// GetDiscoveryAgent returns nil if the tenant has no such agent
+func (r *AssetRepo) GetDiscoveryAgent(ctx context.Context, id, tenantID string) (*AssetDiscoveryAgent, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+discoveryAgentColumns+`
+		FROM asset_discovery_agents WHERE id = $1 AND tenant_id = $2
+	`, id, tenantID)
+
+	agent, err := scanDiscoveryAgent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return agent, nil
+}
+
+// GetDiscoveryAgentByTokenHash returns nil if no agent has the token
+func (r *AssetRepo) GetDiscoveryAgentByTokenHash(ctx context.Context, tokenHash string) (*AssetDiscoveryAgent, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+discoveryAgentColumns+`
+		FROM asset_discovery_agents WHERE token_hash = $1
+	`, tokenHash)
+
+	agent, err := scanDiscoveryAgent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return agent, nil
+}
+
+// SaveDiscoveryAgent inserts or updates an agent
+func (r *AssetRepo) SaveDiscoveryAgent(ctx context.Context, agent *AssetDiscoveryAgent) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO asset_discovery_agents (id, tenant_id, name, token_hash, token_prefix, reporter_id,
+		                                    remove_missing_software, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, token_hash = EXCLUDED.token_hash, token_prefix = EXCLUDED.token_prefix,
+		    reporter_id = EXCLUDED.reporter_id, remove_missing_software = EXCLUDED.remove_missing_software,
+		    is_active = EXCLUDED.is_active, updated_at = EXCLUDED.updated_at
+	`, agent.ID, agent.TenantID, agent.Name, agent.TokenHash, agent.TokenPrefix, agent.ReporterID,
+		agent.RemoveMissingSoftware, agent.IsActive, agent.CreatedAt, agent.UpdatedAt)
+	return err
+}
+
+func (r *AssetRepo) DeleteDiscoveryAgent(ctx context.Context, id, tenantID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM asset_discovery_agents WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	return err
+}
+
+func (r *AssetRepo) TouchDiscoveryAgent(ctx context.Context, id string, reportAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE asset_discovery_agents SET last_report_at = $1 WHERE id = $2`, reportAt, id)
+	return err
+}
+
+// FindDiscoveryCandidates returns the tenant assets whose serial number, MAC address, name or PC number
+// matches one of the given values. Serials and hostnames are compared in lower case.
+func (r *AssetRepo) FindDiscoveryCandidates(ctx context.Context, tenantID string, serials, macAddresses, hostnames []string) ([]Asset, error) {
+	if len(serials) == 0 && len(macAddresses) == 0 && len(hostnames) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, inventory_number, name, status, serial_number, pc_number, mac_address::text
+		FROM assets
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		  AND (LOWER(serial_number) = ANY($2) OR mac_address::text = ANY($3)
+		       OR LOWER(name) = ANY($4) OR LOWER(pc_number) = ANY($4))
+		ORDER BY created_at
+	`, tenantID, pq.Array(serials), pq.Array(macAddresses), pq.Array(hostnames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		var asset Asset
+		var serialNumber, pcNumber, macAddress sql.NullString
+		err := rows.Scan(&asset.ID, &asset.TenantID, &asset.InventoryNumber, &asset.Name, &asset.Status,
+			&serialNumber, &pcNumber, &macAddress)
+		if err != nil {
+			return nil, err
+		}
+		if serialNumber.Valid {
+			asset.SerialNumber = &serialNumber.String
+		}
+		if pcNumber.Valid {
+			asset.PCNumber = &pcNumber.String
+		}
+		if macAddress.Valid {
+			asset.MACAddress = &macAddress.String
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
+
+// Software synchronization
+
+func (r *AssetRepo) UpdateSoftware(ctx context.Context, id, version string, installedAt *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE asset_software
+		SET version = $1, installed_at = COALESCE($2, installed_at), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`, version, installedAt, id)
+	return err
+}
+
+func (r *AssetRepo) DeleteSoftware(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM asset_software WHERE id = $1`, id)
+	return err
+}
+
+// Discovery reports
+const discoveryReportColumns = `id, tenant_id, agent_id, format, hostname, serial_number, mac_addresses, ip_addresses,
+	inventory, status, asset_id, matched_by, candidate_ids, changed_fields, software_added, software_updated,
+	software_removed, received_at, resolved_by, resolved_at`
+
+func scanDiscoveryReport(row rowScanner) (*AssetDiscoveryReport, error) {
+	var report AssetDiscoveryReport
+	var inventory []byte
+	err := row.Scan(&report.ID, &report.TenantID, &report.AgentID, &report.Format, &report.Hostname,
+		&report.SerialNumber, pq.Array(&report.MACAddresses), pq.Array(&report.IPAddresses), &inventory,
+		&report.Status, &report.AssetID, &report.MatchedBy, pq.Array(&report.CandidateIDs),
+		pq.Array(&report.ChangedFields), &report.SoftwareAdded, &report.SoftwareUpdated, &report.SoftwareRemoved,
+		&report.ReceivedAt, &report.ResolvedBy, &report.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(inventory, &report.Inventory); err != nil {
+		return nil, fmt.Errorf("invalid inventory of discovery report %s: %w", report.ID, err)
+	}
+	return &report, nil
+}
+
+// SaveDiscoveryReport inserts or updates a report
+func (r *AssetRepo) SaveDiscoveryReport(ctx context.Context, report *AssetDiscoveryReport) error {
+	inventory, err := json.Marshal(report.Inventory)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO asset_discovery_reports (id, tenant_id, agent_id, format, hostname, serial_number, mac_addresses,
+		                                     ip_addresses, inventory, status, asset_id, matched_by, candidate_ids,
+		                                     changed_fields, software_added, software_updated, software_removed,
+		                                     received_at, resolved_by, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		ON CONFLICT (id) DO UPDATE
+		SET status = EXCLUDED.status, asset_id = EXCLUDED.asset_id, matched_by = EXCLUDED.matched_by,
+		    candidate_ids = EXCLUDED.candidate_ids, changed_fields = EXCLUDED.changed_fields,
+		    software_added = EXCLUDED.software_added, software_updated = EXCLUDED.software_updated,
+		    software_removed = EXCLUDED.software_removed, resolved_by = EXCLUDED.resolved_by,
+		    resolved_at = EXCLUDED.resolved_at
+	`, report.ID, report.TenantID, report.AgentID, report.Format, report.Hostname, report.SerialNumber,
+		pq.Array(report.MACAddresses), pq.Array(report.IPAddresses), inventory, report.Status, report.AssetID,
+		report.MatchedBy, pq.Array(report.CandidateIDs), pq.Array(report.ChangedFields), report.SoftwareAdded,
+		report.SoftwareUpdated, report.SoftwareRemoved, report.ReceivedAt, report.ResolvedBy, report.ResolvedAt)
+	return err
+}
+
+// GetDiscoveryReport returns nil if the tenant has no such report
+func (r *AssetRepo) GetDiscoveryReport(ctx context.Context, id, tenantID string) (*AssetDiscoveryReport, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+discoveryReportColumns+`
+		FROM asset_discovery_reports WHERE id = $1 AND tenant_id = $2
+	`, id, tenantID)
+
+	report, err := scanDiscoveryReport(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListDiscoveryReports returns a page of reports, newest first, optionally filtered by status and asset
+func (r *AssetRepo) ListDiscoveryReports(ctx context.Context, tenantID string, filters map[string]interface{}, page, pageSize int) ([]*AssetDiscoveryReport, int64, error) {
+	where := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+	if status, ok := filters["status"].(string); ok && status != "" {
+		args = append(args, status)
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if assetID, ok := filters["asset_id"].(string); ok && assetID != "" {
+		args = append(args, assetID)
+		where = append(where, fmt.Sprintf("asset_id = $%d", len(args)))
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int64
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM asset_discovery_reports WHERE `+whereClause, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, pageSize, (page-1)*pageSize)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM asset_discovery_reports WHERE %s
+		ORDER BY received_at DESC LIMIT $%d OFFSET $%d
+	`, discoveryReportColumns, whereClause, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var reports []*AssetDiscoveryReport
+	for rows.Next() {
+		report, err := scanDiscoveryReport(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, total, rows.Err()
+}
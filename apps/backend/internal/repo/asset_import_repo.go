@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// txStarter is implemented by *DB; AssetRepo is built on DBInterface which has no transactions
+type txStarter interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Asset Import methods
+
+// CreateAssets inserts all assets with their "created" history entries in one transaction;
+// either every asset is created or none. Assets without an inventory number get a generated one.
+func (r *AssetRepo) CreateAssets(ctx context.Context, assets []Asset, createdBy, historyNote string) error {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range assets {
+		asset := &assets[i]
+		if asset.InventoryNumber == "" {
+			asset.InventoryNumber = r.generateInventoryNumber(ctx, asset.TenantID)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO assets (id, tenant_id, inventory_number, name, type, class, owner_id, responsible_user_id, location,
+			                    criticality, confidentiality, integrity, availability, status,
+			                    serial_number, pc_number, model, cpu, ram, hdd_info, network_card, optical_drive,
+			                    ip_address, mac_address, manufacturer, purchase_year, warranty_until, metadata)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28)
+		`, asset.ID, asset.TenantID, asset.InventoryNumber, asset.Name, asset.Type, asset.Class,
+			asset.OwnerID, asset.ResponsibleUserID, asset.Location, asset.Criticality, asset.Confidentiality,
+			asset.Integrity, asset.Availability, asset.Status,
+			asset.SerialNumber, asset.PCNumber, asset.Model, asset.CPU, asset.RAM, asset.HDDInfo,
+			asset.NetworkCard, asset.OpticalDrive, asset.IPAddress, asset.MACAddress, asset.Manufacturer,
+			asset.PurchaseYear, asset.WarrantyUntil, asset.Metadata); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO asset_history (id, asset_id, field_changed, old_value, new_value, changed_by)
+			VALUES ($1, $2, 'created', '', $3, $4)
+		`, uuid.New().String(), asset.ID, historyNote, createdBy); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ResolveUserIDsByEmail returns active tenant users keyed by lowercased email
+func (r *AssetRepo) ResolveUserIDsByEmail(ctx context.Context, tenantID string, emails []string) (map[string]string, error) {
+	return resolveUserIDsByEmail(ctx, r.db, tenantID, emails)
+}
+
+// FindImportDuplicates returns non-deleted tenant assets that share an inventory number, serial number,
+// MAC address or IP address with the imported rows. Inventory and serial numbers are compared uppercased.
+// Only identification fields are filled in.
+func (r *AssetRepo) FindImportDuplicates(ctx context.Context, tenantID string, inventoryNumbers, serials, macAddresses, ipAddresses []string) ([]Asset, error) {
+	if len(inventoryNumbers) == 0 && len(serials) == 0 && len(macAddresses) == 0 && len(ipAddresses) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, inventory_number, name, serial_number, mac_address::text, host(ip_address)
+		FROM assets
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		  AND (UPPER(inventory_number) = ANY($2) OR UPPER(serial_number) = ANY($3)
+		       OR mac_address::text = ANY($4) OR host(ip_address) = ANY($5))
+		ORDER BY created_at
+	`, tenantID, pq.Array(inventoryNumbers), pq.Array(serials), pq.Array(macAddresses), pq.Array(ipAddresses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		var asset Asset
+		var serialNumber, macAddress, ipAddress sql.NullString
+		err := rows.Scan(&asset.ID, &asset.TenantID, &asset.InventoryNumber, &asset.Name,
+			&serialNumber, &macAddress, &ipAddress)
+		if err != nil {
+			return nil, err
+		}
+		if serialNumber.Valid {
+			asset.SerialNumber = &serialNumber.String
+		}
+		if macAddress.Valid {
+			asset.MACAddress = &macAddress.String
+		}
+		if ipAddress.Valid {
+			asset.IPAddress = &ipAddress.String
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
@@ -0,0 +1,530 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// InventoryCampaign is a planned inventory of the assets within its scope, verified by the assigned auditors
+type InventoryCampaign struct {
+	ID               string     `json:"id"`
+	TenantID         string     `json:"tenant_id"`
+	Name             string     `json:"name"`
+	Description      *string    `json:"description"`
+	Status           string     `json:"status"`
+	ScopeLocations   []string   `json:"scope_locations"`
+	ScopeDepartments []string   `json:"scope_departments"`
+	ScopeTypes       []string   `json:"scope_types"`
+	AuditorIDs       []string   `json:"auditor_ids"`
+	ScheduledStart   time.Time  `json:"scheduled_start"`
+	ScheduledEnd     *time.Time `json:"scheduled_end"`
+	StartedAt        *time.Time `json:"started_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+	SignedBy         *string    `json:"signed_by"`
+	SignedAt         *time.Time `json:"signed_at"`
+	SignComment      *string    `json:"sign_comment"`
+	ReportDocumentID *string    `json:"report_document_id"`
+	ReportSHA256     *string    `json:"report_sha256"`
+	CreatedBy        string     `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// InventoryItem is a position of the inventory list: an expected asset of the scope or an unexpected scan
+type InventoryItem struct {
+	ID                string     `json:"id"`
+	CampaignID        string     `json:"campaign_id"`
+	AssetID           *string    `json:"asset_id"`
+	InventoryNumber   string     `json:"inventory_number"`
+	AssetName         *string    `json:"asset_name"`
+	Expected          bool       `json:"expected"`
+	ExpectedLocation  *string    `json:"expected_location"`
+	Result            string     `json:"result"`
+	ScannedCode       *string    `json:"scanned_code"`
+	ScannedLocation   *string    `json:"scanned_location"`
+	ScannedBy         *string    `json:"scanned_by"`
+	ScannedAt         *time.Time `json:"scanned_at"`
+	ScanCount         int        `json:"scan_count"`
+	Resolution        *string    `json:"resolution"`
+	ResolutionComment *string    `json:"resolution_comment"`
+	ResolvedBy        *string    `json:"resolved_by"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+}
+
+const inventoryCampaignColumns = `id, tenant_id, name, description, status, scope_locations, scope_departments, scope_types,
+	auditor_ids, scheduled_start, scheduled_end, started_at, completed_at, signed_by, signed_at, sign_comment,
+	report_document_id, report_sha256, created_by, created_at, updated_at`
+
+func scanInventoryCampaign(row rowScanner) (*InventoryCampaign, error) {
+	var c InventoryCampaign
+	err := row.Scan(&c.ID, &c.TenantID, &c.Name, &c.Description, &c.Status, pq.Array(&c.ScopeLocations),
+		pq.Array(&c.ScopeDepartments), pq.Array(&c.ScopeTypes), pq.Array(&c.AuditorIDs), &c.ScheduledStart,
+		&c.ScheduledEnd, &c.StartedAt, &c.CompletedAt, &c.SignedBy, &c.SignedAt, &c.SignComment,
+		&c.ReportDocumentID, &c.ReportSHA256, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+const inventoryItemColumns = `id, campaign_id, asset_id, inventory_number, asset_name, expected, expected_location, result,
+	scanned_code, scanned_location, scanned_by, scanned_at, scan_count, resolution, resolution_comment, resolved_by, resolved_at`
+
+func scanInventoryItem(row rowScanner) (*InventoryItem, error) {
+	var item InventoryItem
+	err := row.Scan(&item.ID, &item.CampaignID, &item.AssetID, &item.InventoryNumber, &item.AssetName, &item.Expected,
+		&item.ExpectedLocation, &item.Result, &item.ScannedCode, &item.ScannedLocation, &item.ScannedBy, &item.ScannedAt,
+		&item.ScanCount, &item.Resolution, &item.ResolutionComment, &item.ResolvedBy, &item.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Inventory campaigns
+
+func (r *AssetRepo) CreateInventoryCampaign(ctx context.Context, c *InventoryCampaign) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO asset_inventory_campaigns (tenant_id, name, description, status, scope_locations, scope_departments,
+		                                       scope_types, auditor_ids, scheduled_start, scheduled_end, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at
+	`, c.TenantID, c.Name, c.Description, c.Status, pq.Array(c.ScopeLocations), pq.Array(c.ScopeDepartments),
+		pq.Array(c.ScopeTypes), pq.Array(c.AuditorIDs), c.ScheduledStart, c.ScheduledEnd, c.CreatedBy).
+		Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+}
+
+// UpdateInventoryCampaign changes the plan of a campaign that has not started yet.
+// Returns false if the campaign is no longer planned.
+func (r *AssetRepo) UpdateInventoryCampaign(ctx context.Context, c *InventoryCampaign) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE asset_inventory_campaigns
+		SET name = $3, description = $4, scope_locations = $5, scope_departments = $6, scope_types = $7,
+		    auditor_ids = $8, scheduled_start = $9, scheduled_end = $10, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND status = 'planned'
+	`, c.ID, c.TenantID, c.Name, c.Description, pq.Array(c.ScopeLocations), pq.Array(c.ScopeDepartments),
+		pq.Array(c.ScopeTypes), pq.Array(c.AuditorIDs), c.ScheduledStart, c.ScheduledEnd)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetInventoryCampaign returns nil if the tenant has no such campaign
+func (r *AssetRepo) GetInventoryCampaign(ctx context.Context, id, tenantID string) (*InventoryCampaign, error) {
+	c, err := scanInventoryCampaign(r.db.QueryRowContext(ctx, `
+		SELECT `+inventoryCampaignColumns+`
+		FROM asset_inventory_campaigns WHERE id = $1 AND tenant_id = $2
+	`, id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// ListInventoryCampaigns returns tenant campaigns, latest scheduled first, optionally filtered by status and auditor
+func (r *AssetRepo) ListInventoryCampaigns(ctx context.Context, tenantID string, filters map[string]interface{}) ([]InventoryCampaign, error) {
+	where := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+	if status, ok := filters["status"].(string); ok && status != "" {
+		args = append(args, status)
+		where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if auditorID, ok := filters["auditor_id"].(string); ok && auditorID != "" {
+		args = append(args, auditorID)
+		where = append(where, fmt.Sprintf("$%d = ANY(auditor_ids)", len(args)))
+	}
+
+	return r.queryInventoryCampaigns(ctx, `
+		SELECT `+inventoryCampaignColumns+`
+		FROM asset_inventory_campaigns
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY scheduled_start DESC, created_at DESC
+	`, args...)
+}
+
+// ListDueInventoryCampaigns returns planned campaigns of all tenants scheduled to start on or before the day
+func (r *AssetRepo) ListDueInventoryCampaigns(ctx context.Context, day time.Time) ([]InventoryCampaign, error) {
+	return r.queryInventoryCampaigns(ctx, `
+		SELECT `+inventoryCampaignColumns+`
+		FROM asset_inventory_campaigns
+		WHERE status = 'planned' AND scheduled_start <= $1
+		ORDER BY scheduled_start
+	`, day)
+}
+
+func (r *AssetRepo) queryInventoryCampaigns(ctx context.Context, query string, args ...interface{}) ([]InventoryCampaign, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var campaigns []InventoryCampaign
+	for rows.Next() {
+		c, err := scanInventoryCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, *c)
+	}
+	return campaigns, rows.Err()
+}
+
+// CancelInventoryCampaign cancels a campaign that is planned or in progress; its inventory list is kept.
+// Returns false if the campaign is already completed, signed or cancelled.
+func (r *AssetRepo) CancelInventoryCampaign(ctx context.Context, id, tenantID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE asset_inventory_campaigns SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND status IN ('planned', 'in_progress')
+	`, id, tenantID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// StartInventoryCampaign moves a planned campaign to in_progress and fills its inventory list with the
+// non-deleted, non-decommissioned tenant assets of the scope: location starting with one of the scope
+// locations (case-insensitive), department from the asset metadata, asset type. An empty scope
+// dimension does not restrict. Returns false if the campaign is no longer planned.
+func (r *AssetRepo) StartInventoryCampaign(ctx context.Context, c *InventoryCampaign) (bool, error) {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return false, errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE asset_inventory_campaigns SET status = 'in_progress', started_at = $3, updated_at = $3
+		WHERE id = $1 AND tenant_id = $2 AND status = 'planned'
+	`, c.ID, c.TenantID, now)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+
+	locationPatterns := make([]string, 0, len(c.ScopeLocations))
+	for _, location := range c.ScopeLocations {
+		locationPatterns = append(locationPatterns, escapeLikePattern(strings.ToLower(strings.TrimSpace(location)))+"%")
+	}
+	departments := make([]string, 0, len(c.ScopeDepartments))
+	for _, department := range c.ScopeDepartments {
+		departments = append(departments, strings.ToLower(strings.TrimSpace(department)))
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO asset_inventory_items (campaign_id, asset_id, inventory_number, asset_name, expected, expected_location)
+		SELECT $1, a.id, a.inventory_number, a.name, true, a.location
+		FROM assets a
+		WHERE a.tenant_id = $2 AND a.deleted_at IS NULL AND a.status <> 'decommissioned'
+		  AND (cardinality($3::text[]) = 0 OR LOWER(a.location) LIKE ANY($3))
+		  AND (cardinality($4::text[]) = 0 OR LOWER(a.metadata->>'department') = ANY($4))
+		  AND (cardinality($5::text[]) = 0 OR a.type = ANY($5))
+		ON CONFLICT DO NOTHING
+	`, c.ID, c.TenantID, pq.Array(locationPatterns), pq.Array(departments), pq.Array(c.ScopeTypes)); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	c.Status = dto.InventoryCampaignInProgress
+	c.StartedAt = &now
+	c.UpdatedAt = now
+	return true, nil
+}
+
+// CompleteInventoryCampaign marks the expected assets that were not scanned as missing, writes the
+// result of every listed asset to its history and moves the campaign to completed.
+// Returns false if the campaign is no longer in progress.
+func (r *AssetRepo) CompleteInventoryCampaign(ctx context.Context, c *InventoryCampaign, completedBy string) (bool, error) {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return false, errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE asset_inventory_campaigns SET status = 'completed', completed_at = $3, updated_at = $3
+		WHERE id = $1 AND tenant_id = $2 AND status = 'in_progress'
+	`, c.ID, c.TenantID, now)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE asset_inventory_items SET result = 'missing' WHERE campaign_id = $1 AND result = 'pending'
+	`, c.ID); err != nil {
+		return false, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT asset_id, result FROM asset_inventory_items WHERE campaign_id = $1 AND asset_id IS NOT NULL
+	`, c.ID)
+	if err != nil {
+		return false, err
+	}
+	var results [][2]string
+	for rows.Next() {
+		var assetID, itemResult string
+		if err := rows.Scan(&assetID, &itemResult); err != nil {
+			rows.Close()
+			return false, err
+		}
+		results = append(results, [2]string{assetID, itemResult})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	for _, res := range results {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO asset_history (id, asset_id, field_changed, old_value, new_value, changed_by)
+			VALUES ($1, $2, $3, '', $4, $5)
+		`, uuid.New().String(), res[0], "inventory_"+res[1], c.Name, completedBy); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	c.Status = dto.InventoryCampaignCompleted
+	c.CompletedAt = &now
+	c.UpdatedAt = now
+	return true, nil
+}
+
+// SignInventoryCampaign records the signature of the final report of a completed campaign.
+// Returns false if the campaign is not completed.
+func (r *AssetRepo) SignInventoryCampaign(ctx context.Context, c *InventoryCampaign) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE asset_inventory_campaigns
+		SET status = 'signed', signed_by = $3, signed_at = $4, sign_comment = $5, report_document_id = $6,
+		    report_sha256 = $7, updated_at = $4
+		WHERE id = $1 AND tenant_id = $2 AND status = 'completed'
+	`, c.ID, c.TenantID, c.SignedBy, c.SignedAt, c.SignComment, c.ReportDocumentID, c.ReportSHA256)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Inventory items
+
+// CountInventoryItems returns the item counts of the campaigns by result
+func (r *AssetRepo) CountInventoryItems(ctx context.Context, campaignIDs []string) (map[string]dto.InventoryCampaignSummary, error) {
+	summaries := make(map[string]dto.InventoryCampaignSummary)
+	if len(campaignIDs) == 0 {
+		return summaries, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT campaign_id,
+		       COUNT(*) FILTER (WHERE expected),
+		       COUNT(*) FILTER (WHERE result = 'pending'),
+		       COUNT(*) FILTER (WHERE result = 'found'),
+		       COUNT(*) FILTER (WHERE result = 'moved'),
+		       COUNT(*) FILTER (WHERE result = 'missing'),
+		       COUNT(*) FILTER (WHERE result = 'unexpected'),
+		       COUNT(*) FILTER (WHERE result IN ('moved', 'missing', 'unexpected') AND resolution IS NULL)
+		FROM asset_inventory_items
+		WHERE campaign_id = ANY($1::uuid[])
+		GROUP BY campaign_id
+	`, pq.Array(campaignIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var campaignID string
+		var s dto.InventoryCampaignSummary
+		if err := rows.Scan(&campaignID, &s.Expected, &s.Pending, &s.Found, &s.Moved, &s.Missing, &s.Unexpected, &s.Unresolved); err != nil {
+			return nil, err
+		}
+		summaries[campaignID] = s
+	}
+	return summaries, rows.Err()
+}
+
+// ListInventoryItems returns the inventory list of the campaign ordered by inventory number,
+// optionally filtered by result or by discrepancies without resolution
+func (r *AssetRepo) ListInventoryItems(ctx context.Context, campaignID string, filters map[string]interface{}) ([]InventoryItem, error) {
+	where := []string{"campaign_id = $1"}
+	args := []interface{}{campaignID}
+	if result, ok := filters["result"].(string); ok && result != "" {
+		args = append(args, result)
+		where = append(where, fmt.Sprintf("result = $%d", len(args)))
+	}
+	if unresolved, ok := filters["unresolved"].(bool); ok && unresolved {
+		where = append(where, "result IN ('moved', 'missing', 'unexpected') AND resolution IS NULL")
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+inventoryItemColumns+`
+		FROM asset_inventory_items
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY inventory_number
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []InventoryItem
+	for rows.Next() {
+		item, err := scanInventoryItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+// GetInventoryItem returns nil if the campaign has no such item
+func (r *AssetRepo) GetInventoryItem(ctx context.Context, id, campaignID string) (*InventoryItem, error) {
+	item, err := scanInventoryItem(r.db.QueryRowContext(ctx, `
+		SELECT `+inventoryItemColumns+`
+		FROM asset_inventory_items WHERE id = $1 AND campaign_id = $2
+	`, id, campaignID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return item, err
+}
+
+// GetInventoryItemByNumber finds the item by inventory number, case-insensitive; nil if not listed
+func (r *AssetRepo) GetInventoryItemByNumber(ctx context.Context, campaignID, inventoryNumber string) (*InventoryItem, error) {
+	item, err := scanInventoryItem(r.db.QueryRowContext(ctx, `
+		SELECT `+inventoryItemColumns+`
+		FROM asset_inventory_items WHERE campaign_id = $1 AND UPPER(inventory_number) = UPPER($2)
+	`, campaignID, inventoryNumber))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return item, err
+}
+
+// FindAssetByInventoryNumber returns the non-deleted tenant asset with the inventory number, case-insensitive.
+// Only identification fields, location and status are filled in.
+func (r *AssetRepo) FindAssetByInventoryNumber(ctx context.Context, tenantID, inventoryNumber string) (*Asset, error) {
+	var asset Asset
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, inventory_number, name, type, location, status
+		FROM assets
+		WHERE tenant_id = $1 AND UPPER(inventory_number) = UPPER($2) AND deleted_at IS NULL
+		ORDER BY created_at LIMIT 1
+	`, tenantID, inventoryNumber).Scan(&asset.ID, &asset.TenantID, &asset.InventoryNumber, &asset.Name, &asset.Type,
+		&asset.Location, &asset.Status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// SaveInventoryScan inserts an unexpected item or records a repeated scan of a listed one
+func (r *AssetRepo) SaveInventoryScan(ctx context.Context, item *InventoryItem) error {
+	if item.ID == "" {
+		return r.db.QueryRowContext(ctx, `
+			INSERT INTO asset_inventory_items (campaign_id, asset_id, inventory_number, asset_name, expected,
+			                                   expected_location, result, scanned_code, scanned_location, scanned_by,
+			                                   scanned_at, scan_count)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING id
+		`, item.CampaignID, item.AssetID, item.InventoryNumber, item.AssetName, item.Expected, item.ExpectedLocation,
+			item.Result, item.ScannedCode, item.ScannedLocation, item.ScannedBy, item.ScannedAt, item.ScanCount).
+			Scan(&item.ID)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE asset_inventory_items
+		SET result = $3, scanned_code = $4, scanned_location = $5, scanned_by = $6, scanned_at = $7, scan_count = $8
+		WHERE id = $1 AND campaign_id = $2
+	`, item.ID, item.CampaignID, item.Result, item.ScannedCode, item.ScannedLocation, item.ScannedBy, item.ScannedAt,
+		item.ScanCount)
+	return err
+}
+
+// ResolveInventoryItem records the resolution of a discrepancy. With a non-nil newLocation the asset
+// location is changed to it in the same transaction, with an asset history entry.
+func (r *AssetRepo) ResolveInventoryItem(ctx context.Context, item *InventoryItem, newLocation *string) error {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE asset_inventory_items
+		SET resolution = $3, resolution_comment = $4, resolved_by = $5, resolved_at = $6
+		WHERE id = $1 AND campaign_id = $2
+	`, item.ID, item.CampaignID, item.Resolution, item.ResolutionComment, item.ResolvedBy, item.ResolvedAt); err != nil {
+		return err
+	}
+
+	if newLocation != nil && item.AssetID != nil && item.ResolvedBy != nil {
+		var oldLocation sql.NullString
+		err := tx.QueryRowContext(ctx, `
+			SELECT location FROM assets WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+		`, *item.AssetID).Scan(&oldLocation)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE assets SET location = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+		`, *item.AssetID, *newLocation); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO asset_history (id, asset_id, field_changed, old_value, new_value, changed_by)
+			VALUES ($1, $2, 'location', $3, $4, $5)
+		`, uuid.New().String(), *item.AssetID, oldLocation.String, *newLocation, *item.ResolvedBy); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// escapeLikePattern escapes the LIKE wildcards of a literal
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
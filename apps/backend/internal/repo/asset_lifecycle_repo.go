@@ -0,0 +1,295 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"risknexus/backend/internal/dto"
+
+	"github.com/google/uuid"
+)
+
+// AssetLifecycleOperation is a requested transfer, repair or write-off of an asset.
+// To* fields that are nil are left unchanged when the operation is applied.
+type AssetLifecycleOperation struct {
+	ID                    string                    `json:"id"`
+	TenantID              string                    `json:"tenant_id"`
+	AssetID               string                    `json:"asset_id"`
+	OperationType         string                    `json:"operation_type"`
+	Status                string                    `json:"status"`
+	Reason                string                    `json:"reason"`
+	FromStatus            string                    `json:"from_status"`
+	ToStatus              *string                   `json:"to_status"`
+	FromOwnerID           *string                   `json:"from_owner_id"`
+	ToOwnerID             *string                   `json:"to_owner_id"`
+	FromResponsibleUserID *string                   `json:"from_responsible_user_id"`
+	ToResponsibleUserID   *string                   `json:"to_responsible_user_id"`
+	FromLocation          *string                   `json:"from_location"`
+	ToLocation            *string                   `json:"to_location"`
+	Details               dto.AssetLifecycleDetails `json:"details"`
+	TemplateID            *string                   `json:"template_id"`
+	ActNumber             *string                   `json:"act_number"`
+	DocumentID            *string                   `json:"document_id"`
+	RequestedBy           string                    `json:"requested_by"`
+	ApproverUserID        *string                   `json:"approver_user_id"`
+	DecisionComment       *string                   `json:"decision_comment"`
+	DecidedAt             *time.Time                `json:"decided_at"`
+	CreatedAt             time.Time                 `json:"created_at"`
+	UpdatedAt             time.Time                 `json:"updated_at"`
+
+	AssetName       string `json:"asset_name"`
+	InventoryNumber string `json:"inventory_number"`
+}
+
+const lifecycleOperationColumns = `o.id, o.tenant_id, o.asset_id, o.operation_type, o.status, o.reason,
+	o.from_status, o.to_status, o.from_owner_id, o.to_owner_id, o.from_responsible_user_id, o.to_responsible_user_id,
+	o.from_location, o.to_location, o.details, o.template_id, o.act_number, o.document_id, o.requested_by,
+	o.approver_user_id, o.decision_comment, o.decided_at, o.created_at, o.updated_at, a.name, a.inventory_number`
+
+func scanLifecycleOperation(row rowScanner) (*AssetLifecycleOperation, error) {
+	var op AssetLifecycleOperation
+	var details []byte
+	err := row.Scan(&op.ID, &op.TenantID, &op.AssetID, &op.OperationType, &op.Status, &op.Reason,
+		&op.FromStatus, &op.ToStatus, &op.FromOwnerID, &op.ToOwnerID, &op.FromResponsibleUserID, &op.ToResponsibleUserID,
+		&op.FromLocation, &op.ToLocation, &details, &op.TemplateID, &op.ActNumber, &op.DocumentID, &op.RequestedBy,
+		&op.ApproverUserID, &op.DecisionComment, &op.DecidedAt, &op.CreatedAt, &op.UpdatedAt, &op.AssetName, &op.InventoryNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(details, &op.Details); err != nil {
+		return nil, fmt.Errorf("invalid details of lifecycle operation %s: %w", op.ID, err)
+	}
+	return &op, nil
+}
+
+// Asset lifecycle operations
+
+func (r *AssetRepo) CreateLifecycleOperation(ctx context.Context, op *AssetLifecycleOperation) error {
+	details, err := json.Marshal(op.Details)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO asset_lifecycle_operations (tenant_id, asset_id, operation_type, status, reason,
+		                                        from_status, to_status, from_owner_id, to_owner_id,
+		                                        from_responsible_user_id, to_responsible_user_id, from_location, to_location,
+		                                        details, template_id, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id, created_at, updated_at
+	`, op.TenantID, op.AssetID, op.OperationType, op.Status, op.Reason,
+		op.FromStatus, op.ToStatus, op.FromOwnerID, op.ToOwnerID,
+		op.FromResponsibleUserID, op.ToResponsibleUserID, op.FromLocation, op.ToLocation,
+		details, op.TemplateID, op.RequestedBy).
+		Scan(&op.ID, &op.CreatedAt, &op.UpdatedAt)
+}
+
+// GetLifecycleOperation returns nil if the tenant has no such operation
+func (r *AssetRepo) GetLifecycleOperation(ctx context.Context, id, tenantID string) (*AssetLifecycleOperation, error) {
+	op, err := scanLifecycleOperation(r.db.QueryRowContext(ctx, `
+		SELECT `+lifecycleOperationColumns+`
+		FROM asset_lifecycle_operations o
+		JOIN assets a ON a.id = o.asset_id
+		WHERE o.id = $1 AND o.tenant_id = $2
+	`, id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return op, err
+}
+
+// HasPendingLifecycleOperation reports whether the asset already has an operation awaiting approval
+func (r *AssetRepo) HasPendingLifecycleOperation(ctx context.Context, assetID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM asset_lifecycle_operations WHERE asset_id = $1 AND status = 'pending')
+	`, assetID).Scan(&exists)
+	return exists, err
+}
+
+// ListLifecycleOperations returns tenant operations, newest first, optionally filtered by asset, status and type
+func (r *AssetRepo) ListLifecycleOperations(ctx context.Context, tenantID string, filters map[string]interface{}) ([]AssetLifecycleOperation, error) {
+	where := []string{"o.tenant_id = $1", "a.deleted_at IS NULL"}
+	args := []interface{}{tenantID}
+	if assetID, ok := filters["asset_id"].(string); ok && assetID != "" {
+		args = append(args, assetID)
+		where = append(where, fmt.Sprintf("o.asset_id = $%d", len(args)))
+	}
+	if status, ok := filters["status"].(string); ok && status != "" {
+		args = append(args, status)
+		where = append(where, fmt.Sprintf("o.status = $%d", len(args)))
+	}
+	if operationType, ok := filters["operation_type"].(string); ok && operationType != "" {
+		args = append(args, operationType)
+		where = append(where, fmt.Sprintf("o.operation_type = $%d", len(args)))
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+lifecycleOperationColumns+`
+		FROM asset_lifecycle_operations o
+		JOIN assets a ON a.id = o.asset_id
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY o.created_at DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var operations []AssetLifecycleOperation
+	for rows.Next() {
+		op, err := scanLifecycleOperation(rows)
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, *op)
+	}
+	return operations, rows.Err()
+}
+
+// DecideLifecycleOperation moves a pending operation to rejected or cancelled.
+// Returns false if the operation is no longer pending.
+func (r *AssetRepo) DecideLifecycleOperation(ctx context.Context, id, tenantID, status, decidedBy string, comment *string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE asset_lifecycle_operations
+		SET status = $3, approver_user_id = $4, decision_comment = $5, decided_at = CURRENT_TIMESTAMP,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2 AND status = 'pending'
+	`, id, tenantID, status, decidedBy, comment)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ApplyLifecycleOperation approves a pending operation and applies it to the asset in one transaction:
+// the asset status, owner, responsible user and location are updated, every changed field is written
+// to the asset history and the operation gets the next act number of the tenant for the current year.
+// The from_* fields are refreshed with the asset state at approval so the act shows what was changed.
+// Returns false if the operation is no longer pending; nothing is changed in that case.
+func (r *AssetRepo) ApplyLifecycleOperation(ctx context.Context, op *AssetLifecycleOperation, approvedBy string, comment *string) (bool, error) {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return false, errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var asset Asset
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, owner_id, responsible_user_id, location
+		FROM assets WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, op.AssetID, op.TenantID).Scan(&asset.Status, &asset.OwnerID, &asset.ResponsibleUserID, &asset.Location)
+	if err != nil {
+		return false, err
+	}
+
+	// Номера актов выдаются последовательно в пределах организации и года
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('asset_lifecycle_act:' || $1))`, op.TenantID); err != nil {
+		return false, err
+	}
+	var sequence int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) + 1 FROM asset_lifecycle_operations
+		WHERE tenant_id = $1 AND act_number IS NOT NULL AND EXTRACT(YEAR FROM decided_at) = $2
+	`, op.TenantID, now.Year()).Scan(&sequence)
+	if err != nil {
+		return false, err
+	}
+	actNumber := fmt.Sprintf("%d-%04d", now.Year(), sequence)
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE asset_lifecycle_operations
+		SET status = 'approved', approver_user_id = $3, decision_comment = $4, decided_at = $5, act_number = $6,
+		    from_status = $7, from_owner_id = $8, from_responsible_user_id = $9, from_location = $10, updated_at = $5
+		WHERE id = $1 AND tenant_id = $2 AND status = 'pending'
+	`, op.ID, op.TenantID, approvedBy, comment, now, actNumber,
+		asset.Status, asset.OwnerID, asset.ResponsibleUserID, asset.Location)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+
+	changes := [][3]string{}
+	track := func(field string, current *string, target *string) *string {
+		if target == nil || stringValue(current) == *target {
+			return current
+		}
+		changes = append(changes, [3]string{field, stringValue(current), *target})
+		if *target == "" {
+			return nil
+		}
+		return target
+	}
+	status := asset.Status
+	if op.ToStatus != nil && *op.ToStatus != asset.Status {
+		changes = append(changes, [3]string{"status", asset.Status, *op.ToStatus})
+		status = *op.ToStatus
+	}
+	ownerID := track("owner_id", asset.OwnerID, op.ToOwnerID)
+	responsibleUserID := track("responsible_user_id", asset.ResponsibleUserID, op.ToResponsibleUserID)
+	location := track("location", asset.Location, op.ToLocation)
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE assets
+		SET status = $2, owner_id = $3, responsible_user_id = $4, location = $5, updated_at = $6
+		WHERE id = $1
+	`, op.AssetID, status, ownerID, responsibleUserID, location, now); err != nil {
+		return false, err
+	}
+
+	changes = append(changes, [3]string{"lifecycle_" + op.OperationType, "", fmt.Sprintf("Акт №%s: %s", actNumber, op.Reason)})
+	for _, change := range changes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO asset_history (id, asset_id, field_changed, old_value, new_value, changed_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New().String(), op.AssetID, change[0], change[1], change[2], approvedBy); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	op.Status = dto.AssetLifecycleStatusApproved
+	op.FromStatus = asset.Status
+	op.FromOwnerID = asset.OwnerID
+	op.FromResponsibleUserID = asset.ResponsibleUserID
+	op.FromLocation = asset.Location
+	op.ApproverUserID = &approvedBy
+	op.DecisionComment = comment
+	op.DecidedAt = &now
+	op.ActNumber = &actNumber
+	op.UpdatedAt = now
+	return true, nil
+}
+
+// SetLifecycleDocument links the generated act to the operation
+func (r *AssetRepo) SetLifecycleDocument(ctx context.Context, id, tenantID, documentID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE asset_lifecycle_operations SET document_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND tenant_id = $2
+	`, id, tenantID, documentID)
+	return err
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// AssetRelationship is a typed link "source <relationship_type> target" between two assets of a tenant
+type AssetRelationship struct {
+	ID               string    `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	SourceAssetID    string    `json:"source_asset_id"`
+	TargetAssetID    string    `json:"target_asset_id"`
+	RelationshipType string    `json:"relationship_type"`
+	Description      *string   `json:"description"`
+	CreatedBy        *string   `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+const assetRelationshipColumns = `r.id, r.tenant_id, r.source_asset_id, r.target_asset_id, r.relationship_type,
+	r.description, r.created_by, r.created_at`
+
+func scanAssetRelationship(row rowScanner) (*AssetRelationship, error) {
+	var rel AssetRelationship
+	err := row.Scan(&rel.ID, &rel.TenantID, &rel.SourceAssetID, &rel.TargetAssetID, &rel.RelationshipType,
+		&rel.Description, &rel.CreatedBy, &rel.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// Asset relationships
+
+func (r *AssetRepo) CreateRelationship(ctx context.Context, rel *AssetRelationship) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO asset_relationships (tenant_id, source_asset_id, target_asset_id, relationship_type, description, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, rel.TenantID, rel.SourceAssetID, rel.TargetAssetID, rel.RelationshipType, rel.Description, rel.CreatedBy).
+		Scan(&rel.ID, &rel.CreatedAt)
+}
+
+func (r *AssetRepo) GetRelationship(ctx context.Context, id, tenantID string) (*AssetRelationship, error) {
+	rel, err := scanAssetRelationship(r.db.QueryRowContext(ctx, `
+		SELECT `+assetRelationshipColumns+`
+		FROM asset_relationships r
+		WHERE r.id = $1 AND r.tenant_id = $2
+	`, id, tenantID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return rel, err
+}
+
+func (r *AssetRepo) DeleteRelationship(ctx context.Context, id, tenantID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM asset_relationships WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	return err
+}
+
+// ListRelationships returns every relationship of the tenant between non-deleted assets
+func (r *AssetRepo) ListRelationships(ctx context.Context, tenantID string) ([]AssetRelationship, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+assetRelationshipColumns+`
+		FROM asset_relationships r
+		JOIN assets s ON s.id = r.source_asset_id AND s.deleted_at IS NULL
+		JOIN assets t ON t.id = r.target_asset_id AND t.deleted_at IS NULL
+		WHERE r.tenant_id = $1
+		ORDER BY r.created_at
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var relationships []AssetRelationship
+	for rows.Next() {
+		rel, err := scanAssetRelationship(rows)
+		if err != nil {
+			return nil, err
+		}
+		relationships = append(relationships, *rel)
+	}
+	return relationships, rows.Err()
+}
+
+// ListRelationshipNodes returns the non-deleted tenant assets that take part in at least one relationship.
+// Only identification fields, status and CIA ratings are filled in.
+func (r *AssetRepo) ListRelationshipNodes(ctx context.Context, tenantID string) ([]Asset, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.tenant_id, a.inventory_number, a.name, a.type, a.class, a.status,
+		       a.criticality, a.confidentiality, a.integrity, a.availability
+		FROM assets a
+		WHERE a.tenant_id = $1 AND a.deleted_at IS NULL
+		  AND EXISTS (
+		      SELECT 1 FROM asset_relationships r
+		      WHERE r.source_asset_id = a.id OR r.target_asset_id = a.id
+		  )
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		var asset Asset
+		err := rows.Scan(&asset.ID, &asset.TenantID, &asset.InventoryNumber, &asset.Name, &asset.Type, &asset.Class,
+			&asset.Status, &asset.Criticality, &asset.Confidentiality, &asset.Integrity, &asset.Availability)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
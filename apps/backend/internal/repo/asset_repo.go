@@ -12,6 +12,7 @@ import (
 	"risknexus/backend/internal/dto"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Asset struct {
@@ -883,6 +884,50 @@ func (r *AssetRepo) GetAssetsWithoutOwner(ctx context.Context, tenantID string)
 	return assets, nil
 }
 
+// FindByNetworkIdentity returns assets whose IP address, name or PC number matches one of the given values.
+// Only identification fields are filled in.
+func (r *AssetRepo) FindByNetworkIdentity(ctx context.Context, tenantID string, ipAddresses, hostnames []string) ([]Asset, error) {
+	if len(ipAddresses) == 0 && len(hostnames) == 0 {
+		return nil, nil
+	}
+
+	lowered := make([]string, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		lowered = append(lowered, strings.ToLower(hostname))
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, inventory_number, name, type, class, pc_number, host(ip_address)
+		FROM assets
+		WHERE tenant_id = $1 AND deleted_at IS NULL
+		  AND (host(ip_address) = ANY($2) OR LOWER(name) = ANY($3) OR LOWER(pc_number) = ANY($3))
+		ORDER BY name
+	`, tenantID, pq.Array(ipAddresses), pq.Array(lowered))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assets []Asset
+	for rows.Next() {
+		var asset Asset
+		var pcNumber, ipAddress sql.NullString
+		err := rows.Scan(&asset.ID, &asset.TenantID, &asset.InventoryNumber, &asset.Name,
+			&asset.Type, &asset.Class, &pcNumber, &ipAddress)
+		if err != nil {
+			return nil, err
+		}
+		if pcNumber.Valid {
+			asset.PCNumber = &pcNumber.String
+		}
+		if ipAddress.Valid {
+			asset.IPAddress = &ipAddress.String
+		}
+		assets = append(assets, asset)
+	}
+	return assets, rows.Err()
+}
+
 // GetAssetsWithoutPassport returns assets without passport document
 func (r *AssetRepo) GetAssetsWithoutPassport(ctx context.Context, tenantID string) ([]Asset, error) {
 	rows, err := r.db.Query(`
@@ -0,0 +1,422 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CVEEntry is a vulnerability from an imported NVD feed with its vulnerable CPE configurations
+type CVEEntry struct {
+	ID          string        `json:"id"`
+	TenantID    string        `json:"tenant_id"`
+	CVEID       string        `json:"cve_id"`
+	Description *string       `json:"description"`
+	CVSSScore   *float64      `json:"cvss_score"`
+	CVSSVersion *string       `json:"cvss_version"`
+	CVSSVector  *string       `json:"cvss_vector"`
+	Severity    *string       `json:"severity"`
+	PublishedAt *time.Time    `json:"published_at"`
+	ModifiedAt  *time.Time    `json:"modified_at"`
+	Matches     []CVECPEMatch `json:"matches"`
+}
+
+// CVECPEMatch is a vulnerable CPE configuration of a CVE; version bounds are kept as written in the feed
+type CVECPEMatch struct {
+	CVEEntryID            string   `json:"cve_entry_id"`
+	CVEID                 string   `json:"cve_id"`
+	CVSSScore             *float64 `json:"cvss_score"`
+	CPE23                 string   `json:"cpe23"`
+	Vendor                string   `json:"vendor"`
+	Product               string   `json:"product"`
+	Version               *string  `json:"version"`
+	VersionStartIncluding *string  `json:"version_start_including"`
+	VersionStartExcluding *string  `json:"version_start_excluding"`
+	VersionEndIncluding   *string  `json:"version_end_including"`
+	VersionEndExcluding   *string  `json:"version_end_excluding"`
+}
+
+// CPEProduct identifies a product in CPE terms
+type CPEProduct struct {
+	Vendor  string `json:"vendor"`
+	Product string `json:"product"`
+}
+
+// CPEDictionaryEntry is a product title from the CPE dictionary
+type CPEDictionaryEntry struct {
+	CPEProduct
+	Title           string `json:"title"`
+	NormalizedTitle string `json:"normalized_title"`
+}
+
+// AssetVulnerability is a CVE matched to installed software, with the CVE, software and asset fields
+// needed to list it and to raise a risk for it
+type AssetVulnerability struct {
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	AssetID         string     `json:"asset_id"`
+	SoftwareID      string     `json:"software_id"`
+	CVEEntryID      string     `json:"cve_entry_id"`
+	Vendor          string     `json:"vendor"`
+	Product         string     `json:"product"`
+	Status          string     `json:"status"`
+	RiskID          *string    `json:"risk_id"`
+	FirstDetectedAt time.Time  `json:"first_detected_at"`
+	LastDetectedAt  time.Time  `json:"last_detected_at"`
+	FixedAt         *time.Time `json:"fixed_at"`
+
+	CVEID            string   `json:"cve_id"`
+	Description      *string  `json:"description"`
+	CVSSScore        *float64 `json:"cvss_score"`
+	Severity         *string  `json:"severity"`
+	SoftwareName     string   `json:"software_name"`
+	SoftwareVersion  *string  `json:"software_version"`
+	AssetName        string   `json:"asset_name"`
+	InventoryNumber  string   `json:"inventory_number"`
+	AssetCriticality string   `json:"asset_criticality"`
+	AssetOwnerID     *string  `json:"asset_owner_id"`
+}
+
+// Vulnerability feeds
+
+// UpsertCVEEntries inserts or updates the CVEs of the tenant in one transaction; the CPE configurations
+// of an updated CVE are replaced with the ones from the feed
+func (r *AssetRepo) UpsertCVEEntries(ctx context.Context, tenantID string, entries []CVEEntry) error {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsertEntry, err := tx.PrepareContext(ctx, `
+		INSERT INTO cve_entries (tenant_id, cve_id, description, cvss_score, cvss_version, cvss_vector, severity, published_at, modified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tenant_id, cve_id) DO UPDATE
+		SET description = EXCLUDED.description, cvss_score = EXCLUDED.cvss_score, cvss_version = EXCLUDED.cvss_version,
+		    cvss_vector = EXCLUDED.cvss_vector, severity = EXCLUDED.severity, published_at = EXCLUDED.published_at,
+		    modified_at = EXCLUDED.modified_at, updated_at = CURRENT_TIMESTAMP
+		RETURNING id
+	`)
+	if err != nil {
+		return err
+	}
+	defer upsertEntry.Close()
+
+	deleteMatches, err := tx.PrepareContext(ctx, `DELETE FROM cve_cpe_matches WHERE cve_entry_id = $1`)
+	if err != nil {
+		return err
+	}
+	defer deleteMatches.Close()
+
+	insertMatch, err := tx.PrepareContext(ctx, `
+		INSERT INTO cve_cpe_matches (tenant_id, cve_entry_id, cpe23, vendor, product, version,
+		                             version_start_including, version_start_excluding, version_end_including, version_end_excluding)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`)
+	if err != nil {
+		return err
+	}
+	defer insertMatch.Close()
+
+	for i := range entries {
+		entry := &entries[i]
+		entry.TenantID = tenantID
+		if err := upsertEntry.QueryRowContext(ctx, tenantID, entry.CVEID, entry.Description, entry.CVSSScore, entry.CVSSVersion,
+			entry.CVSSVector, entry.Severity, entry.PublishedAt, entry.ModifiedAt).Scan(&entry.ID); err != nil {
+			return fmt.Errorf("%s: %w", entry.CVEID, err)
+		}
+		if _, err := deleteMatches.ExecContext(ctx, entry.ID); err != nil {
+			return err
+		}
+		for _, m := range entry.Matches {
+			if _, err := insertMatch.ExecContext(ctx, tenantID, entry.ID, m.CPE23, m.Vendor, m.Product, m.Version,
+				m.VersionStartIncluding, m.VersionStartExcluding, m.VersionEndIncluding, m.VersionEndExcluding); err != nil {
+				return fmt.Errorf("%s: %w", entry.CVEID, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// UpsertCPEDictionary inserts the dictionary entries of the tenant, keeping the latest title of duplicates
+func (r *AssetRepo) UpsertCPEDictionary(ctx context.Context, tenantID string, entries []CPEDictionaryEntry) error {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO cpe_dictionary (tenant_id, vendor, product, title, normalized_title)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, vendor, product, normalized_title) DO UPDATE SET title = EXCLUDED.title
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.ExecContext(ctx, tenantID, entry.Vendor, entry.Product, entry.Title, entry.NormalizedTitle); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CountVulnerabilityFeed returns the number of CVEs and CPE dictionary entries of the tenant
+func (r *AssetRepo) CountVulnerabilityFeed(ctx context.Context, tenantID string) (int, int, error) {
+	var cves, dictionary int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM cve_entries WHERE tenant_id = $1),
+		       (SELECT COUNT(*) FROM cpe_dictionary WHERE tenant_id = $1)
+	`, tenantID).Scan(&cves, &dictionary)
+	return cves, dictionary, err
+}
+
+// Vulnerability matching
+
+// ListSoftwareForMatching returns the software installed on non-deleted tenant assets, or on one asset
+// when assetID is not empty
+func (r *AssetRepo) ListSoftwareForMatching(ctx context.Context, tenantID, assetID string) ([]AssetSoftware, error) {
+	query := `
+		SELECT s.id, s.asset_id, s.software_name, s.version, s.installed_at, s.updated_at
+		FROM asset_software s
+		JOIN assets a ON a.id = s.asset_id
+		WHERE a.tenant_id = $1 AND a.deleted_at IS NULL`
+	args := []interface{}{tenantID}
+	if assetID != "" {
+		args = append(args, assetID)
+		query += ` AND a.id = $2`
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var software []AssetSoftware
+	for rows.Next() {
+		var sw AssetSoftware
+		if err := rows.Scan(&sw.ID, &sw.AssetID, &sw.SoftwareName, &sw.Version, &sw.InstalledAt, &sw.UpdatedAt); err != nil {
+			return nil, err
+		}
+		software = append(software, sw)
+	}
+	return software, rows.Err()
+}
+
+// FindCPEByTitles returns dictionary entries whose normalized title is one of titles
+func (r *AssetRepo) FindCPEByTitles(ctx context.Context, tenantID string, titles []string) ([]CPEDictionaryEntry, error) {
+	if len(titles) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT vendor, product, title, normalized_title
+		FROM cpe_dictionary
+		WHERE tenant_id = $1 AND normalized_title = ANY($2)
+	`, tenantID, pq.Array(titles))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []CPEDictionaryEntry
+	for rows.Next() {
+		var e CPEDictionaryEntry
+		if err := rows.Scan(&e.Vendor, &e.Product, &e.Title, &e.NormalizedTitle); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// FindCVEProducts returns the distinct vendor/product pairs of the tenant CVE configurations
+// whose product is one of products
+func (r *AssetRepo) FindCVEProducts(ctx context.Context, tenantID string, products []string) ([]CPEProduct, error) {
+	if len(products) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT vendor, product
+		FROM cve_cpe_matches
+		WHERE tenant_id = $1 AND product = ANY($2)
+	`, tenantID, pq.Array(products))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []CPEProduct
+	for rows.Next() {
+		var p CPEProduct
+		if err := rows.Scan(&p.Vendor, &p.Product); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, rows.Err()
+}
+
+// ListCPEMatches returns the vulnerable configurations of the given "vendor:product" keys
+func (r *AssetRepo) ListCPEMatches(ctx context.Context, tenantID string, productKeys []string) ([]CVECPEMatch, error) {
+	if len(productKeys) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.cve_entry_id, e.cve_id, e.cvss_score, m.cpe23, m.vendor, m.product, m.version,
+		       m.version_start_including, m.version_start_excluding, m.version_end_including, m.version_end_excluding
+		FROM cve_cpe_matches m
+		JOIN cve_entries e ON e.id = m.cve_entry_id
+		WHERE m.tenant_id = $1 AND m.vendor || ':' || m.product = ANY($2)
+	`, tenantID, pq.Array(productKeys))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []CVECPEMatch
+	for rows.Next() {
+		var m CVECPEMatch
+		err := rows.Scan(&m.CVEEntryID, &m.CVEID, &m.CVSSScore, &m.CPE23, &m.Vendor, &m.Product, &m.Version,
+			&m.VersionStartIncluding, &m.VersionStartExcluding, &m.VersionEndIncluding, &m.VersionEndExcluding)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// SaveVulnerabilityMatches records the vulnerabilities found for the checked software in one transaction.
+// Found vulnerabilities are opened (or reopened), open vulnerabilities of the checked software that were not
+// found again are marked fixed. Returns the number of newly detected and of fixed vulnerabilities.
+func (r *AssetRepo) SaveVulnerabilityMatches(ctx context.Context, tenantID string, softwareIDs []string, found []AssetVulnerability, detectedAt time.Time) (int, int, error) {
+	db, ok := r.db.(txStarter)
+	if !ok {
+		return 0, 0, errors.New("asset repository does not support transactions")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO asset_vulnerabilities (tenant_id, asset_id, software_id, cve_entry_id, vendor, product, first_detected_at, last_detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (software_id, cve_entry_id) DO UPDATE
+		SET vendor = EXCLUDED.vendor, product = EXCLUDED.product, last_detected_at = EXCLUDED.last_detected_at,
+		    status = 'open', fixed_at = NULL
+		RETURNING (xmax = 0)
+	`)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer stmt.Close()
+
+	detected := 0
+	for _, v := range found {
+		var inserted bool
+		if err := stmt.QueryRowContext(ctx, tenantID, v.AssetID, v.SoftwareID, v.CVEEntryID, v.Vendor, v.Product, detectedAt).Scan(&inserted); err != nil {
+			return 0, 0, err
+		}
+		if inserted {
+			detected++
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE asset_vulnerabilities
+		SET status = 'fixed', fixed_at = $3
+		WHERE tenant_id = $1 AND software_id = ANY($2) AND status = 'open' AND last_detected_at < $3
+	`, tenantID, pq.Array(softwareIDs), detectedAt)
+	if err != nil {
+		return 0, 0, err
+	}
+	fixed, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return detected, int(fixed), tx.Commit()
+}
+
+// ListAssetVulnerabilities returns the tenant vulnerabilities, most severe first.
+// Supported filters: asset_id, status, severity, min_cvss (float64), without_risk (bool).
+func (r *AssetRepo) ListAssetVulnerabilities(ctx context.Context, tenantID string, filters map[string]interface{}) ([]AssetVulnerability, error) {
+	query := `
+		SELECT v.id, v.tenant_id, v.asset_id, v.software_id, v.cve_entry_id, v.vendor, v.product, v.status, v.risk_id,
+		       v.first_detected_at, v.last_detected_at, v.fixed_at,
+		       e.cve_id, e.description, e.cvss_score, e.severity, s.software_name, s.version,
+		       a.name, a.inventory_number, a.criticality, a.owner_id
+		FROM asset_vulnerabilities v
+		JOIN cve_entries e ON e.id = v.cve_entry_id
+		JOIN asset_software s ON s.id = v.software_id
+		JOIN assets a ON a.id = v.asset_id AND a.deleted_at IS NULL
+		WHERE v.tenant_id = $1`
+	args := []interface{}{tenantID}
+
+	if assetID, ok := filters["asset_id"].(string); ok && assetID != "" {
+		args = append(args, assetID)
+		query += fmt.Sprintf(" AND v.asset_id = $%d", len(args))
+	}
+	if status, ok := filters["status"].(string); ok && status != "" {
+		args = append(args, status)
+		query += fmt.Sprintf(" AND v.status = $%d", len(args))
+	}
+	if severity, ok := filters["severity"].(string); ok && severity != "" {
+		args = append(args, severity)
+		query += fmt.Sprintf(" AND e.severity = $%d", len(args))
+	}
+	if minCVSS, ok := filters["min_cvss"].(float64); ok && minCVSS > 0 {
+		args = append(args, minCVSS)
+		query += fmt.Sprintf(" AND e.cvss_score >= $%d", len(args))
+	}
+	if withoutRisk, ok := filters["without_risk"].(bool); ok && withoutRisk {
+		query += " AND v.risk_id IS NULL"
+	}
+	query += " ORDER BY e.cvss_score DESC NULLS LAST, e.cve_id, a.inventory_number"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vulnerabilities []AssetVulnerability
+	for rows.Next() {
+		var v AssetVulnerability
+		err := rows.Scan(&v.ID, &v.TenantID, &v.AssetID, &v.SoftwareID, &v.CVEEntryID, &v.Vendor, &v.Product, &v.Status, &v.RiskID,
+			&v.FirstDetectedAt, &v.LastDetectedAt, &v.FixedAt,
+			&v.CVEID, &v.Description, &v.CVSSScore, &v.Severity, &v.SoftwareName, &v.SoftwareVersion,
+			&v.AssetName, &v.InventoryNumber, &v.AssetCriticality, &v.AssetOwnerID)
+		if err != nil {
+			return nil, err
+		}
+		vulnerabilities = append(vulnerabilities, v)
+	}
+	return vulnerabilities, rows.Err()
+}
+
+// SetVulnerabilityRisk links the risk to every vulnerability of the asset with the given CVE
+func (r *AssetRepo) SetVulnerabilityRisk(ctx context.Context, tenantID, assetID, cveEntryID, riskID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE asset_vulnerabilities SET risk_id = $4
+		WHERE tenant_id = $1 AND asset_id = $2 AND cve_entry_id = $3
+	`, tenantID, assetID, cveEntryID, riskID)
+	return err
+}
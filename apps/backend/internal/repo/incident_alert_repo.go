@@ -0,0 +1,269 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// IncidentAlertSource is an external system (SIEM, monitoring, agent) allowed to post alerts
+type IncidentAlertSource struct {
+	ID                 string            `json:"id"`
+	TenantID           string            `json:"tenant_id"`
+	Name               string            `json:"name"`
+	Format             string            `json:"format"`
+	IncidentSource     string            `json:"incident_source"`
+	TokenHash          string            `json:"-"`
+	TokenPrefix        string            `json:"token_prefix"`
+	FieldMapping       map[string]string `json:"field_mapping"`
+	SeverityMapping    map[string]string `json:"severity_mapping"`
+	CorrelationFields  []string          `json:"correlation_fields"`
+	DedupWindowMinutes int               `json:"dedup_window_minutes"`
+	DefaultCategory    string            `json:"default_category"`
+	DefaultCriticality string            `json:"default_criticality"`
+	AssignedTo         *string           `json:"assigned_to"`
+	ReporterID         string            `json:"reporter_id"`
+	IsActive           bool              `json:"is_active"`
+	LastReceivedAt     *time.Time        `json:"last_received_at"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// IncidentAlert is an alert received from a source and the incident it was attached to
+type IncidentAlert struct {
+	ID             string            `json:"id"`
+	TenantID       string            `json:"tenant_id"`
+	SourceID       string            `json:"source_id"`
+	SourceName     string            `json:"source_name"`
+	IncidentID     *string           `json:"incident_id"`
+	CorrelationKey string            `json:"correlation_key"`
+	Title          string            `json:"title"`
+	Severity       *string           `json:"severity"`
+	Criticality    string            `json:"criticality"`
+	Fields         map[string]string `json:"fields"`
+	RawPayload     string            `json:"raw_payload"`
+	IsDuplicate    bool              `json:"is_duplicate"`
+	ReceivedAt     time.Time         `json:"received_at"`
+}
+
+// Alert sources
+const alertSourceColumns = `id, tenant_id, name, format, incident_source, token_hash, token_prefix, field_mapping,
+	severity_mapping, correlation_fields, dedup_window_minutes, default_category, default_criticality,
+	assigned_to, reporter_id, is_active, last_received_at, created_at, updated_at`
+
+func scanAlertSource(row rowScanner) (*IncidentAlertSource, error) {
+	var source IncidentAlertSource
+	var fieldMapping, severityMapping []byte
+	err := row.Scan(
+		&source.ID, &source.TenantID, &source.Name, &source.Format, &source.IncidentSource,
+		&source.TokenHash, &source.TokenPrefix, &fieldMapping, &severityMapping,
+		pq.Array(&source.CorrelationFields), &source.DedupWindowMinutes, &source.DefaultCategory,
+		&source.DefaultCriticality, &source.AssignedTo, &source.ReporterID, &source.IsActive,
+		&source.LastReceivedAt, &source.CreatedAt, &source.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshalStringMap(fieldMapping, &source.FieldMapping); err != nil {
+		return nil, err
+	}
+	if err := unmarshalStringMap(severityMapping, &source.SeverityMapping); err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+func unmarshalStringMap(data []byte, target *map[string]string) error {
+	*target = map[string]string{}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
+
+func (r *incidentRepository) ListAlertSources(ctx context.Context, tenantID string) ([]*IncidentAlertSource, error) {
+	query := `SELECT ` + alertSourceColumns + ` FROM incident_alert_sources WHERE tenant_id = $1 ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []*IncidentAlertSource
+	for rows.Next() {
+		source, err := scanAlertSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, rows.Err()
+}
+
+// GetAlertSource returns nil if the tenant has no such source
+func (r *incidentRepository) GetAlertSource(ctx context.Context, id, tenantID string) (*IncidentAlertSource, error) {
+	query := `SELECT ` + alertSourceColumns + ` FROM incident_alert_sources WHERE id = $1 AND tenant_id = $2`
+
+	source, err := scanAlertSource(r.db.QueryRowContext(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return source, nil
+}
+
+// GetAlertSourceByTokenHash returns nil if no source uses the token
+func (r *incidentRepository) GetAlertSourceByTokenHash(ctx context.Context, tokenHash string) (*IncidentAlertSource, error) {
+	query := `SELECT ` + alertSourceColumns + ` FROM incident_alert_sources WHERE token_hash = $1`
+
+	source, err := scanAlertSource(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return source, nil
+}
+
+// SaveAlertSource inserts or updates a source
+func (r *incidentRepository) SaveAlertSource(ctx context.Context, source *IncidentAlertSource) error {
+	fieldMapping, err := json.Marshal(source.FieldMapping)
+	if err != nil {
+		return err
+	}
+	severityMapping, err := json.Marshal(source.SeverityMapping)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO incident_alert_sources (id, tenant_id, name, format, incident_source, token_hash, token_prefix,
+		                                    field_mapping, severity_mapping, correlation_fields, dedup_window_minutes,
+		                                    default_category, default_criticality, assigned_to, reporter_id, is_active,
+		                                    created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, format = EXCLUDED.format, incident_source = EXCLUDED.incident_source,
+		    token_hash = EXCLUDED.token_hash, token_prefix = EXCLUDED.token_prefix,
+		    field_mapping = EXCLUDED.field_mapping, severity_mapping = EXCLUDED.severity_mapping,
+		    correlation_fields = EXCLUDED.correlation_fields, dedup_window_minutes = EXCLUDED.dedup_window_minutes,
+		    default_category = EXCLUDED.default_category, default_criticality = EXCLUDED.default_criticality,
+		    assigned_to = EXCLUDED.assigned_to, is_active = EXCLUDED.is_active, updated_at = EXCLUDED.updated_at
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		source.ID, source.TenantID, source.Name, source.Format, source.IncidentSource, source.TokenHash,
+		source.TokenPrefix, fieldMapping, severityMapping, pq.Array(source.CorrelationFields),
+		source.DedupWindowMinutes, source.DefaultCategory, source.DefaultCriticality, source.AssignedTo,
+		source.ReporterID, source.IsActive, source.CreatedAt, source.UpdatedAt)
+	return err
+}
+
+func (r *incidentRepository) DeleteAlertSource(ctx context.Context, id, tenantID string) error {
+	query := `DELETE FROM incident_alert_sources WHERE id = $1 AND tenant_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, tenantID)
+	return err
+}
+
+// Alerts
+
+// FindOpenIncidentByCorrelation returns the open incident that received an alert with the key since the given time,
+// or an empty string if there is none
+func (r *incidentRepository) FindOpenIncidentByCorrelation(ctx context.Context, tenantID, correlationKey string, since time.Time) (string, error) {
+	query := `
+		SELECT a.incident_id
+		FROM incident_alerts a
+		JOIN incidents i ON i.id = a.incident_id
+		WHERE a.tenant_id = $1 AND a.correlation_key = $2 AND a.received_at >= $3
+		  AND i.status NOT IN ('resolved', 'closed')
+		ORDER BY a.received_at DESC
+		LIMIT 1
+	`
+
+	var incidentID string
+	err := r.db.QueryRowContext(ctx, query, tenantID, correlationKey, since).Scan(&incidentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return incidentID, nil
+}
+
+// AddAlert stores the alert and updates the last reception time of its source
+func (r *incidentRepository) AddAlert(ctx context.Context, alert *IncidentAlert) error {
+	fields, err := json.Marshal(alert.Fields)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_alerts (id, tenant_id, source_id, incident_id, correlation_key, title, severity,
+		                             criticality, fields, raw_payload, is_duplicate, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		alert.ID, alert.TenantID, alert.SourceID, alert.IncidentID, alert.CorrelationKey, alert.Title,
+		alert.Severity, alert.Criticality, fields, alert.RawPayload, alert.IsDuplicate, alert.ReceivedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE incident_alert_sources SET last_received_at = $1 WHERE id = $2`,
+		alert.ReceivedAt, alert.SourceID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *incidentRepository) ListAlerts(ctx context.Context, incidentID string) ([]*IncidentAlert, error) {
+	query := `
+		SELECT a.id, a.tenant_id, a.source_id, s.name, a.incident_id, a.correlation_key, a.title, a.severity,
+		       a.criticality, a.fields, a.raw_payload, a.is_duplicate, a.received_at
+		FROM incident_alerts a
+		JOIN incident_alert_sources s ON s.id = a.source_id
+		WHERE a.incident_id = $1
+		ORDER BY a.received_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []*IncidentAlert
+	for rows.Next() {
+		var alert IncidentAlert
+		var fields []byte
+		err := rows.Scan(
+			&alert.ID, &alert.TenantID, &alert.SourceID, &alert.SourceName, &alert.IncidentID,
+			&alert.CorrelationKey, &alert.Title, &alert.Severity, &alert.Criticality, &fields,
+			&alert.RawPayload, &alert.IsDuplicate, &alert.ReceivedAt)
+		if err != nil {
+			return nil, err
+		}
+		if err := unmarshalStringMap(fields, &alert.Fields); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, &alert)
+	}
+
+	return alerts, rows.Err()
+}
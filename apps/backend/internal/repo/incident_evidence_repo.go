@@ -0,0 +1,220 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// IncidentEvidence is an evidence item with the SHA-256 captured at collection time
+type IncidentEvidence struct {
+	ID                     string     `json:"id"`
+	TenantID               string     `json:"tenant_id"`
+	IncidentID             string     `json:"incident_id"`
+	DocumentID             string     `json:"document_id"`
+	Title                  string     `json:"title"`
+	Description            *string    `json:"description"`
+	EvidenceType           string     `json:"evidence_type"`
+	Source                 *string    `json:"source"`
+	FileName               string     `json:"file_name"`
+	FileSize               int64      `json:"file_size"`
+	SHA256                 string     `json:"sha256"`
+	CollectedBy            *string    `json:"collected_by"`
+	CollectedAt            time.Time  `json:"collected_at"`
+	CustodianID            *string    `json:"custodian_id"`
+	IsSealed               bool       `json:"is_sealed"`
+	SealedBy               *string    `json:"sealed_by"`
+	SealedAt               *time.Time `json:"sealed_at"`
+	LastVerifiedAt         *time.Time `json:"last_verified_at"`
+	LastVerificationStatus *string    `json:"last_verification_status"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
+
+// IncidentEvidenceCustody is an append-only chain of custody entry
+type IncidentEvidenceCustody struct {
+	ID              string    `json:"id"`
+	EvidenceID      string    `json:"evidence_id"`
+	Action          string    `json:"action"`
+	PerformedBy     *string   `json:"performed_by"`
+	FromCustodianID *string   `json:"from_custodian_id"`
+	ToCustodianID   *string   `json:"to_custodian_id"`
+	SHA256          *string   `json:"sha256"`
+	Details         *string   `json:"details"`
+	IPAddress       *string   `json:"ip_address"`
+	PerformedAt     time.Time `json:"performed_at"`
+}
+
+const incidentEvidenceColumns = `id, tenant_id, incident_id, document_id, title, description, evidence_type, source,
+	file_name, file_size, sha256, collected_by, collected_at, custodian_id, is_sealed, sealed_by, sealed_at,
+	last_verified_at, last_verification_status, created_at, updated_at`
+
+func scanIncidentEvidence(row rowScanner) (*IncidentEvidence, error) {
+	var evidence IncidentEvidence
+	err := row.Scan(
+		&evidence.ID, &evidence.TenantID, &evidence.IncidentID, &evidence.DocumentID, &evidence.Title,
+		&evidence.Description, &evidence.EvidenceType, &evidence.Source, &evidence.FileName, &evidence.FileSize,
+		&evidence.SHA256, &evidence.CollectedBy, &evidence.CollectedAt, &evidence.CustodianID, &evidence.IsSealed,
+		&evidence.SealedBy, &evidence.SealedAt, &evidence.LastVerifiedAt, &evidence.LastVerificationStatus,
+		&evidence.CreatedAt, &evidence.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &evidence, nil
+}
+
+func (r *incidentRepository) ListEvidence(ctx context.Context, incidentID string) ([]*IncidentEvidence, error) {
+	query := `SELECT ` + incidentEvidenceColumns + ` FROM incident_evidence WHERE incident_id = $1 ORDER BY collected_at`
+
+	rows, err := r.db.QueryContext(ctx, query, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*IncidentEvidence
+	for rows.Next() {
+		evidence, err := scanIncidentEvidence(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, evidence)
+	}
+
+	return items, rows.Err()
+}
+
+// GetEvidence returns nil if the incident has no such evidence item
+func (r *incidentRepository) GetEvidence(ctx context.Context, id, incidentID string) (*IncidentEvidence, error) {
+	query := `SELECT ` + incidentEvidenceColumns + ` FROM incident_evidence WHERE id = $1 AND incident_id = $2`
+
+	evidence, err := scanIncidentEvidence(r.db.QueryRowContext(ctx, query, id, incidentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return evidence, nil
+}
+
+// IsEvidenceDocument reports whether the document backs an evidence item
+func (r *incidentRepository) IsEvidenceDocument(ctx context.Context, documentID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM incident_evidence WHERE document_id = $1)`, documentID).Scan(&exists)
+	return exists, err
+}
+
+// CreateEvidence stores the evidence item together with its first custody entry
+func (r *incidentRepository) CreateEvidence(ctx context.Context, evidence *IncidentEvidence, entry *IncidentEvidenceCustody) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_evidence (id, tenant_id, incident_id, document_id, title, description, evidence_type,
+		                               source, file_name, file_size, sha256, collected_by, collected_at, custodian_id,
+		                               is_sealed, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		evidence.ID, evidence.TenantID, evidence.IncidentID, evidence.DocumentID, evidence.Title,
+		evidence.Description, evidence.EvidenceType, evidence.Source, evidence.FileName, evidence.FileSize,
+		evidence.SHA256, evidence.CollectedBy, evidence.CollectedAt, evidence.CustodianID, evidence.IsSealed,
+		evidence.CreatedAt, evidence.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertEvidenceCustody(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateEvidence saves the mutable fields and appends the custody entry in one transaction;
+// the database rejects changes to sealed items
+func (r *incidentRepository) UpdateEvidence(ctx context.Context, evidence *IncidentEvidence, entry *IncidentEvidenceCustody) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE incident_evidence
+		SET title = $1, description = $2, evidence_type = $3, source = $4, custodian_id = $5, is_sealed = $6,
+		    sealed_by = $7, sealed_at = $8, last_verified_at = $9, last_verification_status = $10, updated_at = $11
+		WHERE id = $12
+	`
+	_, err = tx.ExecContext(ctx, query,
+		evidence.Title, evidence.Description, evidence.EvidenceType, evidence.Source, evidence.CustodianID,
+		evidence.IsSealed, evidence.SealedBy, evidence.SealedAt, evidence.LastVerifiedAt,
+		evidence.LastVerificationStatus, evidence.UpdatedAt, evidence.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertEvidenceCustody(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddEvidenceCustody appends an entry without changing the evidence item
+func (r *incidentRepository) AddEvidenceCustody(ctx context.Context, entry *IncidentEvidenceCustody) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertEvidenceCustody(ctx, tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertEvidenceCustody(ctx context.Context, tx *sql.Tx, entry *IncidentEvidenceCustody) error {
+	query := `
+		INSERT INTO incident_evidence_custody (id, evidence_id, action, performed_by, from_custodian_id,
+		                                       to_custodian_id, sha256, details, ip_address, performed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		entry.ID, entry.EvidenceID, entry.Action, entry.PerformedBy, entry.FromCustodianID,
+		entry.ToCustodianID, entry.SHA256, entry.Details, entry.IPAddress, entry.PerformedAt)
+	return err
+}
+
+func (r *incidentRepository) GetEvidenceCustody(ctx context.Context, evidenceID string) ([]*IncidentEvidenceCustody, error) {
+	query := `
+		SELECT id, evidence_id, action, performed_by, from_custodian_id, to_custodian_id, sha256, details,
+		       ip_address, performed_at
+		FROM incident_evidence_custody
+		WHERE evidence_id = $1
+		ORDER BY performed_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, evidenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*IncidentEvidenceCustody
+	for rows.Next() {
+		var entry IncidentEvidenceCustody
+		err := rows.Scan(&entry.ID, &entry.EvidenceID, &entry.Action, &entry.PerformedBy, &entry.FromCustodianID,
+			&entry.ToCustodianID, &entry.SHA256, &entry.Details, &entry.IPAddress, &entry.PerformedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
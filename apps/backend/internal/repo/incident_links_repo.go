@@ -0,0 +1,397 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// IncidentLink is a symmetric "related to" link; IncidentID is always the smaller of the two ids
+type IncidentLink struct {
+	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	IncidentID        string    `json:"incident_id"`
+	RelatedIncidentID string    `json:"related_incident_id"`
+	Comment           *string   `json:"comment"`
+	CreatedBy         *string   `json:"created_by"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// IncidentMerge records a duplicate merged into a primary incident together with the moved rows,
+// so that the merge can be reverted
+type IncidentMerge struct {
+	ID                 string     `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	PrimaryIncidentID  string     `json:"primary_incident_id"`
+	MergedIncidentID   string     `json:"merged_incident_id"`
+	Reason             *string    `json:"reason"`
+	PreviousStatus     string     `json:"previous_status"`
+	PreviousClosedAt   *time.Time `json:"previous_closed_at"`
+	PreviousResolution *string    `json:"previous_resolution"`
+	CommentIDs         []string   `json:"comment_ids"`
+	ActionIDs          []string   `json:"action_ids"`
+	AssetLinkIDs       []string   `json:"asset_link_ids"`
+	RiskLinkIDs        []string   `json:"risk_link_ids"`
+	DocumentLinkIDs    []string   `json:"document_link_ids"`
+	MergedBy           *string    `json:"merged_by"`
+	MergedAt           time.Time  `json:"merged_at"`
+	RevertedBy         *string    `json:"reverted_by"`
+	RevertedAt         *time.Time `json:"reverted_at"`
+}
+
+// Hierarchy
+
+// SetParent sets or clears (nil) the parent incident
+func (r *incidentRepository) SetParent(ctx context.Context, incidentID, tenantID string, parentID *string) error {
+	query := `UPDATE incidents SET parent_id = $1, updated_at = $2 WHERE id = $3 AND tenant_id = $4`
+	_, err := r.db.ExecContext(ctx, query, parentID, time.Now(), incidentID, tenantID)
+	return err
+}
+
+func (r *incidentRepository) ListChildIncidents(ctx context.Context, parentID, tenantID string) ([]*Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE parent_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		ORDER BY detected_at
+	`
+	return r.queryIncidents(ctx, query, parentID, tenantID)
+}
+
+// ListMergedIncidents returns the duplicates currently merged into the incident
+func (r *incidentRepository) ListMergedIncidents(ctx context.Context, primaryID, tenantID string) ([]*Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE merged_into_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+		ORDER BY detected_at
+	`
+	return r.queryIncidents(ctx, query, primaryID, tenantID)
+}
+
+func (r *incidentRepository) queryIncidents(ctx context.Context, query string, args ...interface{}) ([]*Incident, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []*Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+// Related links
+
+const incidentLinkColumns = `id, tenant_id, incident_id, related_incident_id, comment, created_by, created_at`
+
+func scanIncidentLink(row rowScanner) (*IncidentLink, error) {
+	var link IncidentLink
+	err := row.Scan(&link.ID, &link.TenantID, &link.IncidentID, &link.RelatedIncidentID,
+		&link.Comment, &link.CreatedBy, &link.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListLinks returns the links in which the incident takes part on either side
+func (r *incidentRepository) ListLinks(ctx context.Context, incidentID string) ([]*IncidentLink, error) {
+	query := `
+		SELECT ` + incidentLinkColumns + `
+		FROM incident_links
+		WHERE incident_id = $1 OR related_incident_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*IncidentLink
+	for rows.Next() {
+		link, err := scanIncidentLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+func (r *incidentRepository) GetLink(ctx context.Context, linkID string) (*IncidentLink, error) {
+	query := `SELECT ` + incidentLinkColumns + ` FROM incident_links WHERE id = $1`
+
+	link, err := scanIncidentLink(r.db.QueryRowContext(ctx, query, linkID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+func (r *incidentRepository) CreateLink(ctx context.Context, link *IncidentLink) error {
+	if link.ID == "" {
+		link.ID = uuid.New().String()
+	}
+	query := `
+		INSERT INTO incident_links (id, tenant_id, incident_id, related_incident_id, comment, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.ExecContext(ctx, query, link.ID, link.TenantID, link.IncidentID, link.RelatedIncidentID,
+		link.Comment, link.CreatedBy, link.CreatedAt)
+	return err
+}
+
+func (r *incidentRepository) DeleteLink(ctx context.Context, linkID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM incident_links WHERE id = $1`, linkID)
+	return err
+}
+
+// Merges
+
+const incidentMergeColumns = `id, tenant_id, primary_incident_id, merged_incident_id, reason, previous_status,
+		       previous_closed_at, previous_resolution, comment_ids, action_ids, asset_link_ids, risk_link_ids,
+		       document_link_ids, merged_by, merged_at, reverted_by, reverted_at`
+
+func scanIncidentMerge(row rowScanner) (*IncidentMerge, error) {
+	var merge IncidentMerge
+	err := row.Scan(
+		&merge.ID, &merge.TenantID, &merge.PrimaryIncidentID, &merge.MergedIncidentID, &merge.Reason,
+		&merge.PreviousStatus, &merge.PreviousClosedAt, &merge.PreviousResolution,
+		pq.Array(&merge.CommentIDs), pq.Array(&merge.ActionIDs), pq.Array(&merge.AssetLinkIDs),
+		pq.Array(&merge.RiskLinkIDs), pq.Array(&merge.DocumentLinkIDs),
+		&merge.MergedBy, &merge.MergedAt, &merge.RevertedBy, &merge.RevertedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &merge, nil
+}
+
+func (r *incidentRepository) GetMerge(ctx context.Context, mergeID, tenantID string) (*IncidentMerge, error) {
+	query := `SELECT ` + incidentMergeColumns + ` FROM incident_merges WHERE id = $1 AND tenant_id = $2`
+
+	merge, err := scanIncidentMerge(r.db.QueryRowContext(ctx, query, mergeID, tenantID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return merge, nil
+}
+
+// ListMerges returns the merges in which the incident was the primary or the duplicate, newest first
+func (r *incidentRepository) ListMerges(ctx context.Context, incidentID string) ([]*IncidentMerge, error) {
+	query := `
+		SELECT ` + incidentMergeColumns + `
+		FROM incident_merges
+		WHERE primary_incident_id = $1 OR merged_incident_id = $1
+		ORDER BY merged_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var merges []*IncidentMerge
+	for rows.Next() {
+		merge, err := scanIncidentMerge(rows)
+		if err != nil {
+			return nil, err
+		}
+		merges = append(merges, merge)
+	}
+
+	return merges, rows.Err()
+}
+
+// MergeIncident moves comments, actions, asset, risk and document links of the duplicate into the primary
+// incident, saves the duplicate (closed by the caller) with its timeline entry and records the merge.
+// Links the primary incident already has, playbook steps it already has and evidence documents stay with the duplicate.
+func (r *incidentRepository) MergeIncident(ctx context.Context, merge *IncidentMerge, duplicate *Incident, change *IncidentStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	primaryID, duplicateID := merge.PrimaryIncidentID, merge.MergedIncidentID
+
+	merge.CommentIDs, err = collectMovedIDs(ctx, tx, `
+		UPDATE incident_comments SET incident_id = $1
+		WHERE incident_id = $2
+		RETURNING id`, primaryID, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	merge.ActionIDs, err = collectMovedIDs(ctx, tx, `
+		UPDATE incident_actions a SET incident_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE a.incident_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM incident_actions p
+			WHERE p.incident_id = $1 AND p.playbook_id = a.playbook_id AND p.step_order = a.step_order)
+		RETURNING a.id`, primaryID, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	merge.AssetLinkIDs, err = collectMovedIDs(ctx, tx, `
+		UPDATE incident_assets a SET incident_id = $1
+		WHERE a.incident_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM incident_assets p WHERE p.incident_id = $1 AND p.asset_id = a.asset_id)
+		RETURNING a.id`, primaryID, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	merge.RiskLinkIDs, err = collectMovedIDs(ctx, tx, `
+		UPDATE incident_risks r SET incident_id = $1
+		WHERE r.incident_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM incident_risks p WHERE p.incident_id = $1 AND p.risk_id = r.risk_id)
+		RETURNING r.id`, primaryID, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	merge.DocumentLinkIDs, err = collectMovedIDs(ctx, tx, `
+		UPDATE document_links l SET entity_id = $1
+		WHERE l.module = 'incidents' AND l.entity_id = $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM document_links p
+			WHERE p.module = 'incidents' AND p.entity_id = $1 AND p.document_id = l.document_id)
+		  AND NOT EXISTS (SELECT 1 FROM incident_evidence e WHERE e.document_id = l.document_id)
+		RETURNING l.id`, primaryID, duplicateID)
+	if err != nil {
+		return err
+	}
+
+	if err := saveMergedIncident(ctx, tx, duplicate, change); err != nil {
+		return err
+	}
+
+	if merge.ID == "" {
+		merge.ID = uuid.New().String()
+	}
+	query := `
+		INSERT INTO incident_merges (id, tenant_id, primary_incident_id, merged_incident_id, reason, previous_status,
+		                             previous_closed_at, previous_resolution, comment_ids, action_ids, asset_link_ids,
+		                             risk_link_ids, document_link_ids, merged_by, merged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		merge.ID, merge.TenantID, primaryID, duplicateID, merge.Reason, merge.PreviousStatus,
+		merge.PreviousClosedAt, merge.PreviousResolution, pq.Array(merge.CommentIDs), pq.Array(merge.ActionIDs),
+		pq.Array(merge.AssetLinkIDs), pq.Array(merge.RiskLinkIDs), pq.Array(merge.DocumentLinkIDs),
+		merge.MergedBy, merge.MergedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevertMerge moves the recorded rows back to the duplicate, restores it (prepared by the caller)
+// and marks the merge as reverted. Rows that were moved on from the primary incident since are left alone.
+func (r *incidentRepository) RevertMerge(ctx context.Context, merge *IncidentMerge, duplicate *Incident, change *IncidentStatusChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	primaryID, duplicateID := merge.PrimaryIncidentID, merge.MergedIncidentID
+
+	moves := []struct {
+		query string
+		ids   []string
+	}{
+		{`UPDATE incident_comments SET incident_id = $1 WHERE incident_id = $2 AND id = ANY($3)`, merge.CommentIDs},
+		{`UPDATE incident_actions a SET incident_id = $1, updated_at = CURRENT_TIMESTAMP
+		  WHERE a.incident_id = $2 AND a.id = ANY($3) AND NOT EXISTS (
+			SELECT 1 FROM incident_actions d
+			WHERE d.incident_id = $1 AND d.playbook_id = a.playbook_id AND d.step_order = a.step_order)`, merge.ActionIDs},
+		{`UPDATE incident_assets a SET incident_id = $1
+		  WHERE a.incident_id = $2 AND a.id = ANY($3) AND NOT EXISTS (
+			SELECT 1 FROM incident_assets d WHERE d.incident_id = $1 AND d.asset_id = a.asset_id)`, merge.AssetLinkIDs},
+		{`UPDATE incident_risks r SET incident_id = $1
+		  WHERE r.incident_id = $2 AND r.id = ANY($3) AND NOT EXISTS (
+			SELECT 1 FROM incident_risks d WHERE d.incident_id = $1 AND d.risk_id = r.risk_id)`, merge.RiskLinkIDs},
+		{`UPDATE document_links l SET entity_id = $1
+		  WHERE l.module = 'incidents' AND l.entity_id = $2 AND l.id = ANY($3) AND NOT EXISTS (
+			SELECT 1 FROM document_links d
+			WHERE d.module = 'incidents' AND d.entity_id = $1 AND d.document_id = l.document_id)`, merge.DocumentLinkIDs},
+	}
+	for _, move := range moves {
+		if len(move.ids) == 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, move.query, duplicateID, primaryID, pq.Array(move.ids)); err != nil {
+			return err
+		}
+	}
+
+	if err := saveMergedIncident(ctx, tx, duplicate, change); err != nil {
+		return err
+	}
+
+	query := `UPDATE incident_merges SET reverted_by = $1, reverted_at = $2 WHERE id = $3 AND reverted_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, merge.RevertedBy, merge.RevertedAt, merge.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// saveMergedIncident writes the duplicate's columns including merged_into_id and the optional timeline entry
+func saveMergedIncident(ctx context.Context, tx *sql.Tx, incident *Incident, change *IncidentStatusChange) error {
+	if _, err := tx.ExecContext(ctx, updateIncidentQuery, updateIncidentArgs(incident)...); err != nil {
+		return err
+	}
+
+	query := `UPDATE incidents SET merged_into_id = $1 WHERE id = $2 AND tenant_id = $3`
+	if _, err := tx.ExecContext(ctx, query, incident.MergedIntoID, incident.ID, incident.TenantID); err != nil {
+		return err
+	}
+
+	if change == nil {
+		return nil
+	}
+	return insertStatusChange(ctx, tx, change)
+}
+
+func collectMovedIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
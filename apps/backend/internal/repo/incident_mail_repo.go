@@ -0,0 +1,270 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// IncidentMailbox is a POP3 mailbox whose messages are turned into incidents
+type IncidentMailbox struct {
+	ID                 string     `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	Name               string     `json:"name"`
+	Host               string     `json:"host"`
+	Port               int        `json:"port"`
+	UseTLS             bool       `json:"use_tls"`
+	Username           string     `json:"username"`
+	Password           string     `json:"-"` // encrypted, see domain secretCipher
+	DefaultCategory    string     `json:"default_category"`
+	DefaultCriticality string     `json:"default_criticality"`
+	AssignedTo         *string    `json:"assigned_to"`
+	FallbackReporterID *string    `json:"fallback_reporter_id"`
+	DeleteAfterImport  bool       `json:"delete_after_import"`
+	IsActive           bool       `json:"is_active"`
+	LastPolledAt       *time.Time `json:"last_polled_at"`
+	LastError          *string    `json:"last_error"`
+	CreatedBy          *string    `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// IncidentMailMessage records how a message from a mailbox was processed
+type IncidentMailMessage struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id"`
+	MailboxID   string     `json:"mailbox_id"`
+	UIDL        string     `json:"uidl"`
+	MessageID   *string    `json:"message_id"`
+	InReplyTo   *string    `json:"in_reply_to"`
+	FromAddress string     `json:"from_address"`
+	Subject     *string    `json:"subject"`
+	Status      string     `json:"status"`
+	IncidentID  *string    `json:"incident_id"`
+	CommentID   *string    `json:"comment_id"`
+	Attachments int        `json:"attachments"`
+	Error       *string    `json:"error"`
+	SentAt      *time.Time `json:"sent_at"`
+	ProcessedAt time.Time  `json:"processed_at"`
+}
+
+// Mailboxes
+const mailboxColumns = `id, tenant_id, name, host, port, use_tls, username, password, default_category,
+	default_criticality, assigned_to, fallback_reporter_id, delete_after_import, is_active, last_polled_at,
+	last_error, created_by, created_at, updated_at`
+
+func scanMailbox(row rowScanner) (*IncidentMailbox, error) {
+	var mailbox IncidentMailbox
+	err := row.Scan(
+		&mailbox.ID, &mailbox.TenantID, &mailbox.Name, &mailbox.Host, &mailbox.Port, &mailbox.UseTLS,
+		&mailbox.Username, &mailbox.Password, &mailbox.DefaultCategory, &mailbox.DefaultCriticality,
+		&mailbox.AssignedTo, &mailbox.FallbackReporterID, &mailbox.DeleteAfterImport, &mailbox.IsActive,
+		&mailbox.LastPolledAt, &mailbox.LastError, &mailbox.CreatedBy, &mailbox.CreatedAt, &mailbox.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &mailbox, nil
+}
+
+func (r *incidentRepository) queryMailboxes(ctx context.Context, query string, args ...interface{}) ([]*IncidentMailbox, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mailboxes []*IncidentMailbox
+	for rows.Next() {
+		mailbox, err := scanMailbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		mailboxes = append(mailboxes, mailbox)
+	}
+
+	return mailboxes, rows.Err()
+}
+
+func (r *incidentRepository) ListMailboxes(ctx context.Context, tenantID string) ([]*IncidentMailbox, error) {
+	query := `SELECT ` + mailboxColumns + ` FROM incident_mailboxes WHERE tenant_id = $1 ORDER BY name`
+	return r.queryMailboxes(ctx, query, tenantID)
+}
+
+// ListActiveMailboxes returns the active mailboxes of all tenants for the poller
+func (r *incidentRepository) ListActiveMailboxes(ctx context.Context) ([]*IncidentMailbox, error) {
+	query := `SELECT ` + mailboxColumns + ` FROM incident_mailboxes WHERE is_active = true ORDER BY tenant_id, name`
+	return r.queryMailboxes(ctx, query)
+}
+
+// GetMailbox returns nil if the tenant has no such mailbox
+func (r *incidentRepository) GetMailbox(ctx context.Context, id, tenantID string) (*IncidentMailbox, error) {
+	query := `SELECT ` + mailboxColumns + ` FROM incident_mailboxes WHERE id = $1 AND tenant_id = $2`
+
+	mailbox, err := scanMailbox(r.db.QueryRowContext(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mailbox, nil
+}
+
+// SaveMailbox inserts or updates a mailbox
+func (r *incidentRepository) SaveMailbox(ctx context.Context, mailbox *IncidentMailbox) error {
+	query := `
+		INSERT INTO incident_mailboxes (id, tenant_id, name, host, port, use_tls, username, password, default_category,
+		                                default_criticality, assigned_to, fallback_reporter_id, delete_after_import,
+		                                is_active, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, host = EXCLUDED.host, port = EXCLUDED.port, use_tls = EXCLUDED.use_tls,
+		    username = EXCLUDED.username, password = EXCLUDED.password, default_category = EXCLUDED.default_category,
+		    default_criticality = EXCLUDED.default_criticality, assigned_to = EXCLUDED.assigned_to,
+		    fallback_reporter_id = EXCLUDED.fallback_reporter_id, delete_after_import = EXCLUDED.delete_after_import,
+		    is_active = EXCLUDED.is_active, updated_at = EXCLUDED.updated_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		mailbox.ID, mailbox.TenantID, mailbox.Name, mailbox.Host, mailbox.Port, mailbox.UseTLS, mailbox.Username,
+		mailbox.Password, mailbox.DefaultCategory, mailbox.DefaultCriticality, mailbox.AssignedTo,
+		mailbox.FallbackReporterID, mailbox.DeleteAfterImport, mailbox.IsActive, mailbox.CreatedBy,
+		mailbox.CreatedAt, mailbox.UpdatedAt)
+	return err
+}
+
+func (r *incidentRepository) DeleteMailbox(ctx context.Context, id, tenantID string) error {
+	query := `DELETE FROM incident_mailboxes WHERE id = $1 AND tenant_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, tenantID)
+	return err
+}
+
+// ListPlaintextPasswordMailboxes returns mailboxes of all tenants whose password was saved before encryption
+func (r *incidentRepository) ListPlaintextPasswordMailboxes(ctx context.Context) ([]*IncidentMailbox, error) {
+	query := `SELECT ` + mailboxColumns + ` FROM incident_mailboxes WHERE password NOT LIKE 'enc:v1:%'`
+	return r.queryMailboxes(ctx, query)
+}
+
+// SetMailboxPollResult stores the time of the last poll and its error, nil on success
+func (r *incidentRepository) SetMailboxPollResult(ctx context.Context, id string, polledAt time.Time, lastError *string) error {
+	query := `UPDATE incident_mailboxes SET last_polled_at = $1, last_error = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, polledAt, lastError, id)
+	return err
+}
+
+// Messages
+
+// ListProcessedMailUIDLs returns which of the given server ids were already processed for the mailbox
+func (r *incidentRepository) ListProcessedMailUIDLs(ctx context.Context, mailboxID string, uidls []string) (map[string]bool, error) {
+	query := `SELECT uidl FROM incident_mail_messages WHERE mailbox_id = $1 AND uidl = ANY($2)`
+
+	rows, err := r.db.QueryContext(ctx, query, mailboxID, pq.Array(uidls))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	processed := make(map[string]bool)
+	for rows.Next() {
+		var uidl string
+		if err := rows.Scan(&uidl); err != nil {
+			return nil, err
+		}
+		processed[uidl] = true
+	}
+
+	return processed, rows.Err()
+}
+
+// FindIncidentByMailReference returns the incident created or commented by one of the referenced messages,
+// following merges to the primary incident, or an empty string if there is none
+func (r *incidentRepository) FindIncidentByMailReference(ctx context.Context, tenantID string, messageIDs []string) (string, error) {
+	query := `
+		SELECT COALESCE(i.merged_into_id, i.id)
+		FROM incident_mail_messages m
+		JOIN incidents i ON i.id = m.incident_id
+		WHERE m.tenant_id = $1 AND m.message_id = ANY($2) AND i.deleted_at IS NULL
+		ORDER BY m.processed_at
+		LIMIT 1
+	`
+
+	var incidentID string
+	err := r.db.QueryRowContext(ctx, query, tenantID, pq.Array(messageIDs)).Scan(&incidentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return incidentID, nil
+}
+
+// ClaimMailMessage records a message as being processed before an incident is created from it.
+// Returns false if the message was already claimed, e.g. by the poller of another backend instance.
+func (r *incidentRepository) ClaimMailMessage(ctx context.Context, message *IncidentMailMessage) (bool, error) {
+	query := `
+		INSERT INTO incident_mail_messages (id, tenant_id, mailbox_id, uidl, from_address, status, processed_at)
+		VALUES ($1, $2, $3, $4, $5, 'processing', $6)
+		ON CONFLICT (mailbox_id, uidl) DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		message.ID, message.TenantID, message.MailboxID, message.UIDL, message.FromAddress, message.ProcessedAt)
+	if err != nil {
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+// FinishMailMessage stores the outcome of a claimed message
+func (r *incidentRepository) FinishMailMessage(ctx context.Context, message *IncidentMailMessage) error {
+	query := `
+		UPDATE incident_mail_messages
+		SET message_id = $1, in_reply_to = $2, from_address = $3, subject = $4, status = $5, incident_id = $6,
+		    comment_id = $7, attachments = $8, error = $9, sent_at = $10, processed_at = $11
+		WHERE mailbox_id = $12 AND uidl = $13 AND status = 'processing'
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		message.MessageID, message.InReplyTo, message.FromAddress, message.Subject, message.Status, message.IncidentID,
+		message.CommentID, message.Attachments, message.Error, message.SentAt, message.ProcessedAt,
+		message.MailboxID, message.UIDL)
+	return err
+}
+
+// ListMailMessages returns the latest processed messages of a mailbox
+func (r *incidentRepository) ListMailMessages(ctx context.Context, mailboxID string, limit int) ([]*IncidentMailMessage, error) {
+	query := `
+		SELECT id, tenant_id, mailbox_id, uidl, message_id, in_reply_to, from_address, subject, status,
+		       incident_id, comment_id, attachments, error, sent_at, processed_at
+		FROM incident_mail_messages
+		WHERE mailbox_id = $1
+		ORDER BY processed_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, mailboxID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*IncidentMailMessage
+	for rows.Next() {
+		var message IncidentMailMessage
+		err := rows.Scan(
+			&message.ID, &message.TenantID, &message.MailboxID, &message.UIDL, &message.MessageID,
+			&message.InReplyTo, &message.FromAddress, &message.Subject, &message.Status, &message.IncidentID,
+			&message.CommentID, &message.Attachments, &message.Error, &message.SentAt, &message.ProcessedAt)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+
+	return messages, rows.Err()
+}
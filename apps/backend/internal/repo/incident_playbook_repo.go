@@ -0,0 +1,265 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// IncidentPlaybook is a response template for an incident category; TenantID is nil for built-in playbooks
+type IncidentPlaybook struct {
+	ID          string                  `json:"id"`
+	TenantID    *string                 `json:"tenant_id"`
+	Category    string                  `json:"category"`
+	Name        string                  `json:"name"`
+	Description *string                 `json:"description"`
+	IsActive    bool                    `json:"is_active"`
+	Steps       []*IncidentPlaybookStep `json:"steps"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// IncidentPlaybookStep becomes one incident action when the playbook is applied
+type IncidentPlaybookStep struct {
+	ID             string  `json:"id"`
+	PlaybookID     string  `json:"playbook_id"`
+	StepOrder      int     `json:"step_order"`
+	ActionType     string  `json:"action_type"`
+	Title          string  `json:"title"`
+	Description    *string `json:"description"`
+	AssigneeRole   *string `json:"assignee_role"`
+	DueOffsetHours *int    `json:"due_offset_hours"`
+}
+
+const incidentPlaybookColumns = `id, tenant_id, category, name, description, is_active, created_at, updated_at`
+
+func scanIncidentPlaybook(row rowScanner) (*IncidentPlaybook, error) {
+	var playbook IncidentPlaybook
+	err := row.Scan(
+		&playbook.ID, &playbook.TenantID, &playbook.Category, &playbook.Name, &playbook.Description,
+		&playbook.IsActive, &playbook.CreatedAt, &playbook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &playbook, nil
+}
+
+// ListPlaybooks returns the tenant's own playbooks together with the built-in ones, with steps
+func (r *incidentRepository) ListPlaybooks(ctx context.Context, tenantID string) ([]*IncidentPlaybook, error) {
+	query := `
+		SELECT ` + incidentPlaybookColumns + `
+		FROM incident_playbooks
+		WHERE tenant_id = $1 OR tenant_id IS NULL
+		ORDER BY category, tenant_id NULLS LAST
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var playbooks []*IncidentPlaybook
+	for rows.Next() {
+		playbook, err := scanIncidentPlaybook(rows)
+		if err != nil {
+			return nil, err
+		}
+		playbooks = append(playbooks, playbook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, playbook := range playbooks {
+		if playbook.Steps, err = r.getPlaybookSteps(ctx, playbook.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return playbooks, nil
+}
+
+// GetPlaybookForCategory returns the tenant's playbook for the category, falling back to the built-in one.
+// Returns nil if neither exists.
+func (r *incidentRepository) GetPlaybookForCategory(ctx context.Context, tenantID, category string) (*IncidentPlaybook, error) {
+	query := `
+		SELECT ` + incidentPlaybookColumns + `
+		FROM incident_playbooks
+		WHERE (tenant_id = $1 OR tenant_id IS NULL) AND category = $2
+		ORDER BY tenant_id NULLS LAST
+		LIMIT 1
+	`
+
+	playbook, err := scanIncidentPlaybook(r.db.QueryRowContext(ctx, query, tenantID, category))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if playbook.Steps, err = r.getPlaybookSteps(ctx, playbook.ID); err != nil {
+		return nil, err
+	}
+	return playbook, nil
+}
+
+func (r *incidentRepository) getPlaybookSteps(ctx context.Context, playbookID string) ([]*IncidentPlaybookStep, error) {
+	query := `
+		SELECT id, playbook_id, step_order, action_type, title, description, assignee_role, due_offset_hours
+		FROM incident_playbook_steps
+		WHERE playbook_id = $1
+		ORDER BY step_order
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, playbookID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var steps []*IncidentPlaybookStep
+	for rows.Next() {
+		var step IncidentPlaybookStep
+		err := rows.Scan(
+			&step.ID, &step.PlaybookID, &step.StepOrder, &step.ActionType, &step.Title,
+			&step.Description, &step.AssigneeRole, &step.DueOffsetHours)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, &step)
+	}
+
+	return steps, rows.Err()
+}
+
+// SavePlaybook creates or replaces the tenant's playbook for its category together with all steps.
+// The playbook keeps its ID so actions already generated from it stay linked.
+func (r *incidentRepository) SavePlaybook(ctx context.Context, playbook *IncidentPlaybook) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_playbooks (id, tenant_id, category, name, description, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), category) DO UPDATE
+		SET name = EXCLUDED.name, description = EXCLUDED.description, is_active = EXCLUDED.is_active,
+		    updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(ctx, query,
+		playbook.ID, playbook.TenantID, playbook.Category, playbook.Name, playbook.Description,
+		playbook.IsActive, playbook.CreatedAt, playbook.UpdatedAt).Scan(&playbook.ID, &playbook.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM incident_playbook_steps WHERE playbook_id = $1`, playbook.ID); err != nil {
+		return err
+	}
+
+	for _, step := range playbook.Steps {
+		step.PlaybookID = playbook.ID
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO incident_playbook_steps (id, playbook_id, step_order, action_type, title, description,
+			                                     assignee_role, due_offset_hours)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, step.ID, step.PlaybookID, step.StepOrder, step.ActionType, step.Title, step.Description,
+			step.AssigneeRole, step.DueOffsetHours)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeletePlaybook removes the tenant's own playbook for the category; the built-in one applies again
+func (r *incidentRepository) DeletePlaybook(ctx context.Context, tenantID, category string) error {
+	query := `DELETE FROM incident_playbooks WHERE tenant_id = $1 AND category = $2`
+	_, err := r.db.ExecContext(ctx, query, tenantID, category)
+	return err
+}
+
+// AddPlaybookActions inserts generated actions, skipping steps the incident already has.
+// Returns the number of actions actually created.
+func (r *incidentRepository) AddPlaybookActions(ctx context.Context, actions []*IncidentAction) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_actions (id, incident_id, action_type, title, description, assigned_to, due_date, status,
+		                              created_by, created_at, updated_at, playbook_id, step_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (incident_id, playbook_id, step_order) DO NOTHING
+	`
+
+	created := 0
+	for _, action := range actions {
+		result, err := tx.ExecContext(ctx, query,
+			action.ID, action.IncidentID, action.ActionType, action.Title, action.Description,
+			action.AssignedTo, action.DueDate, action.Status, action.CreatedBy,
+			action.CreatedAt, action.UpdatedAt, action.PlaybookID, action.StepOrder)
+		if err != nil {
+			return 0, err
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			created++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// CancelPendingPlaybookActions cancels not yet started steps of playbooks other than keepPlaybookID,
+// e.g. after the incident was moved to another category
+func (r *incidentRepository) CancelPendingPlaybookActions(ctx context.Context, incidentID, keepPlaybookID string) (int64, error) {
+	query := `
+		UPDATE incident_actions
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE incident_id = $1 AND playbook_id IS NOT NULL AND playbook_id::text <> $2 AND status = 'pending'
+	`
+	result, err := r.db.ExecContext(ctx, query, incidentID, keepPlaybookID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// FindUserByRoleName picks an active tenant user holding the role: the preferred user if they hold it,
+// otherwise the one with the fewest open incident actions. Returns nil if nobody holds the role.
+func (r *incidentRepository) FindUserByRoleName(ctx context.Context, tenantID, roleName string, preferredUserID *string) (*string, error) {
+	query := `
+		SELECT u.id
+		FROM users u
+		JOIN user_roles ur ON ur.user_id = u.id
+		JOIN roles ro ON ro.id = ur.role_id
+		WHERE ro.tenant_id = $1 AND LOWER(ro.name) = LOWER($2) AND u.is_active = true
+		ORDER BY (u.id::text = COALESCE($3, '')) DESC,
+		         (SELECT COUNT(*) FROM incident_actions a
+		          WHERE a.assigned_to = u.id AND a.status IN ('pending', 'in_progress')) ASC,
+		         u.created_at ASC
+		LIMIT 1
+	`
+
+	var userID string
+	err := r.db.QueryRowContext(ctx, query, tenantID, roleName, preferredUserID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &userID, nil
+}
@@ -0,0 +1,391 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// IncidentRegulatoryProfile is a set of notification obligations the tenant is subject to (GDPR, 152-FZ, GosSOPKA)
+type IncidentRegulatoryProfile struct {
+	ID          string                          `json:"id"`
+	TenantID    string                          `json:"tenant_id"`
+	Name        string                          `json:"name"`
+	Framework   string                          `json:"framework"`
+	Categories  []string                        `json:"categories"`
+	IsActive    bool                            `json:"is_active"`
+	Obligations []*IncidentRegulatoryObligation `json:"obligations"`
+	CreatedAt   time.Time                       `json:"created_at"`
+	UpdatedAt   time.Time                       `json:"updated_at"`
+}
+
+// IncidentRegulatoryObligation is one notification that must be sent within DeadlineHours of detection
+type IncidentRegulatoryObligation struct {
+	ID            string  `json:"id"`
+	ProfileID     string  `json:"profile_id"`
+	ProfileName   string  `json:"profile_name"`
+	Position      int     `json:"position"`
+	Title         string  `json:"title"`
+	RecipientType string  `json:"recipient_type"`
+	Recipient     string  `json:"recipient"`
+	DeadlineHours int     `json:"deadline_hours"`
+	TemplateID    *string `json:"template_id"`
+	Description   *string `json:"description"`
+}
+
+// IncidentNotification tracks a single obligation for a specific incident
+type IncidentNotification struct {
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	IncidentID      string     `json:"incident_id"`
+	IncidentTitle   string     `json:"incident_title"`
+	ObligationID    *string    `json:"obligation_id"`
+	TemplateID      *string    `json:"template_id"`
+	ProfileName     string     `json:"profile_name"`
+	Title           string     `json:"title"`
+	RecipientType   string     `json:"recipient_type"`
+	Recipient       string     `json:"recipient"`
+	DueAt           time.Time  `json:"due_at"`
+	Status          string     `json:"status"`
+	DraftContent    *string    `json:"draft_content"`
+	SentAt          *time.Time `json:"sent_at"`
+	SentBy          *string    `json:"sent_by"`
+	SentTo          *string    `json:"sent_to"`
+	Channel         *string    `json:"channel"`
+	ReferenceNumber *string    `json:"reference_number"`
+	Notes           *string    `json:"notes"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Regulatory profiles
+const regulatoryProfileColumns = `id, tenant_id, name, framework, categories, is_active, created_at, updated_at`
+
+func scanRegulatoryProfile(row rowScanner) (*IncidentRegulatoryProfile, error) {
+	var profile IncidentRegulatoryProfile
+	err := row.Scan(
+		&profile.ID, &profile.TenantID, &profile.Name, &profile.Framework, pq.Array(&profile.Categories),
+		&profile.IsActive, &profile.CreatedAt, &profile.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *incidentRepository) ListRegulatoryProfiles(ctx context.Context, tenantID string) ([]*IncidentRegulatoryProfile, error) {
+	query := `SELECT ` + regulatoryProfileColumns + ` FROM incident_regulatory_profiles WHERE tenant_id = $1 ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []*IncidentRegulatoryProfile
+	for rows.Next() {
+		profile, err := scanRegulatoryProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		if profile.Obligations, err = r.getRegulatoryObligations(ctx, profile); err != nil {
+			return nil, err
+		}
+	}
+
+	return profiles, nil
+}
+
+// GetRegulatoryProfile returns nil if the tenant has no such profile
+func (r *incidentRepository) GetRegulatoryProfile(ctx context.Context, id, tenantID string) (*IncidentRegulatoryProfile, error) {
+	query := `SELECT ` + regulatoryProfileColumns + ` FROM incident_regulatory_profiles WHERE id = $1 AND tenant_id = $2`
+
+	profile, err := scanRegulatoryProfile(r.db.QueryRowContext(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if profile.Obligations, err = r.getRegulatoryObligations(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (r *incidentRepository) getRegulatoryObligations(ctx context.Context, profile *IncidentRegulatoryProfile) ([]*IncidentRegulatoryObligation, error) {
+	query := `
+		SELECT id, profile_id, position, title, recipient_type, recipient, deadline_hours, template_id, description
+		FROM incident_regulatory_obligations
+		WHERE profile_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var obligations []*IncidentRegulatoryObligation
+	for rows.Next() {
+		obligation := IncidentRegulatoryObligation{ProfileName: profile.Name}
+		err := rows.Scan(
+			&obligation.ID, &obligation.ProfileID, &obligation.Position, &obligation.Title,
+			&obligation.RecipientType, &obligation.Recipient, &obligation.DeadlineHours,
+			&obligation.TemplateID, &obligation.Description)
+		if err != nil {
+			return nil, err
+		}
+		obligations = append(obligations, &obligation)
+	}
+
+	return obligations, rows.Err()
+}
+
+// SaveRegulatoryProfile inserts or updates a profile with its obligations. Obligations keep their IDs,
+// so notifications already created for incidents stay linked; obligations missing from the list are removed.
+func (r *incidentRepository) SaveRegulatoryProfile(ctx context.Context, profile *IncidentRegulatoryProfile) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_regulatory_profiles (id, tenant_id, name, framework, categories, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, framework = EXCLUDED.framework, categories = EXCLUDED.categories,
+		    is_active = EXCLUDED.is_active, updated_at = EXCLUDED.updated_at
+	`
+	_, err = tx.ExecContext(ctx, query,
+		profile.ID, profile.TenantID, profile.Name, profile.Framework, pq.Array(profile.Categories),
+		profile.IsActive, profile.CreatedAt, profile.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	keep := make([]string, 0, len(profile.Obligations))
+	for _, obligation := range profile.Obligations {
+		keep = append(keep, obligation.ID)
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM incident_regulatory_obligations WHERE profile_id = $1 AND NOT (id::text = ANY($2))`,
+		profile.ID, pq.Array(keep))
+	if err != nil {
+		return err
+	}
+
+	for _, obligation := range profile.Obligations {
+		obligation.ProfileID = profile.ID
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO incident_regulatory_obligations (id, profile_id, position, title, recipient_type, recipient,
+			                                             deadline_hours, template_id, description)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (id) DO UPDATE
+			SET position = EXCLUDED.position, title = EXCLUDED.title, recipient_type = EXCLUDED.recipient_type,
+			    recipient = EXCLUDED.recipient, deadline_hours = EXCLUDED.deadline_hours,
+			    template_id = EXCLUDED.template_id, description = EXCLUDED.description
+			WHERE incident_regulatory_obligations.profile_id = EXCLUDED.profile_id
+		`, obligation.ID, obligation.ProfileID, obligation.Position, obligation.Title, obligation.RecipientType,
+			obligation.Recipient, obligation.DeadlineHours, obligation.TemplateID, obligation.Description)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *incidentRepository) DeleteRegulatoryProfile(ctx context.Context, id, tenantID string) error {
+	query := `DELETE FROM incident_regulatory_profiles WHERE id = $1 AND tenant_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, tenantID)
+	return err
+}
+
+// ListApplicableObligations returns obligations of the tenant's active profiles covering the incident category
+func (r *incidentRepository) ListApplicableObligations(ctx context.Context, tenantID, category string) ([]*IncidentRegulatoryObligation, error) {
+	query := `
+		SELECT o.id, o.profile_id, p.name, o.position, o.title, o.recipient_type, o.recipient, o.deadline_hours,
+		       o.template_id, o.description
+		FROM incident_regulatory_obligations o
+		JOIN incident_regulatory_profiles p ON p.id = o.profile_id
+		WHERE p.tenant_id = $1 AND p.is_active = true AND $2 = ANY(p.categories)
+		ORDER BY p.name, o.position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID, category)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var obligations []*IncidentRegulatoryObligation
+	for rows.Next() {
+		var obligation IncidentRegulatoryObligation
+		err := rows.Scan(
+			&obligation.ID, &obligation.ProfileID, &obligation.ProfileName, &obligation.Position,
+			&obligation.Title, &obligation.RecipientType, &obligation.Recipient, &obligation.DeadlineHours,
+			&obligation.TemplateID, &obligation.Description)
+		if err != nil {
+			return nil, err
+		}
+		obligations = append(obligations, &obligation)
+	}
+
+	return obligations, rows.Err()
+}
+
+// Incident notifications
+const incidentNotificationColumns = `n.id, n.tenant_id, n.incident_id, i.title, n.obligation_id, o.template_id,
+	n.profile_name, n.title, n.recipient_type, n.recipient, n.due_at, n.status, n.draft_content, n.sent_at,
+	n.sent_by, n.sent_to, n.channel, n.reference_number, n.notes, n.created_at, n.updated_at`
+
+const incidentNotificationJoins = `
+	FROM incident_notifications n
+	JOIN incidents i ON i.id = n.incident_id
+	LEFT JOIN incident_regulatory_obligations o ON o.id = n.obligation_id`
+
+func scanIncidentNotification(row rowScanner) (*IncidentNotification, error) {
+	var notification IncidentNotification
+	err := row.Scan(
+		&notification.ID, &notification.TenantID, &notification.IncidentID, &notification.IncidentTitle,
+		&notification.ObligationID, &notification.TemplateID, &notification.ProfileName, &notification.Title,
+		&notification.RecipientType, &notification.Recipient, &notification.DueAt, &notification.Status,
+		&notification.DraftContent, &notification.SentAt, &notification.SentBy, &notification.SentTo,
+		&notification.Channel, &notification.ReferenceNumber, &notification.Notes,
+		&notification.CreatedAt, &notification.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *incidentRepository) queryIncidentNotifications(ctx context.Context, query string, args ...interface{}) ([]*IncidentNotification, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*IncidentNotification
+	for rows.Next() {
+		notification, err := scanIncidentNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *incidentRepository) ListIncidentNotifications(ctx context.Context, incidentID string) ([]*IncidentNotification, error) {
+	query := `SELECT ` + incidentNotificationColumns + incidentNotificationJoins + `
+		WHERE n.incident_id = $1
+		ORDER BY n.due_at, n.title`
+	return r.queryIncidentNotifications(ctx, query, incidentID)
+}
+
+// ListOverdueNotifications returns unsent notifications of the tenant whose deadline has passed
+func (r *incidentRepository) ListOverdueNotifications(ctx context.Context, tenantID string, now time.Time) ([]*IncidentNotification, error) {
+	query := `SELECT ` + incidentNotificationColumns + incidentNotificationJoins + `
+		WHERE n.tenant_id = $1 AND n.status IN ('pending', 'draft') AND n.due_at < $2
+		ORDER BY n.due_at`
+	return r.queryIncidentNotifications(ctx, query, tenantID, now)
+}
+
+// ListNotificationsForDeadlineAlerts returns unsent notifications of all tenants that reached a deadline
+// alert stage without being alerted: the last quarter of the deadline (due soon) or the deadline itself (overdue)
+func (r *incidentRepository) ListNotificationsForDeadlineAlerts(ctx context.Context, now time.Time) ([]*IncidentNotification, error) {
+	query := `SELECT ` + incidentNotificationColumns + incidentNotificationJoins + `
+		WHERE n.status IN ('pending', 'draft')
+		  AND ((n.due_at < $1 AND n.overdue_alerted_at IS NULL)
+		    OR (n.due_at - (n.due_at - n.created_at) / 4 <= $1 AND n.due_soon_alerted_at IS NULL AND n.overdue_alerted_at IS NULL))
+		ORDER BY n.due_at`
+	return r.queryIncidentNotifications(ctx, query, now)
+}
+
+// MarkNotificationDeadlineAlerted records the alert stage; an overdue alert also closes the due soon stage
+func (r *incidentRepository) MarkNotificationDeadlineAlerted(ctx context.Context, id string, overdue bool, alertedAt time.Time) error {
+	query := `UPDATE incident_notifications SET due_soon_alerted_at = COALESCE(due_soon_alerted_at, $1) WHERE id = $2`
+	if overdue {
+		query = `UPDATE incident_notifications SET due_soon_alerted_at = COALESCE(due_soon_alerted_at, $1), overdue_alerted_at = $1 WHERE id = $2`
+	}
+	_, err := r.db.ExecContext(ctx, query, alertedAt, id)
+	return err
+}
+
+// GetIncidentNotification returns nil if the incident has no such notification
+func (r *incidentRepository) GetIncidentNotification(ctx context.Context, id, incidentID string) (*IncidentNotification, error) {
+	query := `SELECT ` + incidentNotificationColumns + incidentNotificationJoins + `
+		WHERE n.id = $1 AND n.incident_id = $2`
+
+	notification, err := scanIncidentNotification(r.db.QueryRowContext(ctx, query, id, incidentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return notification, nil
+}
+
+// AddIncidentNotifications inserts notifications, skipping obligations the incident already tracks
+func (r *incidentRepository) AddIncidentNotifications(ctx context.Context, notifications []*IncidentNotification) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_notifications (id, tenant_id, incident_id, obligation_id, profile_name, title,
+		                                    recipient_type, recipient, due_at, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (incident_id, obligation_id) DO NOTHING
+	`
+
+	created := 0
+	for _, n := range notifications {
+		result, err := tx.ExecContext(ctx, query,
+			n.ID, n.TenantID, n.IncidentID, n.ObligationID, n.ProfileName, n.Title,
+			n.RecipientType, n.Recipient, n.DueAt, n.Status, n.CreatedAt, n.UpdatedAt)
+		if err != nil {
+			return 0, err
+		}
+		if affected, _ := result.RowsAffected(); affected > 0 {
+			created++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+func (r *incidentRepository) UpdateIncidentNotification(ctx context.Context, n *IncidentNotification) error {
+	query := `
+		UPDATE incident_notifications
+		SET due_soon_alerted_at = CASE WHEN due_at = $1 THEN due_soon_alerted_at END,
+		    overdue_alerted_at = CASE WHEN due_at = $1 THEN overdue_alerted_at END,
+		    due_at = $1, status = $2, draft_content = $3, sent_at = $4, sent_by = $5, sent_to = $6,
+		    channel = $7, reference_number = $8, notes = $9, updated_at = $10
+		WHERE id = $11
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		n.DueAt, n.Status, n.DraftContent, n.SentAt, n.SentBy, n.SentTo,
+		n.Channel, n.ReferenceNumber, n.Notes, n.UpdatedAt, n.ID)
+	return err
+}
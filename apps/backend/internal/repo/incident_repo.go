@@ -11,26 +11,36 @@ import (
 )
 
 type Incident struct {
-	ID          string     `json:"id"`
-	TenantID    string     `json:"tenant_id"`
-	Title       string     `json:"title"`
-	Description *string    `json:"description"`
-	Category    string     `json:"category"`
-	Status      string     `json:"status"`
-	Severity    string     `json:"severity"`
-	Criticality string     `json:"criticality"`
-	Source      string     `json:"source"`
-	ReportedBy  string     `json:"reported_by"`
-	AssignedTo  *string    `json:"assigned_to"`
-	AssetID     *string    `json:"asset_id"`
-	RiskID      *string    `json:"risk_id"`
-	CreatedBy   string     `json:"created_by"`
-	DetectedAt  time.Time  `json:"detected_at"`
-	ResolvedAt  *time.Time `json:"resolved_at"`
-	ClosedAt    *time.Time `json:"closed_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	ID                 string     `json:"id"`
+	TenantID           string     `json:"tenant_id"`
+	Title              string     `json:"title"`
+	Description        *string    `json:"description"`
+	Category           string     `json:"category"`
+	Status             string     `json:"status"`
+	Severity           string     `json:"severity"`
+	Criticality        string     `json:"criticality"`
+	Source             string     `json:"source"`
+	ReportedBy         string     `json:"reported_by"`
+	AssignedTo         *string    `json:"assigned_to"`
+	AssetID            *string    `json:"asset_id"`
+	RiskID             *string    `json:"risk_id"`
+	CreatedBy          string     `json:"created_by"`
+	DetectedAt         time.Time  `json:"detected_at"`
+	ResolvedAt         *time.Time `json:"resolved_at"`
+	ClosedAt           *time.Time `json:"closed_at"`
+	RootCause          *string    `json:"root_cause"`
+	Resolution         *string    `json:"resolution_summary"`
+	ReopenCount        int        `json:"reopen_count"`
+	RespondedAt        *time.Time `json:"responded_at"`
+	ResponseDueAt      *time.Time `json:"response_due_at"`
+	ResolutionDueAt    *time.Time `json:"resolution_due_at"`
+	ResponseBreached   bool       `json:"response_breached"`
+	ResolutionBreached bool       `json:"resolution_breached"`
+	ParentID           *string    `json:"parent_id"`
+	MergedIntoID       *string    `json:"merged_into_id"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
 }
 
 type IncidentAsset struct {
@@ -80,6 +90,11 @@ type IncidentAction struct {
 	CreatedBy   string     `json:"created_by"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Set for actions generated from a playbook step
+	PlaybookID   *string `json:"playbook_id"`
+	PlaybookName *string `json:"playbook_name"`
+	StepOrder    *int    `json:"step_order"`
 }
 
 type IncidentMetrics struct {
@@ -97,6 +112,12 @@ type IncidentRepository interface {
 	Delete(ctx context.Context, id, tenantID string) error
 	List(ctx context.Context, tenantID string, filters map[string]interface{}, limit, offset int) ([]*Incident, int, error)
 
+	// Status workflow
+	ChangeStatus(ctx context.Context, incident *Incident, change *IncidentStatusChange) (bool, error)
+	GetStatusHistory(ctx context.Context, incidentID string) ([]*IncidentStatusChange, error)
+	GetStatusTransitions(ctx context.Context, tenantID string) ([]*IncidentStatusTransition, error)
+	ReplaceStatusTransitions(ctx context.Context, tenantID string, transitions []*IncidentStatusTransition) error
+
 	// Asset relations
 	AddAsset(ctx context.Context, incidentID, assetID string) error
 	RemoveAsset(ctx context.Context, incidentID, assetID string) error
@@ -121,11 +142,103 @@ type IncidentRepository interface {
 	UpdateAction(ctx context.Context, action *IncidentAction) error
 	GetActions(ctx context.Context, incidentID string) ([]*IncidentAction, error)
 	DeleteAction(ctx context.Context, actionID string) error
+	GetAction(ctx context.Context, actionID string) (*IncidentAction, error)
+
+	// Playbooks
+	ListPlaybooks(ctx context.Context, tenantID string) ([]*IncidentPlaybook, error)
+	GetPlaybookForCategory(ctx context.Context, tenantID, category string) (*IncidentPlaybook, error)
+	SavePlaybook(ctx context.Context, playbook *IncidentPlaybook) error
+	DeletePlaybook(ctx context.Context, tenantID, category string) error
+	AddPlaybookActions(ctx context.Context, actions []*IncidentAction) (int, error)
+	CancelPendingPlaybookActions(ctx context.Context, incidentID, keepPlaybookID string) (int64, error)
+	FindUserByRoleName(ctx context.Context, tenantID, roleName string, preferredUserID *string) (*string, error)
+
+	// Regulatory notifications
+	ListRegulatoryProfiles(ctx context.Context, tenantID string) ([]*IncidentRegulatoryProfile, error)
+	GetRegulatoryProfile(ctx context.Context, id, tenantID string) (*IncidentRegulatoryProfile, error)
+	SaveRegulatoryProfile(ctx context.Context, profile *IncidentRegulatoryProfile) error
+	DeleteRegulatoryProfile(ctx context.Context, id, tenantID string) error
+	ListApplicableObligations(ctx context.Context, tenantID, category string) ([]*IncidentRegulatoryObligation, error)
+	ListIncidentNotifications(ctx context.Context, incidentID string) ([]*IncidentNotification, error)
+	ListOverdueNotifications(ctx context.Context, tenantID string, now time.Time) ([]*IncidentNotification, error)
+	ListNotificationsForDeadlineAlerts(ctx context.Context, now time.Time) ([]*IncidentNotification, error)
+	MarkNotificationDeadlineAlerted(ctx context.Context, id string, overdue bool, alertedAt time.Time) error
+	GetIncidentNotification(ctx context.Context, id, incidentID string) (*IncidentNotification, error)
+	AddIncidentNotifications(ctx context.Context, notifications []*IncidentNotification) (int, error)
+	UpdateIncidentNotification(ctx context.Context, notification *IncidentNotification) error
+
+	// Post-incident reviews
+	GetReview(ctx context.Context, incidentID string) (*IncidentReview, error)
+	SaveReview(ctx context.Context, review *IncidentReview) error
+	GetReviewFollowUp(ctx context.Context, id, reviewID string) (*IncidentReviewFollowUp, error)
+	AddReviewFollowUp(ctx context.Context, followUp *IncidentReviewFollowUp) error
+	UpdateReviewFollowUp(ctx context.Context, followUp *IncidentReviewFollowUp) error
+	LinkReviewFollowUp(ctx context.Context, followUp *IncidentReviewFollowUp) error
+	DeleteReviewFollowUp(ctx context.Context, id, reviewID string) error
+
+	// Evidence chain of custody
+	ListEvidence(ctx context.Context, incidentID string) ([]*IncidentEvidence, error)
+	GetEvidence(ctx context.Context, id, incidentID string) (*IncidentEvidence, error)
+	IsEvidenceDocument(ctx context.Context, documentID string) (bool, error)
+	CreateEvidence(ctx context.Context, evidence *IncidentEvidence, entry *IncidentEvidenceCustody) error
+	UpdateEvidence(ctx context.Context, evidence *IncidentEvidence, entry *IncidentEvidenceCustody) error
+	AddEvidenceCustody(ctx context.Context, entry *IncidentEvidenceCustody) error
+	GetEvidenceCustody(ctx context.Context, evidenceID string) ([]*IncidentEvidenceCustody, error)
+
+	// Links, hierarchy and merges
+	SetParent(ctx context.Context, incidentID, tenantID string, parentID *string) error
+	ListChildIncidents(ctx context.Context, parentID, tenantID string) ([]*Incident, error)
+	ListMergedIncidents(ctx context.Context, primaryID, tenantID string) ([]*Incident, error)
+	ListLinks(ctx context.Context, incidentID string) ([]*IncidentLink, error)
+	GetLink(ctx context.Context, linkID string) (*IncidentLink, error)
+	CreateLink(ctx context.Context, link *IncidentLink) error
+	DeleteLink(ctx context.Context, linkID string) error
+	GetMerge(ctx context.Context, mergeID, tenantID string) (*IncidentMerge, error)
+	ListMerges(ctx context.Context, incidentID string) ([]*IncidentMerge, error)
+	MergeIncident(ctx context.Context, merge *IncidentMerge, duplicate *Incident, change *IncidentStatusChange) error
+	RevertMerge(ctx context.Context, merge *IncidentMerge, duplicate *Incident, change *IncidentStatusChange) error
+
+	// Email intake
+	ListMailboxes(ctx context.Context, tenantID string) ([]*IncidentMailbox, error)
+	ListActiveMailboxes(ctx context.Context) ([]*IncidentMailbox, error)
+	ListPlaintextPasswordMailboxes(ctx context.Context) ([]*IncidentMailbox, error)
+	GetMailbox(ctx context.Context, id, tenantID string) (*IncidentMailbox, error)
+	SaveMailbox(ctx context.Context, mailbox *IncidentMailbox) error
+	DeleteMailbox(ctx context.Context, id, tenantID string) error
+	SetMailboxPollResult(ctx context.Context, id string, polledAt time.Time, lastError *string) error
+	ListProcessedMailUIDLs(ctx context.Context, mailboxID string, uidls []string) (map[string]bool, error)
+	FindIncidentByMailReference(ctx context.Context, tenantID string, messageIDs []string) (string, error)
+	ClaimMailMessage(ctx context.Context, message *IncidentMailMessage) (bool, error)
+	FinishMailMessage(ctx context.Context, message *IncidentMailMessage) error
+	ListMailMessages(ctx context.Context, mailboxID string, limit int) ([]*IncidentMailMessage, error)
+
+	// SLA
+	ListSLAPolicies(ctx context.Context, tenantID string) ([]*IncidentSLAPolicy, error)
+	UpsertSLAPolicy(ctx context.Context, policy *IncidentSLAPolicy) error
+	ListBusinessCalendars(ctx context.Context, tenantID string) ([]*IncidentBusinessCalendar, error)
+	GetBusinessCalendar(ctx context.Context, id, tenantID string) (*IncidentBusinessCalendar, error)
+	GetDefaultBusinessCalendar(ctx context.Context, tenantID string) (*IncidentBusinessCalendar, error)
+	SaveBusinessCalendar(ctx context.Context, calendar *IncidentBusinessCalendar) error
+	DeleteBusinessCalendar(ctx context.Context, id, tenantID string) error
+	ListIncidentsWithRunningSLA(ctx context.Context) ([]*Incident, error)
+	RecordSLAEvent(ctx context.Context, event *IncidentSLAEvent) (bool, error)
+	ListSLAEvents(ctx context.Context, tenantID string, filters map[string]interface{}, limit int) ([]*IncidentSLAEvent, error)
+
+	// Alert ingestion
+	ListAlertSources(ctx context.Context, tenantID string) ([]*IncidentAlertSource, error)
+	GetAlertSource(ctx context.Context, id, tenantID string) (*IncidentAlertSource, error)
+	GetAlertSourceByTokenHash(ctx context.Context, tokenHash string) (*IncidentAlertSource, error)
+	SaveAlertSource(ctx context.Context, source *IncidentAlertSource) error
+	DeleteAlertSource(ctx context.Context, id, tenantID string) error
+	FindOpenIncidentByCorrelation(ctx context.Context, tenantID, correlationKey string, since time.Time) (string, error)
+	AddAlert(ctx context.Context, alert *IncidentAlert) error
+	ListAlerts(ctx context.Context, incidentID string) ([]*IncidentAlert, error)
 
 	// Metrics
 	AddMetric(ctx context.Context, metric *IncidentMetrics) error
 	GetMetrics(ctx context.Context, incidentID string) ([]*IncidentMetrics, error)
 	GetIncidentMetrics(ctx context.Context, tenantID string) (*IncidentMetricsSummary, error)
+	ListTrendIncidents(ctx context.Context, tenantID string, from, to time.Time, filters map[string]interface{}) ([]*IncidentTrendRow, error)
 }
 
 type IncidentMetricsSummary struct {
@@ -137,6 +250,12 @@ type IncidentMetricsSummary struct {
 	ByCriticality   map[string]int `json:"by_criticality"`
 	ByCategory      map[string]int `json:"by_category"`
 	ByStatus        map[string]int `json:"by_status"`
+
+	AverageResponseTime float64 `json:"average_response_time_minutes"`
+	ResponseBreaches    int     `json:"response_breaches"`
+	ResolutionBreaches  int     `json:"resolution_breaches"`
+	OpenBreached        int     `json:"open_breached"`
+	SLACompliance       float64 `json:"sla_compliance_percent"`
 }
 
 type incidentRepository struct {
@@ -149,34 +268,70 @@ func NewIncidentRepository(db *sql.DB) IncidentRepository {
 
 func (r *incidentRepository) Create(ctx context.Context, incident *Incident) error {
 	query := `
-		INSERT INTO incidents (id, tenant_id, title, description, category, status, criticality, source, reported_by, assigned_to, detected_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		INSERT INTO incidents (id, tenant_id, title, description, category, status, criticality, source, reported_by, assigned_to, detected_at, response_due_at, resolution_due_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
 		incident.ID, incident.TenantID, incident.Title, incident.Description,
 		incident.Category, incident.Status, incident.Criticality, incident.Source,
 		incident.ReportedBy, incident.AssignedTo, incident.DetectedAt,
-		incident.CreatedAt, incident.UpdatedAt)
+		incident.ResponseDueAt, incident.ResolutionDueAt, incident.CreatedAt, incident.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Начальная запись в истории статусов
+	err = insertStatusChange(ctx, tx, &IncidentStatusChange{
+		ID:         uuid.New().String(),
+		IncidentID: incident.ID,
+		ToStatus:   incident.Status,
+		ChangedBy:  &incident.ReportedBy,
+		ChangedAt:  incident.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *incidentRepository) GetByID(ctx context.Context, id, tenantID string) (*Incident, error) {
-	query := `
-		SELECT id, tenant_id, title, description, category, status, criticality, source, 
-		       reported_by, assigned_to, detected_at, resolved_at, closed_at, created_at, updated_at
-		FROM incidents 
-		WHERE id = $1 AND tenant_id = $2
-	`
+// incidentColumns lists the incidents table columns in the order expected by scanIncident
+const incidentColumns = `id, tenant_id, title, description, category, status, criticality, source,
+		       reported_by, assigned_to, detected_at, resolved_at, closed_at, root_cause, resolution_summary,
+		       reopen_count, responded_at, response_due_at, resolution_due_at, response_breached,
+		       resolution_breached, parent_id, merged_into_id, created_at, updated_at`
 
+func scanIncident(row rowScanner) (*Incident, error) {
 	var incident Incident
-	err := r.db.QueryRowContext(ctx, query, id, tenantID).Scan(
+	err := row.Scan(
 		&incident.ID, &incident.TenantID, &incident.Title, &incident.Description,
 		&incident.Category, &incident.Status, &incident.Criticality, &incident.Source,
 		&incident.ReportedBy, &incident.AssignedTo, &incident.DetectedAt,
-		&incident.ResolvedAt, &incident.ClosedAt, &incident.CreatedAt, &incident.UpdatedAt)
+		&incident.ResolvedAt, &incident.ClosedAt, &incident.RootCause, &incident.Resolution,
+		&incident.ReopenCount, &incident.RespondedAt, &incident.ResponseDueAt, &incident.ResolutionDueAt,
+		&incident.ResponseBreached, &incident.ResolutionBreached, &incident.ParentID, &incident.MergedIntoID,
+		&incident.CreatedAt, &incident.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+func (r *incidentRepository) GetByID(ctx context.Context, id, tenantID string) (*Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents 
+		WHERE id = $1 AND tenant_id = $2
+	`
 
+	incident, err := scanIncident(r.db.QueryRowContext(ctx, query, id, tenantID))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("incident not found")
@@ -184,24 +339,31 @@ func (r *incidentRepository) GetByID(ctx context.Context, id, tenantID string) (
 		return nil, err
 	}
 
-	return &incident, nil
+	return incident, nil
 }
 
-func (r *incidentRepository) Update(ctx context.Context, incident *Incident) error {
-	query := `
+// updateIncidentQuery writes every mutable column; arguments come from updateIncidentArgs
+const updateIncidentQuery = `
 		UPDATE incidents 
 		SET title = $1, description = $2, category = $3, status = $4, criticality = $5, 
 		    source = $6, assigned_to = $7, detected_at = $8, resolved_at = $9, closed_at = $10, 
-		    updated_at = $11
-		WHERE id = $12 AND tenant_id = $13
+		    root_cause = $11, resolution_summary = $12, reopen_count = $13, responded_at = $14,
+		    response_due_at = $15, resolution_due_at = $16, updated_at = $17
+		WHERE id = $18 AND tenant_id = $19
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+func updateIncidentArgs(incident *Incident) []interface{} {
+	return []interface{}{
 		incident.Title, incident.Description, incident.Category, incident.Status,
 		incident.Criticality, incident.Source, incident.AssignedTo, incident.DetectedAt,
-		incident.ResolvedAt, incident.ClosedAt, incident.UpdatedAt,
-		incident.ID, incident.TenantID)
+		incident.ResolvedAt, incident.ClosedAt, incident.RootCause, incident.Resolution,
+		incident.ReopenCount, incident.RespondedAt, incident.ResponseDueAt, incident.ResolutionDueAt,
+		incident.UpdatedAt, incident.ID, incident.TenantID,
+	}
+}
 
+func (r *incidentRepository) Update(ctx context.Context, incident *Incident) error {
+	_, err := r.db.ExecContext(ctx, updateIncidentQuery, updateIncidentArgs(incident)...)
 	return err
 }
 
@@ -257,12 +419,11 @@ func (r *incidentRepository) List(ctx context.Context, tenantID string, filters
 
 	// List query
 	listQuery := fmt.Sprintf(`
-		SELECT id, tenant_id, title, description, category, status, criticality, source, 
-		       reported_by, assigned_to, detected_at, resolved_at, closed_at, created_at, updated_at
+		SELECT %s
 		FROM incidents %s
 		ORDER BY created_at DESC
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+	`, incidentColumns, whereClause, argIndex, argIndex+1)
 
 	args = append(args, limit, offset)
 
@@ -274,16 +435,11 @@ func (r *incidentRepository) List(ctx context.Context, tenantID string, filters
 
 	var incidents []*Incident
 	for rows.Next() {
-		var incident Incident
-		err := rows.Scan(
-			&incident.ID, &incident.TenantID, &incident.Title, &incident.Description,
-			&incident.Category, &incident.Status, &incident.Criticality, &incident.Source,
-			&incident.ReportedBy, &incident.AssignedTo, &incident.DetectedAt,
-			&incident.ResolvedAt, &incident.ClosedAt, &incident.CreatedAt, &incident.UpdatedAt)
+		incident, err := scanIncident(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-		incidents = append(incidents, &incident)
+		incidents = append(incidents, incident)
 	}
 
 	return incidents, total, nil
@@ -470,13 +626,13 @@ func (r *incidentRepository) DeleteAttachment(ctx context.Context, attachmentID
 // Actions
 func (r *incidentRepository) AddAction(ctx context.Context, action *IncidentAction) error {
 	query := `
-		INSERT INTO incident_actions (id, incident_id, action_type, title, description, assigned_to, due_date, status, created_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO incident_actions (id, incident_id, action_type, title, description, assigned_to, due_date, status, created_by, created_at, updated_at, playbook_id, step_order)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 	_, err := r.db.ExecContext(ctx, query,
 		action.ID, action.IncidentID, action.ActionType, action.Title, action.Description,
 		action.AssignedTo, action.DueDate, action.Status, action.CreatedBy,
-		action.CreatedAt, action.UpdatedAt)
+		action.CreatedAt, action.UpdatedAt, action.PlaybookID, action.StepOrder)
 	return err
 }
 
@@ -493,13 +649,29 @@ func (r *incidentRepository) UpdateAction(ctx context.Context, action *IncidentA
 	return err
 }
 
+const incidentActionColumns = `a.id, a.incident_id, a.action_type, a.title, a.description, a.assigned_to, a.due_date,
+	a.completed_at, a.status, a.created_by, a.created_at, a.updated_at, a.playbook_id, p.name, a.step_order`
+
+func scanIncidentAction(row rowScanner) (*IncidentAction, error) {
+	var action IncidentAction
+	err := row.Scan(
+		&action.ID, &action.IncidentID, &action.ActionType, &action.Title,
+		&action.Description, &action.AssignedTo, &action.DueDate, &action.CompletedAt,
+		&action.Status, &action.CreatedBy, &action.CreatedAt, &action.UpdatedAt,
+		&action.PlaybookID, &action.PlaybookName, &action.StepOrder)
+	if err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
 func (r *incidentRepository) GetActions(ctx context.Context, incidentID string) ([]*IncidentAction, error) {
 	query := `
-		SELECT id, incident_id, action_type, title, description, assigned_to, due_date, 
-		       completed_at, status, created_by, created_at, updated_at
-		FROM incident_actions
-		WHERE incident_id = $1
-		ORDER BY created_at ASC
+		SELECT ` + incidentActionColumns + `
+		FROM incident_actions a
+		LEFT JOIN incident_playbooks p ON p.id = a.playbook_id
+		WHERE a.incident_id = $1
+		ORDER BY a.created_at ASC, a.step_order ASC NULLS LAST
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, incidentID)
@@ -510,20 +682,35 @@ func (r *incidentRepository) GetActions(ctx context.Context, incidentID string)
 
 	var actions []*IncidentAction
 	for rows.Next() {
-		var action IncidentAction
-		err := rows.Scan(
-			&action.ID, &action.IncidentID, &action.ActionType, &action.Title,
-			&action.Description, &action.AssignedTo, &action.DueDate, &action.CompletedAt,
-			&action.Status, &action.CreatedBy, &action.CreatedAt, &action.UpdatedAt)
+		action, err := scanIncidentAction(rows)
 		if err != nil {
 			return nil, err
 		}
-		actions = append(actions, &action)
+		actions = append(actions, action)
 	}
 
 	return actions, nil
 }
 
+// GetAction returns nil if the action does not exist
+func (r *incidentRepository) GetAction(ctx context.Context, actionID string) (*IncidentAction, error) {
+	query := `
+		SELECT ` + incidentActionColumns + `
+		FROM incident_actions a
+		LEFT JOIN incident_playbooks p ON p.id = a.playbook_id
+		WHERE a.id = $1
+	`
+
+	action, err := scanIncidentAction(r.db.QueryRowContext(ctx, query, actionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return action, nil
+}
+
 func (r *incidentRepository) DeleteAction(ctx context.Context, actionID string) error {
 	query := `DELETE FROM incident_actions WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, actionID)
@@ -677,6 +864,29 @@ func (r *incidentRepository) GetIncidentMetrics(ctx context.Context, tenantID st
 		return nil, err
 	}
 
+	// SLA breaches and compliance
+	slaQuery := `
+		SELECT
+			COUNT(CASE WHEN response_breached THEN 1 END),
+			COUNT(CASE WHEN resolution_breached THEN 1 END),
+			COUNT(CASE WHEN (response_breached OR resolution_breached) AND status NOT IN ('resolved', 'closed') THEN 1 END),
+			COUNT(CASE WHEN response_due_at IS NOT NULL OR resolution_due_at IS NOT NULL THEN 1 END),
+			COUNT(CASE WHEN (response_due_at IS NOT NULL OR resolution_due_at IS NOT NULL)
+			            AND NOT response_breached AND NOT resolution_breached THEN 1 END),
+			(SELECT AVG(value_minutes) FROM incident_metrics im JOIN incidents i ON im.incident_id = i.id
+			 WHERE i.tenant_id = $1 AND im.metric_type = 'response_time')
+		FROM incidents
+		WHERE tenant_id = $1
+	`
+
+	var responseBreaches, resolutionBreaches, openBreached, withSLA, compliant int
+	var avgResponse sql.NullFloat64
+	err = r.db.QueryRowContext(ctx, slaQuery, tenantID).Scan(
+		&responseBreaches, &resolutionBreaches, &openBreached, &withSLA, &compliant, &avgResponse)
+	if err != nil {
+		return nil, err
+	}
+
 	summary := &IncidentMetricsSummary{
 		TotalIncidents:  total,
 		OpenIncidents:   open,
@@ -686,8 +896,19 @@ func (r *incidentRepository) GetIncidentMetrics(ctx context.Context, tenantID st
 		ByCriticality:   byCriticality,
 		ByCategory:      byCategory,
 		ByStatus:        byStatus,
+
+		ResponseBreaches:   responseBreaches,
+		ResolutionBreaches: resolutionBreaches,
+		OpenBreached:       openBreached,
+		SLACompliance:      100,
 	}
 
+	if withSLA > 0 {
+		summary.SLACompliance = float64(compliant) * 100 / float64(withSLA)
+	}
+	if avgResponse.Valid {
+		summary.AverageResponseTime = avgResponse.Float64
+	}
 	if avgMTTR.Valid {
 		summary.AverageMTTR = avgMTTR.Float64 / 60.0 // Convert to hours
 	}
@@ -0,0 +1,252 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// IncidentReview is the post-incident review (lessons learned) of an incident
+type IncidentReview struct {
+	ID             string                    `json:"id"`
+	TenantID       string                    `json:"tenant_id"`
+	IncidentID     string                    `json:"incident_id"`
+	Status         string                    `json:"status"`
+	Summary        *string                   `json:"summary"`
+	RootCause      *string                   `json:"root_cause"`
+	Impact         *string                   `json:"impact"`
+	WhatWentWell   *string                   `json:"what_went_well"`
+	WhatWentWrong  *string                   `json:"what_went_wrong"`
+	LessonsLearned *string                   `json:"lessons_learned"`
+	ReviewDate     *time.Time                `json:"review_date"`
+	FacilitatorID  *string                   `json:"facilitator_id"`
+	Participants   []string                  `json:"participants"`
+	CompletedAt    *time.Time                `json:"completed_at"`
+	CreatedBy      *string                   `json:"created_by"`
+	Factors        []*IncidentReviewFactor   `json:"factors"`
+	FollowUps      []*IncidentReviewFollowUp `json:"follow_ups"`
+	CreatedAt      time.Time                 `json:"created_at"`
+	UpdatedAt      time.Time                 `json:"updated_at"`
+}
+
+// IncidentReviewFactor is a contributing factor identified during the review
+type IncidentReviewFactor struct {
+	ID          string `json:"id"`
+	ReviewID    string `json:"review_id"`
+	Position    int    `json:"position"`
+	FactorType  string `json:"factor_type"`
+	Description string `json:"description"`
+}
+
+// IncidentReviewFollowUp is a follow-up measure; risk and control follow-ups reference what they created
+type IncidentReviewFollowUp struct {
+	ID           string     `json:"id"`
+	ReviewID     string     `json:"review_id"`
+	FollowUpType string     `json:"followup_type"`
+	Title        string     `json:"title"`
+	Description  *string    `json:"description"`
+	OwnerID      *string    `json:"owner_id"`
+	DueDate      *time.Time `json:"due_date"`
+	Status       string     `json:"status"`
+	RiskID       *string    `json:"risk_id"`
+	ControlID    *string    `json:"control_id"`
+	CreatedBy    *string    `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// GetReview returns the review of the incident with factors and follow-ups, or nil if there is none
+func (r *incidentRepository) GetReview(ctx context.Context, incidentID string) (*IncidentReview, error) {
+	query := `
+		SELECT id, tenant_id, incident_id, status, summary, root_cause, impact, what_went_well, what_went_wrong,
+		       lessons_learned, review_date, facilitator_id, participants, completed_at, created_by,
+		       created_at, updated_at
+		FROM incident_reviews
+		WHERE incident_id = $1
+	`
+
+	var review IncidentReview
+	err := r.db.QueryRowContext(ctx, query, incidentID).Scan(
+		&review.ID, &review.TenantID, &review.IncidentID, &review.Status, &review.Summary, &review.RootCause,
+		&review.Impact, &review.WhatWentWell, &review.WhatWentWrong, &review.LessonsLearned, &review.ReviewDate,
+		&review.FacilitatorID, pq.Array(&review.Participants), &review.CompletedAt, &review.CreatedBy,
+		&review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if review.Factors, err = r.getReviewFactors(ctx, review.ID); err != nil {
+		return nil, err
+	}
+	if review.FollowUps, err = r.listReviewFollowUps(ctx, review.ID); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *incidentRepository) getReviewFactors(ctx context.Context, reviewID string) ([]*IncidentReviewFactor, error) {
+	query := `
+		SELECT id, review_id, position, factor_type, description
+		FROM incident_review_factors
+		WHERE review_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var factors []*IncidentReviewFactor
+	for rows.Next() {
+		var factor IncidentReviewFactor
+		if err := rows.Scan(&factor.ID, &factor.ReviewID, &factor.Position, &factor.FactorType, &factor.Description); err != nil {
+			return nil, err
+		}
+		factors = append(factors, &factor)
+	}
+
+	return factors, rows.Err()
+}
+
+const reviewFollowUpColumns = `id, review_id, followup_type, title, description, owner_id, due_date, status, risk_id,
+	control_id, created_by, created_at, updated_at`
+
+func scanReviewFollowUp(row rowScanner) (*IncidentReviewFollowUp, error) {
+	var followUp IncidentReviewFollowUp
+	err := row.Scan(
+		&followUp.ID, &followUp.ReviewID, &followUp.FollowUpType, &followUp.Title, &followUp.Description,
+		&followUp.OwnerID, &followUp.DueDate, &followUp.Status, &followUp.RiskID, &followUp.ControlID,
+		&followUp.CreatedBy, &followUp.CreatedAt, &followUp.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &followUp, nil
+}
+
+func (r *incidentRepository) listReviewFollowUps(ctx context.Context, reviewID string) ([]*IncidentReviewFollowUp, error) {
+	query := `SELECT ` + reviewFollowUpColumns + ` FROM incident_review_followups WHERE review_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var followUps []*IncidentReviewFollowUp
+	for rows.Next() {
+		followUp, err := scanReviewFollowUp(rows)
+		if err != nil {
+			return nil, err
+		}
+		followUps = append(followUps, followUp)
+	}
+
+	return followUps, rows.Err()
+}
+
+// SaveReview inserts or updates the review and replaces its contributing factors
+func (r *incidentRepository) SaveReview(ctx context.Context, review *IncidentReview) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_reviews (id, tenant_id, incident_id, status, summary, root_cause, impact, what_went_well,
+		                              what_went_wrong, lessons_learned, review_date, facilitator_id, participants,
+		                              completed_at, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (id) DO UPDATE
+		SET status = EXCLUDED.status, summary = EXCLUDED.summary, root_cause = EXCLUDED.root_cause,
+		    impact = EXCLUDED.impact, what_went_well = EXCLUDED.what_went_well,
+		    what_went_wrong = EXCLUDED.what_went_wrong, lessons_learned = EXCLUDED.lessons_learned,
+		    review_date = EXCLUDED.review_date, facilitator_id = EXCLUDED.facilitator_id,
+		    participants = EXCLUDED.participants, completed_at = EXCLUDED.completed_at,
+		    updated_at = EXCLUDED.updated_at
+	`
+	_, err = tx.ExecContext(ctx, query,
+		review.ID, review.TenantID, review.IncidentID, review.Status, review.Summary, review.RootCause,
+		review.Impact, review.WhatWentWell, review.WhatWentWrong, review.LessonsLearned, review.ReviewDate,
+		review.FacilitatorID, pq.Array(review.Participants), review.CompletedAt, review.CreatedBy,
+		review.CreatedAt, review.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM incident_review_factors WHERE review_id = $1`, review.ID); err != nil {
+		return err
+	}
+	for _, factor := range review.Factors {
+		factor.ReviewID = review.ID
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO incident_review_factors (id, review_id, position, factor_type, description)
+			VALUES ($1, $2, $3, $4, $5)
+		`, factor.ID, factor.ReviewID, factor.Position, factor.FactorType, factor.Description)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetReviewFollowUp returns nil if the review has no such follow-up
+func (r *incidentRepository) GetReviewFollowUp(ctx context.Context, id, reviewID string) (*IncidentReviewFollowUp, error) {
+	query := `SELECT ` + reviewFollowUpColumns + ` FROM incident_review_followups WHERE id = $1 AND review_id = $2`
+
+	followUp, err := scanReviewFollowUp(r.db.QueryRowContext(ctx, query, id, reviewID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return followUp, nil
+}
+
+func (r *incidentRepository) AddReviewFollowUp(ctx context.Context, followUp *IncidentReviewFollowUp) error {
+	query := `
+		INSERT INTO incident_review_followups (id, review_id, followup_type, title, description, owner_id, due_date,
+		                                       status, risk_id, control_id, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		followUp.ID, followUp.ReviewID, followUp.FollowUpType, followUp.Title, followUp.Description,
+		followUp.OwnerID, followUp.DueDate, followUp.Status, followUp.RiskID, followUp.ControlID,
+		followUp.CreatedBy, followUp.CreatedAt, followUp.UpdatedAt)
+	return err
+}
+
+func (r *incidentRepository) UpdateReviewFollowUp(ctx context.Context, followUp *IncidentReviewFollowUp) error {
+	query := `
+		UPDATE incident_review_followups
+		SET title = $1, description = $2, owner_id = $3, due_date = $4, status = $5, updated_at = $6
+		WHERE id = $7
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		followUp.Title, followUp.Description, followUp.OwnerID, followUp.DueDate, followUp.Status,
+		followUp.UpdatedAt, followUp.ID)
+	return err
+}
+
+// LinkReviewFollowUp stores the risk and control created for a follow-up
+func (r *incidentRepository) LinkReviewFollowUp(ctx context.Context, followUp *IncidentReviewFollowUp) error {
+	query := `UPDATE incident_review_followups SET risk_id = $1, control_id = $2 WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, followUp.RiskID, followUp.ControlID, followUp.ID)
+	return err
+}
+
+func (r *incidentRepository) DeleteReviewFollowUp(ctx context.Context, id, reviewID string) error {
+	query := `DELETE FROM incident_review_followups WHERE id = $1 AND review_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, reviewID)
+	return err
+}
@@ -0,0 +1,338 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// IncidentSLAPolicy defines response and resolution targets for a criticality level
+type IncidentSLAPolicy struct {
+	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	Criticality       string    `json:"criticality"`
+	ResponseMinutes   int       `json:"response_minutes"`
+	ResolutionMinutes int       `json:"resolution_minutes"`
+	WarningPercent    int       `json:"warning_percent"`
+	BusinessHoursOnly bool      `json:"business_hours_only"`
+	CalendarID        *string   `json:"calendar_id"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// IncidentBusinessCalendar describes working hours used by business-hours SLA policies
+type IncidentBusinessCalendar struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	Timezone  string    `json:"timezone"`
+	WorkDays  []int64   `json:"work_days"` // ISO weekdays, 1 = Monday
+	WorkStart string    `json:"work_start"`
+	WorkEnd   string    `json:"work_end"`
+	Holidays  []string  `json:"holidays"` // YYYY-MM-DD
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IncidentSLAEvent is a warning or breach of an SLA target
+type IncidentSLAEvent struct {
+	ID              string    `json:"id"`
+	TenantID        string    `json:"tenant_id"`
+	IncidentID      string    `json:"incident_id"`
+	Target          string    `json:"target"`
+	EventType       string    `json:"event_type"`
+	DueAt           time.Time `json:"due_at"`
+	RecipientUserID *string   `json:"recipient_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	IncidentTitle   string    `json:"incident_title"`
+}
+
+// SLA policies
+func (r *incidentRepository) ListSLAPolicies(ctx context.Context, tenantID string) ([]*IncidentSLAPolicy, error) {
+	query := `
+		SELECT id, tenant_id, criticality, response_minutes, resolution_minutes, warning_percent,
+		       business_hours_only, calendar_id, updated_at
+		FROM incident_sla_policies
+		WHERE tenant_id = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*IncidentSLAPolicy
+	for rows.Next() {
+		var policy IncidentSLAPolicy
+		err := rows.Scan(
+			&policy.ID, &policy.TenantID, &policy.Criticality, &policy.ResponseMinutes,
+			&policy.ResolutionMinutes, &policy.WarningPercent, &policy.BusinessHoursOnly,
+			&policy.CalendarID, &policy.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, &policy)
+	}
+
+	return policies, rows.Err()
+}
+
+func (r *incidentRepository) UpsertSLAPolicy(ctx context.Context, policy *IncidentSLAPolicy) error {
+	query := `
+		INSERT INTO incident_sla_policies (id, tenant_id, criticality, response_minutes, resolution_minutes,
+		                                   warning_percent, business_hours_only, calendar_id, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (tenant_id, criticality) DO UPDATE
+		SET response_minutes = EXCLUDED.response_minutes, resolution_minutes = EXCLUDED.resolution_minutes,
+		    warning_percent = EXCLUDED.warning_percent, business_hours_only = EXCLUDED.business_hours_only,
+		    calendar_id = EXCLUDED.calendar_id, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+	return r.db.QueryRowContext(ctx, query,
+		policy.ID, policy.TenantID, policy.Criticality, policy.ResponseMinutes, policy.ResolutionMinutes,
+		policy.WarningPercent, policy.BusinessHoursOnly, policy.CalendarID, policy.UpdatedAt).Scan(&policy.ID)
+}
+
+// Business calendars
+const businessCalendarColumns = `id, tenant_id, name, timezone, work_days, work_start, work_end, holidays::text[], is_default, created_at, updated_at`
+
+func scanBusinessCalendar(row rowScanner) (*IncidentBusinessCalendar, error) {
+	var calendar IncidentBusinessCalendar
+	err := row.Scan(
+		&calendar.ID, &calendar.TenantID, &calendar.Name, &calendar.Timezone, pq.Array(&calendar.WorkDays),
+		&calendar.WorkStart, &calendar.WorkEnd, pq.Array(&calendar.Holidays), &calendar.IsDefault,
+		&calendar.CreatedAt, &calendar.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (r *incidentRepository) ListBusinessCalendars(ctx context.Context, tenantID string) ([]*IncidentBusinessCalendar, error) {
+	query := `SELECT ` + businessCalendarColumns + ` FROM incident_business_calendars WHERE tenant_id = $1 ORDER BY is_default DESC, name`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calendars []*IncidentBusinessCalendar
+	for rows.Next() {
+		calendar, err := scanBusinessCalendar(rows)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, calendar)
+	}
+
+	return calendars, rows.Err()
+}
+
+// GetBusinessCalendar returns nil if the tenant has no such calendar
+func (r *incidentRepository) GetBusinessCalendar(ctx context.Context, id, tenantID string) (*IncidentBusinessCalendar, error) {
+	query := `SELECT ` + businessCalendarColumns + ` FROM incident_business_calendars WHERE id = $1 AND tenant_id = $2`
+
+	calendar, err := scanBusinessCalendar(r.db.QueryRowContext(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// GetDefaultBusinessCalendar returns nil if the tenant has not marked a calendar as default
+func (r *incidentRepository) GetDefaultBusinessCalendar(ctx context.Context, tenantID string) (*IncidentBusinessCalendar, error) {
+	query := `SELECT ` + businessCalendarColumns + ` FROM incident_business_calendars WHERE tenant_id = $1 AND is_default`
+
+	calendar, err := scanBusinessCalendar(r.db.QueryRowContext(ctx, query, tenantID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// SaveBusinessCalendar inserts or updates a calendar; marking it default clears the flag on the others
+func (r *incidentRepository) SaveBusinessCalendar(ctx context.Context, calendar *IncidentBusinessCalendar) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if calendar.IsDefault {
+		_, err := tx.ExecContext(ctx, `UPDATE incident_business_calendars SET is_default = FALSE WHERE tenant_id = $1 AND id <> $2`,
+			calendar.TenantID, calendar.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO incident_business_calendars (id, tenant_id, name, timezone, work_days, work_start, work_end,
+		                                         holidays, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::date[], $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, timezone = EXCLUDED.timezone, work_days = EXCLUDED.work_days,
+		    work_start = EXCLUDED.work_start, work_end = EXCLUDED.work_end, holidays = EXCLUDED.holidays,
+		    is_default = EXCLUDED.is_default, updated_at = EXCLUDED.updated_at
+	`
+	_, err = tx.ExecContext(ctx, query,
+		calendar.ID, calendar.TenantID, calendar.Name, calendar.Timezone, pq.Array(calendar.WorkDays),
+		calendar.WorkStart, calendar.WorkEnd, pq.Array(calendar.Holidays), calendar.IsDefault,
+		calendar.CreatedAt, calendar.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *incidentRepository) DeleteBusinessCalendar(ctx context.Context, id, tenantID string) error {
+	query := `DELETE FROM incident_business_calendars WHERE id = $1 AND tenant_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, tenantID)
+	return err
+}
+
+// SLA monitoring
+
+// ListIncidentsWithRunningSLA returns incidents of all tenants whose response or resolution timer
+// is still running and has not been breached yet
+func (r *incidentRepository) ListIncidentsWithRunningSLA(ctx context.Context) ([]*Incident, error) {
+	query := `
+		SELECT ` + incidentColumns + `
+		FROM incidents
+		WHERE (responded_at IS NULL AND response_due_at IS NOT NULL AND NOT response_breached)
+		   OR (status NOT IN ('resolved', 'closed') AND resolution_due_at IS NOT NULL AND NOT resolution_breached)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var incidents []*Incident
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, rows.Err()
+}
+
+// RecordSLAEvent stores the event once per incident, target and type; a breach also flags the incident.
+// Returns false if the event had already been recorded.
+func (r *incidentRepository) RecordSLAEvent(ctx context.Context, event *IncidentSLAEvent) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO incident_sla_events (id, tenant_id, incident_id, target, event_type, due_at, recipient_user_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (incident_id, target, event_type) DO NOTHING
+	`
+	result, err := tx.ExecContext(ctx, query,
+		event.ID, event.TenantID, event.IncidentID, event.Target, event.EventType,
+		event.DueAt, event.RecipientUserID, event.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if inserted == 0 {
+		return false, nil
+	}
+
+	if event.EventType == "breach" {
+		column := "response_breached"
+		if event.Target == "resolution" {
+			column = "resolution_breached"
+		}
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE incidents SET %s = TRUE WHERE id = $1`, column), event.IncidentID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *incidentRepository) ListSLAEvents(ctx context.Context, tenantID string, filters map[string]interface{}, limit int) ([]*IncidentSLAEvent, error) {
+	whereClause := "WHERE e.tenant_id = $1"
+	args := []interface{}{tenantID}
+	argIndex := 2
+
+	if incidentID, ok := filters["incident_id"].(string); ok && incidentID != "" {
+		whereClause += fmt.Sprintf(" AND e.incident_id = $%d", argIndex)
+		args = append(args, incidentID)
+		argIndex++
+	}
+
+	if eventType, ok := filters["event_type"].(string); ok && eventType != "" {
+		whereClause += fmt.Sprintf(" AND e.event_type = $%d", argIndex)
+		args = append(args, eventType)
+		argIndex++
+	}
+
+	if recipient, ok := filters["recipient_user_id"].(string); ok && recipient != "" {
+		whereClause += fmt.Sprintf(" AND e.recipient_user_id = $%d", argIndex)
+		args = append(args, recipient)
+		argIndex++
+	}
+
+	if since, ok := filters["since"].(time.Time); ok {
+		whereClause += fmt.Sprintf(" AND e.created_at >= $%d", argIndex)
+		args = append(args, since)
+		argIndex++
+	}
+
+	query := fmt.Sprintf(`
+		SELECT e.id, e.tenant_id, e.incident_id, e.target, e.event_type, e.due_at, e.recipient_user_id,
+		       e.created_at, i.title
+		FROM incident_sla_events e
+		JOIN incidents i ON i.id = e.incident_id
+		%s
+		ORDER BY e.created_at DESC
+		LIMIT $%d
+	`, whereClause, argIndex)
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*IncidentSLAEvent
+	for rows.Next() {
+		var event IncidentSLAEvent
+		err := rows.Scan(
+			&event.ID, &event.TenantID, &event.IncidentID, &event.Target, &event.EventType,
+			&event.DueAt, &event.RecipientUserID, &event.CreatedAt, &event.IncidentTitle)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// IncidentTrendRow holds the timestamps and durations of one incident used to build trend series
+type IncidentTrendRow struct {
+	ID                 string
+	Category           string
+	Criticality        string
+	DetectedAt         time.Time
+	ResolvedAt         *time.Time
+	ClosedAt           *time.Time
+	ResponseBreached   bool
+	ResolutionBreached bool
+	MTTRMinutes        *int
+	MTTDMinutes        *int
+}
+
+// ListTrendIncidents returns incidents detected, resolved or closed in [from, to); supported filters are
+// category, criticality, assigned_to and asset_id (primary or linked asset)
+func (r *incidentRepository) ListTrendIncidents(ctx context.Context, tenantID string, from, to time.Time, filters map[string]interface{}) ([]*IncidentTrendRow, error) {
+	whereClause := "WHERE i.tenant_id = $1 AND i.deleted_at IS NULL AND ((i.detected_at >= $2 AND i.detected_at < $3) OR (i.resolved_at >= $2 AND i.resolved_at < $3) OR (i.closed_at >= $2 AND i.closed_at < $3))"
+	args := []interface{}{tenantID, from, to}
+	argIndex := 4
+
+	if category, ok := filters["category"].(string); ok && category != "" {
+		whereClause += fmt.Sprintf(" AND i.category = $%d", argIndex)
+		args = append(args, category)
+		argIndex++
+	}
+
+	if criticality, ok := filters["criticality"].(string); ok && criticality != "" {
+		whereClause += fmt.Sprintf(" AND i.criticality = $%d", argIndex)
+		args = append(args, criticality)
+		argIndex++
+	}
+
+	if assignedTo, ok := filters["assigned_to"].(string); ok && assignedTo != "" {
+		whereClause += fmt.Sprintf(" AND i.assigned_to = $%d", argIndex)
+		args = append(args, assignedTo)
+		argIndex++
+	}
+
+	if assetID, ok := filters["asset_id"].(string); ok && assetID != "" {
+		whereClause += fmt.Sprintf(" AND (i.asset_id = $%d OR EXISTS (SELECT 1 FROM incident_assets ia WHERE ia.incident_id = i.id AND ia.asset_id = $%d))", argIndex, argIndex)
+		args = append(args, assetID)
+		argIndex++
+	}
+
+	query := fmt.Sprintf(`
+		SELECT i.id, i.category, i.criticality, i.detected_at, i.resolved_at, i.closed_at,
+		       i.response_breached, i.resolution_breached,
+		       (SELECT MIN(m.value_minutes) FROM incident_metrics m WHERE m.incident_id = i.id AND m.metric_type = 'mttr'),
+		       (SELECT MIN(m.value_minutes) FROM incident_metrics m WHERE m.incident_id = i.id AND m.metric_type = 'mttd')
+		FROM incidents i
+		%s
+		ORDER BY i.detected_at
+	`, whereClause)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*IncidentTrendRow
+	for rows.Next() {
+		var row IncidentTrendRow
+		var mttr, mttd sql.NullInt64
+		err := rows.Scan(&row.ID, &row.Category, &row.Criticality, &row.DetectedAt, &row.ResolvedAt, &row.ClosedAt,
+			&row.ResponseBreached, &row.ResolutionBreached, &mttr, &mttd)
+		if err != nil {
+			return nil, err
+		}
+		if mttr.Valid {
+			value := int(mttr.Int64)
+			row.MTTRMinutes = &value
+		}
+		if mttd.Valid {
+			value := int(mttd.Int64)
+			row.MTTDMinutes = &value
+		}
+		result = append(result, &row)
+	}
+
+	return result, rows.Err()
+}
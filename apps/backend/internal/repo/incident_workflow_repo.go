@@ -0,0 +1,168 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// IncidentStatusTransition describes an allowed status change and the fields it requires
+type IncidentStatusTransition struct {
+	ID             string    `json:"id"`
+	TenantID       *string   `json:"tenant_id"` // nil for the default workflow
+	FromStatus     string    `json:"from_status"`
+	ToStatus       string    `json:"to_status"`
+	RequiredFields []string  `json:"required_fields"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// IncidentStatusChange is an entry of the incident status timeline
+type IncidentStatusChange struct {
+	ID         string    `json:"id"`
+	IncidentID string    `json:"incident_id"`
+	FromStatus *string   `json:"from_status"` // nil for the creation entry
+	ToStatus   string    `json:"to_status"`
+	IsReopen   bool      `json:"is_reopen"`
+	Comment    *string   `json:"comment"`
+	ChangedBy  *string   `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// Status workflow
+
+// ChangeStatus saves the incident and appends the timeline entry in one transaction. The update only
+// applies while the incident is still in change.FromStatus; false means another transition got there first.
+func (r *incidentRepository) ChangeStatus(ctx context.Context, incident *Incident, change *IncidentStatusChange) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var fromStatus string
+	if change.FromStatus != nil {
+		fromStatus = *change.FromStatus
+	}
+	result, err := tx.ExecContext(ctx, updateIncidentQuery+" AND status = $20", append(updateIncidentArgs(incident), fromStatus)...)
+	if err != nil {
+		return false, err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if updated == 0 {
+		return false, nil
+	}
+
+	if err := insertStatusChange(ctx, tx, change); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *incidentRepository) GetStatusHistory(ctx context.Context, incidentID string) ([]*IncidentStatusChange, error) {
+	query := `
+		SELECT id, incident_id, from_status, to_status, is_reopen, comment, changed_by, changed_at
+		FROM incident_status_history
+		WHERE incident_id = $1
+		ORDER BY changed_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, incidentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*IncidentStatusChange
+	for rows.Next() {
+		var change IncidentStatusChange
+		err := rows.Scan(
+			&change.ID, &change.IncidentID, &change.FromStatus, &change.ToStatus,
+			&change.IsReopen, &change.Comment, &change.ChangedBy, &change.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, &change)
+	}
+
+	return history, rows.Err()
+}
+
+// GetStatusTransitions returns the tenant workflow, or the default one if the tenant has not configured its own
+func (r *incidentRepository) GetStatusTransitions(ctx context.Context, tenantID string) ([]*IncidentStatusTransition, error) {
+	query := `
+		SELECT id, tenant_id, from_status, to_status, required_fields, created_at
+		FROM incident_status_transitions
+		WHERE tenant_id = $1
+		   OR (tenant_id IS NULL AND NOT EXISTS (SELECT 1 FROM incident_status_transitions WHERE tenant_id = $1))
+		ORDER BY from_status, to_status
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []*IncidentStatusTransition
+	for rows.Next() {
+		var transition IncidentStatusTransition
+		err := rows.Scan(
+			&transition.ID, &transition.TenantID, &transition.FromStatus, &transition.ToStatus,
+			pq.Array(&transition.RequiredFields), &transition.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, &transition)
+	}
+
+	return transitions, rows.Err()
+}
+
+// ReplaceStatusTransitions replaces the tenant workflow; an empty list restores the default one
+func (r *incidentRepository) ReplaceStatusTransitions(ctx context.Context, tenantID string, transitions []*IncidentStatusTransition) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM incident_status_transitions WHERE tenant_id = $1`, tenantID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO incident_status_transitions (id, tenant_id, from_status, to_status, required_fields, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, transition := range transitions {
+		_, err := tx.ExecContext(ctx, query,
+			transition.ID, tenantID, transition.FromStatus, transition.ToStatus,
+			pq.Array(transition.RequiredFields), transition.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertStatusChange(ctx context.Context, tx *sql.Tx, change *IncidentStatusChange) error {
+	if change.ID == "" {
+		change.ID = uuid.New().String()
+	}
+	query := `
+		INSERT INTO incident_status_history (id, incident_id, from_status, to_status, is_reopen, comment, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		change.ID, change.IncidentID, change.FromStatus, change.ToStatus,
+		change.IsReopen, change.Comment, change.ChangedBy, change.ChangedAt)
+	return err
+}
@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// RiskAcceptance represents a formal decision to accept a risk until a given date
+type RiskAcceptance struct {
+	ID              string
+	TenantID        string
+	RiskID          string
+	Justification   string
+	RiskLevel       *int
+	LevelLabel      string
+	ApproverRole    *string
+	Status          string
+	RequestedBy     string
+	ApproverUserID  *string
+	DecisionComment *string
+	DecidedAt       *time.Time
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	RequestedByName *string // joined from users table
+	ApproverName    *string // joined from users table
+}
+
+// RiskAcceptanceRule defines who may approve acceptance of a risk of a given level
+type RiskAcceptanceRule struct {
+	ID              string
+	TenantID        string
+	LevelLabel      string
+	ApproverRole    *string
+	MaxValidityDays int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+const riskAcceptanceColumns = `a.id, a.tenant_id, a.risk_id, a.justification, a.risk_level, a.level_label, a.approver_role, a.status,
+	a.requested_by, a.approver_user_id, a.decision_comment, a.decided_at, a.expires_at, a.created_at, a.updated_at,
+	COALESCE(req.first_name || ' ' || req.last_name, req.email) as requested_by_name,
+	COALESCE(apr.first_name || ' ' || apr.last_name, apr.email) as approver_name`
+
+const riskAcceptanceJoins = `
+	FROM risk_acceptances a
+	LEFT JOIN users req ON a.requested_by = req.id
+	LEFT JOIN users apr ON a.approver_user_id = apr.id`
+
+func scanRiskAcceptance(row riskScanner) (*RiskAcceptance, error) {
+	var a RiskAcceptance
+	err := row.Scan(&a.ID, &a.TenantID, &a.RiskID, &a.Justification, &a.RiskLevel, &a.LevelLabel, &a.ApproverRole, &a.Status,
+		&a.RequestedBy, &a.ApproverUserID, &a.DecisionComment, &a.DecidedAt, &a.ExpiresAt, &a.CreatedAt, &a.UpdatedAt,
+		&a.RequestedByName, &a.ApproverName)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *RiskRepo) queryRiskAcceptances(ctx context.Context, where string, args ...interface{}) ([]RiskAcceptance, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+riskAcceptanceColumns+riskAcceptanceJoins+` WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var acceptances []RiskAcceptance
+	for rows.Next() {
+		acceptance, err := scanRiskAcceptance(rows)
+		if err != nil {
+			return nil, err
+		}
+		acceptances = append(acceptances, *acceptance)
+	}
+	return acceptances, rows.Err()
+}
+
+// Risk Acceptances methods
+func (r *RiskRepo) CreateAcceptance(ctx context.Context, acceptance RiskAcceptance) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_acceptances (id, tenant_id, risk_id, justification, risk_level, level_label, approver_role, status, requested_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, acceptance.ID, acceptance.TenantID, acceptance.RiskID, acceptance.Justification, acceptance.RiskLevel, acceptance.LevelLabel,
+		acceptance.ApproverRole, acceptance.Status, acceptance.RequestedBy, acceptance.ExpiresAt)
+	return err
+}
+
+func (r *RiskRepo) GetAcceptance(ctx context.Context, id string) (*RiskAcceptance, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+riskAcceptanceColumns+riskAcceptanceJoins+` WHERE a.id = $1`, id)
+
+	acceptance, err := scanRiskAcceptance(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return acceptance, nil
+}
+
+func (r *RiskRepo) ListAcceptances(ctx context.Context, riskID string) ([]RiskAcceptance, error) {
+	return r.queryRiskAcceptances(ctx, `a.risk_id = $1 ORDER BY a.created_at DESC`, riskID)
+}
+
+// ListAcceptancesByStatus returns the risk's acceptances in the given status
+func (r *RiskRepo) ListAcceptancesByStatus(ctx context.Context, riskID, status string) ([]RiskAcceptance, error) {
+	return r.queryRiskAcceptances(ctx, `a.risk_id = $1 AND a.status = $2 ORDER BY a.created_at DESC`, riskID, status)
+}
+
+// ListExpiredAcceptances returns approved acceptances of all tenants whose expiry date has passed
+func (r *RiskRepo) ListExpiredAcceptances(ctx context.Context, asOf time.Time) ([]RiskAcceptance, error) {
+	return r.queryRiskAcceptances(ctx, `a.status = 'approved' AND a.expires_at < $1 ORDER BY a.expires_at ASC`, asOf)
+}
+
+func (r *RiskRepo) UpdateAcceptanceDecision(ctx context.Context, acceptance RiskAcceptance) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risk_acceptances SET status = $1, approver_user_id = $2, decision_comment = $3, decided_at = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5
+	`, acceptance.Status, acceptance.ApproverUserID, acceptance.DecisionComment, acceptance.DecidedAt, acceptance.ID)
+	return err
+}
+
+// Risk Acceptance Rules methods
+func (r *RiskRepo) ListAcceptanceRules(ctx context.Context, tenantID string) ([]RiskAcceptanceRule, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, level_label, approver_role, max_validity_days, created_at, updated_at
+		FROM risk_acceptance_rules WHERE tenant_id = $1
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []RiskAcceptanceRule
+	for rows.Next() {
+		var rule RiskAcceptanceRule
+		if err := rows.Scan(&rule.ID, &rule.TenantID, &rule.LevelLabel, &rule.ApproverRole, &rule.MaxValidityDays, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *RiskRepo) UpsertAcceptanceRule(ctx context.Context, rule RiskAcceptanceRule) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_acceptance_rules (id, tenant_id, level_label, approver_role, max_validity_days)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, level_label) DO UPDATE
+		SET approver_role = EXCLUDED.approver_role, max_validity_days = EXCLUDED.max_validity_days, updated_at = CURRENT_TIMESTAMP
+	`, rule.ID, rule.TenantID, rule.LevelLabel, rule.ApproverRole, rule.MaxValidityDays)
+	return err
+}
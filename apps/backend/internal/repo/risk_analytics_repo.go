@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RiskHeatMapCell is the number of risks with the given likelihood and impact
+type RiskHeatMapCell struct {
+	Likelihood int
+	Impact     int
+	Count      int
+}
+
+// RiskStatusLevelCount is the number of risks with the given status and level
+type RiskStatusLevelCount struct {
+	Status string
+	Level  int
+	Count  int
+}
+
+// RiskOverdueTreatment is an open treatment task past its due date
+type RiskOverdueTreatment struct {
+	TaskID         string
+	TaskTitle      string
+	TaskStatus     string
+	DueDate        time.Time
+	AssigneeUserID *string
+	AssigneeName   *string
+	PlanID         string
+	PlanTitle      string
+	RiskID         string
+	RiskTitle      string
+	RiskLevel      *int
+}
+
+// riskAnalyticsWhere builds the WHERE clause shared by the analytics queries.
+// Supported filters: category, owner_user_id, asset_id.
+func riskAnalyticsWhere(alias, tenantID string, filters map[string]string) (string, []interface{}) {
+	where := fmt.Sprintf("%s.tenant_id = $1", alias)
+	args := []interface{}{tenantID}
+	for _, column := range []string{"category", "owner_user_id", "asset_id"} {
+		if value := filters[column]; value != "" {
+			args = append(args, value)
+			where += fmt.Sprintf(" AND %s.%s = $%d", alias, column, len(args))
+		}
+	}
+	return where, args
+}
+
+// Risk Analytics methods
+
+// GetHeatMapCounts returns risk counts per likelihood/impact cell, closed risks excluded
+func (r *RiskRepo) GetHeatMapCounts(ctx context.Context, tenantID string, filters map[string]string) ([]RiskHeatMapCell, error) {
+	where, args := riskAnalyticsWhere("r", tenantID, filters)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.likelihood, r.impact, COUNT(*)
+		FROM risks r
+		WHERE `+where+` AND r.status <> 'closed' AND r.likelihood IS NOT NULL AND r.impact IS NOT NULL
+		GROUP BY r.likelihood, r.impact
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cells []RiskHeatMapCell
+	for rows.Next() {
+		var cell RiskHeatMapCell
+		if err := rows.Scan(&cell.Likelihood, &cell.Impact, &cell.Count); err != nil {
+			return nil, err
+		}
+		cells = append(cells, cell)
+	}
+	return cells, rows.Err()
+}
+
+// GetStatusLevelCounts returns risk counts grouped by status and level
+func (r *RiskRepo) GetStatusLevelCounts(ctx context.Context, tenantID string, filters map[string]string) ([]RiskStatusLevelCount, error) {
+	where, args := riskAnalyticsWhere("r", tenantID, filters)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.status, COALESCE(r.level, 0), COUNT(*)
+		FROM risks r
+		WHERE `+where+`
+		GROUP BY r.status, COALESCE(r.level, 0)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []RiskStatusLevelCount
+	for rows.Next() {
+		var count RiskStatusLevelCount
+		if err := rows.Scan(&count.Status, &count.Level, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// ListTopRisks returns the open risks with the highest level
+func (r *RiskRepo) ListTopRisks(ctx context.Context, tenantID string, filters map[string]string, limit int) ([]Risk, error) {
+	where, args := riskAnalyticsWhere("risks", tenantID, filters)
+	args = append(args, limit)
+	return r.queryRisks(ctx, `
+		SELECT `+riskColumns+` FROM risks
+		WHERE `+where+` AND status <> 'closed'
+		ORDER BY level DESC NULLS LAST, due_date ASC NULLS LAST, created_at ASC
+		LIMIT $`+fmt.Sprint(len(args)), args...)
+}
+
+// ListRisksForTrend returns all risks created before the given time, including closed ones
+func (r *RiskRepo) ListRisksForTrend(ctx context.Context, tenantID string, filters map[string]string, createdBefore time.Time) ([]Risk, error) {
+	where, args := riskAnalyticsWhere("risks", tenantID, filters)
+	args = append(args, createdBefore)
+	return r.queryRisks(ctx, `
+		SELECT `+riskColumns+` FROM risks
+		WHERE `+where+` AND created_at <= $`+fmt.Sprint(len(args)), args...)
+}
+
+// ListHistorySince returns the tenant's risk_history entries for the given fields
+// recorded after since, newest first
+func (r *RiskRepo) ListHistorySince(ctx context.Context, tenantID string, fields []string, since time.Time) ([]RiskHistory, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT rh.id, rh.risk_id, rh.field_changed, rh.old_value, rh.new_value, rh.change_reason, COALESCE(rh.changed_by::text, ''), rh.changed_at
+		FROM risk_history rh
+		JOIN risks r ON rh.risk_id = r.id
+		WHERE r.tenant_id = $1 AND rh.field_changed = ANY($2) AND rh.changed_at > $3
+		ORDER BY rh.changed_at DESC
+	`, tenantID, pq.Array(fields), since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []RiskHistory
+	for rows.Next() {
+		var h RiskHistory
+		if err := rows.Scan(&h.ID, &h.RiskID, &h.FieldChanged, &h.OldValue, &h.NewValue, &h.ChangeReason, &h.ChangedBy, &h.ChangedAt); err != nil {
+			return nil, err
+		}
+		h.IsSystem = h.ChangedBy == ""
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
+
+// ListOverdueTreatments returns open tasks of active treatment plans past their due date
+func (r *RiskRepo) ListOverdueTreatments(ctx context.Context, tenantID string, filters map[string]string, asOf time.Time) ([]RiskOverdueTreatment, error) {
+	where, args := riskAnalyticsWhere("r", tenantID, filters)
+	args = append(args, asOf)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.title, t.status, t.due_date, t.assignee_user_id,
+		       COALESCE(u.first_name || ' ' || u.last_name, u.email) as assignee_name,
+		       p.id, p.title, r.id, r.title, r.level
+		FROM risk_treatment_tasks t
+		JOIN risk_treatment_plans p ON t.plan_id = p.id
+		JOIN risks r ON p.risk_id = r.id
+		LEFT JOIN users u ON t.assignee_user_id = u.id
+		WHERE `+where+` AND p.status = 'active' AND t.status IN ('open', 'in_progress')
+		  AND t.due_date IS NOT NULL AND t.due_date < $`+fmt.Sprint(len(args))+`
+		ORDER BY t.due_date ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overdue []RiskOverdueTreatment
+	for rows.Next() {
+		var o RiskOverdueTreatment
+		if err := rows.Scan(&o.TaskID, &o.TaskTitle, &o.TaskStatus, &o.DueDate, &o.AssigneeUserID, &o.AssigneeName,
+			&o.PlanID, &o.PlanTitle, &o.RiskID, &o.RiskTitle, &o.RiskLevel); err != nil {
+			return nil, err
+		}
+		overdue = append(overdue, o)
+	}
+	return overdue, rows.Err()
+}
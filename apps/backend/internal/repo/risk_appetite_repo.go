@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RiskAppetiteStatement defines the acceptable risk level for a category; a nil category is the tenant default
+type RiskAppetiteStatement struct {
+	ID             string
+	TenantID       string
+	Category       *string
+	Statement      string
+	AppetiteLevel  int
+	ToleranceLevel int
+	UpdatedBy      *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// RiskResponseCoverage tells whether a risk has a treatment plan or an approved acceptance
+type RiskResponseCoverage struct {
+	HasTreatment  bool
+	HasAcceptance bool
+}
+
+// Risk Appetite methods
+func (r *RiskRepo) ListAppetiteStatements(ctx context.Context, tenantID string) ([]RiskAppetiteStatement, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, category, statement, appetite_level, tolerance_level, updated_by, created_at, updated_at
+		FROM risk_appetite_statements WHERE tenant_id = $1
+		ORDER BY category NULLS FIRST
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []RiskAppetiteStatement
+	for rows.Next() {
+		var st RiskAppetiteStatement
+		if err := rows.Scan(&st.ID, &st.TenantID, &st.Category, &st.Statement, &st.AppetiteLevel, &st.ToleranceLevel,
+			&st.UpdatedBy, &st.CreatedAt, &st.UpdatedAt); err != nil {
+			return nil, err
+		}
+		statements = append(statements, st)
+	}
+	return statements, rows.Err()
+}
+
+// UpsertAppetiteStatement creates or replaces the statement for the category (case-insensitive)
+func (r *RiskRepo) UpsertAppetiteStatement(ctx context.Context, st RiskAppetiteStatement) (*RiskAppetiteStatement, error) {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO risk_appetite_statements (id, tenant_id, category, statement, appetite_level, tolerance_level, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (tenant_id, LOWER(COALESCE(category, ''))) DO UPDATE
+		SET statement = EXCLUDED.statement, appetite_level = EXCLUDED.appetite_level, tolerance_level = EXCLUDED.tolerance_level,
+			updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, updated_at
+	`, st.ID, st.TenantID, st.Category, st.Statement, st.AppetiteLevel, st.ToleranceLevel, st.UpdatedBy).Scan(&st.ID, &st.CreatedAt, &st.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// DeleteAppetiteStatement returns sql.ErrNoRows if the tenant has no such statement
+func (r *RiskRepo) DeleteAppetiteStatement(ctx context.Context, tenantID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM risk_appetite_statements WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ListRisksAboveAppetite returns open risks whose level exceeds the appetite of their category, highest first
+func (r *RiskRepo) ListRisksAboveAppetite(ctx context.Context, tenantID string) ([]Risk, error) {
+	risks, err := r.queryRisks(ctx, `
+		SELECT `+riskColumns+` FROM risks
+		WHERE tenant_id = $1 AND status <> 'closed' AND level IS NOT NULL
+		ORDER BY level DESC, created_at ASC
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var above []Risk
+	for _, risk := range risks {
+		if risk.AppetiteLevel != nil && *risk.Level > *risk.AppetiteLevel {
+			above = append(above, risk)
+		}
+	}
+	return above, nil
+}
+
+// GetRiskResponseCoverage reports, per risk, whether it has an active or completed treatment plan
+// and whether it has an approved acceptance that has not expired as of asOf
+func (r *RiskRepo) GetRiskResponseCoverage(ctx context.Context, riskIDs []string, asOf time.Time) (map[string]RiskResponseCoverage, error) {
+	coverage := make(map[string]RiskResponseCoverage, len(riskIDs))
+	if len(riskIDs) == 0 {
+		return coverage, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.id,
+			EXISTS (SELECT 1 FROM risk_treatment_plans p WHERE p.risk_id = r.id AND p.status IN ('active', 'completed')),
+			EXISTS (SELECT 1 FROM risk_acceptances ra WHERE ra.risk_id = r.id AND ra.status = 'approved' AND ra.expires_at >= $2)
+		FROM risks r WHERE r.id = ANY($1)
+	`, pq.Array(riskIDs), asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var c RiskResponseCoverage
+		if err := rows.Scan(&id, &c.HasTreatment, &c.HasAcceptance); err != nil {
+			return nil, err
+		}
+		coverage[id] = c
+	}
+	return coverage, rows.Err()
+}
+
+// GetAppetiteStatement returns nil if the tenant has no such statement
+func (r *RiskRepo) GetAppetiteStatement(ctx context.Context, id, tenantID string) (*RiskAppetiteStatement, error) {
+	var st RiskAppetiteStatement
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, category, statement, appetite_level, tolerance_level, updated_by, created_at, updated_at
+		FROM risk_appetite_statements WHERE id = $1 AND tenant_id = $2
+	`, id, tenantID).Scan(&st.ID, &st.TenantID, &st.Category, &st.Statement, &st.AppetiteLevel, &st.ToleranceLevel,
+		&st.UpdatedBy, &st.CreatedAt, &st.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &st, nil
+}
@@ -0,0 +1,154 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RiskCatalogEntry is a threat or vulnerability; entries without a tenant form the built-in catalog
+type RiskCatalogEntry struct {
+	ID          string
+	TenantID    *string
+	Kind        string // threat | vulnerability
+	Code        string
+	Name        string
+	Description *string
+	Source      string // builtin | bdu_fstec | custom
+	AssetTypes  []string
+	IsActive    bool
+	CreatedBy   *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// RiskCatalogFilter narrows catalog listings; empty fields are ignored
+type RiskCatalogFilter struct {
+	AssetType  string
+	Source     string
+	Search     string
+	ActiveOnly bool
+}
+
+const riskCatalogColumns = `id, tenant_id, kind, code, name, description, source, asset_types, is_active, created_by, created_at, updated_at`
+
+func scanRiskCatalogEntry(row riskScanner) (*RiskCatalogEntry, error) {
+	var e RiskCatalogEntry
+	err := row.Scan(&e.ID, &e.TenantID, &e.Kind, &e.Code, &e.Name, &e.Description, &e.Source, pq.Array(&e.AssetTypes),
+		&e.IsActive, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Risk Catalog methods
+
+// ListCatalogEntries returns built-in and tenant entries of one kind ordered by code
+func (r *RiskRepo) ListCatalogEntries(ctx context.Context, tenantID, kind string, filter RiskCatalogFilter) ([]RiskCatalogEntry, error) {
+	query := `SELECT ` + riskCatalogColumns + ` FROM risk_catalog_entries
+		WHERE (tenant_id IS NULL OR tenant_id = $1) AND kind = $2`
+	args := []interface{}{tenantID, kind}
+
+	if filter.AssetType != "" {
+		args = append(args, filter.AssetType)
+		query += fmt.Sprintf(` AND ($%d = ANY(asset_types) OR cardinality(asset_types) = 0)`, len(args))
+	}
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		query += fmt.Sprintf(` AND source = $%d`, len(args))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		query += fmt.Sprintf(` AND (code ILIKE $%d OR name ILIKE $%d)`, len(args), len(args))
+	}
+	if filter.ActiveOnly {
+		query += ` AND is_active = true`
+	}
+	query += ` ORDER BY code`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []RiskCatalogEntry
+	for rows.Next() {
+		entry, err := scanRiskCatalogEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetCatalogEntry returns a built-in or tenant entry visible to the tenant
+func (r *RiskRepo) GetCatalogEntry(ctx context.Context, id, tenantID string) (*RiskCatalogEntry, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+riskCatalogColumns+` FROM risk_catalog_entries
+		WHERE id = $1 AND (tenant_id IS NULL OR tenant_id = $2)
+	`, id, tenantID)
+
+	entry, err := scanRiskCatalogEntry(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// CatalogCodeExists reports whether the tenant already has an entry of this kind and code
+func (r *RiskRepo) CatalogCodeExists(ctx context.Context, tenantID, kind, code, excludeID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM risk_catalog_entries
+			WHERE tenant_id = $1 AND kind = $2 AND code = $3 AND id::text <> $4
+		)
+	`, tenantID, kind, code, excludeID).Scan(&exists)
+	return exists, err
+}
+
+func (r *RiskRepo) CreateCatalogEntry(ctx context.Context, entry RiskCatalogEntry) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_catalog_entries (id, tenant_id, kind, code, name, description, source, asset_types, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, entry.ID, entry.TenantID, entry.Kind, entry.Code, entry.Name, entry.Description, entry.Source, pq.Array(entry.AssetTypes),
+		entry.IsActive, entry.CreatedBy)
+	return err
+}
+
+// UpdateCatalogEntry only updates entries owned by the entry's tenant
+func (r *RiskRepo) UpdateCatalogEntry(ctx context.Context, entry RiskCatalogEntry) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risk_catalog_entries SET code = $1, name = $2, description = $3, source = $4, asset_types = $5, is_active = $6,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $7 AND tenant_id = $8
+	`, entry.Code, entry.Name, entry.Description, entry.Source, pq.Array(entry.AssetTypes), entry.IsActive, entry.ID, entry.TenantID)
+	return err
+}
+
+func (r *RiskRepo) DeleteCatalogEntry(ctx context.Context, id, tenantID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM risk_catalog_entries WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	return err
+}
+
+// GetAssetType returns the type of a non-deleted tenant asset, or "" if not found
+func (r *RiskRepo) GetAssetType(ctx context.Context, assetID, tenantID string) (string, error) {
+	var assetType string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT type FROM assets WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+	`, assetID, tenantID).Scan(&assetType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return assetType, err
+}
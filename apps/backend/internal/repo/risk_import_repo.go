@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Risk Import methods
+
+// CreateRisks inserts all risks in one transaction; either every risk is created or none
+func (r *RiskRepo) CreateRisks(ctx context.Context, risks []Risk) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, risk := range risks {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO risks (id, tenant_id, title, description, category, likelihood, impact, status, owner_user_id, asset_id, threat_id, vulnerability_id, methodology, strategy, due_date)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		`, risk.ID, risk.TenantID, risk.Title, risk.Description, risk.Category, risk.Likelihood, risk.Impact, risk.Status, risk.OwnerUserID, risk.AssetID, risk.ThreatID, risk.VulnerabilityID, risk.Methodology, risk.Strategy, risk.DueDate); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ResolveUserIDsByEmail returns active tenant users keyed by lowercased email
+func (r *RiskRepo) ResolveUserIDsByEmail(ctx context.Context, tenantID string, emails []string) (map[string]string, error) {
+	return resolveUserIDsByEmail(ctx, r.db, tenantID, emails)
+}
+
+func resolveUserIDsByEmail(ctx context.Context, db DBInterface, tenantID string, emails []string) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(emails) == 0 {
+		return result, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, LOWER(email) FROM users
+		WHERE tenant_id = $1 AND is_active = true AND LOWER(email) = ANY($2)
+	`, tenantID, pq.Array(emails))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, email string
+		if err := rows.Scan(&id, &email); err != nil {
+			return nil, err
+		}
+		result[email] = id
+	}
+	return result, rows.Err()
+}
+
+// ResolveAssetIDsByInventoryNumber returns non-deleted tenant assets keyed by uppercased inventory number
+func (r *RiskRepo) ResolveAssetIDsByInventoryNumber(ctx context.Context, tenantID string, numbers []string) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(numbers) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, inventory_number FROM assets
+		WHERE tenant_id = $1 AND deleted_at IS NULL AND UPPER(inventory_number) = ANY($2)
+	`, tenantID, pq.Array(numbers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, number string
+		if err := rows.Scan(&id, &number); err != nil {
+			return nil, err
+		}
+		result[strings.ToUpper(number)] = id
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,253 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// RiskKRI is a key risk indicator with amber/red thresholds
+type RiskKRI struct {
+	ID             string
+	TenantID       string
+	Code           string
+	Name           string
+	Description    *string
+	Unit           *string
+	Direction      string // higher_is_worse | lower_is_worse
+	AmberThreshold float64
+	RedThreshold   float64
+	Frequency      string
+	OwnerUserID    *string
+	IsActive       bool
+	CurrentValue   *float64
+	CurrentStatus  string
+	LastMeasuredAt *time.Time
+	CreatedBy      *string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OwnerName      *string  // joined from users table
+	RiskIDs        []string // loaded from risk_kri_links
+}
+
+// RiskKRIMeasurement is a single value of a key risk indicator
+type RiskKRIMeasurement struct {
+	ID         string
+	KRIID      string
+	Value      float64
+	Status     string
+	MeasuredAt time.Time
+	Source     string
+	Comment    *string
+	RecordedBy *string
+	CreatedAt  time.Time
+}
+
+const riskKRIColumns = `k.id, k.tenant_id, k.code, k.name, k.description, k.unit, k.direction, k.amber_threshold, k.red_threshold,
+	k.frequency, k.owner_user_id, k.is_active, k.current_value, k.current_status, k.last_measured_at, k.created_by, k.created_at, k.updated_at,
+	COALESCE(u.first_name || ' ' || u.last_name, u.email) as owner_name`
+
+const riskKRIJoins = `
+	FROM risk_kris k
+	LEFT JOIN users u ON k.owner_user_id = u.id`
+
+func scanRiskKRI(row riskScanner) (*RiskKRI, error) {
+	var k RiskKRI
+	err := row.Scan(&k.ID, &k.TenantID, &k.Code, &k.Name, &k.Description, &k.Unit, &k.Direction, &k.AmberThreshold, &k.RedThreshold,
+		&k.Frequency, &k.OwnerUserID, &k.IsActive, &k.CurrentValue, &k.CurrentStatus, &k.LastMeasuredAt, &k.CreatedBy, &k.CreatedAt, &k.UpdatedAt,
+		&k.OwnerName)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Risk KRI methods
+func (r *RiskRepo) CreateKRI(ctx context.Context, kri RiskKRI) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_kris (id, tenant_id, code, name, description, unit, direction, amber_threshold, red_threshold, frequency, owner_user_id, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, kri.ID, kri.TenantID, kri.Code, kri.Name, kri.Description, kri.Unit, kri.Direction, kri.AmberThreshold, kri.RedThreshold,
+		kri.Frequency, kri.OwnerUserID, kri.IsActive, kri.CreatedBy)
+	return err
+}
+
+func (r *RiskRepo) GetKRI(ctx context.Context, id, tenantID string) (*RiskKRI, error) {
+	return r.getKRIWhere(ctx, `k.id = $1 AND k.tenant_id = $2`, id, tenantID)
+}
+
+func (r *RiskRepo) GetKRIByCode(ctx context.Context, code, tenantID string) (*RiskKRI, error) {
+	return r.getKRIWhere(ctx, `k.code = $1 AND k.tenant_id = $2`, code, tenantID)
+}
+
+func (r *RiskRepo) getKRIWhere(ctx context.Context, where string, args ...interface{}) (*RiskKRI, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+riskKRIColumns+riskKRIJoins+` WHERE `+where, args...)
+
+	kri, err := scanRiskKRI(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	kri.RiskIDs, err = r.ListKRIRiskIDs(ctx, kri.ID)
+	if err != nil {
+		return nil, err
+	}
+	return kri, nil
+}
+
+// ListKRIs returns the tenant's KRIs; if riskID is set only KRIs linked to that risk
+func (r *RiskRepo) ListKRIs(ctx context.Context, tenantID, riskID string) ([]RiskKRI, error) {
+	query := `SELECT ` + riskKRIColumns + riskKRIJoins + ` WHERE k.tenant_id = $1`
+	args := []interface{}{tenantID}
+	if riskID != "" {
+		query += ` AND EXISTS (SELECT 1 FROM risk_kri_links l WHERE l.kri_id = k.id AND l.risk_id = $2)`
+		args = append(args, riskID)
+	}
+	query += ` ORDER BY k.name`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var kris []RiskKRI
+	for rows.Next() {
+		kri, err := scanRiskKRI(rows)
+		if err != nil {
+			return nil, err
+		}
+		kris = append(kris, *kri)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range kris {
+		kris[i].RiskIDs, err = r.ListKRIRiskIDs(ctx, kris[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return kris, nil
+}
+
+func (r *RiskRepo) UpdateKRI(ctx context.Context, kri RiskKRI) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risk_kris SET code = $1, name = $2, description = $3, unit = $4, direction = $5, amber_threshold = $6, red_threshold = $7,
+			frequency = $8, owner_user_id = $9, is_active = $10, current_status = $11, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $12
+	`, kri.Code, kri.Name, kri.Description, kri.Unit, kri.Direction, kri.AmberThreshold, kri.RedThreshold,
+		kri.Frequency, kri.OwnerUserID, kri.IsActive, kri.CurrentStatus, kri.ID)
+	return err
+}
+
+func (r *RiskRepo) DeleteKRI(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM risk_kris WHERE id = $1`, id)
+	return err
+}
+
+// SetKRIRisks replaces the set of risks linked to a KRI
+func (r *RiskRepo) SetKRIRisks(ctx context.Context, kriID string, riskIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM risk_kri_links WHERE kri_id = $1`, kriID); err != nil {
+		return err
+	}
+	for _, riskID := range riskIDs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO risk_kri_links (kri_id, risk_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+		`, kriID, riskID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *RiskRepo) ListKRIRiskIDs(ctx context.Context, kriID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT risk_id FROM risk_kri_links WHERE kri_id = $1 ORDER BY created_at`, kriID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var riskIDs []string
+	for rows.Next() {
+		var riskID string
+		if err := rows.Scan(&riskID); err != nil {
+			return nil, err
+		}
+		riskIDs = append(riskIDs, riskID)
+	}
+	return riskIDs, rows.Err()
+}
+
+// Risk KRI Measurements methods
+func (r *RiskRepo) AddKRIMeasurement(ctx context.Context, measurement RiskKRIMeasurement) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_kri_measurements (id, kri_id, value, status, measured_at, source, comment, recorded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, measurement.ID, measurement.KRIID, measurement.Value, measurement.Status, measurement.MeasuredAt,
+		measurement.Source, measurement.Comment, measurement.RecordedBy)
+	return err
+}
+
+// ListKRIMeasurements returns measurements within [from, to] in chronological order
+func (r *RiskRepo) ListKRIMeasurements(ctx context.Context, kriID string, from, to time.Time) ([]RiskKRIMeasurement, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, kri_id, value, status, measured_at, source, comment, recorded_by, created_at
+		FROM risk_kri_measurements
+		WHERE kri_id = $1 AND measured_at BETWEEN $2 AND $3
+		ORDER BY measured_at ASC
+	`, kriID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var measurements []RiskKRIMeasurement
+	for rows.Next() {
+		var m RiskKRIMeasurement
+		if err := rows.Scan(&m.ID, &m.KRIID, &m.Value, &m.Status, &m.MeasuredAt, &m.Source, &m.Comment, &m.RecordedBy, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+	return measurements, rows.Err()
+}
+
+// GetLatestKRIMeasurement returns the most recent measurement by measured_at
+func (r *RiskRepo) GetLatestKRIMeasurement(ctx context.Context, kriID string) (*RiskKRIMeasurement, error) {
+	var m RiskKRIMeasurement
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, kri_id, value, status, measured_at, source, comment, recorded_by, created_at
+		FROM risk_kri_measurements
+		WHERE kri_id = $1
+		ORDER BY measured_at DESC, created_at DESC
+		LIMIT 1
+	`, kriID).Scan(&m.ID, &m.KRIID, &m.Value, &m.Status, &m.MeasuredAt, &m.Source, &m.Comment, &m.RecordedBy, &m.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// UpdateKRICurrent stores the latest value and status on the KRI
+func (r *RiskRepo) UpdateKRICurrent(ctx context.Context, kriID string, value *float64, status string, measuredAt *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risk_kris SET current_value = $1, current_status = $2, last_measured_at = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`, value, status, measuredAt, kriID)
+	return err
+}
@@ -0,0 +1,200 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// RiskQuantification holds the FAIR inputs of a risk as min / most likely / max estimates
+type RiskQuantification struct {
+	RiskID        string
+	TenantID      string
+	LEFMin        float64 // loss event frequency, events per year
+	LEFMostLikely float64
+	LEFMax        float64
+	LMMin         float64 // loss magnitude per event
+	LMMostLikely  float64
+	LMMax         float64
+	Currency      string
+	UpdatedBy     *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// LossExceedancePoint is the probability that the annual loss exceeds Loss
+type LossExceedancePoint struct {
+	Loss        float64 `json:"loss"`
+	Probability float64 `json:"probability"`
+}
+
+// RiskSimulation is the stored result of a Monte Carlo run
+type RiskSimulation struct {
+	ID              string
+	RiskID          string
+	TenantID        string
+	Iterations      int
+	Seed            int64
+	Inputs          RiskQuantification
+	Currency        string
+	MeanLoss        float64
+	P10             float64
+	P50             float64
+	P90             float64
+	P95             float64
+	P99             float64
+	MaxLoss         float64
+	LossProbability float64
+	ExceedanceCurve []LossExceedancePoint
+	CreatedBy       *string
+	CreatedAt       time.Time
+}
+
+// riskQuantificationInputs is the JSON snapshot of inputs stored with a simulation
+type riskQuantificationInputs struct {
+	LEFMin        float64 `json:"lef_min"`
+	LEFMostLikely float64 `json:"lef_most_likely"`
+	LEFMax        float64 `json:"lef_max"`
+	LMMin         float64 `json:"lm_min"`
+	LMMostLikely  float64 `json:"lm_most_likely"`
+	LMMax         float64 `json:"lm_max"`
+}
+
+// Risk Quantification methods
+func (r *RiskRepo) GetQuantification(ctx context.Context, riskID string) (*RiskQuantification, error) {
+	var q RiskQuantification
+	err := r.db.QueryRowContext(ctx, `
+		SELECT risk_id, tenant_id, lef_min, lef_most_likely, lef_max, lm_min, lm_most_likely, lm_max, currency, updated_by, created_at, updated_at
+		FROM risk_quantifications WHERE risk_id = $1
+	`, riskID).Scan(&q.RiskID, &q.TenantID, &q.LEFMin, &q.LEFMostLikely, &q.LEFMax, &q.LMMin, &q.LMMostLikely, &q.LMMax,
+		&q.Currency, &q.UpdatedBy, &q.CreatedAt, &q.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *RiskRepo) UpsertQuantification(ctx context.Context, q RiskQuantification) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_quantifications (risk_id, tenant_id, lef_min, lef_most_likely, lef_max, lm_min, lm_most_likely, lm_max, currency, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (risk_id) DO UPDATE
+		SET lef_min = EXCLUDED.lef_min, lef_most_likely = EXCLUDED.lef_most_likely, lef_max = EXCLUDED.lef_max,
+			lm_min = EXCLUDED.lm_min, lm_most_likely = EXCLUDED.lm_most_likely, lm_max = EXCLUDED.lm_max,
+			currency = EXCLUDED.currency, updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP
+	`, q.RiskID, q.TenantID, q.LEFMin, q.LEFMostLikely, q.LEFMax, q.LMMin, q.LMMostLikely, q.LMMax, q.Currency, q.UpdatedBy)
+	return err
+}
+
+// DeleteQuantification removes the inputs and clears the stored ALE on the risk; simulation history is kept
+func (r *RiskRepo) DeleteQuantification(ctx context.Context, riskID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM risk_quantifications WHERE risk_id = $1`, riskID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE risks SET ale_mean = NULL, ale_p90 = NULL, quantified_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+	`, riskID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Risk Simulations methods
+
+// CreateSimulation stores the run and copies its mean and P90 onto the risk
+func (r *RiskRepo) CreateSimulation(ctx context.Context, sim RiskSimulation) error {
+	inputs, err := json.Marshal(riskQuantificationInputs{
+		LEFMin:        sim.Inputs.LEFMin,
+		LEFMostLikely: sim.Inputs.LEFMostLikely,
+		LEFMax:        sim.Inputs.LEFMax,
+		LMMin:         sim.Inputs.LMMin,
+		LMMostLikely:  sim.Inputs.LMMostLikely,
+		LMMax:         sim.Inputs.LMMax,
+	})
+	if err != nil {
+		return err
+	}
+	curve, err := json.Marshal(sim.ExceedanceCurve)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO risk_simulations (id, risk_id, tenant_id, iterations, seed, inputs, currency, mean_loss, p10, p50, p90, p95, p99,
+			max_loss, loss_probability, exceedance_curve, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+	`, sim.ID, sim.RiskID, sim.TenantID, sim.Iterations, sim.Seed, inputs, sim.Currency, sim.MeanLoss, sim.P10, sim.P50, sim.P90, sim.P95, sim.P99,
+		sim.MaxLoss, sim.LossProbability, curve, sim.CreatedBy, sim.CreatedAt); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE risks SET ale_mean = $1, ale_p90 = $2, quantified_at = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4
+	`, sim.MeanLoss, sim.P90, sim.CreatedAt, sim.RiskID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListSimulations returns the latest runs of a risk, newest first
+func (r *RiskRepo) ListSimulations(ctx context.Context, riskID string, limit int) ([]RiskSimulation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, risk_id, tenant_id, iterations, seed, inputs, currency, mean_loss, p10, p50, p90, p95, p99,
+			max_loss, loss_probability, exceedance_curve, created_by, created_at
+		FROM risk_simulations WHERE risk_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, riskID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var simulations []RiskSimulation
+	for rows.Next() {
+		var sim RiskSimulation
+		var inputs, curve []byte
+		if err := rows.Scan(&sim.ID, &sim.RiskID, &sim.TenantID, &sim.Iterations, &sim.Seed, &inputs, &sim.Currency,
+			&sim.MeanLoss, &sim.P10, &sim.P50, &sim.P90, &sim.P95, &sim.P99, &sim.MaxLoss, &sim.LossProbability,
+			&curve, &sim.CreatedBy, &sim.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		var snapshot riskQuantificationInputs
+		if err := json.Unmarshal(inputs, &snapshot); err != nil {
+			return nil, err
+		}
+		sim.Inputs = RiskQuantification{
+			RiskID:        sim.RiskID,
+			TenantID:      sim.TenantID,
+			LEFMin:        snapshot.LEFMin,
+			LEFMostLikely: snapshot.LEFMostLikely,
+			LEFMax:        snapshot.LEFMax,
+			LMMin:         snapshot.LMMin,
+			LMMostLikely:  snapshot.LMMostLikely,
+			LMMax:         snapshot.LMMax,
+			Currency:      sim.Currency,
+		}
+		if err := json.Unmarshal(curve, &sim.ExceedanceCurve); err != nil {
+			return nil, err
+		}
+		simulations = append(simulations, sim)
+	}
+	return simulations, rows.Err()
+}
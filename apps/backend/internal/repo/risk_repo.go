@@ -2,29 +2,40 @@ package repo
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
-
-	"github.com/jackc/pgx/v5"
 )
 
 type Risk struct {
-	ID          string
-	TenantID    string
-	Title       string
-	Description *string
-	Category    *string
-	Likelihood  *int
-	Impact      *int
-	Level       *int
-	Status      string
-	OwnerUserID *string
-	AssetID     *string
-	Methodology *string
-	Strategy    *string
-	DueDate     *time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID                string
+	TenantID          string
+	Title             string
+	Description       *string
+	Category          *string
+	Likelihood        *int
+	Impact            *int
+	Level             *int
+	Status            string
+	OwnerUserID       *string
+	AssetID           *string
+	ThreatID          *string // from the threat catalog
+	VulnerabilityID   *string // from the vulnerability catalog
+	Methodology       *string
+	Strategy          *string
+	DueDate           *time.Time
+	TreatmentProgress *int // rolled up from active treatment plans
+	NextReviewDate    *time.Time
+	LastReviewedAt    *time.Time
+	LastReviewedBy    *string
+	ALEMean           *float64 // from the latest quantitative simulation
+	ALEP90            *float64
+	QuantifiedAt      *time.Time
+	AppetiteLevel     *int // from the matching risk appetite statement
+	ToleranceLevel    *int
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
 
 // RiskControl represents a control associated with a risk
@@ -62,9 +73,10 @@ type RiskHistory struct {
 	OldValue      *string
 	NewValue      *string
 	ChangeReason  *string
-	ChangedBy     string
+	ChangedBy     string // empty for system-generated entries
 	ChangedAt     time.Time
 	ChangedByName *string // joined from users table
+	IsSystem      bool
 }
 
 // RiskAttachment represents an attachment to a risk
@@ -92,6 +104,30 @@ type RiskTag struct {
 	CreatedAt time.Time
 }
 
+// riskColumns lists the risks table columns in the order expected by scanRisk;
+// queries must select FROM risks without an alias for the appetite subqueries
+const riskColumns = `id, tenant_id, title, description, category, likelihood, impact, level, status, owner_user_id, asset_id, threat_id, vulnerability_id, methodology, strategy, due_date, treatment_progress, next_review_date, last_reviewed_at, last_reviewed_by, ale_mean, ale_p90, quantified_at, ` +
+	`(SELECT a.appetite_level ` + riskAppetiteMatch + `) AS appetite_level, (SELECT a.tolerance_level ` + riskAppetiteMatch + `) AS tolerance_level, created_at, updated_at`
+
+// riskAppetiteMatch finds the appetite statement for the risk's category, falling back to the tenant default
+const riskAppetiteMatch = `FROM risk_appetite_statements a
+	WHERE a.tenant_id = risks.tenant_id AND (a.category IS NULL OR LOWER(a.category) = LOWER(risks.category))
+	ORDER BY a.category IS NULL LIMIT 1`
+
+// riskScanner is satisfied by both *sql.Row and *sql.Rows
+type riskScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRisk(row riskScanner) (*Risk, error) {
+	var risk Risk
+	err := row.Scan(&risk.ID, &risk.TenantID, &risk.Title, &risk.Description, &risk.Category, &risk.Likelihood, &risk.Impact, &risk.Level, &risk.Status, &risk.OwnerUserID, &risk.AssetID, &risk.ThreatID, &risk.VulnerabilityID, &risk.Methodology, &risk.Strategy, &risk.DueDate, &risk.TreatmentProgress, &risk.NextReviewDate, &risk.LastReviewedAt, &risk.LastReviewedBy, &risk.ALEMean, &risk.ALEP90, &risk.QuantifiedAt, &risk.AppetiteLevel, &risk.ToleranceLevel, &risk.CreatedAt, &risk.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &risk, nil
+}
+
 type RiskRepo struct {
 	db *DB
 }
@@ -102,51 +138,40 @@ func NewRiskRepo(db *DB) *RiskRepo {
 
 func (r *RiskRepo) Create(ctx context.Context, risk Risk) error {
 	_, err := r.db.Exec(`
-		INSERT INTO risks (id, tenant_id, title, description, category, likelihood, impact, status, owner_user_id, asset_id, methodology, strategy, due_date)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-	`, risk.ID, risk.TenantID, risk.Title, risk.Description, risk.Category, risk.Likelihood, risk.Impact, risk.Status, risk.OwnerUserID, risk.AssetID, risk.Methodology, risk.Strategy, risk.DueDate)
+		INSERT INTO risks (id, tenant_id, title, description, category, likelihood, impact, status, owner_user_id, asset_id, threat_id, vulnerability_id, methodology, strategy, due_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`, risk.ID, risk.TenantID, risk.Title, risk.Description, risk.Category, risk.Likelihood, risk.Impact, risk.Status, risk.OwnerUserID, risk.AssetID, risk.ThreatID, risk.VulnerabilityID, risk.Methodology, risk.Strategy, risk.DueDate)
 	return err
 }
 
 func (r *RiskRepo) GetByID(ctx context.Context, id string) (*Risk, error) {
-	row := r.db.QueryRow(`
-		SELECT id, tenant_id, title, description, category, likelihood, impact, level, status, owner_user_id, asset_id, methodology, strategy, due_date, created_at, updated_at
-		FROM risks WHERE id = $1
-	`, id)
+	row := r.db.QueryRow(`SELECT `+riskColumns+` FROM risks WHERE id = $1`, id)
 
-	var risk Risk
-	err := row.Scan(&risk.ID, &risk.TenantID, &risk.Title, &risk.Description, &risk.Category, &risk.Likelihood, &risk.Impact, &risk.Level, &risk.Status, &risk.OwnerUserID, &risk.AssetID, &risk.Methodology, &risk.Strategy, &risk.DueDate, &risk.CreatedAt, &risk.UpdatedAt)
+	risk, err := scanRisk(row)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &risk, nil
+	return risk, nil
 }
 
 func (r *RiskRepo) GetByIDWithTenant(ctx context.Context, id, tenantID string) (*Risk, error) {
-	row := r.db.QueryRow(`
-		SELECT id, tenant_id, title, description, category, likelihood, impact, level, status, owner_user_id, asset_id, methodology, strategy, due_date, created_at, updated_at
-		FROM risks WHERE id = $1 AND tenant_id = $2
-	`, id, tenantID)
+	row := r.db.QueryRow(`SELECT `+riskColumns+` FROM risks WHERE id = $1 AND tenant_id = $2`, id, tenantID)
 
-	var risk Risk
-	err := row.Scan(&risk.ID, &risk.TenantID, &risk.Title, &risk.Description, &risk.Category, &risk.Likelihood, &risk.Impact, &risk.Level, &risk.Status, &risk.OwnerUserID, &risk.AssetID, &risk.Methodology, &risk.Strategy, &risk.DueDate, &risk.CreatedAt, &risk.UpdatedAt)
+	risk, err := scanRisk(row)
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	return &risk, nil
+	return risk, nil
 }
 
 func (r *RiskRepo) List(ctx context.Context, tenantID string) ([]Risk, error) {
-	rows, err := r.db.Query(`
-		SELECT id, tenant_id, title, description, category, likelihood, impact, level, status, owner_user_id, asset_id, methodology, strategy, due_date, created_at, updated_at
-		FROM risks WHERE tenant_id = $1 ORDER BY created_at DESC
-	`, tenantID)
+	rows, err := r.db.Query(`SELECT `+riskColumns+` FROM risks WHERE tenant_id = $1 ORDER BY created_at DESC`, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,21 +179,17 @@ func (r *RiskRepo) List(ctx context.Context, tenantID string) ([]Risk, error) {
 
 	var risks []Risk
 	for rows.Next() {
-		var risk Risk
-		err := rows.Scan(&risk.ID, &risk.TenantID, &risk.Title, &risk.Description, &risk.Category, &risk.Likelihood, &risk.Impact, &risk.Level, &risk.Status, &risk.OwnerUserID, &risk.AssetID, &risk.Methodology, &risk.Strategy, &risk.DueDate, &risk.CreatedAt, &risk.UpdatedAt)
+		risk, err := scanRisk(rows)
 		if err != nil {
 			return nil, err
 		}
-		risks = append(risks, risk)
+		risks = append(risks, *risk)
 	}
 	return risks, nil
 }
 
 func (r *RiskRepo) ListWithFilters(ctx context.Context, tenantID string, filters map[string]interface{}, sortField, sortDirection string) ([]Risk, error) {
-	query := `
-		SELECT id, tenant_id, title, description, category, likelihood, impact, level, status, owner_user_id, asset_id, methodology, strategy, due_date, created_at, updated_at
-		FROM risks WHERE tenant_id = $1
-	`
+	query := `SELECT ` + riskColumns + ` FROM risks WHERE tenant_id = $1`
 	args := []interface{}{tenantID}
 	argIndex := 2
 
@@ -198,6 +219,11 @@ func (r *RiskRepo) ListWithFilters(ctx context.Context, tenantID string, filters
 		args = append(args, ownerUserID)
 		argIndex++
 	}
+	if assetID, ok := filters["asset_id"].(string); ok && assetID != "" {
+		query += fmt.Sprintf(" AND asset_id = $%d", argIndex)
+		args = append(args, assetID)
+		argIndex++
+	}
 	if methodology, ok := filters["methodology"].(string); ok && methodology != "" {
 		query += fmt.Sprintf(" AND methodology = $%d", argIndex)
 		args = append(args, methodology)
@@ -238,21 +264,20 @@ func (r *RiskRepo) ListWithFilters(ctx context.Context, tenantID string, filters
 
 	var risks []Risk
 	for rows.Next() {
-		var risk Risk
-		err := rows.Scan(&risk.ID, &risk.TenantID, &risk.Title, &risk.Description, &risk.Category, &risk.Likelihood, &risk.Impact, &risk.Level, &risk.Status, &risk.OwnerUserID, &risk.AssetID, &risk.Methodology, &risk.Strategy, &risk.DueDate, &risk.CreatedAt, &risk.UpdatedAt)
+		risk, err := scanRisk(rows)
 		if err != nil {
 			return nil, err
 		}
-		risks = append(risks, risk)
+		risks = append(risks, *risk)
 	}
 	return risks, nil
 }
 
 func (r *RiskRepo) Update(ctx context.Context, risk Risk) error {
 	_, err := r.db.Exec(`
-		UPDATE risks SET title = $1, description = $2, category = $3, likelihood = $4, impact = $5, status = $6, owner_user_id = $7, asset_id = $8, methodology = $9, strategy = $10, due_date = $11, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $12
-	`, risk.Title, risk.Description, risk.Category, risk.Likelihood, risk.Impact, risk.Status, risk.OwnerUserID, risk.AssetID, risk.Methodology, risk.Strategy, risk.DueDate, risk.ID)
+		UPDATE risks SET title = $1, description = $2, category = $3, likelihood = $4, impact = $5, status = $6, owner_user_id = $7, asset_id = $8, threat_id = $9, vulnerability_id = $10, methodology = $11, strategy = $12, due_date = $13, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $14
+	`, risk.Title, risk.Description, risk.Category, risk.Likelihood, risk.Impact, risk.Status, risk.OwnerUserID, risk.AssetID, risk.ThreatID, risk.VulnerabilityID, risk.Methodology, risk.Strategy, risk.DueDate, risk.ID)
 	return err
 }
 
@@ -358,14 +383,14 @@ func (r *RiskRepo) GetComments(ctx context.Context, riskID string, includeIntern
 func (r *RiskRepo) AddHistory(ctx context.Context, history RiskHistory) error {
 	_, err := r.db.Exec(`
 		INSERT INTO risk_history (id, risk_id, field_changed, old_value, new_value, change_reason, changed_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid)
 	`, history.ID, history.RiskID, history.FieldChanged, history.OldValue, history.NewValue, history.ChangeReason, history.ChangedBy)
 	return err
 }
 
 func (r *RiskRepo) GetHistory(ctx context.Context, riskID string) ([]RiskHistory, error) {
 	rows, err := r.db.Query(`
-		SELECT rh.id, rh.risk_id, rh.field_changed, rh.old_value, rh.new_value, rh.change_reason, rh.changed_by, rh.changed_at,
+		SELECT rh.id, rh.risk_id, rh.field_changed, rh.old_value, rh.new_value, rh.change_reason, COALESCE(rh.changed_by::text, ''), rh.changed_at,
 		       COALESCE(u.first_name || ' ' || u.last_name, u.email) as changed_by_name
 		FROM risk_history rh
 		LEFT JOIN users u ON rh.changed_by = u.id
@@ -383,6 +408,7 @@ func (r *RiskRepo) GetHistory(ctx context.Context, riskID string) ([]RiskHistory
 		if err != nil {
 			return nil, err
 		}
+		h.IsSystem = h.ChangedBy == ""
 		history = append(history, h)
 	}
 	return history, nil
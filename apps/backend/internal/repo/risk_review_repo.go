@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// RiskReviewInterval defines how often risks of a category or level must be reviewed
+type RiskReviewInterval struct {
+	ID           string
+	TenantID     string
+	Scope        string // category | level
+	ScopeValue   string
+	IntervalDays int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Risk Review Intervals methods
+func (r *RiskRepo) ListReviewIntervals(ctx context.Context, tenantID string) ([]RiskReviewInterval, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, scope, scope_value, interval_days, created_at, updated_at
+		FROM risk_review_intervals WHERE tenant_id = $1
+		ORDER BY scope, scope_value
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var intervals []RiskReviewInterval
+	for rows.Next() {
+		var interval RiskReviewInterval
+		if err := rows.Scan(&interval.ID, &interval.TenantID, &interval.Scope, &interval.ScopeValue, &interval.IntervalDays, &interval.CreatedAt, &interval.UpdatedAt); err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals, rows.Err()
+}
+
+func (r *RiskRepo) UpsertReviewInterval(ctx context.Context, interval RiskReviewInterval) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_review_intervals (id, tenant_id, scope, scope_value, interval_days)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, scope, scope_value) DO UPDATE
+		SET interval_days = EXCLUDED.interval_days, updated_at = CURRENT_TIMESTAMP
+	`, interval.ID, interval.TenantID, interval.Scope, interval.ScopeValue, interval.IntervalDays)
+	return err
+}
+
+// DeleteReviewInterval returns sql.ErrNoRows if the tenant has no such interval
+func (r *RiskRepo) DeleteReviewInterval(ctx context.Context, tenantID, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM risk_review_intervals WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Risk Review methods
+func (r *RiskRepo) SetNextReviewDate(ctx context.Context, riskID string, nextReview *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE risks SET next_review_date = $1 WHERE id = $2`, nextReview, riskID)
+	return err
+}
+
+// RecordReview stores the reviewer and schedules the next review of the risk
+func (r *RiskRepo) RecordReview(ctx context.Context, riskID, reviewedBy string, reviewedAt time.Time, nextReview *time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risks SET last_reviewed_at = $1, last_reviewed_by = $2, next_review_date = $3, review_reminded_on = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`, reviewedAt, reviewedBy, nextReview, riskID)
+	return err
+}
+
+// ListReviewQueue returns open risks of the tenant whose next review is due on or before dueBy
+func (r *RiskRepo) ListReviewQueue(ctx context.Context, tenantID string, dueBy time.Time) ([]Risk, error) {
+	return r.queryRisks(ctx, `
+		SELECT `+riskColumns+` FROM risks
+		WHERE tenant_id = $1 AND status <> 'closed' AND next_review_date IS NOT NULL AND next_review_date <= $2
+		ORDER BY next_review_date ASC, level DESC
+	`, tenantID, dueBy)
+}
+
+// ListRisksDueForReviewReminder returns open risks of all tenants that are due for review
+// and have not been reminded about since the review became due
+func (r *RiskRepo) ListRisksDueForReviewReminder(ctx context.Context, asOf time.Time) ([]Risk, error) {
+	return r.queryRisks(ctx, `
+		SELECT `+riskColumns+` FROM risks
+		WHERE status <> 'closed' AND next_review_date IS NOT NULL AND next_review_date <= $1
+		  AND (review_reminded_on IS NULL OR review_reminded_on < next_review_date)
+		ORDER BY next_review_date ASC
+	`, asOf)
+}
+
+// ListRisksWithoutReviewDate returns open risks of all tenants that have no review scheduled
+func (r *RiskRepo) ListRisksWithoutReviewDate(ctx context.Context) ([]Risk, error) {
+	return r.queryRisks(ctx, `
+		SELECT `+riskColumns+` FROM risks
+		WHERE status <> 'closed' AND next_review_date IS NULL
+	`)
+}
+
+func (r *RiskRepo) MarkReviewReminded(ctx context.Context, riskID string, remindedOn time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE risks SET review_reminded_on = $1 WHERE id = $2`, remindedOn, riskID)
+	return err
+}
+
+func (r *RiskRepo) queryRisks(ctx context.Context, query string, args ...interface{}) ([]Risk, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var risks []Risk
+	for rows.Next() {
+		risk, err := scanRisk(rows)
+		if err != nil {
+			return nil, err
+		}
+		risks = append(risks, *risk)
+	}
+	return risks, rows.Err()
+}
@@ -0,0 +1,240 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// RiskTreatmentPlan represents a treatment plan attached to a risk
+type RiskTreatmentPlan struct {
+	ID          string
+	TenantID    string
+	RiskID      string
+	Title       string
+	Description *string
+	Strategy    string
+	Status      string
+	OwnerUserID *string
+	Budget      *float64
+	Currency    string
+	StartDate   *time.Time
+	DueDate     *time.Time
+	Progress    int
+	CompletedAt *time.Time
+	CreatedBy   *string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	OwnerName   *string // joined from users table
+}
+
+// RiskTreatmentTask represents an ordered step of a treatment plan
+type RiskTreatmentTask struct {
+	ID                 string
+	PlanID             string
+	Position           int
+	Title              string
+	Description        *string
+	AssigneeUserID     *string
+	DueDate            *time.Time
+	Status             string
+	Cost               *float64
+	Evidence           *string
+	EvidenceDocumentID *string
+	CompletedBy        *string
+	CompletedAt        *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	AssigneeName       *string // joined from users table
+}
+
+const treatmentPlanColumns = `p.id, p.tenant_id, p.risk_id, p.title, p.description, p.strategy, p.status, p.owner_user_id, p.budget, p.currency,
+	p.start_date, p.due_date, p.progress, p.completed_at, p.created_by, p.created_at, p.updated_at,
+	COALESCE(u.first_name || ' ' || u.last_name, u.email) as owner_name`
+
+const treatmentTaskColumns = `t.id, t.plan_id, t.position, t.title, t.description, t.assignee_user_id, t.due_date, t.status, t.cost,
+	t.evidence, t.evidence_document_id, t.completed_by, t.completed_at, t.created_at, t.updated_at,
+	COALESCE(u.first_name || ' ' || u.last_name, u.email) as assignee_name`
+
+func scanTreatmentPlan(row riskScanner) (*RiskTreatmentPlan, error) {
+	var p RiskTreatmentPlan
+	err := row.Scan(&p.ID, &p.TenantID, &p.RiskID, &p.Title, &p.Description, &p.Strategy, &p.Status, &p.OwnerUserID, &p.Budget, &p.Currency,
+		&p.StartDate, &p.DueDate, &p.Progress, &p.CompletedAt, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt, &p.OwnerName)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func scanTreatmentTask(row riskScanner) (*RiskTreatmentTask, error) {
+	var t RiskTreatmentTask
+	err := row.Scan(&t.ID, &t.PlanID, &t.Position, &t.Title, &t.Description, &t.AssigneeUserID, &t.DueDate, &t.Status, &t.Cost,
+		&t.Evidence, &t.EvidenceDocumentID, &t.CompletedBy, &t.CompletedAt, &t.CreatedAt, &t.UpdatedAt, &t.AssigneeName)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Risk Treatment Plans methods
+func (r *RiskRepo) CreateTreatmentPlan(ctx context.Context, plan RiskTreatmentPlan) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_treatment_plans (id, tenant_id, risk_id, title, description, strategy, status, owner_user_id, budget, currency, start_date, due_date, progress, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, plan.ID, plan.TenantID, plan.RiskID, plan.Title, plan.Description, plan.Strategy, plan.Status, plan.OwnerUserID, plan.Budget, plan.Currency, plan.StartDate, plan.DueDate, plan.Progress, plan.CreatedBy)
+	return err
+}
+
+func (r *RiskRepo) GetTreatmentPlan(ctx context.Context, id string) (*RiskTreatmentPlan, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+treatmentPlanColumns+`
+		FROM risk_treatment_plans p
+		LEFT JOIN users u ON p.owner_user_id = u.id
+		WHERE p.id = $1
+	`, id)
+
+	plan, err := scanTreatmentPlan(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (r *RiskRepo) ListTreatmentPlans(ctx context.Context, riskID string) ([]RiskTreatmentPlan, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+treatmentPlanColumns+`
+		FROM risk_treatment_plans p
+		LEFT JOIN users u ON p.owner_user_id = u.id
+		WHERE p.risk_id = $1 ORDER BY p.created_at ASC
+	`, riskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []RiskTreatmentPlan
+	for rows.Next() {
+		plan, err := scanTreatmentPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, rows.Err()
+}
+
+func (r *RiskRepo) UpdateTreatmentPlan(ctx context.Context, plan RiskTreatmentPlan) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risk_treatment_plans SET title = $1, description = $2, strategy = $3, status = $4, owner_user_id = $5, budget = $6, currency = $7,
+			start_date = $8, due_date = $9, progress = $10, completed_at = $11, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $12
+	`, plan.Title, plan.Description, plan.Strategy, plan.Status, plan.OwnerUserID, plan.Budget, plan.Currency, plan.StartDate, plan.DueDate, plan.Progress, plan.CompletedAt, plan.ID)
+	return err
+}
+
+func (r *RiskRepo) DeleteTreatmentPlan(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM risk_treatment_plans WHERE id = $1", id)
+	return err
+}
+
+// UpdateTreatmentProgress stores the rolled-up treatment progress on the risk itself
+func (r *RiskRepo) UpdateTreatmentProgress(ctx context.Context, riskID string, progress *int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risks SET treatment_progress = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`, progress, riskID)
+	return err
+}
+
+// Risk Treatment Tasks methods
+func (r *RiskRepo) AddTreatmentTask(ctx context.Context, task RiskTreatmentTask) error {
+	// New tasks are appended to the end of the plan
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO risk_treatment_tasks (id, plan_id, position, title, description, assignee_user_id, due_date, status, cost)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM risk_treatment_tasks WHERE plan_id = $2), $3, $4, $5, $6, $7, $8)
+	`, task.ID, task.PlanID, task.Title, task.Description, task.AssigneeUserID, task.DueDate, task.Status, task.Cost)
+	return err
+}
+
+func (r *RiskRepo) GetTreatmentTask(ctx context.Context, id string) (*RiskTreatmentTask, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+treatmentTaskColumns+`
+		FROM risk_treatment_tasks t
+		LEFT JOIN users u ON t.assignee_user_id = u.id
+		WHERE t.id = $1
+	`, id)
+
+	task, err := scanTreatmentTask(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return task, nil
+}
+
+func (r *RiskRepo) ListTreatmentTasks(ctx context.Context, planID string) ([]RiskTreatmentTask, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+treatmentTaskColumns+`
+		FROM risk_treatment_tasks t
+		LEFT JOIN users u ON t.assignee_user_id = u.id
+		WHERE t.plan_id = $1 ORDER BY t.position ASC, t.created_at ASC
+	`, planID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []RiskTreatmentTask
+	for rows.Next() {
+		task, err := scanTreatmentTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, rows.Err()
+}
+
+func (r *RiskRepo) UpdateTreatmentTask(ctx context.Context, task RiskTreatmentTask) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE risk_treatment_tasks SET title = $1, description = $2, assignee_user_id = $3, due_date = $4, status = $5, cost = $6,
+			evidence = $7, evidence_document_id = $8, completed_by = $9, completed_at = $10, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $11
+	`, task.Title, task.Description, task.AssigneeUserID, task.DueDate, task.Status, task.Cost, task.Evidence, task.EvidenceDocumentID, task.CompletedBy, task.CompletedAt, task.ID)
+	return err
+}
+
+func (r *RiskRepo) DeleteTreatmentTask(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM risk_treatment_tasks WHERE id = $1", id)
+	return err
+}
+
+// ReorderTreatmentTasks assigns positions to the plan's tasks in the given order
+func (r *RiskRepo) ReorderTreatmentTasks(ctx context.Context, planID string, taskIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, taskID := range taskIDs {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE risk_treatment_tasks SET position = $1, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $2 AND plan_id = $3
+		`, i+1, taskID, planID)
+		if err != nil {
+			return err
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// UserNotification is an in-app notification delivered to a user's inbox
+type UserNotification struct {
+	ID        string
+	TenantID  string
+	UserID    string
+	Type      string
+	Title     string
+	Message   string
+	Entity    *string
+	EntityID  *string
+	IsRead    bool
+	CreatedAt time.Time
+	ReadAt    *time.Time
+}
+
+type UserNotificationRepo struct {
+	db *DB
+}
+
+func NewUserNotificationRepo(db *DB) *UserNotificationRepo {
+	return &UserNotificationRepo{db: db}
+}
+
+func (r *UserNotificationRepo) Create(ctx context.Context, n UserNotification) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_notifications (id, tenant_id, user_id, type, title, message, entity, entity_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, n.ID, n.TenantID, n.UserID, n.Type, n.Title, n.Message, n.Entity, n.EntityID, n.CreatedAt)
+	return err
+}
+
+// ListByUser returns the user's notifications, newest first
+func (r *UserNotificationRepo) ListByUser(ctx context.Context, userID, tenantID string, unreadOnly bool, limit int) ([]UserNotification, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, tenant_id, user_id, type, title, message, entity, entity_id, is_read, created_at, read_at
+		FROM user_notifications
+		WHERE user_id = $1 AND tenant_id = $2 AND ($3 = false OR is_read = false)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`, userID, tenantID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []UserNotification
+	for rows.Next() {
+		var n UserNotification
+		var entity, entityID sql.NullString
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.TenantID, &n.UserID, &n.Type, &n.Title, &n.Message, &entity, &entityID, &n.IsRead, &n.CreatedAt, &readAt); err != nil {
+			return nil, err
+		}
+		if entity.Valid {
+			n.Entity = &entity.String
+		}
+		if entityID.Valid {
+			n.EntityID = &entityID.String
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *UserNotificationRepo) CountUnread(ctx context.Context, userID, tenantID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM user_notifications WHERE user_id = $1 AND tenant_id = $2 AND is_read = false
+	`, userID, tenantID).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks one notification (or all of the user's when id is empty) as read; returns the number of rows changed
+func (r *UserNotificationRepo) MarkRead(ctx context.Context, id, userID, tenantID string) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_notifications SET is_read = true, read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+		WHERE user_id = $1 AND tenant_id = $2 AND (($3 = '' AND is_read = false) OR id::text = $3)
+	`, userID, tenantID, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
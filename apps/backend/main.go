@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"path/filepath"
@@ -68,6 +69,7 @@ func main() {
 	emailChangeRepo := repo.NewEmailChangeRepo(db)
 	templateRepo := repo.NewTemplateRepo(db)
 	ragRepo := repo.NewRAGRepo(db)
+	userNotificationRepo := repo.NewUserNotificationRepo(db)
 
 	// Initialize services
 	authService := domain.NewAuthService(userRepo, baseRoleRepo, permissionRepo, cfg.JWTSecret)
@@ -79,7 +81,7 @@ func main() {
 	documentStorageService := domain.NewDocumentStorageService(documentService)
 	templateService := domain.NewTemplateService(templateRepo, assetRepo, documentService)
 	assetService := domain.NewAssetService(assetRepo, userRepo, documentStorageService)
-	riskService := domain.NewRiskService(riskRepo, auditRepo, documentStorageService)
+	riskService := domain.NewRiskService(riskRepo, auditRepo, userRepo, documentStorageService)
 	incidentService := domain.NewIncidentService(incidentRepo, userRepo, assetRepo, riskRepo, documentStorageService)
 	trainingService := domain.NewTrainingService(trainingRepo, documentStorageService)
 	aiService := domain.NewAIService(aiRepo)
@@ -87,10 +89,55 @@ func main() {
 	complianceService := domain.NewComplianceService(complianceRepo)
 	emailChangeService := domain.NewEmailChangeService(emailChangeRepo, userRepo)
 	ragService := domain.NewRAGService(ragRepo, documentRepo)
+	userNotificationService := domain.NewUserNotificationService(userNotificationRepo)
+
+	// Шаблоны документов для черновиков уведомлений об инцидентах
+	incidentService.SetTemplateService(templateService)
+
+	// Риски и меры защиты, создаваемые по итогам разбора инцидента
+	incidentService.SetRiskService(riskService)
+
+	// Напоминания владельцам о пересмотре рисков
+	riskService.SetNotifier(userNotificationService)
+
+	// Ключ шифрования паролей почтовых ящиков для приема инцидентов
+	if err := incidentService.SetMailboxSecretKey(cfg.SecretsKey); err != nil {
+		log.Fatalf("Failed to configure mailbox secret key: %v", err)
+	}
+
+	// Уведомления о сроках SLA инцидентов и уведомления регуляторов
+	incidentService.SetNotifier(userNotificationService)
+
+	// Журнал аудита для объединения инцидентов
+	incidentService.SetAuditRepo(auditRepo)
+
+	// Правила инвентарных номеров для импортируемых активов
+	assetService.SetInventoryNumberGenerator(templateService)
+
+	// Риски по критическим уязвимостям установленного ПО
+	assetService.SetRiskService(riskService)
+
+	// Акты передачи, ремонта и списания активов по шаблонам документов
+	assetService.SetActGenerator(templateService)
 
 	// Связываем AIService с RAGService для QueryWithRAG
 	aiService.SetRAGService(ragService)
 
+	// Фоновые задачи модуля рисков (истечение принятия, напоминания о пересмотре)
+	go riskService.RunScheduler(context.Background(), time.Hour)
+
+	// Плановые инвентаризации активов: запуск кампаний в назначенный день
+	go assetService.RunInventoryScheduler(context.Background(), time.Hour)
+
+	// Мониторинг SLA инцидентов (предупреждения и нарушения сроков)
+	go incidentService.RunSLAMonitor(context.Background(), 5*time.Minute)
+
+	// Оповещения о приближении и истечении сроков уведомления регуляторов
+	go incidentService.RunRegulatoryDeadlineMonitor(context.Background(), 15*time.Minute)
+
+	// Прием сообщений об инцидентах из почтовых ящиков POP3
+	go incidentService.RunMailPoller(context.Background(), time.Minute)
+
 	// Initialize handlers
 	authHandler := http.NewAuthHandler(authService, userService)
 	userHandler := http.NewUserHandler(userService, roleService)
@@ -115,11 +162,16 @@ func main() {
 	emailChangeHandler := http.NewEmailChangeHandler(emailChangeService, validator.New())
 	templateHandler := http.NewTemplateHandler(templateService)
 	ragHandler := http.NewRAGHandler(ragService)
+	userNotificationHandler := http.NewUserNotificationHandler(userNotificationService)
 
 	// Связываем DocumentHandler с RAGService для автоиндексации
 	documentHandler.SetRAGService(ragService)
 
 	app := fiber.New(fiber.Config{
+		// Тела больше лимита по умолчанию не буферизуются целиком: лимиты по маршрутам
+		// проверяет BodyLimitMiddleware, фиды NVD читаются из потока во временные файлы
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			log.Printf("ERROR: Global error handler caught error: %v", err)
 			log.Printf("ERROR: Request path: %s %s", c.Method(), c.Path())
@@ -134,6 +186,9 @@ func main() {
 	})
 
 	// Middleware
+	app.Use(http.BodyLimitMiddleware(http.DefaultBodyLimit, map[string]int{
+		"/api/assets/vulnerabilities/feeds": http.MaxVulnerabilityFeedUploadSize,
+	}))
 	app.Use(logger.New())
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000,http://localhost:5173",
@@ -182,6 +237,12 @@ func main() {
 	// Auth routes
 	authHandler.Register(api)
 
+	// Incident alert ingestion (SIEM/monitoring webhook, authenticated by source token)
+	incidentHandler.RegisterPublic(api)
+
+	// Asset discovery reports (inventory agents, authenticated by agent token)
+	assetHandler.RegisterPublic(api)
+
 	// Test endpoint (this should work without auth)
 	api.Get("/test", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"status": "OK", "message": "Backend is working"})
@@ -208,6 +269,7 @@ func main() {
 	emailChangeHandler.Register(protected)
 	templateHandler.Register(protected)
 	ragHandler.Register(protected)
+	userNotificationHandler.Register(protected)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,53 @@
+-- Migration 034: Risk treatment plans
+-- Планы обработки рисков с упорядоченными задачами, исполнителями, сроками и бюджетом
+
+-- Прогресс обработки, агрегированный по активным планам риска
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS treatment_progress INTEGER CHECK (treatment_progress >= 0 AND treatment_progress <= 100);
+
+-- Планы обработки риска
+CREATE TABLE IF NOT EXISTS risk_treatment_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    risk_id UUID NOT NULL REFERENCES risks(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    strategy VARCHAR(20) NOT NULL CHECK (strategy IN ('accept', 'mitigate', 'transfer', 'avoid')),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'active', 'completed', 'cancelled')),
+    owner_user_id UUID REFERENCES users(id),
+    budget NUMERIC(14, 2) CHECK (budget >= 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    start_date DATE,
+    due_date DATE,
+    progress INTEGER NOT NULL DEFAULT 0 CHECK (progress >= 0 AND progress <= 100),
+    completed_at TIMESTAMP,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Задачи плана обработки
+CREATE TABLE IF NOT EXISTS risk_treatment_tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    plan_id UUID NOT NULL REFERENCES risk_treatment_plans(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    assignee_user_id UUID REFERENCES users(id),
+    due_date DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'in_progress', 'done', 'cancelled')),
+    cost NUMERIC(14, 2) CHECK (cost >= 0),
+    evidence TEXT, -- описание подтверждения выполнения
+    evidence_document_id UUID REFERENCES documents(id) ON DELETE SET NULL,
+    completed_by UUID REFERENCES users(id),
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_treatment_plans_tenant_id ON risk_treatment_plans(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_risk_treatment_plans_risk_id ON risk_treatment_plans(risk_id);
+CREATE INDEX IF NOT EXISTS idx_risk_treatment_plans_status ON risk_treatment_plans(status);
+
+CREATE INDEX IF NOT EXISTS idx_risk_treatment_tasks_plan_id ON risk_treatment_tasks(plan_id, position);
+CREATE INDEX IF NOT EXISTS idx_risk_treatment_tasks_assignee ON risk_treatment_tasks(assignee_user_id);
+CREATE INDEX IF NOT EXISTS idx_risk_treatment_tasks_due_date ON risk_treatment_tasks(due_date);
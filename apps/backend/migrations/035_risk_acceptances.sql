@@ -0,0 +1,48 @@
+-- Migration 035: Formal risk acceptance
+-- Решения о принятии риска с утверждающим, обоснованием и сроком действия
+
+-- Правила утверждения по уровню риска (на уровне организации)
+CREATE TABLE IF NOT EXISTS risk_acceptance_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    level_label VARCHAR(20) NOT NULL CHECK (level_label IN ('Low', 'Medium', 'High', 'Critical')),
+    approver_role VARCHAR(100), -- название роли утверждающего; NULL - любой пользователь с правом risks.accept
+    max_validity_days INTEGER NOT NULL DEFAULT 365 CHECK (max_validity_days > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tenant_id, level_label)
+);
+
+-- Решения о принятии риска
+CREATE TABLE IF NOT EXISTS risk_acceptances (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    risk_id UUID NOT NULL REFERENCES risks(id) ON DELETE CASCADE,
+    justification TEXT NOT NULL,
+    risk_level INTEGER, -- уровень риска на момент запроса
+    level_label VARCHAR(20) NOT NULL,
+    approver_role VARCHAR(100), -- требуемая роль утверждающего на момент запроса
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'expired', 'revoked')),
+    requested_by UUID NOT NULL REFERENCES users(id),
+    approver_user_id UUID REFERENCES users(id),
+    decision_comment TEXT,
+    decided_at TIMESTAMP,
+    expires_at DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_acceptances_tenant_id ON risk_acceptances(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_risk_acceptances_risk_id ON risk_acceptances(risk_id);
+CREATE INDEX IF NOT EXISTS idx_risk_acceptances_status_expires ON risk_acceptances(status, expires_at);
+
+-- Право на утверждение принятия риска
+INSERT INTO permissions (code, module, description) VALUES
+('risks.accept', 'Риски', 'Утверждение принятия рисков')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin' AND p.code = 'risks.accept'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
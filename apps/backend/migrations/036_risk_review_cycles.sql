@@ -0,0 +1,23 @@
+-- Migration 036: Periodic risk review cycles
+-- Периодичность пересмотра рисков по категории или уровню, дата следующего пересмотра
+
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS next_review_date DATE;
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS last_reviewed_at TIMESTAMP;
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS last_reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS review_reminded_on DATE; -- дата последнего напоминания о пересмотре
+
+CREATE INDEX IF NOT EXISTS idx_risks_next_review_date ON risks(tenant_id, next_review_date);
+
+-- Интервалы пересмотра: правило для категории имеет приоритет над правилом для уровня
+CREATE TABLE IF NOT EXISTS risk_review_intervals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('category', 'level')),
+    scope_value VARCHAR(100) NOT NULL, -- название категории или Low/Medium/High/Critical
+    interval_days INTEGER NOT NULL CHECK (interval_days > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tenant_id, scope, scope_value)
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_review_intervals_tenant_id ON risk_review_intervals(tenant_id);
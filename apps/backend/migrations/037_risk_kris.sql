@@ -0,0 +1,48 @@
+-- Migration 037: Key Risk Indicators
+-- Ключевые индикаторы риска (KRI) с порогами и историей измерений
+
+CREATE TABLE IF NOT EXISTS risk_kris (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL, -- идентификатор для передачи измерений через API
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    unit VARCHAR(50),
+    direction VARCHAR(20) NOT NULL DEFAULT 'higher_is_worse' CHECK (direction IN ('higher_is_worse', 'lower_is_worse')),
+    amber_threshold NUMERIC(18,4) NOT NULL,
+    red_threshold NUMERIC(18,4) NOT NULL,
+    frequency VARCHAR(20) NOT NULL DEFAULT 'monthly' CHECK (frequency IN ('daily', 'weekly', 'monthly', 'quarterly')),
+    owner_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    current_value NUMERIC(18,4),
+    current_status VARCHAR(20) NOT NULL DEFAULT 'unknown' CHECK (current_status IN ('unknown', 'green', 'amber', 'red')),
+    last_measured_at TIMESTAMP,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tenant_id, code)
+);
+
+-- Связь KRI с рисками (один индикатор может относиться к нескольким рискам)
+CREATE TABLE IF NOT EXISTS risk_kri_links (
+    kri_id UUID NOT NULL REFERENCES risk_kris(id) ON DELETE CASCADE,
+    risk_id UUID NOT NULL REFERENCES risks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (kri_id, risk_id)
+);
+
+CREATE TABLE IF NOT EXISTS risk_kri_measurements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kri_id UUID NOT NULL REFERENCES risk_kris(id) ON DELETE CASCADE,
+    value NUMERIC(18,4) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('green', 'amber', 'red')),
+    measured_at TIMESTAMP NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (source IN ('manual', 'api')),
+    comment TEXT,
+    recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_kris_tenant_id ON risk_kris(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_risk_kri_links_risk_id ON risk_kri_links(risk_id);
+CREATE INDEX IF NOT EXISTS idx_risk_kri_measurements_kri_measured ON risk_kri_measurements(kri_id, measured_at);
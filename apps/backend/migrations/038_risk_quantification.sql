@@ -0,0 +1,51 @@
+-- Migration 038: Quantitative risk analysis (FAIR)
+-- Количественная оценка рисков: частота событий потерь и величина потерь, результаты моделирования Монте-Карло
+
+-- Входные параметры: оценки min / наиболее вероятное / max (PERT)
+CREATE TABLE IF NOT EXISTS risk_quantifications (
+    risk_id UUID PRIMARY KEY REFERENCES risks(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    lef_min NUMERIC(12,4) NOT NULL CHECK (lef_min >= 0), -- частота событий потерь, раз в год
+    lef_most_likely NUMERIC(12,4) NOT NULL,
+    lef_max NUMERIC(12,4) NOT NULL,
+    lm_min NUMERIC(18,2) NOT NULL CHECK (lm_min >= 0), -- величина потерь одного события
+    lm_most_likely NUMERIC(18,2) NOT NULL,
+    lm_max NUMERIC(18,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (lef_min <= lef_most_likely AND lef_most_likely <= lef_max),
+    CHECK (lm_min <= lm_most_likely AND lm_most_likely <= lm_max)
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_quantifications_tenant_id ON risk_quantifications(tenant_id);
+
+-- Результаты моделирования: годовые ожидаемые потери (ALE) и кривая превышения потерь
+CREATE TABLE IF NOT EXISTS risk_simulations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    risk_id UUID NOT NULL REFERENCES risks(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    iterations INTEGER NOT NULL CHECK (iterations > 0),
+    seed BIGINT NOT NULL,
+    inputs JSONB NOT NULL, -- снимок входных параметров на момент запуска
+    currency VARCHAR(3) NOT NULL,
+    mean_loss NUMERIC(18,2) NOT NULL,
+    p10 NUMERIC(18,2) NOT NULL,
+    p50 NUMERIC(18,2) NOT NULL,
+    p90 NUMERIC(18,2) NOT NULL,
+    p95 NUMERIC(18,2) NOT NULL,
+    p99 NUMERIC(18,2) NOT NULL,
+    max_loss NUMERIC(18,2) NOT NULL,
+    loss_probability NUMERIC(6,4) NOT NULL, -- доля лет хотя бы с одним событием потерь
+    exceedance_curve JSONB NOT NULL, -- [{"loss": ..., "probability": ...}]
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_simulations_risk_id ON risk_simulations(risk_id, created_at DESC);
+
+-- Последний результат хранится рядом с качественной оценкой риска
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS ale_mean NUMERIC(18,2);
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS ale_p90 NUMERIC(18,2);
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS quantified_at TIMESTAMP;
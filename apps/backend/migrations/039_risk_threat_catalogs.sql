@@ -0,0 +1,60 @@
+-- Migration 039: Threat and vulnerability catalogs
+-- Каталоги угроз и уязвимостей (ISO 27005): риск = угроза x уязвимость x актив
+
+-- Записи с tenant_id IS NULL - встроенный каталог, доступный всем организациям
+CREATE TABLE IF NOT EXISTS risk_catalog_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('threat', 'vulnerability')),
+    code VARCHAR(50) NOT NULL, -- например, УБИ.001 для записей из БДУ ФСТЭК
+    name VARCHAR(500) NOT NULL,
+    description TEXT,
+    source VARCHAR(20) NOT NULL DEFAULT 'custom' CHECK (source IN ('builtin', 'bdu_fstec', 'custom')),
+    asset_types TEXT[] NOT NULL DEFAULT '{}', -- типы активов (dto.AssetType*), пустой список - применимо ко всем
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_catalog_entries_code
+    ON risk_catalog_entries(kind, COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), code);
+CREATE INDEX IF NOT EXISTS idx_risk_catalog_entries_tenant_kind ON risk_catalog_entries(tenant_id, kind);
+CREATE INDEX IF NOT EXISTS idx_risk_catalog_entries_asset_types ON risk_catalog_entries USING GIN(asset_types);
+
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS threat_id UUID REFERENCES risk_catalog_entries(id) ON DELETE SET NULL;
+ALTER TABLE risks ADD COLUMN IF NOT EXISTS vulnerability_id UUID REFERENCES risk_catalog_entries(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_risks_threat_id ON risks(threat_id);
+CREATE INDEX IF NOT EXISTS idx_risks_vulnerability_id ON risks(vulnerability_id);
+
+-- Встроенный каталог угроз
+INSERT INTO risk_catalog_entries (kind, code, name, description, source, asset_types) VALUES
+('threat', 'T.001', 'Несанкционированный доступ к информации', 'Получение доступа к информации лицом, не имеющим на это прав', 'builtin', '{server,workstation,application,database,network_device}'),
+('threat', 'T.002', 'Внедрение вредоносного программного обеспечения', 'Заражение вирусами, шифровальщиками, троянскими программами', 'builtin', '{server,workstation,application}'),
+('threat', 'T.003', 'Отказ в обслуживании', 'Нарушение доступности сервиса за счет перегрузки или сбоя', 'builtin', '{server,application,network_device}'),
+('threat', 'T.004', 'Хищение или утрата носителей информации', 'Утрата переносных устройств, бумажных документов и съемных носителей', 'builtin', '{workstation,document}'),
+('threat', 'T.005', 'Перехват сетевого трафика', 'Прослушивание и подмена данных, передаваемых по сети', 'builtin', '{network_device,server}'),
+('threat', 'T.006', 'Эксплуатация уязвимостей программного обеспечения', 'Использование известных уязвимостей ПО для выполнения произвольного кода', 'builtin', '{server,workstation,application,database}'),
+('threat', 'T.007', 'Несанкционированное изменение конфигурации', 'Изменение настроек систем и сетевого оборудования без согласования', 'builtin', '{server,network_device,application}'),
+('threat', 'T.008', 'Физическое повреждение или хищение оборудования', 'Повреждение, кража или уничтожение технических средств', 'builtin', '{server,workstation,network_device}'),
+('threat', 'T.009', 'Ошибки персонала', 'Непреднамеренные действия пользователей и администраторов', 'builtin', '{}'),
+('threat', 'T.010', 'Социальная инженерия и фишинг', 'Получение учетных данных или информации путем обмана сотрудников', 'builtin', '{workstation,document}'),
+('threat', 'T.011', 'Внедрение кода в запросы к базе данных', 'SQL-инъекции и аналогичные атаки на уровне приложения', 'builtin', '{database,application}'),
+('threat', 'T.012', 'Уничтожение или потеря данных', 'Безвозвратная утрата данных вследствие сбоя или умышленных действий', 'builtin', '{database,document,server}')
+ON CONFLICT DO NOTHING;
+
+-- Встроенный каталог уязвимостей
+INSERT INTO risk_catalog_entries (kind, code, name, description, source, asset_types) VALUES
+('vulnerability', 'V.001', 'Слабая парольная политика', 'Простые пароли, отсутствие требований к смене и сложности', 'builtin', '{server,workstation,application,database,network_device}'),
+('vulnerability', 'V.002', 'Отсутствие обновлений безопасности', 'Несвоевременная установка исправлений ОС и ПО', 'builtin', '{server,workstation,application,database,network_device}'),
+('vulnerability', 'V.003', 'Избыточные права доступа', 'Нарушение принципа минимальных привилегий', 'builtin', '{server,application,database}'),
+('vulnerability', 'V.004', 'Отсутствие шифрования при передаче данных', 'Передача данных по открытым каналам без защиты', 'builtin', '{network_device,application}'),
+('vulnerability', 'V.005', 'Отсутствие резервного копирования', 'Нет регулярных проверенных резервных копий', 'builtin', '{server,database,document}'),
+('vulnerability', 'V.006', 'Отсутствие антивирусной защиты', 'Средства антивирусной защиты не установлены или не обновляются', 'builtin', '{workstation,server}'),
+('vulnerability', 'V.007', 'Небезопасная конфигурация по умолчанию', 'Используются стандартные учетные записи и настройки производителя', 'builtin', '{network_device,server,application}'),
+('vulnerability', 'V.008', 'Отсутствие журналирования событий безопасности', 'События не регистрируются или журналы не анализируются', 'builtin', '{server,application,database,network_device}'),
+('vulnerability', 'V.009', 'Недостаточная осведомленность персонала', 'Сотрудники не обучены правилам информационной безопасности', 'builtin', '{workstation,document,other}'),
+('vulnerability', 'V.010', 'Отсутствие контроля физического доступа', 'Помещения и оборудование доступны посторонним лицам', 'builtin', '{server,network_device,document}'),
+('vulnerability', 'V.011', 'Отсутствие проверки входных данных', 'Приложение не проверяет данные, получаемые от пользователя', 'builtin', '{application,database}')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,20 @@
+-- Migration 040: Risk appetite
+-- Заявления о риск-аппетите организации по категориям рисков с уровнями толерантности
+
+-- Уровни сравниваются с risks.level (likelihood * impact):
+-- выше appetite_level - превышение аппетита, выше tolerance_level - риск вне толерантности
+CREATE TABLE IF NOT EXISTS risk_appetite_statements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    category VARCHAR(100), -- NULL - заявление по умолчанию для категорий без собственного
+    statement TEXT NOT NULL,
+    appetite_level INTEGER NOT NULL CHECK (appetite_level BETWEEN 1 AND 16),
+    tolerance_level INTEGER NOT NULL CHECK (tolerance_level BETWEEN 1 AND 16),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (appetite_level <= tolerance_level)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_appetite_statements_category
+    ON risk_appetite_statements(tenant_id, LOWER(COALESCE(category, '')));
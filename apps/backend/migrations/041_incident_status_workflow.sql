@@ -0,0 +1,53 @@
+-- Migration 041: Incident status workflow
+-- Настраиваемая модель состояний инцидента: разрешённые переходы, обязательные поля и история статусов
+
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS root_cause TEXT;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS resolution_summary TEXT;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS reopen_count INTEGER NOT NULL DEFAULT 0;
+
+-- Переходы с tenant_id IS NULL - модель по умолчанию; если у организации есть свои переходы, используются только они
+CREATE TABLE IF NOT EXISTS incident_status_transitions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL CHECK (from_status IN ('new', 'assigned', 'in_progress', 'resolved', 'closed')),
+    to_status VARCHAR(20) NOT NULL CHECK (to_status IN ('new', 'assigned', 'in_progress', 'resolved', 'closed')),
+    required_fields TEXT[] NOT NULL DEFAULT '{}', -- assigned_to, root_cause, resolution_summary, comment
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_status <> to_status)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_status_transitions_unique
+    ON incident_status_transitions(COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), from_status, to_status);
+
+-- Полная история статусов инцидента
+CREATE TABLE IF NOT EXISTS incident_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    from_status VARCHAR(20), -- NULL для записи о создании инцидента
+    to_status VARCHAR(20) NOT NULL,
+    is_reopen BOOLEAN NOT NULL DEFAULT FALSE,
+    comment TEXT,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_status_history_incident ON incident_status_history(incident_id, changed_at);
+
+-- Модель по умолчанию
+INSERT INTO incident_status_transitions (from_status, to_status, required_fields) VALUES
+('new', 'assigned', '{assigned_to}'),
+('new', 'in_progress', '{assigned_to}'),
+('new', 'closed', '{comment}'),
+('assigned', 'in_progress', '{}'),
+('assigned', 'new', '{comment}'),
+('in_progress', 'resolved', '{root_cause,resolution_summary}'),
+('resolved', 'closed', '{}'),
+('resolved', 'in_progress', '{comment}'),
+('closed', 'in_progress', '{comment}')
+ON CONFLICT DO NOTHING;
+
+-- Существующие инциденты получают начальную запись истории
+INSERT INTO incident_status_history (incident_id, from_status, to_status, changed_by, changed_at)
+SELECT i.id, NULL, i.status, i.reported_by, i.created_at
+FROM incidents i
+WHERE NOT EXISTS (SELECT 1 FROM incident_status_history h WHERE h.incident_id = i.id);
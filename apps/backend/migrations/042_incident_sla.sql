@@ -0,0 +1,72 @@
+-- Migration 042: Incident SLA
+-- SLA по критичности инцидента, календари рабочего времени, таймеры и события предупреждения/нарушения
+
+-- Календари рабочего времени организации
+CREATE TABLE IF NOT EXISTS incident_business_calendars (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Europe/Moscow',
+    work_days INTEGER[] NOT NULL DEFAULT '{1,2,3,4,5}', -- дни недели ISO: 1 - понедельник, 7 - воскресенье
+    work_start VARCHAR(5) NOT NULL DEFAULT '09:00' CHECK (work_start ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    work_end VARCHAR(5) NOT NULL DEFAULT '18:00' CHECK (work_end ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    holidays DATE[] NOT NULL DEFAULT '{}',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (work_start < work_end)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_business_calendars_tenant ON incident_business_calendars(tenant_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_business_calendars_default
+    ON incident_business_calendars(tenant_id) WHERE is_default;
+
+-- Политики SLA по критичности; для критичностей без политики используются значения по умолчанию
+CREATE TABLE IF NOT EXISTS incident_sla_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    criticality VARCHAR(20) NOT NULL CHECK (criticality IN ('low', 'medium', 'high', 'critical')),
+    response_minutes INTEGER NOT NULL CHECK (response_minutes > 0),
+    resolution_minutes INTEGER NOT NULL CHECK (resolution_minutes > 0),
+    warning_percent INTEGER NOT NULL DEFAULT 80 CHECK (warning_percent BETWEEN 1 AND 99),
+    business_hours_only BOOLEAN NOT NULL DEFAULT FALSE,
+    calendar_id UUID REFERENCES incident_business_calendars(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, criticality)
+);
+
+-- Сроки SLA инцидента фиксируются при регистрации
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS responded_at TIMESTAMP NULL;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS response_due_at TIMESTAMP NULL;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS resolution_due_at TIMESTAMP NULL;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS response_breached BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS resolution_breached BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_incidents_response_due_at ON incidents(response_due_at) WHERE responded_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_resolution_due_at ON incidents(resolution_due_at) WHERE resolved_at IS NULL;
+
+-- События SLA: предупреждение о приближении срока и нарушение; служат лентой уведомлений
+CREATE TABLE IF NOT EXISTS incident_sla_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    target VARCHAR(20) NOT NULL CHECK (target IN ('response', 'resolution')),
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('warning', 'breach')),
+    due_at TIMESTAMP NOT NULL,
+    recipient_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (incident_id, target, event_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_sla_events_tenant ON incident_sla_events(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_incident_sla_events_recipient ON incident_sla_events(recipient_user_id);
+
+-- Время реакции фиксируется первым уходом из статуса "new"
+UPDATE incidents SET responded_at = h.changed_at
+FROM (
+    SELECT incident_id, MIN(changed_at) AS changed_at
+    FROM incident_status_history
+    WHERE from_status = 'new'
+    GROUP BY incident_id
+) h
+WHERE h.incident_id = incidents.id AND incidents.responded_at IS NULL;
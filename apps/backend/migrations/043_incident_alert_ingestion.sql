@@ -0,0 +1,48 @@
+-- Migration 043: Incident alert ingestion
+-- Приём оповещений SIEM/мониторинга через вебхук (JSON, CEF, syslog), правила сопоставления полей и дедупликация
+
+-- Источники оповещений; аутентификация по токену, в БД хранится только его SHA-256
+CREATE TABLE IF NOT EXISTS incident_alert_sources (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    format VARCHAR(20) NOT NULL CHECK (format IN ('json', 'cef', 'syslog')),
+    incident_source VARCHAR(50) NOT NULL DEFAULT 'siem' CHECK (incident_source IN ('automatic_agent', 'monitoring', 'siem')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    token_prefix VARCHAR(16) NOT NULL,
+    field_mapping JSONB NOT NULL DEFAULT '{}',   -- поле инцидента -> путь(и) в оповещении через запятую
+    severity_mapping JSONB NOT NULL DEFAULT '{}', -- значение важности в оповещении -> критичность инцидента
+    correlation_fields TEXT[] NOT NULL DEFAULT '{title,hostname,ip}',
+    dedup_window_minutes INTEGER NOT NULL DEFAULT 1440 CHECK (dedup_window_minutes > 0),
+    default_category VARCHAR(50) NOT NULL DEFAULT 'other'
+        CHECK (default_category IN ('technical_failure', 'data_breach', 'unauthorized_access', 'physical', 'malware', 'social_engineering', 'other')),
+    default_criticality VARCHAR(20) NOT NULL DEFAULT 'medium' CHECK (default_criticality IN ('low', 'medium', 'high', 'critical')),
+    assigned_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    reporter_id UUID NOT NULL REFERENCES users(id), -- от имени этого пользователя регистрируются инциденты
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_received_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_alert_sources_tenant ON incident_alert_sources(tenant_id);
+
+-- Принятые оповещения; повторные оповещения с тем же ключом корреляции привязываются к открытому инциденту
+CREATE TABLE IF NOT EXISTS incident_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    source_id UUID NOT NULL REFERENCES incident_alert_sources(id) ON DELETE CASCADE,
+    incident_id UUID REFERENCES incidents(id) ON DELETE SET NULL,
+    correlation_key VARCHAR(64) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    severity VARCHAR(50) NULL,
+    criticality VARCHAR(20) NOT NULL,
+    fields JSONB NOT NULL DEFAULT '{}',
+    raw_payload TEXT NOT NULL,
+    is_duplicate BOOLEAN NOT NULL DEFAULT FALSE,
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_correlation ON incident_alerts(tenant_id, correlation_key, received_at DESC);
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_incident ON incident_alerts(incident_id);
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_source ON incident_alerts(source_id, received_at DESC);
@@ -0,0 +1,73 @@
+-- Migration 044: Incident playbooks
+-- Шаблоны реагирования по категориям инцидентов: упорядоченные шаги, роли исполнителей и относительные сроки
+
+-- Плейбуки с tenant_id IS NULL - встроенные; собственный плейбук организации для категории заменяет встроенный
+CREATE TABLE IF NOT EXISTS incident_playbooks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+    category VARCHAR(50) NOT NULL CHECK (category IN ('technical_failure', 'data_breach', 'unauthorized_access', 'physical', 'malware', 'social_engineering', 'other')),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_playbooks_category
+    ON incident_playbooks(COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'::uuid), category);
+
+CREATE TABLE IF NOT EXISTS incident_playbook_steps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    playbook_id UUID NOT NULL REFERENCES incident_playbooks(id) ON DELETE CASCADE,
+    step_order INTEGER NOT NULL CHECK (step_order > 0),
+    action_type VARCHAR(50) NOT NULL CHECK (action_type IN ('investigation', 'containment', 'eradication', 'recovery', 'prevention')),
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    assignee_role VARCHAR(100), -- имя роли организации; NULL - ответственный за инцидент
+    due_offset_hours INTEGER CHECK (due_offset_hours > 0), -- срок от момента запуска плейбука; NULL - без срока
+    UNIQUE (playbook_id, step_order)
+);
+
+-- Действия, созданные плейбуком, помнят свой шаг; повторный запуск не создает дубликатов
+ALTER TABLE incident_actions ADD COLUMN IF NOT EXISTS playbook_id UUID REFERENCES incident_playbooks(id) ON DELETE SET NULL;
+ALTER TABLE incident_actions ADD COLUMN IF NOT EXISTS step_order INTEGER;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_actions_playbook_step
+    ON incident_actions(incident_id, playbook_id, step_order);
+
+-- Встроенные плейбуки
+INSERT INTO incident_playbooks (category, name, description) VALUES
+('malware', 'Заражение вредоносным ПО', 'Изоляция заражённых узлов, удаление вредоносного ПО и восстановление систем'),
+('data_breach', 'Утечка данных', 'Локализация утечки, оценка объёма затронутых данных и уведомление заинтересованных сторон'),
+('unauthorized_access', 'Несанкционированный доступ', 'Блокировка скомпрометированных учётных записей и анализ действий нарушителя'),
+('social_engineering', 'Социальная инженерия', 'Реагирование на фишинг и иные попытки манипуляции сотрудниками'),
+('technical_failure', 'Технический сбой', 'Восстановление работоспособности и устранение причин сбоя')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO incident_playbook_steps (playbook_id, step_order, action_type, title, description, due_offset_hours)
+SELECT p.id, s.step_order, s.action_type, s.title, s.description, s.due_offset_hours
+FROM (VALUES
+    ('malware', 1, 'containment', 'Изолировать заражённые узлы', 'Отключить узлы от сети, сохранив их состояние для анализа', 1),
+    ('malware', 2, 'investigation', 'Определить вредоносное ПО и вектор заражения', 'Собрать образцы, индикаторы компрометации и журналы', 8),
+    ('malware', 3, 'eradication', 'Удалить вредоносное ПО', 'Очистить или переустановить заражённые системы, заблокировать индикаторы', 24),
+    ('malware', 4, 'recovery', 'Восстановить системы из резервных копий', 'Проверить целостность данных перед возвратом в эксплуатацию', 48),
+    ('malware', 5, 'prevention', 'Обновить средства защиты', 'Актуализировать сигнатуры, правила СЗИ и установить обновления', 120),
+    ('data_breach', 1, 'containment', 'Остановить утечку', 'Закрыть канал утечки и отозвать скомпрометированные доступы', 2),
+    ('data_breach', 2, 'investigation', 'Оценить объём и категории затронутых данных', 'Установить состав данных, субъектов и период утечки', 12),
+    ('data_breach', 3, 'investigation', 'Подготовить уведомления регулятору и субъектам', 'Проверить обязательные сроки уведомления', 24),
+    ('data_breach', 4, 'eradication', 'Устранить уязвимость, через которую произошла утечка', NULL, 72),
+    ('data_breach', 5, 'prevention', 'Пересмотреть меры защиты данных', 'Обновить политики доступа и DLP-правила', 240),
+    ('unauthorized_access', 1, 'containment', 'Заблокировать скомпрометированные учётные записи', 'Сбросить пароли, завершить активные сессии, отозвать токены', 1),
+    ('unauthorized_access', 2, 'investigation', 'Проанализировать действия нарушителя', 'Изучить журналы аутентификации и доступа к ресурсам', 12),
+    ('unauthorized_access', 3, 'eradication', 'Удалить закрепление нарушителя', 'Проверить созданные учётные записи, ключи и задачи планировщика', 48),
+    ('unauthorized_access', 4, 'prevention', 'Усилить контроль доступа', 'Включить многофакторную аутентификацию, пересмотреть права', 168),
+    ('social_engineering', 1, 'containment', 'Заблокировать вредоносные письма и ссылки', 'Удалить письма из почтовых ящиков, заблокировать домены отправителя', 2),
+    ('social_engineering', 2, 'investigation', 'Выявить пострадавших сотрудников', 'Определить, кто перешёл по ссылкам или передал данные', 8),
+    ('social_engineering', 3, 'recovery', 'Сбросить учётные данные пострадавших', NULL, 24),
+    ('social_engineering', 4, 'prevention', 'Провести внеплановое обучение сотрудников', NULL, 336),
+    ('technical_failure', 1, 'investigation', 'Диагностировать сбой', 'Определить затронутые сервисы и причину отказа', 2),
+    ('technical_failure', 2, 'recovery', 'Восстановить работоспособность', 'Переключиться на резерв или восстановить сервис', 8),
+    ('technical_failure', 3, 'prevention', 'Устранить причину сбоя', 'Запланировать изменения для исключения повторения', 168)
+) AS s(category, step_order, action_type, title, description, due_offset_hours)
+JOIN incident_playbooks p ON p.tenant_id IS NULL AND p.category = s.category
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,70 @@
+-- Migration 045: Incident regulatory notifications
+-- Регуляторные профили организации (GDPR, 152-ФЗ, ГосСОПКА), обязательства по уведомлению и их исполнение по инцидентам
+
+CREATE TABLE IF NOT EXISTS incident_regulatory_profiles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    framework VARCHAR(20) NOT NULL CHECK (framework IN ('gdpr', '152fz', 'gossopka', 'custom')),
+    categories TEXT[] NOT NULL DEFAULT '{data_breach}', -- категории инцидентов, на которые распространяется профиль
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS incident_regulatory_obligations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    profile_id UUID NOT NULL REFERENCES incident_regulatory_profiles(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    title VARCHAR(255) NOT NULL,
+    recipient_type VARCHAR(20) NOT NULL CHECK (recipient_type IN ('regulator', 'data_subjects', 'cert', 'other')),
+    recipient VARCHAR(255) NOT NULL,
+    deadline_hours INTEGER NOT NULL CHECK (deadline_hours > 0), -- отсчитывается от момента обнаружения инцидента
+    template_id UUID REFERENCES document_templates(id) ON DELETE SET NULL,
+    description TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_regulatory_obligations_profile ON incident_regulatory_obligations(profile_id, position);
+
+-- Уведомления по конкретному инциденту: сроки, черновик и факт отправки
+CREATE TABLE IF NOT EXISTS incident_notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    obligation_id UUID REFERENCES incident_regulatory_obligations(id) ON DELETE SET NULL,
+    profile_name VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    recipient_type VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'draft', 'sent', 'not_required')),
+    draft_content TEXT,
+    sent_at TIMESTAMP,
+    sent_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    sent_to VARCHAR(500), -- фактический адресат: e-mail, портал, номер обращения
+    channel VARCHAR(100),
+    reference_number VARCHAR(100),
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (incident_id, obligation_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_notifications_due ON incident_notifications(tenant_id, due_at)
+    WHERE status IN ('pending', 'draft');
+
+-- Шаблоны уведомлений об инцидентах
+ALTER TABLE document_templates DROP CONSTRAINT IF EXISTS chk_template_type;
+ALTER TABLE document_templates ADD CONSTRAINT chk_template_type CHECK (
+    template_type IN (
+        'passport_pc',
+        'passport_monitor',
+        'passport_device',
+        'transfer_act',
+        'writeoff_act',
+        'repair_log',
+        'breach_notification',
+        'other'
+    )
+);
@@ -0,0 +1,51 @@
+-- Migration 046: Post-incident reviews
+-- Разбор инцидента (lessons learned): причины, способствующие факторы и последующие меры, порождающие риски и меры защиты
+
+CREATE TABLE IF NOT EXISTS incident_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    incident_id UUID NOT NULL UNIQUE REFERENCES incidents(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'completed')),
+    summary TEXT,
+    root_cause TEXT,
+    impact TEXT,
+    what_went_well TEXT,
+    what_went_wrong TEXT,
+    lessons_learned TEXT,
+    review_date DATE,
+    facilitator_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    participants TEXT[] NOT NULL DEFAULT '{}',
+    completed_at TIMESTAMP,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS incident_review_factors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    review_id UUID NOT NULL REFERENCES incident_reviews(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    factor_type VARCHAR(20) NOT NULL CHECK (factor_type IN ('people', 'process', 'technology', 'external', 'other')),
+    description TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_review_factors_review ON incident_review_factors(review_id, position);
+
+-- Последующие меры: простая задача, новый риск или мера защиты для риска
+CREATE TABLE IF NOT EXISTS incident_review_followups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    review_id UUID NOT NULL REFERENCES incident_reviews(id) ON DELETE CASCADE,
+    followup_type VARCHAR(20) NOT NULL CHECK (followup_type IN ('action', 'risk', 'control')),
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    due_date DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'in_progress', 'done', 'cancelled')),
+    risk_id UUID REFERENCES risks(id) ON DELETE SET NULL, -- созданный риск или риск, к которому добавлена мера
+    control_id UUID, -- идентификатор меры в risk_controls
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_review_followups_review ON incident_review_followups(review_id, created_at);
@@ -0,0 +1,93 @@
+-- Migration 047: Incident evidence chain of custody
+-- Доказательства по инцидентам: SHA-256 на момент сбора, журнал хранения (chain of custody) и запечатывание
+
+CREATE TABLE IF NOT EXISTS incident_evidence (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE RESTRICT,
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE RESTRICT,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    evidence_type VARCHAR(30) NOT NULL DEFAULT 'file'
+        CHECK (evidence_type IN ('file', 'log', 'screenshot', 'memory_dump', 'disk_image', 'network_capture', 'email', 'other')),
+    source VARCHAR(255), -- откуда получено: хост, система, лицо
+    file_name VARCHAR(255) NOT NULL,
+    file_size BIGINT NOT NULL,
+    sha256 VARCHAR(64) NOT NULL, -- хеш на момент сбора
+    collected_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    collected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    custodian_id UUID REFERENCES users(id) ON DELETE SET NULL, -- текущий ответственный за хранение
+    is_sealed BOOLEAN NOT NULL DEFAULT false,
+    sealed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    sealed_at TIMESTAMP,
+    last_verified_at TIMESTAMP,
+    last_verification_status VARCHAR(20) CHECK (last_verification_status IN ('valid', 'mismatch', 'missing')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_evidence_incident ON incident_evidence(incident_id, collected_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_evidence_document ON incident_evidence(document_id);
+
+-- Журнал хранения только дополняется: записи не изменяются
+CREATE TABLE IF NOT EXISTS incident_evidence_custody (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    evidence_id UUID NOT NULL REFERENCES incident_evidence(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL
+        CHECK (action IN ('collected', 'accessed', 'exported', 'transferred', 'updated', 'sealed', 'verified')),
+    performed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    from_custodian_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    to_custodian_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    sha256 VARCHAR(64), -- хеш, вычисленный при проверке
+    details TEXT,
+    ip_address VARCHAR(45),
+    performed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_evidence_custody_evidence ON incident_evidence_custody(evidence_id, performed_at);
+
+-- Запечатанное доказательство нельзя изменить: допускаются только смена хранителя и результаты проверки
+CREATE OR REPLACE FUNCTION protect_sealed_incident_evidence() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF OLD.is_sealed THEN
+            RAISE EXCEPTION 'incident evidence % is sealed', OLD.id;
+        END IF;
+        RETURN OLD;
+    END IF;
+
+    IF OLD.sha256 IS DISTINCT FROM NEW.sha256 OR OLD.document_id IS DISTINCT FROM NEW.document_id
+       OR OLD.incident_id IS DISTINCT FROM NEW.incident_id OR OLD.collected_at IS DISTINCT FROM NEW.collected_at THEN
+        RAISE EXCEPTION 'incident evidence % integrity fields are immutable', OLD.id;
+    END IF;
+
+    IF OLD.is_sealed AND (
+        NOT NEW.is_sealed
+        OR OLD.title IS DISTINCT FROM NEW.title
+        OR OLD.description IS DISTINCT FROM NEW.description
+        OR OLD.evidence_type IS DISTINCT FROM NEW.evidence_type
+        OR OLD.source IS DISTINCT FROM NEW.source
+        OR OLD.sealed_at IS DISTINCT FROM NEW.sealed_at
+    ) THEN
+        RAISE EXCEPTION 'incident evidence % is sealed', OLD.id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_protect_sealed_incident_evidence ON incident_evidence;
+CREATE TRIGGER trg_protect_sealed_incident_evidence
+    BEFORE UPDATE OR DELETE ON incident_evidence
+    FOR EACH ROW EXECUTE FUNCTION protect_sealed_incident_evidence();
+
+CREATE OR REPLACE FUNCTION protect_incident_evidence_custody() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'incident evidence custody log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_protect_incident_evidence_custody ON incident_evidence_custody;
+CREATE TRIGGER trg_protect_incident_evidence_custody
+    BEFORE UPDATE ON incident_evidence_custody
+    FOR EACH ROW EXECUTE FUNCTION protect_incident_evidence_custody();
@@ -0,0 +1,57 @@
+-- Migration 048: Incident links, hierarchy and merges
+-- Связи между инцидентами: родитель/дочерние для крупных событий, связи "related to" и объединение дубликатов с возможностью отмены
+
+-- Родительский инцидент (крупное событие) и инцидент, в который объединен дубликат
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES incidents(id) ON DELETE SET NULL;
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS merged_into_id UUID REFERENCES incidents(id) ON DELETE SET NULL;
+
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS chk_incidents_parent_not_self;
+ALTER TABLE incidents ADD CONSTRAINT chk_incidents_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_parent ON incidents(parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_merged_into ON incidents(merged_into_id) WHERE merged_into_id IS NOT NULL;
+
+-- Связи "related to"; пара хранится в упорядоченном виде, чтобы связь была симметричной
+CREATE TABLE IF NOT EXISTS incident_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    related_incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    comment TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (incident_id < related_incident_id),
+    UNIQUE (incident_id, related_incident_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_links_related ON incident_links(related_incident_id);
+
+-- Журнал объединений: что было перенесено в основной инцидент, чтобы объединение можно было отменить
+CREATE TABLE IF NOT EXISTS incident_merges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    primary_incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    merged_incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    reason TEXT,
+    previous_status VARCHAR(20) NOT NULL,
+    previous_closed_at TIMESTAMP,
+    previous_resolution TEXT,
+    -- Перенесенные строки; связи, уже существующие у основного инцидента, и доказательства остаются у дубликата
+    comment_ids UUID[] NOT NULL DEFAULT '{}',
+    action_ids UUID[] NOT NULL DEFAULT '{}',
+    asset_link_ids UUID[] NOT NULL DEFAULT '{}',
+    risk_link_ids UUID[] NOT NULL DEFAULT '{}',
+    document_link_ids UUID[] NOT NULL DEFAULT '{}',
+    merged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    merged_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reverted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reverted_at TIMESTAMP,
+    CHECK (primary_incident_id <> merged_incident_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_merges_primary ON incident_merges(primary_incident_id, merged_at);
+CREATE INDEX IF NOT EXISTS idx_incident_merges_merged ON incident_merges(merged_incident_id);
+
+-- Дубликат может быть объединен только в один инцидент одновременно
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incident_merges_active
+    ON incident_merges(merged_incident_id) WHERE reverted_at IS NULL;
@@ -0,0 +1,52 @@
+-- Migration 049: Incident intake by email
+-- Почтовые ящики (POP3), из которых письма сотрудников превращаются в инциденты, и журнал обработанных писем
+
+CREATE TABLE IF NOT EXISTS incident_mailboxes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    host VARCHAR(255) NOT NULL,
+    port INTEGER NOT NULL DEFAULT 995 CHECK (port BETWEEN 1 AND 65535),
+    use_tls BOOLEAN NOT NULL DEFAULT true,
+    username VARCHAR(255) NOT NULL,
+    password TEXT NOT NULL, -- нужен в открытом виде для входа в ящик, наружу не отдается
+    default_category VARCHAR(50) NOT NULL DEFAULT 'other',
+    default_criticality VARCHAR(20) NOT NULL DEFAULT 'medium'
+        CHECK (default_criticality IN ('low', 'medium', 'high', 'critical')),
+    assigned_to UUID REFERENCES users(id) ON DELETE SET NULL,
+    -- Заявитель для писем от адресов, не найденных среди пользователей; если не задан, такие письма отклоняются
+    fallback_reporter_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    delete_after_import BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    last_polled_at TIMESTAMP,
+    last_error TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_mailboxes_tenant ON incident_mailboxes(tenant_id);
+
+CREATE TABLE IF NOT EXISTS incident_mail_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    mailbox_id UUID NOT NULL REFERENCES incident_mailboxes(id) ON DELETE CASCADE,
+    uidl VARCHAR(255) NOT NULL, -- идентификатор письма на сервере POP3
+    message_id VARCHAR(998),    -- заголовок Message-ID, по нему ответы находят свой инцидент
+    in_reply_to VARCHAR(998),
+    from_address VARCHAR(320) NOT NULL,
+    subject TEXT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('incident', 'comment', 'rejected', 'failed')),
+    incident_id UUID REFERENCES incidents(id) ON DELETE SET NULL,
+    comment_id UUID REFERENCES incident_comments(id) ON DELETE SET NULL,
+    attachments INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    sent_at TIMESTAMP,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (mailbox_id, uidl)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_mail_messages_message_id
+    ON incident_mail_messages(tenant_id, message_id) WHERE incident_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_incident_mail_messages_incident ON incident_mail_messages(incident_id);
+CREATE INDEX IF NOT EXISTS idx_incident_mail_messages_mailbox ON incident_mail_messages(mailbox_id, processed_at);
@@ -0,0 +1,50 @@
+-- Migration 050: Asset discovery agents
+-- Прием отчетов агентов инвентаризации (оборудование и ПО), автоматическое обновление паспортов активов и очередь сверки
+
+-- Агенты инвентаризации; аутентификация по токену, в БД хранится только его SHA-256
+CREATE TABLE IF NOT EXISTS asset_discovery_agents (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    token_prefix VARCHAR(16) NOT NULL,
+    reporter_id UUID NOT NULL REFERENCES users(id), -- от имени этого пользователя записывается история изменений
+    remove_missing_software BOOLEAN NOT NULL DEFAULT TRUE, -- удалять ПО, отсутствующее в отчете
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_report_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_discovery_agents_tenant ON asset_discovery_agents(tenant_id);
+
+-- Принятые отчеты; отчеты без однозначного актива попадают в очередь сверки (status = 'pending')
+CREATE TABLE IF NOT EXISTS asset_discovery_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id UUID REFERENCES asset_discovery_agents(id) ON DELETE SET NULL,
+    format VARCHAR(20) NOT NULL CHECK (format IN ('native', 'osquery', 'ocs')),
+    hostname VARCHAR(255),
+    serial_number VARCHAR(255),
+    mac_addresses TEXT[] NOT NULL DEFAULT '{}',
+    ip_addresses TEXT[] NOT NULL DEFAULT '{}',
+    inventory JSONB NOT NULL, -- отчет, приведенный к единому виду
+    status VARCHAR(20) NOT NULL CHECK (status IN ('applied', 'pending', 'ignored')),
+    asset_id UUID REFERENCES assets(id) ON DELETE SET NULL,
+    matched_by VARCHAR(20) CHECK (matched_by IN ('serial', 'mac', 'hostname', 'manual', 'created')),
+    candidate_ids UUID[] NOT NULL DEFAULT '{}', -- несколько подходящих активов при неоднозначном сопоставлении
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    software_added INTEGER NOT NULL DEFAULT 0,
+    software_updated INTEGER NOT NULL DEFAULT 0,
+    software_removed INTEGER NOT NULL DEFAULT 0,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_discovery_reports_queue ON asset_discovery_reports(tenant_id, status, received_at DESC);
+CREATE INDEX IF NOT EXISTS idx_asset_discovery_reports_asset ON asset_discovery_reports(asset_id, received_at DESC);
+
+-- Поиск активов по MAC-адресу и имени хоста при сопоставлении отчетов
+CREATE INDEX IF NOT EXISTS idx_assets_mac_address ON assets(mac_address);
+CREATE INDEX IF NOT EXISTS idx_assets_lower_name ON assets(tenant_id, LOWER(name));
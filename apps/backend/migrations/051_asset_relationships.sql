@@ -0,0 +1,24 @@
+-- Migration 051: Asset relationships
+-- Типизированные связи между активами для карты зависимостей, наследования критичности (CIA) и анализа влияния отказов
+
+-- Связь читается как "source <тип> target": приложение runs_on сервер, сервис depends_on сервис,
+-- база данных stores_data_of информационный актив, коммутатор connected_to маршрутизатор
+CREATE TABLE IF NOT EXISTS asset_relationships (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    source_asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    target_asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    relationship_type VARCHAR(30) NOT NULL CHECK (relationship_type IN ('runs_on', 'depends_on', 'stores_data_of', 'connected_to')),
+    description TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT asset_relationships_not_self CHECK (source_asset_id <> target_asset_id),
+    CONSTRAINT asset_relationships_unique UNIQUE (source_asset_id, target_asset_id, relationship_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_relationships_tenant ON asset_relationships(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_asset_relationships_source ON asset_relationships(source_asset_id);
+CREATE INDEX IF NOT EXISTS idx_asset_relationships_target ON asset_relationships(target_asset_id);
+
+COMMENT ON TABLE asset_relationships IS 'Typed asset-to-asset relationships (dependency map)';
+COMMENT ON COLUMN asset_relationships.relationship_type IS 'runs_on, depends_on, stores_data_of or connected_to';
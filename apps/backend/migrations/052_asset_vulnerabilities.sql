@@ -0,0 +1,80 @@
+-- Migration 052: Software vulnerability matching
+-- Локальная база CVE (офлайн-импорт фидов NVD и словаря CPE) и сопоставление с установленным на активах ПО
+
+-- Уязвимости из фидов NVD; база загружается организацией самостоятельно, повторный импорт обновляет записи
+CREATE TABLE IF NOT EXISTS cve_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    cve_id VARCHAR(30) NOT NULL,
+    description TEXT,
+    cvss_score NUMERIC(3,1),
+    cvss_version VARCHAR(10),
+    cvss_vector VARCHAR(200),
+    severity VARCHAR(10) CHECK (severity IN ('none', 'low', 'medium', 'high', 'critical')),
+    published_at TIMESTAMP,
+    modified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT cve_entries_unique UNIQUE (tenant_id, cve_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cve_entries_tenant_score ON cve_entries(tenant_id, cvss_score);
+
+-- Уязвимые конфигурации CVE (элементы cpeMatch с vulnerable = true); версии хранятся как в фиде
+CREATE TABLE IF NOT EXISTS cve_cpe_matches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    cve_entry_id UUID NOT NULL REFERENCES cve_entries(id) ON DELETE CASCADE,
+    cpe23 VARCHAR(500) NOT NULL,
+    vendor VARCHAR(200) NOT NULL,
+    product VARCHAR(200) NOT NULL,
+    version VARCHAR(100), -- конкретная версия из CPE, NULL для '*' и '-'
+    version_start_including VARCHAR(100),
+    version_start_excluding VARCHAR(100),
+    version_end_including VARCHAR(100),
+    version_end_excluding VARCHAR(100)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cve_cpe_matches_product ON cve_cpe_matches(tenant_id, product);
+CREATE INDEX IF NOT EXISTS idx_cve_cpe_matches_entry ON cve_cpe_matches(cve_entry_id);
+
+-- Словарь CPE: названия продуктов для сопоставления с названиями установленного ПО.
+-- normalized_title - название в нижнем регистре без номера версии, по нему ищется продукт
+CREATE TABLE IF NOT EXISTS cpe_dictionary (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    vendor VARCHAR(200) NOT NULL,
+    product VARCHAR(200) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    normalized_title VARCHAR(500) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT cpe_dictionary_unique UNIQUE (tenant_id, vendor, product, normalized_title)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cpe_dictionary_title ON cpe_dictionary(tenant_id, normalized_title);
+
+-- Найденные уязвимости установленного ПО. Запись переходит в fixed, когда при очередном сопоставлении
+-- версия ПО перестает попадать под уязвимую конфигурацию
+CREATE TABLE IF NOT EXISTS asset_vulnerabilities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    software_id UUID NOT NULL REFERENCES asset_software(id) ON DELETE CASCADE,
+    cve_entry_id UUID NOT NULL REFERENCES cve_entries(id) ON DELETE CASCADE,
+    vendor VARCHAR(200) NOT NULL,
+    product VARCHAR(200) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'fixed')),
+    risk_id UUID REFERENCES risks(id) ON DELETE SET NULL,
+    first_detected_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_detected_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    fixed_at TIMESTAMP,
+    CONSTRAINT asset_vulnerabilities_unique UNIQUE (software_id, cve_entry_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_vulnerabilities_tenant_status ON asset_vulnerabilities(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_asset_vulnerabilities_asset ON asset_vulnerabilities(asset_id);
+
+COMMENT ON TABLE cve_entries IS 'Local CVE database imported from NVD feeds';
+COMMENT ON TABLE cpe_dictionary IS 'CPE product names used to recognize installed software';
+COMMENT ON TABLE asset_vulnerabilities IS 'CVEs matched to installed asset software';
+COMMENT ON COLUMN asset_vulnerabilities.risk_id IS 'Risk created for the vulnerability, shared by all software of the asset with the same CVE';
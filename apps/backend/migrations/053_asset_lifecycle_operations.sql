@@ -0,0 +1,52 @@
+-- Migration 053: Asset lifecycle operations
+-- Передача, ремонт и списание активов с утверждением и формированием актов по шаблонам документов
+
+-- Операция применяется к активу только после утверждения; изменения владельца, ответственного,
+-- местоположения и статуса вносятся одной транзакцией вместе с записями истории актива
+CREATE TABLE IF NOT EXISTS asset_lifecycle_operations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    operation_type VARCHAR(20) NOT NULL CHECK (operation_type IN ('transfer', 'repair', 'repair_complete', 'writeoff')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled')),
+    reason TEXT NOT NULL,
+    -- Состояние актива на момент запроса и целевое состояние; NULL в to_* - поле не меняется
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50),
+    from_owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    to_owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    from_responsible_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    to_responsible_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    from_location VARCHAR(255),
+    to_location VARCHAR(255),
+    details JSONB NOT NULL DEFAULT '{}', -- подрядчик и срок ремонта, способ списания, состав комиссии
+    template_id UUID REFERENCES document_templates(id) ON DELETE SET NULL, -- NULL - активный шаблон нужного типа
+    act_number VARCHAR(50),
+    document_id UUID REFERENCES documents(id) ON DELETE SET NULL, -- сформированный акт в хранилище документов
+    requested_by UUID NOT NULL REFERENCES users(id),
+    approver_user_id UUID REFERENCES users(id),
+    decision_comment TEXT,
+    decided_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_lifecycle_operations_tenant_status ON asset_lifecycle_operations(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_asset_lifecycle_operations_asset ON asset_lifecycle_operations(asset_id);
+-- Для актива допускается только одна операция на утверждении
+CREATE UNIQUE INDEX IF NOT EXISTS idx_asset_lifecycle_operations_pending
+    ON asset_lifecycle_operations(asset_id) WHERE status = 'pending';
+
+-- Право на утверждение операций жизненного цикла
+INSERT INTO permissions (code, module, description) VALUES
+('assets.lifecycle:approve', 'assets', 'Утверждение передачи, ремонта и списания активов')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'Admin' AND p.code = 'assets.lifecycle:approve'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
+
+COMMENT ON TABLE asset_lifecycle_operations IS 'Approval-based asset transfer, repair and write-off operations with generated acts';
+COMMENT ON COLUMN asset_lifecycle_operations.act_number IS 'Act number assigned on approval, sequential per tenant and year';
@@ -0,0 +1,61 @@
+-- Migration 054: Asset inventory campaigns
+-- Плановые инвентаризации по местоположению, подразделению или типу активов со сверкой по сканированию этикеток
+
+-- Кампания инвентаризации: при старте в нее попадают все активы области проверки,
+-- по завершении несканированные активы считаются отсутствующими
+CREATE TABLE IF NOT EXISTS asset_inventory_campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'in_progress', 'completed', 'signed', 'cancelled')),
+    -- Область проверки; пустой массив - без ограничения по признаку
+    scope_locations TEXT[] NOT NULL DEFAULT '{}',   -- начало местоположения актива, без учета регистра
+    scope_departments TEXT[] NOT NULL DEFAULT '{}', -- подразделение из метаданных актива (metadata->>'department')
+    scope_types TEXT[] NOT NULL DEFAULT '{}',
+    auditor_ids UUID[] NOT NULL DEFAULT '{}',       -- инвентаризационная комиссия: сканирование и подписание описи
+    scheduled_start DATE NOT NULL,                  -- кампания запускается автоматически в этот день
+    scheduled_end DATE,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    signed_by UUID REFERENCES users(id),
+    signed_at TIMESTAMP,
+    sign_comment TEXT,
+    report_document_id UUID REFERENCES documents(id) ON DELETE SET NULL,
+    report_sha256 VARCHAR(64),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_inventory_campaigns_tenant_status ON asset_inventory_campaigns(tenant_id, status);
+CREATE INDEX IF NOT EXISTS idx_asset_inventory_campaigns_scheduled ON asset_inventory_campaigns(scheduled_start) WHERE status = 'planned';
+
+-- Позиции описи: ожидаемые активы из области проверки и неожиданные находки
+CREATE TABLE IF NOT EXISTS asset_inventory_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    campaign_id UUID NOT NULL REFERENCES asset_inventory_campaigns(id) ON DELETE CASCADE,
+    asset_id UUID REFERENCES assets(id) ON DELETE SET NULL, -- NULL - отсканирован неизвестный инвентарный номер
+    inventory_number VARCHAR(255) NOT NULL,
+    asset_name VARCHAR(255),
+    expected BOOLEAN NOT NULL,
+    expected_location TEXT,
+    result VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (result IN ('pending', 'found', 'moved', 'missing', 'unexpected')),
+    scanned_code TEXT,
+    scanned_location TEXT,
+    scanned_by UUID REFERENCES users(id),
+    scanned_at TIMESTAMP,
+    scan_count INTEGER NOT NULL DEFAULT 0,
+    resolution VARCHAR(30) CHECK (resolution IN ('confirmed_missing', 'found', 'location_updated', 'returned', 'ignored')),
+    resolution_comment TEXT,
+    resolved_by UUID REFERENCES users(id),
+    resolved_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_asset_inventory_items_number
+    ON asset_inventory_items(campaign_id, UPPER(inventory_number));
+CREATE INDEX IF NOT EXISTS idx_asset_inventory_items_asset ON asset_inventory_items(asset_id);
+
+COMMENT ON TABLE asset_inventory_campaigns IS 'Scheduled asset inventory campaigns with scan-based verification and a signed final report';
+COMMENT ON COLUMN asset_inventory_campaigns.report_sha256 IS 'SHA-256 of the final inventory report content at signing';
+COMMENT ON TABLE asset_inventory_items IS 'Inventory list of a campaign: expected assets and unexpected scans with their results';
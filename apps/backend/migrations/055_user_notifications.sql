@@ -0,0 +1,18 @@
+-- Migration 055: User notifications
+-- Входящие уведомления пользователя: напоминания о пересмотре рисков, предупреждения SLA, регуляторные сроки
+
+CREATE TABLE IF NOT EXISTS user_notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,    -- risk_review_due, incident_sla_warning, ...
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    entity VARCHAR(50),           -- объект уведомления, как в audit_log: risk, incident
+    entity_id UUID,
+    is_read BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_notifications_user ON user_notifications(user_id, is_read, created_at DESC);
@@ -0,0 +1,5 @@
+-- Migration 056: Regulatory notification deadline alerts
+-- Отметки об оповещении ответственного о приближении и истечении срока уведомления регулятора
+
+ALTER TABLE incident_notifications ADD COLUMN IF NOT EXISTS due_soon_alerted_at TIMESTAMP; -- осталось не более четверти срока
+ALTER TABLE incident_notifications ADD COLUMN IF NOT EXISTS overdue_alerted_at TIMESTAMP;
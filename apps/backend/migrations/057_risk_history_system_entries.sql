@@ -0,0 +1,4 @@
+-- Migration 057: System-generated risk history entries
+-- Записи, созданные планировщиком (например, истечение срока принятия риска), не имеют автора: changed_by = NULL
+
+ALTER TABLE risk_history ALTER COLUMN changed_by DROP NOT NULL;
@@ -0,0 +1,10 @@
+-- Migration 058: Incident mail intake hardening
+-- Письмо сначала записывается со статусом processing и только затем превращается в инцидент:
+-- уникальный (mailbox_id, uidl) не дает двум экземплярам бэкенда обработать одно письмо дважды.
+-- Пароли почтовых ящиков хранятся зашифрованными (AES-GCM, ключ SECRETS_ENCRYPTION_KEY), префикс enc:v1:
+
+ALTER TABLE incident_mail_messages DROP CONSTRAINT IF EXISTS incident_mail_messages_status_check;
+ALTER TABLE incident_mail_messages ADD CONSTRAINT incident_mail_messages_status_check
+    CHECK (status IN ('processing', 'incident', 'comment', 'rejected', 'failed'));
+
+COMMENT ON COLUMN incident_mailboxes.password IS 'Зашифрованный пароль POP3 (enc:v1:...); открытые значения шифруются при запуске';